@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go-runner/internal/mcp"
+)
+
+// go-runner-mcp exposes a running go-runner server to AI assistants as an MCP
+// tool server over stdio. stdout carries the protocol, so all logging goes to
+// stderr.
+func main() {
+	defaultURL := os.Getenv("GO_RUNNER_URL")
+	if defaultURL == "" {
+		defaultURL = "http://localhost:8080/api/v1"
+	}
+
+	apiURL := flag.String("url", defaultURL, "go-runner API base URL (env GO_RUNNER_URL)")
+	token := flag.String("token", os.Getenv("GO_RUNNER_TOKEN"), "API token, when the server requires one (env GO_RUNNER_TOKEN)")
+	schema := flag.Bool("schema", false, "print the tools as function-calling JSON schema and exit")
+	flag.Parse()
+
+	log.SetOutput(os.Stderr)
+
+	server, err := mcp.NewServer(*apiURL, *token, "1.0.0")
+	if err != nil {
+		log.Fatalf("Failed to create MCP server: %v", err)
+	}
+
+	if *schema {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(server.FunctionDefinitions()); err != nil {
+			log.Fatalf("Failed to write schema: %v", err)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("🤖 MCP server ready (API: %s)", *apiURL)
+	if err := server.Serve(ctx, os.Stdin, os.Stdout); err != nil && err != context.Canceled {
+		log.Fatalf("MCP server stopped: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"gopkg.in/yaml.v3"
+)
+
+// Converts the Swagger 2.0 document generated by swag into an OpenAPI 3 document
+func main() {
+	input := flag.String("in", "docs/swagger.json", "Swagger 2.0 document generated by swag")
+	jsonOut := flag.String("json", "docs/openapi.json", "OpenAPI 3 JSON output path")
+	yamlOut := flag.String("yaml", "docs/openapi.yaml", "OpenAPI 3 YAML output path")
+	flag.Parse()
+
+	content, err := os.ReadFile(*input)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *input, err)
+	}
+
+	var doc2 openapi2.T
+	if err := json.Unmarshal(content, &doc2); err != nil {
+		log.Fatalf("Failed to parse Swagger document: %v", err)
+	}
+
+	doc3, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		log.Fatalf("Failed to convert to OpenAPI 3: %v", err)
+	}
+
+	jsonData, err := json.MarshalIndent(doc3, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal OpenAPI JSON: %v", err)
+	}
+	if err := os.WriteFile(*jsonOut, append(jsonData, '\n'), 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *jsonOut, err)
+	}
+
+	// Round-trip through a generic map so YAML keys follow the JSON field names
+	var generic map[string]interface{}
+	if err := json.Unmarshal(jsonData, &generic); err != nil {
+		log.Fatalf("Failed to prepare YAML output: %v", err)
+	}
+	var yamlData bytes.Buffer
+	encoder := yaml.NewEncoder(&yamlData)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(generic); err != nil {
+		log.Fatalf("Failed to marshal OpenAPI YAML: %v", err)
+	}
+	if err := os.WriteFile(*yamlOut, yamlData.Bytes(), 0644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *yamlOut, err)
+	}
+
+	log.Printf("✅ OpenAPI 3 document written to %s and %s", *jsonOut, *yamlOut)
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"os"
+
 	"go-runner/internal/app"
+	"go-runner/internal/db"
+	"go-runner/internal/sysservice"
 )
 
 // @title           Go Runner API
@@ -24,5 +28,14 @@ import (
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
 func main() {
-    app.StartServer()
+	// go-runner service install|uninstall|status|print|run
+	if len(os.Args) > 1 && os.Args[1] == "service" {
+		os.Exit(sysservice.RunCLI(os.Args[2:], app.Run))
+	}
+	// go-runner db migrate
+	if len(os.Args) > 1 && os.Args[1] == "db" {
+		os.Exit(db.RunCLI(os.Args[2:]))
+	}
+
+	app.StartServer()
 }
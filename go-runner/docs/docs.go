@@ -24,9 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/projects": {
+        "/": {
             "get": {
-                "description": "Get a list of all projects",
+                "description": "Get API information and available features",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,91 +34,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "info"
                 ],
-                "summary": "Get all projects",
+                "summary": "API Information",
                 "responses": {
                     "200": {
-                        "description": "List of projects",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "API information",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/APIInfoResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new project with the provided data",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/actions": {
+            "get": {
+                "description": "List the actions that can be executed with POST /actions/{id}/execute, in the order command palettes show them, with their stable ID, the route they run and the schema of their parameters. Path parameters with an entity are the ID of a project or group; destructive actions should be confirmed first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "actions"
                 ],
-                "summary": "Create a new project",
+                "summary": "List actions",
                 "parameters": [
                     {
-                        "description": "Project data",
-                        "name": "project",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_project.Project"
-                        }
+                        "type": "string",
+                        "description": "Only the actions on this entity: project, group or system",
+                        "name": "entity",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created project",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Action"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Invalid entity",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/projects/{id}": {
+        "/actions/{id}": {
             "get": {
-                "description": "Get a specific project by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get an action of the registry by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "actions"
                 ],
-                "summary": "Get a project by ID",
+                "summary": "Get an action",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Project ID",
+                        "type": "string",
+                        "description": "Action ID, e.g. project.restart",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -126,39 +117,35 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project data",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Action"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "404": {
-                        "description": "Project not found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Action not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/system/alerts": {
-            "get": {
-                "description": "Get system alerts with filtering options",
+        "/actions/{id}/execute": {
+            "post": {
+                "description": "Check the parameters against the schema of the action and run its route with them, with the credentials of this request. The response is that of the route: its status, headers and body.",
                 "consumes": [
                     "application/json"
                 ],
@@ -166,98 +153,92 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "actions"
                 ],
-                "summary": "Get system alerts",
+                "summary": "Execute an action",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Alert type filter",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Alert level filter",
-                        "name": "level",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Active alerts only",
-                        "name": "active",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Action ID, e.g. project.restart",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Parameters",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ExecuteRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "System alerts",
+                        "description": "Response of the route of the action",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "object"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid parameters",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Action not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/system/config": {
+        "/dashboards": {
             "get": {
-                "description": "Get system monitoring configuration",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List saved dashboards, optionally of one team",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "dashboards"
+                ],
+                "summary": "List dashboards",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only dashboards of this team",
+                        "name": "team",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get system configuration",
                 "responses": {
                     "200": {
-                        "description": "System configuration",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Dashboard"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Update system monitoring configuration",
+            "post": {
+                "description": "Save a dashboard of metric panels. Each panel shows one metric (see GET /dashboards/metrics) over a time range (15m, 6h, 7d..., default 1h) as a line, area, bar, gauge or stat chart; project metrics show the listed projects, or every project with data.",
                 "consumes": [
                     "application/json"
                 ],
@@ -265,110 +246,130 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "dashboards"
                 ],
-                "summary": "Update system configuration",
+                "summary": "Create a dashboard",
                 "parameters": [
                     {
-                        "description": "System configuration",
-                        "name": "config",
+                        "description": "Dashboard",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_system.SystemConfig"
+                            "$ref": "#/definitions/DashboardRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Updated configuration",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Dashboard"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "Invalid dashboard",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/system/dashboard": {
+        "/dashboards/metrics": {
             "get": {
-                "description": "Get system dashboard with overview information",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the metrics dashboard panels can show, with their unit and whether they are recorded per project",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "dashboards"
                 ],
-                "summary": "Get system dashboard",
+                "summary": "List dashboard metrics",
                 "responses": {
                     "200": {
-                        "description": "System dashboard",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Metric"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/system/info": {
+        "/dashboards/{id}": {
             "get": {
-                "description": "Get comprehensive system information including CPU, memory, disk, and network",
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "dashboards"
+                ],
+                "summary": "Get a dashboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Dashboard ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get system information",
                 "responses": {
                     "200": {
-                        "description": "System information",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Dashboard"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Dashboard not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/system/metrics": {
-            "get": {
-                "description": "Get historical system metrics with pagination",
+            },
+            "put": {
+                "description": "Replace the name, team, description and panels of a dashboard",
                 "consumes": [
                     "application/json"
                 ],
@@ -376,82 +377,402 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "dashboards"
                 ],
-                "summary": "Get system metrics",
+                "summary": "Update a dashboard",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Dashboard ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Hours of data to retrieve",
-                        "name": "hours",
-                        "in": "query"
+                        "description": "Dashboard",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DashboardRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "System metrics",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Dashboard"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid dashboard",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Dashboard not found",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/system/metrics/cleanup": {
-            "post": {
-                "description": "Clear metrics older than specified days",
-                "consumes": [
+            },
+            "delete": {
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "dashboards"
+                ],
+                "summary": "Delete a dashboard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Dashboard ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dashboard deleted",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Dashboard not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboards/{id}/data": {
+            "get": {
+                "description": "Get the series of every panel of a dashboard: one per project for project metrics, oldest point first and averaged down to at most 120 points. Gauge and stat panels only get the latest value. A panel that cannot be read carries an error instead of failing the dashboard.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "dashboards"
                 ],
-                "summary": "Clear old metrics",
+                "summary": "Get dashboard data",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Days to keep (default: 30)",
-                        "name": "days",
+                        "description": "Dashboard ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time range for every panel instead of their own (15m, 6h, 7d...)",
+                        "name": "range",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Cleanup result",
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DashboardData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Dashboard not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "List events recorded by the audit sink, newest first: project lifecycle, alerts, configuration changes and finished jobs (events.audit_categories)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Query the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "lifecycle, alert, config, job, progress, metrics or system",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type, e.g. status_changed",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events of this project",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events after this time (RFC 3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum events (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/AuditEvent"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/integrations/test": {
+            "post": {
+                "description": "Send a test alert to the PagerDuty and Opsgenie integrations (events.pagerduty, events.opsgenie) routing its level, and resolve it right away unless keep_open is set. The alert has type \"test\" and the same dedup key on every fire, so repeated tests update one incident.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Test-fire alert integrations",
+                "parameters": [
+                    {
+                        "description": "Integration and level",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/TestFireRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/TestFireResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid level",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Integration not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/sinks": {
+            "get": {
+                "description": "List the sinks of the event bus with the events they receive and their delivered, failed and dropped counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List event sinks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/SinkStats"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/groups": {
+            "get": {
+                "description": "Get all project groups with their projects",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get all project groups",
+                "responses": {
+                    "200": {
+                        "description": "List of groups",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ProjectGroup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new project group",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create a project group",
+                "parameters": [
+                    {
+                        "description": "Group data",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateProjectGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created group",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectGroup"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
                     "400": {
                         "description": "Bad request",
@@ -460,6 +781,12 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "422": {
+                        "description": "Validation failed, with every failed rule in details",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -470,9 +797,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/system/status": {
+        "/groups/{id}": {
             "get": {
-                "description": "Get current system status and health indicators",
+                "description": "Get a project group by ID with its projects",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get a project group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group data",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectGroup"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the provided fields of a project group",
                 "consumes": [
                     "application/json"
                 ],
@@ -480,17 +867,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "groups"
+                ],
+                "summary": "Update a project group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Group fields",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateProjectGroupRequest"
+                        }
+                    }
                 ],
-                "summary": "Get system status",
                 "responses": {
                     "200": {
-                        "description": "System status",
+                        "description": "Updated group",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectGroup"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
+                    "422": {
+                        "description": "Validation failed, with every failed rule in details",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -499,285 +935,17355 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "description": "Delete a project group by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete a project group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group deleted",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/chaos/kill": {
+            "post": {
+                "description": "Send SIGKILL to one running process picked at random among the projects of a group and their extra instances (instances), as a crash: projects with auto_restart are restarted like after any other crash. Services run through Kubernetes, systemd or SSH are not candidates. Recorded as a chaos annotation of the project hit.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chaos"
+                ],
+                "summary": "Kill a random instance of a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Instance killed",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ChaosKillResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No running instance in the group",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/export/vscode": {
+            "get": {
+                "description": "Generate the tasks and debug configurations of every project of a group (see GET /projects/{id}/export/vscode), plus a compound launch debugging them all at once and a task starting them all, both named after the group.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Export a group to VS Code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/VSCodeExport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/procfile": {
+            "get": {
+                "description": "Write the projects of a group as a Procfile, one process type per project (the project name without the \"\u003cgroup\u003e-\" prefix added by the Procfile import). Projects without a command are listed as comments. Add download=true to get it as an attachment.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Export a group as a Procfile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Send as a Procfile attachment",
+                        "name": "download",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Procfile",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/projects": {
+            "get": {
+                "description": "Get all projects belonging to a group",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get projects of a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Group projects",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Project"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/rolling-restart": {
+            "post": {
+                "description": "Restart the projects of a group as a background job, batch_size at a time (default 1), the projects they depend on first. After each batch it waits until the restarted projects are ready: port accepting connections, socket_path listening and health_check_url answering below 400, whichever they have, or still running after a few seconds when they have none. The first project that fails to restart or is not ready within ready_timeout seconds aborts the remaining batches, which are reported as not_reached. Members that are not running are skipped unless include_stopped is set. Each restarted project gets a restart annotation. The job result is a RollingRestartResult.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Rolling restart of a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Batching and timeouts",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/RollingRestartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Rolling restart job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or group without projects",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A rolling restart of the group is already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/timeline": {
+            "get": {
+                "description": "Overlay the status timelines of the projects of a group. Group buckets average the uptime of the projects and take the worst status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get the status timelines of a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of history (default 24, max 2160)",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of uptime buckets (default 24, max 200)",
+                        "name": "buckets",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/GroupTimeline"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Group not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the service is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Service status",
+                        "schema": {
+                            "$ref": "#/definitions/HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "description": "List background jobs (package installs, imports, ...) newest first, without their output. Job updates are also broadcast to WebSocket clients as \"job_update\" messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List background jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job type (install, import)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (queued, running, succeeded, failed, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of jobs (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Job"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Get a job with its progress, result and output",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get a background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}/cancel": {
+            "post": {
+                "description": "Cancel a queued or running job. Running jobs turn \"cancelled\" once their work has stopped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Cancel a background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Cancellation requested",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Job already finished",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/kubernetes/deployments": {
+            "get": {
+                "description": "List the deployments of a namespace (all namespaces when empty) through kubectl, to pick the ones to import. Requires kubernetes.enabled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kubernetes"
+                ],
+                "summary": "List Kubernetes deployments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "kubeconfig context, the current one when empty",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace, all when empty",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/KubeDeploymentSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "502": {
+                        "description": "kubectl failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Kubernetes mode is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/kubernetes/import": {
+            "post": {
+                "description": "Create a project per deployment, with kube_context, kube_namespace and kube_deployment set and its current replica count as kube_replicas. Start and stop then scale the deployment, status follows pod readiness and logs stream from the pods. Deployments already imported are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kubernetes"
+                ],
+                "summary": "Import Kubernetes deployments",
+                "parameters": [
+                    {
+                        "description": "Deployments to import",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ImportKubeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ImportKubeResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "kubectl failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Kubernetes mode is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/tail": {
+            "post": {
+                "description": "Merge the live logs of several projects into one stream with per-line source tags, to follow a request across frontend, API and worker. With \"Accept: text/event-stream\" the response itself is the SSE stream. Otherwise a session is created; connect to ws_url (WebSocket) or sse_url (SSE) within a minute. Each event is a TailLine.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Tail logs of several projects",
+                "parameters": [
+                    {
+                        "description": "Projects and filters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TailLogsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SSE stream of lines (Accept: text/event-stream)",
+                        "schema": {
+                            "$ref": "#/definitions/TailLine"
+                        }
+                    },
+                    "201": {
+                        "description": "Tail session",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/TailSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/tail/{session}/sse": {
+            "get": {
+                "description": "Connect to a tail session created by POST /logs/tail with EventSource. Events are \"projects\" then \"log\".",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Stream a log tail over SSE",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tail session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SSE stream of lines",
+                        "schema": {
+                            "$ref": "#/definitions/TailLine"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/tail/{session}/ws": {
+            "get": {
+                "description": "Connect to a tail session created by POST /logs/tail. Messages are {\"type\":\"projects\"|\"log\",\"data\":...}.",
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Stream a log tail over WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tail session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching protocols"
+                    },
+                    "404": {
+                        "description": "Session not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/trace/{traceID}": {
+            "get": {
+                "description": "Gather the log lines mentioning a trace or request ID across all projects. IDs are parsed from W3C traceparent values and trace_id=, request_id=, X-Request-ID: style fields as lines are logged; buffered and stored logs are also searched for the raw ID. Projects started with trace_injection get a fresh ID per start, exposed as trace_id in their status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Get the log lines of a trace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trace or request ID",
+                        "name": "traceID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/TraceResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "No lines found for the trace",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance": {
+            "get": {
+                "description": "List maintenance windows with whether they are open now and their next start",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "List maintenance windows",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only open windows",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only windows covering this project",
+                        "name": "project_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Window"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Plan a one-off (starts_at to ends_at) or recurring (cron in server local time, lasting duration_minutes) maintenance window for the system or specific projects. While it is open, alerts are not raised and crashed services with auto_restart are not restarted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Create a maintenance window",
+                "parameters": [
+                    {
+                        "description": "Window",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Window"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid window",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Get a maintenance window",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Window"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Window not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a window, ending it if it is open",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Delete a maintenance window",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Window deleted",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Window not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mdns": {
+            "get": {
+                "description": "List the running projects announced on the local network as \u003cmdns_name\u003e.local (DNS-SD type _http._tcp). Announcing is enabled with mdns.enabled in the config and per project with \"mdns\": true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "List mDNS announcements",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/MDNSStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes": {
+            "get": {
+                "description": "List the hosts of projects with an ssh_host as last checked: status (up, down after nodes.failures consecutive failed checks, unknown before the first check), latency of a TCP connection to the SSH port and, when a WireGuard interface routes the host, its peer and last handshake. The projects of a node that is down are \"unreachable\" until it is back.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List the hosts of SSH projects",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only nodes with this status (up, down, unknown)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Node"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding": {
+            "get": {
+                "description": "Get the onboarding sessions, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "onboarding"
+                ],
+                "summary": "List onboardings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/OnboardingSession"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Scan a repository for Node.js, Go and Python services and propose a project for each: name, type, command, a free port (conflicts with other projects and listening processes are moved and explained in notes), package manager and a group named after the directory. Directories already registered as projects are reused. Review and edit the proposal with PUT /onboarding/{id}, then POST /onboarding/{id}/run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "onboarding"
+                ],
+                "summary": "Start onboarding a repository",
+                "parameters": [
+                    {
+                        "description": "Repository",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/StartOnboardingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/OnboardingSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Path does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding/{id}": {
+            "get": {
+                "description": "Get the state of an onboarding: the current step and status, and per service the project, doctor summary and whether it was installed and started. Progress of a run is also broadcast as \"onboarding_update\" messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "onboarding"
+                ],
+                "summary": "Get an onboarding",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Onboarding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/OnboardingSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Onboarding not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Change the group name, the run options or the proposed services (the list replaces the proposal: set include to false to leave a service out, or change its name, command, port or package manager). Only possible before the first run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "onboarding"
+                ],
+                "summary": "Edit an onboarding proposal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Onboarding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateOnboardingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/OnboardingSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid proposal",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Onboarding not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Onboarding already ran",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an onboarding session. Projects and groups it created are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "onboarding"
+                ],
+                "summary": "Delete an onboarding",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Onboarding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Onboarding not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Onboarding is running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding/{id}/run": {
+            "post": {
+                "description": "Create the group and the included projects, run the doctor on each, install their dependencies and start them, as a background job. Each step is recorded, so a failed or cancelled run resumes at the step it stopped with POST /onboarding/{id}/run again; services already created, installed or started are skipped. Options given here replace the saved ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "onboarding"
+                ],
+                "summary": "Run an onboarding",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Onboarding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Run options",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/OnboardingOptions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Onboarding job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Onboarding not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Onboarding running or completed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ports": {
+            "get": {
+                "description": "List listening TCP ports and bound UDP ports with their owning processes and the project that declared them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ports"
+                ],
+                "summary": "Get ports in use",
+                "responses": {
+                    "200": {
+                        "description": "Ports in use",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/PortInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ports/sockets": {
+            "get": {
+                "description": "List listening Unix domain sockets with their owning processes and the project that declared them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ports"
+                ],
+                "summary": "Get listening Unix sockets",
+                "responses": {
+                    "200": {
+                        "description": "Listening sockets",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/UnixSocketInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ports/{port}": {
+            "delete": {
+                "description": "Kill the process listening on the specified port",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ports"
+                ],
+                "summary": "Kill process on port",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Port number",
+                        "name": "port",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Process killed",
+                        "schema": {
+                            "$ref": "#/definitions/KillPortResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid port",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to kill process",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects": {
+            "get": {
+                "description": "Get a list of all projects, optionally those of one owner or team",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get all projects",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only the projects of this owner",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the projects of this team",
+                        "name": "team",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of projects",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Project"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new project with the provided data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Create a new project",
+                "parameters": [
+                    {
+                        "description": "Project data",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created project",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Project"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Declared port owned by another project",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed, with every failed rule in details",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/apply": {
+            "post": {
+                "description": "Make the projects and groups match a workspace spec, in the import file format (body, upload or path on the server, includes and anchors included): projects and groups are matched by name, created, updated to the spec (settings it leaves out get the defaults of new projects) and, unless prune=false, deleted when missing from it; running projects are stopped before they are deleted. Projects can name their group with group. The spec is linted like imports (422 with the report on errors). dry_run returns the plan without changing anything. The applied spec is stored for GET /projects/drift.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Apply a workspace spec",
+                "parameters": [
+                    {
+                        "description": "Workspace spec",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ImportProjectsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Spec file on the server",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete projects and groups missing from the spec (default true)",
+                        "name": "prune",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return the plan",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ApplyResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The spec has errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/LintReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/detect-services": {
+            "post": {
+                "description": "Scan a directory for Node.js, Go and Python services",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Detect services",
+                "parameters": [
+                    {
+                        "description": "Path to scan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DetectServicesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Detected services",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ServiceDetection"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/drift": {
+            "get": {
+                "description": "Compare the projects and groups with the last spec applied by POST /projects/apply: drifted lists what applying it again would change, such as settings edited since, projects deleted, or projects added when the spec was applied with prune.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Compare projects with the applied spec",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DriftReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "No spec applied yet",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/import": {
+            "post": {
+                "description": "Import groups and projects from a JSON body. A YAML/JSON file can also be uploaded as multipart form field \"file\", or read from the server with path. Files can list other files under include (a file or a list of files, glob patterns allowed; relative to the including file, so only with path), whose groups and projects come first; a project defined twice keeps its last definition. YAML anchors, aliases and merge keys work within a file, with top-level x- keys to hold shared blocks, and env_vars may be a mapping. The file is linted first: unknown keys, values of the wrong type, missing paths, invalid env_vars JSON and ports used by other projects are reported as errors or warnings, and nothing is imported while there are errors (422 with the report). dry_run only returns the report. The import runs as a background job; if it takes longer than 20 seconds the job is returned with 202 and keeps running.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Import projects",
+                "parameters": [
+                    {
+                        "description": "Projects and groups to import",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ImportProjectsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Import file on the server",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only lint the file",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import result, or LintReport with dry_run",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Import still running; poll GET /jobs/{id}",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Another import is running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The file has errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/LintReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/import/pm2": {
+            "post": {
+                "description": "Convert PM2 apps into projects with the equivalent command, working directory, environment (PORT sets the port) and restart settings (autorestart, max_restarts capped to 10, max_memory_restart as memory limit). Apps come from an ecosystem file on the server (ecosystem.config.js is evaluated with node), from the body (ecosystem apps or ` + "`" + `pm2 jlist` + "`" + ` output) or from ` + "`" + `pm2 jlist` + "`" + ` on this host. The import then runs like POST /projects/import (existing projects with the same name are updated). With dry_run the converted projects and the PM2 settings that have no equivalent (cluster instances, watch, cron_restart) are returned instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Import PM2 apps",
+                "parameters": [
+                    {
+                        "description": "PM2 source",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ImportPM2Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import result",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Import still running; poll GET /jobs/{id}",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Another import is running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/import/procfile": {
+            "post": {
+                "description": "Turn each process type of a Procfile into a project named \u003cgroup\u003e-\u003ctype\u003e, in a group named after the directory (created if missing). Like foreman, process types get PORT = base_port + 100 × their position (5000, 5100, ...), set as port and in env_vars so it wins over .env, which is loaded from the directory as usual. Commands using shell syntax such as $PORT run through sh -c. The import then runs like POST /projects/import (existing projects with the same name are updated).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Import a Procfile",
+                "parameters": [
+                    {
+                        "description": "Procfile to import",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ImportProcfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import result, or ProcfileImportPreview with dry_run",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Import still running; poll GET /jobs/{id}",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Another import is running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/summary": {
+            "get": {
+                "description": "Get everything the overview page needs in one request: the number of projects by status, type and group, the restarts (starts of a project after its first one of the period) and crashes (transitions to error) of the last 24 hours, the active alerts per project, and the services that crashed most recently, one entry per project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Summarize projects",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recent crashes to return (default 5, max 50)",
+                        "name": "crashes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectsSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}": {
+            "get": {
+                "description": "Get a specific project by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get a project by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project data",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Project"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing project with the fields of the body, like PATCH: fields left out or null keep their value, and status, PID or logs are never overwritten. When the body has the version of the project it was edited from (or If-Match its ETag), the update is refused with 409 and the current project if another change was saved since.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Update a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the project the changes were made on, e.g. \\",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Changed fields",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated project",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Project"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Changed since the version of the request, with the current project in details; or declared port owned by another project",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed, with every failed rule in details",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a project by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Delete a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project deleted",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change only the fields present in the body; fields left out or null keep their value, so two clients editing different fields do not undo each other. group_id 0 removes the project from its group. With version (or If-Match) set to the version the changes were made on, the update is refused with 409 and the current project if another change was saved since.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Partially update a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the project the changes were made on, e.g. \\",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Changed fields",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated project",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Project"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Changed since the version of the request, with the current project in details",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed, with every failed rule in details",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/annotations": {
+            "get": {
+                "description": "Get the annotations of a project over a period, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List annotations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC 3339, default 24 hours ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End (RFC 3339, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this kind (note, deploy, config)",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ProjectAnnotation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Mark something that happened to a project, such as a deploy from CI or a manual change, so that it shows up as a marker on its timeline, traffic and comparison charts (annotations) and as a line in its logs when it is running. go-runner adds deploy annotations itself when a project starts on a new git revision, and config annotations when its configuration is changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Annotate a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Annotation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateAnnotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectAnnotation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/annotations/{annotation_id}": {
+            "delete": {
+                "description": "Delete an annotation of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Delete an annotation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Annotation ID",
+                        "name": "annotation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Annotation not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/audit": {
+            "get": {
+                "description": "Get the findings of the latest dependency audit of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get the latest dependency audit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DependencyAudit"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or never audited",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Start a dependency vulnerability audit as a background job: npm audit for Node.js, govulncheck and go list -m -u for Go, pip-audit for Python. The job result is the DependencyAudit; the latest summary is included in the project listing as \"audit\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Audit dependencies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ecosystem override",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/AuditRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Audit job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or unknown ecosystem",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "An audit is already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/chaos": {
+            "get": {
+                "description": "Show the latency injected into the proxied requests of a project and the pause it is under, if any.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chaos"
+                ],
+                "summary": "Get the chaos applied to a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ChaosStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/chaos/latency": {
+            "post": {
+                "description": "Hold each request proxied to the project (/projects/{id}/proxy/) for delay_ms milliseconds, plus up to jitter_ms more at random, during duration seconds (default 60), to see how its clients cope with a slow service. A new call replaces the previous latency. Recorded as a chaos annotation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chaos"
+                ],
+                "summary": "Inject latency at the proxy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delay and duration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ChaosLatencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProxyLatency"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or project without port",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop delaying the requests proxied to the project before the end of the injected latency.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chaos"
+                ],
+                "summary": "Remove injected latency",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or no latency injected",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/chaos/pause": {
+            "post": {
+                "description": "Suspend the process of a running project, its descendants and extra instances with SIGSTOP for duration seconds, then continue them with SIGCONT, to see how the services that depend on it react to one that hangs. Like POST /projects/{id}/pause, but timed and recorded as a chaos annotation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chaos"
+                ],
+                "summary": "Pause a service for a while",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Duration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ChaosPauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ServicePause"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Service not running or already paused",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "No local process to pause",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Continue a paused service before the end of its pause, recorded as a chaos annotation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chaos"
+                ],
+                "summary": "End a pause early",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or not paused",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/ci": {
+            "get": {
+                "description": "Get the latest pipeline of the repository of a project on its branch, from GitHub Actions or GitLab CI: ci_repo (github:owner/repo or gitlab:group/project) or the origin remote of its checkout, and ci_branch or the checked out branch. Statuses are polled every ci.poll_interval seconds and pushed by webhooks (/webhooks/github, /webhooks/gitlab); refresh=true fetches it now. Also included as \"ci\" in the project status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get the CI status of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fetch the latest pipeline now",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Status"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No CI repository for the project",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or not checked yet",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Provider request failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/compare": {
+            "get": {
+                "description": "Align the CPU, memory and error rate of a project after two events of its status timeline, such as the previous start and the current one, to see whether a new build uses more resources or leaks memory. Events are status transition IDs (see \"starts\" in the response); without them the last two starts (transitions to running) are compared. Each side covers ` + "`" + `minutes` + "`" + ` after its event, cut at the later event and now, in steps of ` + "`" + `step` + "`" + ` seconds; CPU and memory are sampled every 30 seconds while a project runs and kept for 7 days, errors come from the traffic proxied through /projects/{id}/proxy.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Compare metrics after two lifecycle events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Status transition ID of the first event (default: the start before to_event)",
+                        "name": "from_event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Status transition ID of the second event (default: the last start)",
+                        "name": "to_event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Window after each event (default 60, max 1440)",
+                        "name": "minutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seconds per point (default 60, min 30)",
+                        "name": "step",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Comparison"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project or event not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/config": {
+            "get": {
+                "description": "Export the project configuration as a YAML or JSON string",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get project configuration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Output format (yaml or json)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Serialized configuration",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a project from a YAML or JSON configuration string. The configuration is linted first like imports (unknown keys, values of the wrong type, missing paths, invalid env_vars JSON, ports used by other projects); nothing is saved while it has errors (422 with the report), and warnings are returned with the project. dry_run only returns the report.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Update project from configuration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateProjectFromConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated project, or LintReport with dry_run",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ConfigUpdateResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "The configuration has errors",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/LintReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/database": {
+            "get": {
+                "description": "Connect to the connection string of a database project (postgres://, mysql://, redis:// or a SQLite file) and return its version, size and active connections. The result is stored as the project health status. Database projects are also checked every 30 seconds; the last result is included as \"database\" in the project status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Check a database project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Check result, healthy or not",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DatabaseStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No connection string",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/database/migrate": {
+            "post": {
+                "description": "Run the migration command of a project in its working directory as a background job, with DATABASE_URL set to the connection string. Output is streamed to the project WebSocket as \"migration_log\" messages (InstallLogLine).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Run the migration command",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Migration job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No migration command",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A migration is already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/debug-bundle": {
+            "post": {
+                "description": "Download a zip archive with everything needed to report a problem with a project: its configuration (project.json, secret-looking env_vars and URL passwords redacted), live status (status.json), buffered logs and the tail of its output files (logs/), status timeline (timeline.json), CPU, memory, traffic, queue and system metrics (metrics.json), crashes with the log lines before each (crashes.json), events (events.json), deploy, config and note annotations (annotations.json) and system info (system.json). manifest.json lists the files and any section that could not be collected.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Create a debug bundle of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of history (default 24, max 720)",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Debug bundle",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/dependencies": {
+            "get": {
+                "description": "List the direct dependencies declared in package.json, go.mod or requirements.txt with their current and latest versions. Latest versions come from the npm registry, the Go module proxy (GOPROXY) or PyPI and are cached for an hour.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List dependencies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "npm, go or pip (detected when empty)",
+                        "name": "ecosystem",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Bypass the registry cache",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DependencyReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No supported manifest",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/dependencies/upgrade": {
+            "post": {
+                "description": "Upgrade one direct dependency to the given or latest version as an install job (npm/yarn/pnpm add, go get, pip install). Pinned requirements.txt entries are updated after pip succeeds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Upgrade a dependency",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Package and version",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpgradeDependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Install job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project or dependency not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "An install is already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Latest version lookup failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/disk-usage": {
+            "get": {
+                "description": "Compute the size of the project directory with a breakdown of artifact directories: dependencies (node_modules, vendor, .venv...), build output (dist, build, target, .next...) and caches (.cache, __pycache__, coverage...), plus the free space of the filesystem. Symlinks are not followed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get project disk usage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DiskUsage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Directory not readable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/disk-usage/clean": {
+            "post": {
+                "description": "Remove the given artifact directories, or every directory of the given kinds, as found by GET /projects/{id}/disk-usage. Other paths are rejected. Dependencies and build output come back with an install or build; the project must be stopped unless force is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Remove artifact directories",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Directories to remove",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CleanDiskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/CleanDiskResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Not an artifact directory",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Project is running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/doctor": {
+            "post": {
+                "description": "Verify that the machine can run the project: the start command exists, the node, go and python versions satisfy package.json engines, .nvmrc, .node-version, go.mod, pyproject.toml requires-python, .python-version and runtime.txt, the package manager (packageManager, lockfiles, poetry, pipenv, uv) is installed, the variables listed in .env.example are set, and the database and service URLs of the connection string, .env file and env_vars are reachable. Each check passes, warns or fails with a fix. Versions selected by asdf (.tool-versions), nvm (.nvmrc) and the go.mod toolchain directive are used as at start; other tools are looked up on the PATH the project is started with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Check the project environment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DoctorReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid project ID",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/env-file": {
+            "get": {
+                "description": "Read the project's .env file (env_file, or .env in the project path) with parsed lines, syntax errors and a diff against the running process environment",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get .env file",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ".env file",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/EnvFileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Write the project's .env file. Send either the full content, or set/unset to change individual variables in place (comments and ordering are kept). Invalid KEY=VALUE lines are rejected. The response tells whether the running service must be restarted to pick up the changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Update .env file",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New content or variable changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateEnvFileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated .env file",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/EnvFileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid .env content",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/EnvFileError"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/export/vscode": {
+            "get": {
+                "description": "Generate a tasks.json task running the project command and a launch.json debug configuration (Go via Delve, Node.js including npm/yarn/pnpm scripts, Python via debugpy) with the same working directory and variables as a start: the .env file, env_vars with placeholders resolved, PORT and ENVIRONMENT. Paste them into .vscode/ of the project. Commands without a known debugger only get a task, explained in notes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Export a project to VS Code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/VSCodeExport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/files/changes": {
+            "get": {
+                "description": "Get the recent changes in the project directory (path, operation, time), newest first, with the time of the last change. Requires watch_files; watchers start within 10 seconds of enabling it and skip dependency, build and VCS directories (node_modules, vendor, dist, .git...). Changes are also broadcast to the project WebSocket as \"file_change\" messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get recent file changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/FileChangeFeed"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/force-kill": {
+            "post": {
+                "description": "Forcefully kill the service process of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Force kill a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project force killed",
+                        "schema": {
+                            "$ref": "#/definitions/ProjectActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/inspect": {
+            "post": {
+                "description": "Restart a Node.js project with the inspector listening on 127.0.0.1 (--inspect for node, nodemon and tsx commands, NODE_OPTIONS for others such as npm scripts, where the first Node.js process started takes the port) and return the DevTools URL. The command is not changed: the inspector stays on across restarts until DELETE /projects/{id}/inspect or a manual stop, and is included as \"inspector\" in the project status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Debug a Node.js project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Inspector port",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/InspectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restarted with the inspector; listening is false when it did not answer in time",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/NodeInspector"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Not a local Node.js project",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Port in use or another operation in progress",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Turn the inspector off and restart the project without it when it is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Stop debugging a Node.js project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inspector off",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Another operation in progress",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/install": {
+            "post": {
+                "description": "Start installing dependencies or specific packages in the project directory as a background job and return the job immediately. Output is streamed to the project WebSocket as \"install_log\" messages (InstallLogLine) and job progress as \"job_update\". Only one install runs per project at a time; poll GET /jobs/{id} or cancel with POST /jobs/{id}/cancel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Install packages",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Package manager and packages",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/InstallPackagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Install job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "An install is already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Package manager not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/install/jobs": {
+            "get": {
+                "description": "Get the package install history of a project, newest first, without output. Use GET /jobs/{id} for the output of a job.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List install jobs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Job"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/instances": {
+            "get": {
+                "description": "List the copies of the service of a project run side by side (instances), each with its own status, PID, port and restarts. Index 0 is the main process, the one start, stop and logs act on; the extra instances run the same command with PORT set to the port of the project plus their index (instance_ports offset) or to a free port (auto), and INSTANCE_INDEX to their index. They start and stop with the main process, the proxy balances requests over all of them, and changing instances scales a running project right away.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get the instances of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Instances",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ServiceInstance"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/instances/{index}/logs": {
+            "get": {
+                "description": "Read the last lines of the output of an extra instance of a project, stdout and stderr combined, from its file in the log directory. The output of the main process (index 0) is at /projects/{id}/logs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Get the logs of an instance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Instance index, from 1",
+                        "name": "index",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of lines (default 200, max 10000)",
+                        "name": "lines",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Instance output",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/InstanceLogsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Instance not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/kubernetes": {
+            "get": {
+                "description": "Read the deployment and pods of a project with a kube_deployment now and sync its status and health with pod readiness. The last observation is also included as \"kubernetes\" in the project status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kubernetes"
+                ],
+                "summary": "Get the deployment of a Kubernetes project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/KubeDeployment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Not a Kubernetes project",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Kubernetes mode is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/logs": {
+            "get": {
+                "description": "Get buffered logs of a project from memory or the database. Every line is stamped with its capture time in UTC (entries); with timestamps=true the lines in logs are also prefixed with it, in the timezone given by tz.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Get project logs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Prefix lines with their capture time",
+                        "name": "timestamps",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone of the prefixes: IANA name or +HH:MM (default UTC)",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project logs",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/LogsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/logs/archives": {
+            "get": {
+                "description": "List the rotated output files of a project archived to S3-compatible storage, oldest first. An archive covers the output written between started_at (the previous rotation of its stream) and rotated_at; pending and failed archives are still spooled locally and retried on the next upload run.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "List the log archives of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "stdout or stderr",
+                        "name": "stream",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Archives covering output after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Archives covering output before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/Archive"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/logs/archives/fetch": {
+            "get": {
+                "description": "Download, decompress and concatenate the archives covering a time range, oldest first, as plain text. Archives not uploaded yet are read from the local spool. At most 50 archives are read; X-Archive-Count holds the number read and X-Archive-Truncated is set when more archives match.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Fetch archived logs of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "stdout or stderr",
+                        "name": "stream",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Output after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Output before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Archived output",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or no archive in range",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Log archiving is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/logs/files": {
+            "get": {
+                "description": "List the log files of tail_files tailed for the running service, whose lines are merged into its logs prefixed with \"[source] \". Glob patterns are matched again every 5 seconds; files are followed through rotation, whether renamed or truncated. Empty when the service is not running.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Get the tailed log files of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/TailedFile"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/logs/ws": {
+            "get": {
+                "description": "Upgrade to a WebSocket connection streaming buffered and live logs. Log lines can be filtered server-side with the query parameters below, or later by sending {\"type\":\"subscribe\",\"filter\":{\"level\":\"warn\",\"include\":\"...\",\"exclude\":\"...\"}}. Each log message carries the capture time of its line in UTC (time); prefixing lines with it is toggled by timestamps and tz, or later by sending {\"type\":\"display\",\"timestamps\":true,\"tz\":\"...\"}.",
+                "tags": [
+                    "logs"
+                ],
+                "summary": "Stream project logs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum log level (trace, debug, info, warn, error, fatal)",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stream lines matching this regex",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Drop lines matching this regex",
+                        "name": "exclude",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Prefix lines with their capture time",
+                        "name": "timestamps",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Timezone of the prefixes: IANA name or +HH:MM (default UTC)",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching protocols"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/monitors": {
+            "get": {
+                "description": "List the synthetic monitors of a project with their state (up, slow, down, paused or pending), last check and availability over the last 24 hours",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitors"
+                ],
+                "summary": "List synthetic monitors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/MonitorSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a synthetic monitor to a project: a request sent every interval seconds (60 by default, at least 10) while the project is running or in error. url is a /path sent to the project port, or its first declared TCP port, on 127.0.0.1 (or else to the host of its health_check_url), or a full http(s) URL. A check is up with the expected_status (any below 400 when 0) and a body containing body_contains, and slow when up but slower than latency_threshold_ms. After failure_threshold (2) consecutive failed checks a critical project_monitor alert is raised, after as many slow checks a warning one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitors"
+                ],
+                "summary": "Create a synthetic monitor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Monitor",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MonitorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectMonitor"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid monitor",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/monitors/{monitor_id}": {
+            "put": {
+                "description": "Replace the request, schedule and thresholds of a synthetic monitor. Its checks are kept; its alert is resolved, as the monitor may have been renamed or paused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitors"
+                ],
+                "summary": "Replace a synthetic monitor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Monitor ID",
+                        "name": "monitor_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Monitor",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MonitorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectMonitor"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid monitor",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Monitor not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a synthetic monitor with its checks, resolving its alert",
+                "tags": [
+                    "monitors"
+                ],
+                "summary": "Delete a synthetic monitor",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Monitor ID",
+                        "name": "monitor_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Monitor not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/monitors/{monitor_id}/check": {
+            "post": {
+                "description": "Send the request of a synthetic monitor right away, whatever the project status, and store the check like a scheduled one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitors"
+                ],
+                "summary": "Check a synthetic monitor now",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Monitor ID",
+                        "name": "monitor_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Check, up or not",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/MonitorCheck"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Monitor not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/monitors/{monitor_id}/stats": {
+            "get": {
+                "description": "Get the availability of a synthetic monitor, the percentiles of its latency and a latency histogram (non-cumulative buckets up to 50, 100, 250, 500, 1000, 2500, 5000 ms and +Inf) over the last hours. Latencies only count checks that got an answer. The latency and availability over time are dashboard metrics (monitor_latency, monitor_availability).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "monitors"
+                ],
+                "summary": "Get synthetic monitor statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Monitor ID",
+                        "name": "monitor_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of checks (default 24, max 168)",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/MonitorStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Monitor not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/pause": {
+            "post": {
+                "description": "Freeze the running service of a project with SIGSTOP: its process, their descendants and its extra instances. They keep their memory, state and connections but use no CPU until resumed with POST /projects/{id}/resume, or after duration seconds when given. The project status is \"paused\" meanwhile; monitors are skipped and stopping or restarting the project resumes it first. Services run through Kubernetes, systemd or SSH cannot be paused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Pause a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Automatic resume",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/PauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project paused",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ServicePause"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Service not running or already paused",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "No local process to pause",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/ports": {
+            "get": {
+                "description": "List the ports declared by a project with whether each one is currently listening",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ports"
+                ],
+                "summary": "Get declared ports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Declared ports",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/DeclaredPortStatus"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the declared ports of a project. Ports already declared by another project are rejected with 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ports"
+                ],
+                "summary": "Replace declared ports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Declared ports",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateProjectPortsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated ports",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ProjectPort"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Port owned by another project",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/PortConflict"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/priority": {
+            "get": {
+                "description": "Get the nice value, IO scheduling class (ionice) and CPU affinity (taskset) of a project, applied at each start, and the ones the processes of its running tree have",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get the CPU and IO priority of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/PriorityStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Save the nice value, IO scheduling class and CPU affinity of a project and apply them right away to its running process tree with renice, ionice and taskset, so a background worker can be slowed down or pinned to a few cores during a big build without a restart. Raising a lowered priority again, a negative nice and the realtime class need root (CAP_SYS_NICE); failures to apply are listed in errors, the priority is saved for the next start anyway. ionice and taskset are only available on Linux.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Change the CPU and IO priority of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Priority",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SetPriorityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/PriorityStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid priority",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/process/metrics": {
+            "get": {
+                "description": "Get the samples of the process tree of a project taken every 30 seconds while it runs, oldest first: CPU, memory, number of processes and the bytes per second it reads from and writes to disk (storage I/O, not page cache hits), and the TCP bytes per second it sends and receives, to chart which service is busy, thrashing the disk or using the network. TCP traffic is attributed on Linux by matching the sockets the processes hold to the counters of the kernel; connections opened and closed between two samples are missed. Samples are kept for 7 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get process metrics history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of history (default 1, max 168)",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ProcessMetric"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/profile": {
+            "post": {
+                "description": "Fetch a CPU, heap, allocs or goroutine profile from a running Go project exposing net/http/pprof, as a background job, and store it. The pprof base URL is pprof_url, or http://127.0.0.1:\u003cport\u003e/debug/pprof. With flamegraph the SVG flame graph is rendered as well; it is otherwise rendered on first request. The job result is the stored ProjectProfile; the newest 20 profiles are kept per project.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Capture a pprof profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile to capture",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Profile job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or no pprof URL",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Service is not running or a profile is already being captured",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/profiles": {
+            "get": {
+                "description": "Get the pprof profiles captured from a project, newest first, without their data",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List profiles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ProjectProfile"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/profiles/{profile_id}": {
+            "delete": {
+                "description": "Delete a captured profile and its flame graph",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Delete a profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "profile_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/profiles/{profile_id}/download": {
+            "get": {
+                "description": "Download a captured profile in the gzipped pprof format, for go tool pprof",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Download a profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "profile_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "pprof profile",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/profiles/{profile_id}/flamegraph": {
+            "get": {
+                "description": "Get the SVG flame graph of a captured profile, rendered and stored on first request. Callers are drawn below their callees; hovering a frame shows its value.",
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get the flame graph of a profile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Profile ID",
+                        "name": "profile_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "SVG flame graph",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Profile cannot be read",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/proxy/{path}": {
+            "get": {
+                "description": "Forward a request of any method, WebSocket upgrades included, to the project port on 127.0.0.1, with the path after /proxy and the query string. The prefix is passed in X-Forwarded-Prefix. A project with an idle_timeout is started when it is not running, the request waiting until its port accepts connections (idle.wake_timeout), and is stopped again after idle_timeout minutes without proxied requests. During a blue/green restart (restart_strategy) new requests go to the new instance while those in flight finish on the old one. Projects with more than one instance (instances) get requests round robin. Requests are held first while latency is injected (POST /projects/{id}/chaos/latency). Requests are counted in GET /projects/{id}/traffic.",
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Proxy a request to the project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Path on the project",
+                        "name": "path",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Response of the project"
+                    },
+                    "400": {
+                        "description": "Project has no port",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Project not reachable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Project failed to start",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/queues": {
+            "get": {
+                "description": "Poll the broker of a queue project for queue depth and consumers: RabbitMQ through its management API (amqp:// or http:// connection string), Redis lists, sorted sets and stream consumer groups (redis://, keys listed in queues) or Kafka consumer group lag (kafka://, needs kafka-consumer-groups on PATH). Queue projects are also checked every 30 seconds; each check is stored as metrics and the last one is included as \"queue_stats\" in the project status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Check a queue project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Check result, healthy or not",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/QueueStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No connection string",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/queues/metrics": {
+            "get": {
+                "description": "Get the queue depth samples of a queue project, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get queue depth history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Queue name",
+                        "name": "queue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of history (default 1, max 168)",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/QueueMetric"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/restart": {
+            "post": {
+                "description": "Stop and start the service process of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Restart a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project restarted",
+                        "schema": {
+                            "$ref": "#/definitions/ProjectActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/resume": {
+            "post": {
+                "description": "Continue the processes of a project frozen by POST /projects/{id}/pause with SIGCONT; its status goes back to running.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Resume a paused project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project resumed",
+                        "schema": {
+                            "$ref": "#/definitions/ProjectActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Service is not paused",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/runtime": {
+            "get": {
+                "description": "Read live details of the running process of a project and its descendants to debug it without leaving the dashboard: the sockets they listen on, TCP connections by state, threads, open file descriptors against the limit, working directory, executable, the size of the environment (not its values) and the child processes. Details the runner may not read, e.g. of processes of another user, are listed in errors.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get runtime info of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Runtime info",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/RuntimeInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Service is not running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Process runs on another host",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/runtime-env": {
+            "get": {
+                "description": "Read the environment of the running process (/proc/\u003cpid\u003e/environ, Linux only) and diff it against the environment the project would be started with now. Stale and missing values mean the service must be restarted to pick up configuration edits.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get runtime environment diff",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include variables whose value is unchanged",
+                        "name": "all",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Runtime environment diff",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/RuntimeEnvReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Service is not running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "501": {
+                        "description": "Not supported on this platform",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/scripts": {
+            "get": {
+                "description": "Discover the targets of the project Makefile (GNUmakefile, makefile or Makefile; special and pattern rules excluded, \"## text\" comments used as descriptions) and the scripts of package.json, run with the package manager whose lockfile is present.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List project scripts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ProjectScripts"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/scripts/run": {
+            "post": {
+                "description": "Run a Makefile target or package.json script in the project directory as a background job and return the job immediately, with the variables of a project start (.env, env_vars, PORT). Output is streamed to the project WebSocket as \"script_log\" messages (InstallLogLine) and job progress as \"job_update\". The same script does not run twice at a time; list past runs with GET /jobs?type=script\u0026project_id={id}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Run a project script",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Script to run",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RunScriptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Script job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request or ambiguous name",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project or script not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The script is already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "make or the package manager not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/smoke-tests": {
+            "get": {
+                "description": "Get the smoke test runs of a project, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List smoke test runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs (default 20, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/SmokeTestRun"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/smoke-tests/run": {
+            "post": {
+                "description": "Send the smoke_tests of a project (a JSON array of {\"name\", \"method\", \"path\", \"headers\", \"body\", \"status\", \"body_contains\", \"timeout\"}) to it and store the run. Paths are sent to the project port, or its first declared TCP port, on 127.0.0.1, or else to the host of its health_check_url; full http(s) URLs are sent as-is. A test passes with the expected status (any below 400 when 0) and a body containing body_contains. With smoke_tests_on_start, the tests also run after every start (requests without an answer are sent again for up to 30 seconds), and with smoke_tests_fail_error a failed run sets the project status to error while a passed run sets it back to running. The run is published as a \"smoke_test\" event, and the last one is shown as smoke_test_run in the project status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Run project smoke tests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Smoke test run, passed or not",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SmokeTestRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No or invalid smoke tests",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Smoke tests are already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/start": {
+            "post": {
+                "description": "Start the service process of a project. A start waiting for its wait_for conditions or a start queue slot is answered with 202 and goes on in the background; stopping the project cancels it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Start a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project started",
+                        "schema": {
+                            "$ref": "#/definitions/ProjectActionResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Project waiting to start",
+                        "schema": {
+                            "$ref": "#/definitions/ProjectActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/status": {
+            "get": {
+                "description": "Get the verified runtime status of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get project status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project status",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Project"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/stop": {
+            "post": {
+                "description": "Stop the service process of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Stop a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project stopped",
+                        "schema": {
+                            "$ref": "#/definitions/ProjectActionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Another operation is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/systemd": {
+            "get": {
+                "description": "Read the unit of a \"systemd\" project now (ActiveState, SubState, main PID) and sync the project status with it. systemd projects are also checked every 15 seconds; the last result is included as \"systemd\" in the project status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Get the unit of a systemd project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SystemdUnit"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Not a systemd project",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/terminal": {
+            "get": {
+                "description": "Get the absolute project path and instructions to open a terminal there",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get terminal information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Terminal information",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/TerminalInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/terminal/open": {
+            "post": {
+                "description": "Build the command that opens a terminal in the project directory for the given OS",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Open a terminal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target OS",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/OpenTerminalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Terminal command",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/TerminalInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/test": {
+            "post": {
+                "description": "Run the project test_command, or the detected one (go test -json ./... for go.mod, the package.json test script with a Jest or Vitest JSON report, pytest with a JUnit XML report) as a background job, and store the pass/fail counts and failed tests as a test run. Output is streamed to the project WebSocket as \"test_log\" messages (InstallLogLine), progress as \"test_progress\" (TestProgress) and the stored run as \"test_result\". The job fails when tests fail; its result is the TestRun either way.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Run project tests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Extra arguments",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/TestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Test job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No test command found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tests are already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/test/runs": {
+            "get": {
+                "description": "Get the test run history of a project, newest first, without the failed tests",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List test runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs (default 20, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/TestRun"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/test/runs/{run_id}": {
+            "get": {
+                "description": "Get a test run of a project with its failed tests",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get a test run",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Test run ID",
+                        "name": "run_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/TestRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Test run not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/timeline": {
+            "get": {
+                "description": "Get the status transitions of a project as segments, with uptime (time running over known time) overall and per bucket to render an uptime bar. Time before the first recorded transition is \"unknown\". Transitions are kept for 90 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get the status timeline of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of history (default 24, max 2160)",
+                        "name": "hours",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of uptime buckets (default 24, max 200)",
+                        "name": "buckets",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/StatusTimeline"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/traffic": {
+            "get": {
+                "description": "Get the requests proxied to a project through /projects/{id}/proxy: request and error counts with the CPU, memory and uptime of its process (stats), status codes and latency percentiles of the recent requests since go-runner started (live), and per-minute samples with status classes and latency percentiles (history, oldest first). Errors are 5xx responses, including requests the project could not be reached or started for.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get proxied traffic",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of history (default 1, max 168)",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/TrafficReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/tunnel": {
+            "get": {
+                "description": "Get the open tunnel of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get the public tunnel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Tunnel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or no tunnel",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Expose the project port publicly through cloudflared (quick tunnel), ngrok or an ssh remote forward to the configured relay host, and return the public URL. The tunnel process is closed when the project is stopped and is included as \"tunnel\" in the project status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Open a public tunnel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Provider and port",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/StartTunnelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tunnel open",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Tunnel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "No port",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tunnel already open",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Tunnel failed to start",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop the tunnel process of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Close the public tunnel",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tunnel closed",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or no tunnel",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Search everything go-runner knows for a command palette: projects by name, description, path, owner and team, groups by name and description, the recent logs of each project (at most 5 lines per project, for queries of 3 characters or more) and alert messages. Matching ignores case; names also match by their initials or letters in order (pa for payments-api). Results are ranked within each category: exact names, then prefixes, word prefixes and substrings, name matches before other fields; newer log lines and active, newer alerts first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search projects, groups, logs and alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text to search",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated categories to search: projects, groups, logs, alerts (default all)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Results per category (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SearchResults"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Missing query or invalid category",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/autostart": {
+            "get": {
+                "description": "Get the outcome of the autostart run performed when the server started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get autostart results",
+                "responses": {
+                    "200": {
+                        "description": "Autostart results",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/AutostartSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Autostart has not run yet",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/reconcile": {
+            "get": {
+                "description": "Get what happened, when the server started, to the processes of projects that were running: adopted when their PID, start time and command line matched the ones recorded on start, stopped when they exited or their PID was reused, unverified otherwise. Unverified processes keep running until adopted (POST /services/{id}/adopt) or stopped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get reconcile results",
+                "responses": {
+                    "200": {
+                        "description": "Reconcile results",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ReconcileSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Reconcile has not run yet",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/running": {
+            "get": {
+                "description": "Get the services running now, sorted by name: the processes of this server and the projects the database marks running whose process is still alive (or which run on an SSH host), with their uptime, port and the CPU and memory of their process tree at the last sample",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get running services",
+                "responses": {
+                    "200": {
+                        "description": "Running services",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/RunningService"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/services/summary": {
+            "get": {
+                "description": "Count the services by status and group, with the CPU, memory, disk I/O and TCP traffic of those running. A project counts as running when GET /services/running lists it, and as \"stale\" when the database still marks it running but its process is gone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Summarize services",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ServicesSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/adopt": {
+            "post": {
+                "description": "Monitor the recorded process of a project again after a server restart, even if its identity could not be verified. Its output is tailed when it goes to a file, and its identity is recorded for the next restart.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Adopt a running process",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ReconciledProcess"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid project ID",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No running process, or already monitored",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/status.json": {
+            "get": {
+                "description": "Get the state and daily uptime over the last 90 days of the projects with \"status_page\": true, under their status_page_name. Read-only and meant to be public: no IDs, paths or errors are included. The HTML page is served at /status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Public status page data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/StatusPage"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/alerts": {
+            "get": {
+                "description": "Get system alerts with filtering options",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get system alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert type filter",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alert level filter",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Active alerts only",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "System alerts",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/SystemAlert"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/cleanup": {
+            "get": {
+                "description": "List reclaimable space on the host: package manager caches (npm, Yarn, pnpm, pip), Go build cache entries unused for 7 days, dangling Docker images and build cache, and go-runner's own data (old metrics and alerts, logs of deleted projects, free SQLite pages). Items whose tool is not installed are listed as unavailable. Nothing is removed; see POST /system/cleanup.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Scan for reclaimable space",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/CleanupReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Clean the selected items of GET /system/cleanup as a background job and return the job immediately. Progress is reported per item (\"job_update\" WebSocket messages, GET /jobs/{id}); the result lists the space freed by each item. Only one cleanup runs at a time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Clean reclaimable space",
+                "parameters": [
+                    {
+                        "description": "Items to clean",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CleanupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Cleanup job queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown item",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A cleanup is already running",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/config": {
+            "get": {
+                "description": "Get system monitoring configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get system configuration",
+                "responses": {
+                    "200": {
+                        "description": "System configuration",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SystemConfig"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update system monitoring configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Update system configuration",
+                "parameters": [
+                    {
+                        "description": "System configuration",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SystemConfig"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated configuration",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SystemConfig"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/config/export": {
+            "get": {
+                "description": "Export the system config (collection settings, alert thresholds and notification channels), connectivity targets and maintenance windows that are recurring or not over yet as YAML. Maintenance windows name their projects, so the file can be imported on machines where the project IDs differ. PagerDuty, Opsgenie and webhook integrations live in config.yaml and are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Export the monitoring configuration",
+                "responses": {
+                    "200": {
+                        "description": "YAML configuration",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/config/import": {
+            "post": {
+                "description": "Apply a YAML file from GET /system/config/export. The system, alert_rules and notification_channels sections update the fields they set; connectivity targets and maintenance windows are created or updated by name, and with prune those missing from the file are deleted. Sections missing from the file are left alone. Nothing is changed when any part of the file is invalid, or with dry_run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Import a monitoring configuration",
+                "parameters": [
+                    {
+                        "description": "Configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ImportMonitoringRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ImportMonitoringResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid configuration",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/connectivity": {
+            "get": {
+                "description": "List the hosts probed by the system service with their last result",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "List connectivity targets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ConnectivityTarget"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Probe a host every interval_seconds: ping (ICMP through the ping command), tcp (connect to host:port) or http (GET, 5xx fails). After failure_threshold consecutive failures the target is unreachable and a \"connectivity\" alert is raised; it is resolved when the target answers again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Add a connectivity target",
+                "parameters": [
+                    {
+                        "description": "Target",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ConnectivityTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ConnectivityTarget"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid target",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/connectivity/{id}": {
+            "put": {
+                "description": "Replace the settings of a target; its status is kept unless the type or target changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Update a connectivity target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ConnectivityTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ConnectivityTarget"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid target",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Target not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop probing a target, resolving its alert",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Delete a connectivity target",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Target deleted",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Target not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/connectivity/{id}/check": {
+            "post": {
+                "description": "Probe a target immediately and record the result as a scheduled check would",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Check a connectivity target now",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Target ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/ConnectivityTarget"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Target not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/dashboard": {
+            "get": {
+                "description": "Get system dashboard with overview information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get system dashboard",
+                "responses": {
+                    "200": {
+                        "description": "System dashboard",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SystemDashboard"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/docker": {
+            "get": {
+                "description": "Report the containers of the local Docker daemon with the CPU and memory of running ones (docker stats), the image count and disk usage (docker system df). Containers are listed whether or not go-runner started them. When docker is not installed or the daemon is down, available is false with the reason in error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get Docker daemon status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/DockerOverview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/docker/containers/{id}/restart": {
+            "post": {
+                "description": "Restart a container of the local Docker daemon by ID or name (docker restart); stopped containers are started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Restart a Docker container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Container ID or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DataMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid container ID",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Container not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Docker not available",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/docker/containers/{id}/stop": {
+            "post": {
+                "description": "Stop a container of the local Docker daemon by ID or name (docker stop)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Stop a Docker container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Container ID or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DataMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid container ID",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Container not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Docker not available",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/info": {
+            "get": {
+                "description": "Get comprehensive system information including CPU, memory, disk, and network",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get system information",
+                "responses": {
+                    "200": {
+                        "description": "System information",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SystemInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/internals": {
+            "get": {
+                "description": "Report WebSocket clients per project with their queued messages, the log channel backlog and dropped lines of each running service, recent start/stop/restart latencies and database write rates. A growing log channel backlog points at go-runner's consumers, a growing client queue at the browser, and neither at the service itself.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Report go-runner internals",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/InternalsReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/metrics": {
+            "get": {
+                "description": "Get historical system metrics with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get system metrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Hours of data to retrieve",
+                        "name": "hours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "System metrics",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/SystemMetrics"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/metrics/cleanup": {
+            "post": {
+                "description": "Clear metrics older than specified days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Clear old metrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Days to keep (default: 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cleanup result",
+                        "schema": {
+                            "$ref": "#/definitions/CleanupResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/metrics/query": {
+            "get": {
+                "description": "Read several metrics over a time range, aggregated into buckets of step each, as columns: one list of bucket timestamps and one list of values per series, null where a bucket has no sample, so a chart can plot them as they come. Metrics are those of GET /dashboards/metrics; system metrics give one series, project metrics one per listed project, or per project with data when project_ids is empty. Buckets aggregate their samples with avg (default), max or p95. The step defaults to the range divided by 120 and a query holds at most 5000 buckets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Query metric series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated metrics, e.g. cpu_usage,memory_usage,latency_p95",
+                        "name": "series",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated projects of the project metrics",
+                        "name": "project_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time range: 15m, 6h, 7d... (default 1h, max 90d)",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range, RFC 3339 (default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket size: 30s, 5m, 1h... (at least 1s)",
+                        "name": "step",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregation: avg, max or p95 (default avg)",
+                        "name": "agg",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/QueryResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/network/diagnostics": {
+            "get": {
+                "description": "Report the DNS servers and search domains of /etc/resolv.conf, the resolution time of host names (the npm, GitHub, Go and PyPI registries unless hosts is set) with the proxy each would use, the proxy environment variables and npm proxy and registry settings (credentials masked), and the default gateway. Meant for installs that hang on one machine.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get network diagnostics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated host names to resolve",
+                        "name": "hosts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/NetworkDiagnostics"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/orphans": {
+            "get": {
+                "description": "Scan for zombie processes and for service processes no longer under their project process: started by a previous go-runner (previous_session), left behind by a stop or daemonized (detached), or adopted by init while running in a project directory (reparented). Service processes are recognized by the GO_RUNNER_PROJECT_ID and GO_RUNNER_SESSION variables set on start. The host is also scanned every minute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "List zombie and orphaned processes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/OrphanReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Processes not readable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/orphans/cleanup": {
+            "post": {
+                "description": "Send SIGTERM to the given orphans, or when pids is empty to every previous_session and detached orphan (reparented ones are matched by directory only and must be listed), and SIGKILL to those still running after 5 seconds. Only processes a fresh scan reports as orphans are signaled; zombies are reaped by their parent and cannot be cleaned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Terminate orphaned processes",
+                "parameters": [
+                    {
+                        "description": "Processes to terminate",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/CleanupOrphansRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/OrphanCleanup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Processes not readable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/power": {
+            "get": {
+                "description": "Report whether low-power mode is active, the configured mode (auto follows the host power source, on and off force it), whether the host runs on battery and the optional projects suspended. In low-power mode metrics collection, alert checks, database, queue and file descriptor monitors and the watch_files refresh wait interval_factor times longer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get low-power mode",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/PowerStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Turn low-power mode on or off, or back to auto to follow the power source. Entering it suspends the running projects marked optional, leaving it resumes them. Lasts until go-runner restarts; set power.mode in config.yaml to keep it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Set low-power mode",
+                "parameters": [
+                    {
+                        "description": "Mode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SetPowerModeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataMessageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/PowerStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid mode",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/status": {
+            "get": {
+                "description": "Get current system status and health indicators",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get system status",
+                "responses": {
+                    "200": {
+                        "description": "System status",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/SystemStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/units": {
+            "get": {
+                "description": "List the service units of the host (or of the user manager with user=true), to create \"systemd\" projects for daemons such as postgres or nginx.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "List systemd service units",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Units of the user manager (systemctl --user)",
+                        "name": "user",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/SystemdUnitSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "502": {
+                        "description": "systemd request failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "systemd is not available",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens": {
+            "get": {
+                "description": "List the project API tokens, newest first, with their scopes and when and from where each was last used. The tokens themselves are not stored and cannot be shown again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "List project tokens",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only the tokens of this project",
+                        "name": "project_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/APIToken"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Issue a token that can only operate on one project, within its scopes, for example for a CI pipeline restarting its service on staging: status (GET the project and its status), logs (its logs and log stream), start, stop and restart. The token is returned once; only its hash is stored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Create a project token",
+                "parameters": [
+                    {
+                        "description": "Token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/CreatedToken"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or scope",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{id}": {
+            "delete": {
+                "description": "Delete a project token; requests using it are refused from now on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Revoke a project token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/github": {
+            "post": {
+                "description": "Webhook for the workflow_run events of a GitHub repository, signed with ci.webhook_secret (X-Hub-Signature-256). Updates the CI status of the projects of the repository and branch of the run. Served at /webhooks/github, outside the API base path.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ci"
+                ],
+                "summary": "Receive GitHub workflow runs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/WebhookResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Webhooks disabled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/gitlab": {
+            "post": {
+                "description": "Webhook for the pipeline events of a GitLab project, with ci.webhook_secret as its secret token (X-Gitlab-Token). Updates the CI status of the projects of the repository and branch of the pipeline. Served at /webhooks/gitlab, outside the API base path.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ci"
+                ],
+                "summary": "Receive GitLab pipelines",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/WebhookResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Webhooks disabled",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/clients": {
+            "get": {
+                "description": "List the clients connected to project WebSocket streams, oldest first, with the project each one is subscribed to, its remote IP, how long it has been connected, when it last answered, its queued messages and its log filter and display settings; for debugging why someone does not see the logs of a project",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "List WebSocket clients",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/ClientInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/clients/{client_id}": {
+            "delete": {
+                "description": "Close the connection of a WebSocket client; browsers of the dashboard reconnect on their own, with a new ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Disconnect a WebSocket client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid client ID",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not connected",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "APIInfoResponse": {
+            "type": "object",
+            "properties": {
+                "api": {
+                    "type": "string"
+                },
+                "docs": {
+                    "type": "string"
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "APIToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Never when null",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "First characters of the token, to recognize it",
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "use_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Action": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "destructive": {
+                    "description": "Ask for confirmation first",
+                    "type": "boolean"
+                },
+                "entity": {
+                    "description": "project, group or system",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Stable, e.g. project.restart",
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Param"
+                    }
+                },
+                "path": {
+                    "description": "API route run, below /api/v1",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "AlertSearchHit": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "Project of the process or queue of the alert",
+                    "type": "integer"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "ApplyResult": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PlanChange"
+                    }
+                },
+                "create": {
+                    "type": "integer"
+                },
+                "delete": {
+                    "type": "integer"
+                },
+                "spec_id": {
+                    "description": "Not set with dry_run",
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "update": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LintIssue"
+                    }
+                }
+            }
+        },
+        "Archive": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Upload attempts",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Last upload error",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Object key in the bucket",
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "raw_size": {
+                    "description": "Bytes of the rotated file",
+                    "type": "integer"
+                },
+                "rotated_at": {
+                    "description": "End of the output covered",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Bytes uploaded, after compression",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "Previous rotation, unknown for the first archive of a stream",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, uploaded or failed",
+                    "type": "string"
+                },
+                "stream": {
+                    "description": "stdout or stderr",
+                    "type": "string"
+                },
+                "uploaded_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "AuditEvent": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "data": {},
+                "id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "AuditFinding": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "CVE-..., ...",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "direct": {
+                    "description": "Direct dependency (npm)",
+                    "type": "boolean"
+                },
+                "fixed_in": {
+                    "description": "First fixed version, or the latest version for outdated dependencies",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Advisory ID (GHSA-..., GO-..., PYSEC-...)",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "vulnerability, outdated",
+                    "type": "string"
+                },
+                "package": {
+                    "type": "string"
+                },
+                "reachable": {
+                    "description": "Vulnerable code is called by the project (govulncheck)",
+                    "type": "boolean"
+                },
+                "severity": {
+                    "description": "critical, high, moderate, low, info, unknown",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Installed version or affected range",
+                    "type": "string"
+                }
+            }
+        },
+        "AuditRequest": {
+            "type": "object",
+            "properties": {
+                "ecosystem": {
+                    "description": "Detected from package.json, go.mod or requirements.txt/pyproject.toml when empty",
+                    "type": "string",
+                    "enum": [
+                        "npm",
+                        "go",
+                        "pip"
+                    ]
+                }
+            }
+        },
+        "AuditSummary": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "type": "integer"
+                },
+                "high": {
+                    "type": "integer"
+                },
+                "info": {
+                    "type": "integer"
+                },
+                "low": {
+                    "type": "integer"
+                },
+                "moderate": {
+                    "type": "integer"
+                },
+                "outdated": {
+                    "description": "Outdated direct dependencies",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Vulnerabilities",
+                    "type": "integer"
+                },
+                "unknown": {
+                    "type": "integer"
+                }
+            }
+        },
+        "AutostartResult": {
+            "type": "object",
+            "properties": {
+                "dependency": {
+                    "description": "Started only because a flagged project depends on it",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "started, already_running, failed, skipped",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "AutostartSummary": {
+            "type": "object",
+            "properties": {
+                "finished_at": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AutostartResult"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "CPUInfo": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Number of CPU cores",
+                    "type": "integer"
+                },
+                "load_avg": {
+                    "description": "Load average (1min, 5min, 15min)",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "mhz": {
+                    "description": "CPU frequency in MHz",
+                    "type": "number"
+                },
+                "model_name": {
+                    "description": "CPU model name",
+                    "type": "string"
+                },
+                "usage": {
+                    "description": "CPU usage percentage",
+                    "type": "number"
+                }
+            }
+        },
+        "ChaosKillResult": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "description": "Instance, 0 for the main process",
+                    "type": "integer"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "ChaosLatencyRequest": {
+            "type": "object",
+            "required": [
+                "delay_ms"
+            ],
+            "properties": {
+                "delay_ms": {
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 1
+                },
+                "duration": {
+                    "description": "Seconds, 60 when 0",
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 1
+                },
+                "jitter_ms": {
+                    "description": "Up to this much more, at random",
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 0
+                }
+            }
+        },
+        "ChaosPauseRequest": {
+            "type": "object",
+            "required": [
+                "duration"
+            ],
+            "properties": {
+                "duration": {
+                    "description": "Seconds",
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 1
+                }
+            }
+        },
+        "ChaosStatus": {
+            "type": "object",
+            "properties": {
+                "latency": {
+                    "$ref": "#/definitions/ProxyLatency"
+                },
+                "pause": {
+                    "$ref": "#/definitions/ServicePause"
+                }
+            }
+        },
+        "CleanDiskRequest": {
+            "type": "object",
+            "properties": {
+                "force": {
+                    "description": "Also remove while the project is running",
+                    "type": "boolean"
+                },
+                "kinds": {
+                    "description": "Every directory of these kinds",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "paths": {
+                    "description": "Artifact directories from GET /disk-usage",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CleanDiskResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "freed_bytes": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DiskUsageEntry"
+                    }
+                }
+            }
+        },
+        "CleanupItem": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "What the cleanup runs",
+                    "type": "string"
+                },
+                "available": {
+                    "type": "boolean"
+                },
+                "bytes": {
+                    "description": "Reclaimable bytes, 0 when only rows are known",
+                    "type": "integer"
+                },
+                "category": {
+                    "description": "cache, docker, go-runner",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Why the item is unavailable",
+                    "type": "string"
+                },
+                "rows": {
+                    "description": "Database rows removed",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "CleanupOrphansRequest": {
+            "type": "object",
+            "properties": {
+                "pids": {
+                    "description": "From GET /system/orphans, every marked orphan when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "CleanupReport": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CleanupItem"
+                    }
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "total_bytes": {
+                    "description": "Of available items",
+                    "type": "integer"
+                }
+            }
+        },
+        "CleanupRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "description": "Item IDs from GET /system/cleanup",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CleanupResult": {
+            "type": "object",
+            "properties": {
+                "cutoff_time": {
+                    "type": "string"
+                },
+                "deleted_count": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "ClientInfo": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "connected_at": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "filter": {
+                    "$ref": "#/definitions/LogFilterSpec"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_seen": {
+                    "description": "Last message or answer to a ping (every 54s); past 60s the connection is dropped",
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "Project the client is subscribed to",
+                    "type": "integer"
+                },
+                "queued": {
+                    "description": "Messages waiting to be written, disconnected at capacity",
+                    "type": "integer"
+                },
+                "remote_ip": {
+                    "type": "string"
+                },
+                "sent": {
+                    "description": "Messages queued to the client since it connected",
+                    "type": "integer"
+                },
+                "timestamps": {
+                    "type": "boolean"
+                },
+                "timezone": {
+                    "$ref": "#/definitions/TimezoneInfo"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "ClockInfo": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Why the offset could not be measured",
+                    "type": "string"
+                },
+                "ntp_server": {
+                    "type": "string"
+                },
+                "offset_ms": {
+                    "description": "Local clock minus NTP time, positive when ahead",
+                    "type": "number"
+                },
+                "round_trip_ms": {
+                    "description": "Network delay of the query",
+                    "type": "number"
+                },
+                "synchronized": {
+                    "description": "The OS reports NTP synchronization (timedatectl), null when unknown",
+                    "type": "boolean"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "Comparison": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "$ref": "#/definitions/ComparisonDelta"
+                },
+                "from": {
+                    "$ref": "#/definitions/ComparisonSide"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "starts": {
+                    "description": "Recent starts, newest first, to pick events from",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectStatusHistory"
+                    }
+                },
+                "step_seconds": {
+                    "type": "integer"
+                },
+                "to": {
+                    "$ref": "#/definitions/ComparisonSide"
+                },
+                "window_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ComparisonDelta": {
+            "type": "object",
+            "properties": {
+                "avg_cpu_percent": {
+                    "type": "number"
+                },
+                "avg_memory_bytes": {
+                    "type": "number"
+                },
+                "error_rate": {
+                    "type": "number"
+                },
+                "max_memory_bytes": {
+                    "type": "number"
+                },
+                "memory_growth_per_hour": {
+                    "type": "number"
+                }
+            }
+        },
+        "ComparisonPoint": {
+            "type": "object",
+            "properties": {
+                "cpu_percent": {
+                    "type": "number"
+                },
+                "error_rate": {
+                    "description": "Percent of requests, null without requests",
+                    "type": "number"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "type": "number"
+                },
+                "offset_seconds": {
+                    "description": "Start of the step, from the event",
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ComparisonSide": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "description": "Markers in the window: deploys, config changes and notes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectAnnotation"
+                    }
+                },
+                "event": {
+                    "$ref": "#/definitions/ProjectStatusHistory"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ComparisonPoint"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/ComparisonSummary"
+                },
+                "until": {
+                    "description": "End of the window: the window length, the other event or now",
+                    "type": "string"
+                }
+            }
+        },
+        "ComparisonSummary": {
+            "type": "object",
+            "properties": {
+                "avg_cpu_percent": {
+                    "type": "number"
+                },
+                "avg_memory_bytes": {
+                    "type": "number"
+                },
+                "error_rate": {
+                    "type": "number"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "max_cpu_percent": {
+                    "type": "number"
+                },
+                "max_memory_bytes": {
+                    "type": "number"
+                },
+                "memory_growth_per_hour": {
+                    "description": "Bytes, slope of a linear fit of the samples; steady growth hints at a leak",
+                    "type": "number"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "samples": {
+                    "description": "CPU and memory samples",
+                    "type": "integer"
+                }
+            }
+        },
+        "ConfigUpdateResult": {
+            "type": "object",
+            "required": [
+                "name",
+                "path"
+            ],
+            "properties": {
+                "args": {
+                    "description": "Additional arguments",
+                    "type": "string"
+                },
+                "audit": {
+                    "description": "Latest dependency audit, without findings (not stored on the project)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/DependencyAudit"
+                        }
+                    ]
+                },
+                "auto_restart": {
+                    "description": "Auto-restart settings",
+                    "type": "boolean"
+                },
+                "autostart": {
+                    "description": "Start when the go-runner server starts",
+                    "type": "boolean"
+                },
+                "chat_channel": {
+                    "description": "e.g. #payments-oncall, or a link to the channel",
+                    "type": "string"
+                },
+                "ci_branch": {
+                    "description": "Branch whose pipelines are shown, the checked out one when empty",
+                    "type": "string"
+                },
+                "ci_repo": {
+                    "description": "CI pipeline status (ci.github_token, ci.gitlab_token)",
+                    "type": "string"
+                },
+                "command": {
+                    "description": "Command to start the service",
+                    "type": "string"
+                },
+                "command_hash": {
+                    "description": "Hash of its command line, checked with it",
+                    "type": "string"
+                },
+                "connection_string": {
+                    "description": "Database and queue (types database, queue)",
+                    "type": "string"
+                },
+                "cpu_affinity": {
+                    "description": "CPU cores to run on, e.g. \"0-3,8\" (Linux); empty for all",
+                    "type": "string"
+                },
+                "cpu_limit": {
+                    "description": "Resource limits",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "declared_ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectPort"
+                    }
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/DeletedAt"
+                },
+                "depends_on": {
+                    "description": "Comma-separated names of projects that must start first",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "docs_url": {
+                    "description": "Documentation or runbook",
+                    "type": "string"
+                },
+                "drain_timeout": {
+                    "description": "Seconds the old instance of a blue/green restart keeps serving open proxied connections, 30 when 0",
+                    "type": "integer"
+                },
+                "editor": {
+                    "description": "IDE and development",
+                    "type": "string"
+                },
+                "editor_args": {
+                    "description": "Additional editor arguments",
+                    "type": "string"
+                },
+                "env_file": {
+                    "description": "Path to .env file",
+                    "type": "string"
+                },
+                "env_vars": {
+                    "description": "JSON object of environment variables",
+                    "type": "string"
+                },
+                "environment": {
+                    "description": "Environment and configuration",
+                    "type": "string"
+                },
+                "group": {
+                    "$ref": "#/definitions/ProjectGroup"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "health_check_url": {
+                    "description": "Health check",
+                    "type": "string"
+                },
+                "health_status": {
+                    "description": "healthy, unhealthy, unknown",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "idle_timeout": {
+                    "description": "Wake on demand (/projects/:id/proxy)",
+                    "type": "integer"
+                },
+                "inspect_port": {
+                    "description": "Node.js inspector port while debugging (POST /projects/:id/inspect), 0 when off;\ncleared by a manual stop",
+                    "type": "integer"
+                },
+                "instance_ports": {
+                    "description": "Ports of the extra instances: offset (default) for port + index, or auto for free ports",
+                    "type": "string"
+                },
+                "instances": {
+                    "description": "Copies of the service run side by side, the proxy balancing requests over them, 1 when 0",
+                    "type": "integer"
+                },
+                "io_class": {
+                    "description": "realtime, best-effort or idle; empty for the default",
+                    "type": "string"
+                },
+                "io_priority": {
+                    "description": "0 (highest) to 7 within realtime and best-effort",
+                    "type": "integer"
+                },
+                "kube_context": {
+                    "description": "Kubernetes deployment (kubernetes.enabled), start/stop scale it instead of running a process",
+                    "type": "string"
+                },
+                "kube_deployment": {
+                    "description": "Deployment name, a local process when empty",
+                    "type": "string"
+                },
+                "kube_namespace": {
+                    "description": "\"default\" when empty",
+                    "type": "string"
+                },
+                "kube_replicas": {
+                    "description": "Replicas restored on start, saved on stop",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "Last error message",
+                    "type": "string"
+                },
+                "logs": {
+                    "description": "Logs storage (JSON array of log lines, last 1000 lines)",
+                    "type": "string"
+                },
+                "max_restarts": {
+                    "type": "integer"
+                },
+                "mdns": {
+                    "description": "Local network announcement (mdns.enabled)",
+                    "type": "boolean"
+                },
+                "mdns_name": {
+                    "description": "Host label, the project name when empty",
+                    "type": "string"
+                },
+                "memory_guard_mb": {
+                    "description": "Memory guard: alert (and restart) when the RSS of the process tree stays above a ceiling",
+                    "type": "integer"
+                },
+                "memory_guard_restart": {
+                    "description": "Restart the service once the guard trips",
+                    "type": "boolean"
+                },
+                "memory_guard_samples": {
+                    "description": "Consecutive samples (30s apart) above it, 3 when 0",
+                    "type": "integer"
+                },
+                "memory_limit": {
+                    "description": "Memory limit (e.g., \"512Mi\")",
+                    "type": "string"
+                },
+                "migration_command": {
+                    "description": "Shell command run by the migrate action, with DATABASE_URL set",
+                    "type": "string"
+                },
+                "mock_spec": {
+                    "description": "Mock projects (type mock)",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Basic info",
+                    "type": "string"
+                },
+                "nice": {
+                    "description": "CPU and IO scheduling priority and CPU pinning, applied at start (PUT /projects/:id/priority changes them at runtime)",
+                    "type": "integer"
+                },
+                "optional": {
+                    "description": "Low-power mode",
+                    "type": "boolean"
+                },
+                "owner": {
+                    "description": "Service catalog, shown in listings and included in alert notifications",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path and execution",
+                    "type": "string"
+                },
+                "pid": {
+                    "description": "Process ID when running",
+                    "type": "integer"
+                },
+                "port": {
+                    "description": "Network and ports",
+                    "type": "integer"
+                },
+                "ports": {
+                    "description": "Deprecated: legacy JSON array of ports, use DeclaredPorts",
+                    "type": "string"
+                },
+                "pprof_url": {
+                    "description": "Profiling (POST /projects/:id/profile)",
+                    "type": "string"
+                },
+                "process_start": {
+                    "description": "Creation time of the process, identifies it with the PID after a server restart",
+                    "type": "string"
+                },
+                "queue_backlog_limit": {
+                    "description": "Alert when a queue holds more messages (0 = off)",
+                    "type": "integer"
+                },
+                "queue_growth_limit": {
+                    "description": "Alert when a queue grows faster, in messages per minute (0 = off)",
+                    "type": "integer"
+                },
+                "queues": {
+                    "description": "Comma-separated queues, redis keys or Kafka groups/topics to watch",
+                    "type": "string"
+                },
+                "repository_url": {
+                    "type": "string"
+                },
+                "restart_count": {
+                    "type": "integer"
+                },
+                "restart_strategy": {
+                    "description": "stop_start (default), or blue_green: start the new instance on another port, switch the proxy, then stop the old one",
+                    "type": "string"
+                },
+                "smoke_tests": {
+                    "description": "HTTP smoke tests (POST /projects/:id/smoke-tests/run)",
+                    "type": "string"
+                },
+                "smoke_tests_fail_error": {
+                    "description": "Set the status to error while they fail",
+                    "type": "boolean"
+                },
+                "smoke_tests_on_start": {
+                    "description": "Run them after every start",
+                    "type": "boolean"
+                },
+                "socket_path": {
+                    "description": "Unix domain socket the service listens on (readiness check)",
+                    "type": "string"
+                },
+                "ssh_host": {
+                    "description": "Remote host, start/stop/status run over SSH and logs are tailed from the host",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "When service started",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Service management",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ServiceStatus"
+                        }
+                    ]
+                },
+                "status_page": {
+                    "description": "Public status page (/status)",
+                    "type": "boolean"
+                },
+                "status_page_name": {
+                    "description": "Display name there, the project name when empty",
+                    "type": "string"
+                },
+                "stop_time": {
+                    "description": "When service stopped",
+                    "type": "string"
+                },
+                "systemd_unit": {
+                    "description": "systemd unit (type systemd), start/stop/restart run systemctl and logs come from journald",
+                    "type": "string"
+                },
+                "systemd_user": {
+                    "description": "Unit of the user manager (systemctl --user)",
+                    "type": "boolean"
+                },
+                "tail_files": {
+                    "description": "Log files the service writes itself, merged into its logs tagged with their path",
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "test_command": {
+                    "description": "Test command, detected from the project files when empty",
+                    "type": "string"
+                },
+                "trace_injection": {
+                    "description": "Tracing",
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/ServiceType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented by each configuration change; updates sending an older one are refused",
+                    "type": "integer"
+                },
+                "wait_for": {
+                    "description": "Comma-separated conditions checked before the command runs: tcp://host:port, http(s) URLs, file:// paths, postgres://, mysql:// or redis:// servers",
+                    "type": "string"
+                },
+                "wait_for_interval": {
+                    "description": "Seconds between checks, 1 when 0",
+                    "type": "integer"
+                },
+                "wait_for_timeout": {
+                    "description": "Seconds before the start fails, 60 when 0",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LintIssue"
+                    }
+                },
+                "watch_files": {
+                    "description": "File change feed",
+                    "type": "boolean"
+                },
+                "working_dir": {
+                    "description": "Working directory",
+                    "type": "string"
+                }
+            }
+        },
+        "ConnectivityTarget": {
+            "type": "object",
+            "properties": {
+                "alert_id": {
+                    "description": "Active alert while unreachable",
+                    "type": "integer"
+                },
+                "changed_at": {
+                    "description": "Last status change",
+                    "type": "string"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "failure_threshold": {
+                    "description": "Consecutive failures before unreachable",
+                    "type": "integer"
+                },
+                "failures": {
+                    "description": "Consecutive failed checks",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval_seconds": {
+                    "description": "Between checks",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Last result",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "Host, host:port or URL",
+                    "type": "string"
+                },
+                "timeout_seconds": {
+                    "description": "Of one check",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "ping, tcp, http",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ConnectivityTargetRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "target",
+                "type"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "Default true",
+                    "type": "boolean"
+                },
+                "failure_threshold": {
+                    "description": "Default 2",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "interval_seconds": {
+                    "description": "Default 30",
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 5
+                },
+                "name": {
+                    "type": "string"
+                },
+                "target": {
+                    "description": "Host for ping, host:port for tcp, URL for http",
+                    "type": "string"
+                },
+                "timeout_seconds": {
+                    "description": "Default 5",
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 1
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "ping",
+                        "tcp",
+                        "http"
+                    ]
+                }
+            }
+        },
+        "CreateAnnotationRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "details": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "kind": {
+                    "description": "note when empty",
+                    "type": "string",
+                    "enum": [
+                        "note",
+                        "deploy"
+                    ]
+                },
+                "message": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "revision": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "time": {
+                    "description": "Now when empty",
+                    "type": "string"
+                }
+            }
+        },
+        "CreateProjectGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "CreateProjectRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "path"
+            ],
+            "properties": {
+                "args": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "auto_restart": {
+                    "type": "boolean"
+                },
+                "autostart": {
+                    "type": "boolean"
+                },
+                "chat_channel": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "ci_branch": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "ci_repo": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "command": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "connection_string": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "cpu_affinity": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "cpu_limit": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "declared_ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectPort"
+                    }
+                },
+                "depends_on": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "docs_url": {
+                    "type": "string"
+                },
+                "drain_timeout": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0
+                },
+                "editor": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "editor_args": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "env_file": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "env_vars": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "environment": {
+                    "type": "string",
+                    "enum": [
+                        "development",
+                        "staging",
+                        "production"
+                    ]
+                },
+                "group": {
+                    "description": "Group name, instead of group_id",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "group_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "health_check_url": {
+                    "type": "string"
+                },
+                "idle_timeout": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "instance_ports": {
+                    "type": "string",
+                    "enum": [
+                        "offset",
+                        "auto"
+                    ]
+                },
+                "instances": {
+                    "type": "integer",
+                    "maximum": 16,
+                    "minimum": 0
+                },
+                "io_class": {
+                    "type": "string",
+                    "enum": [
+                        "realtime",
+                        "best-effort",
+                        "idle"
+                    ]
+                },
+                "io_priority": {
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                },
+                "kube_context": {
+                    "type": "string",
+                    "maxLength": 253
+                },
+                "kube_deployment": {
+                    "type": "string",
+                    "maxLength": 253
+                },
+                "kube_namespace": {
+                    "type": "string",
+                    "maxLength": 63
+                },
+                "kube_replicas": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "max_restarts": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 0
+                },
+                "mdns": {
+                    "type": "boolean"
+                },
+                "mdns_name": {
+                    "type": "string",
+                    "maxLength": 63
+                },
+                "memory_guard_mb": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "memory_guard_restart": {
+                    "type": "boolean"
+                },
+                "memory_guard_samples": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "memory_limit": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "migration_command": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "mock_spec": {
+                    "type": "string",
+                    "maxLength": 100000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "nice": {
+                    "type": "integer",
+                    "maximum": 19,
+                    "minimum": -20
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "owner": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "path": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "port": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 0
+                },
+                "ports": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "pprof_url": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "queue_backlog_limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "queue_growth_limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "queues": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "repository_url": {
+                    "type": "string"
+                },
+                "restart_strategy": {
+                    "type": "string",
+                    "enum": [
+                        "stop_start",
+                        "blue_green"
+                    ]
+                },
+                "smoke_tests": {
+                    "type": "string",
+                    "maxLength": 20000
+                },
+                "smoke_tests_fail_error": {
+                    "type": "boolean"
+                },
+                "smoke_tests_on_start": {
+                    "type": "boolean"
+                },
+                "socket_path": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "ssh_host": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "status_page": {
+                    "type": "boolean"
+                },
+                "status_page_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "systemd_unit": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "systemd_user": {
+                    "type": "boolean"
+                },
+                "tail_files": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "team": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "test_command": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "trace_injection": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "enum": [
+                        "backend",
+                        "frontend",
+                        "worker",
+                        "database",
+                        "queue",
+                        "systemd",
+                        "mock",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ServiceType"
+                        }
+                    ]
+                },
+                "wait_for": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "wait_for_interval": {
+                    "type": "integer",
+                    "maximum": 300,
+                    "minimum": 0
+                },
+                "wait_for_timeout": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0
+                },
+                "watch_files": {
+                    "type": "boolean"
+                },
+                "working_dir": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "project_id"
+            ],
+            "properties": {
+                "expires_in_days": {
+                    "description": "Never expires when empty",
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "description": "status, logs, start, stop, restart; status, logs and restart when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CreateWindowRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "cron": {
+                    "description": "e.g. \"0 2 * * 6\" (Saturdays 02:00)",
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "description": "Required with cron",
+                    "type": "integer",
+                    "maximum": 10080,
+                    "minimum": 1
+                },
+                "ends_at": {
+                    "description": "Required for a one-off window",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pause_auto_restart": {
+                    "description": "Default true",
+                    "type": "boolean"
+                },
+                "project_ids": {
+                    "description": "Empty for the system and every project",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "description": "Now when empty",
+                    "type": "string"
+                },
+                "suppress_alerts": {
+                    "description": "Default true",
+                    "type": "boolean"
+                }
+            }
+        },
+        "CreatedToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Never when null",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "First characters of the token, to recognize it",
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "description": "Send as Authorization: Bearer \u003ctoken\u003e; not shown again",
+                    "type": "string"
+                },
+                "use_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "DBWriteStats": {
+            "type": "object",
+            "properties": {
+                "by_table": {
+                    "description": "Since go-runner started, \"raw\" for Exec statements",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "last_minute": {
+                    "type": "integer"
+                },
+                "per_second": {
+                    "description": "Over the last minute",
+                    "type": "number"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "DNSLookup": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duration_ms": {
+                    "type": "number"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "proxy": {
+                    "description": "Proxy HTTPS requests to the host go through",
+                    "type": "string"
+                }
+            }
+        },
+        "Dashboard": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "panels": {
+                    "description": "In display order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Panel"
+                    }
+                },
+                "team": {
+                    "description": "Empty for dashboards shared by everyone",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "DashboardData": {
+            "type": "object",
+            "properties": {
+                "dashboard": {
+                    "$ref": "#/definitions/Dashboard"
+                },
+                "panels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PanelData"
+                    }
+                }
+            }
+        },
+        "DashboardRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "panels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Panel"
+                    }
+                },
+                "team": {
+                    "type": "string"
+                }
+            }
+        },
+        "DataMessageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "DataResponse": {
+            "type": "object",
+            "properties": {
+                "data": {}
+            }
+        },
+        "DatabaseStats": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "connections": {
+                    "description": "Active connections / clients",
+                    "type": "integer"
+                },
+                "engine": {
+                    "description": "postgres, mysql, redis, sqlite",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "size_bytes": {
+                    "description": "Database size (used memory for redis)",
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeclaredPortStatus": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "type": "string"
+                },
+                "public": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "listening, free or unchecked",
+                    "type": "string"
+                }
+            }
+        },
+        "DefaultGateway": {
+            "type": "object",
+            "properties": {
+                "gateway": {
+                    "type": "string"
+                },
+                "interface": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "Dependency": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "Installed or pinned version",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Registry lookup failure",
+                    "type": "string"
+                },
+                "latest": {
+                    "description": "Latest version in the registry",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "outdated": {
+                    "description": "Current is older than latest",
+                    "type": "boolean"
+                },
+                "section": {
+                    "description": "dependencies, devDependencies, require, requirements",
+                    "type": "string"
+                },
+                "wanted": {
+                    "description": "Version or range declared in the manifest",
+                    "type": "string"
+                }
+            }
+        },
+        "DependencyAudit": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ecosystem": {
+                    "description": "npm, go, pip",
+                    "type": "string"
+                },
+                "findings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AuditFinding"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "$ref": "#/definitions/AuditSummary"
+                },
+                "tools": {
+                    "description": "Commands run",
+                    "type": "string"
+                }
+            }
+        },
+        "DependencyReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Dependency"
+                    }
+                },
+                "ecosystem": {
+                    "description": "npm, go, pip",
+                    "type": "string"
+                },
+                "manifest": {
+                    "type": "string"
+                },
+                "outdated": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "DetectServicesRequest": {
+            "type": "object",
+            "required": [
+                "path"
+            ],
+            "properties": {
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "DiskInfo": {
+            "type": "object",
+            "properties": {
+                "free": {
+                    "description": "Free disk space in bytes",
+                    "type": "integer"
+                },
+                "inodes_free": {
+                    "description": "Free inodes",
+                    "type": "integer"
+                },
+                "inodes_total": {
+                    "description": "Total inodes",
+                    "type": "integer"
+                },
+                "inodes_usage": {
+                    "description": "Inodes usage percentage",
+                    "type": "number"
+                },
+                "inodes_used": {
+                    "description": "Used inodes",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total disk space in bytes",
+                    "type": "integer"
+                },
+                "usage": {
+                    "description": "Disk usage percentage",
+                    "type": "number"
+                },
+                "used": {
+                    "description": "Used disk space in bytes",
+                    "type": "integer"
+                }
+            }
+        },
+        "DiskUsage": {
+            "type": "object",
+            "properties": {
+                "by_kind": {
+                    "description": "Bytes per artifact kind",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "bytes": {
+                    "description": "Whole directory",
+                    "type": "integer"
+                },
+                "dir": {
+                    "type": "string"
+                },
+                "entries": {
+                    "description": "Artifact directories, largest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DiskUsageEntry"
+                    }
+                },
+                "files": {
+                    "type": "integer"
+                },
+                "filesystem_free": {
+                    "type": "integer"
+                },
+                "filesystem_total": {
+                    "type": "integer"
+                },
+                "source_bytes": {
+                    "description": "Outside artifact directories",
+                    "type": "integer"
+                },
+                "truncated": {
+                    "description": "More entries than listed",
+                    "type": "boolean"
+                }
+            }
+        },
+        "DiskUsageEntry": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "files": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "dependencies, build, cache",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Relative to the project directory",
+                    "type": "string"
+                }
+            }
+        },
+        "DockerContainer": {
+            "type": "object",
+            "properties": {
+                "cpu_percent": {
+                    "description": "Running containers only",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {
+                    "type": "string"
+                },
+                "mem_limit": {
+                    "type": "integer"
+                },
+                "mem_percent": {
+                    "type": "number"
+                },
+                "mem_usage": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ports": {
+                    "type": "string"
+                },
+                "state": {
+                    "description": "running, exited, paused...",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"Up 2 hours\", \"Exited (0) 3 days ago\"",
+                    "type": "string"
+                }
+            }
+        },
+        "DockerDiskUsage": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "integer"
+                },
+                "reclaimable": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Images, Containers, Local Volumes, Build Cache",
+                    "type": "string"
+                }
+            }
+        },
+        "DockerOverview": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "containers": {
+                    "description": "Running first, then by name",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DockerContainer"
+                    }
+                },
+                "disk_usage": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DockerDiskUsage"
+                    }
+                },
+                "error": {
+                    "description": "Why the daemon cannot be read",
+                    "type": "string"
+                },
+                "images": {
+                    "type": "integer"
+                },
+                "running": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "DoctorCheck": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "project, toolchain, package_manager, env, database",
+                    "type": "string"
+                },
+                "fix": {
+                    "description": "How to resolve a warning or failure",
+                    "type": "string"
+                },
+                "found": {
+                    "description": "What is installed or configured",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pass, warn, fail",
+                    "type": "string"
+                },
+                "wanted": {
+                    "description": "Required version, variable or URL",
+                    "type": "string"
+                }
+            }
+        },
+        "DoctorReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DoctorCheck"
+                    }
+                },
+                "dir": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "description": "No check failed",
+                    "type": "boolean"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "$ref": "#/definitions/DoctorSummary"
+                }
+            }
+        },
+        "DoctorSummary": {
+            "type": "object",
+            "properties": {
+                "fail": {
+                    "type": "integer"
+                },
+                "pass": {
+                    "type": "integer"
+                },
+                "warn": {
+                    "type": "integer"
+                }
+            }
+        },
+        "DriftReport": {
+            "type": "object",
+            "properties": {
+                "drifted": {
+                    "description": "What applying the spec again would change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Plan"
+                        }
+                    ]
+                },
+                "in_sync": {
+                    "type": "boolean"
+                },
+                "spec": {
+                    "$ref": "#/definitions/WorkspaceSpec"
+                }
+            }
+        },
+        "EnvFileError": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "EnvFileLine": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "export": {
+                    "description": "Line uses the ` + "`" + `export KEY=value` + "`" + ` form",
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "raw": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "variable, comment, blank, invalid",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "EnvFileProcessStatus": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Old is the process value, New the file value",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/EnvVarDiff"
+                    }
+                },
+                "overridden": {
+                    "description": "File keys overridden by env_vars",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "restart_required": {
+                    "type": "boolean"
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "EnvFileResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "description": "Keys defined more than once; the last one wins",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "description": "Invalid lines (ignored at start)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/EnvFileError"
+                    }
+                },
+                "exists": {
+                    "type": "boolean"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/EnvFileLine"
+                    }
+                },
+                "modified": {
+                    "description": "PUT only: changes made to the file",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/EnvVarDiff"
+                    }
+                },
+                "path": {
+                    "type": "string"
+                },
+                "process": {
+                    "$ref": "#/definitions/EnvFileProcessStatus"
+                }
+            }
+        },
+        "EnvVarDiff": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "string"
+                },
+                "old": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "added, changed, removed",
+                    "type": "string"
+                }
+            }
+        },
+        "ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "details": {},
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "trace": {
+                    "type": "string"
+                }
+            }
+        },
+        "ExecuteRequest": {
+            "type": "object",
+            "properties": {
+                "params": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {},
+                "to": {}
+            }
+        },
+        "FileChange": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "description": "create, write, remove, rename, chmod",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Relative to the project directory",
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "FileChangeFeed": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FileChange"
+                    }
+                },
+                "dir": {
+                    "type": "string"
+                },
+                "directories": {
+                    "description": "Watched directories",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Why the directory cannot be watched",
+                    "type": "string"
+                },
+                "last_modified": {
+                    "description": "Time of the last change",
+                    "type": "string"
+                },
+                "truncated": {
+                    "description": "More directories than watched",
+                    "type": "boolean"
+                },
+                "watching": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "FileDescriptorInfo": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "description": "fs.file-max (kern.maxfiles on macOS)",
+                    "type": "integer"
+                },
+                "open": {
+                    "description": "Allocated file handles system-wide",
+                    "type": "integer"
+                },
+                "process_limit": {
+                    "description": "Soft RLIMIT_NOFILE of go-runner",
+                    "type": "integer"
+                },
+                "process_open": {
+                    "description": "Open by go-runner itself",
+                    "type": "integer"
+                },
+                "usage": {
+                    "description": "Percentage of max",
+                    "type": "number"
+                }
+            }
+        },
+        "GroupCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "GroupTimeline": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "description": "Average uptime, worst status",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UptimeBucket"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/StatusTimeline"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "uptime_percent": {
+                    "description": "Average of the projects",
+                    "type": "number"
+                }
+            }
+        },
+        "HealthResponse": {
+            "type": "object",
+            "properties": {
+                "service": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "HubStats": {
+            "type": "object",
+            "properties": {
+                "clients": {
+                    "type": "integer"
+                },
+                "dropped": {
+                    "description": "Clients disconnected because their queue was full",
+                    "type": "integer"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectClients"
+                    }
+                },
+                "sent": {
+                    "description": "Messages queued to clients since start",
+                    "type": "integer"
+                }
+            }
+        },
+        "ImportChanges": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unchanged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ImportKubeRequest": {
+            "type": "object",
+            "required": [
+                "deployments",
+                "namespace"
+            ],
+            "properties": {
+                "context": {
+                    "description": "kubeconfig context, the current one when empty",
+                    "type": "string"
+                },
+                "deployments": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Project type, backend when empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ServiceType"
+                        }
+                    ]
+                }
+            }
+        },
+        "ImportKubeResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Project"
+                    }
+                },
+                "skipped": {
+                    "description": "Deployments already imported",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ImportMonitoringRequest": {
+            "type": "object",
+            "required": [
+                "config"
+            ],
+            "properties": {
+                "config": {
+                    "description": "YAML from GET /system/config/export",
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "Report the changes without applying them",
+                    "type": "boolean"
+                },
+                "prune": {
+                    "description": "Delete connectivity targets and maintenance windows missing from the file",
+                    "type": "boolean"
+                }
+            }
+        },
+        "ImportMonitoringResult": {
+            "type": "object",
+            "properties": {
+                "connectivity_targets": {
+                    "$ref": "#/definitions/ImportChanges"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "maintenance_windows": {
+                    "$ref": "#/definitions/ImportChanges"
+                },
+                "system_config": {
+                    "description": "Whether the system config changed",
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ImportPM2Request": {
+            "type": "object",
+            "properties": {
+                "apps": {
+                    "description": "Ecosystem apps or ` + "`" + `pm2 jlist` + "`" + ` entries",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer",
+                            "format": "int32"
+                        }
+                    }
+                },
+                "config_path": {
+                    "description": "ecosystem.config.js/.cjs (read with node), .json or .yaml",
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "Only return the converted projects",
+                    "type": "boolean"
+                },
+                "env_name": {
+                    "description": "Apply env_\u003cname\u003e over env, e.g. production",
+                    "type": "string"
+                },
+                "from_pm2": {
+                    "description": "Run ` + "`" + `pm2 jlist` + "`" + ` on this host",
+                    "type": "boolean"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Project type, backend when empty",
+                    "enum": [
+                        "backend",
+                        "frontend",
+                        "worker",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ServiceType"
+                        }
+                    ]
+                }
+            }
+        },
+        "ImportProcfileRequest": {
+            "type": "object",
+            "required": [
+                "path"
+            ],
+            "properties": {
+                "base_port": {
+                    "description": "PORT of the first process type, 5000 when empty",
+                    "type": "integer",
+                    "maximum": 65000,
+                    "minimum": 1
+                },
+                "dry_run": {
+                    "description": "Only return the converted projects",
+                    "type": "boolean"
+                },
+                "group_name": {
+                    "description": "Group of the projects, the directory name when empty",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Procfile, or the directory holding it",
+                    "type": "string"
+                }
+            }
+        },
+        "ImportProjectsRequest": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CreateProjectGroupRequest"
+                    }
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CreateProjectRequest"
+                    }
+                }
+            }
+        },
+        "ImportResult": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "groups_created": {
+                    "type": "integer"
+                },
+                "groups_updated": {
+                    "type": "integer"
+                },
+                "projects_created": {
+                    "type": "integer"
+                },
+                "projects_updated": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "From linting the import file",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LintIssue"
+                    }
+                }
+            }
+        },
+        "InspectRequest": {
+            "type": "object",
+            "properties": {
+                "port": {
+                    "description": "Free port from 9229 when empty",
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1024
+                }
+            }
+        },
+        "InstallPackagesRequest": {
+            "type": "object",
+            "required": [
+                "package_manager"
+            ],
+            "properties": {
+                "package_manager": {
+                    "type": "string",
+                    "enum": [
+                        "npm",
+                        "yarn",
+                        "pnpm",
+                        "go",
+                        "pip"
+                    ]
+                },
+                "packages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "InstanceLogsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "InternalsReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "database": {
+                    "$ref": "#/definitions/DBWriteStats"
+                },
+                "goroutines": {
+                    "type": "integer"
+                },
+                "hub": {
+                    "$ref": "#/definitions/HubStats"
+                },
+                "log_streams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LogChannelStats"
+                    }
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OperationLatency"
+                    }
+                }
+            }
+        },
+        "Job": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Jobs with the same key never run concurrently",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "output": {
+                    "description": "Log output, truncated to the last 256KB",
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "Percent, 0-100",
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "object"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/Status"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "KillPortResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                }
+            }
+        },
+        "KubeDeployment": {
+            "type": "object",
+            "properties": {
+                "available_replicas": {
+                    "type": "integer"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "context": {
+                    "type": "string"
+                },
+                "deployment": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Why the deployment is failing",
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "pods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/KubePod"
+                    }
+                },
+                "ready_replicas": {
+                    "type": "integer"
+                },
+                "replicas": {
+                    "description": "Desired",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Project status derived from readiness",
+                    "type": "string"
+                },
+                "updated_replicas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "KubeDeploymentSummary": {
+            "type": "object",
+            "properties": {
+                "images": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "ready_replicas": {
+                    "type": "integer"
+                },
+                "replicas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "KubePod": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "node": {
+                    "type": "string"
+                },
+                "phase": {
+                    "type": "string"
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "description": "Waiting or termination reason",
+                    "type": "string"
+                },
+                "restarts": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "LatencyBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "le": {
+                    "description": "Upper bound in milliseconds, +Inf for the last bucket",
+                    "type": "string"
+                }
+            }
+        },
+        "LintIssue": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "project": {
+                    "description": "Project or group name, or its position in the file",
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "error, warning",
+                    "type": "string"
+                }
+            }
+        },
+        "LintReport": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LintIssue"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LintIssue"
+                    }
+                }
+            }
+        },
+        "ListeningSocket": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "IP, or path of unix sockets",
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "process": {
+                    "type": "string"
+                },
+                "protocol": {
+                    "description": "tcp, tcp6, udp, udp6, unix",
+                    "type": "string"
+                }
+            }
+        },
+        "LogChannelStats": {
+            "type": "object",
+            "properties": {
+                "backlog": {
+                    "description": "Lines waiting in the log channel",
+                    "type": "integer"
+                },
+                "buffered": {
+                    "description": "Lines in the log buffer",
+                    "type": "integer"
+                },
+                "capacity": {
+                    "description": "Size of the log channel",
+                    "type": "integer"
+                },
+                "captured": {
+                    "description": "Lines read from the process",
+                    "type": "integer"
+                },
+                "dropped": {
+                    "description": "Lines not sent because the channel was full",
+                    "type": "integer"
+                },
+                "last_line_at": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "LogEntry": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "string"
+                },
+                "time": {
+                    "description": "UTC, zero for lines saved before capture times were recorded",
+                    "type": "string"
+                }
+            }
+        },
+        "LogFilterSpec": {
+            "type": "object",
+            "properties": {
+                "exclude": {
+                    "description": "Drop lines matching this regex",
+                    "type": "string"
+                },
+                "include": {
+                    "description": "Only lines matching this regex",
+                    "type": "string"
+                },
+                "level": {
+                    "description": "Minimum level: trace, debug, info, warn, error, fatal",
+                    "type": "string"
+                }
+            }
+        },
+        "LogSearchHit": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "time": {
+                    "description": "Zero for lines saved before capture times were recorded",
+                    "type": "string"
+                }
+            }
+        },
+        "LogsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "entries": {
+                    "description": "Lines with their capture time in UTC",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LogEntry"
+                    }
+                },
+                "logs": {
+                    "description": "Prefixed with their capture time when timestamps are requested",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "description": "Timezone of the prefixes, UTC by default",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/TimezoneInfo"
+                        }
+                    ]
+                }
+            }
+        },
+        "MDNSStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Service"
+                    }
+                }
+            }
+        },
+        "MemoryInfo": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Available memory in bytes",
+                    "type": "integer"
+                },
+                "free": {
+                    "description": "Free memory in bytes",
+                    "type": "integer"
+                },
+                "swap_free": {
+                    "description": "Free swap in bytes",
+                    "type": "integer"
+                },
+                "swap_total": {
+                    "description": "Total swap in bytes",
+                    "type": "integer"
+                },
+                "swap_usage": {
+                    "description": "Swap usage percentage",
+                    "type": "number"
+                },
+                "swap_used": {
+                    "description": "Used swap in bytes",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total memory in bytes",
+                    "type": "integer"
+                },
+                "usage": {
+                    "description": "Memory usage percentage",
+                    "type": "number"
+                },
+                "used": {
+                    "description": "Used memory in bytes",
+                    "type": "integer"
+                }
+            }
+        },
+        "MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "Metric": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "per_project": {
+                    "description": "One series per project instead of a system-wide one",
+                    "type": "boolean"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "MonitorCheck": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "monitor_id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "slow": {
+                    "description": "Up, but slower than the latency threshold",
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "up": {
+                    "description": "The answer was the expected one",
+                    "type": "boolean"
+                }
+            }
+        },
+        "MonitorRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "body_contains": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "expected_status": {
+                    "type": "integer",
+                    "maximum": 599,
+                    "minimum": 100
+                },
+                "failure_threshold": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "interval": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 10
+                },
+                "latency_threshold_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "method": {
+                    "description": "GET when empty",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "timeout": {
+                    "type": "integer",
+                    "maximum": 60,
+                    "minimum": 0
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "MonitorStats": {
+            "type": "object",
+            "properties": {
+                "availability_percent": {
+                    "description": "null without checks",
+                    "type": "number"
+                },
+                "avg_ms": {
+                    "description": "Latencies of the checks that got an answer",
+                    "type": "number"
+                },
+                "checks": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LatencyBucket"
+                    }
+                },
+                "max_ms": {
+                    "type": "number"
+                },
+                "monitor_id": {
+                    "type": "integer"
+                },
+                "p50_ms": {
+                    "type": "number"
+                },
+                "p95_ms": {
+                    "type": "number"
+                },
+                "p99_ms": {
+                    "type": "number"
+                },
+                "slow": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "up": {
+                    "type": "integer"
+                }
+            }
+        },
+        "MonitorSummary": {
+            "type": "object",
+            "properties": {
+                "availability_percent": {
+                    "description": "Last 24 hours, null without checks",
+                    "type": "number"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "body_contains": {
+                    "description": "Substring the response body must contain",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expected_status": {
+                    "description": "Any below 400 when 0",
+                    "type": "integer"
+                },
+                "failure_threshold": {
+                    "description": "Consecutive failed or slow checks that raise an alert, 2 when 0",
+                    "type": "integer"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "description": "Seconds between checks, 60 when 0",
+                    "type": "integer"
+                },
+                "last_check": {
+                    "$ref": "#/definitions/MonitorCheck"
+                },
+                "latency_threshold_ms": {
+                    "description": "Checks slower than this are slow, 0 to disable",
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "GET when empty",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "state": {
+                    "description": "up, slow, down, paused, or pending before the first check",
+                    "type": "string"
+                },
+                "timeout": {
+                    "description": "Seconds, 5 when 0",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "/path on the project port, or a full http(s) URL",
+                    "type": "string"
+                }
+            }
+        },
+        "NetworkDiagnostics": {
+            "type": "object",
+            "properties": {
+                "default_gateway": {
+                    "$ref": "#/definitions/DefaultGateway"
+                },
+                "dns_servers": {
+                    "description": "nameserver lines of /etc/resolv.conf",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "description": "Parts that could not be read",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lookups": {
+                    "description": "In the order requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DNSLookup"
+                    }
+                },
+                "npm_config": {
+                    "description": "npm proxy and registry settings, when set",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "proxy_variables": {
+                    "description": "Set variables, credentials masked",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "search_domains": {
+                    "description": "search and domain lines",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "NetworkInfo": {
+            "type": "object",
+            "properties": {
+                "interfaces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NetworkInterface"
+                    }
+                },
+                "total_bytes_received": {
+                    "type": "integer"
+                },
+                "total_bytes_sent": {
+                    "type": "integer"
+                },
+                "total_packets_received": {
+                    "type": "integer"
+                },
+                "total_packets_sent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "NetworkInterface": {
+            "type": "object",
+            "properties": {
+                "addrs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "bytes_received": {
+                    "type": "integer"
+                },
+                "bytes_sent": {
+                    "type": "integer"
+                },
+                "drop_in": {
+                    "type": "integer"
+                },
+                "drop_out": {
+                    "type": "integer"
+                },
+                "errors_in": {
+                    "type": "integer"
+                },
+                "errors_out": {
+                    "type": "integer"
+                },
+                "flags": {
+                    "type": "string"
+                },
+                "hardware_addr": {
+                    "type": "string"
+                },
+                "mtu": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "packets_received": {
+                    "type": "integer"
+                },
+                "packets_sent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Node": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "host:port checked",
+                    "type": "string"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failures": {
+                    "description": "Consecutive failed checks",
+                    "type": "integer"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "project_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "since": {
+                    "description": "Time of the last status change",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "up, down or unknown",
+                    "type": "string"
+                },
+                "tunnel": {
+                    "description": "WireGuard peer routing the host",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/WireGuardPeer"
+                        }
+                    ]
+                }
+            }
+        },
+        "NodeInspector": {
+            "type": "object",
+            "properties": {
+                "devtools_url": {
+                    "description": "Open in Chrome, or chrome://inspect",
+                    "type": "string"
+                },
+                "listening": {
+                    "description": "The inspector answered on the port",
+                    "type": "boolean"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Script being debugged",
+                    "type": "string"
+                },
+                "websocket_url": {
+                    "description": "For editors attaching by URL",
+                    "type": "string"
+                }
+            }
+        },
+        "OnboardingDoctor": {
+            "type": "object",
+            "properties": {
+                "healthy": {
+                    "type": "boolean"
+                },
+                "issues": {
+                    "description": "Warnings and failures",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DoctorCheck"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/DoctorSummary"
+                }
+            }
+        },
+        "OnboardingOptions": {
+            "type": "object",
+            "properties": {
+                "require_healthy": {
+                    "description": "Stop at the doctor step when a check fails",
+                    "type": "boolean"
+                },
+                "skip_install": {
+                    "type": "boolean"
+                },
+                "skip_start": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "OnboardingService": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "doctor": {
+                    "$ref": "#/definitions/OnboardingDoctor"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "include": {
+                    "description": "Create, install and start this service",
+                    "type": "boolean"
+                },
+                "installed": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "description": "Why the proposal differs from what was detected",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "package_manager": {
+                    "description": "npm, yarn, pnpm, go, pip; empty skips the install",
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "description": "Existing project with this path, or the one created",
+                    "type": "integer"
+                },
+                "started": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "OnboardingSession": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "description": "Group created or reused by the run",
+                    "type": "integer"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "description": "Last run",
+                    "type": "integer"
+                },
+                "options": {
+                    "$ref": "#/definitions/OnboardingOptions"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OnboardingService"
+                    }
+                },
+                "status": {
+                    "description": "pending, running, failed, completed",
+                    "type": "string"
+                },
+                "step": {
+                    "description": "review, create, doctor, install, start, done",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "OpenTerminalRequest": {
+            "type": "object",
+            "properties": {
+                "os": {
+                    "description": "\"macos\", \"linux\", \"windows\", or \"auto\" (auto-detect from User-Agent)",
+                    "type": "string"
+                }
+            }
+        },
+        "OperationLatency": {
+            "type": "object",
+            "properties": {
+                "avg_ms": {
+                    "description": "Over the recent samples",
+                    "type": "number"
+                },
+                "count": {
+                    "description": "Since go-runner started",
+                    "type": "integer"
+                },
+                "last_ms": {
+                    "type": "number"
+                },
+                "max_ms": {
+                    "type": "number"
+                },
+                "name": {
+                    "description": "start, stop, restart",
+                    "type": "string"
+                },
+                "p95_ms": {
+                    "type": "number"
+                }
+            }
+        },
+        "OrphanCleanup": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "terminated, killed",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "OrphanReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "orphans": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/StrayProcess"
+                    }
+                },
+                "zombies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/StrayProcess"
+                    }
+                }
+            }
+        },
+        "PaginatedResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "pagination": {
+                    "$ref": "#/definitions/Pagination"
+                }
+            }
+        },
+        "Pagination": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Panel": {
+            "type": "object",
+            "properties": {
+                "chart": {
+                    "description": "line, area, bar, gauge or stat (default line)",
+                    "type": "string"
+                },
+                "metric": {
+                    "description": "See GET /dashboards/metrics",
+                    "type": "string"
+                },
+                "project_ids": {
+                    "description": "Projects of a project metric, every project with data when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "range": {
+                    "description": "Time range: 15m, 6h, 7d... (default 1h)",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "width": {
+                    "description": "Grid columns (1-12) for the frontend layout",
+                    "type": "integer"
+                }
+            }
+        },
+        "PanelData": {
+            "type": "object",
+            "properties": {
+                "chart": {
+                    "description": "line, area, bar, gauge or stat (default line)",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "metric": {
+                    "description": "See GET /dashboards/metrics",
+                    "type": "string"
+                },
+                "project_ids": {
+                    "description": "Projects of a project metric, every project with data when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "range": {
+                    "description": "Time range: 15m, 6h, 7d... (default 1h)",
+                    "type": "string"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Series"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "width": {
+                    "description": "Grid columns (1-12) for the frontend layout",
+                    "type": "integer"
+                }
+            }
+        },
+        "Param": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "entity": {
+                    "description": "The parameter is the ID of a project or group",
+                    "type": "string"
+                },
+                "enum": {
+                    "description": "Allowed values, of the elements for arrays",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "in": {
+                    "description": "path, query or body",
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Type of the elements of arrays",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "string, integer, boolean or array",
+                    "type": "string"
+                }
+            }
+        },
+        "PauseRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Seconds before it is resumed automatically, 0 to stay paused until resumed",
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 0
+                }
+            }
+        },
+        "Plan": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PlanChange"
+                    }
+                },
+                "create": {
+                    "type": "integer"
+                },
+                "delete": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "update": {
+                    "type": "integer"
+                }
+            }
+        },
+        "PlanChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "create, update, delete",
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldChange"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "group, project",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "running": {
+                    "description": "Stopped before it is deleted",
+                    "type": "boolean"
+                }
+            }
+        },
+        "Point": {
+            "type": "object",
+            "properties": {
+                "timestamp": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "PortConflict": {
+            "type": "object",
+            "properties": {
+                "number": {
+                    "type": "integer"
+                },
+                "port_name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "protocol": {
+                    "$ref": "#/definitions/PortProtocol"
+                }
+            }
+        },
+        "PortInfo": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "port_name": {
+                    "description": "Name of the declared port (http, grpc, ...)",
+                    "type": "string"
+                },
+                "process_name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "Project owning this port, if any",
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "protocol": {
+                    "description": "tcp or udp",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "PortProtocol": {
+            "type": "string",
+            "enum": [
+                "tcp",
+                "udp",
+                "tcp",
+                "udp"
+            ],
+            "x-enum-varnames": [
+                "ProtocolTCP",
+                "ProtocolUDP"
+            ]
+        },
+        "PowerStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Why the power source could not be read",
+                    "type": "string"
+                },
+                "interval_factor": {
+                    "type": "integer"
+                },
+                "low_power": {
+                    "type": "boolean"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "on_battery": {
+                    "description": "Unset when the power source cannot be read",
+                    "type": "boolean"
+                },
+                "paused": {
+                    "description": "Optional projects suspended until low-power mode ends",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                }
+            }
+        },
+        "PriorityStatus": {
+            "type": "object",
+            "properties": {
+                "cpu_affinity": {
+                    "description": "CPU list such as \"0-3,8\"; empty for all cores",
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "io_class": {
+                    "description": "realtime (root only), best-effort or idle; empty for the default",
+                    "type": "string"
+                },
+                "io_priority": {
+                    "description": "0 (highest) to 7 (lowest) within realtime and best-effort",
+                    "type": "integer"
+                },
+                "nice": {
+                    "description": "-20 (highest) to 19 (lowest), 0 by default; below 0 needs root",
+                    "type": "integer"
+                },
+                "processes": {
+                    "description": "Main process first, then its descendants",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProcessPriority"
+                    }
+                },
+                "running": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "ProcessInfo": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "cpu_percent": {
+                    "type": "number"
+                },
+                "create_time": {
+                    "type": "integer"
+                },
+                "memory_percent": {
+                    "type": "number"
+                },
+                "memory_rss": {
+                    "description": "Resident Set Size",
+                    "type": "integer"
+                },
+                "memory_vms": {
+                    "description": "Virtual Memory Size",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProcessMetric": {
+            "type": "object",
+            "properties": {
+                "cpu_percent": {
+                    "description": "Of one core since the previous sample, null for the first sample of a process",
+                    "type": "number"
+                },
+                "disk_read_bytes_per_sec": {
+                    "description": "Bytes read from and written to disk per second since the previous\nsample, null for the first sample of a process",
+                    "type": "number"
+                },
+                "disk_write_bytes_per_sec": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "net_received_bytes_per_sec": {
+                    "type": "number"
+                },
+                "net_sent_bytes_per_sec": {
+                    "description": "TCP bytes sent and received per second since the previous sample, null\nfor the first sample of a process or where sockets cannot be\nattributed to processes",
+                    "type": "number"
+                },
+                "processes": {
+                    "description": "Size of the process tree",
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProcessPriority": {
+            "type": "object",
+            "properties": {
+                "cpus": {
+                    "description": "CPU list the process may run on, as taskset reports it",
+                    "type": "string"
+                },
+                "io": {
+                    "description": "As ionice reports it, e.g. \"idle\" or \"best-effort: prio 7\"",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nice": {
+                    "description": "Null when it could not be read",
+                    "type": "integer"
+                },
+                "pid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ProfileRequest": {
+            "type": "object",
+            "properties": {
+                "flamegraph": {
+                    "description": "Render the flame graph right away",
+                    "type": "boolean"
+                },
+                "seconds": {
+                    "description": "CPU profiling duration, default 10, max 300",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "cpu (default), heap, allocs or goroutine",
+                    "type": "string"
+                }
+            }
+        },
+        "Project": {
+            "type": "object",
+            "required": [
+                "name",
+                "path"
+            ],
+            "properties": {
+                "args": {
+                    "description": "Additional arguments",
+                    "type": "string"
+                },
+                "audit": {
+                    "description": "Latest dependency audit, without findings (not stored on the project)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/DependencyAudit"
+                        }
+                    ]
+                },
+                "auto_restart": {
+                    "description": "Auto-restart settings",
+                    "type": "boolean"
+                },
+                "autostart": {
+                    "description": "Start when the go-runner server starts",
+                    "type": "boolean"
+                },
+                "chat_channel": {
+                    "description": "e.g. #payments-oncall, or a link to the channel",
+                    "type": "string"
+                },
+                "ci_branch": {
+                    "description": "Branch whose pipelines are shown, the checked out one when empty",
+                    "type": "string"
+                },
+                "ci_repo": {
+                    "description": "CI pipeline status (ci.github_token, ci.gitlab_token)",
+                    "type": "string"
+                },
+                "command": {
+                    "description": "Command to start the service",
+                    "type": "string"
+                },
+                "command_hash": {
+                    "description": "Hash of its command line, checked with it",
+                    "type": "string"
+                },
+                "connection_string": {
+                    "description": "Database and queue (types database, queue)",
+                    "type": "string"
+                },
+                "cpu_affinity": {
+                    "description": "CPU cores to run on, e.g. \"0-3,8\" (Linux); empty for all",
+                    "type": "string"
+                },
+                "cpu_limit": {
+                    "description": "Resource limits",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "declared_ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectPort"
+                    }
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/DeletedAt"
+                },
+                "depends_on": {
+                    "description": "Comma-separated names of projects that must start first",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "docs_url": {
+                    "description": "Documentation or runbook",
+                    "type": "string"
+                },
+                "drain_timeout": {
+                    "description": "Seconds the old instance of a blue/green restart keeps serving open proxied connections, 30 when 0",
+                    "type": "integer"
+                },
+                "editor": {
+                    "description": "IDE and development",
+                    "type": "string"
+                },
+                "editor_args": {
+                    "description": "Additional editor arguments",
+                    "type": "string"
+                },
+                "env_file": {
+                    "description": "Path to .env file",
+                    "type": "string"
+                },
+                "env_vars": {
+                    "description": "JSON object of environment variables",
+                    "type": "string"
+                },
+                "environment": {
+                    "description": "Environment and configuration",
+                    "type": "string"
+                },
+                "group": {
+                    "$ref": "#/definitions/ProjectGroup"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "health_check_url": {
+                    "description": "Health check",
+                    "type": "string"
+                },
+                "health_status": {
+                    "description": "healthy, unhealthy, unknown",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "idle_timeout": {
+                    "description": "Wake on demand (/projects/:id/proxy)",
+                    "type": "integer"
+                },
+                "inspect_port": {
+                    "description": "Node.js inspector port while debugging (POST /projects/:id/inspect), 0 when off;\ncleared by a manual stop",
+                    "type": "integer"
+                },
+                "instance_ports": {
+                    "description": "Ports of the extra instances: offset (default) for port + index, or auto for free ports",
+                    "type": "string"
+                },
+                "instances": {
+                    "description": "Copies of the service run side by side, the proxy balancing requests over them, 1 when 0",
+                    "type": "integer"
+                },
+                "io_class": {
+                    "description": "realtime, best-effort or idle; empty for the default",
+                    "type": "string"
+                },
+                "io_priority": {
+                    "description": "0 (highest) to 7 within realtime and best-effort",
+                    "type": "integer"
+                },
+                "kube_context": {
+                    "description": "Kubernetes deployment (kubernetes.enabled), start/stop scale it instead of running a process",
+                    "type": "string"
+                },
+                "kube_deployment": {
+                    "description": "Deployment name, a local process when empty",
+                    "type": "string"
+                },
+                "kube_namespace": {
+                    "description": "\"default\" when empty",
+                    "type": "string"
+                },
+                "kube_replicas": {
+                    "description": "Replicas restored on start, saved on stop",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "Last error message",
+                    "type": "string"
+                },
+                "logs": {
+                    "description": "Logs storage (JSON array of log lines, last 1000 lines)",
+                    "type": "string"
+                },
+                "max_restarts": {
+                    "type": "integer"
+                },
+                "mdns": {
+                    "description": "Local network announcement (mdns.enabled)",
+                    "type": "boolean"
+                },
+                "mdns_name": {
+                    "description": "Host label, the project name when empty",
+                    "type": "string"
+                },
+                "memory_guard_mb": {
+                    "description": "Memory guard: alert (and restart) when the RSS of the process tree stays above a ceiling",
+                    "type": "integer"
+                },
+                "memory_guard_restart": {
+                    "description": "Restart the service once the guard trips",
+                    "type": "boolean"
+                },
+                "memory_guard_samples": {
+                    "description": "Consecutive samples (30s apart) above it, 3 when 0",
+                    "type": "integer"
+                },
+                "memory_limit": {
+                    "description": "Memory limit (e.g., \"512Mi\")",
+                    "type": "string"
+                },
+                "migration_command": {
+                    "description": "Shell command run by the migrate action, with DATABASE_URL set",
+                    "type": "string"
+                },
+                "mock_spec": {
+                    "description": "Mock projects (type mock)",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Basic info",
+                    "type": "string"
+                },
+                "nice": {
+                    "description": "CPU and IO scheduling priority and CPU pinning, applied at start (PUT /projects/:id/priority changes them at runtime)",
+                    "type": "integer"
+                },
+                "optional": {
+                    "description": "Low-power mode",
+                    "type": "boolean"
+                },
+                "owner": {
+                    "description": "Service catalog, shown in listings and included in alert notifications",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path and execution",
+                    "type": "string"
+                },
+                "pid": {
+                    "description": "Process ID when running",
+                    "type": "integer"
+                },
+                "port": {
+                    "description": "Network and ports",
+                    "type": "integer"
+                },
+                "ports": {
+                    "description": "Deprecated: legacy JSON array of ports, use DeclaredPorts",
+                    "type": "string"
+                },
+                "pprof_url": {
+                    "description": "Profiling (POST /projects/:id/profile)",
+                    "type": "string"
+                },
+                "process_start": {
+                    "description": "Creation time of the process, identifies it with the PID after a server restart",
+                    "type": "string"
+                },
+                "queue_backlog_limit": {
+                    "description": "Alert when a queue holds more messages (0 = off)",
+                    "type": "integer"
+                },
+                "queue_growth_limit": {
+                    "description": "Alert when a queue grows faster, in messages per minute (0 = off)",
+                    "type": "integer"
+                },
+                "queues": {
+                    "description": "Comma-separated queues, redis keys or Kafka groups/topics to watch",
+                    "type": "string"
+                },
+                "repository_url": {
+                    "type": "string"
+                },
+                "restart_count": {
+                    "type": "integer"
+                },
+                "restart_strategy": {
+                    "description": "stop_start (default), or blue_green: start the new instance on another port, switch the proxy, then stop the old one",
+                    "type": "string"
+                },
+                "smoke_tests": {
+                    "description": "HTTP smoke tests (POST /projects/:id/smoke-tests/run)",
+                    "type": "string"
+                },
+                "smoke_tests_fail_error": {
+                    "description": "Set the status to error while they fail",
+                    "type": "boolean"
+                },
+                "smoke_tests_on_start": {
+                    "description": "Run them after every start",
+                    "type": "boolean"
+                },
+                "socket_path": {
+                    "description": "Unix domain socket the service listens on (readiness check)",
+                    "type": "string"
+                },
+                "ssh_host": {
+                    "description": "Remote host, start/stop/status run over SSH and logs are tailed from the host",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "When service started",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Service management",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ServiceStatus"
+                        }
+                    ]
+                },
+                "status_page": {
+                    "description": "Public status page (/status)",
+                    "type": "boolean"
+                },
+                "status_page_name": {
+                    "description": "Display name there, the project name when empty",
+                    "type": "string"
+                },
+                "stop_time": {
+                    "description": "When service stopped",
+                    "type": "string"
+                },
+                "systemd_unit": {
+                    "description": "systemd unit (type systemd), start/stop/restart run systemctl and logs come from journald",
+                    "type": "string"
+                },
+                "systemd_user": {
+                    "description": "Unit of the user manager (systemctl --user)",
+                    "type": "boolean"
+                },
+                "tail_files": {
+                    "description": "Log files the service writes itself, merged into its logs tagged with their path",
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "test_command": {
+                    "description": "Test command, detected from the project files when empty",
+                    "type": "string"
+                },
+                "trace_injection": {
+                    "description": "Tracing",
+                    "type": "boolean"
+                },
+                "type": {
+                    "$ref": "#/definitions/ServiceType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented by each configuration change; updates sending an older one are refused",
+                    "type": "integer"
+                },
+                "wait_for": {
+                    "description": "Comma-separated conditions checked before the command runs: tcp://host:port, http(s) URLs, file:// paths, postgres://, mysql:// or redis:// servers",
+                    "type": "string"
+                },
+                "wait_for_interval": {
+                    "description": "Seconds between checks, 1 when 0",
+                    "type": "integer"
+                },
+                "wait_for_timeout": {
+                    "description": "Seconds before the start fails, 60 when 0",
+                    "type": "integer"
+                },
+                "watch_files": {
+                    "description": "File change feed",
+                    "type": "boolean"
+                },
+                "working_dir": {
+                    "description": "Working directory",
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectActionResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ProjectAlertCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ProjectAnnotation": {
+            "type": "object",
+            "properties": {
+                "automatic": {
+                    "description": "Added by go-runner rather than through the API",
+                    "type": "boolean"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "note, deploy, config, restart, chaos",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "revision": {
+                    "description": "Git commit of deploys",
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectClients": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "Queue size of the clients together",
+                    "type": "integer"
+                },
+                "clients": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "queued": {
+                    "description": "Messages waiting to be written to the clients",
+                    "type": "integer"
+                }
+            }
+        },
+        "ProjectGroup": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "description": "Hex color for UI",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Project"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectMonitor": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "body_contains": {
+                    "description": "Substring the response body must contain",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expected_status": {
+                    "description": "Any below 400 when 0",
+                    "type": "integer"
+                },
+                "failure_threshold": {
+                    "description": "Consecutive failed or slow checks that raise an alert, 2 when 0",
+                    "type": "integer"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "description": "Seconds between checks, 60 when 0",
+                    "type": "integer"
+                },
+                "latency_threshold_ms": {
+                    "description": "Checks slower than this are slow, 0 to disable",
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "GET when empty",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "timeout": {
+                    "description": "Seconds, 5 when 0",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "/path on the project port, or a full http(s) URL",
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectPort": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "e.g. http, grpc, metrics",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Port number (1-65535)",
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "description": "tcp or udp",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/PortProtocol"
+                        }
+                    ]
+                },
+                "public": {
+                    "description": "Exposed beyond localhost",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectPortRequest": {
+            "type": "object",
+            "required": [
+                "number"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "number": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "protocol": {
+                    "enum": [
+                        "tcp",
+                        "udp"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/PortProtocol"
+                        }
+                    ]
+                },
+                "public": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "ProjectProfile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "has_flame_graph": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "sample_type": {
+                    "description": "e.g. cpu/nanoseconds, inuse_space/bytes",
+                    "type": "string"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "seconds": {
+                    "description": "CPU profiling duration",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Bytes of the gzipped profile",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Sum of the sample values, in the sample type unit",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "cpu, heap, allocs, goroutine",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectScript": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "description": "Script body or target recipe",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "\"## text\" comment of a Makefile target",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "run": {
+                    "description": "Command line used to run it",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "make or npm",
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectScripts": {
+            "type": "object",
+            "properties": {
+                "dir": {
+                    "type": "string"
+                },
+                "makefile": {
+                    "type": "string"
+                },
+                "package_manager": {
+                    "description": "npm, yarn or pnpm, from the lockfile",
+                    "type": "string"
+                },
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectScript"
+                    }
+                }
+            }
+        },
+        "ProjectStatusHistory": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "previous_status": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProjectTraffic": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "p95_ms": {
+                    "description": "Highest per-minute p95",
+                    "type": "number"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ProjectsSummary": {
+            "type": "object",
+            "properties": {
+                "active_alerts": {
+                    "description": "Including host-wide alerts",
+                    "type": "integer"
+                },
+                "by_group": {
+                    "description": "Ungrouped projects last, with a null group_id",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/GroupCount"
+                    }
+                },
+                "by_status": {
+                    "description": "Status as stored, see GET /services/summary for the live one",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "by_type": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "crashes_24h": {
+                    "type": "integer"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "project_alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectAlertCount"
+                    }
+                },
+                "recent_crashes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/RecentCrash"
+                    }
+                },
+                "restarts_24h": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ProxyLatency": {
+            "type": "object",
+            "properties": {
+                "delay_ms": {
+                    "type": "integer"
+                },
+                "jitter_ms": {
+                    "description": "Up to this much more, at random",
+                    "type": "integer"
+                },
+                "until": {
+                    "type": "string"
+                }
+            }
+        },
+        "QueryResult": {
+            "type": "object",
+            "properties": {
+                "aggregation": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/QuerySeries"
+                    }
+                },
+                "step": {
+                    "description": "Seconds per bucket",
+                    "type": "integer"
+                },
+                "timestamps": {
+                    "description": "Unix seconds of the start of each bucket",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "QuerySeries": {
+            "type": "object",
+            "properties": {
+                "metric": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Metric or project name",
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "values": {
+                    "description": "Null for buckets without samples",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "QueueDepth": {
+            "type": "object",
+            "properties": {
+                "consumers": {
+                    "type": "integer"
+                },
+                "depth": {
+                    "description": "Messages waiting, or consumer lag for streams and Kafka",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "QueueMetric": {
+            "type": "object",
+            "properties": {
+                "consumers": {
+                    "type": "integer"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "queue": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "QueueStats": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "engine": {
+                    "description": "rabbitmq, redis, kafka",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "queues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/QueueDepth"
+                    }
+                },
+                "total_depth": {
+                    "type": "integer"
+                }
+            }
+        },
+        "RecentCrash": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Current status",
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "ReconcileSummary": {
+            "type": "object",
+            "properties": {
+                "finished_at": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ReconciledProcess"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ReconciledProcess": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "description": "adopt, stop",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "command": {
+                    "type": "string"
+                },
+                "log_file": {
+                    "description": "Output file tailed again, logs are lost when output went to a pipe",
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "adopted, unverified, stopped",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "RollingRestartRequest": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "description": "Projects restarted at once (default 1)",
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 1
+                },
+                "include_stopped": {
+                    "description": "Also start members that are not running",
+                    "type": "boolean"
+                },
+                "pause": {
+                    "description": "Seconds to wait between batches",
+                    "type": "integer",
+                    "maximum": 600,
+                    "minimum": 0
+                },
+                "ready_timeout": {
+                    "description": "Seconds a batch has to be ready (default 60)",
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 1
+                }
+            }
+        },
+        "RunScriptRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "args": {
+                    "description": "Extra arguments (VAR=value for make)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Needed when a target and a script share the name",
+                    "type": "string",
+                    "enum": [
+                        "make",
+                        "npm"
+                    ]
+                }
+            }
+        },
+        "RunningService": {
+            "type": "object",
+            "properties": {
+                "adopted": {
+                    "type": "boolean"
+                },
+                "cpu_percent": {
+                    "description": "Of one core at the last process sample, null until two samples were taken",
+                    "type": "number"
+                },
+                "disk_read_bytes_per_sec": {
+                    "description": "Disk I/O of the process tree at the last process sample, null until\ntwo samples were taken",
+                    "type": "number"
+                },
+                "disk_write_bytes_per_sec": {
+                    "type": "number"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "log_follower": {
+                    "description": "Logs followed from Kubernetes or journald, not a process of this server",
+                    "type": "boolean"
+                },
+                "managed": {
+                    "description": "Held in memory by this server, rather than only marked running in the database",
+                    "type": "boolean"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "net_received_bytes_per_sec": {
+                    "type": "number"
+                },
+                "net_sent_bytes_per_sec": {
+                    "description": "TCP traffic of the process tree at the last process sample, null until\ntwo samples were taken or where it cannot be attributed (Linux only)",
+                    "type": "number"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "processes": {
+                    "description": "Size of the local process tree, 0 for remote services",
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "remote": {
+                    "description": "Runs on an SSH host",
+                    "type": "boolean"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                },
+                "uptime_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "RuntimeEnvReport": {
+            "type": "object",
+            "properties": {
+                "env_file": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "restart_required": {
+                    "description": "Some configured values are stale or missing",
+                    "type": "boolean"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/RuntimeEnvSummary"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/RuntimeEnvVar"
+                    }
+                }
+            }
+        },
+        "RuntimeEnvSummary": {
+            "type": "object",
+            "properties": {
+                "extra": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "type": "integer"
+                },
+                "overridden": {
+                    "type": "integer"
+                },
+                "stale": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "RuntimeEnvVar": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "string"
+                },
+                "configured": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "system, env_file, env_vars, default",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "unchanged, stale, missing, extra, overridden",
+                    "type": "string"
+                }
+            }
+        },
+        "RuntimeInfo": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "children": {
+                    "description": "Descendants, breadth first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/RuntimeProcess"
+                    }
+                },
+                "command": {
+                    "type": "string"
+                },
+                "connections": {
+                    "description": "TCP connections of the tree by state, e.g. ESTABLISHED",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "cwd": {
+                    "type": "string"
+                },
+                "environment_bytes": {
+                    "type": "integer"
+                },
+                "environment_vars": {
+                    "description": "Environment of the main process, without the values",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "Details that could not be read, e.g. without permission",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "executable": {
+                    "type": "string"
+                },
+                "file_limit": {
+                    "description": "Soft RLIMIT_NOFILE of the main process, 0 when unknown",
+                    "type": "integer"
+                },
+                "listening": {
+                    "description": "Whole process tree",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ListeningSocket"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_files": {
+                    "description": "Main process, -1 when it could not be read",
+                    "type": "integer"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "threads": {
+                    "description": "Main process",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Sums over the tree; PID, names and status left empty",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/RuntimeProcess"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "RuntimeProcess": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "cpu_percent": {
+                    "description": "Average since the process started, of one core",
+                    "type": "number"
+                },
+                "memory_rss": {
+                    "description": "Bytes",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_files": {
+                    "description": "-1 when it could not be read",
+                    "type": "integer"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "ppid": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "e.g. running, sleep, zombie",
+                    "type": "string"
+                },
+                "threads": {
+                    "type": "integer"
+                }
+            }
+        },
+        "SearchHit": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field that matched best: name, description, path, owner or team",
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "snippet": {
+                    "description": "Value of the field, when not the name",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Of projects",
+                    "type": "string"
+                }
+            }
+        },
+        "SearchResults": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AlertSearchHit"
+                    }
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SearchHit"
+                    }
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LogSearchHit"
+                    }
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SearchHit"
+                    }
+                },
+                "query": {
+                    "type": "string"
+                }
+            }
+        },
+        "Series": {
+            "type": "object",
+            "properties": {
+                "latest": {
+                    "type": "number"
+                },
+                "name": {
+                    "description": "Metric or project name",
+                    "type": "string"
+                },
+                "points": {
+                    "description": "Oldest first; only the latest for gauge and stat panels",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Point"
+                    }
+                },
+                "project_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Service": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "description": "Host name, e.g. api.local",
+                    "type": "string"
+                },
+                "instance": {
+                    "description": "DNS-SD instance name (project name)",
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "ServiceDetection": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "package_file": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "ServiceGroupSummary": {
+            "type": "object",
+            "properties": {
+                "by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "cpu_percent": {
+                    "type": "number"
+                },
+                "disk_read_bytes_per_sec": {
+                    "type": "number"
+                },
+                "disk_write_bytes_per_sec": {
+                    "type": "number"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "net_received_bytes_per_sec": {
+                    "type": "number"
+                },
+                "net_sent_bytes_per_sec": {
+                    "type": "number"
+                },
+                "running": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ServiceInstance": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "log_file": {
+                    "description": "Combined stdout and stderr, for indexes from 1",
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "restarts": {
+                    "description": "Automatic restarts after crashes (auto_restart)",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "running, stopped, error",
+                    "type": "string"
+                },
+                "stopped_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ServicePause": {
+            "type": "object",
+            "properties": {
+                "paused_at": {
+                    "type": "string"
+                },
+                "pids": {
+                    "description": "Processes suspended: the service, its descendants and extra instances",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "until": {
+                    "description": "Resumed automatically then; paused until resumed when empty",
+                    "type": "string"
+                }
+            }
+        },
+        "ServiceStats": {
+            "type": "object",
+            "properties": {
+                "cpu_usage": {
+                    "type": "number"
+                },
+                "error_count": {
+                    "type": "integer"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "memory_usage": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "request_count": {
+                    "type": "integer"
+                },
+                "uptime": {
+                    "description": "in seconds",
+                    "type": "integer"
+                }
+            }
+        },
+        "ServiceStatus": {
+            "type": "string",
+            "enum": [
+                "stopped",
+                "starting",
+                "running",
+                "stopping",
+                "error",
+                "paused",
+                "unknown",
+                "unreachable",
+                "stopped",
+                "starting",
+                "running",
+                "stopping",
+                "error",
+                "paused",
+                "unknown",
+                "unreachable"
+            ],
+            "x-enum-comments": {
+                "StatusPaused": "Frozen with SIGSTOP, see POST /projects/:id/pause"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "",
+                "Frozen with SIGSTOP, see POST /projects/:id/pause",
+                "",
+                ""
+            ],
+            "x-enum-varnames": [
+                "StatusStopped",
+                "StatusStarting",
+                "StatusRunning",
+                "StatusStopping",
+                "StatusError",
+                "StatusPaused",
+                "StatusUnknown",
+                "StatusUnreachable"
+            ]
+        },
+        "ServiceType": {
+            "type": "string",
+            "enum": [
+                "backend",
+                "frontend",
+                "worker",
+                "database",
+                "queue",
+                "systemd",
+                "mock",
+                "other",
+                "backend",
+                "frontend",
+                "worker",
+                "database",
+                "queue",
+                "systemd",
+                "mock",
+                "other"
+            ],
+            "x-enum-comments": {
+                "TypeMock": "HTTP stub served by go-runner from mock_spec",
+                "TypeSystemd": "Host unit controlled with systemctl"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "",
+                "Host unit controlled with systemctl",
+                "HTTP stub served by go-runner from mock_spec",
+                ""
+            ],
+            "x-enum-varnames": [
+                "TypeBackend",
+                "TypeFrontend",
+                "TypeWorker",
+                "TypeDatabase",
+                "TypeQueue",
+                "TypeSystemd",
+                "TypeMock",
+                "TypeOther"
+            ]
+        },
+        "ServicesSummary": {
+            "type": "object",
+            "properties": {
+                "by_status": {
+                    "description": "\"stale\" for projects marked running whose process is gone",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "cpu_percent": {
+                    "type": "number"
+                },
+                "disk_read_bytes_per_sec": {
+                    "type": "number"
+                },
+                "disk_write_bytes_per_sec": {
+                    "type": "number"
+                },
+                "groups": {
+                    "description": "Ungrouped projects last, with a null group_id",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ServiceGroupSummary"
+                    }
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "net_received_bytes_per_sec": {
+                    "type": "number"
+                },
+                "net_sent_bytes_per_sec": {
+                    "type": "number"
+                },
+                "running": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "SetPowerModeRequest": {
+            "type": "object",
+            "required": [
+                "mode"
+            ],
+            "properties": {
+                "mode": {
+                    "description": "auto, on, off",
+                    "type": "string",
+                    "example": "on"
+                }
+            }
+        },
+        "SetPriorityRequest": {
+            "type": "object",
+            "properties": {
+                "cpu_affinity": {
+                    "description": "CPU cores such as \"0-3,8\", or empty for all",
+                    "type": "string"
+                },
+                "io_class": {
+                    "description": "realtime, best-effort, idle, or empty for the default",
+                    "type": "string"
+                },
+                "io_priority": {
+                    "description": "0 (highest) to 7 within realtime and best-effort",
+                    "type": "integer"
+                },
+                "nice": {
+                    "description": "-20 (highest) to 19 (lowest); below 0 needs root",
+                    "type": "integer"
+                }
+            }
+        },
+        "SinkStats": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "delivered": {
+                    "type": "integer"
+                },
+                "dropped": {
+                    "description": "Events skipped because the sink fell behind",
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "SmokeTestResult": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "Why it failed",
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status answered, 0 when the request failed",
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "SmokeTestRun": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SmokeTestResult"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "trigger": {
+                    "description": "manual, start",
+                    "type": "string"
+                }
+            }
+        },
+        "StartOnboardingRequest": {
+            "type": "object",
+            "required": [
+                "path"
+            ],
+            "properties": {
+                "group_name": {
+                    "description": "The directory name when empty",
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "StartTunnelRequest": {
+            "type": "object",
+            "properties": {
+                "port": {
+                    "description": "Project port when empty",
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "provider": {
+                    "description": "Configured or detected when empty",
+                    "type": "string",
+                    "enum": [
+                        "cloudflared",
+                        "ngrok",
+                        "ssh"
+                    ]
+                }
+            }
+        },
+        "Status": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "failed",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "StatusQueued",
+                "StatusRunning",
+                "StatusSucceeded",
+                "StatusFailed",
+                "StatusCancelled"
+            ]
+        },
+        "StatusPage": {
+            "type": "object",
+            "properties": {
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/StatusPageService"
+                    }
+                },
+                "state": {
+                    "description": "Worst state of the services",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "StatusPageDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "YYYY-MM-DD, server local time",
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "uptime_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "StatusPageService": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "Oldest first, today last",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/StatusPageDay"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "uptime_percent": {
+                    "description": "Over the shown days",
+                    "type": "number"
+                }
+            }
+        },
+        "StatusSegment": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "number"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "StatusTimeline": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "description": "Markers: deploys, config changes and notes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectAnnotation"
+                    }
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UptimeBucket"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/StatusSegment"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "uptime_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "StrayProcess": {
+            "type": "object",
+            "properties": {
+                "cleanable": {
+                    "description": "Zombies are reaped by their parent only",
+                    "type": "boolean"
+                },
+                "command": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_name": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "ppid": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "zombie, previous_session, detached, reparented",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "SystemAlert": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "level": {
+                    "description": "info, warning, error, critical",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resource": {
+                    "description": "Project queue, process or connectivity target; empty for host-wide alerts",
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "type": {
+                    "description": "cpu, memory, disk, network, disk_forecast, memory_forecast...",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "SystemConfig": {
+            "type": "object",
+            "properties": {
+                "alert_email": {
+                    "description": "Alert email address",
+                    "type": "string"
+                },
+                "alert_webhook": {
+                    "description": "Alert webhook URL",
+                    "type": "string"
+                },
+                "check_interval": {
+                    "description": "Check interval in seconds",
+                    "type": "integer"
+                },
+                "clock_drift_limit": {
+                    "description": "Clock offset threshold (seconds), 0 disables",
+                    "type": "number"
+                },
+                "cpu_limit": {
+                    "description": "CPU usage threshold (%)",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disk_limit": {
+                    "description": "Disk usage threshold (%)",
+                    "type": "number"
+                },
+                "enable_alerts": {
+                    "description": "Enable alerting",
+                    "type": "boolean"
+                },
+                "file_descriptor_limit": {
+                    "description": "Open files threshold (% of the limit), system-wide and per project process",
+                    "type": "number"
+                },
+                "forecast_confidence": {
+                    "description": "Minimum R² of the trend for a forecast alert, 0 to 1",
+                    "type": "number"
+                },
+                "forecast_horizon": {
+                    "description": "Alert when disk or memory usage trends to 100% within this many hours, 0 disables",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "memory_limit": {
+                    "description": "Memory usage threshold (%)",
+                    "type": "number"
+                },
+                "network_limit": {
+                    "description": "Network usage threshold (Mbps)",
+                    "type": "number"
+                },
+                "retention_days": {
+                    "description": "Metrics retention in days",
+                    "type": "integer"
+                },
+                "swap_limit": {
+                    "description": "Swap usage threshold (%), 0 disables",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "SystemDashboard": {
+            "type": "object",
+            "properties": {
+                "active_alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemAlert"
+                    }
+                },
+                "recent_metrics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemMetrics"
+                    }
+                },
+                "system_info": {
+                    "$ref": "#/definitions/SystemInfo"
+                },
+                "system_status": {
+                    "$ref": "#/definitions/SystemStatus"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "top_processes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProcessInfo"
+                    }
+                },
+                "traffic": {
+                    "description": "Proxied requests per project over the last hour",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectTraffic"
+                    }
+                }
+            }
+        },
+        "SystemInfo": {
+            "type": "object",
+            "properties": {
+                "architecture": {
+                    "type": "string"
+                },
+                "clock": {
+                    "$ref": "#/definitions/ClockInfo"
+                },
+                "cpu": {
+                    "$ref": "#/definitions/CPUInfo"
+                },
+                "disk": {
+                    "$ref": "#/definitions/DiskInfo"
+                },
+                "file_descriptors": {
+                    "$ref": "#/definitions/FileDescriptorInfo"
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "memory": {
+                    "$ref": "#/definitions/MemoryInfo"
+                },
+                "network": {
+                    "$ref": "#/definitions/NetworkInfo"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "processes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProcessInfo"
+                    }
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "integer"
+                }
+            }
+        },
+        "SystemMetrics": {
+            "type": "object",
+            "properties": {
+                "cpu_usage": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disk_usage": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "load_avg_1": {
+                    "type": "number"
+                },
+                "load_avg_15": {
+                    "type": "number"
+                },
+                "load_avg_5": {
+                    "type": "number"
+                },
+                "memory_usage": {
+                    "type": "number"
+                },
+                "open_files": {
+                    "description": "Open file handles system-wide",
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "SystemStatus": {
+            "type": "object",
+            "properties": {
+                "active_alerts": {
+                    "type": "integer"
+                },
+                "connectivity": {
+                    "description": "Enabled connectivity targets",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ConnectivityTarget"
+                    }
+                },
+                "cpu_status": {
+                    "type": "string"
+                },
+                "disk_status": {
+                    "type": "string"
+                },
+                "last_check": {
+                    "type": "string"
+                },
+                "memory_status": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "network_status": {
+                    "description": "From connectivity targets, empty without any",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "healthy, warning, critical",
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "integer"
+                }
+            }
+        },
+        "SystemdUnit": {
+            "type": "object",
+            "properties": {
+                "active_state": {
+                    "description": "active, activating, deactivating, inactive, failed",
+                    "type": "string"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "load_state": {
+                    "description": "loaded, not-found, masked...",
+                    "type": "string"
+                },
+                "main_pid": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "since": {
+                    "description": "Last change of the active state",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Project status derived from the active state",
+                    "type": "string"
+                },
+                "sub_state": {
+                    "description": "running, exited, dead...",
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "Unit of the user manager (systemctl --user)",
+                    "type": "boolean"
+                }
+            }
+        },
+        "SystemdUnitSummary": {
+            "type": "object",
+            "properties": {
+                "active_state": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "load_state": {
+                    "type": "string"
+                },
+                "sub_state": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "TailLine": {
+            "type": "object",
+            "properties": {
+                "backlog": {
+                    "type": "boolean"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "string"
+                },
+                "project": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "text": {
+                    "description": "\"[project] line\", with ANSI colors when requested",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "TailLogsRequest": {
+            "type": "object",
+            "required": [
+                "project_ids"
+            ],
+            "properties": {
+                "backlog": {
+                    "description": "Buffered lines to replay per project before live lines",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                },
+                "color": {
+                    "description": "Add ANSI color codes to the source tag in text",
+                    "type": "boolean"
+                },
+                "exclude": {
+                    "description": "Drop lines matching this regex",
+                    "type": "string"
+                },
+                "include": {
+                    "description": "Only lines matching this regex",
+                    "type": "string"
+                },
+                "level": {
+                    "description": "Minimum level: trace, debug, info, warn, error, fatal",
+                    "type": "string"
+                },
+                "project_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "TailProject": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "TailSession": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TailProject"
+                    }
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "sse_url": {
+                    "type": "string"
+                },
+                "ws_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "TailedFile": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "type": "string"
+                },
+                "rotations": {
+                    "description": "Times the file was replaced by log rotation",
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "Tag of its lines, the path as configured or matched",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "tailing, waiting (not created yet) or ended",
+                    "type": "string"
+                }
+            }
+        },
+        "TerminalInfo": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "commands": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "instructions": {
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "simple_command": {
+                    "type": "string"
+                },
+                "working_dir": {
+                    "type": "string"
+                }
+            }
+        },
+        "TestFailure": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "suite": {
+                    "description": "Go package, test file or class",
+                    "type": "string"
+                }
+            }
+        },
+        "TestFireRequest": {
+            "type": "object",
+            "properties": {
+                "integration": {
+                    "description": "Sink name, every integration when empty",
+                    "type": "string",
+                    "example": "pagerduty staging"
+                },
+                "keep_open": {
+                    "description": "Leave the test incident open instead of resolving it right away",
+                    "type": "boolean"
+                },
+                "level": {
+                    "description": "Level of the test alert, critical when empty",
+                    "type": "string",
+                    "example": "critical"
+                }
+            }
+        },
+        "TestFireResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "integration": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "sent, skipped (level not routed to the integration) or failed",
+                    "type": "string"
+                }
+            }
+        },
+        "TestRequest": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "description": "e.g. [\"-run\", \"TestLogin\"] for go test, a file for pytest",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "TestRun": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TestFailure"
+                    }
+                },
+                "framework": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "passed, failed, error",
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/TestSummary"
+                }
+            }
+        },
+        "TestSummary": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "passed": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "TimezoneInfo": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "IANA name, UTC or the offset given",
+                    "type": "string"
+                },
+                "offset": {
+                    "description": "e.g. +07:00",
+                    "type": "string"
+                },
+                "offset_seconds": {
+                    "description": "East of UTC",
+                    "type": "integer"
+                }
+            }
+        },
+        "TraceLine": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "live, buffer, stored",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "Capture time, unknown for lines saved without one",
+                    "type": "string"
+                }
+            }
+        },
+        "TraceProject": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "started_with": {
+                    "description": "The trace was injected when this project started",
+                    "type": "boolean"
+                }
+            }
+        },
+        "TraceResult": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TraceLine"
+                    }
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TraceProject"
+                    }
+                },
+                "trace_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "TrafficMetric": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_ms": {
+                    "type": "number"
+                },
+                "p50_ms": {
+                    "type": "number"
+                },
+                "p95_ms": {
+                    "type": "number"
+                },
+                "p99_ms": {
+                    "type": "number"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "status_2xx": {
+                    "type": "integer"
+                },
+                "status_3xx": {
+                    "type": "integer"
+                },
+                "status_4xx": {
+                    "type": "integer"
+                },
+                "status_5xx": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "End of the interval",
+                    "type": "string"
+                }
+            }
+        },
+        "TrafficReport": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "description": "Markers over the history: deploys, config changes and notes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectAnnotation"
+                    }
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TrafficMetric"
+                    }
+                },
+                "live": {
+                    "description": "Null until a request is proxied",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/TrafficStats"
+                        }
+                    ]
+                },
+                "stats": {
+                    "$ref": "#/definitions/ServiceStats"
+                }
+            }
+        },
+        "TrafficStats": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "5xx responses, including failed proxying and starts",
+                    "type": "integer"
+                },
+                "last_request_at": {
+                    "type": "string"
+                },
+                "max_ms": {
+                    "type": "number"
+                },
+                "p50_ms": {
+                    "description": "Over the recent requests",
+                    "type": "number"
+                },
+                "p90_ms": {
+                    "type": "number"
+                },
+                "p99_ms": {
+                    "type": "number"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "status_classes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "status_codes": {
+                    "description": "By status code",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                }
+            }
+        },
+        "Tunnel": {
+            "type": "object",
+            "properties": {
+                "local_port": {
+                    "type": "integer"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "public_url": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "UnixSocketInfo": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "process_name": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "Project declaring this socket path, if any",
+                    "type": "integer"
+                },
+                "project_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "UpdateEnvFileRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Full file content",
+                    "type": "string"
+                },
+                "set": {
+                    "description": "Variables to add or update",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "unset": {
+                    "description": "Variables to remove",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "UpdateOnboardingRequest": {
+            "type": "object",
+            "properties": {
+                "group_name": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/OnboardingOptions"
+                },
+                "services": {
+                    "description": "Replaces the proposed services",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OnboardingService"
+                    }
+                }
+            }
+        },
+        "UpdateProjectFromConfigRequest": {
+            "type": "object",
+            "required": [
+                "config"
+            ],
+            "properties": {
+                "config": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "Only lint the configuration",
+                    "type": "boolean"
+                },
+                "format": {
+                    "description": "yaml or json",
+                    "type": "string"
+                }
+            }
+        },
+        "UpdateProjectGroupRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "UpdateProjectPortsRequest": {
+            "type": "object",
+            "properties": {
+                "ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectPortRequest"
+                    }
+                }
+            }
+        },
+        "UpdateProjectRequest": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "auto_restart": {
+                    "type": "boolean"
+                },
+                "autostart": {
+                    "type": "boolean"
+                },
+                "chat_channel": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "ci_branch": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "ci_repo": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "command": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "connection_string": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "cpu_affinity": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "cpu_limit": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "declared_ports": {
+                    "description": "Replace the declared ports",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ProjectPort"
+                    }
+                },
+                "depends_on": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "docs_url": {
+                    "type": "string"
+                },
+                "drain_timeout": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0
+                },
+                "editor": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "editor_args": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "env_file": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "env_vars": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "environment": {
+                    "type": "string",
+                    "enum": [
+                        "development",
+                        "staging",
+                        "production"
+                    ]
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "health_check_url": {
+                    "type": "string"
+                },
+                "idle_timeout": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "instance_ports": {
+                    "type": "string",
+                    "enum": [
+                        "offset",
+                        "auto"
+                    ]
+                },
+                "instances": {
+                    "type": "integer",
+                    "maximum": 16,
+                    "minimum": 0
+                },
+                "io_class": {
+                    "type": "string",
+                    "enum": [
+                        "realtime",
+                        "best-effort",
+                        "idle"
+                    ]
+                },
+                "io_priority": {
+                    "type": "integer",
+                    "maximum": 7,
+                    "minimum": 0
+                },
+                "kube_context": {
+                    "type": "string",
+                    "maxLength": 253
+                },
+                "kube_deployment": {
+                    "type": "string",
+                    "maxLength": 253
+                },
+                "kube_namespace": {
+                    "type": "string",
+                    "maxLength": 63
+                },
+                "kube_replicas": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "max_restarts": {
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 0
+                },
+                "mdns": {
+                    "type": "boolean"
+                },
+                "mdns_name": {
+                    "type": "string",
+                    "maxLength": 63
+                },
+                "memory_guard_mb": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "memory_guard_restart": {
+                    "type": "boolean"
+                },
+                "memory_guard_samples": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "memory_limit": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "migration_command": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "mock_spec": {
+                    "type": "string",
+                    "maxLength": 100000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "nice": {
+                    "type": "integer",
+                    "maximum": 19,
+                    "minimum": -20
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "owner": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "path": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "port": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 0
+                },
+                "ports": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "pprof_url": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "queue_backlog_limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "queue_growth_limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "queues": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "repository_url": {
+                    "type": "string"
+                },
+                "restart_strategy": {
+                    "type": "string",
+                    "enum": [
+                        "stop_start",
+                        "blue_green"
+                    ]
+                },
+                "smoke_tests": {
+                    "type": "string",
+                    "maxLength": 20000
+                },
+                "smoke_tests_fail_error": {
+                    "type": "boolean"
+                },
+                "smoke_tests_on_start": {
+                    "type": "boolean"
+                },
+                "socket_path": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "ssh_host": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "status_page": {
+                    "type": "boolean"
+                },
+                "status_page_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "systemd_unit": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "systemd_user": {
+                    "type": "boolean"
+                },
+                "tail_files": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "team": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "test_command": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "trace_injection": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "enum": [
+                        "backend",
+                        "frontend",
+                        "worker",
+                        "database",
+                        "queue",
+                        "systemd",
+                        "mock",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ServiceType"
+                        }
+                    ]
+                },
+                "version": {
+                    "description": "Version the changes were made on, checked rather than set",
+                    "type": "integer"
+                },
+                "wait_for": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "wait_for_interval": {
+                    "type": "integer",
+                    "maximum": 300,
+                    "minimum": 0
+                },
+                "wait_for_timeout": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0
+                },
+                "watch_files": {
+                    "type": "boolean"
+                },
+                "working_dir": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "UpgradeDependencyRequest": {
+            "type": "object",
+            "required": [
+                "package"
+            ],
+            "properties": {
+                "package": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Latest when empty",
+                    "type": "string"
+                }
+            }
+        },
+        "UptimeBucket": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status with the longest time in the bucket (worst one for groups)",
+                    "type": "string"
+                },
+                "uptime_percent": {
+                    "description": "Time running over known time, absent when nothing is known",
+                    "type": "number"
+                }
+            }
+        },
+        "VSCodeCompound": {
+            "type": "object",
+            "properties": {
+                "configurations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "stopAll": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "VSCodeExport": {
+            "type": "object",
+            "properties": {
+                "launch": {
+                    "$ref": "#/definitions/VSCodeLaunch"
+                },
+                "notes": {
+                    "description": "Projects without a debugger configuration",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tasks": {
+                    "$ref": "#/definitions/VSCodeTasks"
+                }
             }
-        }
-    },
-    "definitions": {
-        "gorm.DeletedAt": {
+        },
+        "VSCodeLaunch": {
             "type": "object",
             "properties": {
-                "time": {
-                    "type": "string"
+                "compounds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/VSCodeCompound"
+                    }
                 },
-                "valid": {
-                    "description": "Valid is true if Time is not NULL",
-                    "type": "boolean"
+                "configurations": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": true
+                    }
+                },
+                "version": {
+                    "type": "string"
                 }
             }
         },
-        "internal_project.Project": {
+        "VSCodeTask": {
             "type": "object",
-            "required": [
-                "name",
-                "path"
-            ],
             "properties": {
                 "args": {
-                    "description": "Additional arguments",
-                    "type": "string"
-                },
-                "auto_restart": {
-                    "description": "Auto-restart settings",
-                    "type": "boolean"
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "command": {
-                    "description": "Command to start the service",
                     "type": "string"
                 },
-                "cpu_limit": {
-                    "description": "Resource limits",
-                    "type": "string"
+                "dependsOn": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "created_at": {
+                "dependsOrder": {
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
-                },
-                "description": {
+                "detail": {
                     "type": "string"
                 },
-                "editor": {
-                    "description": "IDE and development",
-                    "type": "string"
+                "isBackground": {
+                    "type": "boolean"
                 },
-                "editor_args": {
-                    "description": "Additional editor arguments",
+                "label": {
                     "type": "string"
                 },
-                "env_file": {
-                    "description": "Path to .env file",
-                    "type": "string"
+                "options": {
+                    "$ref": "#/definitions/VSCodeTaskOptions"
                 },
-                "env_vars": {
-                    "description": "JSON object of environment variables",
-                    "type": "string"
+                "problemMatcher": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "environment": {
-                    "description": "Environment and configuration",
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "VSCodeTaskOptions": {
+            "type": "object",
+            "properties": {
+                "cwd": {
                     "type": "string"
                 },
-                "group": {
-                    "$ref": "#/definitions/internal_project.ProjectGroup"
+                "env": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "VSCodeTasks": {
+            "type": "object",
+            "properties": {
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/VSCodeTask"
+                    }
                 },
-                "group_id": {
-                    "type": "integer"
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "WebhookResult": {
+            "type": "object",
+            "properties": {
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "Window": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Computed on read",
+                    "type": "boolean"
                 },
-                "health_check_url": {
-                    "description": "Health check",
+                "created_at": {
                     "type": "string"
                 },
-                "health_status": {
-                    "description": "healthy, unhealthy, unknown",
+                "cron": {
+                    "description": "Recurring window: starts on the cron schedule (server local time) and\nlasts duration_minutes",
                     "type": "string"
                 },
-                "id": {
+                "duration_minutes": {
                     "type": "integer"
                 },
-                "last_error": {
-                    "description": "Last error message",
+                "ends_at": {
                     "type": "string"
                 },
-                "max_restarts": {
+                "id": {
                     "type": "integer"
                 },
-                "memory_limit": {
-                    "description": "Memory limit (e.g., \"512Mi\")",
-                    "type": "string"
-                },
                 "name": {
-                    "description": "Basic info",
                     "type": "string"
                 },
-                "path": {
-                    "description": "Path and execution",
+                "next_start": {
                     "type": "string"
                 },
-                "pid": {
-                    "description": "Process ID when running",
-                    "type": "integer"
-                },
-                "port": {
-                    "description": "Network and ports",
-                    "type": "integer"
-                },
-                "ports": {
-                    "description": "JSON array of ports for complex services",
-                    "type": "string"
+                "pause_auto_restart": {
+                    "description": "Do not restart crashed services",
+                    "type": "boolean"
                 },
-                "restart_count": {
-                    "type": "integer"
+                "project_ids": {
+                    "description": "Scope: the listed projects, or the system and every project when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "start_time": {
-                    "description": "When service started",
+                "reason": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "Service management",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/internal_project.ServiceStatus"
-                        }
-                    ]
-                },
-                "stop_time": {
-                    "description": "When service stopped",
+                "starts_at": {
+                    "description": "One-off window",
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/internal_project.ServiceType"
+                "suppress_alerts": {
+                    "description": "Do not raise alerts",
+                    "type": "boolean"
                 },
                 "updated_at": {
                     "type": "string"
-                },
-                "working_dir": {
-                    "description": "Working directory",
-                    "type": "string"
                 }
             }
         },
-        "internal_project.ProjectGroup": {
+        "WireGuardPeer": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "color": {
-                    "description": "Hex color for UI",
-                    "type": "string"
+                "allowed_ips": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "created_at": {
+                "endpoint": {
                     "type": "string"
                 },
-                "deleted_at": {
-                    "$ref": "#/definitions/gorm.DeletedAt"
+                "handshake_age_seconds": {
+                    "type": "number"
                 },
-                "description": {
+                "interface": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "latest_handshake": {
+                    "type": "string"
                 },
-                "name": {
+                "public_key": {
                     "type": "string"
                 },
-                "projects": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/internal_project.Project"
-                    }
+                "rx_bytes": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "status": {
+                    "description": "up, or down when the last handshake is older than the timeout",
                     "type": "string"
+                },
+                "tx_bytes": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_project.ServiceStatus": {
-            "type": "string",
-            "enum": [
-                "stopped",
-                "starting",
-                "running",
-                "stopping",
-                "error",
-                "unknown",
-                "stopped",
-                "starting",
-                "running",
-                "stopping",
-                "error",
-                "unknown"
-            ],
-            "x-enum-varnames": [
-                "StatusStopped",
-                "StatusStarting",
-                "StatusRunning",
-                "StatusStopping",
-                "StatusError",
-                "StatusUnknown"
-            ]
-        },
-        "internal_project.ServiceType": {
-            "type": "string",
-            "enum": [
-                "backend",
-                "frontend",
-                "worker",
-                "database",
-                "queue",
-                "other",
-                "backend",
-                "frontend",
-                "worker",
-                "database",
-                "queue",
-                "other"
-            ],
-            "x-enum-varnames": [
-                "TypeBackend",
-                "TypeFrontend",
-                "TypeWorker",
-                "TypeDatabase",
-                "TypeQueue",
-                "TypeOther"
-            ]
-        },
-        "internal_system.SystemConfig": {
+        "WorkspaceSpec": {
             "type": "object",
             "properties": {
-                "alert_email": {
-                    "description": "Alert email address",
-                    "type": "string"
-                },
-                "alert_webhook": {
-                    "description": "Alert webhook URL",
-                    "type": "string"
-                },
-                "check_interval": {
-                    "description": "Check interval in seconds",
-                    "type": "integer"
-                },
-                "cpu_limit": {
-                    "description": "CPU usage threshold (%)",
-                    "type": "number"
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "disk_limit": {
-                    "description": "Disk usage threshold (%)",
-                    "type": "number"
-                },
-                "enable_alerts": {
-                    "description": "Enable alerting",
-                    "type": "boolean"
+                "hash": {
+                    "description": "sha256 of the expanded spec",
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "memory_limit": {
-                    "description": "Memory usage threshold (%)",
-                    "type": "number"
-                },
-                "network_limit": {
-                    "description": "Network usage threshold (Mbps)",
-                    "type": "number"
-                },
-                "retention_days": {
-                    "description": "Metrics retention in days",
-                    "type": "integer"
+                "prune": {
+                    "description": "Projects and groups missing from the spec were deleted",
+                    "type": "boolean"
                 },
-                "updated_at": {
+                "source": {
+                    "description": "Path of the file on the server, or upload",
                     "type": "string"
                 }
             }
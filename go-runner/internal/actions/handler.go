@@ -0,0 +1,253 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the action registry
+type Handler struct {
+	engine   *gin.Engine
+	basePath string
+}
+
+// RegisterRoutes registers the action routes. Actions run their route on
+// engine below the base path of r, through the same authentication.
+func RegisterRoutes(r *gin.RouterGroup, engine *gin.Engine) {
+	h := &Handler{engine: engine, basePath: r.BasePath()}
+
+	actions := r.Group("/actions")
+	{
+		actions.GET("", h.GetActions)
+		actions.GET("/:id", h.GetAction)
+		actions.POST("/:id/execute", h.ExecuteAction)
+	}
+}
+
+// ExecuteRequest holds the parameters of an action by name
+type ExecuteRequest struct {
+	Params map[string]interface{} `json:"params"`
+}
+
+// GetActions godoc
+// @Summary      List actions
+// @Description  List the actions that can be executed with POST /actions/{id}/execute, in the order command palettes show them, with their stable ID, the route they run and the schema of their parameters. Path parameters with an entity are the ID of a project or group; destructive actions should be confirmed first.
+// @Tags         actions
+// @Produce      json
+// @Param        entity  query     string  false  "Only the actions on this entity: project, group or system"
+// @Success      200     {object}  types.DataResponse{data=[]Action}
+// @Failure      400     {object}  middleware.ErrorResponse  "Invalid entity"
+// @Router       /actions [get]
+func (h *Handler) GetActions(c *gin.Context) {
+	entity := c.Query("entity")
+	if entity != "" && entity != EntityProject && entity != EntityGroup && entity != EntitySystem {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid entity", "Expected project, group or system"))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: List(entity)})
+}
+
+// GetAction godoc
+// @Summary      Get an action
+// @Description  Get an action of the registry by its ID
+// @Tags         actions
+// @Produce      json
+// @Param        id   path      string  true  "Action ID, e.g. project.restart"
+// @Success      200  {object}  types.DataResponse{data=Action}
+// @Failure      404  {object}  middleware.ErrorResponse  "Action not found"
+// @Router       /actions/{id} [get]
+func (h *Handler) GetAction(c *gin.Context) {
+	action, ok := Lookup(c.Param("id"))
+	if !ok {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Action not found", c.Param("id")))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: action})
+}
+
+// ExecuteAction godoc
+// @Summary      Execute an action
+// @Description  Check the parameters against the schema of the action and run its route with them, with the credentials of this request. The response is that of the route: its status, headers and body.
+// @Tags         actions
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string          true   "Action ID, e.g. project.restart"
+// @Param        request  body      ExecuteRequest  false  "Parameters"
+// @Success      200      {object}  object          "Response of the route of the action"
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid parameters"
+// @Failure      404      {object}  middleware.ErrorResponse  "Action not found"
+// @Router       /actions/{id}/execute [post]
+func (h *Handler) ExecuteAction(c *gin.Context) {
+	action, ok := Lookup(c.Param("id"))
+	if !ok {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Action not found", c.Param("id")))
+		return
+	}
+	var req ExecuteRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+			return
+		}
+	}
+
+	target, body, err := action.request(h.basePath, req.Params)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	inner, err := http.NewRequestWithContext(c.Request.Context(), action.Method, target, bytes.NewReader(body))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to run action", err.Error()))
+		return
+	}
+	for _, header := range []string{"Authorization", "Accept", "X-Forwarded-For", "X-Real-IP"} {
+		if value := c.GetHeader(header); value != "" {
+			inner.Header.Set(header, value)
+		}
+	}
+	if body != nil {
+		inner.Header.Set("Content-Type", "application/json")
+	}
+	inner.RemoteAddr = c.Request.RemoteAddr
+
+	recorder := httptest.NewRecorder()
+	h.engine.ServeHTTP(recorder, inner)
+	for key, values := range recorder.Header() {
+		c.Writer.Header()[key] = values
+	}
+	c.Writer.Header().Set("X-Action", action.ID)
+	c.Status(recorder.Code)
+	c.Writer.Write(recorder.Body.Bytes())
+}
+
+// request builds the URL and JSON body of the route of the action from the
+// parameters, refusing unknown, missing and mistyped ones
+func (a Action) request(basePath string, params map[string]interface{}) (string, []byte, error) {
+	known := make(map[string]bool, len(a.Params))
+	for _, param := range a.Params {
+		known[param.Name] = true
+	}
+	for name := range params {
+		if !known[name] {
+			return "", nil, middleware.NewError(http.StatusBadRequest, "Unknown parameter "+name, a.paramNames())
+		}
+	}
+
+	path := a.Path
+	query := url.Values{}
+	var body map[string]interface{}
+	for _, param := range a.Params {
+		if param.In == InBody && body == nil {
+			body = map[string]interface{}{}
+		}
+		value, set := params[param.Name]
+		if !set || value == nil {
+			if param.Required {
+				return "", nil, middleware.NewError(http.StatusBadRequest, "Missing parameter "+param.Name, param)
+			}
+			continue
+		}
+		if err := param.check(value); err != nil {
+			return "", nil, middleware.NewError(http.StatusBadRequest, "Invalid parameter "+param.Name, err.Error())
+		}
+		switch param.In {
+		case InPath:
+			path = strings.Replace(path, ":"+param.Name, url.PathEscape(scalar(value)), 1)
+		case InQuery:
+			query.Set(param.Name, scalar(value))
+		case InBody:
+			body[param.Name] = value
+		}
+	}
+
+	target := basePath + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	if body == nil {
+		return target, nil, nil
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return "", nil, middleware.NewError(http.StatusBadRequest, "Invalid parameters", err.Error())
+	}
+	return target, raw, nil
+}
+
+func (a Action) paramNames() []string {
+	names := make([]string, len(a.Params))
+	for i, param := range a.Params {
+		names[i] = param.Name
+	}
+	return names
+}
+
+// check reports whether a value decoded from JSON fits the parameter
+func (p Param) check(value interface{}) error {
+	if p.Type != TypeArray {
+		return checkValue(p.Type, p.Enum, value)
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected an array, got %T", value)
+	}
+	for i, item := range items {
+		if err := checkValue(p.Items, p.Enum, item); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func checkValue(kind string, enum []string, value interface{}) error {
+	switch kind {
+	case TypeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		if len(enum) == 0 {
+			return nil
+		}
+		for _, allowed := range enum {
+			if s == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", s, strings.Join(enum, ", "))
+	case TypeInteger:
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("expected a whole number, got %v", value)
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected true or false, got %v", value)
+		}
+	}
+	return nil
+}
+
+// scalar formats a path or query parameter
+func scalar(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,385 @@
+// Package actions is the registry of what can be done to projects, groups
+// and the system, with stable IDs and parameter schemas, so command
+// palettes and the CLI share one action surface. Actions run the API route
+// they describe.
+package actions
+
+// Entities actions operate on
+const (
+	EntityProject = "project"
+	EntityGroup   = "group"
+	EntitySystem  = "system"
+)
+
+// Parameter locations
+const (
+	InPath  = "path"
+	InQuery = "query"
+	InBody  = "body"
+)
+
+// Parameter types, as in JSON Schema
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeArray   = "array"
+)
+
+// Param describes a parameter of an action
+type Param struct {
+	Name        string   `json:"name"`
+	In          string   `json:"in"`              // path, query or body
+	Type        string   `json:"type"`            // string, integer, boolean or array
+	Items       string   `json:"items,omitempty"` // Type of the elements of arrays
+	Required    bool     `json:"required"`
+	Enum        []string `json:"enum,omitempty"`   // Allowed values, of the elements for arrays
+	Entity      string   `json:"entity,omitempty"` // The parameter is the ID of a project or group
+	Description string   `json:"description,omitempty"`
+}
+
+// Action is something that can be executed with POST /actions/:id/execute
+type Action struct {
+	ID          string  `json:"id"` // Stable, e.g. project.restart
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Entity      string  `json:"entity"` // project, group or system
+	Method      string  `json:"method"`
+	Path        string  `json:"path"`        // API route run, below /api/v1
+	Destructive bool    `json:"destructive"` // Ask for confirmation first
+	Params      []Param `json:"params"`
+}
+
+// projectID is the path parameter of project actions
+var projectID = Param{Name: "id", In: InPath, Type: TypeInteger, Required: true, Entity: EntityProject, Description: "Project ID"}
+
+// groupID is the path parameter of group actions
+var groupID = Param{Name: "id", In: InPath, Type: TypeInteger, Required: true, Entity: EntityGroup, Description: "Group ID"}
+
+// registry lists the actions in the order palettes show them. IDs are
+// never renamed; retired actions are removed.
+var registry = []Action{
+	{
+		ID: "project.start", Title: "Start", Entity: EntityProject,
+		Description: "Start the service, queued while the host is busy",
+		Method:      "POST", Path: "/projects/:id/start",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.stop", Title: "Stop", Entity: EntityProject,
+		Description: "Stop the service",
+		Method:      "POST", Path: "/projects/:id/stop",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.restart", Title: "Restart", Entity: EntityProject,
+		Description: "Stop and start the service",
+		Method:      "POST", Path: "/projects/:id/restart",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.force_kill", Title: "Force kill", Entity: EntityProject,
+		Description: "Kill the service process right away, without a graceful stop",
+		Method:      "POST", Path: "/projects/:id/force-kill", Destructive: true,
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.pause", Title: "Pause", Entity: EntityProject,
+		Description: "Freeze the service with SIGSTOP, keeping its state",
+		Method:      "POST", Path: "/projects/:id/pause",
+		Params: []Param{
+			projectID,
+			{Name: "duration", In: InBody, Type: TypeInteger, Description: "Seconds before it is resumed, paused until resumed when empty"},
+		},
+	},
+	{
+		ID: "project.resume", Title: "Resume", Entity: EntityProject,
+		Description: "Continue a paused service",
+		Method:      "POST", Path: "/projects/:id/resume",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.status", Title: "Show status", Entity: EntityProject,
+		Description: "Status, resources and health of the service",
+		Method:      "GET", Path: "/projects/:id/status",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.logs", Title: "Show logs", Entity: EntityProject,
+		Description: "Recent logs of the service",
+		Method:      "GET", Path: "/projects/:id/logs",
+		Params: []Param{
+			projectID,
+			{Name: "timestamps", In: InQuery, Type: TypeBoolean, Description: "Prefix lines with their capture time"},
+			{Name: "tz", In: InQuery, Type: TypeString, Description: "Time zone of the timestamps, e.g. Europe/Berlin"},
+		},
+	},
+	{
+		ID: "project.ci", Title: "Show CI status", Entity: EntityProject,
+		Description: "Latest pipeline of the repository of the project",
+		Method:      "GET", Path: "/projects/:id/ci",
+		Params: []Param{
+			projectID,
+			{Name: "refresh", In: InQuery, Type: TypeBoolean, Description: "Fetch the latest pipeline now"},
+		},
+	},
+	{
+		ID: "project.doctor", Title: "Run doctor", Entity: EntityProject,
+		Description: "Check that the machine can run the project: command, toolchain versions, variables and reachable services",
+		Method:      "POST", Path: "/projects/:id/doctor",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.test", Title: "Run tests", Entity: EntityProject,
+		Description: "Run the test command of the project",
+		Method:      "POST", Path: "/projects/:id/test",
+		Params: []Param{
+			projectID,
+			{Name: "args", In: InBody, Type: TypeArray, Items: TypeString, Description: "Extra arguments"},
+		},
+	},
+	{
+		ID: "project.smoke_test", Title: "Run smoke tests", Entity: EntityProject,
+		Description: "Send the smoke test requests of the project and check the answers",
+		Method:      "POST", Path: "/projects/:id/smoke-tests/run",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.run_script", Title: "Run script", Entity: EntityProject,
+		Description: "Run a make target or package.json script of the project",
+		Method:      "POST", Path: "/projects/:id/scripts/run",
+		Params: []Param{
+			projectID,
+			{Name: "name", In: InBody, Type: TypeString, Required: true, Description: "Target or script"},
+			{Name: "source", In: InBody, Type: TypeString, Enum: []string{"make", "npm"}, Description: "Needed when a target and a script share the name"},
+			{Name: "args", In: InBody, Type: TypeArray, Items: TypeString, Description: "Extra arguments"},
+		},
+	},
+	{
+		ID: "project.install", Title: "Install packages", Entity: EntityProject,
+		Description: "Install dependencies, or the listed packages, in the background",
+		Method:      "POST", Path: "/projects/:id/install",
+		Params: []Param{
+			projectID,
+			{Name: "package_manager", In: InBody, Type: TypeString, Required: true, Enum: []string{"npm", "yarn", "pnpm", "go", "pip"}},
+			{Name: "packages", In: InBody, Type: TypeArray, Items: TypeString, Description: "Every dependency when empty"},
+		},
+	},
+	{
+		ID: "project.migrate", Title: "Migrate database", Entity: EntityProject,
+		Description: "Run the migration command of the project as a background job",
+		Method:      "POST", Path: "/projects/:id/database/migrate",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.profile", Title: "Capture profile", Entity: EntityProject,
+		Description: "Capture a pprof profile of the service",
+		Method:      "POST", Path: "/projects/:id/profile",
+		Params: []Param{
+			projectID,
+			{Name: "type", In: InBody, Type: TypeString, Enum: []string{"cpu", "heap", "allocs", "goroutine"}, Description: "cpu when empty"},
+			{Name: "seconds", In: InBody, Type: TypeInteger, Description: "CPU profiling duration, default 10, max 300"},
+			{Name: "flamegraph", In: InBody, Type: TypeBoolean, Description: "Render the flame graph right away"},
+		},
+	},
+	{
+		ID: "project.debug_bundle", Title: "Download debug bundle", Entity: EntityProject,
+		Description: "Logs, configuration, history and system state of the project in a zip",
+		Method:      "POST", Path: "/projects/:id/debug-bundle",
+		Params: []Param{
+			projectID,
+			{Name: "hours", In: InQuery, Type: TypeInteger, Description: "Hours of history (default 24, max 720)"},
+		},
+	},
+	{
+		ID: "project.annotate", Title: "Annotate", Entity: EntityProject,
+		Description: "Add a note or deploy marker to the timeline of the project",
+		Method:      "POST", Path: "/projects/:id/annotations",
+		Params: []Param{
+			projectID,
+			{Name: "message", In: InBody, Type: TypeString, Required: true},
+			{Name: "kind", In: InBody, Type: TypeString, Enum: []string{"note", "deploy"}, Description: "note when empty"},
+			{Name: "details", In: InBody, Type: TypeString},
+			{Name: "revision", In: InBody, Type: TypeString},
+		},
+	},
+	{
+		ID: "project.tunnel_start", Title: "Open tunnel", Entity: EntityProject,
+		Description: "Expose the service on a public URL",
+		Method:      "POST", Path: "/projects/:id/tunnel",
+		Params: []Param{
+			projectID,
+			{Name: "provider", In: InBody, Type: TypeString, Enum: []string{"cloudflared", "ngrok", "ssh"}, Description: "Configured or detected when empty"},
+			{Name: "port", In: InBody, Type: TypeInteger, Description: "Project port when empty"},
+		},
+	},
+	{
+		ID: "project.tunnel_stop", Title: "Close tunnel", Entity: EntityProject,
+		Description: "Stop the tunnel of the service",
+		Method:      "DELETE", Path: "/projects/:id/tunnel",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.inspect_enable", Title: "Enable inspector", Entity: EntityProject,
+		Description: "Restart the Node.js service with the inspector on",
+		Method:      "POST", Path: "/projects/:id/inspect",
+		Params: []Param{
+			projectID,
+			{Name: "port", In: InBody, Type: TypeInteger, Description: "Free port from 9229 when empty"},
+		},
+	},
+	{
+		ID: "project.inspect_disable", Title: "Disable inspector", Entity: EntityProject,
+		Description: "Restart the Node.js service without the inspector",
+		Method:      "DELETE", Path: "/projects/:id/inspect",
+		Params: []Param{projectID},
+	},
+	{
+		ID: "project.priority", Title: "Set priority", Entity: EntityProject,
+		Description: "Change the CPU and IO priority and CPU pinning of the running service and its next starts",
+		Method:      "PUT", Path: "/projects/:id/priority",
+		Params: []Param{
+			projectID,
+			{Name: "nice", In: InBody, Type: TypeInteger, Description: "-20 (highest) to 19 (lowest)"},
+			{Name: "io_class", In: InBody, Type: TypeString, Enum: []string{"", "realtime", "best-effort", "idle"}, Description: "IO scheduling class, empty for the default"},
+			{Name: "io_priority", In: InBody, Type: TypeInteger, Description: "0 (highest) to 7 within realtime and best-effort"},
+			{Name: "cpu_affinity", In: InBody, Type: TypeString, Description: "CPU cores to pin to, e.g. 0-3,8; empty for all"},
+		},
+	},
+	{
+		ID: "project.clean_disk", Title: "Clean build artifacts", Entity: EntityProject,
+		Description: "Remove dependency, build and cache directories of the project",
+		Method:      "POST", Path: "/projects/:id/disk-usage/clean", Destructive: true,
+		Params: []Param{
+			projectID,
+			{Name: "paths", In: InBody, Type: TypeArray, Items: TypeString, Description: "Artifact directories from GET /disk-usage"},
+			{Name: "kinds", In: InBody, Type: TypeArray, Items: TypeString, Enum: []string{"dependencies", "build", "cache"}, Description: "Every directory of these kinds"},
+			{Name: "force", In: InBody, Type: TypeBoolean, Description: "Also remove while the project is running"},
+		},
+	},
+	{
+		ID: "project.chaos_latency", Title: "Inject latency", Entity: EntityProject,
+		Description: "Delay the requests proxied to the service for a while",
+		Method:      "POST", Path: "/projects/:id/chaos/latency",
+		Params: []Param{
+			projectID,
+			{Name: "delay_ms", In: InBody, Type: TypeInteger, Required: true, Description: "Milliseconds added to each request"},
+			{Name: "jitter_ms", In: InBody, Type: TypeInteger, Description: "Up to this many more milliseconds, at random"},
+			{Name: "duration", In: InBody, Type: TypeInteger, Description: "Seconds (default 60)"},
+		},
+	},
+	{
+		ID: "project.chaos_pause", Title: "Chaos pause", Entity: EntityProject,
+		Description: "Suspend the service for a while, then continue it",
+		Method:      "POST", Path: "/projects/:id/chaos/pause", Destructive: true,
+		Params: []Param{
+			projectID,
+			{Name: "duration", In: InBody, Type: TypeInteger, Required: true, Description: "Seconds"},
+		},
+	},
+	{
+		ID: "project.delete", Title: "Delete", Entity: EntityProject,
+		Description: "Stop the service and delete the project",
+		Method:      "DELETE", Path: "/projects/:id", Destructive: true,
+		Params: []Param{projectID},
+	},
+	{
+		ID: "group.projects", Title: "Show projects", Entity: EntityGroup,
+		Description: "Projects of the group",
+		Method:      "GET", Path: "/groups/:id/projects",
+		Params: []Param{groupID},
+	},
+	{
+		ID: "group.timeline", Title: "Show timeline", Entity: EntityGroup,
+		Description: "Status history of the projects of the group",
+		Method:      "GET", Path: "/groups/:id/timeline",
+		Params: []Param{
+			groupID,
+			{Name: "hours", In: InQuery, Type: TypeInteger, Description: "Hours of history (default 24, max 2160)"},
+			{Name: "buckets", In: InQuery, Type: TypeInteger, Description: "Number of uptime buckets (default 24, max 200)"},
+		},
+	},
+	{
+		ID: "group.rolling_restart", Title: "Rolling restart", Entity: EntityGroup,
+		Description: "Restart the projects of the group one batch at a time, waiting for readiness",
+		Method:      "POST", Path: "/groups/:id/rolling-restart",
+		Params: []Param{
+			groupID,
+			{Name: "batch_size", In: InBody, Type: TypeInteger, Description: "Projects restarted at once (default 1)"},
+			{Name: "ready_timeout", In: InBody, Type: TypeInteger, Description: "Seconds a batch has to be ready (default 60)"},
+			{Name: "pause", In: InBody, Type: TypeInteger, Description: "Seconds to wait between batches"},
+			{Name: "include_stopped", In: InBody, Type: TypeBoolean, Description: "Also start projects that are not running"},
+		},
+	},
+	{
+		ID: "group.chaos_kill", Title: "Kill a random instance", Entity: EntityGroup,
+		Description: "Send SIGKILL to a running process of the group picked at random",
+		Method:      "POST", Path: "/groups/:id/chaos/kill", Destructive: true,
+		Params: []Param{groupID},
+	},
+	{
+		ID: "group.delete", Title: "Delete", Entity: EntityGroup,
+		Description: "Delete the group; its projects are kept",
+		Method:      "DELETE", Path: "/groups/:id", Destructive: true,
+		Params: []Param{groupID},
+	},
+	{
+		ID: "system.search", Title: "Search", Entity: EntitySystem,
+		Description: "Search projects, groups, logs and alerts",
+		Method:      "GET", Path: "/search",
+		Params: []Param{
+			{Name: "q", In: InQuery, Type: TypeString, Required: true},
+			{Name: "types", In: InQuery, Type: TypeString, Description: "Comma-separated categories: projects, groups, logs, alerts"},
+			{Name: "limit", In: InQuery, Type: TypeInteger, Description: "Results per category (default 10, max 50)"},
+		},
+	},
+	{
+		ID: "system.running_services", Title: "Show running services", Entity: EntitySystem,
+		Description: "Services running now with their resources",
+		Method:      "GET", Path: "/services/running",
+		Params: []Param{},
+	},
+	{
+		ID: "system.drift", Title: "Check workspace drift", Entity: EntitySystem,
+		Description: "Differences between the projects and the last applied workspace",
+		Method:      "GET", Path: "/projects/drift",
+		Params: []Param{},
+	},
+	{
+		ID: "system.power", Title: "Set power mode", Entity: EntitySystem,
+		Description: "Turn low-power mode on or off, or follow the power source",
+		Method:      "PUT", Path: "/system/power",
+		Params: []Param{
+			{Name: "mode", In: InBody, Type: TypeString, Required: true, Enum: []string{"auto", "on", "off"}},
+		},
+	},
+	{
+		ID: "system.cleanup_orphans", Title: "Clean up orphaned processes", Entity: EntitySystem,
+		Description: "Terminate processes left behind by services",
+		Method:      "POST", Path: "/system/orphans/cleanup", Destructive: true,
+		Params: []Param{
+			{Name: "pids", In: InBody, Type: TypeArray, Items: TypeInteger, Description: "From GET /system/orphans, every marked orphan when empty"},
+		},
+	},
+}
+
+// Lookup returns the action with the ID
+func Lookup(id string) (Action, bool) {
+	for _, action := range registry {
+		if action.ID == id {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// List returns the actions on the entity, every action when it is empty
+func List(entity string) []Action {
+	actions := make([]Action, 0, len(registry))
+	for _, action := range registry {
+		if entity == "" || action.Entity == entity {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/service"
+)
+
+// runAutostart starts projects flagged with autostart and reports each
+// outcome in the log and as events
+func runAutostart(manager *service.Manager, bus *events.Bus) {
+	summary := manager.Autostart(func(result service.AutostartResult) {
+		switch result.Result {
+		case service.AutostartStarted:
+			log.Printf("🚀 Autostart: started %s", result.ProjectName)
+		case service.AutostartAlreadyRunning:
+			log.Printf("✅ Autostart: %s is already running", result.ProjectName)
+		default:
+			log.Printf("❌ Autostart: %s %s: %s", result.ProjectName, result.Result, result.Error)
+		}
+		bus.Publish(result.ProjectID, "autostart", result)
+	})
+
+	if len(summary.Results) == 0 {
+		return
+	}
+
+	failed := 0
+	for _, result := range summary.Results {
+		if result.Result == service.AutostartFailed || result.Result == service.AutostartSkipped {
+			failed++
+		}
+	}
+	log.Printf("🏁 Autostart finished: %d project(s), %d failed or skipped in %s",
+		len(summary.Results), failed, summary.FinishedAt.Sub(summary.StartedAt).Round(time.Millisecond))
+	bus.Publish(0, "autostart_complete", summary)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"go-runner/internal/config"
+	"go-runner/internal/events"
+	"go-runner/internal/websocket"
+
+	"gorm.io/gorm"
+)
+
+// newEventBus creates the event bus with the sinks of the events section:
+// the WebSocket hub gets every event, the audit log and the NDJSON file the
+// configured categories, each webhook its own selection and the PagerDuty
+// and Opsgenie integrations the alerts
+func newEventBus(db *gorm.DB, hub *websocket.Hub, cfg config.EventsConfig) *events.Bus {
+	bus := events.NewBus()
+	bus.Subscribe(events.NewHubSink(hub), events.SinkOptions{Sync: true})
+
+	retention := time.Duration(cfg.AuditRetention) * 24 * time.Hour
+	bus.Subscribe(events.NewAuditSink(db, retention), events.SinkOptions{Categories: cfg.AuditCategories})
+
+	if cfg.LogFile != "" {
+		sink, err := events.NewFileSink(cfg.LogFile, int64(cfg.LogMaxSize)<<20)
+		if err != nil {
+			log.Printf("⚠️  Event log %s disabled: %v", cfg.LogFile, err)
+		} else {
+			bus.Subscribe(sink, events.SinkOptions{Categories: cfg.LogCategories})
+		}
+	}
+
+	for _, webhook := range cfg.Webhooks {
+		sink, err := events.NewWebhookSink(webhook.URL, webhook.Secret)
+		if err != nil {
+			log.Printf("⚠️  Event webhook disabled: %v", err)
+			continue
+		}
+		bus.Subscribe(sink, events.SinkOptions{Categories: webhook.Categories, Types: webhook.Types})
+	}
+
+	for _, pd := range cfg.PagerDuty {
+		sink, err := events.NewPagerDutySink(pd.Name, pd.RoutingKey, pd.Levels, pd.APIURL)
+		if err != nil {
+			log.Printf("⚠️  PagerDuty integration %s disabled: %v", pd.Name, err)
+			continue
+		}
+		bus.Subscribe(sink, events.SinkOptions{Types: events.AlertTypes})
+	}
+
+	for _, og := range cfg.Opsgenie {
+		sink, err := events.NewOpsgenieSink(og.Name, og.APIKey, events.OpsgenieOptions{
+			Levels:     og.Levels,
+			Priorities: og.Priorities,
+			Team:       og.Team,
+			Tags:       og.Tags,
+			APIURL:     og.APIURL,
+		})
+		if err != nil {
+			log.Printf("⚠️  Opsgenie integration %s disabled: %v", og.Name, err)
+			continue
+		}
+		bus.Subscribe(sink, events.SinkOptions{Types: events.AlertTypes})
+	}
+	return bus
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"go-runner/internal/mdns"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// MDNSStatus lists the projects announced on the local network
+type MDNSStatus struct {
+	Enabled  bool           `json:"enabled"`
+	Services []mdns.Service `json:"services"`
+}
+
+// startMDNS announces running projects that opted in with "mdns"
+func startMDNS(db *gorm.DB) *mdns.Responder {
+	responder := mdns.NewResponder(func() []mdns.Service {
+		var projects []struct {
+			ID       uint
+			Name     string
+			MDNSName string `gorm:"column:mdns_name"`
+			Port     int
+		}
+		db.Table(tables.Name("projects")).
+			Select("id, name, mdns_name, port").
+			Where("mdns_announce = ? AND status = ? AND port > 0 AND deleted_at IS NULL", true, string(types.StatusRunning)).
+			Order("id").
+			Find(&projects)
+
+		services := make([]mdns.Service, 0, len(projects))
+		for _, p := range projects {
+			services = append(services, mdns.Service{ProjectID: p.ID, Instance: p.Name, Host: p.MDNSName, Port: p.Port})
+		}
+		return services
+	})
+
+	go func() {
+		if err := responder.Run(context.Background()); err != nil {
+			log.Printf("❌ mDNS responder stopped: %v", err)
+		}
+	}()
+	return responder
+}
+
+// mdnsStatus godoc
+// @Summary      List mDNS announcements
+// @Description  List the running projects announced on the local network as <mdns_name>.local (DNS-SD type _http._tcp). Announcing is enabled with mdns.enabled in the config and per project with "mdns": true.
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=MDNSStatus}
+// @Router       /mdns [get]
+func mdnsStatus(responder *mdns.Responder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := MDNSStatus{Services: []mdns.Service{}}
+		if responder != nil {
+			status.Enabled = true
+			status.Services = responder.Services()
+		}
+		c.JSON(http.StatusOK, types.DataResponse{Data: status})
+	}
+}
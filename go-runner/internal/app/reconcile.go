@@ -0,0 +1,28 @@
+package app
+
+import (
+	"log"
+
+	"go-runner/internal/events"
+	"go-runner/internal/service"
+)
+
+// runReconcile re-attaches to services that kept running while the server
+// was down and reports each outcome in the log and as events
+func runReconcile(manager *service.Manager, bus *events.Bus) {
+	summary := manager.ReconcileProcesses(func(result service.ReconciledProcess) {
+		switch result.Result {
+		case service.ReconcileAdopted:
+			log.Printf("🔗 Reconcile: re-attached %s (PID %d)", result.ProjectName, result.PID)
+		case service.ReconcileStopped:
+			log.Printf("⏹️  Reconcile: %s is no longer running: %s", result.ProjectName, result.Reason)
+		default:
+			log.Printf("⚠️  Reconcile: %s (PID %d) is running but unverified, adopt or stop it: %s", result.ProjectName, result.PID, result.Reason)
+		}
+		bus.Publish(result.ProjectID, "reconcile", result)
+	})
+
+	if len(summary.Results) > 0 {
+		bus.Publish(0, "reconcile_complete", summary)
+	}
+}
@@ -1,11 +1,24 @@
 package app
 
 import (
+	"log"
+	"time"
+
 	_ "go-runner/docs"
+	"go-runner/internal/actions"
+	"go-runner/internal/archive"
+	"go-runner/internal/ci"
+	"go-runner/internal/config"
+	"go-runner/internal/dashboard"
+	"go-runner/internal/events"
+	"go-runner/internal/jobs"
+	"go-runner/internal/maintenance"
+	"go-runner/internal/mdns"
 	"go-runner/internal/middleware"
 	"go-runner/internal/project"
 	"go-runner/internal/service"
 	"go-runner/internal/system"
+	"go-runner/internal/tokens"
 	"go-runner/internal/websocket"
 
 	"gorm.io/gorm"
@@ -15,7 +28,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(r *gin.Engine, db *gorm.DB) {
+func SetupRouter(r *gin.Engine, db *gorm.DB, cfg *config.Config, monitor *system.Service) {
 	// Global middleware
 	r.Use(middleware.Logger())
 	r.Use(middleware.Recovery())
@@ -28,61 +41,335 @@ func SetupRouter(r *gin.Engine, db *gorm.DB) {
 
 	// Initialize service manager and websocket hub
 	manager := service.NewManager(db)
+	manager.SetTunnelOptions(service.TunnelOptions{
+		Provider:     cfg.Tunnel.Provider,
+		RelayHost:    cfg.Tunnel.RelayHost,
+		RelayPort:    cfg.Tunnel.RelayPort,
+		RelayURL:     cfg.Tunnel.RelayURL,
+		StartTimeout: time.Duration(cfg.Tunnel.StartTimeout) * time.Second,
+	})
+	manager.SetKubeOptions(service.KubeOptions{
+		Enabled:    cfg.Kubernetes.Enabled,
+		Kubectl:    cfg.Kubernetes.Kubectl,
+		Kubeconfig: cfg.Kubernetes.Kubeconfig,
+	})
+	manager.SetSSHOptions(service.SSHOptions{
+		Binary:         cfg.SSH.Binary,
+		IdentityFile:   cfg.SSH.IdentityFile,
+		KnownHostsFile: cfg.SSH.KnownHostsFile,
+		HostKeyCheck:   cfg.SSH.HostKeyCheck,
+		User:           cfg.SSH.User,
+		ConnectTimeout: time.Duration(cfg.SSH.ConnectTimeout) * time.Second,
+		StateDir:       cfg.SSH.StateDir,
+	})
+	manager.SetNodeOptions(service.NodeOptions{
+		WG:               cfg.Nodes.WG,
+		HandshakeTimeout: time.Duration(cfg.Nodes.HandshakeTimeout) * time.Second,
+		Failures:         cfg.Nodes.Failures,
+	})
+	manager.SetLogOptions(service.LogOptions{
+		Dir:         cfg.ServiceLogs.Dir,
+		MaxSize:     int64(cfg.ServiceLogs.MaxSize) << 20,
+		MaxLineSize: cfg.ServiceLogs.MaxLineSize << 10,
+	})
+	var archiver *archive.Archiver
+	if cfg.LogArchive.Enabled {
+		var err error
+		archiver, err = archive.New(db, archive.Options{
+			Store: archive.NewStore(archive.Store{
+				Endpoint:  cfg.LogArchive.Endpoint,
+				Region:    cfg.LogArchive.Region,
+				Bucket:    cfg.LogArchive.Bucket,
+				AccessKey: cfg.LogArchive.AccessKey,
+				SecretKey: cfg.LogArchive.SecretKey,
+				PathStyle: cfg.LogArchive.PathStyle,
+			}),
+			Prefix:        cfg.LogArchive.Prefix,
+			SpoolDir:      cfg.LogArchive.SpoolDir,
+			Interval:      time.Duration(cfg.LogArchive.Interval) * time.Minute,
+			Retention:     time.Duration(cfg.LogArchive.RetentionDays) * 24 * time.Hour,
+			MaxPerProject: cfg.LogArchive.MaxPerProject,
+		})
+		if err != nil {
+			log.Printf("⚠️  Log archiving disabled: %v", err)
+		} else {
+			manager.SetLogRotationListener(archiver.Rotated)
+		}
+	}
+	detector := system.NewDetector()
+	manager.SetStartOptions(service.StartOptions{
+		MaxConcurrent: cfg.StartQueue.MaxConcurrent,
+		CPUThreshold:  cfg.StartQueue.CPUThreshold,
+		Settle:        time.Duration(cfg.StartQueue.SettleTime) * time.Second,
+		MaxWait:       time.Duration(cfg.StartQueue.MaxWait) * time.Second,
+		CPULoad: func() (float64, error) {
+			return detector.CPUUsage(200 * time.Millisecond)
+		},
+	})
+	manager.SetPowerOptions(service.PowerOptions{
+		Mode:           cfg.Power.Mode,
+		IntervalFactor: cfg.Power.IntervalFactor,
+		OnBattery: func() (bool, error) {
+			source, err := system.ReadPowerSource()
+			if err != nil {
+				return false, err
+			}
+			return source.OnBattery, nil
+		},
+	})
+	monitor.SetIntervalScale(manager.PowerIntervalFactor)
+	manager.SetIdleOptions(service.IdleOptions{
+		WakeTimeout: time.Duration(cfg.Idle.WakeTimeout) * time.Second,
+	})
+	manager.TrackDatabaseWrites()
 	hub := websocket.NewHub()
 	
 	// Start websocket hub in goroutine
 	go hub.Run()
 
-	// Health check endpoint
-	// @Summary      Health check
-	// @Description  Check if the service is running
-	// @Tags         health
-	// @Accept       json
-	// @Produce      json
-	// @Success      200  {object}  map[string]interface{}  "Service status"
-	// @Router       /health [get]
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-			"service": "go-runner",
-			"version": "1.0.0",
+	// Lifecycle, alert, config and job events go to the hub and the other sinks
+	bus := newEventBus(db, hub, cfg.Events)
+	bus.SetProjectInfo(project.CatalogInfo(db))
+	monitor.SetEvents(bus)
+	annotator := project.NewAnnotator(db, manager, bus)
+	memoryGuard := project.NewMemoryGuard(db, manager, bus, annotator)
+	smokeTester := project.NewSmokeTester(db, manager, bus)
+	manager.SetStatusListener(func(change service.StatusChange) {
+		bus.Publish(change.ProjectID, "status_changed", change)
+		// Starts on a new git revision are annotated as deploys; apart, as
+		// statuses are recorded under the manager lock
+		go annotator.StatusChanged(change)
+		go memoryGuard.StatusChanged(change)
+		go smokeTester.StatusChanged(change)
+	})
+
+	// Background jobs outlive the requests that start them
+	jobManager := jobs.NewManager(db, bus, jobs.Options{
+		Workers:    cfg.Jobs.Workers,
+		Retention:  time.Duration(cfg.Jobs.Retention) * time.Hour,
+		MaxHistory: cfg.Jobs.MaxHistory,
+	})
+
+	// Re-attach to services that outlived the previous server, then start
+	// projects flagged with autostart
+	go func() {
+		runReconcile(manager, bus)
+		runAutostart(manager, bus)
+	}()
+
+	// Check database projects and broadcast health changes
+	go manager.MonitorDatabases(30*time.Second, func(projectID uint, stats *service.DatabaseStats) {
+		bus.Publish(projectID, "database_health", stats)
+	})
+
+	// Poll queue projects for depth metrics and backlog alerts
+	go manager.MonitorQueues(30*time.Second, project.NewQueueRecorder(db, bus).Record)
+
+	// Count open files of running projects against their ulimit
+	go manager.MonitorFileDescriptors(30*time.Second, project.NewFileDescriptorRecorder(db, bus).Record)
+
+	// Enter low-power mode on battery (power.mode)
+	go manager.MonitorPower(30*time.Second, func(status service.PowerStatus) {
+		bus.Publish(0, "power_mode", status)
+	})
+
+	// Stop projects past their idle_timeout, the proxy starts them again
+	go manager.MonitorIdle(time.Duration(cfg.Idle.CheckInterval)*time.Second, func(projectID uint, idle time.Duration) {
+		bus.Publish(projectID, "idle_stop", gin.H{"idle_seconds": int(idle.Seconds())})
+	})
+
+	// Sample the CPU and memory of running projects, and check them against
+	// their memory guard
+	processMetrics := project.NewProcessMetricRecorder(db)
+	go manager.MonitorProcessStats(30*time.Second, func(sample service.ProcessSample) {
+		processMetrics.Record(sample)
+		memoryGuard.Record(sample)
+	})
+
+	// Check the synthetic monitors of projects at their interval
+	go project.NewMonitorScheduler(db, manager, bus).Run(5 * time.Second)
+
+	// Store the traffic proxied to projects every minute
+	go manager.FlushTraffic(time.Minute, project.NewTrafficRecorder(db, bus).Record)
+
+	// Poll the CI pipelines of project repositories (ci.enabled)
+	ciMonitor := ci.NewMonitor(db, bus, ci.Options{
+		Enabled:       cfg.CI.Enabled,
+		GitHubToken:   cfg.CI.GitHubToken,
+		GitHubAPIURL:  cfg.CI.GitHubAPIURL,
+		GitLabToken:   cfg.CI.GitLabToken,
+		GitLabURL:     cfg.CI.GitLabURL,
+		PollInterval:  time.Duration(cfg.CI.PollInterval) * time.Second,
+		WebhookSecret: cfg.CI.WebhookSecret,
+	})
+	go ciMonitor.Run()
+
+	// Keep service output files under service_logs.max_size
+	go manager.RotateServiceLogs(time.Minute)
+
+	// Upload rotated output files and apply the archive retention
+	if archiver != nil {
+		go archiver.Run()
+	}
+
+	// Flag zombie processes and service processes left behind
+	go manager.MonitorOrphans(time.Minute, orphanReporter(bus))
+
+	// Sync Kubernetes projects with their pods
+	if cfg.Kubernetes.Enabled {
+		go manager.MonitorKubernetes(time.Duration(cfg.Kubernetes.PollInterval)*time.Second, func(projectID uint, deployment *service.KubeDeployment) {
+			bus.Publish(projectID, "kubernetes_status", deployment)
 		})
+	}
+
+	// Sync systemd projects with their units
+	go manager.MonitorSystemd(15*time.Second, func(projectID uint, unit *service.SystemdUnit) {
+		bus.Publish(projectID, "systemd_status", unit)
+	})
+
+	// Sync remote projects with their process on the SSH host
+	go manager.MonitorSSH(time.Duration(cfg.SSH.PollInterval)*time.Second, func(projectID uint, remote *service.RemoteProcess) {
+		bus.Publish(projectID, "ssh_status", remote)
 	})
+	// And track their hosts, holding their status while a host is unreachable
+	go manager.MonitorNodes(time.Duration(cfg.Nodes.PollInterval)*time.Second, func(node service.Node) {
+		bus.Publish(0, "node_status", node)
+	})
+
+	// Record file changes of projects with watch_files
+	go manager.WatchProjectFiles(10*time.Second, func(projectID uint, change service.FileChange) {
+		bus.Publish(projectID, "file_change", change)
+	})
+
+	// Announce opted-in running projects on the LAN
+	var responder *mdns.Responder
+	if cfg.MDNS.Enabled {
+		responder = startMDNS(db)
+	}
+
+	// Health check endpoint
+	r.GET("/health", healthCheck)
+
+	// Public status page
+	if cfg.StatusPage.Enabled {
+		project.RegisterStatusPage(r, db, cfg.StatusPage.Title)
+	}
+
+	// CI webhooks check their own secret rather than API tokens
+	ci.RegisterWebhooks(r, ciMonitor)
 
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// API routes
 	api := r.Group("/api/v1")
+	api.Use(tokens.Authenticate(db, cfg.Auth.AdminToken))
 	{
 		// Project routes
-		project.RegisterRoutes(api, db, manager, hub)
+		project.RegisterRoutes(api, db, manager, hub, bus, jobManager)
+
+		// Background job routes
+		jobs.RegisterRoutes(api, jobManager)
+
+		// Audit log and event sink routes
+		events.RegisterRoutes(api, db, bus)
+
+		// Maintenance window routes
+		maintenance.RegisterRoutes(api, db)
+
+		// Project API token routes
+		tokens.RegisterRoutes(api, db)
+
+		// CI pipeline status routes
+		ci.RegisterRoutes(api, ciMonitor)
+
+		// Saved dashboard routes
+		dashboard.RegisterRoutes(api, db)
+
+		// Log archive routes
+		archive.RegisterRoutes(api, db, archiver)
 		
 		// System monitoring routes
-		system.RegisterRoutes(api, db)
+		system.RegisterRoutes(api, db, jobManager, bus)
+
+		// mDNS announcements
+		api.GET("/mdns", mdnsStatus(responder))
+
+		// Action registry, running the routes above
+		actions.RegisterRoutes(api, r)
 	}
 
 	// Root endpoint
-	// @Summary      API Information
-	// @Description  Get API information and available features
-	// @Tags         info
-	// @Accept       json
-	// @Produce      json
-	// @Success      200  {object}  map[string]interface{}  "API information"
-	// @Router       / [get]
-	r.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "Welcome to Go Runner API - Microservice Management",
-			"version": "1.0.0",
-			"docs": "/swagger/index.html",
-			"api": "/api/v1",
-			"features": []string{
-				"Microservice Management",
-				"Real-time Logs",
-				"Project Groups",
-				"Health Monitoring",
-				"WebSocket Support",
-			},
-		})
+	r.GET("/", apiInfo)
+}
+
+// orphanReporter logs zombie and orphaned processes when their number
+// changes and broadcasts each scan as an "orphans" message
+func orphanReporter(bus *events.Bus) func(report *service.OrphanReport) {
+	var zombies, orphans int
+	return func(report *service.OrphanReport) {
+		if len(report.Zombies) != zombies || len(report.Orphans) != orphans {
+			zombies, orphans = len(report.Zombies), len(report.Orphans)
+			if zombies > 0 || orphans > 0 {
+				log.Printf("⚠️  Found %d zombie and %d orphaned processes, see GET /api/v1/system/orphans", zombies, orphans)
+			}
+		}
+		bus.Publish(0, "orphans", report)
+	}
+}
+
+// healthCheck godoc
+// @Summary      Health check
+// @Description  Check if the service is running
+// @Tags         health
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  HealthResponse  "Service status"
+// @Router       /health [get]
+func healthCheck(c *gin.Context) {
+	c.JSON(200, HealthResponse{
+		Status:  "ok",
+		Service: "go-runner",
+		Version: "1.0.0",
+	})
+}
+
+// apiInfo godoc
+// @Summary      API Information
+// @Description  Get API information and available features
+// @Tags         info
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  APIInfoResponse  "API information"
+// @Router       / [get]
+func apiInfo(c *gin.Context) {
+	c.JSON(200, APIInfoResponse{
+		Message: "Welcome to Go Runner API - Microservice Management",
+		Version: "1.0.0",
+		Docs:    "/swagger/index.html",
+		API:     "/api/v1",
+		Features: []string{
+			"Microservice Management",
+			"Real-time Logs",
+			"Project Groups",
+			"Health Monitoring",
+			"WebSocket Support",
+		},
 	})
 }
+
+// HealthResponse represents the health check payload
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+	Version string `json:"version"`
+}
+
+// APIInfoResponse represents the root endpoint payload
+type APIInfoResponse struct {
+	Message  string   `json:"message"`
+	Version  string   `json:"version"`
+	Docs     string   `json:"docs"`
+	API      string   `json:"api"`
+	Features []string `json:"features"`
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
@@ -17,7 +16,16 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// StartServer runs the server until SIGINT or SIGTERM is received
 func StartServer() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	Run(ctx)
+}
+
+// Run runs the server until ctx is cancelled, then shuts it down gracefully
+func Run(ctx context.Context) {
 	cfg := config.Load()
 	
 	// Set Gin mode
@@ -27,11 +35,11 @@ func StartServer() {
 	database := db.InitDB(cfg)
 
 	// Initialize system monitoring service
-	_ = system.NewService(database)
+	monitor := system.NewService(database)
 
 	// Setup router
 	r := gin.Default()
-	SetupRouter(r, database)
+	SetupRouter(r, database, cfg, monitor)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -49,17 +57,15 @@ func StartServer() {
 		}
 	}()
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Wait for the context to be cancelled (signal or service stop request)
+	<-ctx.Done()
 	log.Println("🛑 Shutting down server...")
 
 	// Give outstanding requests 30 seconds to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
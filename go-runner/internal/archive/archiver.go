@@ -0,0 +1,268 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Batch sizes: archives uploaded per run and fetched per request
+const (
+	uploadBatch = 100
+	maxFetch    = 50
+)
+
+// Options configures the archiver
+type Options struct {
+	Store         *Store
+	Prefix        string        // Key prefix in the bucket
+	SpoolDir      string        // Local copies of rotated files until uploaded
+	Interval      time.Duration // Between upload runs
+	Retention     time.Duration // Age after which archives are deleted, 0 to keep them
+	MaxPerProject int           // Archives kept per project, 0 for no limit
+}
+
+// Archiver spools rotated output files and uploads them on a schedule
+type Archiver struct {
+	db   *gorm.DB
+	opts Options
+	mu   sync.Mutex // One run at a time
+}
+
+// Query selects the archives of a project
+type Query struct {
+	ProjectID uint
+	Stream    string    // stdout or stderr, both when empty
+	From      time.Time // Archives covering output after it, zero for no bound
+	To        time.Time // Archives covering output before it, zero for no bound
+}
+
+// New returns an archiver spooling to opts.SpoolDir
+func New(db *gorm.DB, opts Options) (*Archiver, error) {
+	if opts.Store == nil || opts.Store.Bucket == "" {
+		return nil, fmt.Errorf("no bucket configured")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 15 * time.Minute
+	}
+	if err := os.MkdirAll(opts.SpoolDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %v", err)
+	}
+	return &Archiver{db: db, opts: opts}, nil
+}
+
+// Rotated spools a rotated output file of a project; it is the rotation
+// listener of the service manager. The file is copied at once since the
+// next rotation overwrites it.
+func (a *Archiver) Rotated(projectID uint, stream, rotated string) {
+	now := time.Now()
+	spool := filepath.Join(a.opts.SpoolDir, fmt.Sprintf("project-%d.%s.%d.log", projectID, stream, now.UnixNano()))
+	size, err := copyFile(rotated, spool)
+	if err != nil {
+		log.Printf("⚠️  Failed to spool %s for archiving: %v", rotated, err)
+		os.Remove(spool)
+		return
+	}
+
+	archive := Archive{
+		ProjectID: projectID,
+		Stream:    stream,
+		RotatedAt: now,
+		Key:       path.Join(a.opts.Prefix, fmt.Sprintf("project-%d", projectID), stream, now.UTC().Format("2006/01/02/150405.000000000")+".log.gz"),
+		RawSize:   size,
+		Status:    StatusPending,
+		SpoolFile: spool,
+	}
+	var previous Archive
+	if a.db.Where("project_id = ? AND stream = ?", projectID, stream).Order("rotated_at desc").Take(&previous).Error == nil {
+		archive.StartedAt = &previous.RotatedAt
+	}
+	if err := a.db.Create(&archive).Error; err != nil {
+		log.Printf("⚠️  Failed to record log archive of project %d: %v", projectID, err)
+		os.Remove(spool)
+	}
+}
+
+// Run uploads spooled archives and applies the retention rules every
+// interval
+func (a *Archiver) Run() {
+	ticker := time.NewTicker(a.opts.Interval)
+	defer ticker.Stop()
+	for {
+		a.RunOnce(context.Background())
+		<-ticker.C
+	}
+}
+
+// RunOnce uploads the spooled archives and deletes the expired ones
+func (a *Archiver) RunOnce(ctx context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.upload(ctx)
+	a.prune(ctx)
+}
+
+// upload compresses and uploads pending archives, retrying failed ones
+func (a *Archiver) upload(ctx context.Context) {
+	var archives []Archive
+	a.db.Where("status IN ?", []string{StatusPending, StatusFailed}).Order("id").Limit(uploadBatch).Find(&archives)
+
+	for _, archive := range archives {
+		raw, err := os.ReadFile(archive.SpoolFile)
+		if err != nil {
+			// Nothing left to upload
+			log.Printf("⚠️  Log archive %d lost its spool file: %v", archive.ID, err)
+			a.db.Delete(&archive)
+			continue
+		}
+
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		zw.Write(raw)
+		zw.Close()
+
+		archive.Attempts++
+		if err := a.opts.Store.Put(ctx, archive.Key, compressed.Bytes(), "application/gzip"); err != nil {
+			archive.Status, archive.Error = StatusFailed, err.Error()
+			a.db.Save(&archive)
+			continue
+		}
+		uploadedAt := time.Now()
+		archive.Status, archive.Error = StatusUploaded, ""
+		archive.Size, archive.UploadedAt = int64(compressed.Len()), &uploadedAt
+		a.db.Save(&archive)
+		os.Remove(archive.SpoolFile)
+	}
+}
+
+// prune deletes archives past the retention period and over the limit per
+// project, from the bucket and the spool
+func (a *Archiver) prune(ctx context.Context) {
+	var expired []Archive
+	if a.opts.Retention > 0 {
+		a.db.Where("rotated_at < ?", time.Now().Add(-a.opts.Retention)).Find(&expired)
+	}
+	if a.opts.MaxPerProject > 0 {
+		var projectIDs []uint
+		a.db.Model(&Archive{}).Group("project_id").Having("COUNT(*) > ?", a.opts.MaxPerProject).Pluck("project_id", &projectIDs)
+		for _, id := range projectIDs {
+			var archives []Archive
+			a.db.Where("project_id = ?", id).Order("rotated_at desc").Find(&archives)
+			if len(archives) > a.opts.MaxPerProject {
+				expired = append(expired, archives[a.opts.MaxPerProject:]...)
+			}
+		}
+	}
+
+	deleted := 0
+	for _, archive := range expired {
+		if archive.Status == StatusUploaded {
+			if err := a.opts.Store.Delete(ctx, archive.Key); err != nil {
+				log.Printf("⚠️  Failed to delete log archive %s: %v", archive.Key, err)
+				continue
+			}
+		} else {
+			os.Remove(archive.SpoolFile)
+		}
+		if a.db.Delete(&archive).RowsAffected > 0 {
+			deleted++
+		}
+	}
+	if deleted > 0 {
+		log.Printf("🗑️  Deleted %d expired log archives", deleted)
+	}
+}
+
+// Find returns the archives of a project covering part of a time range,
+// oldest first
+func Find(db *gorm.DB, q Query) ([]Archive, error) {
+	tx := db.Where("project_id = ?", q.ProjectID)
+	if q.Stream != "" {
+		tx = tx.Where("stream = ?", q.Stream)
+	}
+	if !q.From.IsZero() {
+		tx = tx.Where("rotated_at >= ?", q.From)
+	}
+	if !q.To.IsZero() {
+		tx = tx.Where("(started_at IS NULL OR started_at <= ?)", q.To)
+	}
+	archives := []Archive{}
+	err := tx.Order("rotated_at, id").Find(&archives).Error
+	return archives, err
+}
+
+// Fetch writes the output archived for a time range, downloading and
+// decompressing uploaded archives and reading the spooled ones. At most
+// maxFetch archives are read; it returns the archives written.
+func (a *Archiver) Fetch(ctx context.Context, w io.Writer, q Query) ([]Archive, error) {
+	archives, err := Find(a.db, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) > maxFetch {
+		archives = archives[:maxFetch]
+	}
+
+	for i, archive := range archives {
+		if err := a.read(ctx, w, &archive); err != nil {
+			return archives[:i], fmt.Errorf("archive %d: %v", archive.ID, err)
+		}
+	}
+	return archives, nil
+}
+
+// read writes the output of an archive
+func (a *Archiver) read(ctx context.Context, w io.Writer, archive *Archive) error {
+	if archive.Status != StatusUploaded {
+		f, err := os.Open(archive.SpoolFile)
+		if err == nil {
+			defer f.Close()
+			_, err = io.Copy(w, f)
+			return err
+		}
+		// Uploaded since it was listed
+		if !os.IsNotExist(err) || a.db.First(archive, archive.ID).Error != nil || archive.Status != StatusUploaded {
+			return err
+		}
+	}
+
+	body, err := a.opts.Store.Get(ctx, archive.Key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, zr)
+	return err
+}
+
+// copyFile copies src to a new file dst and returns the bytes copied
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
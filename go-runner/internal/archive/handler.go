@@ -0,0 +1,137 @@
+package archive
+
+import (
+	"bufio"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler serves the log archive API
+type Handler struct {
+	db       *gorm.DB
+	archiver *Archiver // nil when log archiving is disabled
+}
+
+// RegisterRoutes registers the log archive routes; archiver is nil when
+// archiving is disabled
+func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB, archiver *Archiver) {
+	h := &Handler{db: db, archiver: archiver}
+
+	r.GET("/projects/:id/logs/archives", h.GetArchives)
+	r.GET("/projects/:id/logs/archives/fetch", h.FetchArchives)
+}
+
+// GetArchives godoc
+// @Summary      List the log archives of a project
+// @Description  List the rotated output files of a project archived to S3-compatible storage, oldest first. An archive covers the output written between started_at (the previous rotation of its stream) and rotated_at; pending and failed archives are still spooled locally and retried on the next upload run.
+// @Tags         logs
+// @Produce      json
+// @Param        id      path      int     true   "Project ID"
+// @Param        stream  query     string  false  "stdout or stderr"
+// @Param        from    query     string  false  "Archives covering output after this time (RFC 3339)"
+// @Param        to      query     string  false  "Archives covering output before this time (RFC 3339)"
+// @Success      200     {object}  types.DataResponse{data=[]Archive}
+// @Failure      400     {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404     {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/logs/archives [get]
+func (h *Handler) GetArchives(c *gin.Context) {
+	q, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	archives, err := Find(h.db, q)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch log archives", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: archives})
+}
+
+// FetchArchives godoc
+// @Summary      Fetch archived logs of a project
+// @Description  Download, decompress and concatenate the archives covering a time range, oldest first, as plain text. Archives not uploaded yet are read from the local spool. At most 50 archives are read; X-Archive-Count holds the number read and X-Archive-Truncated is set when more archives match.
+// @Tags         logs
+// @Produce      plain
+// @Param        id      path      int     true   "Project ID"
+// @Param        stream  query     string  false  "stdout or stderr"
+// @Param        from    query     string  false  "Output after this time (RFC 3339)"
+// @Param        to      query     string  false  "Output before this time (RFC 3339)"
+// @Success      200     {string}  string  "Archived output"
+// @Failure      400     {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404     {object}  middleware.ErrorResponse  "Project not found or no archive in range"
+// @Failure      503     {object}  middleware.ErrorResponse  "Log archiving is disabled"
+// @Router       /projects/{id}/logs/archives/fetch [get]
+func (h *Handler) FetchArchives(c *gin.Context) {
+	if h.archiver == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusServiceUnavailable, "Log archiving is disabled", "set log_archive.enabled"))
+		return
+	}
+	q, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	archives, err := Find(h.db, q)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch log archives", err.Error()))
+		return
+	}
+	if len(archives) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "No log archive in range", nil))
+		return
+	}
+
+	// Errors past the headers can only cut the output short
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("X-Archive-Count", strconv.Itoa(min(len(archives), maxFetch)))
+	if len(archives) > maxFetch {
+		c.Header("X-Archive-Truncated", "true")
+	}
+	c.Status(http.StatusOK)
+	w := bufio.NewWriter(c.Writer)
+	if _, err := h.archiver.Fetch(c.Request.Context(), w, q); err != nil {
+		log.Printf("⚠️  Failed to fetch log archives of project %d: %v", q.ProjectID, err)
+	}
+	w.Flush()
+}
+
+// parseQuery reads the project and the filters of an archive request
+func (h *Handler) parseQuery(c *gin.Context) (Query, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return Query{}, false
+	}
+	var count int64
+	h.db.Table(tables.Name("projects")).Where("id = ? AND deleted_at IS NULL", id).Count(&count)
+	if count == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Project not found", nil))
+		return Query{}, false
+	}
+
+	q := Query{ProjectID: uint(id), Stream: c.Query("stream")}
+	if q.Stream != "" && q.Stream != "stdout" && q.Stream != "stderr" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid stream", "expected stdout or stderr"))
+		return Query{}, false
+	}
+	for name, t := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if raw := c.Query(name); raw != "" {
+			if *t, err = time.Parse(time.RFC3339, raw); err != nil {
+				middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid "+name, err.Error()))
+				return Query{}, false
+			}
+		}
+	}
+	return q, true
+}
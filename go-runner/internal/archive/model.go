@@ -0,0 +1,40 @@
+// Package archive compresses the rotated output files of projects and
+// uploads them to S3-compatible storage, so logs outlive the local rotation
+package archive
+
+import (
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// Archive states: spooled locally until the next upload run
+const (
+	StatusPending  = "pending"
+	StatusUploaded = "uploaded"
+	StatusFailed   = "failed" // Retried on every run
+)
+
+// Archive is one rotated output file of a project, covering the output
+// written from the previous rotation of its stream to this one
+type Archive struct {
+	ID         uint       `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ProjectID  uint       `json:"project_id" gorm:"index"`
+	Stream     string     `json:"stream"`                  // stdout or stderr
+	StartedAt  *time.Time `json:"started_at,omitempty"`    // Previous rotation, unknown for the first archive of a stream
+	RotatedAt  time.Time  `json:"rotated_at" gorm:"index"` // End of the output covered
+	Key        string     `json:"key"`                     // Object key in the bucket
+	RawSize    int64      `json:"raw_size"`                // Bytes of the rotated file
+	Size       int64      `json:"size"`                    // Bytes uploaded, after compression
+	Status     string     `json:"status" gorm:"index"`     // pending, uploaded or failed
+	Error      string     `json:"error,omitempty"`         // Last upload error
+	Attempts   int        `json:"attempts"`                // Upload attempts
+	UploadedAt *time.Time `json:"uploaded_at,omitempty"`
+	SpoolFile  string     `json:"-"` // Local copy until uploaded
+}
+
+// TableName keeps archives apart from other tables
+func (Archive) TableName() string {
+	return tables.Name("log_archives")
+}
@@ -0,0 +1,170 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// emptyHash is the SHA-256 of an empty payload
+const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Store is an S3-compatible bucket: AWS S3, MinIO, Ceph, R2 or Google Cloud
+// Storage through its XML API with HMAC keys. Requests are signed with AWS
+// Signature Version 4.
+type Store struct {
+	Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com or https://storage.googleapis.com
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // endpoint/bucket/key instead of bucket.endpoint/key
+
+	client *http.Client
+}
+
+// NewStore returns a store, defaulting to the AWS endpoint of the region
+func NewStore(s Store) *Store {
+	if s.Region == "" {
+		s.Region = "us-east-1"
+	}
+	if s.Endpoint == "" {
+		s.Endpoint = "https://s3." + s.Region + ".amazonaws.com"
+	}
+	s.Endpoint = strings.TrimSuffix(s.Endpoint, "/")
+	s.client = &http.Client{Timeout: 5 * time.Minute}
+	return &s
+}
+
+// Put uploads an object
+func (s *Store) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, body, map[string]string{"Content-Type": contentType})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object; the caller closes the body
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object; removing a missing object is not an error
+func (s *Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a signed request for an object, failing on a non-2xx response
+func (s *Store) do(ctx context.Context, method, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", s.Endpoint, err)
+	}
+	path := "/" + strings.TrimPrefix(key, "/")
+	if s.PathStyle {
+		path = "/" + s.Bucket + path
+	} else {
+		endpoint.Host = s.Bucket + "." + endpoint.Host
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + path
+	endpoint.RawPath = escapePath(endpoint.Path)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s %s: %s %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// sign adds the Signature Version 4 headers of a request
+func (s *Store) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := emptyHash
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath encodes a path as S3 expects in signatures: every byte but the
+// unreserved characters and slashes
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
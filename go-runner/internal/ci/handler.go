@@ -0,0 +1,216 @@
+package ci
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler serves the CI status API and webhooks
+type Handler struct {
+	monitor *Monitor
+}
+
+// RegisterRoutes registers the CI status routes
+func RegisterRoutes(r *gin.RouterGroup, monitor *Monitor) {
+	h := &Handler{monitor: monitor}
+	r.GET("/projects/:id/ci", h.GetStatus)
+}
+
+// RegisterWebhooks registers the webhook receivers outside the API, as
+// providers cannot send API tokens; they check the webhook secret instead
+func RegisterWebhooks(r *gin.Engine, monitor *Monitor) {
+	h := &Handler{monitor: monitor}
+	r.POST("/webhooks/github", h.GitHubWebhook)
+	r.POST("/webhooks/gitlab", h.GitLabWebhook)
+}
+
+// WebhookResult lists the projects a webhook updated
+type WebhookResult struct {
+	Updated []uint `json:"updated"`
+}
+
+// GetStatus godoc
+// @Summary      Get the CI status of a project
+// @Description  Get the latest pipeline of the repository of a project on its branch, from GitHub Actions or GitLab CI: ci_repo (github:owner/repo or gitlab:group/project) or the origin remote of its checkout, and ci_branch or the checked out branch. Statuses are polled every ci.poll_interval seconds and pushed by webhooks (/webhooks/github, /webhooks/gitlab); refresh=true fetches it now. Also included as "ci" in the project status.
+// @Tags         projects
+// @Produce      json
+// @Param        id       path      int   true   "Project ID"
+// @Param        refresh  query     bool  false  "Fetch the latest pipeline now"
+// @Success      200      {object}  types.DataResponse{data=Status}
+// @Failure      400      {object}  middleware.ErrorResponse  "No CI repository for the project"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found or not checked yet"
+// @Failure      502      {object}  middleware.ErrorResponse  "Provider request failed"
+// @Router       /projects/{id}/ci [get]
+func (h *Handler) GetStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	if refresh, _ := strconv.ParseBool(c.Query("refresh")); refresh {
+		status, err := h.monitor.Refresh(c.Request.Context(), uint(id))
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			middleware.HandleError(c, middleware.ErrNotFound)
+		case errors.Is(err, ErrNoRepo) || (err != nil && status == nil):
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, err.Error(), nil))
+		case err != nil:
+			middleware.HandleError(c, middleware.NewError(http.StatusBadGateway, "Failed to fetch the pipeline", err.Error()))
+		default:
+			c.JSON(http.StatusOK, types.DataResponse{Data: status})
+		}
+		return
+	}
+
+	status := Latest(h.monitor.db, uint(id))
+	if status == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "CI status not checked yet", "Use refresh=true"))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: status})
+}
+
+// GitHubWebhook godoc
+// @Summary      Receive GitHub workflow runs
+// @Description  Webhook for the workflow_run events of a GitHub repository, signed with ci.webhook_secret (X-Hub-Signature-256). Updates the CI status of the projects of the repository and branch of the run. Served at /webhooks/github, outside the API base path.
+// @Tags         ci
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=WebhookResult}
+// @Failure      401  {object}  middleware.ErrorResponse  "Invalid signature"
+// @Failure      403  {object}  middleware.ErrorResponse  "Webhooks disabled"
+// @Router       /webhooks/github [post]
+func (h *Handler) GitHubWebhook(c *gin.Context) {
+	body, ok := h.readWebhook(c)
+	if !ok {
+		return
+	}
+	mac := hmac.New(sha256.New, []byte(h.monitor.opts.WebhookSecret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(c.GetHeader("X-Hub-Signature-256")), []byte(expected)) {
+		middleware.HandleError(c, middleware.NewError(http.StatusUnauthorized, "Invalid signature", nil))
+		return
+	}
+	if c.GetHeader("X-GitHub-Event") != "workflow_run" {
+		c.JSON(http.StatusOK, types.DataResponse{Data: WebhookResult{Updated: []uint{}}})
+		return
+	}
+
+	var event struct {
+		WorkflowRun githubRun `json:"workflow_run"`
+		Repository  struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil || event.Repository.FullName == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid workflow_run event", errorDetails(err)))
+		return
+	}
+	repo := Repo{Provider: ProviderGitHub, Path: event.Repository.FullName}
+	updated := h.monitor.receive(repo, event.WorkflowRun.HeadBranch, event.WorkflowRun.status())
+	c.JSON(http.StatusOK, types.DataResponse{Data: WebhookResult{Updated: updated}})
+}
+
+// GitLabWebhook godoc
+// @Summary      Receive GitLab pipelines
+// @Description  Webhook for the pipeline events of a GitLab project, with ci.webhook_secret as its secret token (X-Gitlab-Token). Updates the CI status of the projects of the repository and branch of the pipeline. Served at /webhooks/gitlab, outside the API base path.
+// @Tags         ci
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=WebhookResult}
+// @Failure      401  {object}  middleware.ErrorResponse  "Invalid token"
+// @Failure      403  {object}  middleware.ErrorResponse  "Webhooks disabled"
+// @Router       /webhooks/gitlab [post]
+func (h *Handler) GitLabWebhook(c *gin.Context) {
+	body, ok := h.readWebhook(c)
+	if !ok {
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Gitlab-Token")), []byte(h.monitor.opts.WebhookSecret)) != 1 {
+		middleware.HandleError(c, middleware.NewError(http.StatusUnauthorized, "Invalid token", nil))
+		return
+	}
+
+	var event struct {
+		ObjectKind       string `json:"object_kind"`
+		ObjectAttributes struct {
+			ID         int64  `json:"id"`
+			Name       string `json:"name"`
+			Ref        string `json:"ref"`
+			SHA        string `json:"sha"`
+			Status     string `json:"status"`
+			FinishedAt string `json:"finished_at"`
+		} `json:"object_attributes"`
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+			WebURL            string `json:"web_url"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid event", err.Error()))
+		return
+	}
+	if event.ObjectKind != "pipeline" || event.Project.PathWithNamespace == "" {
+		c.JSON(http.StatusOK, types.DataResponse{Data: WebhookResult{Updated: []uint{}}})
+		return
+	}
+
+	attrs := event.ObjectAttributes
+	pipeline := gitlabPipeline{
+		ID:     attrs.ID,
+		Name:   attrs.Name,
+		Status: attrs.Status,
+		Ref:    attrs.Ref,
+		SHA:    attrs.SHA,
+		WebURL: fmt.Sprintf("%s/-/pipelines/%d", strings.TrimRight(event.Project.WebURL, "/"), attrs.ID),
+	}
+	// Webhooks send "2024-01-02 15:04:05 UTC"; the last change is when it
+	// finished, or now while it runs
+	pipeline.UpdatedAt = time.Now()
+	if finished, err := time.Parse("2006-01-02 15:04:05 MST", attrs.FinishedAt); err == nil {
+		pipeline.UpdatedAt = finished
+	}
+	repo := Repo{Provider: ProviderGitLab, Path: event.Project.PathWithNamespace}
+	updated := h.monitor.receive(repo, attrs.Ref, pipeline.status())
+	c.JSON(http.StatusOK, types.DataResponse{Data: WebhookResult{Updated: updated}})
+}
+
+// readWebhook reads the body of a webhook, refusing it unless webhooks are
+// enabled with a secret
+func (h *Handler) readWebhook(c *gin.Context) ([]byte, bool) {
+	if !h.monitor.opts.Enabled || h.monitor.opts.WebhookSecret == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusForbidden, "CI webhooks disabled", "Set ci.enabled and ci.webhook_secret"))
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 5<<20))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Failed to read body", err.Error()))
+		return nil, false
+	}
+	return body, true
+}
+
+func errorDetails(err error) interface{} {
+	if err != nil {
+		return err.Error()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Package ci shows the status of the latest CI pipeline of the repository of
+// each project, from GitHub Actions or GitLab CI, polled or pushed by
+// webhooks
+package ci
+
+import (
+	"time"
+
+	"go-runner/internal/tables"
+
+	"gorm.io/gorm"
+)
+
+// Pipeline states, common to the providers
+const (
+	StateSuccess   = "success"
+	StateFailure   = "failure"
+	StateRunning   = "running"
+	StatePending   = "pending" // Queued, waiting or manual
+	StateCancelled = "cancelled"
+	StateSkipped   = "skipped"
+	StateNone      = "none" // No pipeline ran on the branch
+	StateUnknown   = "unknown"
+)
+
+// Status is the latest pipeline of the repository and branch of a project
+type Status struct {
+	ID        uint       `json:"-" gorm:"primarykey"`
+	ProjectID uint       `json:"project_id" gorm:"uniqueIndex"`
+	Provider  string     `json:"provider"` // github, gitlab
+	Repo      string     `json:"repo"`     // owner/repo or group/project
+	Branch    string     `json:"branch"`   // Every branch when empty
+	State     string     `json:"state"`    // success, failure, running, pending, cancelled, skipped, none, unknown
+	Name      string     `json:"name"`     // Workflow or pipeline name
+	Commit    string     `json:"commit"`
+	URL       string     `json:"url"`
+	UpdatedAt *time.Time `json:"updated_at"` // Of the pipeline
+	CheckedAt time.Time  `json:"checked_at"`
+	Source    string     `json:"source"`          // poll, webhook
+	Error     string     `json:"error,omitempty"` // Last failed check; the state is from the check before
+}
+
+// TableName keeps CI statuses apart from other tables
+func (Status) TableName() string {
+	return tables.Name("ci_statuses")
+}
+
+// Latest returns the stored pipeline status of a project, or nil
+func Latest(db *gorm.DB, projectID uint) *Status {
+	var status Status
+	if err := db.Where("project_id = ?", projectID).Take(&status).Error; err != nil {
+		return nil
+	}
+	return &status
+}
+
+// changed reports whether a new status is worth an event: another
+// pipeline, or another state
+func (s *Status) changed(previous *Status) bool {
+	return previous == nil || previous.State != s.State || previous.URL != s.URL || previous.Commit != s.Commit
+}
@@ -0,0 +1,203 @@
+package ci
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/service"
+	"go-runner/internal/tables"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoRepo is returned for projects whose repository is not known
+var ErrNoRepo = errors.New("no CI repository: set ci_repo, or add a GitHub or GitLab origin remote")
+
+// Monitor keeps the pipeline status of projects up to date
+type Monitor struct {
+	db     *gorm.DB
+	bus    *events.Bus
+	opts   Options
+	client *http.Client
+}
+
+// NewMonitor creates a CI monitor
+func NewMonitor(db *gorm.DB, bus *events.Bus, opts Options) *Monitor {
+	if opts.GitHubAPIURL == "" {
+		opts.GitHubAPIURL = "https://api.github.com"
+	}
+	if opts.GitLabURL == "" {
+		opts.GitLabURL = "https://gitlab.com"
+	}
+	return &Monitor{db: db, bus: bus, opts: opts, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// target is the repository and branch of a project
+type target struct {
+	projectID uint
+	repo      Repo
+	branch    string
+}
+
+// resolve returns the repository and branch of a project, from ci_repo and
+// ci_branch or from its checkout
+func (m *Monitor) resolve(p *projectRow) (target, error) {
+	t := target{projectID: p.ID, branch: p.CIBranch}
+	dir := p.Path
+	if p.WorkingDir != "" {
+		dir = p.WorkingDir
+	}
+	local := p.SSHHost == ""
+
+	if p.CIRepo != "" {
+		repo, err := ParseRepo(p.CIRepo)
+		if err != nil {
+			return t, err
+		}
+		t.repo = repo
+	} else if remote := service.GitRemoteURL(dir, "origin"); local && remote != "" {
+		repo, ok := m.opts.repoFromRemote(remote)
+		if !ok {
+			return t, ErrNoRepo
+		}
+		t.repo = repo
+	} else {
+		return t, ErrNoRepo
+	}
+	if t.branch == "" && local {
+		t.branch = service.GitBranch(dir)
+	}
+	return t, nil
+}
+
+type projectRow struct {
+	ID         uint
+	Path       string
+	WorkingDir string
+	SSHHost    string
+	CIRepo     string
+	CIBranch   string
+}
+
+func (m *Monitor) projects(query string, args ...interface{}) []projectRow {
+	var rows []projectRow
+	db := m.db.Table(tables.Name("projects")).Select("id, path, working_dir, ssh_host, ci_repo, ci_branch").Where("deleted_at IS NULL")
+	if query != "" {
+		db = db.Where(query, args...)
+	}
+	db.Find(&rows)
+	return rows
+}
+
+// Run polls the pipelines of every project with a repository every poll
+// interval; projects of the same repository and branch share one request
+func (m *Monitor) Run() {
+	if !m.opts.Enabled || m.opts.PollInterval <= 0 {
+		return
+	}
+	for {
+		fetched := make(map[string]*Status)
+		failed := make(map[string]error)
+		for _, p := range m.projects("") {
+			t, err := m.resolve(&p)
+			if err != nil {
+				continue
+			}
+			key := t.repo.String() + "@" + t.branch
+			status, seen := fetched[key]
+			err = failed[key]
+			if !seen && err == nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+				status, err = m.opts.fetch(ctx, m.client, t.repo, t.branch)
+				cancel()
+				if err != nil {
+					failed[key] = err
+				} else {
+					fetched[key] = status
+				}
+			}
+			m.save(t, status, err, "poll")
+		}
+		time.Sleep(m.opts.PollInterval)
+	}
+}
+
+// Refresh fetches the latest pipeline of a project now
+func (m *Monitor) Refresh(ctx context.Context, projectID uint) (*Status, error) {
+	rows := m.projects("id = ?", projectID)
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	t, err := m.resolve(&rows[0])
+	if err != nil {
+		return nil, err
+	}
+	status, err := m.opts.fetch(ctx, m.client, t.repo, t.branch)
+	return m.save(t, status, err, "poll"), err
+}
+
+// save stores the status of a project, keeping the previous state with the
+// error of a failed check, and publishes a "ci_status" event when the
+// pipeline or its state changed
+func (m *Monitor) save(t target, fetched *Status, fetchErr error, source string) *Status {
+	previous := Latest(m.db, t.projectID)
+	status := &Status{State: StateUnknown}
+	if fetchErr != nil {
+		if previous != nil {
+			copied := *previous
+			status = &copied
+		}
+		status.Error = fetchErr.Error()
+	} else {
+		copied := *fetched
+		status = &copied
+	}
+	if previous != nil {
+		status.ID = previous.ID
+	}
+	status.ProjectID = t.projectID
+	status.Provider = t.repo.Provider
+	status.Repo = t.repo.Path
+	status.Branch = t.branch
+	status.CheckedAt = time.Now()
+	if fetchErr == nil {
+		status.Source = source
+	}
+
+	if err := m.db.Save(status).Error; err != nil {
+		log.Printf("Failed to save CI status of project %d: %v", t.projectID, err)
+		return status
+	}
+	if fetchErr == nil && status.changed(previous) {
+		m.bus.Publish(t.projectID, "ci_status", status)
+	}
+	return status
+}
+
+// receive stores a pipeline pushed by a webhook for the projects of its
+// repository and branch, returning their IDs. Pipelines older than the
+// stored one are ignored, as webhooks can arrive out of order.
+func (m *Monitor) receive(repo Repo, branch string, pipeline *Status) []uint {
+	updated := []uint{}
+	for _, p := range m.projects("") {
+		t, err := m.resolve(&p)
+		if err != nil || t.repo.Provider != repo.Provider || !strings.EqualFold(t.repo.Path, repo.Path) {
+			continue
+		}
+		if t.branch != "" && t.branch != branch {
+			continue
+		}
+		if previous := Latest(m.db, p.ID); previous != nil && previous.UpdatedAt != nil && pipeline.UpdatedAt != nil &&
+			previous.Branch == t.branch && pipeline.UpdatedAt.Before(*previous.UpdatedAt) {
+			continue
+		}
+		m.save(t, pipeline, nil, "webhook")
+		updated = append(updated, p.ID)
+	}
+	return updated
+}
@@ -0,0 +1,257 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Providers
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// Options configure the providers
+type Options struct {
+	Enabled       bool
+	GitHubToken   string
+	GitHubAPIURL  string // https://api.github.com, or https://<host>/api/v3 for GitHub Enterprise
+	GitLabToken   string
+	GitLabURL     string // https://gitlab.com or a self-managed instance
+	PollInterval  time.Duration
+	WebhookSecret string
+}
+
+// Repo is a repository on a provider
+type Repo struct {
+	Provider string
+	Path     string // owner/repo or group/subgroup/project
+}
+
+// String returns the repository as written in ci_repo
+func (r Repo) String() string {
+	return r.Provider + ":" + r.Path
+}
+
+// ParseRepo parses a ci_repo value, github:owner/repo or
+// gitlab:group/project
+func ParseRepo(s string) (Repo, error) {
+	provider, path, ok := strings.Cut(strings.TrimSpace(s), ":")
+	path = strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+	if !ok || (provider != ProviderGitHub && provider != ProviderGitLab) || !strings.Contains(path, "/") {
+		return Repo{}, fmt.Errorf("invalid ci_repo %q (use github:owner/repo or gitlab:group/project)", s)
+	}
+	return Repo{Provider: provider, Path: path}, nil
+}
+
+// repoFromRemote recognizes a GitHub or GitLab repository from a git remote
+// URL, such as git@github.com:owner/repo.git or
+// https://gitlab.com/group/project
+func (o Options) repoFromRemote(remote string) (Repo, bool) {
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, "@"); ok && at != "" {
+		// scp-like syntax: user@host:path
+		host, path, _ = strings.Cut(rest, ":")
+	} else {
+		return Repo{}, false
+	}
+	path = strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+	if !strings.Contains(path, "/") {
+		return Repo{}, false
+	}
+
+	host = strings.ToLower(host)
+	switch host {
+	case "github.com", hostOf(o.GitHubAPIURL):
+		return Repo{Provider: ProviderGitHub, Path: path}, true
+	case "gitlab.com", hostOf(o.GitLabURL):
+		return Repo{Provider: ProviderGitLab, Path: path}, true
+	}
+	return Repo{}, false
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// fetch returns the latest pipeline of a repository, on branch when set
+func (o Options) fetch(ctx context.Context, client *http.Client, repo Repo, branch string) (*Status, error) {
+	switch repo.Provider {
+	case ProviderGitHub:
+		return o.fetchGitHub(ctx, client, repo, branch)
+	case ProviderGitLab:
+		return o.fetchGitLab(ctx, client, repo, branch)
+	}
+	return nil, fmt.Errorf("unknown provider %q", repo.Provider)
+}
+
+// githubRun is a workflow run of the GitHub API and workflow_run webhooks
+type githubRun struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	HeadSHA    string    `json:"head_sha"`
+	HeadBranch string    `json:"head_branch"`
+	HTMLURL    string    `json:"html_url"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (r githubRun) status() *Status {
+	updated := r.UpdatedAt
+	return &Status{State: githubState(r.Status, r.Conclusion), Name: r.Name, Commit: r.HeadSHA, URL: r.HTMLURL, UpdatedAt: &updated}
+}
+
+func (o Options) fetchGitHub(ctx context.Context, client *http.Client, repo Repo, branch string) (*Status, error) {
+	query := url.Values{"per_page": {"1"}}
+	if branch != "" {
+		query.Set("branch", branch)
+	}
+	endpoint := strings.TrimRight(o.GitHubAPIURL, "/") + "/repos/" + repo.Path + "/actions/runs?" + query.Encode()
+	header := http.Header{"Accept": {"application/vnd.github+json"}}
+	if o.GitHubToken != "" {
+		header.Set("Authorization", "Bearer "+o.GitHubToken)
+	}
+
+	var body struct {
+		WorkflowRuns []githubRun `json:"workflow_runs"`
+	}
+	if err := getJSON(ctx, client, endpoint, header, &body); err != nil {
+		return nil, err
+	}
+	if len(body.WorkflowRuns) == 0 {
+		return &Status{State: StateNone}, nil
+	}
+	return body.WorkflowRuns[0].status(), nil
+}
+
+// githubState maps the status and conclusion of a workflow run
+func githubState(status, conclusion string) string {
+	switch status {
+	case "in_progress":
+		return StateRunning
+	case "queued", "waiting", "pending", "requested":
+		return StatePending
+	case "completed":
+	default:
+		return StateUnknown
+	}
+	switch conclusion {
+	case "success":
+		return StateSuccess
+	case "failure", "timed_out", "startup_failure":
+		return StateFailure
+	case "cancelled":
+		return StateCancelled
+	case "skipped", "neutral":
+		return StateSkipped
+	case "action_required":
+		return StatePending
+	}
+	return StateUnknown
+}
+
+// gitlabPipeline is a pipeline of the GitLab API and pipeline webhooks
+type gitlabPipeline struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Ref       string    `json:"ref"`
+	SHA       string    `json:"sha"`
+	WebURL    string    `json:"web_url"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (p gitlabPipeline) status() *Status {
+	status := &Status{State: gitlabState(p.Status), Name: p.Name, Commit: p.SHA, URL: p.WebURL}
+	if !p.UpdatedAt.IsZero() {
+		updated := p.UpdatedAt
+		status.UpdatedAt = &updated
+	}
+	if status.Name == "" {
+		status.Name = fmt.Sprintf("Pipeline #%d", p.ID)
+	}
+	return status
+}
+
+func (o Options) fetchGitLab(ctx context.Context, client *http.Client, repo Repo, branch string) (*Status, error) {
+	query := url.Values{"per_page": {"1"}}
+	if branch != "" {
+		query.Set("ref", branch)
+	}
+	endpoint := strings.TrimRight(o.GitLabURL, "/") + "/api/v4/projects/" + url.PathEscape(repo.Path) + "/pipelines?" + query.Encode()
+	header := http.Header{}
+	if o.GitLabToken != "" {
+		header.Set("PRIVATE-TOKEN", o.GitLabToken)
+	}
+
+	var pipelines []gitlabPipeline
+	if err := getJSON(ctx, client, endpoint, header, &pipelines); err != nil {
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return &Status{State: StateNone}, nil
+	}
+	return pipelines[0].status(), nil
+}
+
+// gitlabState maps the status of a pipeline
+func gitlabState(status string) string {
+	switch status {
+	case "success":
+		return StateSuccess
+	case "failed":
+		return StateFailure
+	case "running":
+		return StateRunning
+	case "created", "pending", "waiting_for_resource", "preparing", "scheduled", "manual":
+		return StatePending
+	case "canceled", "canceling":
+		return StateCancelled
+	case "skipped":
+		return StateSkipped
+	}
+	return StateUnknown
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, header http.Header, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiError) == nil && apiError.Message != "" {
+			return fmt.Errorf("%s: %s", resp.Status, apiError.Message)
+		}
+		return errors.New(resp.Status)
+	}
+	return json.Unmarshal(body, v)
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -14,6 +16,21 @@ type Config struct {
 	Database DatabaseConfig `mapstructure:"database"`
 	Logging  LoggingConfig  `mapstructure:"logging"`
 	HotReload HotReloadConfig `mapstructure:"hot_reload"`
+	Jobs     JobsConfig     `mapstructure:"jobs"`
+	Tunnel   TunnelConfig   `mapstructure:"tunnel"`
+	MDNS     MDNSConfig     `mapstructure:"mdns"`
+	StatusPage StatusPageConfig `mapstructure:"status_page"`
+	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
+	SSH        SSHConfig        `mapstructure:"ssh"`
+	Nodes      NodesConfig      `mapstructure:"nodes"`
+	ServiceLogs ServiceLogsConfig `mapstructure:"service_logs"`
+	LogArchive LogArchiveConfig `mapstructure:"log_archive"`
+	StartQueue StartQueueConfig `mapstructure:"start_queue"`
+	Power      PowerConfig      `mapstructure:"power"`
+	Idle       IdleConfig       `mapstructure:"idle"`
+	Events     EventsConfig     `mapstructure:"events"`
+	Auth       AuthConfig       `mapstructure:"auth"`
+	CI         CIConfig         `mapstructure:"ci"`
 }
 
 type ServerConfig struct {
@@ -33,6 +50,42 @@ type DatabaseConfig struct {
 	DBName   string `mapstructure:"dbname"`
 	SSLMode  string `mapstructure:"sslmode"`
 	Path     string `mapstructure:"path"` // For SQLite
+
+	// Sharing a database: every table is named with TablePrefix. Workspace
+	// gives SQLite a file of its own, project-<workspace>.db next to Path,
+	// and other drivers the prefix <workspace>_ when TablePrefix is empty.
+	TablePrefix string `mapstructure:"table_prefix"`
+	Workspace   string `mapstructure:"workspace"`
+
+	// Connection pool, retries of transient errors and read replicas
+	MaxOpenConns     int      `mapstructure:"max_open_conns"`     // Connections open at most, 0 for no limit
+	MaxIdleConns     int      `mapstructure:"max_idle_conns"`     // Connections kept open while idle
+	ConnMaxLifetime  int      `mapstructure:"conn_max_lifetime"`  // Seconds a connection is reused, 0 for no limit
+	ConnMaxIdleTime  int      `mapstructure:"conn_max_idle_time"` // Seconds an idle connection is kept, 0 for no limit
+	StatementTimeout int      `mapstructure:"statement_timeout"`  // Seconds a statement may run, 0 for no limit (Postgres; MySQL 5.7+ reads)
+	Retries          int      `mapstructure:"retries"`            // Attempts after a connection error, on connecting and for reads
+	RetryBackoff     int      `mapstructure:"retry_backoff"`      // Milliseconds before the first retry, doubled after each
+	MaxRetryBackoff  int      `mapstructure:"max_retry_backoff"`  // Milliseconds between retries at most
+	Replicas         []string `mapstructure:"replicas"`           // Read replicas as host or host:port, with the credentials above (Postgres, MySQL)
+}
+
+var validWorkspace = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// ApplyWorkspace resolves the SQLite file and table prefix of the workspace
+func (d *DatabaseConfig) ApplyWorkspace() error {
+	if d.Workspace == "" {
+		return nil
+	}
+	if !validWorkspace.MatchString(d.Workspace) {
+		return fmt.Errorf("invalid workspace %q: start with a lowercase letter, then letters, digits, dashes and underscores", d.Workspace)
+	}
+	if d.Driver == "sqlite" {
+		ext := filepath.Ext(d.Path)
+		d.Path = strings.TrimSuffix(d.Path, ext) + "-" + d.Workspace + ext
+	} else if d.TablePrefix == "" {
+		d.TablePrefix = strings.ReplaceAll(d.Workspace, "-", "_") + "_"
+	}
+	return nil
 }
 
 type LoggingConfig struct {
@@ -53,6 +106,141 @@ type HotReloadConfig struct {
 	LogLevel    string   `mapstructure:"log_level"`
 }
 
+type JobsConfig struct {
+	Workers    int `mapstructure:"workers"`     // Background jobs running concurrently
+	Retention  int `mapstructure:"retention"`   // Hours finished jobs are kept
+	MaxHistory int `mapstructure:"max_history"` // Finished jobs kept at most
+}
+
+type TunnelConfig struct {
+	Provider     string `mapstructure:"provider"`      // cloudflared, ngrok or ssh (detected when empty)
+	RelayHost    string `mapstructure:"relay_host"`    // ssh destination of the relay for the ssh provider
+	RelayPort    int    `mapstructure:"relay_port"`    // Remote port on the relay, 0 to let it allocate one
+	RelayURL     string `mapstructure:"relay_url"`     // Public URL of the relay, {port} is the remote port
+	StartTimeout int    `mapstructure:"start_timeout"` // Seconds to wait for the public URL
+}
+
+type MDNSConfig struct {
+	Enabled bool `mapstructure:"enabled"` // Announce opted-in running projects as <name>.local
+}
+
+type StatusPageConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // Serve the public /status page
+	Title   string `mapstructure:"title"`
+}
+
+type StartQueueConfig struct {
+	MaxConcurrent int     `mapstructure:"max_concurrent"` // Projects starting at once, 0 for no limit
+	CPUThreshold  float64 `mapstructure:"cpu_threshold"`  // CPU percent above which queued starts wait for running ones, 0 disables
+	SettleTime    int     `mapstructure:"settle_time"`    // Seconds a start holds its slot unless its port opens or it exits first
+	MaxWait       int     `mapstructure:"max_wait"`       // Seconds a start waits in line before it fails
+}
+
+type PowerConfig struct {
+	Mode           string `mapstructure:"mode"`            // auto (low power on battery), on, off
+	IntervalFactor int    `mapstructure:"interval_factor"` // Monitor intervals are multiplied by this in low-power mode
+}
+
+type IdleConfig struct {
+	CheckInterval int `mapstructure:"check_interval"` // Seconds between checks for projects past their idle_timeout
+	WakeTimeout   int `mapstructure:"wake_timeout"`   // Seconds a proxied request waits for a stopped project to start
+}
+
+type AuthConfig struct {
+	AdminToken string `mapstructure:"admin_token"` // Bearer token required by every API route when set; project tokens (/tokens) only open their scopes
+}
+
+type CIConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`        // Poll the pipelines of project repositories and accept webhooks
+	GitHubToken   string `mapstructure:"github_token"`   // Token reading Actions (actions:read), public repositories only when empty
+	GitHubAPIURL  string `mapstructure:"github_api_url"` // https://<host>/api/v3 for GitHub Enterprise
+	GitLabToken   string `mapstructure:"gitlab_token"`   // Token with read_api
+	GitLabURL     string `mapstructure:"gitlab_url"`     // Self-managed instance, gitlab.com by default
+	PollInterval  int    `mapstructure:"poll_interval"`  // Seconds between pipeline checks, 0 to rely on webhooks
+	WebhookSecret string `mapstructure:"webhook_secret"` // Secret of the /webhooks/github and /webhooks/gitlab receivers, which are off without it
+}
+
+type EventsConfig struct {
+	AuditCategories []string          `mapstructure:"audit_categories"` // Event categories kept in the audit log, every event when empty
+	AuditRetention  int               `mapstructure:"audit_retention"`  // Days audit events are kept, 0 for forever
+	LogFile         string            `mapstructure:"log_file"`         // NDJSON file events are appended to, disabled when empty
+	LogCategories   []string          `mapstructure:"log_categories"`   // Event categories written to log_file, every event when empty
+	LogMaxSize      int               `mapstructure:"log_max_size"`     // MB before log_file is rotated to <file>.1, 0 for no limit
+	Webhooks        []WebhookConfig   `mapstructure:"webhooks"`
+	PagerDuty       []PagerDutyConfig `mapstructure:"pagerduty"` // Alerts sent to PagerDuty services
+	Opsgenie        []OpsgenieConfig  `mapstructure:"opsgenie"`  // Alerts sent to Opsgenie
+}
+
+type WebhookConfig struct {
+	URL        string   `mapstructure:"url"`
+	Secret     string   `mapstructure:"secret"`     // Signs the body in X-Go-Runner-Signature
+	Categories []string `mapstructure:"categories"` // Event categories posted
+	Types      []string `mapstructure:"types"`      // Event types posted besides the categories; every event when both are empty
+}
+
+type PagerDutyConfig struct {
+	Name       string   `mapstructure:"name"`        // Tells several services apart in sink stats and test fires
+	RoutingKey string   `mapstructure:"routing_key"` // Integration key of an Events API v2 integration
+	Levels     []string `mapstructure:"levels"`      // Alert levels triggered on this service, every level when empty
+	APIURL     string   `mapstructure:"api_url"`     // Events API endpoint, https://events.pagerduty.com/v2/enqueue when empty
+}
+
+type OpsgenieConfig struct {
+	Name       string            `mapstructure:"name"`       // Tells several teams apart in sink stats and test fires
+	APIKey     string            `mapstructure:"api_key"`    // Key of an API integration
+	Levels     []string          `mapstructure:"levels"`     // Alert levels sent, every level when empty
+	Priorities map[string]string `mapstructure:"priorities"` // Alert level to priority (P1-P5), overriding critical P1, error P2, warning P3, info P5
+	Team       string            `mapstructure:"team"`       // Team the alerts are routed to, the integration's team when empty
+	Tags       []string          `mapstructure:"tags"`       // Added to every alert besides go-runner and the alert type
+	APIURL     string            `mapstructure:"api_url"`    // https://api.opsgenie.com when empty, https://api.eu.opsgenie.com for the EU instance
+}
+
+type ServiceLogsConfig struct {
+	Dir         string `mapstructure:"dir"`           // Output files of service processes, one stdout and one stderr file per project
+	MaxSize     int    `mapstructure:"max_size"`      // MB before a file is rotated to <file>.1, 0 for no limit
+	MaxLineSize int    `mapstructure:"max_line_size"` // KB of a log line kept, longer lines are truncated
+}
+
+type LogArchiveConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`         // Upload rotated service output files to S3-compatible storage
+	Endpoint      string `mapstructure:"endpoint"`        // e.g. https://storage.googleapis.com or http://minio:9000, AWS S3 of the region when empty
+	Region        string `mapstructure:"region"`
+	Bucket        string `mapstructure:"bucket"`
+	AccessKey     string `mapstructure:"access_key"`      // HMAC key for Google Cloud Storage
+	SecretKey     string `mapstructure:"secret_key"`
+	PathStyle     bool   `mapstructure:"path_style"`      // endpoint/bucket/key instead of bucket.endpoint/key
+	Prefix        string `mapstructure:"prefix"`          // Key prefix in the bucket
+	SpoolDir      string `mapstructure:"spool_dir"`       // Local copies of rotated files until uploaded
+	Interval      int    `mapstructure:"interval"`        // Minutes between upload runs
+	RetentionDays int    `mapstructure:"retention_days"`  // Days archives are kept, 0 to keep them
+	MaxPerProject int    `mapstructure:"max_per_project"` // Archives kept per project, 0 for no limit
+}
+
+type KubernetesConfig struct {
+	Enabled      bool   `mapstructure:"enabled"`       // Let projects control a deployment (kube_deployment)
+	Kubectl      string `mapstructure:"kubectl"`       // kubectl binary
+	Kubeconfig   string `mapstructure:"kubeconfig"`    // kubeconfig file, kubectl's default when empty
+	PollInterval int    `mapstructure:"poll_interval"` // Seconds between pod readiness checks
+}
+
+type SSHConfig struct {
+	Binary         string `mapstructure:"binary"`           // ssh client
+	IdentityFile   string `mapstructure:"identity_file"`    // Private key of the hosts, ssh's default keys when empty
+	KnownHostsFile string `mapstructure:"known_hosts_file"` // ssh's default when empty
+	HostKeyCheck   string `mapstructure:"host_key_check"`   // StrictHostKeyChecking: yes, accept-new or no
+	User           string `mapstructure:"user"`             // Login user of ssh_host values without one
+	ConnectTimeout int    `mapstructure:"connect_timeout"`  // Seconds
+	PollInterval   int    `mapstructure:"poll_interval"`    // Seconds between remote process checks
+	StateDir       string `mapstructure:"state_dir"`        // Remote directory of pid and log files, relative to the home directory
+}
+
+type NodesConfig struct {
+	PollInterval     int    `mapstructure:"poll_interval"`     // Seconds between checks of the hosts of SSH projects
+	Failures         int    `mapstructure:"failures"`          // Consecutive failed checks before a node is down
+	WG               string `mapstructure:"wg"`                // WireGuard CLI, tunnels are not tracked when missing
+	HandshakeTimeout int    `mapstructure:"handshake_timeout"` // Seconds since the last handshake after which a tunnel is down
+}
+
 func Load() *Config {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -81,6 +269,11 @@ func Load() *Config {
 		os.Exit(1)
 	}
 
+	if err := config.Database.ApplyWorkspace(); err != nil {
+		fmt.Printf("Error in database config: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Ensure data directory exists for SQLite
 	if config.Database.Driver == "sqlite" {
 		dir := filepath.Dir(config.Database.Path)
@@ -109,6 +302,17 @@ func setDefaults() {
 	viper.SetDefault("database.password", "")
 	viper.SetDefault("database.dbname", "go_runner")
 	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.table_prefix", "")
+	viper.SetDefault("database.workspace", "")
+	viper.SetDefault("database.max_open_conns", 0)
+	viper.SetDefault("database.max_idle_conns", 2)
+	viper.SetDefault("database.conn_max_lifetime", 1800)
+	viper.SetDefault("database.conn_max_idle_time", 300)
+	viper.SetDefault("database.statement_timeout", 30)
+	viper.SetDefault("database.retries", 5)
+	viper.SetDefault("database.retry_backoff", 500)
+	viper.SetDefault("database.max_retry_backoff", 10000)
+	viper.SetDefault("database.replicas", []string{})
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -127,6 +331,76 @@ func setDefaults() {
 	setPlatformSpecificDefaults()
 	
 	viper.SetDefault("hot_reload.log_level", "info")
+
+	// Background job defaults
+	viper.SetDefault("jobs.workers", 4)
+	viper.SetDefault("jobs.retention", 168)
+	viper.SetDefault("jobs.max_history", 1000)
+
+	// Tunnel defaults
+	viper.SetDefault("tunnel.provider", "")
+	viper.SetDefault("tunnel.relay_port", 0)
+	viper.SetDefault("tunnel.start_timeout", 30)
+
+	// mDNS defaults
+	viper.SetDefault("mdns.enabled", false)
+
+	// Status page defaults
+	viper.SetDefault("status_page.enabled", true)
+	viper.SetDefault("status_page.title", "Service Status")
+
+	// Kubernetes defaults
+	viper.SetDefault("kubernetes.enabled", false)
+	viper.SetDefault("kubernetes.kubectl", "kubectl")
+	viper.SetDefault("kubernetes.kubeconfig", "")
+	viper.SetDefault("kubernetes.poll_interval", 15)
+
+	// SSH defaults
+	viper.SetDefault("ssh.binary", "ssh")
+	viper.SetDefault("ssh.identity_file", "")
+	viper.SetDefault("ssh.known_hosts_file", "")
+	viper.SetDefault("ssh.host_key_check", "accept-new")
+	viper.SetDefault("ssh.user", "")
+	viper.SetDefault("ssh.connect_timeout", 10)
+	viper.SetDefault("ssh.poll_interval", 15)
+	viper.SetDefault("ssh.state_dir", ".go-runner")
+
+	// Node defaults
+	viper.SetDefault("nodes.poll_interval", 10)
+	viper.SetDefault("nodes.failures", 3)
+	viper.SetDefault("nodes.wg", "wg")
+	viper.SetDefault("nodes.handshake_timeout", 180)
+
+	// Service output defaults
+	viper.SetDefault("service_logs.dir", "./data/logs")
+	viper.SetDefault("service_logs.max_size", 10)
+	viper.SetDefault("service_logs.max_line_size", 256)
+	viper.SetDefault("log_archive.enabled", false)
+	viper.SetDefault("log_archive.region", "us-east-1")
+	viper.SetDefault("log_archive.path_style", true)
+	viper.SetDefault("log_archive.prefix", "go-runner")
+	viper.SetDefault("log_archive.spool_dir", "./data/log-archive")
+	viper.SetDefault("log_archive.interval", 15)
+	viper.SetDefault("log_archive.retention_days", 90)
+	viper.SetDefault("log_archive.max_per_project", 0)
+	viper.SetDefault("start_queue.max_concurrent", 0)
+	viper.SetDefault("start_queue.cpu_threshold", 0)
+	viper.SetDefault("start_queue.settle_time", 30)
+	viper.SetDefault("start_queue.max_wait", 600)
+	viper.SetDefault("power.mode", "auto")
+	viper.SetDefault("power.interval_factor", 4)
+	viper.SetDefault("idle.check_interval", 60)
+	viper.SetDefault("idle.wake_timeout", 60)
+	viper.SetDefault("events.audit_categories", []string{"lifecycle", "alert", "config", "job"})
+	viper.SetDefault("events.audit_retention", 30)
+	viper.SetDefault("events.log_file", "")
+	viper.SetDefault("events.log_categories", []string{"lifecycle", "alert", "config", "job"})
+	viper.SetDefault("events.log_max_size", 50)
+	viper.SetDefault("auth.admin_token", "")
+	viper.SetDefault("ci.enabled", false)
+	viper.SetDefault("ci.github_api_url", "https://api.github.com")
+	viper.SetDefault("ci.gitlab_url", "https://gitlab.com")
+	viper.SetDefault("ci.poll_interval", 300)
 }
 
 // setPlatformSpecificDefaults sets platform-specific default values
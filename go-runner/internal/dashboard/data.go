@@ -0,0 +1,184 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-runner/internal/tables"
+
+	"gorm.io/gorm"
+)
+
+// maxPoints caps the points of a series; longer ranges are averaged into
+// buckets
+const maxPoints = 120
+
+// Point is one sample of a series
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// Series is the samples of a metric, system-wide or for one project
+type Series struct {
+	ProjectID uint     `json:"project_id,omitempty"`
+	Name      string   `json:"name"` // Metric or project name
+	Latest    *float64 `json:"latest"`
+	Points    []Point  `json:"points"` // Oldest first; only the latest for gauge and stat panels
+}
+
+// PanelData is a panel with its series
+type PanelData struct {
+	Panel
+	Unit   string    `json:"unit"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Series []Series  `json:"series"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// DashboardData is a dashboard with the data of every panel
+type DashboardData struct {
+	Dashboard Dashboard   `json:"dashboard"`
+	Panels    []PanelData `json:"panels"`
+}
+
+type sample struct {
+	ProjectID uint
+	Timestamp time.Time
+	Value     float64
+}
+
+// loadPanel reads the samples of a panel over its range, or over span when
+// it is set
+func loadPanel(db *gorm.DB, p Panel, span time.Duration, now time.Time) PanelData {
+	data := PanelData{Panel: p, To: now, Series: []Series{}}
+	metric, ok := findMetric(p.Metric)
+	if !ok {
+		data.Error = fmt.Sprintf("unknown metric %q", p.Metric)
+		return data
+	}
+	data.Unit = metric.Unit
+
+	if span == 0 {
+		var err error
+		if span, err = parseRange(p.Range); err != nil {
+			data.Error = err.Error()
+			return data
+		}
+	}
+	data.From = now.Add(-span)
+
+	byProject, order, err := loadSamples(db, metric, p.ProjectIDs, data.From, now)
+	if err != nil {
+		data.Error = err.Error()
+		return data
+	}
+	names := seriesNames(db, metric, order)
+
+	latestOnly := p.Chart == ChartGauge || p.Chart == ChartStat
+	for _, id := range order {
+		rows := byProject[id]
+		series := Series{ProjectID: id, Name: names[id]}
+		latest := rows[len(rows)-1]
+		series.Latest = &latest.Value
+		if latestOnly {
+			series.Points = []Point{{Timestamp: latest.Timestamp, Value: latest.Value}}
+		} else {
+			series.Points = downsample(rows, data.From, span)
+		}
+		data.Series = append(data.Series, series)
+	}
+	return data
+}
+
+// loadSamples reads the samples of a metric between from and to, oldest
+// first, by project (0 for system metrics) with the projects in ID order
+func loadSamples(db *gorm.DB, metric Metric, projectIDs []uint, from, to time.Time) (map[uint][]sample, []uint, error) {
+	columns := "0 AS project_id, timestamp, " + metric.value + " AS value"
+	if metric.PerProject {
+		columns = "project_id, timestamp, " + metric.value + " AS value"
+	}
+	query := db.Table(tables.Name(metric.table)).Select(columns).Where("timestamp >= ? AND timestamp <= ?", from, to)
+	if metric.PerProject && len(projectIDs) > 0 {
+		query = query.Where("project_id IN ?", projectIDs)
+	}
+	if metric.nullable {
+		query = query.Where(metric.value + " IS NOT NULL")
+	}
+	if metric.group {
+		query = query.Group("project_id, timestamp")
+	}
+
+	var samples []sample
+	if err := query.Order("timestamp").Scan(&samples).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %v", metric.Name, err)
+	}
+
+	byProject := make(map[uint][]sample)
+	var order []uint
+	for _, s := range samples {
+		if _, ok := byProject[s.ProjectID]; !ok {
+			order = append(order, s.ProjectID)
+		}
+		byProject[s.ProjectID] = append(byProject[s.ProjectID], s)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+	return byProject, order, nil
+}
+
+// seriesNames names the series of a metric: the metric for a system one,
+// the projects for a project one
+func seriesNames(db *gorm.DB, metric Metric, ids []uint) map[uint]string {
+	names := map[uint]string{0: metric.Name}
+	if metric.PerProject && len(ids) > 0 {
+		var projects []struct {
+			ID   uint
+			Name string
+		}
+		db.Table(tables.Name("projects")).Select("id, name").Where("id IN ?", ids).Scan(&projects)
+		for _, project := range projects {
+			names[project.ID] = project.Name
+		}
+	}
+	for _, id := range ids {
+		if names[id] == "" {
+			names[id] = fmt.Sprintf("project %d", id)
+		}
+	}
+	return names
+}
+
+// downsample averages samples into at most maxPoints buckets
+func downsample(rows []sample, from time.Time, span time.Duration) []Point {
+	if len(rows) <= maxPoints {
+		points := make([]Point, len(rows))
+		for i, r := range rows {
+			points[i] = Point{Timestamp: r.Timestamp, Value: r.Value}
+		}
+		return points
+	}
+
+	step := span / maxPoints
+	var points []Point
+	var sum float64
+	var count int
+	bucket := -1
+	flush := func() {
+		if count > 0 {
+			points = append(points, Point{Timestamp: from.Add(time.Duration(bucket) * step), Value: sum / float64(count)})
+		}
+	}
+	for _, r := range rows {
+		b := int(r.Timestamp.Sub(from) / step)
+		if b != bucket {
+			flush()
+			bucket, sum, count = b, 0, 0
+		}
+		sum += r.Value
+		count++
+	}
+	flush()
+	return points
+}
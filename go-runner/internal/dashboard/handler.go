@@ -0,0 +1,265 @@
+package dashboard
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler serves the saved dashboard API
+type Handler struct {
+	db *gorm.DB
+}
+
+// RegisterRoutes registers the dashboard routes
+func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB) {
+	h := &Handler{db: db}
+
+	dashboards := r.Group("/dashboards")
+	{
+		dashboards.GET("", h.GetDashboards)
+		dashboards.POST("", h.CreateDashboard)
+		dashboards.GET("/metrics", h.GetMetrics)
+		dashboards.GET("/:id", h.GetDashboard)
+		dashboards.PUT("/:id", h.UpdateDashboard)
+		dashboards.DELETE("/:id", h.DeleteDashboard)
+		dashboards.GET("/:id/data", h.GetDashboardData)
+	}
+
+	r.GET("/system/metrics/query", h.QueryMetrics)
+}
+
+// DashboardRequest creates or replaces a dashboard
+type DashboardRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Team        string  `json:"team"`
+	Description string  `json:"description"`
+	Panels      []Panel `json:"panels"`
+}
+
+// GetMetrics godoc
+// @Summary      List dashboard metrics
+// @Description  List the metrics dashboard panels can show, with their unit and whether they are recorded per project
+// @Tags         dashboards
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]Metric}
+// @Router       /dashboards/metrics [get]
+func (h *Handler) GetMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, types.DataResponse{Data: metrics})
+}
+
+// GetDashboards godoc
+// @Summary      List dashboards
+// @Description  List saved dashboards, optionally of one team
+// @Tags         dashboards
+// @Produce      json
+// @Param        team  query     string  false  "Only dashboards of this team"
+// @Success      200   {object}  types.DataResponse{data=[]Dashboard}
+// @Router       /dashboards [get]
+func (h *Handler) GetDashboards(c *gin.Context) {
+	query := h.db.Order("team, name")
+	if team, ok := c.GetQuery("team"); ok {
+		query = query.Where("team = ?", team)
+	}
+
+	dashboards := []Dashboard{}
+	if err := query.Find(&dashboards).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch dashboards", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: dashboards})
+}
+
+// CreateDashboard godoc
+// @Summary      Create a dashboard
+// @Description  Save a dashboard of metric panels. Each panel shows one metric (see GET /dashboards/metrics) over a time range (15m, 6h, 7d..., default 1h) as a line, area, bar, gauge or stat chart; project metrics show the listed projects, or every project with data.
+// @Tags         dashboards
+// @Accept       json
+// @Produce      json
+// @Param        request  body      DashboardRequest  true  "Dashboard"
+// @Success      201      {object}  types.DataResponse{data=Dashboard}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid dashboard"
+// @Router       /dashboards [post]
+func (h *Handler) CreateDashboard(c *gin.Context) {
+	var req DashboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	dashboard := Dashboard{}
+	if !h.apply(c, &dashboard, req) {
+		return
+	}
+	if err := h.db.Create(&dashboard).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to create dashboard", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.DataResponse{Data: dashboard})
+}
+
+// GetDashboard godoc
+// @Summary      Get a dashboard
+// @Tags         dashboards
+// @Produce      json
+// @Param        id   path      int  true  "Dashboard ID"
+// @Success      200  {object}  types.DataResponse{data=Dashboard}
+// @Failure      404  {object}  middleware.ErrorResponse  "Dashboard not found"
+// @Router       /dashboards/{id} [get]
+func (h *Handler) GetDashboard(c *gin.Context) {
+	dashboard, ok := h.loadDashboard(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: dashboard})
+}
+
+// UpdateDashboard godoc
+// @Summary      Update a dashboard
+// @Description  Replace the name, team, description and panels of a dashboard
+// @Tags         dashboards
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int               true  "Dashboard ID"
+// @Param        request  body      DashboardRequest  true  "Dashboard"
+// @Success      200      {object}  types.DataResponse{data=Dashboard}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid dashboard"
+// @Failure      404      {object}  middleware.ErrorResponse  "Dashboard not found"
+// @Router       /dashboards/{id} [put]
+func (h *Handler) UpdateDashboard(c *gin.Context) {
+	dashboard, ok := h.loadDashboard(c)
+	if !ok {
+		return
+	}
+
+	var req DashboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if !h.apply(c, dashboard, req) {
+		return
+	}
+	if err := h.db.Save(dashboard).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to update dashboard", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: dashboard})
+}
+
+// DeleteDashboard godoc
+// @Summary      Delete a dashboard
+// @Tags         dashboards
+// @Produce      json
+// @Param        id   path      int  true  "Dashboard ID"
+// @Success      200  {object}  types.MessageResponse     "Dashboard deleted"
+// @Failure      404  {object}  middleware.ErrorResponse  "Dashboard not found"
+// @Router       /dashboards/{id} [delete]
+func (h *Handler) DeleteDashboard(c *gin.Context) {
+	dashboard, ok := h.loadDashboard(c)
+	if !ok {
+		return
+	}
+	if err := h.db.Delete(dashboard).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete dashboard", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Dashboard deleted"})
+}
+
+// GetDashboardData godoc
+// @Summary      Get dashboard data
+// @Description  Get the series of every panel of a dashboard: one per project for project metrics, oldest point first and averaged down to at most 120 points. Gauge and stat panels only get the latest value. A panel that cannot be read carries an error instead of failing the dashboard.
+// @Tags         dashboards
+// @Produce      json
+// @Param        id     path      int     true   "Dashboard ID"
+// @Param        range  query     string  false  "Time range for every panel instead of their own (15m, 6h, 7d...)"
+// @Success      200    {object}  types.DataResponse{data=DashboardData}
+// @Failure      400    {object}  middleware.ErrorResponse  "Invalid range"
+// @Failure      404    {object}  middleware.ErrorResponse  "Dashboard not found"
+// @Router       /dashboards/{id}/data [get]
+func (h *Handler) GetDashboardData(c *gin.Context) {
+	dashboard, ok := h.loadDashboard(c)
+	if !ok {
+		return
+	}
+
+	var span time.Duration
+	if raw := c.Query("range"); raw != "" {
+		var err error
+		if span, err = parseRange(raw); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid range", err.Error()))
+			return
+		}
+	}
+
+	now := time.Now()
+	data := DashboardData{Dashboard: *dashboard, Panels: make([]PanelData, len(dashboard.Panels))}
+	for i, panel := range dashboard.Panels {
+		data.Panels[i] = loadPanel(h.db, panel, span, now)
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: data})
+}
+
+// apply validates a request and copies it onto a dashboard
+func (h *Handler) apply(c *gin.Context, dashboard *Dashboard, req DashboardRequest) bool {
+	dashboard.Name = req.Name
+	dashboard.Team = req.Team
+	dashboard.Description = req.Description
+	dashboard.Panels = req.Panels
+	if dashboard.Panels == nil {
+		dashboard.Panels = []Panel{}
+	}
+	if err := dashboard.validate(); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid dashboard", err.Error()))
+		return false
+	}
+
+	ids := make(map[uint]bool)
+	for _, panel := range dashboard.Panels {
+		for _, id := range panel.ProjectIDs {
+			ids[id] = true
+		}
+	}
+	if len(ids) > 0 {
+		list := make([]uint, 0, len(ids))
+		for id := range ids {
+			list = append(list, id)
+		}
+		var count int64
+		h.db.Table(tables.Name("projects")).Where("id IN ? AND deleted_at IS NULL", list).Count(&count)
+		if int(count) != len(list) {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Unknown project in project_ids", list))
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Handler) loadDashboard(c *gin.Context) (*Dashboard, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return nil, false
+	}
+
+	var dashboard Dashboard
+	if err := h.db.First(&dashboard, id).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Dashboard not found", nil))
+		return nil, false
+	}
+	return &dashboard, true
+}
@@ -0,0 +1,155 @@
+// Package dashboard holds saved dashboards: named sets of metric panels that
+// arrange the recorded system, traffic and queue metrics into custom views
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Chart types a panel can be drawn as
+const (
+	ChartLine  = "line"
+	ChartArea  = "area"
+	ChartBar   = "bar"
+	ChartGauge = "gauge" // Latest value only
+	ChartStat  = "stat"  // Latest value only
+)
+
+var chartTypes = map[string]bool{ChartLine: true, ChartArea: true, ChartBar: true, ChartGauge: true, ChartStat: true}
+
+// maxRange is the longest time range a panel can show
+const maxRange = 90 * 24 * time.Hour
+
+// Dashboard is a saved view of metric panels, owned by a team
+type Dashboard struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name" gorm:"not null"`
+	Team        string    `json:"team" gorm:"index"` // Empty for dashboards shared by everyone
+	Description string    `json:"description"`
+	Panels      []Panel   `json:"panels" gorm:"type:text;serializer:json"` // In display order
+}
+
+// Panel is one chart of a dashboard
+type Panel struct {
+	Title      string `json:"title"`
+	Metric     string `json:"metric"`                // See GET /dashboards/metrics
+	ProjectIDs []uint `json:"project_ids,omitempty"` // Projects of a project metric, every project with data when empty
+	Range      string `json:"range"`                 // Time range: 15m, 6h, 7d... (default 1h)
+	Chart      string `json:"chart"`                 // line, area, bar, gauge or stat (default line)
+	Width      int    `json:"width,omitempty"`       // Grid columns (1-12) for the frontend layout
+}
+
+// Metric is a metric panels can show
+type Metric struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+	PerProject  bool   `json:"per_project"` // One series per project instead of a system-wide one
+
+	table string // Table the samples are read from
+	value string // SQL expression of the value
+	group bool   // Sum the rows sharing a timestamp (one row per queue)
+
+	nullable bool // Skip the rows without a value, such as the first sample of a process
+}
+
+// metrics are the recorded metrics, in catalog order
+var metrics = []Metric{
+	{Name: "cpu_usage", Description: "System CPU usage", Unit: "%", table: "system_metrics", value: "cpu_usage"},
+	{Name: "memory_usage", Description: "System memory usage", Unit: "%", table: "system_metrics", value: "memory_usage"},
+	{Name: "disk_usage", Description: "System disk usage", Unit: "%", table: "system_metrics", value: "disk_usage"},
+	{Name: "load_avg_1", Description: "1 minute load average", table: "system_metrics", value: "load_avg_1"},
+	{Name: "load_avg_5", Description: "5 minute load average", table: "system_metrics", value: "load_avg_5"},
+	{Name: "load_avg_15", Description: "15 minute load average", table: "system_metrics", value: "load_avg_15"},
+	{Name: "open_files", Description: "Open file handles system-wide", table: "system_metrics", value: "open_files"},
+	{Name: "requests", Description: "Proxied requests per interval", PerProject: true, table: "traffic_metrics", value: "requests"},
+	{Name: "errors", Description: "Proxied 5xx responses and failures per interval", PerProject: true, table: "traffic_metrics", value: "errors"},
+	{Name: "error_rate", Description: "Share of proxied requests that failed", Unit: "%", PerProject: true, table: "traffic_metrics", value: "CASE WHEN requests > 0 THEN errors * 100.0 / requests ELSE 0 END"},
+	{Name: "latency_p50", Description: "Median proxied request latency", Unit: "ms", PerProject: true, table: "traffic_metrics", value: "p50_ms"},
+	{Name: "latency_p95", Description: "95th percentile proxied request latency", Unit: "ms", PerProject: true, table: "traffic_metrics", value: "p95_ms"},
+	{Name: "latency_p99", Description: "99th percentile proxied request latency", Unit: "ms", PerProject: true, table: "traffic_metrics", value: "p99_ms"},
+	{Name: "queue_depth", Description: "Messages waiting in the project queues", PerProject: true, table: "queue_metrics", value: "SUM(depth)", group: true},
+	{Name: "monitor_latency", Description: "Synthetic monitor response time", Unit: "ms", PerProject: true, table: "monitor_checks", value: "latency_ms"},
+	{Name: "monitor_availability", Description: "Share of synthetic monitor checks that passed", Unit: "%", PerProject: true, table: "monitor_checks", value: "CASE WHEN up THEN 100 ELSE 0 END"},
+	{Name: "process_cpu", Description: "CPU of the project process tree, of one core", Unit: "%", PerProject: true, table: "process_metrics", value: "cpu_percent", nullable: true},
+	{Name: "process_memory", Description: "Resident memory of the project process tree", Unit: "bytes", PerProject: true, table: "process_metrics", value: "memory_bytes"},
+	{Name: "process_disk_read", Description: "Bytes read from disk by the project process tree", Unit: "bytes/s", PerProject: true, table: "process_metrics", value: "disk_read_bytes_per_sec", nullable: true},
+	{Name: "process_disk_write", Description: "Bytes written to disk by the project process tree", Unit: "bytes/s", PerProject: true, table: "process_metrics", value: "disk_write_bytes_per_sec", nullable: true},
+	{Name: "process_net_sent", Description: "TCP bytes sent by the project process tree", Unit: "bytes/s", PerProject: true, table: "process_metrics", value: "net_sent_bytes_per_sec", nullable: true},
+	{Name: "process_net_received", Description: "TCP bytes received by the project process tree", Unit: "bytes/s", PerProject: true, table: "process_metrics", value: "net_received_bytes_per_sec", nullable: true},
+}
+
+func findMetric(name string) (Metric, bool) {
+	for _, m := range metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// parseRange parses a panel time range: a Go duration (90m, 6h) or a number
+// of days (7d)
+func parseRange(s string) (time.Duration, error) {
+	if s == "" {
+		return time.Hour, nil
+	}
+
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid range %q (use e.g. 15m, 6h or 7d)", s)
+	}
+	if d < time.Minute || d > maxRange {
+		return 0, fmt.Errorf("range %q must be between 1m and 90d", s)
+	}
+	return d, nil
+}
+
+// validate checks the panels and fills their defaults
+func (d *Dashboard) validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	for i := range d.Panels {
+		p := &d.Panels[i]
+		metric, ok := findMetric(p.Metric)
+		if !ok {
+			return fmt.Errorf("panel %d: unknown metric %q", i+1, p.Metric)
+		}
+		if !metric.PerProject && len(p.ProjectIDs) > 0 {
+			return fmt.Errorf("panel %d: %s is system-wide and takes no project_ids", i+1, p.Metric)
+		}
+		if _, err := parseRange(p.Range); err != nil {
+			return fmt.Errorf("panel %d: %v", i+1, err)
+		}
+		if p.Range == "" {
+			p.Range = "1h"
+		}
+		if p.Chart == "" {
+			p.Chart = ChartLine
+		}
+		if !chartTypes[p.Chart] {
+			return fmt.Errorf("panel %d: unknown chart %q (line, area, bar, gauge or stat)", i+1, p.Chart)
+		}
+		if p.Width < 0 || p.Width > 12 {
+			return fmt.Errorf("panel %d: width must be between 1 and 12", i+1)
+		}
+		if p.Title == "" {
+			p.Title = metric.Description
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBuckets caps the buckets of a query, range divided by step
+const maxBuckets = 5000
+
+// Aggregations of the samples of a bucket
+const (
+	AggregateAvg = "avg"
+	AggregateMax = "max"
+	AggregateP95 = "p95"
+)
+
+// QueryResult is the series of a query in columns: every series has one
+// value per timestamp
+type QueryResult struct {
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Step        int64         `json:"step"` // Seconds per bucket
+	Aggregation string        `json:"aggregation"`
+	Timestamps  []int64       `json:"timestamps"` // Unix seconds of the start of each bucket
+	Series      []QuerySeries `json:"series"`
+}
+
+// QuerySeries is the values of a metric, system-wide or for one project
+type QuerySeries struct {
+	Metric    string     `json:"metric"`
+	ProjectID uint       `json:"project_id,omitempty"`
+	Name      string     `json:"name"` // Metric or project name
+	Unit      string     `json:"unit"`
+	Values    []*float64 `json:"values"` // Null for buckets without samples
+}
+
+// QueryMetrics godoc
+// @Summary      Query metric series
+// @Description  Read several metrics over a time range, aggregated into buckets of step each, as columns: one list of bucket timestamps and one list of values per series, null where a bucket has no sample, so a chart can plot them as they come. Metrics are those of GET /dashboards/metrics; system metrics give one series, project metrics one per listed project, or per project with data when project_ids is empty. Buckets aggregate their samples with avg (default), max or p95. The step defaults to the range divided by 120 and a query holds at most 5000 buckets.
+// @Tags         system
+// @Produce      json
+// @Param        series       query     string  true   "Comma-separated metrics, e.g. cpu_usage,memory_usage,latency_p95"
+// @Param        project_ids  query     string  false  "Comma-separated projects of the project metrics"
+// @Param        range        query     string  false  "Time range: 15m, 6h, 7d... (default 1h, max 90d)"
+// @Param        to           query     string  false  "End of the range, RFC 3339 (default now)"
+// @Param        step         query     string  false  "Bucket size: 30s, 5m, 1h... (at least 1s)"
+// @Param        agg          query     string  false  "Aggregation: avg, max or p95 (default avg)"
+// @Success      200          {object}  types.DataResponse{data=QueryResult}
+// @Failure      400          {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      500          {object}  middleware.ErrorResponse  "Internal server error"
+// @Router       /system/metrics/query [get]
+func (h *Handler) QueryMetrics(c *gin.Context) {
+	var selected []Metric
+	for _, name := range splitList(c.Query("series")) {
+		metric, ok := findMetric(name)
+		if !ok {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Unknown metric", name))
+			return
+		}
+		selected = append(selected, metric)
+	}
+	if len(selected) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "series is required", nil))
+		return
+	}
+
+	var projectIDs []uint
+	for _, raw := range splitList(c.Query("project_ids")) {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid project_ids", raw))
+			return
+		}
+		projectIDs = append(projectIDs, uint(id))
+	}
+
+	span, err := parseRange(c.Query("range"))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid range", err.Error()))
+		return
+	}
+	to := time.Now()
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid to", err.Error()))
+			return
+		}
+	}
+
+	step := (span + maxPoints*time.Second - 1) / (maxPoints * time.Second) * time.Second
+	if raw := c.Query("step"); raw != "" {
+		if step, err = time.ParseDuration(raw); err != nil || step < time.Second {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "step must be a duration of at least 1s", raw))
+			return
+		}
+		step = step.Truncate(time.Second)
+	}
+	if span/step > maxBuckets {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, fmt.Sprintf("range and step give more than %d buckets", maxBuckets), nil))
+		return
+	}
+
+	aggregation := c.DefaultQuery("agg", AggregateAvg)
+	if aggregation != AggregateAvg && aggregation != AggregateMax && aggregation != AggregateP95 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "agg must be avg, max or p95", aggregation))
+		return
+	}
+
+	from, buckets := bucketRange(to, span, step)
+	result := QueryResult{
+		From:        from,
+		To:          to.UTC(),
+		Step:        int64(step.Seconds()),
+		Aggregation: aggregation,
+		Timestamps:  make([]int64, buckets),
+		Series:      []QuerySeries{},
+	}
+	for i := range result.Timestamps {
+		result.Timestamps[i] = from.Add(time.Duration(i) * step).Unix()
+	}
+
+	for _, metric := range selected {
+		ids := projectIDs
+		if !metric.PerProject {
+			ids = nil
+		}
+		byProject, order, err := loadSamples(h.db, metric, ids, from, to)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to query metrics", err.Error()))
+			return
+		}
+		names := seriesNames(h.db, metric, order)
+		for _, id := range order {
+			result.Series = append(result.Series, QuerySeries{
+				Metric:    metric.Name,
+				ProjectID: id,
+				Name:      names[id],
+				Unit:      metric.Unit,
+				Values:    bucketize(byProject[id], from, step, buckets, aggregation),
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: result})
+}
+
+// bucketRange returns the start of the first bucket of a range ending at to
+// and the number of buckets up to to. Buckets are aligned on the step, so
+// that successive queries line up.
+func bucketRange(to time.Time, span, step time.Duration) (from time.Time, buckets int) {
+	seconds := int64(step.Seconds())
+	from = time.Unix(to.Add(-span).Unix()/seconds*seconds, 0).UTC()
+	return from, int(to.Sub(from)/step) + 1
+}
+
+// bucketize aggregates samples into buckets of step from from
+func bucketize(rows []sample, from time.Time, step time.Duration, buckets int, aggregation string) []*float64 {
+	grouped := make([][]float64, buckets)
+	for _, r := range rows {
+		// Checked before dividing, which rounds toward zero
+		if r.Timestamp.Before(from) {
+			continue
+		}
+		b := int(r.Timestamp.Sub(from) / step)
+		if b < buckets {
+			grouped[b] = append(grouped[b], r.Value)
+		}
+	}
+
+	values := make([]*float64, buckets)
+	for i, group := range grouped {
+		if len(group) == 0 {
+			continue
+		}
+		var value float64
+		switch aggregation {
+		case AggregateMax:
+			value = group[0]
+			for _, v := range group[1:] {
+				value = math.Max(value, v)
+			}
+		case AggregateP95:
+			// Nearest rank
+			sort.Float64s(group)
+			rank := int(math.Ceil(0.95 * float64(len(group))))
+			value = group[max(rank, 1)-1]
+		default:
+			for _, v := range group {
+				value += v
+			}
+			value /= float64(len(group))
+		}
+		values[i] = &value
+	}
+	return values
+}
+
+// splitList splits a comma-separated query parameter, skipping blanks
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,97 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		to      string
+		span    time.Duration
+		step    time.Duration
+		from    string
+		buckets int
+	}{
+		{"aligned", "2026-01-01T12:00:00Z", time.Hour, time.Minute, "2026-01-01T11:00:00Z", 61},
+		{"start rounded down", "2026-01-01T00:10:30Z", 10 * time.Minute, time.Minute, "2026-01-01T00:00:00Z", 11},
+		{"step of 5m", "2026-01-01T12:07:00Z", time.Hour, 5 * time.Minute, "2026-01-01T11:05:00Z", 13},
+		{"step larger than span", "2026-01-01T12:30:00Z", 15 * time.Minute, time.Hour, "2026-01-01T12:00:00Z", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to, _ := time.Parse(time.RFC3339, tt.to)
+			from, buckets := bucketRange(to, tt.span, tt.step)
+			if got := from.Format(time.RFC3339); got != tt.from || buckets != tt.buckets {
+				t.Errorf("bucketRange = %s, %d buckets, want %s, %d buckets", got, buckets, tt.from, tt.buckets)
+			}
+		})
+	}
+}
+
+func TestBucketize(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int, values ...float64) []sample {
+		rows := make([]sample, len(values))
+		for i, v := range values {
+			rows[i] = sample{Timestamp: from.Add(time.Duration(seconds) * time.Second), Value: v}
+		}
+		return rows
+	}
+	join := func(groups ...[]sample) []sample {
+		var rows []sample
+		for _, group := range groups {
+			rows = append(rows, group...)
+		}
+		return rows
+	}
+	// 1 to n in a shuffled order
+	series := func(seconds, n int) []sample {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = float64((i*11)%n + 1)
+		}
+		return at(seconds, values...)
+	}
+	none := -1.0
+
+	tests := []struct {
+		name        string
+		rows        []sample
+		buckets     int
+		aggregation string
+		want        []float64 // none for a bucket without samples
+	}{
+		{"avg", join(at(0, 1, 3), at(65, 10)), 2, AggregateAvg, []float64{2, 10}},
+		{"max", join(at(0, 1, 3), at(65, 10, 4)), 2, AggregateMax, []float64{3, 10}},
+		{"max of negatives", at(0, -5, -2, -9), 1, AggregateMax, []float64{-2}},
+		{"empty bucket is null", join(at(0, 1), at(150, 2)), 3, AggregateAvg, []float64{1, none, 2}},
+		{"bucket start belongs to the bucket", join(at(59, 1), at(60, 5)), 2, AggregateAvg, []float64{1, 5}},
+		{"samples outside the range dropped", join(at(-1, 100), at(30, 1), at(120, 100)), 2, AggregateAvg, []float64{1, none}},
+		{"p95 of one sample", at(0, 7), 1, AggregateP95, []float64{7}},
+		{"p95 of 10 samples is the max", series(0, 10), 1, AggregateP95, []float64{10}},
+		{"p95 of 20 samples is the 19th", series(0, 20), 1, AggregateP95, []float64{19}},
+		{"p95 of 21 samples is the 20th", series(0, 21), 1, AggregateP95, []float64{20}},
+		{"p95 of 100 samples is the 95th", series(0, 100), 1, AggregateP95, []float64{95}},
+		{"p95 per bucket", join(series(0, 20), series(60, 10)), 2, AggregateP95, []float64{19, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := bucketize(tt.rows, from, time.Minute, tt.buckets, tt.aggregation)
+			if len(values) != len(tt.want) {
+				t.Fatalf("got %d buckets, want %d", len(values), len(tt.want))
+			}
+			for i, want := range tt.want {
+				switch {
+				case want == none && values[i] != nil:
+					t.Errorf("bucket %d = %v, want null", i, *values[i])
+				case want != none && values[i] == nil:
+					t.Errorf("bucket %d = null, want %v", i, want)
+				case want != none && *values[i] != want:
+					t.Errorf("bucket %d = %v, want %v", i, *values[i], want)
+				}
+			}
+		})
+	}
+}
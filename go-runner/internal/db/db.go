@@ -3,69 +3,222 @@ package db
 import (
 	"fmt"
 	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
+	"go-runner/internal/archive"
+	"go-runner/internal/ci"
 	"go-runner/internal/config"
+	"go-runner/internal/dashboard"
+	"go-runner/internal/events"
+	"go-runner/internal/jobs"
+	"go-runner/internal/maintenance"
 	"go-runner/internal/project"
 	"go-runner/internal/system"
+	"go-runner/internal/tables"
+	"go-runner/internal/tokens"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 	_ "modernc.org/sqlite"
 )
 
-func InitDB(cfg *config.Config) *gorm.DB {
-	var db *gorm.DB
-	var err error
+// Open connects to the database of the configuration, without migrating it,
+// retrying with backoff while the server is unreachable
+func Open(cfg *config.Config) (*gorm.DB, error) {
+	// Every table is named with the prefix, for models and raw queries alike
+	if err := tables.SetPrefix(cfg.Database.TablePrefix); err != nil {
+		return nil, err
+	}
 
 	// Configure GORM logger
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		NamingStrategy: schema.NamingStrategy{TablePrefix: tables.Prefix()},
 	}
 
-	switch cfg.Database.Driver {
+	retry := newRetryPlugin(cfg.Database)
+	var db *gorm.DB
+	backoff := retry.backoff
+	for attempt := 0; ; attempt++ {
+		dialector, err := dialectorFor(cfg.Database, cfg.Database.Host, cfg.Database.Port)
+		if err != nil {
+			return nil, err
+		}
+		db, err = gorm.Open(dialector, gormConfig)
+		if err == nil {
+			break
+		}
+		if attempt >= retry.retries || !isTransient(err) {
+			return nil, err
+		}
+		log.Printf("⚠️  Database unavailable (%v), retrying in %s", err, backoff)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, retry.maxBackoff)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(time.Duration(cfg.Database.ConnMaxLifetime) * time.Second)
+		sqlDB.SetConnMaxIdleTime(time.Duration(cfg.Database.ConnMaxIdleTime) * time.Second)
+	}
+	if err := db.Use(retry); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// dialectorFor returns the dialector of the driver for a server of the
+// configuration, the primary or a replica
+func dialectorFor(cfg config.DatabaseConfig, host string, port int) (gorm.Dialector, error) {
+	switch cfg.Driver {
 	case "sqlite":
-		db, err = gorm.Open(sqlite.Open(cfg.Database.Path), gormConfig)
+		return sqlite.Open(cfg.Path), nil
 	case "postgres":
 		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
-			cfg.Database.Host,
-			cfg.Database.Username,
-			cfg.Database.Password,
-			cfg.Database.DBName,
-			cfg.Database.Port,
-			cfg.Database.SSLMode,
+			host,
+			cfg.Username,
+			cfg.Password,
+			cfg.DBName,
+			port,
+			cfg.SSLMode,
 		)
-		db, err = gorm.Open(postgres.Open(dsn), gormConfig)
+		if cfg.StatementTimeout > 0 {
+			dsn += fmt.Sprintf(" statement_timeout=%d", cfg.StatementTimeout*1000)
+		}
+		return postgres.Open(dsn), nil
 	case "mysql":
 		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-			cfg.Database.Username,
-			cfg.Database.Password,
-			cfg.Database.Host,
-			cfg.Database.Port,
-			cfg.Database.DBName,
+			cfg.Username,
+			cfg.Password,
+			host,
+			port,
+			cfg.DBName,
 		)
-		db, err = gorm.Open(mysql.Open(dsn), gormConfig)
+		if cfg.StatementTimeout > 0 {
+			dsn += fmt.Sprintf("&max_execution_time=%d", cfg.StatementTimeout*1000)
+		}
+		return mysql.Open(dsn), nil
 	default:
-		log.Fatalf("unsupported database driver: %s", cfg.Database.Driver)
+		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	}
+}
+
+// useReplicas sends the reads outside transactions to the read replicas of
+// the configuration, and keeps the primary when they cannot be reached
+func useReplicas(db *gorm.DB, cfg config.DatabaseConfig) {
+	if len(cfg.Replicas) == 0 {
+		return
+	}
+	if cfg.Driver == "sqlite" {
+		log.Printf("⚠️  Read replicas are ignored with SQLite")
+		return
+	}
+
+	var replicas []gorm.Dialector
+	for _, replica := range cfg.Replicas {
+		host, port := replica, cfg.Port
+		if h, p, err := net.SplitHostPort(replica); err == nil {
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				log.Printf("⚠️  Invalid read replica %q: %v", replica, err)
+				return
+			}
+			host, port = h, n
+		}
+		dialector, _ := dialectorFor(cfg, host, port)
+		replicas = append(replicas, dialector)
 	}
 
+	resolver := dbresolver.Register(dbresolver.Config{Replicas: replicas, Policy: dbresolver.RandomPolicy{}}).
+		SetMaxOpenConns(cfg.MaxOpenConns).
+		SetMaxIdleConns(cfg.MaxIdleConns).
+		SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second).
+		SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second)
+	if err := db.Use(resolver); err != nil {
+		log.Printf("⚠️  Read replicas disabled, reading from the primary: %v", err)
+		return
+	}
+	log.Printf("✅ Reading from %d replica(s)", len(replicas))
+}
+
+// InitDB connects to the database and migrates its schema
+func InitDB(cfg *config.Config) *gorm.DB {
+	db, err := Open(cfg)
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
 	// Auto migrate schemas
-	if err := db.AutoMigrate(
+	if err := db.AutoMigrate(models()...); err != nil {
+		log.Fatalf("failed to migrate database: %v", err)
+	}
+	dropLegacyIndexes(db)
+	project.MigrateLegacyPorts(db)
+	tables.SetProjectTables(projectTables(db))
+	// Replicas serve reads once the schema is up to date on the primary
+	useReplicas(db, cfg.Database)
+
+	log.Printf("✅ Database connected successfully (%s)", cfg.Database.Driver)
+	if cfg.Database.Workspace != "" || tables.Prefix() != "" {
+		log.Printf("   Workspace %q, table prefix %q", cfg.Database.Workspace, tables.Prefix())
+	}
+	return db
+}
+
+// models are the migrated tables
+func models() []interface{} {
+	return []interface{}{
 		&project.ProjectGroup{}, 
 		&project.Project{},
+		&project.ProjectPort{},
+		&project.DependencyAudit{},
+		&project.TestRun{},
+		&project.SmokeTestRun{},
+		&project.ProjectMonitor{},
+		&project.MonitorCheck{},
+		&project.ProjectProfile{},
+		&project.ProjectAnnotation{},
+		&project.QueueMetric{},
+		&project.TrafficMetric{},
+		&project.ProcessMetric{},
+		&project.ProjectStatusHistory{},
+		&project.OnboardingSession{},
+		&project.WorkspaceSpec{},
+		&jobs.Job{},
+		&events.AuditEvent{},
+		&maintenance.Window{},
+		&tokens.APIToken{},
+		&ci.Status{},
+		&dashboard.Dashboard{},
+		&archive.Archive{},
 		&system.SystemMetrics{},
 		&system.SystemAlert{},
 		&system.SystemConfig{},
-	); err != nil {
-		log.Fatalf("failed to migrate database: %v", err)
+		&system.ConnectivityTarget{},
 	}
+}
 
-	log.Printf("✅ Database connected successfully (%s)", cfg.Database.Driver)
-	return db
+// projectTables returns the tables of the models with a project_id column
+func projectTables(db *gorm.DB) []string {
+	var names []string
+	for _, model := range models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			log.Printf("⚠️  Failed to parse model %T: %v", model, err)
+			continue
+		}
+		if stmt.Schema.LookUpField("project_id") != nil {
+			names = append(names, strings.TrimPrefix(stmt.Schema.Table, tables.Prefix()))
+		}
+	}
+	return names
 }
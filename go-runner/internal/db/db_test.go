@@ -0,0 +1,49 @@
+package db
+
+import (
+	"slices"
+	"testing"
+
+	"go-runner/internal/tables"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func TestProjectTables(t *testing.T) {
+	for _, prefix := range []string{"", "ws_"} {
+		t.Run("prefix "+prefix, func(t *testing.T) {
+			if err := tables.SetPrefix(prefix); err != nil {
+				t.Fatal(err)
+			}
+			defer tables.SetPrefix("")
+
+			db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+				Logger:         logger.Default.LogMode(logger.Silent),
+				NamingStrategy: schema.NamingStrategy{TablePrefix: tables.Prefix()},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := projectTables(db)
+			for _, table := range []string{
+				"project_ports", "dependency_audits", "test_runs", "smoke_test_runs",
+				"project_monitors", "monitor_checks", "project_profiles", "project_annotations",
+				"queue_metrics", "traffic_metrics", "process_metrics", "project_status_histories",
+				"jobs", "audit_events", "api_tokens", "ci_statuses", "log_archives",
+			} {
+				if !slices.Contains(names, table) {
+					t.Errorf("projectTables() = %q, lacks %s", names, table)
+				}
+			}
+			for _, table := range []string{"projects", "project_groups", "system_metrics"} {
+				if slices.Contains(names, table) {
+					t.Errorf("projectTables() = %q, has %s without project_id", names, table)
+				}
+			}
+		})
+	}
+}
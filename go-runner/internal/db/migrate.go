@@ -0,0 +1,209 @@
+package db
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"go-runner/internal/config"
+	"go-runner/internal/project"
+	"go-runner/internal/tables"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const usage = `Usage: go-runner db migrate [flags]
+
+Move existing data to the table prefix or workspace of the configuration
+(database.table_prefix, database.workspace) before starting the server on it:
+the tables named with --from-prefix are renamed with their indexes, and with
+--from-file a SQLite database is first copied to the file of the workspace.
+
+Flags:
+`
+
+// legacyIndexes are composite indexes named before table prefixes, now
+// named after their table
+var legacyIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	{&project.ProjectAnnotation{}, "idx_annotations_lookup"},
+	{&project.ProjectStatusHistory{}, "idx_status_history_lookup"},
+}
+
+// dropLegacyIndexes drops the composite indexes replaced by those named
+// after their table
+func dropLegacyIndexes(db *gorm.DB) {
+	for _, index := range legacyIndexes {
+		if db.Migrator().HasIndex(index.model, index.name) {
+			if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+				log.Printf("⚠️  Failed to drop legacy index %s: %v", index.name, err)
+			}
+		}
+	}
+}
+
+// RunCLI handles `go-runner db ...` and returns the process exit code
+func RunCLI(args []string) int {
+	fs := flag.NewFlagSet("go-runner db migrate", flag.ContinueOnError)
+	fromPrefix := fs.String("from-prefix", "", "Table prefix the data is stored with now")
+	fromFile := fs.String("from-file", "", "SQLite database to copy to the workspace file, e.g. ./data/project.db")
+	dryRun := fs.Bool("dry-run", false, "Print what would be moved without changing anything")
+	fs.Usage = func() { printUsage(os.Stderr, fs) }
+
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage(os.Stdout, fs)
+		return 0
+	}
+	if args[0] != "migrate" {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n", args[0])
+		printUsage(os.Stderr, fs)
+		return 2
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	cfg := config.Load()
+	if err := migrateData(cfg, *fromPrefix, *fromFile, *dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprint(w, usage)
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
+
+// migrateData copies a SQLite file to the workspace file when fromFile is
+// set, then renames the tables named with fromPrefix to the configured prefix
+func migrateData(cfg *config.Config, fromPrefix, fromFile string, dryRun bool) error {
+	if fromFile != "" {
+		if cfg.Database.Driver != "sqlite" {
+			return errors.New("--from-file needs the sqlite driver")
+		}
+		if fromFile == cfg.Database.Path {
+			return fmt.Errorf("%s is already the database of the configuration", fromFile)
+		}
+		if _, err := os.Stat(cfg.Database.Path); err == nil {
+			return fmt.Errorf("%s already exists, remove it to copy %s over it", cfg.Database.Path, fromFile)
+		}
+		fmt.Printf("Copy %s to %s\n", fromFile, cfg.Database.Path)
+		if !dryRun {
+			if err := copySQLite(fromFile, cfg.Database.Path); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tables.Validate(fromPrefix); err != nil {
+		return err
+	}
+	if dryRun && fromFile != "" {
+		if fromPrefix != cfg.Database.TablePrefix {
+			fmt.Printf("Rename the tables of the copy from prefix %q to %q\n", fromPrefix, cfg.Database.TablePrefix)
+		}
+		return nil
+	}
+
+	db, err := Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect database: %w", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+	prefix := tables.Prefix()
+	moved := 0
+	for _, model := range models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		table := stmt.Schema.Table
+		old := fromPrefix + strings.TrimPrefix(table, prefix)
+		if old == table || !db.Migrator().HasTable(old) {
+			continue
+		}
+		if db.Migrator().HasTable(table) {
+			var count int64
+			db.Table(table).Count(&count)
+			if count > 0 {
+				fmt.Printf("⚠️  Skip %s: %s already has %d rows\n", old, table, count)
+				continue
+			}
+			fmt.Printf("Drop empty %s\n", table)
+			if !dryRun {
+				if err := db.Migrator().DropTable(table); err != nil {
+					return fmt.Errorf("drop %s: %w", table, err)
+				}
+			}
+		}
+
+		fmt.Printf("Rename %s to %s\n", old, table)
+		if dryRun {
+			moved++
+			continue
+		}
+		if err := db.Migrator().RenameTable(old, table); err != nil {
+			return fmt.Errorf("rename %s: %w", old, err)
+		}
+		if err := renameIndexes(db, old, table); err != nil {
+			return err
+		}
+		moved++
+	}
+
+	if moved == 0 {
+		fmt.Printf("Nothing to move: no table named with prefix %q\n", fromPrefix)
+	} else if !dryRun {
+		fmt.Printf("✅ Moved %d table(s) to prefix %q\n", moved, prefix)
+	}
+	return nil
+}
+
+// renameIndexes renames the indexes of a renamed table that are named after
+// it, since index names must be unique in the whole database
+func renameIndexes(db *gorm.DB, old, table string) error {
+	indexes, err := db.Migrator().GetIndexes(table)
+	if err != nil {
+		return fmt.Errorf("list indexes of %s: %w", table, err)
+	}
+	for _, index := range indexes {
+		name := index.Name()
+		for _, kind := range []string{"idx_", "uni_"} {
+			if rest, ok := strings.CutPrefix(name, kind+old+"_"); ok {
+				if err := db.Migrator().RenameIndex(table, name, kind+table+"_"+rest); err != nil {
+					return fmt.Errorf("rename index %s: %w", name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// copySQLite writes a consistent copy of a SQLite database to a new file
+func copySQLite(from, to string) error {
+	if _, err := os.Stat(from); err != nil {
+		return err
+	}
+	source, err := gorm.Open(sqlite.Open(from), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return fmt.Errorf("open %s: %w", from, err)
+	}
+	if sqlDB, err := source.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	if err := source.Exec("VACUUM INTO ?", to).Error; err != nil {
+		return fmt.Errorf("copy %s: %w", from, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-runner/internal/config"
+
+	"gorm.io/gorm"
+)
+
+// transientMessages are errors of a dropped, refused or busy connection,
+// for drivers that do not wrap a typed error
+var transientMessages = []string{
+	"bad connection",
+	"invalid connection",
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"server closed the connection",
+	"terminating connection",
+	"the database system is starting up",
+	"the database system is shutting down",
+	"the database system is in recovery mode",
+	"too many connections",
+	"too many clients",
+	"database is locked",
+}
+
+// retryPlugin retries reads and the begin of transactions that fail on a
+// transient error, with exponential backoff. Other writes are not retried,
+// since they may have been applied before the connection dropped, nor are
+// statements inside a transaction, whose connection is gone.
+type retryPlugin struct {
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func newRetryPlugin(cfg config.DatabaseConfig) *retryPlugin {
+	return &retryPlugin{
+		retries:    max(cfg.Retries, 0),
+		backoff:    max(time.Duration(cfg.RetryBackoff)*time.Millisecond, 10*time.Millisecond),
+		maxBackoff: max(time.Duration(cfg.MaxRetryBackoff)*time.Millisecond, 10*time.Millisecond),
+	}
+}
+
+func (p *retryPlugin) Name() string {
+	return "go-runner:retry"
+}
+
+func (p *retryPlugin) Initialize(db *gorm.DB) error {
+	if p.retries == 0 {
+		return nil
+	}
+	query := db.Callback().Query()
+	if err := query.Replace("gorm:query", p.wrap(query.Get("gorm:query"))); err != nil {
+		return err
+	}
+	create := db.Callback().Create()
+	if err := create.Replace("gorm:begin_transaction", p.wrap(create.Get("gorm:begin_transaction"))); err != nil {
+		return err
+	}
+	update := db.Callback().Update()
+	if err := update.Replace("gorm:begin_transaction", p.wrap(update.Get("gorm:begin_transaction"))); err != nil {
+		return err
+	}
+	del := db.Callback().Delete()
+	return del.Replace("gorm:begin_transaction", p.wrap(del.Get("gorm:begin_transaction")))
+}
+
+// wrap runs a callback again while it fails on a transient error outside a
+// transaction
+func (p *retryPlugin) wrap(next func(*gorm.DB)) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil {
+			next(db)
+			return
+		}
+
+		backoff := p.backoff
+		for attempt := 1; ; attempt++ {
+			next(db)
+			if db.Error == nil || attempt > p.retries || !isTransient(db.Error) {
+				return
+			}
+			if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+				return
+			}
+
+			log.Printf("⚠️  Database error (%v), retry %d/%d in %s", db.Error, attempt, p.retries, backoff)
+			select {
+			case <-db.Statement.Context.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, p.maxBackoff)
+			db.Error = nil
+		}
+	}
+}
+
+// isTransient reports whether an error comes from the connection rather than
+// the statement, so that running it again may succeed
+func isTransient(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, transient := range transientMessages {
+		if strings.Contains(msg, transient) {
+			return true
+		}
+	}
+	return false
+}
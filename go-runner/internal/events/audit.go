@@ -0,0 +1,74 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+
+	"gorm.io/gorm"
+)
+
+// AuditEvent is an event kept in the audit log
+type AuditEvent struct {
+	ID        uint        `json:"id" gorm:"primarykey"`
+	Seq       uint64      `json:"seq"`
+	Type      string      `json:"type" gorm:"index"`
+	Category  string      `json:"category" gorm:"index"`
+	ProjectID uint        `json:"project_id,omitempty" gorm:"index"`
+	Data      interface{} `json:"data" gorm:"type:text;serializer:json"`
+	Time      time.Time   `json:"time" gorm:"index"`
+}
+
+// TableName keeps the audit log apart from other tables
+func (AuditEvent) TableName() string {
+	return tables.Name("audit_events")
+}
+
+// AuditSink stores events in the audit_events table, deleting those older
+// than the retention
+type AuditSink struct {
+	db        *gorm.DB
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewAuditSink creates a sink storing events for retention (forever when 0)
+func NewAuditSink(db *gorm.DB, retention time.Duration) *AuditSink {
+	return &AuditSink{db: db, retention: retention}
+}
+
+// Name identifies the sink in stats
+func (s *AuditSink) Name() string {
+	return "audit"
+}
+
+// Handle stores the event, pruning the audit log at most once an hour
+func (s *AuditSink) Handle(event Event) error {
+	record := AuditEvent{
+		Seq:       event.Seq,
+		Type:      event.Type,
+		Category:  event.Category,
+		ProjectID: event.ProjectID,
+		Data:      event.Data,
+		Time:      event.Time,
+	}
+	if err := s.db.Create(&record).Error; err != nil {
+		return err
+	}
+
+	if s.retention > 0 {
+		s.mu.Lock()
+		prune := time.Since(s.lastPrune) > time.Hour
+		if prune {
+			s.lastPrune = time.Now()
+		}
+		s.mu.Unlock()
+		if prune {
+			s.db.Where("time < ?", time.Now().Add(-s.retention)).Delete(&AuditEvent{})
+		}
+	}
+	return nil
+}
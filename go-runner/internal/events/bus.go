@@ -0,0 +1,268 @@
+// Package events is the internal event bus: project lifecycle, alert,
+// configuration and job events are published once and delivered to every
+// sink subscribed to them (WebSocket hub, webhooks, audit log, NDJSON file)
+package events
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event categories
+const (
+	CategoryLifecycle = "lifecycle" // Project starts, stops, crashes, autostart, reconcile and annotations
+	CategoryAlert     = "alert"     // Alerts raised and resolved
+	CategoryConfig    = "config"    // Projects, groups and settings created, changed or deleted
+	CategoryJob       = "job"       // Finished jobs and their results
+	CategoryProgress  = "progress"  // Progress of running jobs and test runs
+	CategoryMetrics   = "metrics"   // Periodic checks: traffic, queues, databases, file descriptors...
+	CategorySystem    = "system"    // Host state such as the power mode
+)
+
+// categories maps event types to their category; types not listed are
+// system events
+var categories = map[string]string{
+	"status_update":         CategoryLifecycle,
+	"status_changed":        CategoryLifecycle,
+	"autostart":             CategoryLifecycle,
+	"autostart_complete":    CategoryLifecycle,
+	"reconcile":             CategoryLifecycle,
+	"reconcile_complete":    CategoryLifecycle,
+	"idle_stop":             CategoryLifecycle,
+	"annotation":            CategoryLifecycle,
+	"alert_raised":          CategoryAlert,
+	"alert_resolved":        CategoryAlert,
+	"project_created":       CategoryConfig,
+	"project_updated":       CategoryConfig,
+	"project_deleted":       CategoryConfig,
+	"group_created":         CategoryConfig,
+	"group_updated":         CategoryConfig,
+	"group_deleted":         CategoryConfig,
+	"system_config_updated": CategoryConfig,
+	"job_update":            CategoryJob,
+	"test_result":           CategoryJob,
+	"onboarding_update":     CategoryJob,
+	"ci_status":             CategoryJob,
+	"test_progress":         CategoryProgress,
+	"database_health":       CategoryMetrics,
+	"traffic_update":        CategoryMetrics,
+	"queue_update":          CategoryMetrics,
+	"fd_update":             CategoryMetrics,
+	"kubernetes_status":     CategoryMetrics,
+	"systemd_status":        CategoryMetrics,
+	"ssh_status":            CategoryMetrics,
+	"node_status":           CategorySystem,
+	"file_change":           CategoryMetrics,
+	"orphans":               CategoryMetrics,
+}
+
+// CategoryOf returns the category of an event type
+func CategoryOf(eventType string) string {
+	if category, ok := categories[eventType]; ok {
+		return category
+	}
+	return CategorySystem
+}
+
+// queueSize is the number of events an asynchronous sink can fall behind
+// before events are dropped for it
+const queueSize = 1024
+
+// Event is something that happened in go-runner
+type Event struct {
+	Seq       uint64      `json:"seq"` // Increases with every event since the server started
+	Type      string      `json:"type"`
+	Category  string      `json:"category"`
+	ProjectID uint        `json:"project_id,omitempty"` // 0 for events not about one project
+	Data      interface{} `json:"data"`
+	Time      time.Time   `json:"time"`
+	// Catalog entry of the project, on alert events about one
+	Project *ProjectInfo `json:"project,omitempty"`
+}
+
+// Sink receives the events it subscribed to
+type Sink interface {
+	Name() string
+	Handle(event Event) error
+}
+
+// SinkOptions select the events a sink receives: those of the listed
+// categories or types, or every event when both are empty
+type SinkOptions struct {
+	Categories []string
+	Types      []string
+	// Handle events in Publish instead of a queue; for sinks that never
+	// block, such as the WebSocket hub
+	Sync bool
+}
+
+// SinkStats reports the deliveries of a sink
+type SinkStats struct {
+	Name       string   `json:"name"`
+	Categories []string `json:"categories,omitempty"`
+	Types      []string `json:"types,omitempty"`
+	Delivered  uint64   `json:"delivered"`
+	Failed     uint64   `json:"failed"`
+	Dropped    uint64   `json:"dropped"` // Events skipped because the sink fell behind
+	LastError  string   `json:"last_error,omitempty"`
+}
+
+type subscription struct {
+	sink       Sink
+	opts       SinkOptions
+	categories map[string]bool
+	types      map[string]bool
+	queue      chan Event
+
+	delivered atomic.Uint64
+	failed    atomic.Uint64
+	dropped   atomic.Uint64
+	lastError atomic.Value // string
+}
+
+func (s *subscription) matches(event Event) bool {
+	if len(s.categories) == 0 && len(s.types) == 0 {
+		return true
+	}
+	return s.categories[event.Category] || s.types[event.Type]
+}
+
+func (s *subscription) handle(event Event) {
+	if err := s.sink.Handle(event); err != nil {
+		s.failed.Add(1)
+		s.lastError.Store(err.Error())
+		log.Printf("Event sink %s failed on %s: %v", s.sink.Name(), event.Type, err)
+		return
+	}
+	s.delivered.Add(1)
+}
+
+// Bus delivers published events to the subscribed sinks
+type Bus struct {
+	mu          sync.RWMutex
+	subs        []*subscription
+	seq         atomic.Uint64
+	projectInfo func(projectID uint) *ProjectInfo
+}
+
+// NewBus creates an event bus without sinks
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// SetProjectInfo sets how the catalog entries of projects are looked up for
+// their alert events
+func (b *Bus) SetProjectInfo(lookup func(projectID uint) *ProjectInfo) {
+	b.mu.Lock()
+	b.projectInfo = lookup
+	b.mu.Unlock()
+}
+
+// Subscribe adds a sink. Asynchronous sinks get a queue and a goroutine, so
+// a slow webhook does not hold up the publisher.
+func (b *Bus) Subscribe(sink Sink, opts SinkOptions) {
+	sub := &subscription{
+		sink:       sink,
+		opts:       opts,
+		categories: make(map[string]bool),
+		types:      make(map[string]bool),
+	}
+	for _, category := range opts.Categories {
+		sub.categories[category] = true
+	}
+	for _, eventType := range opts.Types {
+		sub.types[eventType] = true
+	}
+	if !opts.Sync {
+		sub.queue = make(chan Event, queueSize)
+		go func() {
+			for event := range sub.queue {
+				sub.handle(event)
+			}
+		}()
+	}
+
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+}
+
+// Publish publishes an event about a project, or about go-runner itself
+// when projectID is 0, in the category of its type
+func (b *Bus) Publish(projectID uint, eventType string, data interface{}) {
+	b.PublishEvent(Event{Type: eventType, ProjectID: projectID, Data: data})
+}
+
+// PublishEvent publishes an event, filling in its sequence number, time and,
+// when empty, category and the catalog entry of the project of alerts
+func (b *Bus) PublishEvent(event Event) {
+	if b == nil {
+		return
+	}
+	event.Seq = b.seq.Add(1)
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Category == "" {
+		event.Category = CategoryOf(event.Type)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if event.Category == CategoryAlert && event.ProjectID != 0 && event.Project == nil && b.projectInfo != nil {
+		event.Project = b.projectInfo(event.ProjectID)
+	}
+	for _, sub := range b.subs {
+		if !sub.matches(event) {
+			continue
+		}
+		if sub.queue == nil {
+			sub.handle(event)
+			continue
+		}
+		select {
+		case sub.queue <- event:
+		default:
+			if sub.dropped.Add(1)%100 == 1 {
+				log.Printf("Event sink %s is falling behind, dropping events", sub.sink.Name())
+			}
+		}
+	}
+}
+
+// Sinks returns the subscribed sinks
+func (b *Bus) Sinks() []Sink {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	sinks := make([]Sink, 0, len(b.subs))
+	for _, sub := range b.subs {
+		sinks = append(sinks, sub.sink)
+	}
+	return sinks
+}
+
+// Stats reports the deliveries of every sink
+func (b *Bus) Stats() []SinkStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make([]SinkStats, 0, len(b.subs))
+	for _, sub := range b.subs {
+		s := SinkStats{
+			Name:       sub.sink.Name(),
+			Categories: sub.opts.Categories,
+			Types:      sub.opts.Types,
+			Delivered:  sub.delivered.Load(),
+			Failed:     sub.failed.Load(),
+			Dropped:    sub.dropped.Load(),
+		}
+		if err, ok := sub.lastError.Load().(string); ok {
+			s.LastError = err
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
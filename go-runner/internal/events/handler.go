@@ -0,0 +1,192 @@
+package events
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler serves the audit log, sink stats and alert integration tests
+type Handler struct {
+	db  *gorm.DB
+	bus *Bus
+}
+
+// RegisterRoutes registers the event routes
+func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB, bus *Bus) {
+	h := &Handler{db: db, bus: bus}
+
+	events := r.Group("/events")
+	{
+		events.GET("", h.GetEvents)
+		events.GET("/sinks", h.GetSinks)
+		events.POST("/integrations/test", h.TestIntegrations)
+	}
+}
+
+// GetEvents godoc
+// @Summary      Query the audit log
+// @Description  List events recorded by the audit sink, newest first: project lifecycle, alerts, configuration changes and finished jobs (events.audit_categories)
+// @Tags         events
+// @Produce      json
+// @Param        category    query     string  false  "lifecycle, alert, config, job, progress, metrics or system"
+// @Param        type        query     string  false  "Event type, e.g. status_changed"
+// @Param        project_id  query     int     false  "Only events of this project"
+// @Param        since       query     string  false  "Only events after this time (RFC 3339)"
+// @Param        limit       query     int     false  "Maximum events (default 100, max 1000)"
+// @Success      200         {object}  types.DataResponse{data=[]AuditEvent}
+// @Failure      400         {object}  middleware.ErrorResponse  "Invalid filter"
+// @Router       /events [get]
+func (h *Handler) GetEvents(c *gin.Context) {
+	query := h.db.Order("id desc")
+	if category := c.Query("category"); category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if eventType := c.Query("type"); eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid project_id", raw))
+			return
+		}
+		query = query.Where("project_id = ?", id)
+	}
+	if raw := c.Query("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid since", err.Error()))
+			return
+		}
+		query = query.Where("time > ?", since)
+	}
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 1000 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "limit must be between 1 and 1000", raw))
+			return
+		}
+		limit = n
+	}
+
+	events := []AuditEvent{}
+	if err := query.Limit(limit).Find(&events).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch events", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: events})
+}
+
+// GetSinks godoc
+// @Summary      List event sinks
+// @Description  List the sinks of the event bus with the events they receive and their delivered, failed and dropped counts
+// @Tags         events
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]SinkStats}
+// @Router       /events/sinks [get]
+func (h *Handler) GetSinks(c *gin.Context) {
+	c.JSON(http.StatusOK, types.DataResponse{Data: h.bus.Stats()})
+}
+
+// TestFireRequest selects the alert integrations a test alert is sent to
+type TestFireRequest struct {
+	Integration string `json:"integration" example:"pagerduty staging"` // Sink name, every integration when empty
+	Level       string `json:"level" example:"critical"`                // Level of the test alert, critical when empty
+	KeepOpen    bool   `json:"keep_open"`                               // Leave the test incident open instead of resolving it right away
+}
+
+// TestFireResult is the outcome of a test alert at one integration
+type TestFireResult struct {
+	Integration string `json:"integration"`
+	Status      string `json:"status"` // sent, skipped (level not routed to the integration) or failed
+	Resolved    bool   `json:"resolved"`
+	Error       string `json:"error,omitempty"`
+}
+
+// TestIntegrations godoc
+// @Summary      Test-fire alert integrations
+// @Description  Send a test alert to the PagerDuty and Opsgenie integrations (events.pagerduty, events.opsgenie) routing its level, and resolve it right away unless keep_open is set. The alert has type "test" and the same dedup key on every fire, so repeated tests update one incident.
+// @Tags         events
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TestFireRequest  false  "Integration and level"
+// @Success      200      {object}  types.DataResponse{data=[]TestFireResult}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid level"
+// @Failure      404      {object}  middleware.ErrorResponse  "Integration not found"
+// @Router       /events/integrations/test [post]
+func (h *Handler) TestIntegrations(c *gin.Context) {
+	var req TestFireRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+			return
+		}
+	}
+	if req.Level == "" {
+		req.Level = "critical"
+	}
+	if !alertLevels[req.Level] {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "level must be info, warning, error or critical", req.Level))
+		return
+	}
+
+	var integrations []AlertIntegration
+	for _, sink := range h.bus.Sinks() {
+		if integration, ok := sink.(AlertIntegration); ok && (req.Integration == "" || sink.Name() == req.Integration) {
+			integrations = append(integrations, integration)
+		}
+	}
+	if len(integrations) == 0 {
+		if req.Integration != "" {
+			middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Integration not found", req.Integration))
+		} else {
+			middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "No alert integrations configured", "Add events.pagerduty or events.opsgenie to config.yaml"))
+		}
+		return
+	}
+
+	hostname, _ := os.Hostname()
+	alert := Alert{
+		Type:     "test",
+		Level:    req.Level,
+		Message:  "Test alert from go-runner on " + hostname,
+		Resource: "test-fire",
+	}
+	raised := Event{Type: "alert_raised", Category: CategoryAlert, Data: alert, Time: time.Now()}
+
+	results := make([]TestFireResult, 0, len(integrations))
+	for _, integration := range integrations {
+		result := TestFireResult{Integration: integration.Name(), Status: "sent"}
+		if !integration.Routes(req.Level) {
+			result.Status = "skipped"
+			results = append(results, result)
+			continue
+		}
+		if err := integration.Handle(raised); err != nil {
+			result.Status, result.Error = "failed", err.Error()
+			results = append(results, result)
+			continue
+		}
+		if !req.KeepOpen {
+			resolved := Event{Type: "alert_resolved", Category: CategoryAlert, Data: alert, Time: time.Now()}
+			if err := integration.Handle(resolved); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Resolved = true
+			}
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: results})
+}
@@ -0,0 +1,213 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// AlertTypes are the event types alert integrations subscribe to
+var AlertTypes = []string{"alert_raised", "alert_resolved"}
+
+// alertLevels are the levels of system alerts
+var alertLevels = map[string]bool{"info": true, "warning": true, "error": true, "critical": true}
+
+// Alert is the alert carried by "alert_raised" and "alert_resolved" events,
+// the fields of a system alert the integrations use
+type Alert struct {
+	ID        uint    `json:"id"`
+	Type      string  `json:"type"`
+	Level     string  `json:"level"`
+	Message   string  `json:"message"`
+	Resource  string  `json:"resource,omitempty"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+}
+
+// ProjectInfo is the catalog entry of the project of an alert event, so
+// whoever sees the alert knows whom to contact and where to look
+type ProjectInfo struct {
+	Name          string `json:"name"`
+	Owner         string `json:"owner,omitempty"`
+	Team          string `json:"team,omitempty"`
+	RepositoryURL string `json:"repository_url,omitempty"`
+	DocsURL       string `json:"docs_url,omitempty"`
+	ChatChannel   string `json:"chat_channel,omitempty"`
+}
+
+// details returns the fields of the entry that are set, by their JSON name
+func (p *ProjectInfo) details() map[string]string {
+	details := make(map[string]string)
+	if p == nil {
+		return details
+	}
+	for key, value := range map[string]string{
+		"project":        p.Name,
+		"owner":          p.Owner,
+		"team":           p.Team,
+		"repository_url": p.RepositoryURL,
+		"docs_url":       p.DocsURL,
+		"chat_channel":   p.ChatChannel,
+	} {
+		if value != "" {
+			details[key] = value
+		}
+	}
+	return details
+}
+
+// contacts describes the entry in a few lines for alert descriptions, empty
+// when the project has no catalog fields
+func (p *ProjectInfo) contacts() string {
+	if p == nil {
+		return ""
+	}
+	var lines []string
+	for _, field := range [][2]string{
+		{"Owner", p.Owner},
+		{"Team", p.Team},
+		{"Chat", p.ChatChannel},
+		{"Repository", p.RepositoryURL},
+		{"Docs", p.DocsURL},
+	} {
+		if field[1] != "" {
+			lines = append(lines, field[0]+": "+field[1])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// decodeAlert reads the alert of an event; the events package cannot use
+// the system alert type itself
+func decodeAlert(data interface{}) (Alert, error) {
+	var alert Alert
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return alert, err
+	}
+	if err := json.Unmarshal(raw, &alert); err != nil {
+		return alert, fmt.Errorf("event data is not an alert: %w", err)
+	}
+	return alert, nil
+}
+
+// AlertIntegration is a sink paging an on-call service for alerts
+type AlertIntegration interface {
+	Sink
+	// Routes reports whether alerts of the level are sent to the service
+	Routes(level string) bool
+}
+
+// alertRouting holds what PagerDuty and Opsgenie sinks share: the alert
+// levels they are sent and the host they are sent from
+type alertRouting struct {
+	levels map[string]bool
+	source string
+}
+
+func newAlertRouting(levels []string) (alertRouting, error) {
+	routing := alertRouting{levels: make(map[string]bool), source: "go-runner"}
+	for _, level := range levels {
+		if !alertLevels[level] {
+			return routing, fmt.Errorf("invalid alert level %q, expected info, warning, error or critical", level)
+		}
+		routing.levels[level] = true
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		routing.source = hostname
+	}
+	return routing, nil
+}
+
+// Routes reports whether alerts of the level are sent
+func (r alertRouting) Routes(level string) bool {
+	return len(r.levels) == 0 || r.levels[level]
+}
+
+// dedupKey identifies an alert at the on-call service, so a flapping or
+// escalating alert updates one incident and its resolution closes it. It
+// is derived from the host, the alert type and the resource, not the
+// alert ID, so alerts raised again after a restart find their incident.
+func (r alertRouting) dedupKey(alert Alert, projectID uint) string {
+	parts := []string{"go-runner", r.source, alert.Type}
+	if projectID != 0 {
+		parts = append(parts, fmt.Sprintf("project-%d", projectID))
+	}
+	if alert.Resource != "" {
+		parts = append(parts, alert.Resource)
+	}
+	return strings.Join(parts, "/")
+}
+
+// postJSON posts body to url with the headers, retrying failed connections,
+// rate limits and 5xx responses like the webhook sink
+func postJSON(client *http.Client, url string, header http.Header, body []byte) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = postJSONOnce(client, url, header, body)
+		if err == nil || attempt == webhookAttempts {
+			return err
+		}
+		if _, permanent := err.(permanentError); permanent {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+}
+
+func postJSONOnce(client *http.Client, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-runner")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	// Both services explain rejected events in the body
+	var reply struct {
+		Message string          `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+	}
+	json.NewDecoder(resp.Body).Decode(&reply)
+	err = fmt.Errorf("%s answered %s", req.URL.Host, resp.Status)
+	if reply.Message != "" {
+		err = fmt.Errorf("%w: %s", err, reply.Message)
+	}
+	if len(reply.Errors) > 0 {
+		err = fmt.Errorf("%w %s", err, reply.Errors)
+	}
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return permanentError{err}
+}
+
+// truncate cuts s to max bytes on a rune boundary, as the services reject
+// longer fields
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - len("...")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
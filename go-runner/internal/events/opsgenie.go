@@ -0,0 +1,175 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// opsgenieURL is the Opsgenie API of the US instance
+const opsgenieURL = "https://api.opsgenie.com"
+
+// opsgeniePriorities are the default priorities of the alert levels
+var opsgeniePriorities = map[string]string{"critical": "P1", "error": "P2", "warning": "P3", "info": "P5"}
+
+// OpsgenieSink creates Opsgenie alerts for raised alerts and closes them
+// with the alerts, using the dedup key as Opsgenie alias
+type OpsgenieSink struct {
+	alertRouting
+	name       string
+	apiKey     string
+	url        string
+	priorities map[string]string
+	team       string
+	tags       []string
+	client     *http.Client
+}
+
+// OpsgenieOptions are the optional settings of an Opsgenie sink
+type OpsgenieOptions struct {
+	Levels     []string          // Alert levels sent, every level when empty
+	Priorities map[string]string // Priority (P1-P5) of alert levels, overriding the defaults
+	Team       string            // Team the alerts are routed to
+	Tags       []string
+	APIURL     string
+}
+
+// NewOpsgenieSink creates a sink sending alerts with the API key
+func NewOpsgenieSink(name, apiKey string, opts OpsgenieOptions) (*OpsgenieSink, error) {
+	if apiKey == "" {
+		return nil, errors.New("Opsgenie api_key is required")
+	}
+	routing, err := newAlertRouting(opts.Levels)
+	if err != nil {
+		return nil, err
+	}
+
+	priorities := make(map[string]string, len(opsgeniePriorities))
+	for level, priority := range opsgeniePriorities {
+		priorities[level] = priority
+	}
+	for level, priority := range opts.Priorities {
+		level, priority = strings.ToLower(level), strings.ToUpper(priority)
+		if !alertLevels[level] {
+			return nil, fmt.Errorf("invalid alert level %q in Opsgenie priorities", level)
+		}
+		if len(priority) != 2 || priority[0] != 'P' || priority[1] < '1' || priority[1] > '5' {
+			return nil, fmt.Errorf("invalid Opsgenie priority %q for %s, expected P1 to P5", priority, level)
+		}
+		priorities[level] = priority
+	}
+
+	apiURL := strings.TrimSuffix(opts.APIURL, "/")
+	if apiURL == "" {
+		apiURL = opsgenieURL
+	}
+	return &OpsgenieSink{
+		alertRouting: routing,
+		name:         name,
+		apiKey:       apiKey,
+		url:          apiURL,
+		priorities:   priorities,
+		team:         opts.Team,
+		tags:         opts.Tags,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name identifies the sink in stats and test fires
+func (s *OpsgenieSink) Name() string {
+	if s.name == "" {
+		return "opsgenie"
+	}
+	return "opsgenie " + s.name
+}
+
+type opsgenieResponder struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type opsgenieAlert struct {
+	Message     string              `json:"message"`
+	Alias       string              `json:"alias"`
+	Description string              `json:"description"`
+	Responders  []opsgenieResponder `json:"responders,omitempty"`
+	Tags        []string            `json:"tags"`
+	Details     map[string]string   `json:"details"`
+	Entity      string              `json:"entity,omitempty"`
+	Source      string              `json:"source"`
+	Priority    string              `json:"priority"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note"`
+}
+
+// Handle creates an alert for raised alerts of the routed levels and closes
+// it for every resolved alert, as the alert may have changed level since it
+// was raised
+func (s *OpsgenieSink) Handle(event Event) error {
+	alert, err := decodeAlert(event.Data)
+	if err != nil {
+		return err
+	}
+	alias := s.dedupKey(alert, event.ProjectID)
+
+	var endpoint string
+	var body interface{}
+	switch event.Type {
+	case "alert_raised":
+		if !s.Routes(alert.Level) {
+			return nil
+		}
+		endpoint = s.url + "/v2/alerts"
+		create := opsgenieAlert{
+			Message:     truncate(alert.Message, 130),
+			Alias:       truncate(alias, 512),
+			Description: alert.Message,
+			Tags:        append([]string{"go-runner", alert.Type}, s.tags...),
+			Details: map[string]string{
+				"level":     alert.Level,
+				"value":     fmt.Sprintf("%g", alert.Value),
+				"threshold": fmt.Sprintf("%g", alert.Threshold),
+			},
+			Entity:   alert.Resource,
+			Source:   s.source,
+			Priority: s.priorities[alert.Level],
+		}
+		if event.ProjectID != 0 {
+			create.Details["project_id"] = fmt.Sprint(event.ProjectID)
+		}
+		// Whom to contact, from the catalog entry of the project
+		for key, value := range event.Project.details() {
+			create.Details[key] = value
+		}
+		if contacts := event.Project.contacts(); contacts != "" {
+			create.Description = truncate(alert.Message+"\n\n"+contacts, 15000)
+		}
+		if s.team != "" {
+			create.Responders = []opsgenieResponder{{Name: s.team, Type: "team"}}
+		}
+		body = create
+	case "alert_resolved":
+		endpoint = s.url + "/v2/alerts/" + url.PathEscape(truncate(alias, 512)) + "/close?identifierType=alias"
+		body = opsgenieClose{Source: s.source, Note: "Resolved in go-runner"}
+	default:
+		return nil
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("Authorization", "GenieKey "+s.apiKey)
+	if err := postJSON(s.client, endpoint, header, raw); err != nil {
+		return fmt.Errorf("Opsgenie %s: %w", strings.TrimPrefix(event.Type, "alert_"), err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// pagerDutyURL is the endpoint of the PagerDuty Events API v2
+const pagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutySink triggers PagerDuty incidents for raised alerts and resolves
+// them with the alerts, through the Events API v2
+type PagerDutySink struct {
+	alertRouting
+	name       string
+	routingKey string
+	url        string
+	client     *http.Client
+}
+
+// NewPagerDutySink creates a sink sending alerts of the levels (every level
+// when empty) to the service of routingKey
+func NewPagerDutySink(name, routingKey string, levels []string, apiURL string) (*PagerDutySink, error) {
+	if routingKey == "" {
+		return nil, errors.New("PagerDuty routing_key is required")
+	}
+	routing, err := newAlertRouting(levels)
+	if err != nil {
+		return nil, err
+	}
+	if apiURL == "" {
+		apiURL = pagerDutyURL
+	}
+	return &PagerDutySink{
+		alertRouting: routing,
+		name:         name,
+		routingKey:   routingKey,
+		url:          apiURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name identifies the sink in stats and test fires
+func (s *PagerDutySink) Name() string {
+	if s.name == "" {
+		return "pagerduty"
+	}
+	return "pagerduty " + s.name
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // trigger or resolve
+	DedupKey    string            `json:"dedup_key"`
+	Client      string            `json:"client,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"` // Alert levels are PagerDuty severities
+	Timestamp     string                 `json:"timestamp"`
+	Component     string                 `json:"component,omitempty"`
+	Class         string                 `json:"class"`
+	CustomDetails map[string]interface{} `json:"custom_details"`
+}
+
+// Handle triggers an incident for raised alerts of the routed levels and
+// resolves it for every resolved alert, as the alert may have changed level
+// since it was raised
+func (s *PagerDutySink) Handle(event Event) error {
+	alert, err := decodeAlert(event.Data)
+	if err != nil {
+		return err
+	}
+
+	body := pagerDutyEvent{
+		RoutingKey: s.routingKey,
+		DedupKey:   s.dedupKey(alert, event.ProjectID),
+	}
+	switch event.Type {
+	case "alert_raised":
+		if !s.Routes(alert.Level) {
+			return nil
+		}
+		body.EventAction = "trigger"
+		body.Client = "go-runner"
+		body.Payload = &pagerDutyPayload{
+			Summary:   truncate(alert.Message, 1024),
+			Source:    s.source,
+			Severity:  alert.Level,
+			Timestamp: event.Time.Format(time.RFC3339),
+			Component: alert.Resource,
+			Class:     alert.Type,
+			CustomDetails: map[string]interface{}{
+				"alert_id":   alert.ID,
+				"value":      alert.Value,
+				"threshold":  alert.Threshold,
+				"project_id": event.ProjectID,
+			},
+		}
+		// Whom to contact, from the catalog entry of the project
+		for key, value := range event.Project.details() {
+			body.Payload.CustomDetails[key] = value
+		}
+		if event.Project != nil {
+			if event.Project.RepositoryURL != "" {
+				body.Links = append(body.Links, pagerDutyLink{Href: event.Project.RepositoryURL, Text: "Repository"})
+			}
+			if event.Project.DocsURL != "" {
+				body.Links = append(body.Links, pagerDutyLink{Href: event.Project.DocsURL, Text: "Docs"})
+			}
+		}
+	case "alert_resolved":
+		body.EventAction = "resolve"
+	default:
+		return nil
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	if err := postJSON(s.client, s.url, nil, raw); err != nil {
+		return fmt.Errorf("PagerDuty %s: %w", body.EventAction, err)
+	}
+	return nil
+}
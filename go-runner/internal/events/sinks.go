@@ -0,0 +1,188 @@
+package events
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-runner/internal/websocket"
+)
+
+// HubSink broadcasts events to WebSocket clients: project events to the
+// clients of the project, other events to every client
+type HubSink struct {
+	hub *websocket.Hub
+}
+
+// NewHubSink creates a sink broadcasting to the hub
+func NewHubSink(hub *websocket.Hub) *HubSink {
+	return &HubSink{hub: hub}
+}
+
+// Name identifies the sink in stats
+func (s *HubSink) Name() string {
+	return "websocket"
+}
+
+// Handle broadcasts the event data as a message of the event type
+func (s *HubSink) Handle(event Event) error {
+	if event.ProjectID != 0 {
+		s.hub.BroadcastToProject(event.ProjectID, event.Type, event.Data)
+	} else {
+		s.hub.BroadcastToAll(event.Type, event.Data)
+	}
+	return nil
+}
+
+// webhookAttempts is how often a delivery is tried before it fails
+const webhookAttempts = 3
+
+// WebhookSink posts events as JSON to a URL. With a secret, the body is
+// signed with HMAC-SHA256 in the X-Go-Runner-Signature header.
+type WebhookSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink posting to rawURL
+func NewWebhookSink(rawURL, secret string) (*WebhookSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", rawURL)
+	}
+	return &WebhookSink{url: rawURL, secret: secret, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// Name identifies the sink in stats, without the path that may hold a token
+func (s *WebhookSink) Name() string {
+	u, _ := url.Parse(s.url)
+	return "webhook " + u.Scheme + "://" + u.Host
+}
+
+// Handle posts the event, retrying failed connections and 5xx responses
+func (s *WebhookSink) Handle(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = s.post(event, body)
+		if err == nil || attempt == webhookAttempts {
+			return err
+		}
+		if _, permanent := err.(permanentError); permanent {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+}
+
+// permanentError is a delivery error retrying will not fix
+type permanentError struct{ error }
+
+func (s *WebhookSink) post(event Event, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-runner")
+	req.Header.Set("X-Go-Runner-Event", event.Type)
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(body)
+		req.Header.Set("X-Go-Runner-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	case resp.StatusCode >= 300:
+		return permanentError{fmt.Errorf("webhook answered %s", resp.Status)}
+	}
+	return nil
+}
+
+// FileSink appends events to a file as newline-delimited JSON, rotating it
+// to <file>.1 past maxSize bytes
+type FileSink struct {
+	path    string
+	maxSize int64
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileSink opens (or creates) the event log file
+func NewFileSink(path string, maxSize int64) (*FileSink, error) {
+	s := &FileSink{path: path, maxSize: maxSize}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Name identifies the sink in stats
+func (s *FileSink) Name() string {
+	return "file " + s.path
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file, s.size = file, info.Size()
+	return nil
+}
+
+// Handle writes the event as one line
+func (s *FileSink) Handle(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxSize > 0 && s.size+int64(len(line)) > s.maxSize && s.size > 0 {
+		s.file.Close()
+		if err := os.Rename(s.path, s.path+".1"); err != nil {
+			return err
+		}
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the job status API
+type Handler struct {
+	manager *Manager
+}
+
+// RegisterRoutes registers the job routes
+func RegisterRoutes(r *gin.RouterGroup, manager *Manager) {
+	h := &Handler{manager: manager}
+
+	jobs := r.Group("/jobs")
+	{
+		jobs.GET("", h.GetJobs)
+		jobs.GET("/:id", h.GetJob)
+		jobs.POST("/:id/cancel", h.CancelJob)
+	}
+}
+
+// GetJobs godoc
+// @Summary      List background jobs
+// @Description  List background jobs (package installs, imports, ...) newest first, without their output. Job updates are also broadcast to WebSocket clients as "job_update" messages.
+// @Tags         jobs
+// @Produce      json
+// @Param        type        query     string  false  "Job type (install, import)"
+// @Param        status      query     string  false  "Status (queued, running, succeeded, failed, cancelled)"
+// @Param        project_id  query     int     false  "Project ID"
+// @Param        limit       query     int     false  "Maximum number of jobs (default 50, max 500)"
+// @Success      200         {object}  types.DataResponse{data=[]Job}
+// @Failure      400         {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /jobs [get]
+func (h *Handler) GetJobs(c *gin.Context) {
+	filter := ListFilter{
+		Type:   c.Query("type"),
+		Status: Status(c.Query("status")),
+		Limit:  50,
+	}
+
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid project_id", raw))
+			return
+		}
+		projectID := uint(id)
+		filter.ProjectID = &projectID
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 500 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "limit must be between 1 and 500", raw))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	jobs, err := h.manager.List(filter)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch jobs", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: jobs})
+}
+
+// GetJob godoc
+// @Summary      Get a background job
+// @Description  Get a job with its progress, result and output
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {object}  types.DataResponse{data=Job}
+// @Failure      400  {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse  "Job not found"
+// @Router       /jobs/{id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	job, err := h.manager.Get(uint(id))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: job})
+}
+
+// CancelJob godoc
+// @Summary      Cancel a background job
+// @Description  Cancel a queued or running job. Running jobs turn "cancelled" once their work has stopped.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      202  {object}  types.DataResponse{data=Job}  "Cancellation requested"
+// @Failure      400  {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse  "Job not found"
+// @Failure      409  {object}  middleware.ErrorResponse  "Job already finished"
+// @Router       /jobs/{id}/cancel [post]
+func (h *Handler) CancelJob(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	job, err := h.manager.Cancel(uint(id))
+	if err != nil {
+		HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// HandleError maps job manager errors to API errors
+func HandleError(c *gin.Context, err error) {
+	var conflict *ConflictError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Job not found", nil))
+	case errors.Is(err, ErrNotActive):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Job already finished", nil))
+	case errors.Is(err, ErrQueueFull):
+		middleware.HandleError(c, middleware.NewError(http.StatusServiceUnavailable, "Too many queued jobs", nil))
+	case errors.As(err, &conflict):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "A job of this kind is already running", gin.H{"job_id": conflict.JobID}))
+	default:
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Job error", err.Error()))
+	}
+}
@@ -0,0 +1,467 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go-runner/internal/events"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxOutput bounds the output kept in a job record
+	maxOutput = 256 * 1024
+	// progressInterval throttles how often progress is saved and broadcast
+	progressInterval = 500 * time.Millisecond
+	// queueSize is the number of jobs that can wait for a worker
+	queueSize = 1024
+)
+
+var (
+	// ErrNotFound is returned for unknown job IDs
+	ErrNotFound = errors.New("job not found")
+	// ErrNotActive is returned when cancelling a finished job
+	ErrNotActive = errors.New("job is not queued or running")
+	// ErrQueueFull is returned when too many jobs are waiting for a worker
+	ErrQueueFull = errors.New("job queue is full")
+)
+
+// Func is the work of a job. It must return when ctx is cancelled; the value
+// it returns is stored as the job result.
+type Func func(ctx context.Context, run *Run) (interface{}, error)
+
+// Spec describes a job to submit
+type Spec struct {
+	Type      string
+	ProjectID *uint
+	Key       string // Optional; a second job with the same key is rejected while one is active
+	Message   string // Initial status message
+}
+
+// Options configures the worker pool and job retention
+type Options struct {
+	Workers    int           // Jobs running concurrently
+	Retention  time.Duration // Finished jobs older than this are deleted
+	MaxHistory int           // Finished jobs kept at most
+}
+
+// Manager runs jobs on a worker pool and records them in the database
+type Manager struct {
+	db     *gorm.DB
+	events *events.Bus
+	opts   Options
+
+	queue  chan *Run
+	mu     sync.Mutex
+	active map[uint]*Run   // Queued and running jobs by ID
+	keys   map[string]uint // Active job ID by key
+}
+
+// Run is the handle of an active job, passed to its Func to report progress
+type Run struct {
+	m      *Manager
+	fn     Func
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu         sync.Mutex
+	job        Job
+	output     []byte
+	lastUpdate time.Time
+}
+
+// NewManager creates a job manager and starts its workers. Jobs left queued or
+// running by a previous server process are marked failed.
+func NewManager(db *gorm.DB, bus *events.Bus, opts Options) *Manager {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+
+	m := &Manager{
+		db:     db,
+		events: bus,
+		opts:   opts,
+		queue:  make(chan *Run, queueSize),
+		active: make(map[uint]*Run),
+		keys:   make(map[string]uint),
+	}
+
+	m.failInterrupted()
+	for i := 0; i < opts.Workers; i++ {
+		go m.worker()
+	}
+	go m.retentionLoop()
+
+	return m
+}
+
+// Submit records a job and queues it for a worker
+func (m *Manager) Submit(spec Spec, fn Func) (*Job, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &Run{
+		m:      m,
+		fn:     fn,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		job: Job{
+			Type:      spec.Type,
+			ProjectID: spec.ProjectID,
+			Key:       spec.Key,
+			Status:    StatusQueued,
+			Message:   spec.Message,
+		},
+	}
+
+	m.mu.Lock()
+	if spec.Key != "" {
+		if id, ok := m.keys[spec.Key]; ok {
+			m.mu.Unlock()
+			cancel()
+			return nil, &ConflictError{Key: spec.Key, JobID: id}
+		}
+	}
+	if err := m.db.Create(&run.job).Error; err != nil {
+		m.mu.Unlock()
+		cancel()
+		return nil, fmt.Errorf("failed to record job: %v", err)
+	}
+
+	// Register before queueing so a worker always finds the job active
+	m.active[run.job.ID] = run
+	if spec.Key != "" {
+		m.keys[spec.Key] = run.job.ID
+	}
+
+	select {
+	case m.queue <- run:
+	default:
+		delete(m.active, run.job.ID)
+		delete(m.keys, spec.Key)
+		m.mu.Unlock()
+		cancel()
+		m.db.Model(&Job{}).Where("id = ?", run.job.ID).Updates(map[string]interface{}{
+			"status":      StatusFailed,
+			"error":       ErrQueueFull.Error(),
+			"finished_at": time.Now(),
+		})
+		return nil, ErrQueueFull
+	}
+	job := run.job
+	m.mu.Unlock()
+
+	m.announce(job)
+	return &job, nil
+}
+
+// Get returns a job, with the live state of active jobs
+func (m *Manager) Get(id uint) (*Job, error) {
+	m.mu.Lock()
+	run, ok := m.active[id]
+	m.mu.Unlock()
+	if ok {
+		job := run.snapshot()
+		return &job, nil
+	}
+
+	var job Job
+	if err := m.db.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List returns jobs matching the filter, newest first and without output
+func (m *Manager) List(filter ListFilter) ([]Job, error) {
+	query := m.db.Model(&Job{}).Omit("output").Order("id DESC")
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	jobs := []Job{}
+	if err := query.Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	// Active jobs may have progressed since their last save
+	m.mu.Lock()
+	for i := range jobs {
+		if run, ok := m.active[jobs[i].ID]; ok {
+			jobs[i] = run.snapshot()
+			jobs[i].Output = ""
+		}
+	}
+	m.mu.Unlock()
+	return jobs, nil
+}
+
+// Cancel requests cancellation of a queued or running job. Queued jobs are
+// cancelled right away; running jobs once their Func returns.
+func (m *Manager) Cancel(id uint) (*Job, error) {
+	m.mu.Lock()
+	run, ok := m.active[id]
+	m.mu.Unlock()
+	if !ok {
+		if _, err := m.Get(id); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotActive
+	}
+
+	run.cancel()
+
+	run.mu.Lock()
+	queued := run.job.Status == StatusQueued
+	if queued {
+		// The worker skips jobs that are no longer queued
+		run.job.Status = StatusCancelled
+	}
+	run.mu.Unlock()
+	if queued {
+		run.finish(StatusCancelled, nil, "cancelled before it started")
+		m.release(run)
+	}
+
+	job := run.snapshot()
+	return &job, nil
+}
+
+// Wait waits up to timeout for a job to finish and returns its latest state
+// and whether it finished
+func (m *Manager) Wait(ctx context.Context, id uint, timeout time.Duration) (*Job, bool) {
+	m.mu.Lock()
+	run, ok := m.active[id]
+	m.mu.Unlock()
+
+	if ok {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-run.done:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
+
+	job, err := m.Get(id)
+	if err != nil {
+		return nil, false
+	}
+	return job, job.Status.Finished()
+}
+
+// worker runs queued jobs until the process exits
+func (m *Manager) worker() {
+	for run := range m.queue {
+		m.execute(run)
+	}
+}
+
+// execute runs one job and records its outcome
+func (m *Manager) execute(run *Run) {
+	run.mu.Lock()
+	if run.job.Status != StatusQueued {
+		// Cancelled while waiting for a worker
+		run.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	run.job.Status = StatusRunning
+	run.job.StartedAt = &now
+	run.mu.Unlock()
+	defer m.release(run)
+	run.save(true)
+
+	result, err := func() (result interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("job panicked: %v", r)
+			}
+		}()
+		return run.fn(run.ctx, run)
+	}()
+
+	switch {
+	case run.ctx.Err() != nil:
+		run.finish(StatusCancelled, result, "cancelled")
+	case err != nil:
+		run.finish(StatusFailed, result, err.Error())
+	default:
+		run.finish(StatusSucceeded, result, "")
+	}
+}
+
+// release removes a finished job from the active set
+func (m *Manager) release(run *Run) {
+	m.mu.Lock()
+	delete(m.active, run.job.ID)
+	if run.job.Key != "" && m.keys[run.job.Key] == run.job.ID {
+		delete(m.keys, run.job.Key)
+	}
+	m.mu.Unlock()
+	run.cancel()
+	close(run.done)
+}
+
+// announce publishes a job update without its output: progress while the
+// job is active, a job event once it finished
+func (m *Manager) announce(job Job) {
+	job.Output = ""
+	event := events.Event{Type: "job_update", Category: events.CategoryProgress, Data: job}
+	if job.Status.Finished() {
+		event.Category = events.CategoryJob
+	}
+	if job.ProjectID != nil {
+		event.ProjectID = *job.ProjectID
+	}
+	m.events.PublishEvent(event)
+}
+
+// failInterrupted marks jobs of a previous server process as failed
+func (m *Manager) failInterrupted() {
+	result := m.db.Model(&Job{}).Where("status IN ?", []Status{StatusQueued, StatusRunning}).Updates(map[string]interface{}{
+		"status":      StatusFailed,
+		"error":       "interrupted by a server restart",
+		"finished_at": time.Now(),
+	})
+	if result.Error == nil && result.RowsAffected > 0 {
+		log.Printf("⚠️  Marked %d interrupted job(s) as failed", result.RowsAffected)
+	}
+}
+
+// retentionLoop deletes old finished jobs every hour
+func (m *Manager) retentionLoop() {
+	m.prune()
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.prune()
+	}
+}
+
+// prune applies the retention period and history limit to finished jobs
+func (m *Manager) prune() {
+	finished := []Status{StatusSucceeded, StatusFailed, StatusCancelled}
+
+	if m.opts.Retention > 0 {
+		m.db.Where("status IN ? AND finished_at < ?", finished, time.Now().Add(-m.opts.Retention)).Delete(&Job{})
+	}
+	if m.opts.MaxHistory > 0 {
+		var stale []uint
+		m.db.Model(&Job{}).Where("status IN ?", finished).Order("id DESC").Offset(m.opts.MaxHistory).Pluck("id", &stale)
+		if len(stale) > 0 {
+			m.db.Delete(&Job{}, stale)
+		}
+	}
+}
+
+// ID returns the job ID
+func (r *Run) ID() uint {
+	return r.job.ID
+}
+
+// Progress records the completion percentage and a status message. Updates
+// are saved and broadcast at most every 500ms.
+func (r *Run) Progress(percent int, message string) {
+	if percent < 0 {
+		percent = 0
+	} else if percent > 100 {
+		percent = 100
+	}
+
+	r.mu.Lock()
+	r.job.Progress = percent
+	if message != "" {
+		r.job.Message = message
+	}
+	r.mu.Unlock()
+	r.save(false)
+}
+
+// Log appends a line to the job output
+func (r *Run) Log(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.output = append(r.output, line...)
+	r.output = append(r.output, '\n')
+	if len(r.output) > 2*maxOutput {
+		r.output = append([]byte(nil), r.output[len(r.output)-maxOutput:]...)
+	}
+}
+
+// snapshot returns a copy of the job with its output so far
+func (r *Run) snapshot() Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job := r.job
+	job.Output = string(r.tail())
+	return job
+}
+
+// tail returns the last maxOutput bytes of output; r.mu must be held
+func (r *Run) tail() []byte {
+	if len(r.output) > maxOutput {
+		return r.output[len(r.output)-maxOutput:]
+	}
+	return r.output
+}
+
+// save persists and broadcasts the job, throttled unless force is set
+func (r *Run) save(force bool) {
+	r.mu.Lock()
+	if !force && time.Since(r.lastUpdate) < progressInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastUpdate = time.Now()
+	job := r.job
+	job.Output = string(r.tail())
+	r.mu.Unlock()
+
+	if err := r.m.db.Save(&job).Error; err != nil {
+		log.Printf("⚠️  Failed to save job %d: %v", job.ID, err)
+	}
+	r.m.announce(job)
+}
+
+// finish records the final state of the job
+func (r *Run) finish(status Status, result interface{}, message string) {
+	now := time.Now()
+
+	r.mu.Lock()
+	r.job.Status = status
+	r.job.FinishedAt = &now
+	r.job.Error = message
+	if status == StatusSucceeded {
+		r.job.Progress = 100
+	}
+	if result != nil {
+		if data, err := json.Marshal(result); err == nil {
+			r.job.Result = data
+		}
+	}
+	r.mu.Unlock()
+
+	r.save(true)
+}
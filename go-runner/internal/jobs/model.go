@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status is the state of a background job
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Finished reports whether a job in this status will not change anymore
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCancelled
+}
+
+// Job types
+const (
+	TypeInstall        = "install"         // Package installation in a project directory
+	TypeImport         = "import"          // Project and group import
+	TypeAudit          = "audit"           // Dependency vulnerability audit
+	TypeMigration      = "migration"       // Database migration command
+	TypeScript         = "script"          // Makefile target or package.json script
+	TypeTest           = "test"            // Project test run
+	TypeCleanup        = "cleanup"         // Reclaiming disk space (GET /system/cleanup)
+	TypeOnboarding     = "onboarding"      // Onboarding wizard run (POST /onboarding/{id}/run)
+	TypeProfile        = "profile"         // pprof profile capture (POST /projects/{id}/profile)
+	TypeRollingRestart = "rolling_restart" // Group rolling restart (POST /groups/{id}/rolling-restart)
+)
+
+// Job is a long-running operation executed by the worker pool, independent of
+// the HTTP request that started it
+type Job struct {
+	ID         uint            `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	Type       string          `json:"type" gorm:"index"`
+	ProjectID  *uint           `json:"project_id,omitempty" gorm:"index"`
+	Key        string          `json:"key,omitempty"` // Jobs with the same key never run concurrently
+	Status     Status          `json:"status" gorm:"index"`
+	Progress   int             `json:"progress"` // Percent, 0-100
+	Message    string          `json:"message,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty" gorm:"type:text" swaggertype:"object"`
+	Output     string          `json:"output,omitempty"` // Log output, truncated to the last 256KB
+	StartedAt  *time.Time      `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+}
+
+// ListFilter selects jobs for List
+type ListFilter struct {
+	Type      string
+	Status    Status
+	ProjectID *uint
+	Limit     int
+}
+
+// ConflictError is returned when a job with the same key is already queued
+// or running
+type ConflictError struct {
+	Key   string
+	JobID uint
+}
+
+func (e *ConflictError) Error() string {
+	return "a job with key " + e.Key + " is already active"
+}
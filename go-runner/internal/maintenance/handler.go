@@ -0,0 +1,202 @@
+package maintenance
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler serves the maintenance window API
+type Handler struct {
+	db *gorm.DB
+}
+
+// RegisterRoutes registers the maintenance window routes
+func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB) {
+	h := &Handler{db: db}
+
+	maintenance := r.Group("/maintenance")
+	{
+		maintenance.GET("", h.GetWindows)
+		maintenance.POST("", h.CreateWindow)
+		maintenance.GET("/:id", h.GetWindow)
+		maintenance.DELETE("/:id", h.DeleteWindow)
+	}
+}
+
+// CreateWindowRequest describes a maintenance window: either starts_at and
+// ends_at, or cron and duration_minutes
+type CreateWindowRequest struct {
+	Name             string     `json:"name" binding:"required"`
+	Reason           string     `json:"reason"`
+	ProjectIDs       []uint     `json:"project_ids"`                                          // Empty for the system and every project
+	StartsAt         *time.Time `json:"starts_at"`                                            // Now when empty
+	EndsAt           *time.Time `json:"ends_at"`                                              // Required for a one-off window
+	Cron             string     `json:"cron"`                                                 // e.g. "0 2 * * 6" (Saturdays 02:00)
+	DurationMinutes  int        `json:"duration_minutes" binding:"omitempty,min=1,max=10080"` // Required with cron
+	SuppressAlerts   *bool      `json:"suppress_alerts"`                                      // Default true
+	PauseAutoRestart *bool      `json:"pause_auto_restart"`                                   // Default true
+}
+
+// GetWindows godoc
+// @Summary      List maintenance windows
+// @Description  List maintenance windows with whether they are open now and their next start
+// @Tags         maintenance
+// @Produce      json
+// @Param        active      query     bool  false  "Only open windows"
+// @Param        project_id  query     int   false  "Only windows covering this project"
+// @Success      200         {object}  types.DataResponse{data=[]Window}
+// @Failure      400         {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /maintenance [get]
+func (h *Handler) GetWindows(c *gin.Context) {
+	var projectID uint
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid project_id", raw))
+			return
+		}
+		projectID = uint(id)
+	}
+	activeOnly := c.Query("active") == "true"
+
+	var windows []Window
+	if err := h.db.Order("id desc").Find(&windows).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch maintenance windows", err.Error()))
+		return
+	}
+
+	now := time.Now()
+	result := []Window{}
+	for _, w := range windows {
+		w.fill(now)
+		if (activeOnly && !w.Active) || (projectID != 0 && !w.Covers(projectID)) {
+			continue
+		}
+		result = append(result, w)
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: result})
+}
+
+// CreateWindow godoc
+// @Summary      Create a maintenance window
+// @Description  Plan a one-off (starts_at to ends_at) or recurring (cron in server local time, lasting duration_minutes) maintenance window for the system or specific projects. While it is open, alerts are not raised and crashed services with auto_restart are not restarted.
+// @Tags         maintenance
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateWindowRequest  true  "Window"
+// @Success      201      {object}  types.DataResponse{data=Window}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid window"
+// @Router       /maintenance [post]
+func (h *Handler) CreateWindow(c *gin.Context) {
+	var req CreateWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	window := Window{
+		Name:             req.Name,
+		Reason:           req.Reason,
+		ProjectIDs:       req.ProjectIDs,
+		SuppressAlerts:   req.SuppressAlerts == nil || *req.SuppressAlerts,
+		PauseAutoRestart: req.PauseAutoRestart == nil || *req.PauseAutoRestart,
+	}
+
+	if req.Cron != "" {
+		window.Cron = req.Cron
+		window.DurationMinutes = req.DurationMinutes
+		window.StartsAt, window.EndsAt = req.StartsAt, req.EndsAt
+	} else if req.EndsAt != nil {
+		startsAt := time.Now()
+		if req.StartsAt != nil {
+			startsAt = *req.StartsAt
+		}
+		window.StartsAt = &startsAt
+		window.EndsAt = req.EndsAt
+	}
+	if err := window.Validate(); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid maintenance window", err.Error()))
+		return
+	}
+
+	if len(req.ProjectIDs) > 0 {
+		var count int64
+		h.db.Table(tables.Name("projects")).Where("id IN ? AND deleted_at IS NULL", req.ProjectIDs).Count(&count)
+		if int(count) != len(req.ProjectIDs) {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Unknown project in project_ids", req.ProjectIDs))
+			return
+		}
+	}
+
+	if err := h.db.Create(&window).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to create maintenance window", err.Error()))
+		return
+	}
+	window.fill(time.Now())
+
+	c.JSON(http.StatusCreated, types.DataResponse{Data: window})
+}
+
+// GetWindow godoc
+// @Summary      Get a maintenance window
+// @Tags         maintenance
+// @Produce      json
+// @Param        id   path      int  true  "Window ID"
+// @Success      200  {object}  types.DataResponse{data=Window}
+// @Failure      404  {object}  middleware.ErrorResponse  "Window not found"
+// @Router       /maintenance/{id} [get]
+func (h *Handler) GetWindow(c *gin.Context) {
+	window, ok := h.loadWindow(c)
+	if !ok {
+		return
+	}
+	window.fill(time.Now())
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: window})
+}
+
+// DeleteWindow godoc
+// @Summary      Delete a maintenance window
+// @Description  Delete a window, ending it if it is open
+// @Tags         maintenance
+// @Produce      json
+// @Param        id   path      int  true  "Window ID"
+// @Success      200  {object}  types.MessageResponse     "Window deleted"
+// @Failure      404  {object}  middleware.ErrorResponse  "Window not found"
+// @Router       /maintenance/{id} [delete]
+func (h *Handler) DeleteWindow(c *gin.Context) {
+	window, ok := h.loadWindow(c)
+	if !ok {
+		return
+	}
+	if err := h.db.Delete(window).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete maintenance window", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Maintenance window deleted"})
+}
+
+func (h *Handler) loadWindow(c *gin.Context) (*Window, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return nil, false
+	}
+
+	var window Window
+	if err := h.db.First(&window, id).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Maintenance window not found", nil))
+		return nil, false
+	}
+	return &window, true
+}
@@ -0,0 +1,168 @@
+// Package maintenance holds planned maintenance windows, during which alerts
+// are suppressed and crashed services are not restarted automatically
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-runner/internal/tables"
+
+	"gorm.io/gorm"
+)
+
+// maxDuration caps recurring windows, in minutes (one week)
+const maxDuration = 7 * 24 * 60
+
+// Window is a one-off (starts_at to ends_at) or recurring (cron + duration)
+// maintenance window, for the whole system or a set of projects
+type Window struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"not null"`
+	Reason    string    `json:"reason"`
+
+	// Scope: the listed projects, or the system and every project when empty
+	ProjectIDs []uint `json:"project_ids" gorm:"serializer:json"`
+
+	// One-off window
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+
+	// Recurring window: starts on the cron schedule (server local time) and
+	// lasts duration_minutes
+	Cron            string `json:"cron"`
+	DurationMinutes int    `json:"duration_minutes"`
+
+	SuppressAlerts   bool `json:"suppress_alerts"`    // Do not raise alerts
+	PauseAutoRestart bool `json:"pause_auto_restart"` // Do not restart crashed services
+
+	// Computed on read
+	Active    bool       `json:"active" gorm:"-"`
+	NextStart *time.Time `json:"next_start,omitempty" gorm:"-"`
+}
+
+// TableName keeps windows apart from other tables
+func (Window) TableName() string {
+	return tables.Name("maintenance_windows")
+}
+
+// Covers reports whether the window applies to a project, 0 being the
+// system itself
+func (w *Window) Covers(projectID uint) bool {
+	if len(w.ProjectIDs) == 0 {
+		return true
+	}
+	for _, id := range w.ProjectIDs {
+		if id == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAt reports whether the window is open at t
+func (w *Window) ActiveAt(t time.Time) bool {
+	if w.Cron == "" {
+		return w.StartsAt != nil && w.EndsAt != nil && !t.Before(*w.StartsAt) && t.Before(*w.EndsAt)
+	}
+
+	s, err := parseCron(w.Cron)
+	if err != nil {
+		return false
+	}
+	// Open if the schedule fired within the last duration_minutes
+	start := t.Truncate(time.Minute)
+	for i := 0; i < w.DurationMinutes && i < maxDuration; i++ {
+		if s.matches(start.Add(-time.Duration(i) * time.Minute)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fill computes Active and NextStart at t
+// Validate checks the window is either one-off, ending after it starts, or
+// recurring on a valid cron schedule for duration_minutes
+func (w *Window) Validate() error {
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	if w.Cron == "" {
+		if w.StartsAt == nil || w.EndsAt == nil {
+			return errors.New("ends_at or cron is required")
+		}
+		if !w.EndsAt.After(*w.StartsAt) {
+			return errors.New("ends_at must be after starts_at")
+		}
+		return nil
+	}
+
+	if w.StartsAt != nil || w.EndsAt != nil {
+		return errors.New("use either cron or starts_at/ends_at")
+	}
+	if _, err := parseCron(w.Cron); err != nil {
+		return fmt.Errorf("invalid cron expression: %w", err)
+	}
+	if w.DurationMinutes < 1 || w.DurationMinutes > maxDuration {
+		return fmt.Errorf("duration_minutes between 1 and %d is required with cron", maxDuration)
+	}
+	return nil
+}
+
+func (w *Window) fill(t time.Time) {
+	w.Active = w.ActiveAt(t)
+	w.NextStart = nil
+	if w.Cron != "" {
+		if s, err := parseCron(w.Cron); err == nil {
+			if next, ok := s.next(t); ok {
+				w.NextStart = &next
+			}
+		}
+	} else if w.StartsAt != nil && w.StartsAt.After(t) {
+		w.NextStart = w.StartsAt
+	}
+}
+
+// Open returns the open window covering a project, or nil
+func Open(db *gorm.DB, projectID uint) *Window {
+	return find(db, projectID, "")
+}
+
+// SuppressingAlerts returns the open window suppressing the alerts of a
+// project (0 for system alerts), or nil
+func SuppressingAlerts(db *gorm.DB, projectID uint) *Window {
+	return find(db, projectID, "suppress_alerts")
+}
+
+// PausingAutoRestart returns the open window pausing the auto-restart of a
+// project, or nil
+func PausingAutoRestart(db *gorm.DB, projectID uint) *Window {
+	return find(db, projectID, "pause_auto_restart")
+}
+
+func find(db *gorm.DB, projectID uint, flag string) *Window {
+	now := time.Now()
+
+	query := db.Where("cron <> '' OR (starts_at <= ? AND ends_at > ?)", now, now)
+	if flag != "" {
+		query = query.Where(flag+" = ?", true)
+	}
+
+	var windows []Window
+	if err := query.Find(&windows).Error; err != nil {
+		log.Printf("Failed to load maintenance windows: %v", err)
+		return nil
+	}
+
+	for i := range windows {
+		if windows[i].Covers(projectID) && windows[i].ActiveAt(now) {
+			windows[i].Active = true
+			return &windows[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"go-runner/pkg/client"
+)
+
+// Server exposes go-runner operations as MCP tools over stdio (newline
+// delimited JSON-RPC). It does not manage processes itself; every tool call
+// goes through the HTTP API of a running go-runner server.
+type Server struct {
+	client  *client.ClientWithResponses
+	tools   []toolEntry
+	version string
+
+	writeMu sync.Mutex
+	out     io.Writer
+}
+
+// NewServer creates an MCP server talking to the go-runner API at apiURL
+// (for example http://localhost:8080/api/v1), sending token as a bearer
+// token when set
+func NewServer(apiURL, token, version string) (*Server, error) {
+	var options []client.ClientOption
+	if token != "" {
+		options = append(options, client.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		}))
+	}
+	c, err := client.NewClientWithResponses(apiURL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	s := &Server{
+		client:  c,
+		version: version,
+	}
+	s.registerTools()
+	return s, nil
+}
+
+// Serve reads requests from in and writes responses to out until in is closed
+// or ctx is cancelled
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.writeError(nil, codeParseError, "parse error: "+err.Error())
+			continue
+		}
+
+		s.handle(ctx, &req)
+	}
+
+	return scanner.Err()
+}
+
+func (s *Server) handle(ctx context.Context, req *request) {
+	// Notifications (no ID) never get a response
+	isNotification := len(req.ID) == 0
+
+	if req.JSONRPC != "2.0" {
+		if !isNotification {
+			s.writeError(req.ID, codeInvalidRequest, "jsonrpc must be \"2.0\"")
+		}
+		return
+	}
+
+	switch req.Method {
+	case "initialize":
+		s.writeResult(req.ID, initializeResult{
+			ProtocolVersion: ProtocolVersion,
+			Capabilities: map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			ServerInfo: serverInfo{Name: "go-runner", Version: s.version},
+		})
+	case "notifications/initialized", "notifications/cancelled":
+		// Nothing to do
+	case "ping":
+		s.writeResult(req.ID, map[string]interface{}{})
+	case "tools/list":
+		s.writeResult(req.ID, listToolsResult{Tools: s.Tools()})
+	case "tools/call":
+		var params callToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.writeError(req.ID, codeInvalidParams, "invalid params: "+err.Error())
+			return
+		}
+		result, err := s.CallTool(ctx, params.Name, params.Arguments)
+		if err != nil {
+			s.writeError(req.ID, codeInvalidParams, err.Error())
+			return
+		}
+		s.writeResult(req.ID, result)
+	default:
+		if !isNotification {
+			s.writeError(req.ID, codeMethodNotFound, "method not found: "+req.Method)
+		}
+	}
+}
+
+// CallTool runs a tool by name. Unknown tools are reported as an error;
+// failures inside a tool are reported in the result with IsError set so the
+// assistant can see and react to them.
+func (s *Server) CallTool(ctx context.Context, name string, args map[string]interface{}) (*CallToolResult, error) {
+	for _, entry := range s.tools {
+		if entry.tool.Name != name {
+			continue
+		}
+
+		if args == nil {
+			args = map[string]interface{}{}
+		}
+		text, err := entry.handler(ctx, args)
+		if err != nil {
+			log.Printf("MCP tool %s failed: %v", name, err)
+			return &CallToolResult{
+				Content: []Content{{Type: "text", Text: err.Error()}},
+				IsError: true,
+			}, nil
+		}
+		return &CallToolResult{
+			Content: []Content{{Type: "text", Text: text}},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown tool: %s", name)
+}
+
+func (s *Server) writeResult(id json.RawMessage, result interface{}) {
+	s.write(response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) writeError(id json.RawMessage, code int, message string) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}})
+}
+
+func (s *Server) write(resp response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("MCP failed to encode response: %v", err)
+		data, _ = json.Marshal(response{
+			JSONRPC: "2.0",
+			ID:      resp.ID,
+			Error:   &rpcError{Code: codeInternalError, Message: "failed to encode response"},
+		})
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.out.Write(append(data, '\n'))
+}
@@ -0,0 +1,412 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-runner/pkg/client"
+)
+
+const defaultLogLines = 50
+
+// errorLinePattern matches log lines that look like errors when errors_only is set
+var errorLinePattern = regexp.MustCompile(`(?i)\b(error|err|fatal|panic|exception|traceback)\b`)
+
+// toolHandler runs a tool against the API and returns its text output
+type toolHandler func(ctx context.Context, args map[string]interface{}) (string, error)
+
+type toolEntry struct {
+	tool    Tool
+	handler toolHandler
+}
+
+// serviceArgSchema is the shared schema for tools addressing a single service
+var serviceArgSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"service": map[string]interface{}{
+			"type":        "string",
+			"description": "Project ID or project name (case-insensitive)",
+		},
+	},
+	"required": []string{"service"},
+}
+
+// registerTools builds the tool table backed by the go-runner API client
+func (s *Server) registerTools() {
+	s.tools = []toolEntry{
+		{
+			tool: Tool{
+				Name:        "list_services",
+				Description: "List all projects managed by go-runner with their ID, type, status, port and group.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"status": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return services in this status",
+							"enum":        []string{"running", "stopped", "starting", "stopping", "error", "unknown"},
+						},
+					},
+				},
+			},
+			handler: s.listServices,
+		},
+		{
+			tool: Tool{
+				Name:        "get_service_status",
+				Description: "Get the current status, PID, health and last error of a service.",
+				InputSchema: serviceArgSchema,
+			},
+			handler: s.getServiceStatus,
+		},
+		{
+			tool: Tool{
+				Name:        "read_logs",
+				Description: "Read the most recent log lines of a service, prefixed with their capture time in UTC, optionally filtered to errors or a substring.",
+				InputSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"service": serviceArgSchema["properties"].(map[string]interface{})["service"],
+						"lines": map[string]interface{}{
+							"type":        "integer",
+							"description": "Number of lines to return after filtering",
+							"default":     defaultLogLines,
+						},
+						"errors_only": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Only return lines mentioning error, fatal, panic or exception",
+						},
+						"contains": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return lines containing this text (case-insensitive)",
+						},
+					},
+					"required": []string{"service"},
+				},
+			},
+			handler: s.readLogs,
+		},
+		{
+			tool: Tool{
+				Name:        "start_service",
+				Description: "Start a stopped service.",
+				InputSchema: serviceArgSchema,
+			},
+			handler: s.startService,
+		},
+		{
+			tool: Tool{
+				Name:        "stop_service",
+				Description: "Stop a running service.",
+				InputSchema: serviceArgSchema,
+			},
+			handler: s.stopService,
+		},
+		{
+			tool: Tool{
+				Name:        "restart_service",
+				Description: "Restart a running service.",
+				InputSchema: serviceArgSchema,
+			},
+			handler: s.restartService,
+		},
+		{
+			tool: Tool{
+				Name:        "system_status",
+				Description: "Get host CPU, memory and disk usage with their health status.",
+				InputSchema: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{},
+				},
+			},
+			handler: s.systemStatus,
+		},
+	}
+}
+
+// Tools returns the MCP tool definitions
+func (s *Server) Tools() []Tool {
+	tools := make([]Tool, 0, len(s.tools))
+	for _, entry := range s.tools {
+		tools = append(tools, entry.tool)
+	}
+	return tools
+}
+
+// FunctionDefinitions returns the tools in function-calling form
+func (s *Server) FunctionDefinitions() []FunctionDefinition {
+	functions := make([]FunctionDefinition, 0, len(s.tools))
+	for _, entry := range s.tools {
+		functions = append(functions, FunctionDefinition{
+			Name:        entry.tool.Name,
+			Description: entry.tool.Description,
+			Parameters:  entry.tool.InputSchema,
+		})
+	}
+	return functions
+}
+
+// serviceSummary is the compact view of a project returned by list_services
+type serviceSummary struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	Status string `json:"status,omitempty"`
+	Port   int    `json:"port,omitempty"`
+	Group  string `json:"group,omitempty"`
+}
+
+func (s *Server) listServices(ctx context.Context, args map[string]interface{}) (string, error) {
+	projects, err := s.fetchProjects(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	status := stringArg(args, "status")
+	services := make([]serviceSummary, 0, len(projects))
+	for _, p := range projects {
+		summary := serviceSummary{
+			ID:   deref(p.Id),
+			Name: p.Name,
+			Port: deref(p.Port),
+		}
+		if p.Type != nil {
+			summary.Type = string(*p.Type)
+		}
+		if p.Status != nil {
+			summary.Status = string(*p.Status)
+		}
+		if p.Group != nil {
+			summary.Group = p.Group.Name
+		}
+		if status != "" && summary.Status != status {
+			continue
+		}
+		services = append(services, summary)
+	}
+
+	return toJSON(services)
+}
+
+func (s *Server) getServiceStatus(ctx context.Context, args map[string]interface{}) (string, error) {
+	id, err := s.resolveService(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.GetProjectsIdStatusWithResponse(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return dataOrError(resp.HTTPResponse, resp.Body)
+}
+
+func (s *Server) readLogs(ctx context.Context, args map[string]interface{}) (string, error) {
+	id, err := s.resolveService(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	timestamps := true
+	resp, err := s.client.GetProjectsIdLogsWithResponse(ctx, id, &client.GetProjectsIdLogsParams{Timestamps: &timestamps})
+	if err != nil {
+		return "", err
+	}
+	if resp.JSON200 == nil || resp.JSON200.Data == nil {
+		return dataOrError(resp.HTTPResponse, resp.Body)
+	}
+
+	lines := intArg(args, "lines", defaultLogLines)
+	errorsOnly := boolArg(args, "errors_only")
+	contains := strings.ToLower(stringArg(args, "contains"))
+
+	var matched []string
+	for _, line := range deref(resp.JSON200.Data.Logs) {
+		if errorsOnly && !errorLinePattern.MatchString(line) {
+			continue
+		}
+		if contains != "" && !strings.Contains(strings.ToLower(line), contains) {
+			continue
+		}
+		matched = append(matched, line)
+	}
+	if lines > 0 && len(matched) > lines {
+		matched = matched[len(matched)-lines:]
+	}
+
+	if len(matched) == 0 {
+		return "No matching log lines.", nil
+	}
+	return strings.Join(matched, "\n"), nil
+}
+
+func (s *Server) startService(ctx context.Context, args map[string]interface{}) (string, error) {
+	id, err := s.resolveService(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.PostProjectsIdStartWithResponse(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return dataOrError(resp.HTTPResponse, resp.Body)
+}
+
+func (s *Server) stopService(ctx context.Context, args map[string]interface{}) (string, error) {
+	id, err := s.resolveService(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.PostProjectsIdStopWithResponse(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return dataOrError(resp.HTTPResponse, resp.Body)
+}
+
+func (s *Server) restartService(ctx context.Context, args map[string]interface{}) (string, error) {
+	id, err := s.resolveService(ctx, args)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.PostProjectsIdRestartWithResponse(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return dataOrError(resp.HTTPResponse, resp.Body)
+}
+
+func (s *Server) systemStatus(ctx context.Context, args map[string]interface{}) (string, error) {
+	resp, err := s.client.GetSystemStatusWithResponse(ctx)
+	if err != nil {
+		return "", err
+	}
+	return dataOrError(resp.HTTPResponse, resp.Body)
+}
+
+func (s *Server) fetchProjects(ctx context.Context) ([]client.Project, error) {
+	resp, err := s.client.GetProjectsWithResponse(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		_, err := dataOrError(resp.HTTPResponse, resp.Body)
+		if err == nil {
+			err = fmt.Errorf("unexpected response listing projects: %s", resp.Status())
+		}
+		return nil, err
+	}
+	return deref(resp.JSON200.Data), nil
+}
+
+// resolveService maps the "service" argument, an ID or a name, to a project ID
+func (s *Server) resolveService(ctx context.Context, args map[string]interface{}) (int, error) {
+	ref := strings.TrimSpace(stringArg(args, "service"))
+	if ref == "" {
+		return 0, fmt.Errorf("service is required")
+	}
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+
+	projects, err := s.fetchProjects(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var names []string
+	for _, p := range projects {
+		name := p.Name
+		if strings.EqualFold(name, ref) {
+			return deref(p.Id), nil
+		}
+		names = append(names, name)
+	}
+	return 0, fmt.Errorf("no service named %q (available: %s)", ref, strings.Join(names, ", "))
+}
+
+// dataOrError unwraps the {"data": ...} envelope of a successful response and
+// turns error responses into a Go error carrying the API message
+func dataOrError(resp *http.Response, body []byte) (string, error) {
+	var envelope map[string]interface{}
+	_ = json.Unmarshal(body, &envelope)
+
+	if resp == nil || resp.StatusCode >= 300 {
+		status := "no response"
+		if resp != nil {
+			status = resp.Status
+		}
+		if msg, ok := envelope["message"].(string); ok && msg != "" {
+			return "", fmt.Errorf("%s: %s", status, msg)
+		}
+		if msg, ok := envelope["error"].(string); ok && msg != "" {
+			return "", fmt.Errorf("%s: %s", status, msg)
+		}
+		return "", fmt.Errorf("%s: %s", status, strings.TrimSpace(string(body)))
+	}
+
+	if data, ok := envelope["data"]; ok {
+		return toJSON(data)
+	}
+	if envelope != nil {
+		return toJSON(envelope)
+	}
+	return string(body), nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
+
+func stringArg(args map[string]interface{}, key string) string {
+	switch v := args[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func intArg(args map[string]interface{}, key string, fallback int) int {
+	switch v := args[key].(type) {
+	case float64:
+		return int(v)
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func boolArg(args map[string]interface{}, key string) bool {
+	switch v := args[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
@@ -0,0 +1,350 @@
+// Package mdns announces running projects on the local network with
+// multicast DNS and DNS-SD, so they can be reached as <name>.local
+package mdns
+
+import (
+	"context"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// recordTTL is the TTL of announced records, in seconds
+	recordTTL = 120
+	// refreshInterval is how often the announced services are refreshed
+	refreshInterval = 10 * time.Second
+	// serviceType is the DNS-SD type services are announced as
+	serviceType = "_http._tcp.local."
+	// servicesMeta lists the announced service types (DNS-SD enumeration)
+	servicesMeta = "_services._dns-sd._udp.local."
+	// cacheFlush marks records this host owns exclusively
+	cacheFlush = 1 << 15
+)
+
+var groupAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// Service is a project announced on the local network
+type Service struct {
+	ProjectID uint   `json:"project_id"`
+	Instance  string `json:"instance"` // DNS-SD instance name (project name)
+	Host      string `json:"host"`     // Host name, e.g. api.local
+	Port      int    `json:"port"`
+	URL       string `json:"url"`
+}
+
+// Source returns the projects to announce: their ID, name, host label
+// (empty for the slug of the name) and port
+type Source func() []Service
+
+// Responder answers mDNS queries for the services of a source and announces
+// them when they appear or go away
+type Responder struct {
+	source Source
+	conn   *net.UDPConn
+
+	mu       sync.RWMutex
+	services map[string]Service // By lower-case host name
+}
+
+// NewResponder creates a responder for the services of source
+func NewResponder(source Source) *Responder {
+	return &Responder{source: source, services: make(map[string]Service)}
+}
+
+// Services returns the announced services, sorted by host
+func (r *Responder) Services() []Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	services := make([]Service, 0, len(r.services))
+	for _, s := range r.services {
+		services = append(services, s)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Host < services[j].Host })
+	return services
+}
+
+// Run answers queries until ctx is done, then says goodbye for all services
+func (r *Responder) Run(ctx context.Context) error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, groupAddr)
+	if err != nil {
+		return err
+	}
+	r.conn = conn
+
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		services := make([]Service, 0, len(r.services))
+		for _, s := range r.services {
+			services = append(services, s)
+		}
+		r.services = make(map[string]Service)
+		r.mu.Unlock()
+		for _, s := range services {
+			r.send(r.records(s, 0), groupAddr)
+		}
+		conn.Close()
+	}()
+	go r.refreshLoop(ctx)
+
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		r.handleQuery(buf[:n], from)
+	}
+}
+
+// refreshLoop announces new services and says goodbye to removed ones
+func (r *Responder) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		r.refresh()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Responder) refresh() {
+	next := make(map[string]Service)
+	for _, s := range r.source() {
+		label := Slug(s.Host)
+		if label == "" {
+			label = Slug(s.Instance)
+		}
+		if label == "" || s.Port == 0 {
+			continue
+		}
+		if s.Instance == "" {
+			s.Instance = label
+		}
+		host := label + ".local"
+		for i := 2; ; i++ {
+			if _, taken := next[host]; !taken {
+				break
+			}
+			host = label + "-" + strconv.Itoa(i) + ".local"
+		}
+		s.Host = host
+		s.Instance = strings.ReplaceAll(s.Instance, ".", " ")
+		s.URL = "http://" + host + ":" + strconv.Itoa(s.Port)
+		next[host] = s
+	}
+
+	r.mu.Lock()
+	var added, removed []Service
+	for host, s := range next {
+		if old, ok := r.services[host]; !ok || old != s {
+			added = append(added, s)
+		}
+	}
+	for host, s := range r.services {
+		if _, ok := next[host]; !ok {
+			removed = append(removed, s)
+		}
+	}
+	r.services = next
+	r.mu.Unlock()
+
+	for _, s := range removed {
+		r.send(r.records(s, 0), groupAddr)
+	}
+	for _, s := range added {
+		log.Printf("📣 mDNS: announcing %s (%s)", s.URL, s.Instance)
+		r.send(r.records(s, recordTTL), groupAddr)
+	}
+}
+
+// handleQuery answers the questions about announced services
+func (r *Responder) handleQuery(msg []byte, from *net.UDPAddr) {
+	var p dnsmessage.Parser
+	header, err := p.Start(msg)
+	if err != nil || header.Response {
+		return
+	}
+	questions, err := p.AllQuestions()
+	if err != nil {
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var answers []dnsmessage.Resource
+	unicast := from.Port != groupAddr.Port
+	for _, q := range questions {
+		if q.Class&cacheFlush != 0 {
+			unicast = true // QU bit: the querier asks for a unicast reply
+		}
+		name := strings.ToLower(q.Name.String())
+
+		switch {
+		case name == servicesMeta && matchType(q.Type, dnsmessage.TypePTR):
+			if len(r.services) > 0 {
+				answers = append(answers, ptr(servicesMeta, serviceType, recordTTL))
+			}
+		case name == serviceType && matchType(q.Type, dnsmessage.TypePTR):
+			for _, s := range r.services {
+				answers = append(answers, r.records(s, recordTTL)...)
+			}
+		default:
+			for host, s := range r.services {
+				if name == strings.ToLower(host)+"." && matchType(q.Type, dnsmessage.TypeA) {
+					answers = append(answers, r.addresses(s, recordTTL)...)
+				}
+				if name == strings.ToLower(instanceName(s)) && (matchType(q.Type, dnsmessage.TypeSRV) || matchType(q.Type, dnsmessage.TypeTXT)) {
+					answers = append(answers, r.records(s, recordTTL)[1:]...)
+				}
+			}
+		}
+	}
+	if len(answers) == 0 {
+		return
+	}
+
+	to := groupAddr
+	if unicast {
+		to = from
+	}
+	r.send(answers, to)
+}
+
+func matchType(asked, want dnsmessage.Type) bool {
+	return asked == want || asked == dnsmessage.TypeALL
+}
+
+// records returns the PTR, SRV, TXT and A records of a service
+func (r *Responder) records(s Service, ttl uint32) []dnsmessage.Resource {
+	instance := instanceName(s)
+	host := s.Host + "."
+
+	records := []dnsmessage.Resource{
+		ptr(serviceType, instance, ttl),
+		{
+			Header: header(instance, dnsmessage.TypeSRV, ttl, true),
+			Body:   &dnsmessage.SRVResource{Port: uint16(s.Port), Target: dnsmessage.MustNewName(host)},
+		},
+		{
+			Header: header(instance, dnsmessage.TypeTXT, ttl, true),
+			Body:   &dnsmessage.TXTResource{TXT: []string{"path=/", "project_id=" + strconv.Itoa(int(s.ProjectID))}},
+		},
+	}
+	return append(records, r.addresses(s, ttl)...)
+}
+
+// addresses returns the A records of a service host
+func (r *Responder) addresses(s Service, ttl uint32) []dnsmessage.Resource {
+	var records []dnsmessage.Resource
+	for _, ip := range localIPv4s() {
+		var a [4]byte
+		copy(a[:], ip)
+		records = append(records, dnsmessage.Resource{
+			Header: header(s.Host+".", dnsmessage.TypeA, ttl, true),
+			Body:   &dnsmessage.AResource{A: a},
+		})
+	}
+	return records
+}
+
+func instanceName(s Service) string {
+	return s.Instance + "." + serviceType
+}
+
+func ptr(name, target string, ttl uint32) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: header(name, dnsmessage.TypePTR, ttl, false),
+		Body:   &dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(target)},
+	}
+}
+
+func header(name string, typ dnsmessage.Type, ttl uint32, unique bool) dnsmessage.ResourceHeader {
+	class := dnsmessage.ClassINET
+	if unique {
+		class |= cacheFlush
+	}
+	return dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: typ, Class: class, TTL: ttl}
+}
+
+// send writes an authoritative response
+func (r *Responder) send(answers []dnsmessage.Resource, to *net.UDPAddr) {
+	if r.conn == nil || len(answers) == 0 {
+		return
+	}
+	msg := dnsmessage.Message{
+		Header:  dnsmessage.Header{Response: true, Authoritative: true},
+		Answers: answers,
+	}
+	packed, err := msg.Pack()
+	if err != nil {
+		log.Printf("mDNS: failed to pack response: %v", err)
+		return
+	}
+	if _, err := r.conn.WriteToUDP(packed, to); err != nil {
+		log.Printf("mDNS: failed to send response: %v", err)
+	}
+}
+
+// localIPv4s returns the IPv4 addresses of the up, non-loopback interfaces
+func localIPv4s() []net.IP {
+	var ips []net.IP
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				if ip := ipNet.IP.To4(); ip != nil {
+					ips = append(ips, ip)
+				}
+			}
+		}
+	}
+	return ips
+}
+
+// Slug turns a project name into a host label: lower-case letters, digits
+// and dashes
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSuffix(name, ".local")) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	label := strings.TrimSuffix(b.String(), "-")
+	if len(label) > 63 {
+		label = strings.TrimSuffix(label[:63], "-")
+	}
+	return label
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -33,11 +34,14 @@ func RequestLogger() gin.HandlerFunc {
 		}
 
 		// Wrap response writer to capture response body
-		blw := &ResponseWriter{
-			ResponseWriter: c.Writer,
-			body:          bytes.NewBufferString(""),
+		// (not for event streams, which would be buffered for their whole lifetime)
+		if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			blw := &ResponseWriter{
+				ResponseWriter: c.Writer,
+				body:          bytes.NewBufferString(""),
+			}
+			c.Writer = blw
 		}
-		c.Writer = blw
 
 		// Process request
 		c.Next()
@@ -1,9 +1,14 @@
 package middleware
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +19,14 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	// Failed fields are named as in the JSON body
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
 	
 	// Register custom validators
 	validate.RegisterValidation("notempty", notEmpty)
@@ -21,47 +34,75 @@ func init() {
 	validate.RegisterValidation("hexcolor", isHexColor)
 }
 
-// ValidationMiddleware validates request body
+// ValidationMiddleware validates the JSON body against the validate tags of
+// model, a pointer to a request struct, and answers 422 with every failed
+// rule. The body is left for the handler to bind, and the validated copy is
+// stored in the context as validated_model.
 func ValidationMiddleware(model interface{}) gin.HandlerFunc {
+	modelType := reflect.TypeOf(model)
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
 	return func(c *gin.Context) {
-		var requestBody interface{}
-		
-		// Parse JSON body
-		if err := c.ShouldBindJSON(&requestBody); err != nil {
-			HandleError(c, NewError(http.StatusBadRequest, "Invalid JSON format", err.Error()))
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			HandleError(c, NewError(http.StatusBadRequest, "Failed to read request body", err.Error()))
+			c.Abort()
 			return
 		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
 
-		// Convert to target model
-		jsonData, _ := json.Marshal(requestBody)
-		if err := json.Unmarshal(jsonData, model); err != nil {
-			HandleError(c, NewError(http.StatusBadRequest, "Invalid request format", err.Error()))
+		// A new value per request, requests run concurrently
+		target := reflect.New(modelType).Interface()
+		if err := json.Unmarshal(body, target); err != nil {
+			HandleError(c, NewError(http.StatusBadRequest, "Invalid JSON format", err.Error()))
+			c.Abort()
 			return
 		}
 
-		// Validate the model
-		if err := validate.Struct(model); err != nil {
+		if err := validate.Struct(target); err != nil {
+			var fieldErrors validator.ValidationErrors
+			if !errors.As(err, &fieldErrors) {
+				HandleError(c, NewError(http.StatusBadRequest, "Invalid request format", err.Error()))
+				c.Abort()
+				return
+			}
 			var validationErrors []ValidationError
-			
-			for _, err := range err.(validator.ValidationErrors) {
+			for _, fieldError := range fieldErrors {
 				validationErrors = append(validationErrors, ValidationError{
-					Field:   err.Field(),
-					Tag:     err.Tag(),
-					Value:   err.Value().(string),
-					Message: getValidationMessage(err),
+					Field:   fieldError.Field(),
+					Tag:     fieldError.Tag(),
+					Value:   formatValue(fieldError.Value()),
+					Message: getValidationMessage(fieldError.Field(), fieldError),
 				})
 			}
-			
 			HandleValidationError(c, validationErrors)
+			c.Abort()
 			return
 		}
 
 		// Store validated model in context
-		c.Set("validated_model", model)
+		c.Set("validated_model", target)
 		c.Next()
 	}
 }
 
+// formatValue formats the value of a failed field, following pointers
+func formatValue(value interface{}) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
 // Custom validation functions
 func notEmpty(fl validator.FieldLevel) bool {
 	value := fl.Field()
@@ -99,52 +140,83 @@ func isHexColor(fl validator.FieldLevel) bool {
 	return true
 }
 
-// Get validation error message
-func getValidationMessage(err validator.FieldError) string {
+// getValidationMessage describes a failed rule of a field
+func getValidationMessage(field string, err validator.FieldError) string {
 	switch err.Tag() {
 	case "required":
-		return err.Field() + " is required"
+		return field + " is required"
 	case "min":
-		return err.Field() + " must be at least " + err.Param()
+		return field + " must be at least " + err.Param()
 	case "max":
-		return err.Field() + " must be at most " + err.Param()
+		return field + " must be at most " + err.Param()
 	case "email":
-		return err.Field() + " must be a valid email address"
+		return field + " must be a valid email address"
 	case "url":
-		return err.Field() + " must be a valid URL"
+		return field + " must be a valid URL"
+	case "oneof":
+		return field + " must be one of " + strings.Join(strings.Fields(err.Param()), ", ")
+	case "number", "numeric":
+		return field + " must be a number"
 	case "notempty":
-		return err.Field() + " cannot be empty"
+		return field + " cannot be empty"
 	case "port":
-		return err.Field() + " must be a valid port number (1-65535)"
+		return field + " must be a valid port number (1-65535)"
 	case "hexcolor":
-		return err.Field() + " must be a valid hex color (e.g., #FF0000)"
+		return field + " must be a valid hex color (e.g., #FF0000)"
 	default:
-		return err.Field() + " is invalid"
+		return field + " is invalid"
 	}
 }
 
-// ValidateQueryParams validates query parameters
+// ValidateQueryParams validates query parameters against validator rules
+// applied to their raw string, e.g. {"hours": "omitempty,number"}: min and
+// max are lengths there
 func ValidateQueryParams(rules map[string]string) gin.HandlerFunc {
+	return validateValues(rules, (*gin.Context).Query)
+}
+
+// ValidateParams validates path parameters against validator rules, e.g.
+// {"id": "number"}
+func ValidateParams(rules map[string]string) gin.HandlerFunc {
+	return validateValues(rules, (*gin.Context).Param)
+}
+
+// validateValues checks the values read by get against rules, answering 422
+// with every failed rule
+func validateValues(rules map[string]string, get func(*gin.Context, string) string) gin.HandlerFunc {
+	fields := make([]string, 0, len(rules))
+	for field, rule := range rules {
+		// Unknown rules panic when the routes are set up, not on a request
+		_ = validate.Var("", rule)
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
 	return func(c *gin.Context) {
 		var validationErrors []ValidationError
-		
-		for field, rule := range rules {
-			value := c.Query(field)
-			if value == "" && strings.Contains(rule, "required") {
+		for _, field := range fields {
+			value := get(c, field)
+			err := validate.Var(value, rules[field])
+			var fieldErrors validator.ValidationErrors
+			if !errors.As(err, &fieldErrors) {
+				continue
+			}
+			for _, fieldError := range fieldErrors {
 				validationErrors = append(validationErrors, ValidationError{
 					Field:   field,
-					Tag:     "required",
+					Tag:     fieldError.Tag(),
 					Value:   value,
-					Message: field + " is required",
+					Message: getValidationMessage(field, fieldError),
 				})
 			}
 		}
-		
+
 		if len(validationErrors) > 0 {
 			HandleValidationError(c, validationErrors)
+			c.Abort()
 			return
 		}
-		
+
 		c.Next()
 	}
 }
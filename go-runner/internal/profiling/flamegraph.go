@@ -0,0 +1,149 @@
+package profiling
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"sort"
+)
+
+// Flame graph layout
+const (
+	graphWidth   = 1200
+	graphPadding = 10
+	rowHeight    = 16
+	charWidth    = 7 // Approximate width of a 12px monospace character
+	minWidth     = 0.5
+)
+
+// frame is a node of the call tree
+type frame struct {
+	name     string
+	value    int64
+	children map[string]*frame
+}
+
+func (f *frame) child(name string) *frame {
+	c := f.children[name]
+	if c == nil {
+		c = &frame{name: name, children: map[string]*frame{}}
+		f.children[name] = c
+	}
+	return c
+}
+
+func (f *frame) depth() int {
+	d := 0
+	for _, c := range f.children {
+		d = max(d, c.depth())
+	}
+	return d + 1
+}
+
+// FlameGraph renders a profile as an SVG flame graph, callers below callees.
+// Hovering a frame shows its full name and value.
+func FlameGraph(p *Profile, title string) []byte {
+	root := &frame{name: "all", children: map[string]*frame{}}
+	for _, s := range p.Samples {
+		if p.ValueIndex >= len(s.Values) || s.Values[p.ValueIndex] <= 0 {
+			continue
+		}
+		value := s.Values[p.ValueIndex]
+		root.value += value
+		node := root
+		for i := len(s.Stack) - 1; i >= 0; i-- {
+			node = node.child(s.Stack[i])
+			node.value += value
+		}
+	}
+
+	unit := p.SampleType()
+	depth := root.depth()
+	height := depth*rowHeight + 3*graphPadding + 2*rowHeight
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" standalone="no"?>
+<svg version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" xmlns="http://www.w3.org/2000/svg">
+<style>text { font-family: monospace; font-size: 12px; fill: #000; } rect:hover { stroke: #000; stroke-width: 0.5; }</style>
+<rect x="0" y="0" width="100%%" height="100%%" fill="#f8f8f8"/>
+<text x="%d" y="%d" style="font-size: 14px">%s</text>
+<text x="%d" y="%d" text-anchor="end">%s %s</text>
+`, graphWidth, height, graphWidth, height,
+		graphPadding, graphPadding+rowHeight, html.EscapeString(title),
+		graphWidth-graphPadding, graphPadding+rowHeight, html.EscapeString(unit.Type), html.EscapeString(formatValue(root.value, unit.Unit)))
+
+	if root.value > 0 {
+		scale := float64(graphWidth-2*graphPadding) / float64(root.value)
+		bottom := height - graphPadding
+		var draw func(f *frame, x float64, level int)
+		draw = func(f *frame, x float64, level int) {
+			width := float64(f.value) * scale
+			if width < minWidth {
+				return
+			}
+			y := bottom - (level+1)*rowHeight
+			label := fmt.Sprintf("%s (%s, %.2f%%)", f.name, formatValue(f.value, unit.Unit), float64(f.value)*100/float64(root.value))
+			fmt.Fprintf(&buf, `<g><title>%s</title><rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" rx="2"/>`,
+				html.EscapeString(label), x, y, width, rowHeight-1, color(f.name))
+			if chars := int(width-6) / charWidth; chars >= 3 {
+				text := f.name
+				if len(text) > chars {
+					text = text[:chars-2] + ".."
+				}
+				fmt.Fprintf(&buf, `<text x="%.1f" y="%d">%s</text>`, x+3, y+rowHeight-4, html.EscapeString(text))
+			}
+			buf.WriteString("</g>\n")
+
+			children := make([]*frame, 0, len(f.children))
+			for _, c := range f.children {
+				children = append(children, c)
+			}
+			sort.Slice(children, func(i, j int) bool { return children[i].name < children[j].name })
+			for _, c := range children {
+				draw(c, x, level+1)
+				x += float64(c.value) * scale
+			}
+		}
+		draw(root, graphPadding, 0)
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// color returns a warm colour derived from a function name, so a function
+// keeps its colour across graphs
+func color(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	v := h.Sum32()
+	return fmt.Sprintf("rgb(%d,%d,%d)", 205+v%50, 80+(v>>8)%150, (v>>16)%60)
+}
+
+// formatValue formats a sample value in a readable unit
+func formatValue(v int64, unit string) string {
+	f := float64(v)
+	switch unit {
+	case "nanoseconds":
+		switch {
+		case f >= 1e9:
+			return fmt.Sprintf("%.2fs", f/1e9)
+		case f >= 1e6:
+			return fmt.Sprintf("%.2fms", f/1e6)
+		case f >= 1e3:
+			return fmt.Sprintf("%.2fµs", f/1e3)
+		}
+		return fmt.Sprintf("%dns", v)
+	case "bytes":
+		switch {
+		case f >= 1<<30:
+			return fmt.Sprintf("%.2f GB", f/(1<<30))
+		case f >= 1<<20:
+			return fmt.Sprintf("%.2f MB", f/(1<<20))
+		case f >= 1<<10:
+			return fmt.Sprintf("%.2f KB", f/(1<<10))
+		}
+		return fmt.Sprintf("%d B", v)
+	}
+	return fmt.Sprintf("%d", v)
+}
@@ -0,0 +1,278 @@
+// Package profiling reads pprof profiles and renders them as flame graphs
+package profiling
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxProfileSize bounds the uncompressed size of a profile
+const maxProfileSize = 256 << 20
+
+// ErrNotProfile is returned for data that is not a pprof profile
+var ErrNotProfile = errors.New("not a pprof profile")
+
+// Profile is the part of a pprof profile needed to draw a flame graph
+type Profile struct {
+	SampleTypes []ValueType
+	Samples     []Sample
+	ValueIndex  int // Sample value drawn: the default sample type, else the last one
+}
+
+// ValueType is the kind and unit of a sample value, e.g. cpu/nanoseconds
+type ValueType struct {
+	Type string
+	Unit string
+}
+
+// Sample is a call stack, leaf first, with its values
+type Sample struct {
+	Stack  []string
+	Values []int64
+}
+
+// SampleType returns the sample type drawn
+func (p *Profile) SampleType() ValueType {
+	if p.ValueIndex < len(p.SampleTypes) {
+		return p.SampleTypes[p.ValueIndex]
+	}
+	return ValueType{}
+}
+
+// Total returns the sum of the values drawn
+func (p *Profile) Total() int64 {
+	var total int64
+	for _, s := range p.Samples {
+		if p.ValueIndex < len(s.Values) {
+			total += s.Values[p.ValueIndex]
+		}
+	}
+	return total
+}
+
+// Parse reads a pprof profile, gzipped (as served by net/http/pprof) or not
+func Parse(data []byte) (*Profile, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrNotProfile
+		}
+		data, err = io.ReadAll(io.LimitReader(zr, maxProfileSize+1))
+		if err != nil {
+			return nil, ErrNotProfile
+		}
+		if len(data) > maxProfileSize {
+			return nil, fmt.Errorf("profile larger than %d MB", maxProfileSize>>20)
+		}
+	}
+
+	var (
+		rawTypes     [][2]int64
+		rawSamples   []rawSample
+		locations    = map[uint64][]uint64{} // Location ID to function IDs, innermost first
+		functions    = map[uint64]int64{}    // Function ID to name string index
+		table        []string
+		defaultType  int64
+		hasDefault   bool
+		hasStringTab bool
+	)
+	err := walk(data, func(field int, wire int, v uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			var t [2]int64
+			err := walk(b, func(field int, wire int, v uint64, _ []byte) error {
+				if wire == wireVarint && (field == 1 || field == 2) {
+					t[field-1] = int64(v)
+				}
+				return nil
+			})
+			rawTypes = append(rawTypes, t)
+			return err
+		case field == 2 && wire == wireBytes:
+			s, err := parseSample(b)
+			rawSamples = append(rawSamples, s)
+			return err
+		case field == 4 && wire == wireBytes:
+			id, funcs, err := parseLocation(b)
+			locations[id] = funcs
+			return err
+		case field == 5 && wire == wireBytes:
+			var id uint64
+			var name int64
+			err := walk(b, func(field int, wire int, v uint64, _ []byte) error {
+				switch {
+				case field == 1 && wire == wireVarint:
+					id = v
+				case field == 2 && wire == wireVarint:
+					name = int64(v)
+				}
+				return nil
+			})
+			functions[id] = name
+			return err
+		case field == 6 && wire == wireBytes:
+			table = append(table, string(b))
+			hasStringTab = true
+		case field == 14 && wire == wireVarint:
+			defaultType = int64(v)
+			hasDefault = true
+		}
+		return nil
+	})
+	if err != nil || !hasStringTab || len(rawTypes) == 0 {
+		return nil, ErrNotProfile
+	}
+
+	str := func(i int64) string {
+		if i >= 0 && i < int64(len(table)) {
+			return table[i]
+		}
+		return ""
+	}
+	p := &Profile{ValueIndex: len(rawTypes) - 1}
+	for i, t := range rawTypes {
+		p.SampleTypes = append(p.SampleTypes, ValueType{Type: str(t[0]), Unit: str(t[1])})
+		if hasDefault && t[0] == defaultType {
+			p.ValueIndex = i
+		}
+	}
+	for _, s := range rawSamples {
+		sample := Sample{Values: s.values}
+		for _, loc := range s.locations {
+			funcs, ok := locations[loc]
+			if !ok || len(funcs) == 0 {
+				sample.Stack = append(sample.Stack, fmt.Sprintf("0x%x", loc))
+				continue
+			}
+			for _, fn := range funcs {
+				name := str(functions[fn])
+				if name == "" {
+					name = "?"
+				}
+				sample.Stack = append(sample.Stack, name)
+			}
+		}
+		p.Samples = append(p.Samples, sample)
+	}
+	return p, nil
+}
+
+type rawSample struct {
+	locations []uint64
+	values    []int64
+}
+
+// parseSample reads a Sample message: location_id (1) and value (2), packed
+// or not
+func parseSample(b []byte) (rawSample, error) {
+	var s rawSample
+	err := walk(b, func(field int, wire int, v uint64, packed []byte) error {
+		if field != 1 && field != 2 {
+			return nil
+		}
+		add := func(v uint64) {
+			if field == 1 {
+				s.locations = append(s.locations, v)
+			} else {
+				s.values = append(s.values, int64(v))
+			}
+		}
+		switch wire {
+		case wireVarint:
+			add(v)
+		case wireBytes:
+			for len(packed) > 0 {
+				v, n := binary.Uvarint(packed)
+				if n <= 0 {
+					return ErrNotProfile
+				}
+				add(v)
+				packed = packed[n:]
+			}
+		}
+		return nil
+	})
+	return s, err
+}
+
+// parseLocation reads a Location message: id (1) and its lines (4), each
+// with a function_id (1). Inlined functions come first.
+func parseLocation(b []byte) (uint64, []uint64, error) {
+	var id uint64
+	var funcs []uint64
+	err := walk(b, func(field int, wire int, v uint64, line []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			id = v
+		case field == 4 && wire == wireBytes:
+			return walk(line, func(field int, wire int, v uint64, _ []byte) error {
+				if field == 1 && wire == wireVarint {
+					funcs = append(funcs, v)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return id, funcs, err
+}
+
+// Protocol buffer wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// walk calls fn with each field of a protocol buffer message: the varint
+// value for varint fields, the payload for length-delimited ones
+func walk(b []byte, fn func(field int, wire int, v uint64, payload []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return ErrNotProfile
+		}
+		b = b[n:]
+		field, wire := int(key>>3), int(key&7)
+		var v uint64
+		var payload []byte
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return ErrNotProfile
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return ErrNotProfile
+			}
+			v = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return ErrNotProfile
+			}
+			v = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || size > uint64(len(b)-n) {
+				return ErrNotProfile
+			}
+			payload = b[n : n+int(size)]
+			b = b[n+int(size):]
+		default:
+			return ErrNotProfile
+		}
+		if err := fn(field, wire, v, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package project
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// annotationRetention is how long annotations are kept, like the timeline
+const annotationRetention = 90 * 24 * time.Hour
+
+// Annotation kinds
+const (
+	AnnotationNote    = "note"    // Added through the API
+	AnnotationDeploy  = "deploy"  // New git revision on start, or declared through the API (CI)
+	AnnotationConfig  = "config"  // Project configuration changed
+	AnnotationRestart = "restart" // Restarted by go-runner, e.g. by the memory guard
+	AnnotationChaos   = "chaos"   // Latency, pause or kill injected for chaos testing
+)
+
+// ProjectAnnotation marks something that happened to a project at a point in
+// time, drawn as a marker on its logs and charts
+type ProjectAnnotation struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"index:,composite:lookup"`
+	Time      time.Time `json:"time" gorm:"index:,composite:lookup"`
+	Kind      string    `json:"kind"` // note, deploy, config, restart, chaos
+	Message   string    `json:"message"`
+	Details   string    `json:"details,omitempty" gorm:"type:text"`
+	Revision  string    `json:"revision,omitempty"` // Git commit of deploys
+	Automatic bool      `json:"automatic"`          // Added by go-runner rather than through the API
+}
+
+// CreateAnnotationRequest is an annotation added through the API
+type CreateAnnotationRequest struct {
+	Message  string     `json:"message" binding:"required,max=500"`
+	Kind     string     `json:"kind" binding:"omitempty,oneof=note deploy"` // note when empty
+	Details  string     `json:"details" binding:"max=10000"`
+	Revision string     `json:"revision" binding:"max=100"`
+	Time     *time.Time `json:"time"` // Now when empty
+}
+
+// Annotator stores annotations and writes them to the logs of running
+// projects
+type Annotator struct {
+	db      *gorm.DB
+	manager *service.Manager
+	events  *events.Bus
+}
+
+// NewAnnotator creates an annotator
+func NewAnnotator(db *gorm.DB, manager *service.Manager, bus *events.Bus) *Annotator {
+	return &Annotator{db: db, manager: manager, events: bus}
+}
+
+// Add stores an annotation, writes it to the logs of the project when it is
+// running and publishes it as an "annotation" event
+func (a *Annotator) Add(annotation *ProjectAnnotation) error {
+	if annotation.Time.IsZero() {
+		annotation.Time = time.Now()
+	}
+	if err := a.db.Create(annotation).Error; err != nil {
+		return err
+	}
+	a.db.Where("project_id = ? AND time < ?", annotation.ProjectID, time.Now().Add(-annotationRetention)).Delete(&ProjectAnnotation{})
+
+	a.manager.AppendOutput(annotation.ProjectID, fmt.Sprintf("---- %s: %s ----", annotation.Kind, annotation.Message))
+	a.events.Publish(annotation.ProjectID, "annotation", annotation)
+	return nil
+}
+
+// StatusChanged adds a deploy annotation when a project starts on another
+// git revision than its last deploy
+func (a *Annotator) StatusChanged(change service.StatusChange) {
+	if change.Status != string(StatusRunning) {
+		return
+	}
+	var project Project
+	if err := a.db.Select("id, path, working_dir").First(&project, change.ProjectID).Error; err != nil {
+		return
+	}
+	dir := project.Path
+	if project.WorkingDir != "" {
+		dir = project.WorkingDir
+	}
+	revision := service.GitRevision(dir)
+	if revision == "" {
+		return
+	}
+
+	var last ProjectAnnotation
+	a.db.Where("project_id = ? AND kind = ? AND revision <> ''", project.ID, AnnotationDeploy).Order("time desc, id desc").Limit(1).Find(&last)
+	if last.Revision == revision {
+		return
+	}
+	annotation := &ProjectAnnotation{
+		ProjectID: project.ID,
+		Time:      change.Timestamp,
+		Kind:      AnnotationDeploy,
+		Message:   "Deployed " + service.ShortRevision(revision),
+		Revision:  revision,
+		Automatic: true,
+	}
+	if last.Revision != "" {
+		annotation.Details = "Previous revision " + last.Revision
+	}
+	if err := a.Add(annotation); err != nil {
+		log.Printf("Failed to add deploy annotation of project %d: %v", project.ID, err)
+	}
+}
+
+// ConfigChanged adds a config annotation listing the settings that differ
+// between two versions of a project, without their values
+func (a *Annotator) ConfigChanged(before, after *Project) {
+	changed := configChanges(before, after)
+	if len(changed) == 0 {
+		return
+	}
+	annotation := &ProjectAnnotation{
+		ProjectID: after.ID,
+		Kind:      AnnotationConfig,
+		Message:   "Configuration changed: " + strings.Join(changed, ", "),
+		Automatic: true,
+	}
+	if err := a.Add(annotation); err != nil {
+		log.Printf("Failed to add config annotation of project %d: %v", after.ID, err)
+	}
+}
+
+// configChanges returns the sorted settings of the exported configuration
+// that differ between two versions of a project
+func configChanges(before, after *Project) []string {
+	old, current := projectConfigMap(before), projectConfigMap(after)
+	var changed []string
+	for key, value := range current {
+		if !reflect.DeepEqual(old[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range old {
+		if _, ok := current[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// findAnnotations returns the annotations of a project between from and to,
+// oldest first
+func findAnnotations(db *gorm.DB, projectID uint, from, to time.Time) ([]ProjectAnnotation, error) {
+	annotations := []ProjectAnnotation{}
+	err := db.Where("project_id = ? AND time >= ? AND time <= ?", projectID, from, to).Order("time asc, id asc").Find(&annotations).Error
+	return annotations, err
+}
+
+// CreateAnnotation godoc
+// @Summary      Annotate a project
+// @Description  Mark something that happened to a project, such as a deploy from CI or a manual change, so that it shows up as a marker on its timeline, traffic and comparison charts (annotations) and as a line in its logs when it is running. go-runner adds deploy annotations itself when a project starts on a new git revision, and config annotations when its configuration is changed.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                      true  "Project ID"
+// @Param        request  body      CreateAnnotationRequest  true  "Annotation"
+// @Success      201      {object}  types.DataResponse{data=ProjectAnnotation}
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/annotations [post]
+func (h *Handler) CreateAnnotation(c *gin.Context) {
+	var req CreateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	annotation := &ProjectAnnotation{
+		ProjectID: project.ID,
+		Kind:      req.Kind,
+		Message:   req.Message,
+		Details:   req.Details,
+		Revision:  req.Revision,
+	}
+	if annotation.Kind == "" {
+		annotation.Kind = AnnotationNote
+	}
+	if req.Time != nil {
+		annotation.Time = *req.Time
+	}
+	if err := NewAnnotator(h.db, h.manager, h.events).Add(annotation); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to save annotation", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.DataResponse{Data: annotation})
+}
+
+// GetAnnotations godoc
+// @Summary      List annotations
+// @Description  Get the annotations of a project over a period, oldest first
+// @Tags         projects
+// @Produce      json
+// @Param        id     path      int     true   "Project ID"
+// @Param        from   query     string  false  "Start (RFC 3339, default 24 hours ago)"
+// @Param        to     query     string  false  "End (RFC 3339, default now)"
+// @Param        kind   query     string  false  "Only this kind (note, deploy, config)"
+// @Success      200    {object}  types.DataResponse{data=[]ProjectAnnotation}
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /projects/{id}/annotations [get]
+func (h *Handler) GetAnnotations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if raw := c.Query(param.name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, param.name+" must be an RFC 3339 time", raw))
+				return
+			}
+			*param.value = t
+		}
+	}
+
+	query := h.db.Where("project_id = ? AND time >= ? AND time <= ?", id, from, to)
+	if kind := c.Query("kind"); kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	annotations := []ProjectAnnotation{}
+	if err := query.Order("time asc, id asc").Find(&annotations).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch annotations", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: annotations})
+}
+
+// DeleteAnnotation godoc
+// @Summary      Delete an annotation
+// @Description  Delete an annotation of a project
+// @Tags         projects
+// @Produce      json
+// @Param        id             path      int  true  "Project ID"
+// @Param        annotation_id  path      int  true  "Annotation ID"
+// @Success      200            {object}  types.MessageResponse
+// @Failure      404            {object}  middleware.ErrorResponse  "Annotation not found"
+// @Router       /projects/{id}/annotations/{annotation_id} [delete]
+func (h *Handler) DeleteAnnotation(c *gin.Context) {
+	result := h.db.Where("project_id = ?", c.Param("id")).Delete(&ProjectAnnotation{}, c.Param("annotation_id"))
+	if result.Error != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete annotation", result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Annotation deleted"})
+}
@@ -0,0 +1,506 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxWorkspaceSpecs is how many applied specs are kept
+const maxWorkspaceSpecs = 20
+
+// WorkspaceSpec is an applied workspace specification; the latest one is the
+// state drift is measured against
+type WorkspaceSpec struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	Source    string    `json:"source"` // Path of the file on the server, or upload
+	Hash      string    `json:"hash"`   // sha256 of the expanded spec
+	Prune     bool      `json:"prune"`  // Projects and groups missing from the spec were deleted
+	Spec      string    `json:"-" gorm:"type:text"`
+}
+
+// TableName keeps workspace specs apart from other tables
+func (WorkspaceSpec) TableName() string {
+	return tables.Name("workspace_specs")
+}
+
+// Plan actions
+const (
+	PlanCreate = "create"
+	PlanUpdate = "update"
+	PlanDelete = "delete"
+)
+
+// FieldChange is a setting a plan changes
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// PlanChange is a project or group a plan creates, updates or deletes
+type PlanChange struct {
+	Action  string        `json:"action"` // create, update, delete
+	Kind    string        `json:"kind"`   // group, project
+	Name    string        `json:"name"`
+	ID      uint          `json:"id,omitempty"`
+	Fields  []FieldChange `json:"fields,omitempty"`
+	Running bool          `json:"running,omitempty"` // Stopped before it is deleted
+}
+
+// Plan lists the changes that make the projects match a spec
+type Plan struct {
+	Create    int          `json:"create"`
+	Update    int          `json:"update"`
+	Delete    int          `json:"delete"`
+	Unchanged int          `json:"unchanged"`
+	Changes   []PlanChange `json:"changes"`
+}
+
+// Summary describes a plan in one line
+func (p Plan) Summary() string {
+	return fmt.Sprintf("%d to create, %d to update, %d to delete, %d unchanged", p.Create, p.Update, p.Delete, p.Unchanged)
+}
+
+// ApplyResult is the plan of an apply, with the spec it stored
+type ApplyResult struct {
+	Plan
+	SpecID   uint        `json:"spec_id,omitempty"` // Not set with dry_run
+	Warnings []LintIssue `json:"warnings"`
+}
+
+// DriftReport compares the projects with the last applied spec
+type DriftReport struct {
+	Spec    WorkspaceSpec `json:"spec"`
+	InSync  bool          `json:"in_sync"`
+	Drifted Plan          `json:"drifted"` // What applying the spec again would change
+}
+
+// specDocument is a workspace spec: an import file, includes expanded
+type specDocument struct {
+	Groups   []CreateProjectGroupRequest `json:"groups"`
+	Projects []map[string]interface{}    `json:"projects"`
+}
+
+// projectConfigColumns are the fields of Project set by a spec
+var projectConfigColumns = func() []string {
+	var columns []string
+	t := reflect.TypeOf(Project{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if _, ok := projectConfigFields[name]; ok && name != "group" {
+			columns = append(columns, t.Field(i).Name)
+		}
+	}
+	return columns
+}()
+
+// plannedProject is a project of a spec with its current state
+type plannedProject struct {
+	desired Project
+	group   string   // Group name, resolved when applied
+	current *Project // nil when created
+}
+
+// applyPlan is a plan with what it applies
+type applyPlan struct {
+	Plan
+	createGroups []ProjectGroup
+	updateGroups []ProjectGroup
+	deleteGroups []ProjectGroup
+	projects     []plannedProject // Created or updated
+	deletes      []Project
+}
+
+func (p *applyPlan) add(change PlanChange) {
+	switch change.Action {
+	case PlanCreate:
+		p.Create++
+	case PlanUpdate:
+		p.Update++
+	case PlanDelete:
+		p.Delete++
+	}
+	p.Changes = append(p.Changes, change)
+}
+
+// desiredProject returns the project a spec entry describes; settings it
+// does not set keep the defaults of new projects
+func desiredProject(raw map[string]interface{}) (Project, string, error) {
+	project := Project{Type: TypeOther, MaxRestarts: 3, KubeReplicas: 1}
+	settings := make(map[string]interface{}, len(raw))
+	for key, value := range raw {
+		if _, ok := projectConfigFields[key]; ok && key != "group" {
+			settings[key] = value
+		}
+	}
+	encoded, err := json.Marshal(settings)
+	if err == nil {
+		err = json.Unmarshal(encoded, &project)
+	}
+	group, _ := raw["group"].(string)
+	return project, group, err
+}
+
+// diffConfig returns the settings of a project that differ, by name, with
+// the group by name
+func diffConfig(current *Project, currentGroup string, desired *Project, desiredGroup string) []FieldChange {
+	from := projectConfigMap(current)
+	to := projectConfigMap(desired)
+	delete(from, "group_id")
+	delete(to, "group_id")
+	from["group"] = currentGroup
+	to["group"] = desiredGroup
+
+	var changes []FieldChange
+	for _, key := range sortedKeys(to) {
+		if key != "name" && from[key] != to[key] {
+			changes = append(changes, FieldChange{Field: key, From: from[key], To: to[key]})
+		}
+	}
+	return changes
+}
+
+// plan compares the projects and groups with a spec. With prune, those
+// missing from the spec are deleted.
+func (h *Handler) plan(spec specDocument, prune bool) (*applyPlan, error) {
+	var groups []ProjectGroup
+	if err := h.db.Order("name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	var projects []Project
+	if err := h.db.Omit("Logs", "LogTimes").Order("name").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	groupNames := make(map[uint]string, len(groups))
+	groupsByName := make(map[string]ProjectGroup, len(groups))
+	for _, g := range groups {
+		groupNames[g.ID] = g.Name
+		groupsByName[g.Name] = g
+	}
+	groupName := func(id *uint) string {
+		if id == nil {
+			return ""
+		}
+		return groupNames[*id]
+	}
+
+	plan := &applyPlan{Plan: Plan{Changes: []PlanChange{}}}
+	specGroups := make(map[string]bool, len(spec.Groups))
+	for _, g := range spec.Groups {
+		specGroups[g.Name] = true
+		current, ok := groupsByName[g.Name]
+		if !ok {
+			plan.createGroups = append(plan.createGroups, ProjectGroup{Name: g.Name, Description: g.Description, Color: g.Color})
+			plan.add(PlanChange{Action: PlanCreate, Kind: "group", Name: g.Name})
+			continue
+		}
+		var fields []FieldChange
+		if current.Description != g.Description {
+			fields = append(fields, FieldChange{Field: "description", From: current.Description, To: g.Description})
+		}
+		if current.Color != g.Color {
+			fields = append(fields, FieldChange{Field: "color", From: current.Color, To: g.Color})
+		}
+		if len(fields) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		current.Description, current.Color = g.Description, g.Color
+		plan.updateGroups = append(plan.updateGroups, current)
+		plan.add(PlanChange{Action: PlanUpdate, Kind: "group", Name: g.Name, ID: current.ID, Fields: fields})
+	}
+
+	projectsByName := make(map[string]*Project, len(projects))
+	for i := range projects {
+		if projectsByName[projects[i].Name] == nil {
+			projectsByName[projects[i].Name] = &projects[i]
+		}
+	}
+	specProjects := make(map[string]bool, len(spec.Projects))
+	for _, raw := range spec.Projects {
+		desired, group, err := desiredProject(raw)
+		if err != nil {
+			return nil, fmt.Errorf("project %v: %w", raw["name"], err)
+		}
+		specProjects[desired.Name] = true
+		if group == "" && desired.GroupID != nil {
+			name, ok := groupNames[*desired.GroupID]
+			if !ok {
+				return nil, fmt.Errorf("project %s: group %d does not exist", desired.Name, *desired.GroupID)
+			}
+			group = name
+		}
+		if _, exists := groupsByName[group]; group != "" && !specGroups[group] && (!exists || prune) {
+			return nil, fmt.Errorf("project %s: group %s is not in the spec", desired.Name, group)
+		}
+
+		current := projectsByName[desired.Name]
+		if current == nil {
+			plan.projects = append(plan.projects, plannedProject{desired: desired, group: group})
+			plan.add(PlanChange{Action: PlanCreate, Kind: "project", Name: desired.Name})
+			continue
+		}
+		fields := diffConfig(current, groupName(current.GroupID), &desired, group)
+		if len(fields) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		desired.ID = current.ID
+		plan.projects = append(plan.projects, plannedProject{desired: desired, group: group, current: current})
+		plan.add(PlanChange{Action: PlanUpdate, Kind: "project", Name: desired.Name, ID: current.ID, Fields: fields})
+	}
+
+	if !prune {
+		return plan, nil
+	}
+	for i, p := range projects {
+		// Projects sharing the name of another one are duplicates
+		if !specProjects[p.Name] || projectsByName[p.Name] != &projects[i] {
+			plan.deletes = append(plan.deletes, p)
+			plan.add(PlanChange{Action: PlanDelete, Kind: "project", Name: p.Name, ID: p.ID, Running: p.Status == StatusRunning})
+		}
+	}
+	for _, g := range groups {
+		if !specGroups[g.Name] {
+			plan.deleteGroups = append(plan.deleteGroups, g)
+			plan.add(PlanChange{Action: PlanDelete, Kind: "group", Name: g.Name, ID: g.ID})
+		}
+	}
+	return plan, nil
+}
+
+// apply makes the projects and groups match a plan. Projects about to be
+// deleted are stopped first, while they still exist.
+func (h *Handler) apply(plan *applyPlan) error {
+	for _, p := range plan.deletes {
+		if p.Status == StatusRunning {
+			if err := h.manager.StopService(p.ID); err != nil {
+				log.Printf("⚠️  Failed to stop project %q before deleting it: %v", p.Name, err)
+			}
+		}
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		groupIDs := make(map[string]uint)
+		var groups []ProjectGroup
+		if err := tx.Select("id, name").Find(&groups).Error; err != nil {
+			return err
+		}
+		for _, g := range groups {
+			groupIDs[g.Name] = g.ID
+		}
+
+		for i := range plan.createGroups {
+			if err := tx.Create(&plan.createGroups[i]).Error; err != nil {
+				return fmt.Errorf("group %s: %w", plan.createGroups[i].Name, err)
+			}
+			groupIDs[plan.createGroups[i].Name] = plan.createGroups[i].ID
+		}
+		for i := range plan.updateGroups {
+			g := &plan.updateGroups[i]
+			if err := tx.Model(g).Select("Description", "Color").Updates(g).Error; err != nil {
+				return fmt.Errorf("group %s: %w", g.Name, err)
+			}
+		}
+
+		for i := range plan.projects {
+			p := &plan.projects[i]
+			p.desired.GroupID = nil
+			if p.group != "" {
+				id := groupIDs[p.group]
+				p.desired.GroupID = &id
+			}
+			if p.current == nil {
+				// Zero values get the column defaults on insert, and the
+				// inserted copy with them; the update below sets them
+				created := p.desired
+				if err := tx.Create(&created).Error; err != nil {
+					return fmt.Errorf("project %s: %w", p.desired.Name, err)
+				}
+				p.desired.ID, p.desired.CreatedAt = created.ID, created.CreatedAt
+			}
+			if err := tx.Model(&p.desired).Select(projectConfigColumns).Updates(&p.desired).Error; err != nil {
+				return fmt.Errorf("project %s: %w", p.desired.Name, err)
+			}
+			if p.current != nil {
+				if err := tx.Model(&p.desired).UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+					return fmt.Errorf("project %s: %w", p.desired.Name, err)
+				}
+			}
+		}
+
+		for _, p := range plan.deletes {
+			if err := tx.Delete(&Project{}, p.ID).Error; err != nil {
+				return fmt.Errorf("project %s: %w", p.Name, err)
+			}
+		}
+		for _, g := range plan.deleteGroups {
+			if err := tx.Model(&Project{}).Where("group_id = ?", g.ID).Update("group_id", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&ProjectGroup{}, g.ID).Error; err != nil {
+				return fmt.Errorf("group %s: %w", g.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, g := range plan.createGroups {
+		h.events.Publish(0, "group_created", g)
+	}
+	for _, g := range plan.updateGroups {
+		h.events.Publish(0, "group_updated", g)
+	}
+	annotator := NewAnnotator(h.db, h.manager, h.events)
+	for _, p := range plan.projects {
+		if p.current == nil {
+			h.events.Publish(p.desired.ID, "project_created", p.desired)
+			continue
+		}
+		h.events.Publish(p.desired.ID, "project_updated", p.desired)
+		annotator.ConfigChanged(p.current, &p.desired)
+	}
+	for _, p := range plan.deletes {
+		h.events.Publish(p.ID, "project_deleted", gin.H{"id": p.ID})
+	}
+	for _, g := range plan.deleteGroups {
+		h.events.Publish(0, "group_deleted", gin.H{"id": g.ID})
+	}
+	return nil
+}
+
+// ApplyWorkspace godoc
+// @Summary      Apply a workspace spec
+// @Description  Make the projects and groups match a workspace spec, in the import file format (body, upload or path on the server, includes and anchors included): projects and groups are matched by name, created, updated to the spec (settings it leaves out get the defaults of new projects) and, unless prune=false, deleted when missing from it; running projects are stopped before they are deleted. Projects can name their group with group. The spec is linted like imports (422 with the report on errors). dry_run returns the plan without changing anything. The applied spec is stored for GET /projects/drift.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ImportProjectsRequest  true   "Workspace spec"
+// @Param        path     query     string                 false  "Spec file on the server"
+// @Param        prune    query     bool                   false  "Delete projects and groups missing from the spec (default true)"
+// @Param        dry_run  query     bool                   false  "Only return the plan"
+// @Success      200      {object}  types.DataMessageResponse{data=ApplyResult}
+// @Failure      400      {object}  middleware.ErrorResponse                      "Bad request"
+// @Failure      422      {object}  middleware.ErrorResponse{details=LintReport}  "The spec has errors"
+// @Router       /projects/apply [post]
+func (h *Handler) ApplyWorkspace(c *gin.Context) {
+	data, path, err := readImportFile(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	prune, err := strconv.ParseBool(c.DefaultQuery("prune", "true"))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid prune", c.Query("prune")))
+		return
+	}
+
+	linter := newConfigLinter(h.db)
+	data = linter.expandImport(data, path)
+	linter.lintImport(data)
+	report := linter.result()
+	if !report.Valid {
+		middleware.HandleError(c, middleware.NewError(http.StatusUnprocessableEntity, "Spec has errors", report))
+		return
+	}
+
+	var spec specDocument
+	encoded, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(encoded, &spec)
+	}
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid spec", err.Error()))
+		return
+	}
+	plan, err := h.plan(spec, prune)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid spec", err.Error()))
+		return
+	}
+
+	result := ApplyResult{Plan: plan.Plan, Warnings: report.Warnings}
+	if dryRun, _ := strconv.ParseBool(c.Query("dry_run")); dryRun {
+		c.JSON(http.StatusOK, types.DataMessageResponse{Message: "Plan: " + plan.Summary(), Data: result})
+		return
+	}
+	if err := h.apply(plan); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to apply spec", err.Error()))
+		return
+	}
+
+	// The expanded spec, so drift does not depend on included files
+	canonical, _ := json.Marshal(spec)
+	sum := sha256.Sum256(canonical)
+	stored := WorkspaceSpec{Source: path, Hash: hex.EncodeToString(sum[:]), Prune: prune, Spec: string(canonical)}
+	if stored.Source == "" {
+		stored.Source = "upload"
+	}
+	if err := h.db.Create(&stored).Error; err != nil {
+		log.Printf("⚠️  Failed to store workspace spec: %v", err)
+	} else {
+		result.SpecID = stored.ID
+		h.db.Where("id <= ?", int(stored.ID)-maxWorkspaceSpecs).Delete(&WorkspaceSpec{})
+	}
+
+	c.JSON(http.StatusOK, types.DataMessageResponse{Message: "Applied: " + plan.Summary(), Data: result})
+}
+
+// GetWorkspaceDrift godoc
+// @Summary      Compare projects with the applied spec
+// @Description  Compare the projects and groups with the last spec applied by POST /projects/apply: drifted lists what applying it again would change, such as settings edited since, projects deleted, or projects added when the spec was applied with prune.
+// @Tags         projects
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=DriftReport}
+// @Failure      404  {object}  middleware.ErrorResponse  "No spec applied yet"
+// @Router       /projects/drift [get]
+func (h *Handler) GetWorkspaceDrift(c *gin.Context) {
+	var stored WorkspaceSpec
+	if err := h.db.Order("id desc").First(&stored).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "No workspace spec applied yet", "Use POST /projects/apply"))
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch spec", err.Error()))
+		return
+	}
+
+	var spec specDocument
+	if err := json.Unmarshal([]byte(stored.Spec), &spec); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Invalid stored spec", err.Error()))
+		return
+	}
+	plan, err := h.plan(spec, stored.Prune)
+	if err != nil {
+		// A group_id of the spec was deleted since
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Spec no longer applies", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: DriftReport{
+		Spec:    stored,
+		InSync:  len(plan.Changes) == 0,
+		Drifted: plan.Plan,
+	}})
+}
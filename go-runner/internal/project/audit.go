@@ -0,0 +1,649 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// auditHistory is the number of audits kept per project
+const auditHistory = 10
+
+// Audit finding severities. govulncheck and pip-audit do not rate
+// vulnerabilities, so their findings are "unknown".
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityModerate = "moderate"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+	SeverityUnknown  = "unknown"
+)
+
+// Audit finding kinds
+const (
+	FindingVulnerability = "vulnerability"
+	FindingOutdated      = "outdated" // Newer version available (go list -m -u)
+)
+
+// AuditFinding is a vulnerable or outdated dependency
+type AuditFinding struct {
+	Kind      string   `json:"kind"` // vulnerability, outdated
+	Package   string   `json:"package"`
+	Version   string   `json:"version,omitempty"` // Installed version or affected range
+	Severity  string   `json:"severity"`          // critical, high, moderate, low, info, unknown
+	ID        string   `json:"id,omitempty"`      // Advisory ID (GHSA-..., GO-..., PYSEC-...)
+	Aliases   []string `json:"aliases,omitempty"` // CVE-..., ...
+	Title     string   `json:"title,omitempty"`
+	URL       string   `json:"url,omitempty"`
+	FixedIn   string   `json:"fixed_in,omitempty"`  // First fixed version, or the latest version for outdated dependencies
+	Direct    *bool    `json:"direct,omitempty"`    // Direct dependency (npm)
+	Reachable *bool    `json:"reachable,omitempty"` // Vulnerable code is called by the project (govulncheck)
+}
+
+// AuditSummary counts audit findings by severity
+type AuditSummary struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Moderate int `json:"moderate"`
+	Low      int `json:"low"`
+	Info     int `json:"info"`
+	Unknown  int `json:"unknown"`
+	Total    int `json:"total"`    // Vulnerabilities
+	Outdated int `json:"outdated"` // Outdated direct dependencies
+}
+
+// DependencyAudit is the result of a dependency vulnerability audit
+type DependencyAudit struct {
+	ID        uint           `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time      `json:"created_at"`
+	ProjectID uint           `json:"project_id" gorm:"index;not null"`
+	JobID     uint           `json:"job_id"`
+	Ecosystem string         `json:"ecosystem"` // npm, go, pip
+	Tools     string         `json:"tools"`     // Commands run
+	Summary   AuditSummary   `json:"summary" gorm:"embedded;embeddedPrefix:summary_"`
+	Findings  []AuditFinding `json:"findings,omitempty" gorm:"type:text;serializer:json"`
+}
+
+// AuditRequest selects the package ecosystem to audit
+type AuditRequest struct {
+	Ecosystem string `json:"ecosystem" binding:"omitempty,oneof=npm go pip"` // Detected from package.json, go.mod or requirements.txt/pyproject.toml when empty
+}
+
+// add counts a finding
+func (s *AuditSummary) add(f AuditFinding) {
+	if f.Kind == FindingOutdated {
+		s.Outdated++
+		return
+	}
+	s.Total++
+	switch f.Severity {
+	case SeverityCritical:
+		s.Critical++
+	case SeverityHigh:
+		s.High++
+	case SeverityModerate:
+		s.Moderate++
+	case SeverityLow:
+		s.Low++
+	case SeverityInfo:
+		s.Info++
+	default:
+		s.Unknown++
+	}
+}
+
+// detectEcosystem guesses the package ecosystem of a directory
+func detectEcosystem(dir string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	switch {
+	case exists("package.json"):
+		return "npm"
+	case exists("go.mod"):
+		return "go"
+	case exists("requirements.txt"), exists("pyproject.toml"), exists("Pipfile"):
+		return "pip"
+	}
+	return ""
+}
+
+// AuditProject godoc
+// @Summary      Audit dependencies
+// @Description  Start a dependency vulnerability audit as a background job: npm audit for Node.js, govulncheck and go list -m -u for Go, pip-audit for Python. The job result is the DependencyAudit; the latest summary is included in the project listing as "audit".
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int           true   "Project ID"
+// @Param        request  body      AuditRequest  false  "Ecosystem override"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Audit job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request or unknown ecosystem"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "An audit is already running"
+// @Router       /projects/{id}/audit [post]
+func (h *Handler) AuditProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var req AuditRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	var project Project
+	if err := h.db.First(&project, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			middleware.HandleError(c, middleware.ErrNotFound)
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error()))
+		return
+	}
+
+	dir := project.Path
+	if project.WorkingDir != "" {
+		dir = project.WorkingDir
+	}
+
+	ecosystem := req.Ecosystem
+	if ecosystem == "" {
+		ecosystem = detectEcosystem(dir)
+	}
+	if ecosystem == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Cannot detect the package ecosystem",
+			"no package.json, go.mod, requirements.txt or pyproject.toml in "+dir))
+		return
+	}
+
+	projectID := project.ID
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:      jobs.TypeAudit,
+		ProjectID: &projectID,
+		Key:       fmt.Sprintf("audit:%d", projectID),
+		Message:   "Auditing " + ecosystem + " dependencies",
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		audit, err := runAudit(ctx, run, ecosystem, dir)
+		if err != nil {
+			return nil, err
+		}
+		audit.ProjectID = projectID
+		audit.JobID = run.ID()
+		if err := h.saveAudit(audit); err != nil {
+			return nil, err
+		}
+		run.Progress(100, fmt.Sprintf("%d vulnerabilities, %d outdated dependencies", audit.Summary.Total, audit.Summary.Outdated))
+		return audit, nil
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "An audit is already running for this project", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// GetProjectAudit godoc
+// @Summary      Get the latest dependency audit
+// @Description  Get the findings of the latest dependency audit of a project
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=DependencyAudit}
+// @Failure      400  {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found or never audited"
+// @Router       /projects/{id}/audit [get]
+func (h *Handler) GetProjectAudit(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var audit DependencyAudit
+	if err := h.db.Where("project_id = ?", id).Order("id DESC").First(&audit).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Project has not been audited", nil))
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch audit", err.Error()))
+		return
+	}
+	if audit.Findings == nil {
+		audit.Findings = []AuditFinding{}
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: audit})
+}
+
+// saveAudit stores an audit and prunes the project's audit history
+func (h *Handler) saveAudit(audit *DependencyAudit) error {
+	if err := h.db.Create(audit).Error; err != nil {
+		return fmt.Errorf("failed to save audit: %v", err)
+	}
+
+	var stale []uint
+	h.db.Model(&DependencyAudit{}).Where("project_id = ?", audit.ProjectID).
+		Order("id DESC").Offset(auditHistory).Pluck("id", &stale)
+	if len(stale) > 0 {
+		h.db.Delete(&DependencyAudit{}, stale)
+	}
+	return nil
+}
+
+// attachLatestAudits sets the latest audit summary of each project
+func (h *Handler) attachLatestAudits(projects []Project) {
+	if len(projects) == 0 {
+		return
+	}
+
+	ids := make([]uint, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+
+	var audits []DependencyAudit
+	latest := h.db.Model(&DependencyAudit{}).Select("MAX(id)").Where("project_id IN ?", ids).Group("project_id")
+	if err := h.db.Omit("findings").Where("id IN (?)", latest).Find(&audits).Error; err != nil {
+		return
+	}
+
+	byProject := make(map[uint]*DependencyAudit, len(audits))
+	for i := range audits {
+		byProject[audits[i].ProjectID] = &audits[i]
+	}
+	for i := range projects {
+		projects[i].Audit = byProject[projects[i].ID]
+	}
+}
+
+// runAudit runs the audit tools of an ecosystem in dir
+func runAudit(ctx context.Context, run *jobs.Run, ecosystem, dir string) (*DependencyAudit, error) {
+	audit := &DependencyAudit{Ecosystem: ecosystem, Findings: []AuditFinding{}}
+	var tools []string
+
+	step := func(percent int, name string, args []string, parse func([]byte) ([]AuditFinding, error)) error {
+		run.Progress(percent, "Running "+name)
+		tools = append(tools, strings.Join(args, " "))
+
+		output, err := runAuditTool(ctx, dir, args)
+		if err != nil {
+			return err
+		}
+		findings, err := parse(output)
+		if err != nil {
+			run.Log(string(output))
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		audit.Findings = append(audit.Findings, findings...)
+		return nil
+	}
+
+	var err error
+	switch ecosystem {
+	case "npm":
+		err = step(10, "npm audit", []string{"npm", "audit", "--json"}, parseNpmAudit)
+	case "go":
+		err = step(10, "govulncheck", []string{"govulncheck", "-json", "./..."}, parseGovulncheck)
+		if err == nil {
+			err = step(60, "go list -m -u", []string{"go", "list", "-m", "-u", "-json", "all"}, parseGoListUpdates)
+		}
+	case "pip":
+		args := []string{"pip-audit", "-f", "json", "--progress-spinner", "off"}
+		if _, statErr := os.Stat(filepath.Join(dir, "requirements.txt")); statErr == nil {
+			args = append(args, "-r", "requirements.txt")
+		} else {
+			args = append(args, ".")
+		}
+		err = step(10, "pip-audit", args, parsePipAudit)
+	default:
+		err = fmt.Errorf("unsupported ecosystem %q", ecosystem)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Most severe first, outdated dependencies last
+	rank := map[string]int{SeverityCritical: 0, SeverityHigh: 1, SeverityModerate: 2, SeverityLow: 3, SeverityUnknown: 4, SeverityInfo: 5}
+	sort.SliceStable(audit.Findings, func(i, j int) bool {
+		a, b := audit.Findings[i], audit.Findings[j]
+		if (a.Kind == FindingOutdated) != (b.Kind == FindingOutdated) {
+			return b.Kind == FindingOutdated
+		}
+		if rank[a.Severity] != rank[b.Severity] {
+			return rank[a.Severity] < rank[b.Severity]
+		}
+		return a.Package < b.Package
+	})
+
+	audit.Tools = strings.Join(tools, "; ")
+	for _, f := range audit.Findings {
+		audit.Summary.add(f)
+	}
+	return audit, nil
+}
+
+// runAuditTool runs an audit command and returns its stdout. Audit tools exit
+// non-zero when they find vulnerabilities, so the exit status is left to the
+// parser; a missing tool is an error.
+func runAuditTool(ctx context.Context, dir string, args []string) ([]byte, error) {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		hint := map[string]string{
+			"govulncheck": " (install with: go install golang.org/x/vuln/cmd/govulncheck@latest)",
+			"pip-audit":   " (install with: pip install pip-audit)",
+		}[args[0]]
+		return nil, fmt.Errorf("%s is not installed%s", args[0], hint)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if stdout.Len() == 0 && err != nil {
+		return nil, fmt.Errorf("%s failed: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseNpmAudit parses `npm audit --json` (npm 7+)
+func parseNpmAudit(output []byte) ([]AuditFinding, error) {
+	var report struct {
+		Error *struct {
+			Code    string `json:"code"`
+			Summary string `json:"summary"`
+		} `json:"error"`
+		Vulnerabilities map[string]struct {
+			Name     string            `json:"name"`
+			Severity string            `json:"severity"`
+			IsDirect bool              `json:"isDirect"`
+			Range    string            `json:"range"`
+			Via      []json.RawMessage `json:"via"`
+			Fix      json.RawMessage   `json:"fixAvailable"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("unexpected output: %v", err)
+	}
+	if report.Error != nil {
+		return nil, fmt.Errorf("%s: %s", report.Error.Code, report.Error.Summary)
+	}
+
+	findings := []AuditFinding{}
+	for name, vuln := range report.Vulnerabilities {
+		direct := vuln.IsDirect
+		fixedIn := ""
+		var fix struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(vuln.Fix, &fix) == nil && fix.Name == name {
+			fixedIn = fix.Version
+		}
+
+		// via lists advisories, or names of vulnerable dependencies for
+		// packages that are only affected transitively
+		var advisories, through []string
+		for _, raw := range vuln.Via {
+			var advisory struct {
+				Source   interface{} `json:"source"`
+				Title    string      `json:"title"`
+				URL      string      `json:"url"`
+				Severity string      `json:"severity"`
+				Range    string      `json:"range"`
+			}
+			var dependency string
+			if json.Unmarshal(raw, &dependency) == nil {
+				through = append(through, dependency)
+				continue
+			}
+			if json.Unmarshal(raw, &advisory) != nil {
+				continue
+			}
+			advisories = append(advisories, advisory.URL)
+			findings = append(findings, AuditFinding{
+				Kind:     FindingVulnerability,
+				Package:  name,
+				Version:  advisory.Range,
+				Severity: npmSeverity(advisory.Severity),
+				ID:       advisoryID(advisory.URL, advisory.Source),
+				Title:    advisory.Title,
+				URL:      advisory.URL,
+				FixedIn:  fixedIn,
+				Direct:   &direct,
+			})
+		}
+		if len(advisories) == 0 && len(through) > 0 {
+			findings = append(findings, AuditFinding{
+				Kind:     FindingVulnerability,
+				Package:  name,
+				Version:  vuln.Range,
+				Severity: npmSeverity(vuln.Severity),
+				Title:    "Depends on vulnerable " + strings.Join(through, ", "),
+				FixedIn:  fixedIn,
+				Direct:   &direct,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// npmSeverity normalizes npm severities
+func npmSeverity(severity string) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh, SeverityModerate, SeverityLow, SeverityInfo:
+		return severity
+	}
+	return SeverityUnknown
+}
+
+// advisoryID returns the GHSA ID of an advisory URL, or the npm source ID
+func advisoryID(url string, source interface{}) string {
+	if i := strings.LastIndex(url, "/"); i >= 0 && strings.HasPrefix(url[i+1:], "GHSA-") {
+		return url[i+1:]
+	}
+	if source != nil {
+		return fmt.Sprint(source)
+	}
+	return ""
+}
+
+// parseGovulncheck parses the JSON message stream of `govulncheck -json`
+func parseGovulncheck(output []byte) ([]AuditFinding, error) {
+	type frame struct {
+		Module   string `json:"module"`
+		Version  string `json:"version"`
+		Package  string `json:"package"`
+		Function string `json:"function"`
+	}
+	type message struct {
+		OSV *struct {
+			ID       string   `json:"id"`
+			Summary  string   `json:"summary"`
+			Aliases  []string `json:"aliases"`
+			Database struct {
+				URL string `json:"url"`
+			} `json:"database_specific"`
+		} `json:"osv"`
+		Finding *struct {
+			OSV          string  `json:"osv"`
+			FixedVersion string  `json:"fixed_version"`
+			Trace        []frame `json:"trace"`
+		} `json:"finding"`
+	}
+
+	byID := make(map[string]*AuditFinding)
+	var order []string
+	osvs := make(map[string]AuditFinding)
+
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var msg message
+		if err := decoder.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unexpected output: %v", err)
+		}
+
+		if msg.OSV != nil {
+			osvs[msg.OSV.ID] = AuditFinding{Title: msg.OSV.Summary, Aliases: msg.OSV.Aliases, URL: msg.OSV.Database.URL}
+		}
+		if msg.Finding == nil || len(msg.Finding.Trace) == 0 {
+			continue
+		}
+
+		// Findings are reported per module, package and symbol; keep one per
+		// advisory and whether any of them reaches vulnerable code
+		top := msg.Finding.Trace[0]
+		reachable := top.Function != ""
+		f, ok := byID[msg.Finding.OSV]
+		if !ok {
+			f = &AuditFinding{
+				Kind:      FindingVulnerability,
+				Package:   top.Module,
+				Version:   top.Version,
+				Severity:  SeverityUnknown,
+				ID:        msg.Finding.OSV,
+				FixedIn:   msg.Finding.FixedVersion,
+				Reachable: &reachable,
+			}
+			byID[msg.Finding.OSV] = f
+			order = append(order, msg.Finding.OSV)
+		} else if reachable {
+			f.Reachable = &reachable
+		}
+	}
+
+	findings := make([]AuditFinding, 0, len(order))
+	for _, id := range order {
+		f := *byID[id]
+		if osv, ok := osvs[id]; ok {
+			f.Title, f.Aliases, f.URL = osv.Title, osv.Aliases, osv.URL
+		}
+		if f.URL == "" {
+			f.URL = "https://pkg.go.dev/vuln/" + id
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// parseGoListUpdates parses `go list -m -u -json all` into outdated direct
+// dependencies
+func parseGoListUpdates(output []byte) ([]AuditFinding, error) {
+	findings := []AuditFinding{}
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var module struct {
+			Path     string
+			Version  string
+			Main     bool
+			Indirect bool
+			Update   *struct {
+				Version string
+			}
+		}
+		if err := decoder.Decode(&module); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unexpected output: %v", err)
+		}
+		if module.Main || module.Indirect || module.Update == nil {
+			continue
+		}
+		findings = append(findings, AuditFinding{
+			Kind:     FindingOutdated,
+			Package:  module.Path,
+			Version:  module.Version,
+			Severity: SeverityInfo,
+			FixedIn:  module.Update.Version,
+		})
+	}
+	return findings, nil
+}
+
+// parsePipAudit parses `pip-audit -f json`, both the current object form and
+// the list form of older releases
+func parsePipAudit(output []byte) ([]AuditFinding, error) {
+	type dependency struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Vulns   []struct {
+			ID          string   `json:"id"`
+			FixVersions []string `json:"fix_versions"`
+			Aliases     []string `json:"aliases"`
+			Description string   `json:"description"`
+		} `json:"vulns"`
+	}
+
+	var report struct {
+		Dependencies []dependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		if err := json.Unmarshal(output, &report.Dependencies); err != nil {
+			return nil, fmt.Errorf("unexpected output: %v", err)
+		}
+	}
+
+	findings := []AuditFinding{}
+	for _, dep := range report.Dependencies {
+		for _, vuln := range dep.Vulns {
+			f := AuditFinding{
+				Kind:     FindingVulnerability,
+				Package:  dep.Name,
+				Version:  dep.Version,
+				Severity: SeverityUnknown,
+				ID:       vuln.ID,
+				Aliases:  vuln.Aliases,
+				Title:    firstLine(vuln.Description),
+			}
+			if len(vuln.FixVersions) > 0 {
+				f.FixedIn = vuln.FixVersions[0]
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// firstLine returns the first line of s, shortened to 200 characters
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if len(s) > 200 {
+		s = s[:200] + "..."
+	}
+	return s
+}
@@ -0,0 +1,24 @@
+package project
+
+import (
+	"go-runner/internal/events"
+	"go-runner/internal/tables"
+
+	"gorm.io/gorm"
+)
+
+// CatalogInfo looks up the owner, team and links of projects, which the
+// event bus adds to their alert events
+func CatalogInfo(db *gorm.DB) func(projectID uint) *events.ProjectInfo {
+	return func(projectID uint) *events.ProjectInfo {
+		var info events.ProjectInfo
+		err := db.Table(tables.Name("projects")).
+			Select("name, owner, team, repository_url, docs_url, chat_channel").
+			Where("id = ?", projectID).
+			Take(&info).Error
+		if err != nil {
+			return nil
+		}
+		return &info
+	}
+}
@@ -0,0 +1,254 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultChaosDuration is how long latency is injected, in seconds
+const defaultChaosDuration = 60
+
+// ChaosLatencyRequest injects latency into the proxied requests of a project
+type ChaosLatencyRequest struct {
+	DelayMs  int `json:"delay_ms" binding:"required,min=1,max=60000"`
+	JitterMs int `json:"jitter_ms" binding:"min=0,max=60000"`         // Up to this much more, at random
+	Duration int `json:"duration" binding:"omitempty,min=1,max=3600"` // Seconds, 60 when 0
+}
+
+// ChaosPauseRequest suspends a service for a while
+type ChaosPauseRequest struct {
+	Duration int `json:"duration" binding:"required,min=1,max=3600"` // Seconds
+}
+
+// ChaosKillResult is the instance hit by a random kill
+type ChaosKillResult struct {
+	service.ChaosTarget
+	ProjectName string `json:"project_name"`
+}
+
+// GetChaos godoc
+// @Summary      Get the chaos applied to a project
+// @Description  Show the latency injected into the proxied requests of a project and the pause it is under, if any.
+// @Tags         chaos
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.ChaosStatus}
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/chaos [get]
+func (h *Handler) GetChaos(c *gin.Context) {
+	project, ok := h.chaosProject(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: h.manager.ChaosStatus(project.ID)})
+}
+
+// InjectLatency godoc
+// @Summary      Inject latency at the proxy
+// @Description  Hold each request proxied to the project (/projects/{id}/proxy/) for delay_ms milliseconds, plus up to jitter_ms more at random, during duration seconds (default 60), to see how its clients cope with a slow service. A new call replaces the previous latency. Recorded as a chaos annotation.
+// @Tags         chaos
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true  "Project ID"
+// @Param        request  body      ChaosLatencyRequest  true  "Delay and duration"
+// @Success      200      {object}  types.DataResponse{data=service.ProxyLatency}
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request or project without port"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/chaos/latency [post]
+func (h *Handler) InjectLatency(c *gin.Context) {
+	var req ChaosLatencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if req.Duration == 0 {
+		req.Duration = defaultChaosDuration
+	}
+	project, ok := h.chaosProject(c)
+	if !ok {
+		return
+	}
+	if project.Port == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project has no port to proxy to", nil))
+		return
+	}
+
+	latency := h.manager.InjectLatency(project.ID,
+		time.Duration(req.DelayMs)*time.Millisecond,
+		time.Duration(req.JitterMs)*time.Millisecond,
+		time.Duration(req.Duration)*time.Second)
+	message := fmt.Sprintf("Chaos: %d ms latency on proxied requests for %ds", req.DelayMs, req.Duration)
+	if req.JitterMs > 0 {
+		message = fmt.Sprintf("Chaos: %d-%d ms latency on proxied requests for %ds", req.DelayMs, req.DelayMs+req.JitterMs, req.Duration)
+	}
+	h.annotateChaos(project.ID, message)
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: latency})
+}
+
+// ClearLatency godoc
+// @Summary      Remove injected latency
+// @Description  Stop delaying the requests proxied to the project before the end of the injected latency.
+// @Tags         chaos
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.MessageResponse
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found or no latency injected"
+// @Router       /projects/{id}/chaos/latency [delete]
+func (h *Handler) ClearLatency(c *gin.Context) {
+	project, ok := h.chaosProject(c)
+	if !ok {
+		return
+	}
+	if !h.manager.ClearLatency(project.ID) {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "No latency injected", nil))
+		return
+	}
+	h.annotateChaos(project.ID, "Chaos: latency removed")
+
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Latency removed"})
+}
+
+// ChaosPause godoc
+// @Summary      Pause a service for a while
+// @Description  Suspend the process of a running project, its descendants and extra instances with SIGSTOP for duration seconds, then continue them with SIGCONT, to see how the services that depend on it react to one that hangs. Like POST /projects/{id}/pause, but timed and recorded as a chaos annotation.
+// @Tags         chaos
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                true  "Project ID"
+// @Param        request  body      ChaosPauseRequest  true  "Duration"
+// @Success      200      {object}  types.DataResponse{data=service.ServicePause}
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Service not running or already paused"
+// @Failure      422      {object}  middleware.ErrorResponse  "No local process to pause"
+// @Router       /projects/{id}/chaos/pause [post]
+func (h *Handler) ChaosPause(c *gin.Context) {
+	var req ChaosPauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	project, ok := h.chaosProject(c)
+	if !ok {
+		return
+	}
+
+	pause, err := h.manager.PauseService(project.ID, time.Duration(req.Duration)*time.Second)
+	switch {
+	case errors.Is(err, service.ErrServiceNotRunning):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is not running", err.Error()))
+		return
+	case errors.Is(err, service.ErrAlreadyPaused):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is already paused", err.Error()))
+		return
+	case errors.Is(err, service.ErrNotPausable):
+		middleware.HandleError(c, middleware.NewError(http.StatusUnprocessableEntity, "No local process to pause", err.Error()))
+		return
+	case err != nil:
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to pause service", err.Error()))
+		return
+	}
+	h.annotateChaos(project.ID, fmt.Sprintf("Chaos: paused %d process(es) for %ds", len(pause.PIDs), req.Duration))
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: pause})
+}
+
+// ChaosResume godoc
+// @Summary      End a pause early
+// @Description  Continue a paused service before the end of its pause, recorded as a chaos annotation.
+// @Tags         chaos
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.MessageResponse
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found or not paused"
+// @Router       /projects/{id}/chaos/pause [delete]
+func (h *Handler) ChaosResume(c *gin.Context) {
+	project, ok := h.chaosProject(c)
+	if !ok {
+		return
+	}
+	if !h.manager.ResumeService(project.ID) {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Service is not paused", nil))
+		return
+	}
+	h.annotateChaos(project.ID, "Chaos: resumed")
+
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Service resumed"})
+}
+
+// ChaosKill godoc
+// @Summary      Kill a random instance of a group
+// @Description  Send SIGKILL to one running process picked at random among the projects of a group and their extra instances (instances), as a crash: projects with auto_restart are restarted like after any other crash. Services run through Kubernetes, systemd or SSH are not candidates. Recorded as a chaos annotation of the project hit.
+// @Tags         chaos
+// @Produce      json
+// @Param        id   path      int  true  "Group ID"
+// @Success      200  {object}  types.DataResponse{data=ChaosKillResult}  "Instance killed"
+// @Failure      404  {object}  middleware.ErrorResponse                  "Group not found"
+// @Failure      409  {object}  middleware.ErrorResponse                  "No running instance in the group"
+// @Router       /groups/{id}/chaos/kill [post]
+func (h *Handler) ChaosKill(c *gin.Context) {
+	var group ProjectGroup
+	if err := h.db.First(&group, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	var projects []Project
+	if err := h.db.Select("id, name").Where("group_id = ?", group.ID).Find(&projects).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
+		return
+	}
+	names := make(map[uint]string, len(projects))
+	ids := make([]uint, 0, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+		ids = append(ids, p.ID)
+	}
+
+	targets := h.manager.ChaosTargets(ids)
+	if len(targets) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "No running instance in the group", nil))
+		return
+	}
+	target := service.PickChaosTarget(targets)
+	if err := h.manager.KillInstance(target); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to kill instance", err.Error()))
+		return
+	}
+	h.annotateChaos(target.ProjectID, fmt.Sprintf("Chaos: killed instance %d (PID %d) of group %s", target.Index, target.PID, group.Name))
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: ChaosKillResult{ChaosTarget: target, ProjectName: names[target.ProjectID]}})
+}
+
+// chaosProject loads the project of a chaos request, answering the errors
+func (h *Handler) chaosProject(c *gin.Context) (*Project, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return nil, false
+	}
+	var project Project
+	if err := h.db.Select("id, name, port").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return nil, false
+	}
+	return &project, true
+}
+
+// annotateChaos records chaos injected into a project
+func (h *Handler) annotateChaos(projectID uint, message string) {
+	annotation := &ProjectAnnotation{ProjectID: projectID, Kind: AnnotationChaos, Message: message, Automatic: true}
+	if err := NewAnnotator(h.db, h.manager, h.events).Add(annotation); err != nil {
+		log.Printf("Failed to add chaos annotation of project %d: %v", projectID, err)
+	}
+}
@@ -0,0 +1,333 @@
+package project
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComparisonPoint is one step after an event: averages of the CPU and memory
+// samples and totals of the proxied traffic within it
+type ComparisonPoint struct {
+	OffsetSeconds int      `json:"offset_seconds"` // Start of the step, from the event
+	CPUPercent    *float64 `json:"cpu_percent"`
+	MemoryBytes   *float64 `json:"memory_bytes"`
+	Requests      uint64   `json:"requests"`
+	Errors        uint64   `json:"errors"`
+	ErrorRate     *float64 `json:"error_rate"` // Percent of requests, null without requests
+}
+
+// ComparisonSummary sums up the window after an event
+type ComparisonSummary struct {
+	Samples             int      `json:"samples"` // CPU and memory samples
+	AvgCPUPercent       *float64 `json:"avg_cpu_percent"`
+	MaxCPUPercent       *float64 `json:"max_cpu_percent"`
+	AvgMemoryBytes      *float64 `json:"avg_memory_bytes"`
+	MaxMemoryBytes      *float64 `json:"max_memory_bytes"`
+	MemoryGrowthPerHour *float64 `json:"memory_growth_per_hour"` // Bytes, slope of a linear fit of the samples; steady growth hints at a leak
+	Requests            uint64   `json:"requests"`
+	Errors              uint64   `json:"errors"`
+	ErrorRate           *float64 `json:"error_rate"`
+}
+
+// ComparisonSide is the metrics after one event
+type ComparisonSide struct {
+	Event       ProjectStatusHistory `json:"event"`
+	Until       time.Time            `json:"until"` // End of the window: the window length, the other event or now
+	Points      []ComparisonPoint    `json:"points"`
+	Summary     ComparisonSummary    `json:"summary"`
+	Annotations []ProjectAnnotation  `json:"annotations"` // Markers in the window: deploys, config changes and notes
+}
+
+// ComparisonDelta is the change from the first event to the second one,
+// null when either side has no data
+type ComparisonDelta struct {
+	AvgCPUPercent       *float64 `json:"avg_cpu_percent"`
+	AvgMemoryBytes      *float64 `json:"avg_memory_bytes"`
+	MaxMemoryBytes      *float64 `json:"max_memory_bytes"`
+	MemoryGrowthPerHour *float64 `json:"memory_growth_per_hour"`
+	ErrorRate           *float64 `json:"error_rate"`
+}
+
+// Comparison aligns the metrics of a project after two lifecycle events
+type Comparison struct {
+	ProjectID     uint                   `json:"project_id"`
+	WindowSeconds int                    `json:"window_seconds"`
+	StepSeconds   int                    `json:"step_seconds"`
+	From          ComparisonSide         `json:"from"`
+	To            ComparisonSide         `json:"to"`
+	Delta         ComparisonDelta        `json:"delta"`
+	Starts        []ProjectStatusHistory `json:"starts"` // Recent starts, newest first, to pick events from
+}
+
+// CompareEvents godoc
+// @Summary      Compare metrics after two lifecycle events
+// @Description  Align the CPU, memory and error rate of a project after two events of its status timeline, such as the previous start and the current one, to see whether a new build uses more resources or leaks memory. Events are status transition IDs (see "starts" in the response); without them the last two starts (transitions to running) are compared. Each side covers `minutes` after its event, cut at the later event and now, in steps of `step` seconds; CPU and memory are sampled every 30 seconds while a project runs and kept for 7 days, errors come from the traffic proxied through /projects/{id}/proxy.
+// @Tags         projects
+// @Produce      json
+// @Param        id          path      int  true   "Project ID"
+// @Param        from_event  query     int  false  "Status transition ID of the first event (default: the start before to_event)"
+// @Param        to_event    query     int  false  "Status transition ID of the second event (default: the last start)"
+// @Param        minutes     query     int  false  "Window after each event (default 60, max 1440)"
+// @Param        step        query     int  false  "Seconds per point (default 60, min 30)"
+// @Success      200         {object}  types.DataResponse{data=Comparison}
+// @Failure      400         {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404         {object}  middleware.ErrorResponse  "Project or event not found"
+// @Router       /projects/{id}/compare [get]
+func (h *Handler) CompareEvents(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id, name").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	params := map[string]int{"from_event": 0, "to_event": 0, "minutes": 60, "step": 60}
+	for name := range params {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 1 {
+				middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid "+name, raw))
+				return
+			}
+			params[name] = value
+		}
+	}
+	if params["minutes"] > 1440 || params["step"] < 30 || params["step"] > params["minutes"]*60 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "minutes must be at most 1440 and step between 30 seconds and the window", nil))
+		return
+	}
+
+	var starts []ProjectStatusHistory
+	if err := h.db.Where("project_id = ? AND status = ?", project.ID, string(StatusRunning)).
+		Order("timestamp desc, id desc").Limit(20).Find(&starts).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch status history", err.Error()))
+		return
+	}
+
+	to, ok := h.comparisonEvent(c, project.ID, params["to_event"], starts, time.Now())
+	if !ok {
+		return
+	}
+	from, ok := h.comparisonEvent(c, project.ID, params["from_event"], starts, to.Timestamp)
+	if !ok {
+		return
+	}
+	if !from.Timestamp.Before(to.Timestamp) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "from_event must be before to_event", nil))
+		return
+	}
+
+	window := time.Duration(params["minutes"]) * time.Minute
+	step := time.Duration(params["step"]) * time.Second
+	now := time.Now()
+	comparison := Comparison{
+		ProjectID:     project.ID,
+		WindowSeconds: int(window.Seconds()),
+		StepSeconds:   params["step"],
+		Starts:        starts,
+	}
+	var err error
+	if comparison.From, err = h.comparisonSide(project.ID, *from, minTime(from.Timestamp.Add(window), to.Timestamp), window, step); err == nil {
+		comparison.To, err = h.comparisonSide(project.ID, *to, minTime(to.Timestamp.Add(window), now), window, step)
+	}
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch metrics", err.Error()))
+		return
+	}
+
+	a, b := comparison.From.Summary, comparison.To.Summary
+	comparison.Delta = ComparisonDelta{
+		AvgCPUPercent:       change(a.AvgCPUPercent, b.AvgCPUPercent),
+		AvgMemoryBytes:      change(a.AvgMemoryBytes, b.AvgMemoryBytes),
+		MaxMemoryBytes:      change(a.MaxMemoryBytes, b.MaxMemoryBytes),
+		MemoryGrowthPerHour: change(a.MemoryGrowthPerHour, b.MemoryGrowthPerHour),
+		ErrorRate:           change(a.ErrorRate, b.ErrorRate),
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: comparison})
+}
+
+// comparisonEvent loads a status transition of a project, or picks the
+// latest start before a time when id is 0
+func (h *Handler) comparisonEvent(c *gin.Context, projectID uint, id int, starts []ProjectStatusHistory, before time.Time) (*ProjectStatusHistory, bool) {
+	if id == 0 {
+		for i := range starts {
+			if starts[i].Timestamp.Before(before) {
+				return &starts[i], true
+			}
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Not enough starts to compare", "pass from_event and to_event"))
+		return nil, false
+	}
+
+	var event ProjectStatusHistory
+	if err := h.db.Where("id = ? AND project_id = ?", id, projectID).First(&event).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Event not found", id))
+		return nil, false
+	}
+	return &event, true
+}
+
+// comparisonSide buckets the metrics of a project from an event to until
+func (h *Handler) comparisonSide(projectID uint, event ProjectStatusHistory, until time.Time, window, step time.Duration) (ComparisonSide, error) {
+	side := ComparisonSide{Event: event, Until: until}
+
+	var samples []ProcessMetric
+	if err := h.db.Where("project_id = ? AND timestamp >= ? AND timestamp < ?", projectID, event.Timestamp, until).
+		Order("timestamp").Find(&samples).Error; err != nil {
+		return side, err
+	}
+	annotations, err := findAnnotations(h.db, projectID, event.Timestamp, until)
+	if err != nil {
+		return side, err
+	}
+	side.Annotations = annotations
+
+	// Traffic samples are stamped with the end of their interval
+	var traffic []TrafficMetric
+	if err := h.db.Where("project_id = ? AND timestamp > ? AND timestamp <= ?", projectID, event.Timestamp, until).
+		Order("timestamp").Find(&traffic).Error; err != nil {
+		return side, err
+	}
+
+	type bucket struct {
+		cpu, memory      float64
+		cpuN, memoryN    int
+		requests, errors uint64
+	}
+	buckets := make([]bucket, int(math.Ceil(float64(window)/float64(step))))
+	index := func(t time.Time) int {
+		i := int(t.Sub(event.Timestamp) / step)
+		return min(max(i, 0), len(buckets)-1)
+	}
+
+	summary := &side.Summary
+	var xs, ys []float64
+	for _, s := range samples {
+		b := &buckets[index(s.Timestamp)]
+		if s.CPUPercent != nil {
+			b.cpu += *s.CPUPercent
+			b.cpuN++
+			summary.AvgCPUPercent = accumulate(summary.AvgCPUPercent, *s.CPUPercent)
+			summary.MaxCPUPercent = maxOf(summary.MaxCPUPercent, *s.CPUPercent)
+		}
+		memory := float64(s.MemoryBytes)
+		b.memory += memory
+		b.memoryN++
+		summary.AvgMemoryBytes = accumulate(summary.AvgMemoryBytes, memory)
+		summary.MaxMemoryBytes = maxOf(summary.MaxMemoryBytes, memory)
+		xs = append(xs, s.Timestamp.Sub(event.Timestamp).Hours())
+		ys = append(ys, memory)
+	}
+	for _, t := range traffic {
+		b := &buckets[index(t.Timestamp.Add(-time.Nanosecond))]
+		b.requests += t.Requests
+		b.errors += t.Errors
+		summary.Requests += t.Requests
+		summary.Errors += t.Errors
+	}
+
+	summary.Samples = len(samples)
+	if summary.AvgCPUPercent != nil {
+		cpuN := 0
+		for _, b := range buckets {
+			cpuN += b.cpuN
+		}
+		*summary.AvgCPUPercent /= float64(cpuN)
+	}
+	if summary.AvgMemoryBytes != nil {
+		*summary.AvgMemoryBytes /= float64(len(samples))
+	}
+	summary.MemoryGrowthPerHour = slope(xs, ys)
+	summary.ErrorRate = errorRate(summary.Requests, summary.Errors)
+
+	side.Points = []ComparisonPoint{}
+	for i, b := range buckets {
+		start := event.Timestamp.Add(time.Duration(i) * step)
+		if !start.Before(until) {
+			break
+		}
+		point := ComparisonPoint{
+			OffsetSeconds: int((time.Duration(i) * step).Seconds()),
+			Requests:      b.requests,
+			Errors:        b.errors,
+			ErrorRate:     errorRate(b.requests, b.errors),
+		}
+		if b.cpuN > 0 {
+			cpu := b.cpu / float64(b.cpuN)
+			point.CPUPercent = &cpu
+		}
+		if b.memoryN > 0 {
+			memory := b.memory / float64(b.memoryN)
+			point.MemoryBytes = &memory
+		}
+		side.Points = append(side.Points, point)
+	}
+	return side, nil
+}
+
+// slope fits y = a + b*x by least squares and returns b, nil with fewer
+// than two distinct x
+func slope(xs, ys []float64) *float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return nil
+	}
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	d := n*sxx - sx*sx
+	if d == 0 {
+		return nil
+	}
+	b := (n*sxy - sx*sy) / d
+	return &b
+}
+
+func errorRate(requests, errors uint64) *float64 {
+	if requests == 0 {
+		return nil
+	}
+	rate := float64(errors) / float64(requests) * 100
+	return &rate
+}
+
+func accumulate(sum *float64, v float64) *float64 {
+	if sum == nil {
+		return &v
+	}
+	*sum += v
+	return sum
+}
+
+func maxOf(m *float64, v float64) *float64 {
+	if m == nil || v > *m {
+		return &v
+	}
+	return m
+}
+
+func change(a, b *float64) *float64 {
+	if a == nil || b == nil {
+		return nil
+	}
+	d := *b - *a
+	return &d
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,99 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDatabaseStatus godoc
+// @Summary      Check a database project
+// @Description  Connect to the connection string of a database project (postgres://, mysql://, redis:// or a SQLite file) and return its version, size and active connections. The result is stored as the project health status. Database projects are also checked every 30 seconds; the last result is included as "database" in the project status.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.DatabaseStats}  "Check result, healthy or not"
+// @Failure      400  {object}  middleware.ErrorResponse  "No connection string"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/database [get]
+func (h *Handler) GetDatabaseStatus(c *gin.Context) {
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if project.ConnectionString == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project has no connection string", nil))
+		return
+	}
+
+	var stats *service.DatabaseStats = h.manager.CheckDatabase(c.Request.Context(), project.ID, project.ConnectionString)
+	c.JSON(http.StatusOK, types.DataResponse{Data: stats})
+}
+
+// MigrateDatabase godoc
+// @Summary      Run the migration command
+// @Description  Run the migration command of a project in its working directory as a background job, with DATABASE_URL set to the connection string. Output is streamed to the project WebSocket as "migration_log" messages (InstallLogLine).
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      202  {object}  types.DataResponse{data=jobs.Job}  "Migration job queued"
+// @Failure      400  {object}  middleware.ErrorResponse  "No migration command"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409  {object}  middleware.ErrorResponse  "A migration is already running"
+// @Router       /projects/{id}/database/migrate [post]
+func (h *Handler) MigrateDatabase(c *gin.Context) {
+	project, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if project.MigrationCommand == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project has no migration command", nil))
+		return
+	}
+
+	projectID := project.ID
+	command := project.MigrationCommand
+	env := os.Environ()
+	if project.ConnectionString != "" {
+		env = append(env, "DATABASE_URL="+project.ConnectionString)
+	}
+
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:      jobs.TypeMigration,
+		ProjectID: &projectID,
+		Key:       fmt.Sprintf("migration:%d", projectID),
+		Message:   command,
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		}
+		cmd.Dir = dir
+		cmd.Env = env
+		return h.streamCommand(run, projectID, "migration_log", cmd)
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "A migration is already running for this project", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
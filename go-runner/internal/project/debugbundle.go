@@ -0,0 +1,303 @@
+package project
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/system"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limits of a debug bundle
+const (
+	bundleLogFileTail = 1 << 20 // Bytes of each output file
+	bundleMaxRows     = 2000    // Metric samples and events per section
+	bundleCrashLines  = 50      // Log lines before each crash
+)
+
+// secretEnvName matches the environment variables whose values are left out
+// of debug bundles
+var secretEnvName = regexp.MustCompile(`(?i)secret|token|passw|pwd|key|credential|auth|private|cookie|session`)
+
+// DebugBundleManifest describes the files of a debug bundle
+type DebugBundleManifest struct {
+	ProjectID   uint              `json:"project_id"`
+	ProjectName string            `json:"project_name"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	From        time.Time         `json:"from"`             // Start of the history included
+	Files       []string          `json:"files"`            // Besides manifest.json
+	Errors      map[string]string `json:"errors,omitempty"` // Sections that could not be collected, by file
+}
+
+// CrashReport is a transition of a project to error with the log lines
+// captured before it
+type CrashReport struct {
+	Time   time.Time          `json:"time"`
+	Reason string             `json:"reason"`
+	Logs   []service.LogEntry `json:"logs"`
+}
+
+// bundleCrashes is the crash section of a debug bundle
+type bundleCrashes struct {
+	RestartCount int           `json:"restart_count"`
+	MaxRestarts  int           `json:"max_restarts"`
+	AutoRestart  bool          `json:"auto_restart"`
+	LastError    string        `json:"last_error"`
+	Crashes      []CrashReport `json:"crashes"`
+}
+
+// bundleMetrics is the metrics section of a debug bundle
+type bundleMetrics struct {
+	Process []ProcessMetric        `json:"process"`
+	Traffic []TrafficMetric        `json:"traffic"`
+	Queues  []QueueMetric          `json:"queues"`
+	System  []system.SystemMetrics `json:"system"`
+}
+
+// CreateDebugBundle godoc
+// @Summary      Create a debug bundle of a project
+// @Description  Download a zip archive with everything needed to report a problem with a project: its configuration (project.json, secret-looking env_vars and URL passwords redacted), live status (status.json), buffered logs and the tail of its output files (logs/), status timeline (timeline.json), CPU, memory, traffic, queue and system metrics (metrics.json), crashes with the log lines before each (crashes.json), events (events.json), deploy, config and note annotations (annotations.json) and system info (system.json). manifest.json lists the files and any section that could not be collected.
+// @Tags         projects
+// @Produce      application/zip
+// @Param        id     path      int  true   "Project ID"
+// @Param        hours  query     int  false  "Hours of history (default 24, max 720)"
+// @Success      200    {file}    file  "Debug bundle"
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404    {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/debug-bundle [post]
+func (h *Handler) CreateDebugBundle(c *gin.Context) {
+	hours := 24
+	if raw := c.Query("hours"); raw != "" {
+		var err error
+		if hours, err = strconv.Atoi(raw); err != nil || hours < 1 || hours > 720 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "hours must be between 1 and 720", raw))
+			return
+		}
+	}
+
+	var project Project
+	if err := h.db.Preload("Group").Preload("DeclaredPorts").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	now := time.Now()
+	from := now.Add(-time.Duration(hours) * time.Hour)
+
+	manifest := DebugBundleManifest{
+		ProjectID:   project.ID,
+		ProjectName: project.Name,
+		GeneratedAt: now,
+		From:        from,
+		Errors:      map[string]string{},
+	}
+	files := map[string][]byte{}
+	addJSON := func(name string, v interface{}, err error) {
+		if err != nil {
+			manifest.Errors[name] = err.Error()
+			return
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			manifest.Errors[name] = err.Error()
+			return
+		}
+		files[name] = data
+		manifest.Files = append(manifest.Files, name)
+	}
+
+	entries := h.manager.GetLogEntries(project.ID)
+	addJSON("project.json", redactProject(project), nil)
+	status, err := h.manager.GetServiceStatus(project.ID)
+	addJSON("status.json", redactStatus(status), err)
+	timeline, err := buildTimeline(h.db, &project, from, now, 24)
+	addJSON("timeline.json", timeline, err)
+	metrics, err := h.bundleMetrics(project.ID, from)
+	addJSON("metrics.json", metrics, err)
+	crashes, err := h.bundleCrashes(&project, from, entries)
+	addJSON("crashes.json", crashes, err)
+	var auditEvents []events.AuditEvent
+	err = h.db.Where("project_id = ? AND time >= ?", project.ID, from).Order("time desc").Limit(bundleMaxRows).Find(&auditEvents).Error
+	addJSON("events.json", auditEvents, err)
+	annotations, err := findAnnotations(h.db, project.ID, from, now)
+	addJSON("annotations.json", annotations, err)
+	info, err := system.NewDetector().GetSystemInfo()
+	addJSON("system.json", info, err)
+
+	var logs strings.Builder
+	for _, entry := range entries {
+		if !entry.Time.IsZero() {
+			logs.WriteString(entry.Time.Format(time.RFC3339Nano) + " ")
+		}
+		logs.WriteString(entry.Line + "\n")
+	}
+	files["logs/buffer.log"] = []byte(logs.String())
+	manifest.Files = append(manifest.Files, "logs/buffer.log")
+	stdoutPath, stderrPath := h.manager.LogFilePaths(project.ID)
+	for _, file := range [][2]string{{"logs/stdout.log", stdoutPath}, {"logs/stderr.log", stderrPath}} {
+		name := file[0]
+		data, err := tailFile(file[1], bundleLogFileTail)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				manifest.Errors[name] = err.Error()
+			}
+			continue
+		}
+		files[name] = data
+		manifest.Files = append(manifest.Files, name)
+	}
+	manifestData, _ := json.MarshalIndent(manifest, "", "  ")
+	files["manifest.json"] = manifestData
+
+	filename := fmt.Sprintf("debug-%s-%s.zip", procfileName(project.Name), now.Format("20060102-150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+	for _, name := range append([]string{"manifest.json"}, manifest.Files...) {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return
+		}
+		w.Write(files[name])
+	}
+	zw.Close()
+}
+
+// redactProject returns the configuration of a project without its logs,
+// the values of secret-looking environment variables and URL passwords
+func redactProject(project Project) Project {
+	project.Logs = ""
+	project.EnvVars = redactEnvVars(project.EnvVars)
+	project.ConnectionString = redactURL(project.ConnectionString)
+	project.HealthCheckURL = redactURL(project.HealthCheckURL)
+	return project
+}
+
+// redactStatus hides the same settings in a service status
+func redactStatus(status map[string]interface{}) map[string]interface{} {
+	if env, ok := status["env_vars"].(string); ok {
+		status["env_vars"] = redactEnvVars(env)
+	}
+	for _, key := range []string{"connection_string", "health_check_url"} {
+		if value, ok := status[key].(string); ok {
+			status[key] = redactURL(value)
+		}
+	}
+	return status
+}
+
+// redactEnvVars replaces the values of secret-looking variables of an
+// env_vars JSON object
+func redactEnvVars(envVars string) string {
+	var env map[string]interface{}
+	if json.Unmarshal([]byte(envVars), &env) != nil {
+		return envVars
+	}
+	for name := range env {
+		if secretEnvName.MatchString(name) {
+			env[name] = "[redacted]"
+		}
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return envVars
+	}
+	return string(data)
+}
+
+// redactURL hides the password of a URL
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	return u.Redacted()
+}
+
+// bundleMetrics collects the metrics of a project and the system since from
+func (h *Handler) bundleMetrics(projectID uint, from time.Time) (*bundleMetrics, error) {
+	metrics := &bundleMetrics{Process: []ProcessMetric{}, Traffic: []TrafficMetric{}, Queues: []QueueMetric{}, System: []system.SystemMetrics{}}
+	if err := h.db.Where("project_id = ? AND timestamp >= ?", projectID, from).Order("timestamp desc").Limit(bundleMaxRows).Find(&metrics.Process).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Where("project_id = ? AND timestamp >= ?", projectID, from).Order("timestamp desc").Limit(bundleMaxRows).Find(&metrics.Traffic).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Where("project_id = ? AND timestamp >= ?", projectID, from).Order("timestamp desc").Limit(bundleMaxRows).Find(&metrics.Queues).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Where("timestamp >= ?", from).Order("timestamp desc").Limit(bundleMaxRows).Find(&metrics.System).Error; err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// bundleCrashes collects the transitions of a project to error since from,
+// each with the buffered log lines captured before it
+func (h *Handler) bundleCrashes(project *Project, from time.Time, entries []service.LogEntry) (*bundleCrashes, error) {
+	var transitions []ProjectStatusHistory
+	if err := h.db.Where("project_id = ? AND status = ? AND timestamp >= ?", project.ID, string(StatusError), from).
+		Order("timestamp asc").Find(&transitions).Error; err != nil {
+		return nil, err
+	}
+
+	crashes := &bundleCrashes{
+		RestartCount: project.RestartCount,
+		MaxRestarts:  project.MaxRestarts,
+		AutoRestart:  project.AutoRestart,
+		LastError:    project.LastError,
+		Crashes:      []CrashReport{},
+	}
+	for _, t := range transitions {
+		report := CrashReport{Time: t.Timestamp, Reason: t.Reason, Logs: []service.LogEntry{}}
+		end := 0
+		for end < len(entries) && !entries[end].Time.IsZero() && !entries[end].Time.After(t.Timestamp) {
+			end++
+		}
+		report.Logs = append(report.Logs, entries[max(0, end-bundleCrashLines):end]...)
+		crashes.Crashes = append(crashes.Crashes, report)
+	}
+	return crashes, nil
+}
+
+// tailFile reads the last n bytes of a file, from the start of a line
+func tailFile(path string, n int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(0, info.Size()-n)
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	return data, nil
+}
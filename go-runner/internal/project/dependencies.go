@@ -0,0 +1,400 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"gorm.io/gorm"
+)
+
+const (
+	// registryCacheTTL is how long latest versions are cached
+	registryCacheTTL = time.Hour
+	// registryErrorTTL is how long registry failures are cached
+	registryErrorTTL = 5 * time.Minute
+	// registryConcurrency bounds parallel registry requests per report
+	registryConcurrency = 8
+)
+
+// Dependency is a direct dependency with its current and latest versions
+type Dependency struct {
+	Name     string `json:"name"`
+	Section  string `json:"section"`           // dependencies, devDependencies, require, requirements
+	Wanted   string `json:"wanted,omitempty"`  // Version or range declared in the manifest
+	Current  string `json:"current,omitempty"` // Installed or pinned version
+	Latest   string `json:"latest,omitempty"`  // Latest version in the registry
+	Outdated bool   `json:"outdated"`          // Current is older than latest
+	Error    string `json:"error,omitempty"`   // Registry lookup failure
+}
+
+// DependencyReport lists the direct dependencies of a project
+type DependencyReport struct {
+	ProjectID    uint         `json:"project_id"`
+	Ecosystem    string       `json:"ecosystem"` // npm, go, pip
+	Manifest     string       `json:"manifest"`
+	Outdated     int          `json:"outdated"`
+	Dependencies []Dependency `json:"dependencies"`
+	CheckedAt    time.Time    `json:"checked_at"`
+}
+
+// UpgradeDependencyRequest selects the dependency to upgrade
+type UpgradeDependencyRequest struct {
+	Package string `json:"package" binding:"required"`
+	Version string `json:"version"` // Latest when empty
+}
+
+// registryClient looks up latest package versions, with caching
+type registryClient struct {
+	http  *http.Client
+	mu    sync.Mutex
+	cache map[string]registryEntry
+}
+
+type registryEntry struct {
+	version string
+	err     error
+	expires time.Time
+}
+
+func newRegistryClient() *registryClient {
+	return &registryClient{
+		http:  &http.Client{Timeout: 10 * time.Second},
+		cache: make(map[string]registryEntry),
+	}
+}
+
+// latest returns the latest version of a package, from the cache unless
+// refresh is set
+func (r *registryClient) latest(ctx context.Context, ecosystem, name string, refresh bool) (string, error) {
+	key := ecosystem + ":" + name
+
+	r.mu.Lock()
+	entry, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok && !refresh && time.Now().Before(entry.expires) {
+		return entry.version, entry.err
+	}
+
+	version, err := r.fetch(ctx, ecosystem, name)
+	if ctx.Err() != nil {
+		return "", err // Don't cache requests cut short by the client
+	}
+
+	entry = registryEntry{version: version, err: err, expires: time.Now().Add(registryCacheTTL)}
+	if err != nil {
+		entry.expires = time.Now().Add(registryErrorTTL)
+	}
+	r.mu.Lock()
+	r.cache[key] = entry
+	r.mu.Unlock()
+
+	return version, err
+}
+
+// fetch queries the registry of an ecosystem
+func (r *registryClient) fetch(ctx context.Context, ecosystem, name string) (string, error) {
+	var target string
+	var result struct {
+		Version   string `json:"version"` // npm
+		GoVersion string `json:"Version"` // Go module proxy
+		Info      struct {
+			Version string `json:"version"`
+		} `json:"info"` // PyPI
+	}
+
+	switch ecosystem {
+	case "npm":
+		target = "https://registry.npmjs.org/" + strings.Replace(name, "/", "%2f", 1) + "/latest"
+	case "go":
+		escaped, err := module.EscapePath(name)
+		if err != nil {
+			return "", err
+		}
+		target = goProxyURL() + "/" + escaped + "/@latest"
+	case "pip":
+		target = "https://pypi.org/pypi/" + url.PathEscape(name) + "/json"
+	default:
+		return "", fmt.Errorf("unsupported ecosystem %q", ecosystem)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return "", fmt.Errorf("not found in the registry")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry returned %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid registry response: %v", err)
+	}
+
+	// The JSON decoder matches keys case-insensitively, so the npm and proxy
+	// fields may both be set
+	for _, v := range []string{result.GoVersion, result.Version, result.Info.Version} {
+		if v != "" {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("registry returned no version")
+}
+
+// goProxyURL returns the first HTTP proxy of GOPROXY, or the public proxy
+func goProxyURL() string {
+	for _, proxy := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(proxy, "http://") || strings.HasPrefix(proxy, "https://") {
+			return strings.TrimSuffix(proxy, "/")
+		}
+	}
+	return "https://proxy.golang.org"
+}
+
+// versionOutdated reports whether current is older than latest. Versions that
+// are not semver-like are compared for equality.
+func versionOutdated(current, latest string) bool {
+	if current == "" || latest == "" {
+		return false
+	}
+	c, l := "v"+strings.TrimPrefix(current, "v"), "v"+strings.TrimPrefix(latest, "v")
+	if semver.IsValid(c) && semver.IsValid(l) {
+		return semver.Compare(c, l) < 0
+	}
+	return current != latest
+}
+
+// GetDependencies godoc
+// @Summary      List dependencies
+// @Description  List the direct dependencies declared in package.json, go.mod or requirements.txt with their current and latest versions. Latest versions come from the npm registry, the Go module proxy (GOPROXY) or PyPI and are cached for an hour.
+// @Tags         projects
+// @Produce      json
+// @Param        id         path      int   true   "Project ID"
+// @Param        ecosystem  query     string  false  "npm, go or pip (detected when empty)"
+// @Param        refresh    query     bool  false  "Bypass the registry cache"
+// @Success      200        {object}  types.DataResponse{data=DependencyReport}
+// @Failure      400        {object}  middleware.ErrorResponse  "No supported manifest"
+// @Failure      404        {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/dependencies [get]
+func (h *Handler) GetDependencies(c *gin.Context) {
+	project, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	report, err := readDependencies(dir, c.Query("ecosystem"))
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	report.ProjectID = project.ID
+
+	refresh := c.Query("refresh") == "true"
+	sem := make(chan struct{}, registryConcurrency)
+	var wg sync.WaitGroup
+	for i := range report.Dependencies {
+		wg.Add(1)
+		go func(dep *Dependency) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			latest, err := h.registry.latest(c.Request.Context(), report.Ecosystem, dep.Name, refresh)
+			if err != nil {
+				dep.Error = err.Error()
+				return
+			}
+			dep.Latest = latest
+			dep.Outdated = versionOutdated(dep.Current, latest)
+		}(&report.Dependencies[i])
+	}
+	wg.Wait()
+
+	for _, dep := range report.Dependencies {
+		if dep.Outdated {
+			report.Outdated++
+		}
+	}
+	report.CheckedAt = time.Now()
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: report})
+}
+
+// loadProjectDir loads the project of the :id parameter and its
+// working directory
+func (h *Handler) loadProjectDir(c *gin.Context) (*Project, string, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, "", middleware.ErrBadRequest
+	}
+
+	var project Project
+	if err := h.db.First(&project, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, "", middleware.ErrNotFound
+		}
+		return nil, "", middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error())
+	}
+
+	dir := project.Path
+	if project.WorkingDir != "" {
+		dir = project.WorkingDir
+	}
+	return &project, dir, nil
+}
+
+// readDependencies parses the manifest of an ecosystem, detected when empty
+func readDependencies(dir, ecosystem string) (*DependencyReport, error) {
+	if ecosystem == "" {
+		ecosystem = detectEcosystem(dir)
+	}
+
+	manifest := map[string]string{"npm": "package.json", "go": "go.mod", "pip": "requirements.txt"}[ecosystem]
+	if manifest == "" {
+		return nil, middleware.NewError(http.StatusBadRequest, "Cannot detect the package ecosystem",
+			"no package.json, go.mod or requirements.txt in "+dir)
+	}
+
+	path := filepath.Join(dir, manifest)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, middleware.NewError(http.StatusBadRequest, "Cannot read "+manifest, err.Error())
+	}
+
+	var deps []Dependency
+	switch ecosystem {
+	case "npm":
+		deps, err = parsePackageJSON(dir, data)
+	case "go":
+		deps, err = parseGoMod(path, data)
+	case "pip":
+		deps = parseRequirements(data)
+	}
+	if err != nil {
+		return nil, middleware.NewError(http.StatusBadRequest, "Cannot parse "+manifest, err.Error())
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Section != deps[j].Section {
+			return deps[i].Section < deps[j].Section
+		}
+		return deps[i].Name < deps[j].Name
+	})
+
+	return &DependencyReport{Ecosystem: ecosystem, Manifest: path, Dependencies: deps}, nil
+}
+
+// parsePackageJSON lists dependencies and devDependencies, with the version
+// installed in node_modules when present
+func parsePackageJSON(dir string, data []byte) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+
+	deps := []Dependency{}
+	add := func(section string, specs map[string]string) {
+		for name, wanted := range specs {
+			dep := Dependency{Name: name, Section: section, Wanted: wanted}
+
+			var installed struct {
+				Version string `json:"version"`
+			}
+			if raw, err := os.ReadFile(filepath.Join(dir, "node_modules", name, "package.json")); err == nil && json.Unmarshal(raw, &installed) == nil {
+				dep.Current = installed.Version
+			} else {
+				// Not installed: compare the lowest version the range allows
+				dep.Current = strings.TrimLeft(wanted, "^~=>v ")
+			}
+			deps = append(deps, dep)
+		}
+	}
+	add("dependencies", pkg.Dependencies)
+	add("devDependencies", pkg.DevDependencies)
+	return deps, nil
+}
+
+// parseGoMod lists the direct requirements of a go.mod
+func parseGoMod(path string, data []byte) ([]Dependency, error) {
+	file, err := modfile.ParseLax(path, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := []Dependency{}
+	for _, req := range file.Require {
+		if req.Indirect {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:    req.Mod.Path,
+			Section: "require",
+			Wanted:  req.Mod.Version,
+			Current: req.Mod.Version,
+		})
+	}
+	return deps, nil
+}
+
+// requirementPattern matches "name[extras] <op> version" in requirements.txt
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*(?:(===|==|~=|>=|<=|!=|>|<)\s*([^\s;,#]+))?`)
+
+// parseRequirements lists the packages of a requirements.txt. Only pinned
+// (==) versions are known; other entries have no current version.
+func parseRequirements(data []byte) []Dependency {
+	deps := []Dependency{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue // Comments, -r includes, --index-url and other options
+		}
+
+		m := requirementPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue // URLs, paths, ...
+		}
+		dep := Dependency{Name: m[1], Section: "requirements"}
+		if m[2] != "" {
+			dep.Wanted = m[2] + m[3]
+		}
+		if m[2] == "==" || m[2] == "===" {
+			dep.Current = m[3]
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
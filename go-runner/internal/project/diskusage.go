@@ -0,0 +1,263 @@
+package project
+
+import (
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// diskUsageMaxEntries bounds the artifact directories listed, largest first
+const diskUsageMaxEntries = 100
+
+// Artifact kinds
+const (
+	ArtifactDependencies = "dependencies" // Installed packages, restored by an install
+	ArtifactBuild        = "build"        // Build output, restored by a build
+	ArtifactCache        = "cache"        // Tool caches
+)
+
+// artifactDirs maps directory names to their artifact kind
+var artifactDirs = map[string]string{
+	"node_modules":     ArtifactDependencies,
+	"bower_components": ArtifactDependencies,
+	"vendor":           ArtifactDependencies,
+	".venv":            ArtifactDependencies,
+	"venv":             ArtifactDependencies,
+	".pnpm-store":      ArtifactDependencies,
+	"dist":             ArtifactBuild,
+	"build":            ArtifactBuild,
+	"target":           ArtifactBuild,
+	"out":              ArtifactBuild,
+	".next":            ArtifactBuild,
+	".nuxt":            ArtifactBuild,
+	".output":          ArtifactBuild,
+	".svelte-kit":      ArtifactBuild,
+	".turbo":           ArtifactCache,
+	".cache":           ArtifactCache,
+	".parcel-cache":    ArtifactCache,
+	".angular":         ArtifactCache,
+	".gradle":          ArtifactCache,
+	"__pycache__":      ArtifactCache,
+	".pytest_cache":    ArtifactCache,
+	".mypy_cache":      ArtifactCache,
+	".ruff_cache":      ArtifactCache,
+	"coverage":         ArtifactCache,
+}
+
+// DiskUsageEntry is an artifact directory of a project
+type DiskUsageEntry struct {
+	Path  string `json:"path"` // Relative to the project directory
+	Kind  string `json:"kind"` // dependencies, build, cache
+	Bytes int64  `json:"bytes"`
+	Files int    `json:"files"`
+}
+
+// DiskUsage is the size of a project directory with its artifact directories
+type DiskUsage struct {
+	Dir             string           `json:"dir"`
+	Bytes           int64            `json:"bytes"` // Whole directory
+	Files           int              `json:"files"`
+	SourceBytes     int64            `json:"source_bytes"` // Outside artifact directories
+	ByKind          map[string]int64 `json:"by_kind"`      // Bytes per artifact kind
+	Entries         []DiskUsageEntry `json:"entries"`      // Artifact directories, largest first
+	Truncated       bool             `json:"truncated"`    // More entries than listed
+	FilesystemFree  uint64           `json:"filesystem_free"`
+	FilesystemTotal uint64           `json:"filesystem_total"`
+}
+
+// CleanDiskRequest selects artifact directories to remove
+type CleanDiskRequest struct {
+	Paths []string `json:"paths"`                                                         // Artifact directories from GET /disk-usage
+	Kinds []string `json:"kinds" binding:"omitempty,dive,oneof=dependencies build cache"` // Every directory of these kinds
+	Force bool     `json:"force"`                                                         // Also remove while the project is running
+}
+
+// CleanDiskResult lists the removed directories
+type CleanDiskResult struct {
+	Removed    []DiskUsageEntry `json:"removed"`
+	FreedBytes int64            `json:"freed_bytes"`
+	Errors     []string         `json:"errors,omitempty"`
+}
+
+// GetDiskUsage godoc
+// @Summary      Get project disk usage
+// @Description  Compute the size of the project directory with a breakdown of artifact directories: dependencies (node_modules, vendor, .venv...), build output (dist, build, target, .next...) and caches (.cache, __pycache__, coverage...), plus the free space of the filesystem. Symlinks are not followed.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=DiskUsage}
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      500  {object}  middleware.ErrorResponse  "Directory not readable"
+// @Router       /projects/{id}/disk-usage [get]
+func (h *Handler) GetDiskUsage(c *gin.Context) {
+	_, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	usage, err := scanDiskUsage(dir)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to scan the project directory", err.Error()))
+		return
+	}
+	if len(usage.Entries) > diskUsageMaxEntries {
+		usage.Entries = usage.Entries[:diskUsageMaxEntries]
+		usage.Truncated = true
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: usage})
+}
+
+// CleanDiskUsage godoc
+// @Summary      Remove artifact directories
+// @Description  Remove the given artifact directories, or every directory of the given kinds, as found by GET /projects/{id}/disk-usage. Other paths are rejected. Dependencies and build output come back with an install or build; the project must be stopped unless force is set.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int               true  "Project ID"
+// @Param        request  body      CleanDiskRequest  true  "Directories to remove"
+// @Success      200      {object}  types.DataMessageResponse{data=CleanDiskResult}
+// @Failure      400      {object}  middleware.ErrorResponse  "Not an artifact directory"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Project is running"
+// @Router       /projects/{id}/disk-usage/clean [post]
+func (h *Handler) CleanDiskUsage(c *gin.Context) {
+	var req CleanDiskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if len(req.Paths) == 0 && len(req.Kinds) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Set paths or kinds", nil))
+		return
+	}
+
+	project, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if !req.Force && h.manager.IsServiceRunning(project.ID) {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Project is running, stop it or set force", nil))
+		return
+	}
+
+	usage, err := scanDiskUsage(dir)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to scan the project directory", err.Error()))
+		return
+	}
+
+	byPath := make(map[string]DiskUsageEntry, len(usage.Entries))
+	for _, entry := range usage.Entries {
+		byPath[entry.Path] = entry
+	}
+	selected := make(map[string]bool)
+	for _, path := range req.Paths {
+		path = filepath.ToSlash(filepath.Clean(path))
+		if _, ok := byPath[path]; !ok {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Not an artifact directory of the project", path))
+			return
+		}
+		selected[path] = true
+	}
+	for _, entry := range usage.Entries {
+		for _, kind := range req.Kinds {
+			if entry.Kind == kind {
+				selected[entry.Path] = true
+			}
+		}
+	}
+
+	result := CleanDiskResult{Removed: []DiskUsageEntry{}}
+	for _, entry := range usage.Entries {
+		if !selected[entry.Path] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, filepath.FromSlash(entry.Path))); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Removed = append(result.Removed, entry)
+		result.FreedBytes += entry.Bytes
+	}
+
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Data:    result,
+		Message: "Artifact directories removed",
+	})
+}
+
+// scanDiskUsage walks a project directory once, sizing artifact directories
+// as a whole (nested artifacts such as node_modules inside node_modules are
+// part of the outer one)
+func scanDiskUsage(dir string) (*DiskUsage, error) {
+	usage := &DiskUsage{Dir: dir, ByKind: map[string]int64{}, Entries: []DiskUsageEntry{}}
+	var current *DiskUsageEntry
+	var currentPath string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil // Unreadable entries are skipped
+		}
+		if current != nil && !strings.HasPrefix(path, currentPath+string(filepath.Separator)) {
+			usage.Entries = append(usage.Entries, *current)
+			current = nil
+		}
+
+		if d.IsDir() {
+			if kind, ok := artifactDirs[d.Name()]; ok && current == nil && path != dir {
+				rel, _ := filepath.Rel(dir, path)
+				current = &DiskUsageEntry{Path: filepath.ToSlash(rel), Kind: kind}
+				currentPath = path
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		usage.Bytes += info.Size()
+		usage.Files++
+		if current != nil {
+			current.Bytes += info.Size()
+			current.Files++
+		} else {
+			usage.SourceBytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		usage.Entries = append(usage.Entries, *current)
+	}
+
+	for _, entry := range usage.Entries {
+		usage.ByKind[entry.Kind] += entry.Bytes
+	}
+	sort.SliceStable(usage.Entries, func(i, j int) bool { return usage.Entries[i].Bytes > usage.Entries[j].Bytes })
+
+	if stat, err := disk.Usage(dir); err == nil {
+		usage.FilesystemFree = stat.Free
+		usage.FilesystemTotal = stat.Total
+	}
+	return usage, nil
+}
@@ -0,0 +1,42 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RunDoctor godoc
+// @Summary      Check the project environment
+// @Description  Verify that the machine can run the project: the start command exists, the node, go and python versions satisfy package.json engines, .nvmrc, .node-version, go.mod, pyproject.toml requires-python, .python-version and runtime.txt, the package manager (packageManager, lockfiles, poetry, pipenv, uv) is installed, the variables listed in .env.example are set, and the database and service URLs of the connection string, .env file and env_vars are reachable. Each check passes, warns or fails with a fix. Versions selected by asdf (.tool-versions), nvm (.nvmrc) and the go.mod toolchain directive are used as at start; other tools are looked up on the PATH the project is started with.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=go-runner_internal_service.DoctorReport}
+// @Failure      400  {object}  middleware.ErrorResponse  "Invalid project ID"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/doctor [post]
+func (h *Handler) RunDoctor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	report, err := h.manager.RunDoctor(c.Request.Context(), project.ID)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to check project", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: report})
+}
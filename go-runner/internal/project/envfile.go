@@ -0,0 +1,277 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnvFileResponse is the content of a project's .env file compared with the
+// running process
+type EnvFileResponse struct {
+	Path       string                        `json:"path"`
+	Exists     bool                          `json:"exists"`
+	Content    string                        `json:"content"`
+	Lines      []service.EnvFileLine         `json:"lines"`
+	Errors     []service.EnvFileError        `json:"errors,omitempty"`     // Invalid lines (ignored at start)
+	Duplicates []string                      `json:"duplicates,omitempty"` // Keys defined more than once; the last one wins
+	Modified   []service.EnvVarDiff          `json:"modified,omitempty"`   // PUT only: changes made to the file
+	Process    *service.EnvFileProcessStatus `json:"process"`
+}
+
+// UpdateEnvFileRequest replaces the .env file content, or sets and removes
+// individual variables while keeping comments and ordering
+type UpdateEnvFileRequest struct {
+	Content *string           `json:"content"` // Full file content
+	Set     map[string]string `json:"set"`     // Variables to add or update
+	Unset   []string          `json:"unset"`   // Variables to remove
+}
+
+// GetEnvFile godoc
+// @Summary      Get .env file
+// @Description  Read the project's .env file (env_file, or .env in the project path) with parsed lines, syntax errors and a diff against the running process environment
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=EnvFileResponse}  ".env file"
+// @Failure      400  {object}  middleware.ErrorResponse                  "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse                  "Project not found"
+// @Failure      500  {object}  middleware.ErrorResponse                  "Internal server error"
+// @Router       /projects/{id}/env-file [get]
+func (h *Handler) GetEnvFile(c *gin.Context) {
+	project, envPath, ok := h.loadEnvFileProject(c)
+	if !ok {
+		return
+	}
+
+	content, exists, err := readEnvFile(envPath)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to read .env file", err.Error()))
+		return
+	}
+
+	lines := service.ParseEnvFile(content)
+	response := EnvFileResponse{
+		Path:       envPath,
+		Exists:     exists,
+		Content:    content,
+		Lines:      lines,
+		Errors:     service.ValidateEnvFile(lines),
+		Duplicates: service.DuplicateEnvKeys(lines),
+		Process:    h.manager.CompareEnvFile(project.ID, service.EnvFileVars(lines), project.EnvVars, nil),
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: response})
+}
+
+// UpdateEnvFile godoc
+// @Summary      Update .env file
+// @Description  Write the project's .env file. Send either the full content, or set/unset to change individual variables in place (comments and ordering are kept). Invalid KEY=VALUE lines are rejected. The response tells whether the running service must be restarted to pick up the changes.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Project ID"
+// @Param        request  body      UpdateEnvFileRequest  true  "New content or variable changes"
+// @Success      200      {object}  types.DataResponse{data=EnvFileResponse}                  "Updated .env file"
+// @Failure      400      {object}  middleware.ErrorResponse{details=[]service.EnvFileError}  "Invalid .env content"
+// @Failure      404      {object}  middleware.ErrorResponse                                  "Project not found"
+// @Failure      500      {object}  middleware.ErrorResponse                                  "Internal server error"
+// @Router       /projects/{id}/env-file [put]
+func (h *Handler) UpdateEnvFile(c *gin.Context) {
+	var req UpdateEnvFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if req.Content == nil && len(req.Set) == 0 && len(req.Unset) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", "content, set or unset is required"))
+		return
+	}
+	if req.Content != nil && (len(req.Set) > 0 || len(req.Unset) > 0) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", "content cannot be combined with set or unset"))
+		return
+	}
+
+	project, envPath, ok := h.loadEnvFileProject(c)
+	if !ok {
+		return
+	}
+
+	oldContent, exists, err := readEnvFile(envPath)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to read .env file", err.Error()))
+		return
+	}
+	oldLines := service.ParseEnvFile(oldContent)
+
+	newContent := ""
+	if req.Content != nil {
+		newContent = *req.Content
+	} else {
+		newContent, err = service.ApplyEnvChanges(oldLines, req.Set, req.Unset)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid .env content", err.Error()))
+			return
+		}
+	}
+
+	newLines := service.ParseEnvFile(newContent)
+	if errs := service.ValidateEnvFile(newLines); len(errs) > 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest,
+			fmt.Sprintf("Invalid .env content: line %d: %s", errs[0].Line, errs[0].Message), errs))
+		return
+	}
+
+	if err := writeEnvFile(envPath, newContent, exists); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to write .env file", err.Error()))
+		return
+	}
+
+	oldVars := service.EnvFileVars(oldLines)
+	newVars := service.EnvFileVars(newLines)
+	var removed []string
+	for key := range oldVars {
+		if _, ok := newVars[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	response := EnvFileResponse{
+		Path:       envPath,
+		Exists:     true,
+		Content:    newContent,
+		Lines:      newLines,
+		Duplicates: service.DuplicateEnvKeys(newLines),
+		Modified:   service.DiffEnvVars(oldVars, newVars),
+		Process:    h.manager.CompareEnvFile(project.ID, newVars, project.EnvVars, removed),
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: response})
+}
+
+// GetRuntimeEnv godoc
+// @Summary      Get runtime environment diff
+// @Description  Read the environment of the running process (/proc/<pid>/environ, Linux only) and diff it against the environment the project would be started with now. Stale and missing values mean the service must be restarted to pick up configuration edits.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int   true   "Project ID"
+// @Param        all  query     bool  false  "Include variables whose value is unchanged"
+// @Success      200  {object}  types.DataResponse{data=service.RuntimeEnvReport}  "Runtime environment diff"
+// @Failure      400  {object}  middleware.ErrorResponse                           "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse                           "Project not found"
+// @Failure      409  {object}  middleware.ErrorResponse                           "Service is not running"
+// @Failure      500  {object}  middleware.ErrorResponse                           "Internal server error"
+// @Failure      501  {object}  middleware.ErrorResponse                           "Not supported on this platform"
+// @Router       /projects/{id}/runtime-env [get]
+func (h *Handler) GetRuntimeEnv(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	report, err := h.manager.GetRuntimeEnv(project.ID, c.Query("all") == "true")
+	switch {
+	case errors.Is(err, service.ErrServiceNotRunning):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is not running", err.Error()))
+		return
+	case errors.Is(err, service.ErrRuntimeEnvUnsupported):
+		middleware.HandleError(c, middleware.NewError(http.StatusNotImplemented, "Not supported on this platform", err.Error()))
+		return
+	case err != nil:
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to read runtime environment", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: report})
+}
+
+// loadEnvFileProject loads the project and resolves its .env path, writing
+// the error response on failure
+func (h *Handler) loadEnvFileProject(c *gin.Context) (*Project, string, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return nil, "", false
+	}
+
+	var project Project
+	if err := h.db.Select("id, path, env_file, env_vars").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return nil, "", false
+	}
+
+	envPath := service.ResolveEnvFilePath(project.EnvFile, project.Path)
+	if envPath == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "No .env file", "project has neither env_file nor path configured"))
+		return nil, "", false
+	}
+
+	return &project, envPath, true
+}
+
+// readEnvFile reads a .env file; a missing file is returned as empty content
+func readEnvFile(path string) (string, bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(content), true, nil
+}
+
+// writeEnvFile writes a .env file, keeping the mode of an existing file.
+// New files are only readable by the owner since they usually hold secrets.
+func writeEnvFile(path, content string, exists bool) error {
+	if _, err := os.Stat(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("directory %s does not exist", filepath.Dir(path))
+	}
+
+	mode := os.FileMode(0600)
+	if exists {
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	if content != "" && content[len(content)-1] != '\n' {
+		content += "\n"
+	}
+
+	// Write to a temporary file and rename so a failed write never truncates the .env
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".env.tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,65 @@
+package project
+
+import (
+	"fmt"
+
+	"go-runner/internal/events"
+	"go-runner/internal/service"
+	"go-runner/internal/system"
+
+	"gorm.io/gorm"
+)
+
+// FileDescriptorRecorder raises alerts for projects running out of file
+// descriptors
+type FileDescriptorRecorder struct {
+	db     *gorm.DB
+	events *events.Bus
+}
+
+// NewFileDescriptorRecorder creates a file descriptor recorder
+func NewFileDescriptorRecorder(db *gorm.DB, bus *events.Bus) *FileDescriptorRecorder {
+	return &FileDescriptorRecorder{db: db, events: bus}
+}
+
+// Record publishes an open file count as an "fd_update" event and raises
+// a project_file_descriptors alert while a process of the project uses more
+// of its ulimit than the file_descriptor_limit of the system config. A nil
+// count, for a stopped project, resolves the alert.
+func (r *FileDescriptorRecorder) Record(projectID uint, stats *service.FileDescriptorStats) {
+	var project Project
+	if err := r.db.Unscoped().Select("id, name").First(&project, projectID).Error; err != nil {
+		return
+	}
+	prefix := fmt.Sprintf("Process %s: ", project.Name)
+	if stats == nil {
+		resolveAlert(r.db, r.events, "project_file_descriptors", projectID, prefix)
+		return
+	}
+	r.events.Publish(projectID, "fd_update", stats)
+
+	limit := 80.0
+	var config system.SystemConfig
+	if err := r.db.First(&config).Error; err == nil {
+		limit = config.FileDescriptorLimit
+	}
+	if limit <= 0 || stats.Limit == 0 || stats.Usage < limit {
+		resolveAlert(r.db, r.events, "project_file_descriptors", projectID, prefix)
+		return
+	}
+
+	worst := stats.Processes[0]
+	for _, p := range stats.Processes {
+		if p.Usage > worst.Usage {
+			worst = p
+		}
+	}
+	level := "warning"
+	if stats.Usage >= 95 {
+		level = "critical"
+	}
+	raiseAlert(r.db, r.events, "project_file_descriptors", level, projectID, prefix,
+		fmt.Sprintf("PID %d (%s) has %d of %d files open (ulimit -n), %.1f%% (threshold: %.1f%%)",
+			worst.PID, worst.Name, worst.Open, worst.SoftLimit, worst.Usage, limit),
+		stats.Usage, limit)
+}
@@ -0,0 +1,43 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFileChanges godoc
+// @Summary      Get recent file changes
+// @Description  Get the recent changes in the project directory (path, operation, time), newest first, with the time of the last change. Requires watch_files; watchers start within 10 seconds of enabling it and skip dependency, build and VCS directories (node_modules, vendor, dist, .git...). Changes are also broadcast to the project WebSocket as "file_change" messages.
+// @Tags         projects
+// @Produce      json
+// @Param        id     path      int  true   "Project ID"
+// @Param        limit  query     int  false  "Maximum number of changes (default 50, max 200)"
+// @Success      200    {object}  types.DataResponse{data=service.FileChangeFeed}
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404    {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/files/changes [get]
+func (h *Handler) GetFileChanges(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > 200 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "limit must be between 1 and 200", raw))
+			return
+		}
+	}
+
+	var feed *service.FileChangeFeed = h.manager.FileChanges(project.ID, limit)
+	c.JSON(http.StatusOK, types.DataResponse{Data: feed})
+}
@@ -1,20 +1,24 @@
 package project
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"go-runner/internal/ci"
+	"go-runner/internal/events"
+	"go-runner/internal/jobs"
 	"go-runner/internal/middleware"
 	"go-runner/internal/service"
+	"go-runner/internal/types"
 	"go-runner/internal/websocket"
 
 	"github.com/gin-gonic/gin"
@@ -26,42 +30,129 @@ type Handler struct {
 	db      *gorm.DB
 	manager *service.Manager
 	hub     *websocket.Hub
+	events  *events.Bus
 	// Track last time buffered logs were sent for each project to avoid duplicates on refresh
 	lastBufferedLogsSent map[uint]time.Time
 	bufferedLogsMu       sync.RWMutex
+	// Multiplexed log tails waiting for their stream connection
+	tailSessions map[string]*tailSession
+	tailMu       sync.Mutex
+	jobs         *jobs.Manager
+	// Latest package versions from npm, the Go module proxy and PyPI
+	registry *registryClient
+	// Queue depth metrics and backlog alerts
+	queues *QueueRecorder
 }
 
-func NewHandler(db *gorm.DB, manager *service.Manager, hub *websocket.Hub) *Handler {
+func NewHandler(db *gorm.DB, manager *service.Manager, hub *websocket.Hub, bus *events.Bus, jobManager *jobs.Manager) *Handler {
 	return &Handler{
 		db:                   db,
 		manager:              manager,
 		hub:                  hub,
+		events:               bus,
 		lastBufferedLogsSent: make(map[uint]time.Time),
+		tailSessions:         make(map[string]*tailSession),
+		jobs:                 jobManager,
+		registry:             newRegistryClient(),
+		queues:               NewQueueRecorder(db, bus),
 	}
 }
 
-func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB, manager *service.Manager, hub *websocket.Hub) {
-	h := NewHandler(db, manager, hub)
+func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB, manager *service.Manager, hub *websocket.Hub, bus *events.Bus, jobManager *jobs.Manager) {
+	h := NewHandler(db, manager, hub, bus, jobManager)
+	validateID := middleware.ValidateParams(map[string]string{"id": "number"})
 	
 	// Project routes
 	projects := r.Group("/projects")
 	{
 		projects.GET("", h.GetProjects)
-		projects.POST("", h.CreateProject)
+		projects.POST("", middleware.ValidationMiddleware(&CreateProjectRequest{}), h.CreateProject)
+		projects.GET("/summary", middleware.ValidateQueryParams(map[string]string{"crashes": "omitempty,number"}), h.GetProjectsSummary)
 		projects.GET("/:id", h.GetProject)
-		projects.PUT("/:id", h.UpdateProject)
+		projects.PUT("/:id", validateID, middleware.ValidationMiddleware(&UpdateProjectRequest{}), h.UpdateProject)
+		projects.PATCH("/:id", validateID, middleware.ValidationMiddleware(&UpdateProjectRequest{}), h.PatchProject)
 		projects.DELETE("/:id", h.DeleteProject)
 		projects.POST("/:id/start", h.StartProject)
 		projects.POST("/:id/stop", h.StopProject)
 		projects.POST("/:id/restart", h.RestartProject)
 		projects.POST("/:id/force-kill", h.ForceKillProject)
+		projects.POST("/:id/pause", h.PauseProject)
+		projects.POST("/:id/resume", h.ResumeProject)
+		projects.GET("/:id/priority", h.GetPriority)
+		projects.PUT("/:id/priority", h.SetPriority)
 		projects.GET("/:id/status", h.GetProjectStatus)
+		projects.GET("/:id/timeline", h.GetProjectTimeline)
+		projects.GET("/:id/compare", h.CompareEvents)
+		projects.POST("/:id/annotations", h.CreateAnnotation)
+		projects.GET("/:id/annotations", h.GetAnnotations)
+		projects.DELETE("/:id/annotations/:annotation_id", h.DeleteAnnotation)
 		projects.GET("/:id/logs", h.GetLogs)
 		projects.GET("/:id/logs/ws", h.StreamLogs)
+		projects.GET("/:id/logs/files", h.GetTailedFiles)
+		projects.POST("/:id/debug-bundle", h.CreateDebugBundle)
+		projects.POST("/:id/profile", h.CaptureProfile)
+		projects.GET("/:id/profiles", h.GetProfiles)
+		projects.GET("/:id/profiles/:profile_id/download", h.DownloadProfile)
+		projects.GET("/:id/profiles/:profile_id/flamegraph", h.GetProfileFlameGraph)
+		projects.DELETE("/:id/profiles/:profile_id", h.DeleteProfile)
+		projects.GET("/:id/ports", h.GetProjectPorts)
+		projects.PUT("/:id/ports", h.UpdateProjectPorts)
+		projects.GET("/:id/env-file", h.GetEnvFile)
+		projects.PUT("/:id/env-file", h.UpdateEnvFile)
+		projects.GET("/:id/runtime-env", h.GetRuntimeEnv)
+		projects.GET("/:id/runtime", h.GetRuntimeInfo)
+		projects.GET("/:id/process/metrics", h.GetProcessMetrics)
+		projects.GET("/:id/instances", h.GetInstances)
+		projects.GET("/:id/instances/:index/logs", h.GetInstanceLogs)
+		projects.GET("/:id/chaos", h.GetChaos)
+		projects.POST("/:id/chaos/latency", h.InjectLatency)
+		projects.DELETE("/:id/chaos/latency", h.ClearLatency)
+		projects.POST("/:id/chaos/pause", h.ChaosPause)
+		projects.DELETE("/:id/chaos/pause", h.ChaosResume)
+		projects.GET("/:id/smoke-tests", h.GetSmokeTestRuns)
+		projects.POST("/:id/smoke-tests/run", h.RunSmokeTests)
+		projects.GET("/:id/monitors", h.GetMonitors)
+		projects.POST("/:id/monitors", h.CreateMonitor)
+		projects.PUT("/:id/monitors/:monitor_id", h.UpdateMonitor)
+		projects.DELETE("/:id/monitors/:monitor_id", h.DeleteMonitor)
+		projects.POST("/:id/monitors/:monitor_id/check", h.CheckMonitor)
+		projects.GET("/:id/monitors/:monitor_id/stats", h.GetMonitorStats)
+		projects.POST("/:id/doctor", h.RunDoctor)
+		projects.GET("/:id/files/changes", h.GetFileChanges)
+		projects.GET("/:id/disk-usage", h.GetDiskUsage)
+		projects.POST("/:id/disk-usage/clean", h.CleanDiskUsage)
 		projects.POST("/:id/install", h.InstallPackages)
+		projects.GET("/:id/install/jobs", h.GetInstallJobs)
+		projects.GET("/:id/scripts", h.GetScripts)
+		projects.POST("/:id/scripts/run", h.RunScript)
+		projects.POST("/:id/test", h.RunTests)
+		projects.GET("/:id/test/runs", h.GetTestRuns)
+		projects.GET("/:id/test/runs/:run_id", h.GetTestRun)
+		projects.POST("/:id/audit", h.AuditProject)
+		projects.GET("/:id/audit", h.GetProjectAudit)
+		projects.GET("/:id/dependencies", h.GetDependencies)
+		projects.POST("/:id/dependencies/upgrade", h.UpgradeDependency)
+		projects.GET("/:id/database", h.GetDatabaseStatus)
+		projects.POST("/:id/database/migrate", h.MigrateDatabase)
+		projects.GET("/:id/queues", h.GetQueueStatus)
+		projects.GET("/:id/queues/metrics", h.GetQueueMetrics)
+		projects.POST("/:id/tunnel", h.StartTunnel)
+		projects.GET("/:id/tunnel", h.GetTunnel)
+		projects.DELETE("/:id/tunnel", h.StopTunnel)
+		projects.POST("/:id/inspect", h.EnableInspector)
+		projects.DELETE("/:id/inspect", h.DisableInspector)
+		projects.Any("/:id/proxy/*path", h.ProxyProject)
+		projects.GET("/:id/traffic", h.GetTraffic)
+		projects.GET("/:id/kubernetes", h.GetProjectKubernetes)
+		projects.GET("/:id/systemd", h.GetProjectSystemd)
+		projects.GET("/:id/export/vscode", h.ExportVSCode)
 		projects.GET("/:id/terminal", h.GetTerminalUrl)
 		projects.POST("/:id/terminal/open", h.OpenTerminal)
 		projects.POST("/import", h.ImportProjects)
+		projects.POST("/apply", h.ApplyWorkspace)
+		projects.GET("/drift", h.GetWorkspaceDrift)
+		projects.POST("/import/pm2", h.ImportPM2)
+		projects.POST("/import/procfile", h.ImportProcfile)
 		projects.GET("/:id/config", h.GetProjectConfig)
 		projects.PUT("/:id/config", h.UpdateProjectFromConfig)
 		projects.POST("/detect-services", h.DetectServices)
@@ -71,42 +162,106 @@ func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB, manager *service.Manager, h
 	groups := r.Group("/groups")
 	{
 		groups.GET("", h.GetProjectGroups)
-		groups.POST("", h.CreateProjectGroup)
+		groups.POST("", middleware.ValidationMiddleware(&CreateProjectGroupRequest{}), h.CreateProjectGroup)
 		groups.GET("/:id", h.GetProjectGroup)
-		groups.PUT("/:id", h.UpdateProjectGroup)
+		groups.PUT("/:id", validateID, middleware.ValidationMiddleware(&UpdateProjectGroupRequest{}), h.UpdateProjectGroup)
 		groups.DELETE("/:id", h.DeleteProjectGroup)
 		groups.GET("/:id/projects", h.GetGroupProjects)
+		groups.GET("/:id/timeline", h.GetGroupTimeline)
+		groups.GET("/:id/procfile", h.ExportProcfile)
+		groups.GET("/:id/export/vscode", h.ExportGroupVSCode)
+		groups.POST("/:id/rolling-restart", h.RollingRestart)
+		groups.POST("/:id/chaos/kill", h.ChaosKill)
 	}
 
 	// Service management routes
 	services := r.Group("/services")
 	{
 		services.GET("/running", h.GetRunningServices)
+		services.GET("/summary", h.GetServicesSummary)
+		services.GET("/autostart", h.GetAutostartResults)
+		services.GET("/reconcile", h.GetReconcileResults)
+		services.POST("/:id/adopt", h.AdoptProcess)
 		services.POST("/:id/start", h.StartProject)
 		services.POST("/:id/stop", h.StopProject)
 		services.POST("/:id/restart", h.RestartProject)
 	}
 
+	// Cross-project log routes
+	logs := r.Group("/logs")
+	{
+		logs.POST("/tail", h.TailLogs)
+		logs.GET("/tail/:session/ws", h.TailLogsWebSocket)
+		logs.GET("/tail/:session/sse", h.TailLogsSSE)
+		logs.GET("/trace/:traceID", h.GetTrace)
+	}
+
+	// Kubernetes routes
+	kubernetes := r.Group("/kubernetes")
+	{
+		kubernetes.GET("/deployments", h.GetKubeDeployments)
+		kubernetes.POST("/import", h.ImportKubeDeployments)
+	}
+
+	// systemd routes
+	r.GET("/systemd/units", h.GetSystemdUnits)
+
+	// Hosts of SSH projects
+	r.GET("/nodes", h.GetNodes)
+
 	// Port management routes
 	ports := r.Group("/ports")
 	{
 		ports.GET("", h.GetPorts)
+		ports.GET("/sockets", h.GetUnixSockets)
 		ports.DELETE("/:port", h.KillPort)
 	}
+
+	// Onboarding wizard routes
+	onboarding := r.Group("/onboarding")
+	{
+		onboarding.GET("", h.GetOnboardings)
+		onboarding.POST("", h.StartOnboarding)
+		onboarding.GET("/:id", h.GetOnboarding)
+		onboarding.PUT("/:id", h.UpdateOnboarding)
+		onboarding.DELETE("/:id", h.DeleteOnboarding)
+		onboarding.POST("/:id/run", h.RunOnboarding)
+	}
+
+	// Zombie and orphaned process routes
+	r.GET("/system/orphans", h.GetOrphans)
+	r.POST("/system/orphans/cleanup", h.CleanupOrphans)
+	r.GET("/system/internals", h.GetInternals)
+	r.GET("/ws/clients", h.GetWebSocketClients)
+	r.DELETE("/ws/clients/:client_id", h.DisconnectWebSocketClient)
+	r.GET("/system/power", h.GetPowerMode)
+	r.PUT("/system/power", h.SetPowerMode)
+
+	// Search across projects, groups, logs and alerts
+	r.GET("/search", h.Search)
 }
 
 // GetProjects godoc
 // @Summary      Get all projects
-// @Description  Get a list of all projects
+// @Description  Get a list of all projects, optionally those of one owner or team
 // @Tags         projects
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "List of projects"
-// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Param        owner  query     string  false  "Only the projects of this owner"
+// @Param        team   query     string  false  "Only the projects of this team"
+// @Success      200  {object}  types.DataResponse{data=[]Project}  "List of projects"
+// @Failure      500  {object}  middleware.ErrorResponse            "Internal server error"
 // @Router       /projects [get]
 func (h *Handler) GetProjects(c *gin.Context) {
 	var projects []Project
-	if err := h.db.Find(&projects).Error; err != nil {
+	query := h.db.Preload("DeclaredPorts")
+	if owner := c.Query("owner"); owner != "" {
+		query = query.Where("owner = ?", owner)
+	}
+	if team := c.Query("team"); team != "" {
+		query = query.Where("team = ?", team)
+	}
+	if err := query.Find(&projects).Error; err != nil {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
 		return
 	}
@@ -129,8 +284,9 @@ func (h *Handler) GetProjects(c *gin.Context) {
 			}
 		}
 	}
+	h.attachLatestAudits(projects)
 	
-	c.JSON(http.StatusOK, gin.H{"data": projects})
+	c.JSON(http.StatusOK, types.DataResponse{Data: projects})
 }
 
 // GetProject godoc
@@ -140,10 +296,10 @@ func (h *Handler) GetProjects(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      int  true  "Project ID"
-// @Success      200  {object}  map[string]interface{}  "Project data"
-// @Failure      400  {object}  map[string]interface{}  "Bad request"
-// @Failure      404  {object}  map[string]interface{}  "Project not found"
-// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Success      200  {object}  types.DataResponse{data=Project}  "Project data"
+// @Failure      400  {object}  middleware.ErrorResponse          "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse          "Project not found"
+// @Failure      500  {object}  middleware.ErrorResponse          "Internal server error"
 // @Router       /projects/{id} [get]
 func (h *Handler) GetProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -162,7 +318,20 @@ func (h *Handler) GetProject(c *gin.Context) {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": project})
+
+	var ports []ProjectPort
+	h.db.Where("project_id = ?", id).Order("number").Find(&ports)
+	project["declared_ports"] = ports
+
+	var audit DependencyAudit
+	if h.db.Omit("findings").Where("project_id = ?", id).Order("id DESC").First(&audit).Error == nil {
+		project["audit"] = audit
+	}
+
+	if version, ok := project["version"].(uint); ok {
+		c.Header("ETag", projectETag(version))
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: project})
 }
 
 // CreateProject godoc
@@ -171,54 +340,77 @@ func (h *Handler) GetProject(c *gin.Context) {
 // @Tags         projects
 // @Accept       json
 // @Produce      json
-// @Param        project  body      Project  true  "Project data"
-// @Success      201     {object}  map[string]interface{}  "Created project"
-// @Failure      400     {object}  map[string]interface{}  "Bad request"
-// @Failure      500     {object}  map[string]interface{}  "Internal server error"
+// @Param        project  body      CreateProjectRequest  true  "Project data"
+// @Success      201     {object}  types.DataResponse{data=Project}  "Created project"
+// @Failure      400     {object}  map[string]interface{}            "Bad request"
+// @Failure      409     {object}  middleware.ErrorResponse          "Declared port owned by another project"
+// @Failure      422     {object}  middleware.ErrorResponse          "Validation failed, with every failed rule in details"
+// @Failure      500     {object}  map[string]interface{}            "Internal server error"
 // @Router       /projects [post]
 func (h *Handler) CreateProject(c *gin.Context) {
-	var project Project
-	if err := c.ShouldBindJSON(&project); err != nil {
+	// The same type ValidationMiddleware checked
+	var req CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.db.Create(&project).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(http.StatusCreated, gin.H{"data": project})
-}
-
-func (h *Handler) UpdateProject(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
-		return
-	}
-
-	var project Project
-	if err := h.db.First(&project, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+	project := req.Project()
+	if req.Group != "" && project.GroupID == nil {
+		groupID, err := h.groupIDByName(nil, req.Group)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Group not found", req.Group))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		project.GroupID = &groupID
 	}
 
-	if err := c.ShouldBindJSON(&project); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := validateProjectPorts(h.db, 0, project.DeclaredPorts); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if !validateProject(c, &project) {
 		return
 	}
 
-	if err := h.db.Save(&project).Error; err != nil {
+	if err := h.db.Create(&project).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": project})
+	h.events.Publish(project.ID, "project_created", project)
+	c.JSON(http.StatusCreated, types.DataResponse{Data: project})
+}
+
+// UpdateProject godoc
+// @Summary      Update a project
+// @Description  Update an existing project with the fields of the body, like PATCH: fields left out or null keep their value, and status, PID or logs are never overwritten. When the body has the version of the project it was edited from (or If-Match its ETag), the update is refused with 409 and the current project if another change was saved since.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                   true   "Project ID"
+// @Param        If-Match  header    string                false  "ETag of the project the changes were made on, e.g. \"3\"; the version field of the body works too"
+// @Param        project   body      UpdateProjectRequest  true   "Changed fields"
+// @Success      200       {object}  types.DataResponse{data=Project}  "Updated project"
+// @Failure      400       {object}  middleware.ErrorResponse          "Bad request"
+// @Failure      404       {object}  middleware.ErrorResponse          "Project not found"
+// @Failure      409       {object}  middleware.ErrorResponse          "Changed since the version of the request, with the current project in details; or declared port owned by another project"
+// @Failure      422       {object}  middleware.ErrorResponse          "Validation failed, with every failed rule in details"
+// @Failure      500       {object}  middleware.ErrorResponse          "Internal server error"
+// @Router       /projects/{id} [put]
+func (h *Handler) UpdateProject(c *gin.Context) {
+	h.updateProject(c)
 }
 
+// DeleteProject godoc
+// @Summary      Delete a project
+// @Description  Delete a project by its ID
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.MessageResponse   "Project deleted"
+// @Failure      400  {object}  map[string]interface{}  "Bad request"
+// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Router       /projects/{id} [delete]
 func (h *Handler) DeleteProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -230,9 +422,22 @@ func (h *Handler) DeleteProject(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+	h.events.Publish(uint(id), "project_deleted", gin.H{"id": id})
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Project deleted successfully"})
 }
 
+// StartProject godoc
+// @Summary      Start a project
+// @Description  Start the service process of a project. A start waiting for its wait_for conditions or a start queue slot is answered with 202 and goes on in the background; stopping the project cancels it.
+// @Tags         services
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.ProjectActionResponse  "Project started"
+// @Success      202  {object}  types.ProjectActionResponse  "Project waiting to start"
+// @Failure      400  {object}  map[string]interface{}       "Bad request"
+// @Failure      409  {object}  middleware.ErrorResponse     "Another operation is in progress"
+// @Failure      500  {object}  map[string]interface{}       "Internal server error"
+// @Router       /projects/{id}/start [post]
 func (h *Handler) StartProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -240,16 +445,20 @@ func (h *Handler) StartProject(c *gin.Context) {
 		return
 	}
 
-	// Broadcast status update via WebSocket before starting
-	h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+	// Publish the status update before starting
+	h.events.Publish(uint(id), "status_update", gin.H{
 		"project_id": id,
 		"status":     "starting",
 		"message":    "Project is starting...",
 	})
 
-	if err := h.manager.StartService(uint(id)); err != nil {
-		// Broadcast error status
-		h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+	pending, err := h.manager.StartServiceAsync(uint(id))
+	if err != nil {
+		if h.handleOperationConflict(c, err) {
+			return
+		}
+		// Publish the error status
+		h.events.Publish(uint(id), "status_update", gin.H{
 			"project_id": id,
 			"status":     "error",
 			"message":    fmt.Sprintf("Failed to start: %v", err),
@@ -257,6 +466,12 @@ func (h *Handler) StartProject(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// The start goes on in the background while it waits (wait_for,
+	// start_queue); its status tells how far it is
+	if pending {
+		c.JSON(http.StatusAccepted, types.ProjectActionResponse{Message: "Project is waiting to start", ProjectID: id})
+		return
+	}
 
 	// Wait a bit to verify process started successfully
 	time.Sleep(500 * time.Millisecond)
@@ -264,17 +479,28 @@ func (h *Handler) StartProject(c *gin.Context) {
 	// Get updated status from database
 	var project Project
 	if err := h.db.First(&project, id).Error; err == nil {
-		// Broadcast actual status (should be "running" if successful)
-		h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+		// Publish the actual status (should be "running" if successful)
+		h.events.Publish(uint(id), "status_update", gin.H{
 			"project_id": id,
 			"status":     project.Status,
 			"message":    fmt.Sprintf("Project status: %s", project.Status),
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project started successfully", "project_id": id})
+	c.JSON(http.StatusOK, types.ProjectActionResponse{Message: "Project started successfully", ProjectID: id})
 }
 
+// StopProject godoc
+// @Summary      Stop a project
+// @Description  Stop the service process of a project
+// @Tags         services
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.ProjectActionResponse  "Project stopped"
+// @Failure      400  {object}  map[string]interface{}       "Bad request"
+// @Failure      409  {object}  middleware.ErrorResponse     "Another operation is in progress"
+// @Failure      500  {object}  map[string]interface{}       "Internal server error"
+// @Router       /projects/{id}/stop [post]
 func (h *Handler) StopProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -283,20 +509,33 @@ func (h *Handler) StopProject(c *gin.Context) {
 	}
 
 	if err := h.manager.StopService(uint(id)); err != nil {
+		if h.handleOperationConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Broadcast status update via WebSocket
-	h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+	// Publish the status update
+	h.events.Publish(uint(id), "status_update", gin.H{
 		"project_id": id,
 		"status":     "stopped",
 		"message":    "Project stopped",
 	})
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project stopped successfully", "project_id": id})
+	c.JSON(http.StatusOK, types.ProjectActionResponse{Message: "Project stopped successfully", ProjectID: id})
 }
 
+// ForceKillProject godoc
+// @Summary      Force kill a project
+// @Description  Forcefully kill the service process of a project
+// @Tags         services
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.ProjectActionResponse  "Project force killed"
+// @Failure      400  {object}  map[string]interface{}       "Bad request"
+// @Failure      500  {object}  map[string]interface{}       "Internal server error"
+// @Router       /projects/{id}/force-kill [post]
 func (h *Handler) ForceKillProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -304,16 +543,16 @@ func (h *Handler) ForceKillProject(c *gin.Context) {
 		return
 	}
 
-	// Broadcast status update via WebSocket
-	h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+	// Publish the status update
+	h.events.Publish(uint(id), "status_update", gin.H{
 		"project_id": id,
 		"status":     "stopping",
 		"message":    "Force killing project...",
 	})
 
 	if err := h.manager.ForceKillService(uint(id)); err != nil {
-		// Broadcast error status
-		h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+		// Publish the error status
+		h.events.Publish(uint(id), "status_update", gin.H{
 			"project_id": id,
 			"status":     "error",
 			"message":    fmt.Sprintf("Failed to force kill: %v", err),
@@ -322,16 +561,27 @@ func (h *Handler) ForceKillProject(c *gin.Context) {
 		return
 	}
 
-	// Broadcast status update via WebSocket
-	h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+	// Publish the status update
+	h.events.Publish(uint(id), "status_update", gin.H{
 		"project_id": id,
 		"status":     "stopped",
 		"message":    "Project force killed",
 	})
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project force killed successfully", "project_id": id})
+	c.JSON(http.StatusOK, types.ProjectActionResponse{Message: "Project force killed successfully", ProjectID: id})
 }
 
+// RestartProject godoc
+// @Summary      Restart a project
+// @Description  Stop and start the service process of a project
+// @Tags         services
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.ProjectActionResponse  "Project restarted"
+// @Failure      400  {object}  map[string]interface{}       "Bad request"
+// @Failure      409  {object}  middleware.ErrorResponse     "Another operation is in progress"
+// @Failure      500  {object}  map[string]interface{}       "Internal server error"
+// @Router       /projects/{id}/restart [post]
 func (h *Handler) RestartProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -340,20 +590,49 @@ func (h *Handler) RestartProject(c *gin.Context) {
 	}
 
 	if err := h.manager.RestartService(uint(id)); err != nil {
+		if h.handleOperationConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Broadcast status update via WebSocket
-	h.hub.BroadcastToProject(uint(id), "status_update", gin.H{
+	// Publish the status update
+	h.events.Publish(uint(id), "status_update", gin.H{
 		"project_id": id,
 		"status":     "restarting",
 		"message":    "Project is restarting...",
 	})
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project restarted successfully", "project_id": id})
+	c.JSON(http.StatusOK, types.ProjectActionResponse{Message: "Project restarted successfully", ProjectID: id})
 }
 
+// handleOperationConflict responds with 409 when another lifecycle operation
+// is already running for the project. It returns true if the error was handled.
+func (h *Handler) handleOperationConflict(c *gin.Context, err error) bool {
+	var opErr *service.OperationInProgressError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+
+	middleware.HandleError(c, middleware.NewError(http.StatusConflict, err.Error(), gin.H{
+		"project_id": opErr.ProjectID,
+		"requested":  opErr.Requested,
+		"operation":  opErr.Current,
+	}))
+	return true
+}
+
+// GetProjectStatus godoc
+// @Summary      Get project status
+// @Description  Get the verified runtime status of a project
+// @Tags         services
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=Project}  "Project status"
+// @Failure      400  {object}  map[string]interface{}            "Bad request"
+// @Failure      500  {object}  map[string]interface{}            "Internal server error"
+// @Router       /projects/{id}/status [get]
 func (h *Handler) GetProjectStatus(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -366,10 +645,41 @@ func (h *Handler) GetProjectStatus(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	project["declared_ports"] = h.manager.GetDeclaredPortStatuses(uint(id))
+	if pipeline := ci.Latest(h.db, uint(id)); pipeline != nil {
+		project["ci"] = pipeline
+	}
+	if run := latestSmokeTestRun(h.db, uint(id)); run != nil {
+		project["smoke_test_run"] = run
+	}
+	if monitors, err := projectMonitorSummaries(h.db, uint(id)); err == nil && len(monitors) > 0 {
+		project["monitors"] = monitors
+	}
+	// The port the service listens on after a blue/green restart
+	if port, ok := project["port"].(int); ok && port > 0 {
+		if active := h.manager.ActivePort(uint(id), port); active != port {
+			project["active_port"] = active
+		}
+	}
+	// And each instance of a project scaled out
+	if instances, err := h.manager.Instances(uint(id)); err == nil && len(instances) > 1 {
+		project["instance_statuses"] = instances
+	}
 
-	c.JSON(http.StatusOK, gin.H{"data": project})
+	c.JSON(http.StatusOK, types.DataResponse{Data: project})
 }
 
+// GetLogs godoc
+// @Summary      Get project logs
+// @Description  Get buffered logs of a project from memory or the database. Every line is stamped with its capture time in UTC (entries); with timestamps=true the lines in logs are also prefixed with it, in the timezone given by tz.
+// @Tags         logs
+// @Produce      json
+// @Param        id          path      int     true   "Project ID"
+// @Param        timestamps  query     bool    false  "Prefix lines with their capture time"
+// @Param        tz          query     string  false  "Timezone of the prefixes: IANA name or +HH:MM (default UTC)"
+// @Success      200  {object}  types.DataResponse{data=LogsResponse}  "Project logs"
+// @Failure      400  {object}  map[string]interface{}                 "Bad request"
+// @Router       /projects/{id}/logs [get]
 func (h *Handler) GetLogs(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -377,29 +687,69 @@ func (h *Handler) GetLogs(c *gin.Context) {
 		return
 	}
 
-	// First try to get logs from running service (memory buffer)
-	logs := h.manager.GetServiceLogBuffer(uint(id))
-	
-	// If no logs in memory, try to get from database
-	if len(logs) == 0 {
-		var project Project
-		if err := h.db.First(&project, id).Error; err == nil && project.Logs != "" {
-			// Parse logs from database
-			var dbLogs []string
-			if err := json.Unmarshal([]byte(project.Logs), &dbLogs); err == nil {
-				logs = dbLogs
-			}
+	var display websocket.LogDisplay
+	if v := c.Query("timestamps"); v != "" {
+		if display.Timestamps, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timestamps value"})
+			return
 		}
 	}
+	if display.Location, err = websocket.ParseTimezone(c.Query("tz")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// From the running service (memory buffer), or the database
+	entries := h.manager.GetLogEntries(uint(id))
+	logs := make([]string, len(entries))
+	for i, entry := range entries {
+		logs[i] = display.Format(entry.Line, entry.Time)
+	}
 	
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"logs": logs,
-			"count": len(logs),
-		},
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: LogsResponse{
+		Logs:     logs,
+		Entries:  entries,
+		Count:    len(logs),
+		Timezone: websocket.DescribeTimezone(display.Location, time.Now()),
+	}})
+}
+
+// GetTailedFiles godoc
+// @Summary      Get the tailed log files of a project
+// @Description  List the log files of tail_files tailed for the running service, whose lines are merged into its logs prefixed with "[source] ". Glob patterns are matched again every 5 seconds; files are followed through rotation, whether renamed or truncated. Empty when the service is not running.
+// @Tags         logs
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=[]service.TailedFile}
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/logs/files [get]
+func (h *Handler) GetTailedFiles(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	files := h.manager.TailedFiles(project.ID)
+	if files == nil {
+		files = []service.TailedFile{}
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: files})
 }
 
+// StreamLogs godoc
+// @Summary      Stream project logs
+// @Description  Upgrade to a WebSocket connection streaming buffered and live logs. Log lines can be filtered server-side with the query parameters below, or later by sending {"type":"subscribe","filter":{"level":"warn","include":"...","exclude":"..."}}. Each log message carries the capture time of its line in UTC (time); prefixing lines with it is toggled by timestamps and tz, or later by sending {"type":"display","timestamps":true,"tz":"..."}.
+// @Tags         logs
+// @Param        id          path   int     true   "Project ID"
+// @Param        level       query  string  false  "Minimum log level (trace, debug, info, warn, error, fatal)"
+// @Param        include     query  string  false  "Only stream lines matching this regex"
+// @Param        exclude     query  string  false  "Drop lines matching this regex"
+// @Param        timestamps  query  bool    false  "Prefix lines with their capture time"
+// @Param        tz          query  string  false  "Timezone of the prefixes: IANA name or +HH:MM (default UTC)"
+// @Success      101  "Switching protocols"
+// @Failure      400  {object}  map[string]interface{}  "Bad request"
+// @Router       /projects/{id}/logs/ws [get]
 func (h *Handler) StreamLogs(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -428,26 +778,14 @@ func (h *Handler) StreamLogs(c *gin.Context) {
 		shouldSendBuffered := !hasSent || timeSinceLastSent > 10*time.Second
 		
 		if shouldSendBuffered {
-			// First, try to get logs from running service (memory buffer)
-			bufferedLogs := h.manager.GetServiceLogBuffer(uint(id))
-			
-			// If no logs in memory, try to get from database
-			if len(bufferedLogs) == 0 {
-				var project Project
-				if err := h.db.First(&project, id).Error; err == nil && project.Logs != "" {
-					// Parse logs from database
-					var dbLogs []string
-					if err := json.Unmarshal([]byte(project.Logs), &dbLogs); err == nil {
-						bufferedLogs = dbLogs
-					}
-				}
-			}
+			// From the running service (memory buffer), or the database
+			bufferedLogs := h.manager.GetLogEntries(uint(id))
 			
 			// Send buffered logs if available
 			if len(bufferedLogs) > 0 {
 				// Send buffered logs
-				for _, logLine := range bufferedLogs {
-					h.hub.BroadcastToProject(uint(id), "log", logLine)
+				for _, entry := range bufferedLogs {
+					h.hub.BroadcastLog(uint(id), entry.Line, entry.Time)
 					time.Sleep(10 * time.Millisecond) // Small delay to avoid overwhelming
 				}
 				// Send separator if service is running
@@ -477,22 +815,14 @@ func (h *Handler) StreamLogs(c *gin.Context) {
 						"status": "stopped",
 						"p_id":   0,
 					})
+					h.manager.RecordStatus(project.ID, "stopped", "Process no longer running")
 					statusChanged = true
 				}
 				
 				// Only send message if we haven't sent buffered logs recently (to avoid duplicate messages)
 				if shouldSendBuffered {
 					// Get buffered logs for message
-					bufferedLogs := h.manager.GetServiceLogBuffer(uint(id))
-					if len(bufferedLogs) == 0 {
-						var project Project
-						if err := h.db.First(&project, id).Error; err == nil && project.Logs != "" {
-							var dbLogs []string
-							if err := json.Unmarshal([]byte(project.Logs), &dbLogs); err == nil {
-								bufferedLogs = dbLogs
-							}
-						}
-					}
+					bufferedLogs := h.manager.GetLogEntries(uint(id))
 					
 					// Send appropriate message with more context
 					if len(bufferedLogs) == 0 {
@@ -531,27 +861,72 @@ func (h *Handler) StreamLogs(c *gin.Context) {
 		}
 
 		// Stream new logs (only new logs from channel, not buffered)
-		for logLine := range logs {
-			h.hub.BroadcastToProject(uint(id), "log", logLine)
+		for entry := range logs {
+			h.hub.BroadcastLog(uint(id), entry.Line, entry.Time)
 		}
 	}()
 }
 
+// GetRunningServices godoc
+// @Summary      Get running services
+// @Description  Get the services running now, sorted by name: the processes of this server and the projects the database marks running whose process is still alive (or which run on an SSH host), with their uptime, port and the CPU and memory of their process tree at the last sample
+// @Tags         services
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]service.RunningService}  "Running services"
+// @Router       /services/running [get]
 func (h *Handler) GetRunningServices(c *gin.Context) {
 	services := h.manager.GetRunningServices()
-	c.JSON(http.StatusOK, gin.H{"data": services})
+	c.JSON(http.StatusOK, types.DataResponse{Data: services})
+}
+
+// GetAutostartResults godoc
+// @Summary      Get autostart results
+// @Description  Get the outcome of the autostart run performed when the server started
+// @Tags         services
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=service.AutostartSummary}  "Autostart results"
+// @Failure      404  {object}  middleware.ErrorResponse                           "Autostart has not run yet"
+// @Router       /services/autostart [get]
+func (h *Handler) GetAutostartResults(c *gin.Context) {
+	summary := h.manager.LastAutostart()
+	if summary == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Autostart has not run yet", nil))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: summary})
 }
 
 // Project Group handlers
+
+// GetProjectGroups godoc
+// @Summary      Get all project groups
+// @Description  Get all project groups with their projects
+// @Tags         groups
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]ProjectGroup}  "List of groups"
+// @Failure      500  {object}  map[string]interface{}                   "Internal server error"
+// @Router       /groups [get]
 func (h *Handler) GetProjectGroups(c *gin.Context) {
 	var groups []ProjectGroup
 	if err := h.db.Preload("Projects").Find(&groups).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": groups})
+	c.JSON(http.StatusOK, types.DataResponse{Data: groups})
 }
 
+// CreateProjectGroup godoc
+// @Summary      Create a project group
+// @Description  Create a new project group
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        group  body      CreateProjectGroupRequest  true  "Group data"
+// @Success      201    {object}  types.DataResponse{data=ProjectGroup}  "Created group"
+// @Failure      400    {object}  map[string]interface{}                 "Bad request"
+// @Failure      422    {object}  middleware.ErrorResponse               "Validation failed, with every failed rule in details"
+// @Failure      500    {object}  map[string]interface{}                 "Internal server error"
+// @Router       /groups [post]
 func (h *Handler) CreateProjectGroup(c *gin.Context) {
 	var req CreateProjectGroupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -569,10 +944,22 @@ func (h *Handler) CreateProjectGroup(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.events.Publish(0, "group_created", group)
 
-	c.JSON(http.StatusCreated, gin.H{"data": group})
+	c.JSON(http.StatusCreated, types.DataResponse{Data: group})
 }
 
+// GetProjectGroup godoc
+// @Summary      Get a project group
+// @Description  Get a project group by ID with its projects
+// @Tags         groups
+// @Produce      json
+// @Param        id   path      int  true  "Group ID"
+// @Success      200  {object}  types.DataResponse{data=ProjectGroup}  "Group data"
+// @Failure      400  {object}  map[string]interface{}                 "Bad request"
+// @Failure      404  {object}  map[string]interface{}                 "Group not found"
+// @Failure      500  {object}  map[string]interface{}                 "Internal server error"
+// @Router       /groups/{id} [get]
 func (h *Handler) GetProjectGroup(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -590,9 +977,23 @@ func (h *Handler) GetProjectGroup(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": group})
+	c.JSON(http.StatusOK, types.DataResponse{Data: group})
 }
 
+// UpdateProjectGroup godoc
+// @Summary      Update a project group
+// @Description  Update the provided fields of a project group
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                        true  "Group ID"
+// @Param        group  body      UpdateProjectGroupRequest  true  "Group fields"
+// @Success      200    {object}  types.DataResponse{data=ProjectGroup}  "Updated group"
+// @Failure      400    {object}  map[string]interface{}                 "Bad request"
+// @Failure      404    {object}  map[string]interface{}                 "Group not found"
+// @Failure      422    {object}  middleware.ErrorResponse               "Validation failed, with every failed rule in details"
+// @Failure      500    {object}  map[string]interface{}                 "Internal server error"
+// @Router       /groups/{id} [put]
 func (h *Handler) UpdateProjectGroup(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -631,10 +1032,21 @@ func (h *Handler) UpdateProjectGroup(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.events.Publish(0, "group_updated", group)
 
-	c.JSON(http.StatusOK, gin.H{"data": group})
+	c.JSON(http.StatusOK, types.DataResponse{Data: group})
 }
 
+// DeleteProjectGroup godoc
+// @Summary      Delete a project group
+// @Description  Delete a project group by ID
+// @Tags         groups
+// @Produce      json
+// @Param        id   path      int  true  "Group ID"
+// @Success      200  {object}  types.MessageResponse   "Group deleted"
+// @Failure      400  {object}  map[string]interface{}  "Bad request"
+// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Router       /groups/{id} [delete]
 func (h *Handler) DeleteProjectGroup(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -646,10 +1058,21 @@ func (h *Handler) DeleteProjectGroup(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.events.Publish(0, "group_deleted", gin.H{"id": id})
 
-	c.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Group deleted successfully"})
 }
 
+// GetGroupProjects godoc
+// @Summary      Get projects of a group
+// @Description  Get all projects belonging to a group
+// @Tags         groups
+// @Produce      json
+// @Param        id   path      int  true  "Group ID"
+// @Success      200  {object}  types.DataResponse{data=[]Project}  "Group projects"
+// @Failure      400  {object}  map[string]interface{}            "Bad request"
+// @Failure      500  {object}  map[string]interface{}            "Internal server error"
+// @Router       /groups/{id}/projects [get]
 func (h *Handler) GetGroupProjects(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -663,100 +1086,19 @@ func (h *Handler) GetGroupProjects(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": projects})
-}
-
-// InstallPackagesRequest represents the request to install packages
-type InstallPackagesRequest struct {
-	PackageManager string   `json:"package_manager" binding:"required,oneof=npm yarn pnpm go pip"`
-	Packages       []string `json:"packages"`
-}
-
-// InstallPackages installs packages for a project
-func (h *Handler) InstallPackages(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		middleware.HandleError(c, middleware.ErrBadRequest)
-		return
-	}
-
-	var req InstallPackagesRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
-		return
-	}
-
-	// Get project from database
-	var project Project
-	if err := h.db.First(&project, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			middleware.HandleError(c, middleware.ErrNotFound)
-			return
-		}
-		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error()))
-		return
-	}
-
-	// Determine working directory
-	workingDir := project.Path
-	if project.WorkingDir != "" {
-		workingDir = project.WorkingDir
-	}
-
-	// Build command based on package manager
-	var cmd *exec.Cmd
-	switch req.PackageManager {
-	case "npm":
-		if len(req.Packages) > 0 {
-			cmd = exec.Command("npm", append([]string{"install"}, req.Packages...)...)
-		} else {
-			cmd = exec.Command("npm", "install")
-		}
-	case "yarn":
-		if len(req.Packages) > 0 {
-			cmd = exec.Command("yarn", append([]string{"add"}, req.Packages...)...)
-		} else {
-			cmd = exec.Command("yarn", "install")
-		}
-	case "pnpm":
-		if len(req.Packages) > 0 {
-			cmd = exec.Command("pnpm", append([]string{"add"}, req.Packages...)...)
-		} else {
-			cmd = exec.Command("pnpm", "install")
-		}
-	case "go":
-		if len(req.Packages) > 0 {
-			cmd = exec.Command("go", append([]string{"get"}, req.Packages...)...)
-		} else {
-			cmd = exec.Command("go", "mod", "download")
-		}
-	case "pip":
-		if len(req.Packages) > 0 {
-			cmd = exec.Command("pip", append([]string{"install"}, req.Packages...)...)
-		} else {
-			cmd = exec.Command("pip", "install", "-r", "requirements.txt")
-		}
-	default:
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Unsupported package manager", fmt.Sprintf("Package manager %s is not supported", req.PackageManager)))
-		return
-	}
-
-	cmd.Dir = workingDir
-
-	// Execute command
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to install packages", string(output)))
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Packages installed successfully",
-		"output":  string(output),
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: projects})
 }
 
-// GetTerminalUrl returns terminal access information for a project
+// GetTerminalUrl godoc
+// @Summary      Get terminal information
+// @Description  Get the absolute project path and instructions to open a terminal there
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=TerminalInfo}  "Terminal information"
+// @Failure      400  {object}  middleware.ErrorResponse               "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse               "Project not found"
+// @Router       /projects/{id}/terminal [get]
 func (h *Handler) GetTerminalUrl(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -789,18 +1131,16 @@ func (h *Handler) GetTerminalUrl(c *gin.Context) {
 		absPath = workingDir
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"path":        absPath,
-			"working_dir": absPath,
-			"instructions": fmt.Sprintf(
-				"To open a terminal for this project, navigate to: %s\n"+
-					"On macOS/Linux: cd %s\n"+
-					"On Windows: cd /d %s",
-				absPath, absPath, absPath,
-			),
-		},
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: TerminalInfo{
+		Path:       absPath,
+		WorkingDir: absPath,
+		Instructions: fmt.Sprintf(
+			"To open a terminal for this project, navigate to: %s\n"+
+				"On macOS/Linux: cd %s\n"+
+				"On Windows: cd /d %s",
+			absPath, absPath, absPath,
+		),
+	}})
 }
 
 // OpenTerminalRequest represents the request to open a terminal
@@ -808,7 +1148,18 @@ type OpenTerminalRequest struct {
 	OS string `json:"os"` // "macos", "linux", "windows", or "auto" (auto-detect from User-Agent)
 }
 
-// OpenTerminal creates a script to open terminal in the project directory
+// OpenTerminal godoc
+// @Summary      Open a terminal
+// @Description  Build the command that opens a terminal in the project directory for the given OS
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true   "Project ID"
+// @Param        request  body      OpenTerminalRequest  false  "Target OS"
+// @Success      200      {object}  types.DataResponse{data=TerminalInfo}  "Terminal command"
+// @Failure      400      {object}  middleware.ErrorResponse               "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse               "Project not found"
+// @Router       /projects/{id}/terminal/open [post]
 func (h *Handler) OpenTerminal(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -915,17 +1266,16 @@ func (h *Handler) OpenTerminal(c *gin.Context) {
 		escapedForAppleScript = strings.ReplaceAll(escapedForAppleScript, `\`, `\\`)
 		macosCmd := fmt.Sprintf(`osascript -e 'tell application "Terminal" to do script "cd " & quoted form of (POSIX path of "%s")'`, escapedForAppleScript)
 		
-		c.JSON(http.StatusOK, gin.H{
-			"data": gin.H{
-				"path":        absPath,
-				"working_dir": absPath,
-				"commands": map[string]string{
-					"macos":   macosCmd,
-					"linux":   fmt.Sprintf(`gnome-terminal --working-directory="%s"`, escapedPath),
-					"windows": fmt.Sprintf(`cmd /k "cd /d \"%s\""`, escapedPath),
-				},
-				"simple_command": fmt.Sprintf(`cd "%s"`, absPath),
-				"instructions": fmt.Sprintf(
+		c.JSON(http.StatusOK, types.DataResponse{Data: TerminalInfo{
+			Path:       absPath,
+			WorkingDir: absPath,
+			Commands: map[string]string{
+				"macos":   macosCmd,
+				"linux":   fmt.Sprintf(`gnome-terminal --working-directory="%s"`, escapedPath),
+				"windows": fmt.Sprintf(`cmd /k "cd /d \"%s\""`, escapedPath),
+			},
+			SimpleCommand: fmt.Sprintf(`cd "%s"`, absPath),
+			Instructions: fmt.Sprintf(
 					"Copy and run one of these commands in your terminal:\n\n"+
 						"macOS:\n"+
 						"  %s\n\n"+
@@ -935,37 +1285,68 @@ func (h *Handler) OpenTerminal(c *gin.Context) {
 						"  cmd /k \"cd /d \\\"%s\\\"\"\n\n"+
 						"Or simply navigate to:\n"+
 						"  cd \"%s\"",
-					macosCmd, absPath, absPath, absPath,
-				),
-			},
-		})
+				macosCmd, absPath, absPath, absPath,
+			),
+		}})
 		return
 	}
 
 	// Return command and instructions as JSON
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"path":           absPath,
-			"working_dir":    absPath,
-			"command":        command,
-			"simple_command": fmt.Sprintf(`cd "%s"`, absPath),
-			"os":             req.OS,
-			"instructions":  instructions,
-		},
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: TerminalInfo{
+		Path:          absPath,
+		WorkingDir:    absPath,
+		Command:       command,
+		SimpleCommand: fmt.Sprintf(`cd "%s"`, absPath),
+		OS:            req.OS,
+		Instructions:  instructions,
+	}})
 }
 
-// GetPorts returns list of ports in use
+// GetPorts godoc
+// @Summary      Get ports in use
+// @Description  List listening TCP ports and bound UDP ports with their owning processes and the project that declared them
+// @Tags         ports
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]service.PortInfo}  "Ports in use"
+// @Failure      500  {object}  middleware.ErrorResponse                     "Internal server error"
+// @Router       /ports [get]
 func (h *Handler) GetPorts(c *gin.Context) {
 	ports, err := h.manager.GetPortsInUse()
 	if err != nil {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to get ports", err.Error()))
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": ports})
+	h.manager.AttachPortOwners(ports)
+	c.JSON(http.StatusOK, types.DataResponse{Data: ports})
 }
 
-// KillPort kills the process using the specified port
+// GetUnixSockets godoc
+// @Summary      Get listening Unix sockets
+// @Description  List listening Unix domain sockets with their owning processes and the project that declared them
+// @Tags         ports
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]service.UnixSocketInfo}  "Listening sockets"
+// @Failure      500  {object}  middleware.ErrorResponse                           "Internal server error"
+// @Router       /ports/sockets [get]
+func (h *Handler) GetUnixSockets(c *gin.Context) {
+	sockets, err := h.manager.GetUnixSocketsInUse()
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to get unix sockets", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: sockets})
+}
+
+// KillPort godoc
+// @Summary      Kill process on port
+// @Description  Kill the process listening on the specified port
+// @Tags         ports
+// @Produce      json
+// @Param        port  path      int  true  "Port number"
+// @Success      200   {object}  KillPortResponse          "Process killed"
+// @Failure      400   {object}  middleware.ErrorResponse  "Invalid port"
+// @Failure      500   {object}  middleware.ErrorResponse  "Failed to kill process"
+// @Router       /ports/{port} [delete]
 func (h *Handler) KillPort(c *gin.Context) {
 	portStr := c.Param("port")
 	port, err := strconv.Atoi(portStr)
@@ -980,88 +1361,138 @@ func (h *Handler) KillPort(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("Process on port %d has been killed", port),
-		"port":    port,
+	c.JSON(http.StatusOK, KillPortResponse{
+		Message: fmt.Sprintf("Process on port %d has been killed", port),
+		Port:    port,
 	})
 }
 
+// importWaitTimeout is how long an import request waits for its job before
+// returning 202 Accepted, below the server write timeout
+const importWaitTimeout = 20 * time.Second
+
 // ImportProjectsRequest represents the import request
 type ImportProjectsRequest struct {
 	Projects []CreateProjectRequest `json:"projects"`
 	Groups   []CreateProjectGroupRequest `json:"groups"`
 }
 
-// ImportProjects imports multiple projects from config file
+// ImportProjects godoc
+// @Summary      Import projects
+// @Description  Import groups and projects from a JSON body. A YAML/JSON file can also be uploaded as multipart form field "file", or read from the server with path. Files can list other files under include (a file or a list of files, glob patterns allowed; relative to the including file, so only with path), whose groups and projects come first; a project defined twice keeps its last definition. YAML anchors, aliases and merge keys work within a file, with top-level x- keys to hold shared blocks, and env_vars may be a mapping. The file is linted first: unknown keys, values of the wrong type, missing paths, invalid env_vars JSON and ports used by other projects are reported as errors or warnings, and nothing is imported while there are errors (422 with the report). dry_run only returns the report. The import runs as a background job; if it takes longer than 20 seconds the job is returned with 202 and keeps running.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ImportProjectsRequest  true   "Projects and groups to import"
+// @Param        path     query     string                 false  "Import file on the server"
+// @Param        dry_run  query     bool                   false  "Only lint the file"
+// @Success      200      {object}  types.DataMessageResponse{data=ImportResult}  "Import result, or LintReport with dry_run"
+// @Success      202      {object}  types.DataMessageResponse{data=jobs.Job}      "Import still running; poll GET /jobs/{id}"
+// @Failure      400      {object}  middleware.ErrorResponse                      "Bad request"
+// @Failure      409      {object}  middleware.ErrorResponse                      "Another import is running"
+// @Failure      422      {object}  middleware.ErrorResponse{details=LintReport}  "The file has errors"
+// @Router       /projects/import [post]
 func (h *Handler) ImportProjects(c *gin.Context) {
-	// Check if it's a file upload
-	file, err := c.FormFile("file")
-	if err == nil {
-		// Handle file upload
-		src, err := file.Open()
-		if err != nil {
-			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Failed to open file", err.Error()))
-			return
-		}
-		defer src.Close()
+	data, path, err := readImportFile(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
 
-		content, err := io.ReadAll(src)
-		if err != nil {
-			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Failed to read file", err.Error()))
-			return
-		}
+	// Lint the decoded file before anything is saved
+	linter := newConfigLinter(h.db)
+	data = linter.expandImport(data, path)
+	linter.lintImport(data)
+	report := linter.result()
+	if dryRun, _ := strconv.ParseBool(c.Query("dry_run")); dryRun {
+		c.JSON(http.StatusOK, types.DataMessageResponse{
+			Message: fmt.Sprintf("%d error(s), %d warning(s)", len(report.Errors), len(report.Warnings)),
+			Data:    report,
+		})
+		return
+	}
+	if !report.Valid {
+		middleware.HandleError(c, middleware.NewError(http.StatusUnprocessableEntity, "Import file has errors", report))
+		return
+	}
 
-		var importData ImportProjectsRequest
+	// YAML files use the same keys as JSON
+	var importData ImportProjectsRequest
+	normalized, err := json.Marshal(data)
+	if err == nil {
+		err = json.Unmarshal(normalized, &importData)
+	}
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid import file", err.Error()))
+		return
+	}
 
-		// Try to parse as YAML first
-		if strings.HasSuffix(strings.ToLower(file.Filename), ".yaml") || strings.HasSuffix(strings.ToLower(file.Filename), ".yml") {
-			if err := yaml.Unmarshal(content, &importData); err != nil {
-				middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Failed to parse YAML", err.Error()))
-				return
-			}
-		} else {
-			// Try JSON
-			if err := json.Unmarshal(content, &importData); err != nil {
-				middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Failed to parse JSON", err.Error()))
-				return
-			}
-		}
+	h.runImport(c, importData, report.Warnings)
+}
 
-		result := h.processImport(importData)
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Import completed",
-			"data":    result,
-		})
+// runImport imports in a background job so large imports survive HTTP
+// timeouts. The result is returned directly when the job finishes within
+// importWaitTimeout, otherwise the job is returned with 202 Accepted.
+func (h *Handler) runImport(c *gin.Context, importData ImportProjectsRequest, warnings []LintIssue) {
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:    jobs.TypeImport,
+		Key:     jobs.TypeImport,
+		Message: fmt.Sprintf("Importing %d group(s) and %d project(s)", len(importData.Groups), len(importData.Projects)),
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		result := h.processImport(ctx, importData, run.Progress)
+		result.Warnings = warnings
+		return result, ctx.Err()
+	})
+	if err != nil {
+		jobs.HandleError(c, err)
 		return
 	}
 
-	// Handle JSON body
-	var importData ImportProjectsRequest
-	if err := c.ShouldBindJSON(&importData); err != nil {
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+	job, done := h.jobs.Wait(c.Request.Context(), job.ID, importWaitTimeout)
+	if job == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Import job lost", nil))
+		return
+	}
+	if !done {
+		c.JSON(http.StatusAccepted, types.DataMessageResponse{
+			Message: "Import is still running",
+			Data:    job,
+		})
+		return
+	}
+	if job.Status != jobs.StatusSucceeded {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Import failed", job.Error))
 		return
 	}
 
-	result := h.processImport(importData)
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Import completed",
-		"data":    result,
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Message: "Import completed",
+		Data:    job.Result,
 	})
 }
 
-// processImport processes the import data
-func (h *Handler) processImport(importData ImportProjectsRequest) map[string]interface{} {
-	result := map[string]interface{}{
-		"groups_created":   0,
-		"groups_updated":   0,
-		"projects_created": 0,
-		"projects_updated": 0,
-		"errors":           []string{},
+// processImport processes the import data, reporting progress after each
+// group and project. It stops early when ctx is cancelled.
+func (h *Handler) processImport(ctx context.Context, importData ImportProjectsRequest, progress func(percent int, message string)) ImportResult {
+	result := ImportResult{
+		Errors: []string{},
+	}
+
+	total := len(importData.Groups) + len(importData.Projects)
+	step := 0
+	report := func(name string) {
+		step++
+		progress(step*100/total, "Imported "+name)
 	}
 
 	// Create/Update groups first
 	groupMap := make(map[string]uint)
 	for _, groupReq := range importData.Groups {
+		if ctx.Err() != nil {
+			return result
+		}
+		report(groupReq.Name)
+
 		var group ProjectGroup
 		if err := h.db.Where("name = ?", groupReq.Name).First(&group).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -1072,12 +1503,12 @@ func (h *Handler) processImport(importData ImportProjectsRequest) map[string]int
 					Color:       groupReq.Color,
 				}
 				if err := h.db.Create(&group).Error; err != nil {
-					result["errors"] = append(result["errors"].([]string), fmt.Sprintf("Failed to create group %s: %v", groupReq.Name, err))
+					result.Errors = append(result.Errors, fmt.Sprintf("Failed to create group %s: %v", groupReq.Name, err))
 					continue
 				}
-				result["groups_created"] = result["groups_created"].(int) + 1
+				result.GroupsCreated++
 			} else {
-				result["errors"] = append(result["errors"].([]string), fmt.Sprintf("Failed to query group %s: %v", groupReq.Name, err))
+				result.Errors = append(result.Errors, fmt.Sprintf("Failed to query group %s: %v", groupReq.Name, err))
 				continue
 			}
 		} else {
@@ -1089,19 +1520,24 @@ func (h *Handler) processImport(importData ImportProjectsRequest) map[string]int
 				group.Color = groupReq.Color
 			}
 			if err := h.db.Save(&group).Error; err != nil {
-				result["errors"] = append(result["errors"].([]string), fmt.Sprintf("Failed to update group %s: %v", groupReq.Name, err))
+				result.Errors = append(result.Errors, fmt.Sprintf("Failed to update group %s: %v", groupReq.Name, err))
 				continue
 			}
-			result["groups_updated"] = result["groups_updated"].(int) + 1
+			result.GroupsUpdated++
 		}
 		groupMap[groupReq.Name] = group.ID
 	}
 
 	// Create/Update projects
 	for _, projectReq := range importData.Projects {
+		if ctx.Err() != nil {
+			return result
+		}
+		report(projectReq.Name)
+
 		// Validate path exists
 		if _, err := os.Stat(projectReq.Path); os.IsNotExist(err) {
-			result["errors"] = append(result["errors"].([]string), fmt.Sprintf("Path does not exist for project %s: %s", projectReq.Name, projectReq.Path))
+			result.Errors = append(result.Errors, fmt.Sprintf("Path does not exist for project %s: %s", projectReq.Name, projectReq.Path))
 			continue
 		}
 
@@ -1120,6 +1556,14 @@ func (h *Handler) processImport(importData ImportProjectsRequest) map[string]int
 				if projectReq.GroupID != nil {
 					project.GroupID = projectReq.GroupID
 				}
+				if projectReq.Group != "" {
+					groupID, err := h.groupIDByName(groupMap, projectReq.Group)
+					if err != nil {
+						result.Errors = append(result.Errors, fmt.Sprintf("Group %s not found for project %s", projectReq.Group, projectReq.Name))
+						continue
+					}
+					project.GroupID = &groupID
+				}
 				if projectReq.Command != "" {
 					project.Command = projectReq.Command
 				}
@@ -1135,6 +1579,9 @@ func (h *Handler) processImport(importData ImportProjectsRequest) map[string]int
 				if projectReq.Ports != "" {
 					project.Ports = projectReq.Ports
 				}
+				if projectReq.SocketPath != "" {
+					project.SocketPath = projectReq.SocketPath
+				}
 				if projectReq.Environment != "" {
 					project.Environment = projectReq.Environment
 				}
@@ -1154,9 +1601,129 @@ func (h *Handler) processImport(importData ImportProjectsRequest) map[string]int
 					project.HealthCheckURL = projectReq.HealthCheckURL
 				}
 				project.AutoRestart = projectReq.AutoRestart
+				project.Autostart = projectReq.Autostart
+				project.TraceInjection = projectReq.TraceInjection
+				project.WatchFiles = projectReq.WatchFiles
+				project.Optional = projectReq.Optional
+				project.MDNSAnnounce = projectReq.MDNSAnnounce
+				if projectReq.MDNSName != "" {
+					project.MDNSName = projectReq.MDNSName
+				}
+				project.StatusPage = projectReq.StatusPage
+				if projectReq.StatusPageName != "" {
+					project.StatusPageName = projectReq.StatusPageName
+				}
+				project.KubeContext = projectReq.KubeContext
+				project.KubeNamespace = projectReq.KubeNamespace
+				project.KubeDeployment = projectReq.KubeDeployment
+				if projectReq.KubeReplicas > 0 {
+					project.KubeReplicas = projectReq.KubeReplicas
+				}
+				project.SystemdUnit = projectReq.SystemdUnit
+				project.SystemdUser = projectReq.SystemdUser
+				project.SSHHost = projectReq.SSHHost
+				if projectReq.ConnectionString != "" {
+					project.ConnectionString = projectReq.ConnectionString
+				}
+				if projectReq.MigrationCommand != "" {
+					project.MigrationCommand = projectReq.MigrationCommand
+				}
+				if projectReq.TestCommand != "" {
+					project.TestCommand = projectReq.TestCommand
+				}
+				if projectReq.Queues != "" {
+					project.Queues = projectReq.Queues
+				}
+				if projectReq.TailFiles != "" {
+					project.TailFiles = projectReq.TailFiles
+				}
+				if projectReq.PprofURL != "" {
+					project.PprofURL = projectReq.PprofURL
+				}
+				if projectReq.CIRepo != "" {
+					project.CIRepo = projectReq.CIRepo
+				}
+				if projectReq.CIBranch != "" {
+					project.CIBranch = projectReq.CIBranch
+				}
+				if projectReq.Owner != "" {
+					project.Owner = projectReq.Owner
+				}
+				if projectReq.Team != "" {
+					project.Team = projectReq.Team
+				}
+				if projectReq.RepositoryURL != "" {
+					project.RepositoryURL = projectReq.RepositoryURL
+				}
+				if projectReq.DocsURL != "" {
+					project.DocsURL = projectReq.DocsURL
+				}
+				if projectReq.ChatChannel != "" {
+					project.ChatChannel = projectReq.ChatChannel
+				}
+				if projectReq.Nice != 0 {
+					project.Nice = projectReq.Nice
+				}
+				if projectReq.IOClass != "" {
+					project.IOClass = projectReq.IOClass
+				}
+				if projectReq.IOPriority != 0 {
+					project.IOPriority = projectReq.IOPriority
+				}
+				if projectReq.CPUAffinity != "" {
+					project.CPUAffinity = projectReq.CPUAffinity
+				}
+				if projectReq.MemoryGuardMB != 0 {
+					project.MemoryGuardMB = projectReq.MemoryGuardMB
+				}
+				if projectReq.MemoryGuardSamples != 0 {
+					project.MemoryGuardSamples = projectReq.MemoryGuardSamples
+				}
+				project.MemoryGuardRestart = projectReq.MemoryGuardRestart
+				if projectReq.SmokeTests != "" {
+					project.SmokeTests = projectReq.SmokeTests
+				}
+				project.SmokeTestsOnStart = projectReq.SmokeTestsOnStart
+				project.SmokeTestsFailError = projectReq.SmokeTestsFailError
+				if projectReq.QueueBacklogLimit > 0 {
+					project.QueueBacklogLimit = projectReq.QueueBacklogLimit
+				}
+				if projectReq.QueueGrowthLimit > 0 {
+					project.QueueGrowthLimit = projectReq.QueueGrowthLimit
+				}
+				if projectReq.IdleTimeout > 0 {
+					project.IdleTimeout = projectReq.IdleTimeout
+				}
+				if projectReq.MockSpec != "" {
+					project.MockSpec = projectReq.MockSpec
+				}
+				if projectReq.DependsOn != "" {
+					project.DependsOn = projectReq.DependsOn
+				}
+				if projectReq.WaitFor != "" {
+					project.WaitFor = projectReq.WaitFor
+				}
+				if projectReq.WaitForTimeout != 0 {
+					project.WaitForTimeout = projectReq.WaitForTimeout
+				}
+				if projectReq.WaitForInterval != 0 {
+					project.WaitForInterval = projectReq.WaitForInterval
+				}
 				if projectReq.MaxRestarts > 0 {
 					project.MaxRestarts = projectReq.MaxRestarts
 				}
+				if projectReq.RestartStrategy != "" {
+					project.RestartStrategy = projectReq.RestartStrategy
+				}
+				if projectReq.DrainTimeout > 0 {
+					project.DrainTimeout = projectReq.DrainTimeout
+				}
+				if projectReq.Instances > 0 {
+					project.Instances = projectReq.Instances
+				}
+				if projectReq.InstancePorts != "" {
+					project.InstancePorts = projectReq.InstancePorts
+				}
 				if projectReq.CPULimit != "" {
 					project.CPULimit = projectReq.CPULimit
 				}
@@ -1165,12 +1732,12 @@ func (h *Handler) processImport(importData ImportProjectsRequest) map[string]int
 				}
 
 				if err := h.db.Create(&project).Error; err != nil {
-					result["errors"] = append(result["errors"].([]string), fmt.Sprintf("Failed to create project %s: %v", projectReq.Name, err))
+					result.Errors = append(result.Errors, fmt.Sprintf("Failed to create project %s: %v", projectReq.Name, err))
 					continue
 				}
-				result["projects_created"] = result["projects_created"].(int) + 1
+				result.ProjectsCreated++
 			} else {
-				result["errors"] = append(result["errors"].([]string), fmt.Sprintf("Failed to query project %s: %v", projectReq.Name, err))
+				result.Errors = append(result.Errors, fmt.Sprintf("Failed to query project %s: %v", projectReq.Name, err))
 				continue
 			}
 		} else {
@@ -1187,24 +1754,188 @@ func (h *Handler) processImport(importData ImportProjectsRequest) map[string]int
 			if projectReq.Port > 0 {
 				project.Port = projectReq.Port
 			}
+			if projectReq.SocketPath != "" {
+				project.SocketPath = projectReq.SocketPath
+			}
 			if projectReq.Environment != "" {
 				project.Environment = projectReq.Environment
 			}
-			// AutoRestart is a bool, so we always update it
+			if projectReq.DependsOn != "" {
+				project.DependsOn = projectReq.DependsOn
+			}
+			if projectReq.WaitFor != "" {
+				project.WaitFor = projectReq.WaitFor
+			}
+			if projectReq.WaitForTimeout != 0 {
+				project.WaitForTimeout = projectReq.WaitForTimeout
+			}
+			if projectReq.WaitForInterval != 0 {
+				project.WaitForInterval = projectReq.WaitForInterval
+			}
+			if projectReq.ConnectionString != "" {
+				project.ConnectionString = projectReq.ConnectionString
+			}
+			if projectReq.MigrationCommand != "" {
+				project.MigrationCommand = projectReq.MigrationCommand
+			}
+			if projectReq.TestCommand != "" {
+				project.TestCommand = projectReq.TestCommand
+			}
+			if projectReq.Queues != "" {
+				project.Queues = projectReq.Queues
+			}
+			if projectReq.TailFiles != "" {
+				project.TailFiles = projectReq.TailFiles
+			}
+			if projectReq.PprofURL != "" {
+				project.PprofURL = projectReq.PprofURL
+			}
+			if projectReq.CIRepo != "" {
+				project.CIRepo = projectReq.CIRepo
+			}
+			if projectReq.CIBranch != "" {
+				project.CIBranch = projectReq.CIBranch
+			}
+			if projectReq.Owner != "" {
+				project.Owner = projectReq.Owner
+			}
+			if projectReq.Team != "" {
+				project.Team = projectReq.Team
+			}
+			if projectReq.RepositoryURL != "" {
+				project.RepositoryURL = projectReq.RepositoryURL
+			}
+			if projectReq.DocsURL != "" {
+				project.DocsURL = projectReq.DocsURL
+			}
+			if projectReq.ChatChannel != "" {
+				project.ChatChannel = projectReq.ChatChannel
+			}
+			if projectReq.Nice != 0 {
+				project.Nice = projectReq.Nice
+			}
+			if projectReq.IOClass != "" {
+				project.IOClass = projectReq.IOClass
+			}
+			if projectReq.IOPriority != 0 {
+				project.IOPriority = projectReq.IOPriority
+			}
+			if projectReq.CPUAffinity != "" {
+				project.CPUAffinity = projectReq.CPUAffinity
+			}
+			if projectReq.MemoryGuardMB != 0 {
+				project.MemoryGuardMB = projectReq.MemoryGuardMB
+			}
+			if projectReq.MemoryGuardSamples != 0 {
+				project.MemoryGuardSamples = projectReq.MemoryGuardSamples
+			}
+			project.MemoryGuardRestart = projectReq.MemoryGuardRestart
+			if projectReq.SmokeTests != "" {
+				project.SmokeTests = projectReq.SmokeTests
+			}
+			project.SmokeTestsOnStart = projectReq.SmokeTestsOnStart
+			project.SmokeTestsFailError = projectReq.SmokeTestsFailError
+			if projectReq.RestartStrategy != "" {
+				project.RestartStrategy = projectReq.RestartStrategy
+			}
+			if projectReq.DrainTimeout > 0 {
+				project.DrainTimeout = projectReq.DrainTimeout
+			}
+			if projectReq.Instances > 0 {
+				project.Instances = projectReq.Instances
+			}
+			if projectReq.InstancePorts != "" {
+				project.InstancePorts = projectReq.InstancePorts
+			}
+			if projectReq.QueueBacklogLimit > 0 {
+				project.QueueBacklogLimit = projectReq.QueueBacklogLimit
+			}
+			if projectReq.QueueGrowthLimit > 0 {
+				project.QueueGrowthLimit = projectReq.QueueGrowthLimit
+			}
+			if projectReq.IdleTimeout > 0 {
+				project.IdleTimeout = projectReq.IdleTimeout
+			}
+			if projectReq.MockSpec != "" {
+				project.MockSpec = projectReq.MockSpec
+			}
+			if projectReq.MDNSName != "" {
+				project.MDNSName = projectReq.MDNSName
+			}
+			if projectReq.StatusPageName != "" {
+				project.StatusPageName = projectReq.StatusPageName
+			}
+			if projectReq.KubeDeployment != "" {
+				project.KubeContext = projectReq.KubeContext
+				project.KubeNamespace = projectReq.KubeNamespace
+				project.KubeDeployment = projectReq.KubeDeployment
+			}
+			if projectReq.KubeReplicas > 0 {
+				project.KubeReplicas = projectReq.KubeReplicas
+			}
+			if projectReq.SystemdUnit != "" {
+				project.SystemdUnit = projectReq.SystemdUnit
+			}
+			if projectReq.SSHHost != "" {
+				project.SSHHost = projectReq.SSHHost
+			}
+			if projectReq.GroupID != nil {
+				project.GroupID = projectReq.GroupID
+			}
+			if projectReq.Group != "" {
+				groupID, err := h.groupIDByName(groupMap, projectReq.Group)
+				if err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("Group %s not found for project %s", projectReq.Group, projectReq.Name))
+					continue
+				}
+				project.GroupID = &groupID
+			}
+			// AutoRestart, Autostart, TraceInjection, WatchFiles, Optional, MDNSAnnounce, StatusPage and SystemdUser are bools, so we always update them
 			project.AutoRestart = projectReq.AutoRestart
+			project.Autostart = projectReq.Autostart
+			project.TraceInjection = projectReq.TraceInjection
+			project.WatchFiles = projectReq.WatchFiles
+			project.Optional = projectReq.Optional
+			project.MDNSAnnounce = projectReq.MDNSAnnounce
+			project.StatusPage = projectReq.StatusPage
+			project.SystemdUser = projectReq.SystemdUser
+			project.Version++
 
 			if err := h.db.Save(&project).Error; err != nil {
-				result["errors"] = append(result["errors"].([]string), fmt.Sprintf("Failed to update project %s: %v", projectReq.Name, err))
+				result.Errors = append(result.Errors, fmt.Sprintf("Failed to update project %s: %v", projectReq.Name, err))
 				continue
 			}
-			result["projects_updated"] = result["projects_updated"].(int) + 1
+			result.ProjectsUpdated++
 		}
 	}
 
 	return result
 }
 
-// GetProjectConfig returns the project configuration in YAML or JSON format
+// groupIDByName returns the ID of a group of the import, or of an existing
+// group
+func (h *Handler) groupIDByName(groupMap map[string]uint, name string) (uint, error) {
+	if id, ok := groupMap[name]; ok {
+		return id, nil
+	}
+	var group ProjectGroup
+	if err := h.db.Select("id").Where("name = ?", name).First(&group).Error; err != nil {
+		return 0, err
+	}
+	return group.ID, nil
+}
+
+// GetProjectConfig godoc
+// @Summary      Get project configuration
+// @Description  Export the project configuration as a YAML or JSON string
+// @Tags         projects
+// @Produce      json
+// @Param        id      path      int     true   "Project ID"
+// @Param        format  query     string  false  "Output format (yaml or json)"
+// @Success      200     {object}  types.DataResponse{data=string}  "Serialized configuration"
+// @Failure      400     {object}  middleware.ErrorResponse         "Bad request"
+// @Failure      404     {object}  middleware.ErrorResponse         "Project not found"
+// @Router       /projects/{id}/config [get]
 func (h *Handler) GetProjectConfig(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -1227,7 +1958,33 @@ func (h *Handler) GetProjectConfig(c *gin.Context) {
 		format = "yaml"
 	}
 
-	// Create config structure
+	config := projectConfigMap(&project)
+
+	if format == "json" {
+		c.Header("Content-Type", "application/json")
+		jsonData, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to marshal JSON", err.Error()))
+			return
+		}
+		c.JSON(http.StatusOK, types.DataResponse{Data: string(jsonData)})
+		return
+	}
+
+	// Convert to YAML
+	yamlData, err := yaml.Marshal(config)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to marshal YAML", err.Error()))
+		return
+	}
+
+	c.Header("Content-Type", "application/x-yaml")
+	c.JSON(http.StatusOK, types.DataResponse{Data: string(yamlData)})
+}
+
+// projectConfigMap returns the configuration of a project, as exported by
+// GET /projects/{id}/config
+func projectConfigMap(project *Project) map[string]interface{} {
 	config := map[string]interface{}{
 		"name":           project.Name,
 		"description":    project.Description,
@@ -1238,6 +1995,7 @@ func (h *Handler) GetProjectConfig(c *gin.Context) {
 		"working_dir":    project.WorkingDir,
 		"port":           project.Port,
 		"ports":          project.Ports,
+		"socket_path":    project.SocketPath,
 		"environment":    project.Environment,
 		"env_file":       project.EnvFile,
 		"env_vars":       project.EnvVars,
@@ -1245,44 +2003,94 @@ func (h *Handler) GetProjectConfig(c *gin.Context) {
 		"editor_args":    project.EditorArgs,
 		"health_check_url": project.HealthCheckURL,
 		"auto_restart":   project.AutoRestart,
+		"autostart":      project.Autostart,
+		"depends_on":     project.DependsOn,
+		"wait_for":          project.WaitFor,
+		"wait_for_timeout":  project.WaitForTimeout,
+		"wait_for_interval": project.WaitForInterval,
+		"trace_injection": project.TraceInjection,
+		"watch_files":    project.WatchFiles,
+		"optional":       project.Optional,
+		"mdns":           project.MDNSAnnounce,
+		"mdns_name":      project.MDNSName,
+		"status_page":    project.StatusPage,
+		"status_page_name": project.StatusPageName,
+		"kube_context":   project.KubeContext,
+		"kube_namespace": project.KubeNamespace,
+		"kube_deployment": project.KubeDeployment,
+		"kube_replicas":  project.KubeReplicas,
+		"systemd_unit":   project.SystemdUnit,
+		"systemd_user":   project.SystemdUser,
+		"ssh_host":       project.SSHHost,
+		"connection_string": project.ConnectionString,
+		"migration_command": project.MigrationCommand,
+		"test_command":   project.TestCommand,
+		"queues":         project.Queues,
+		"tail_files":     project.TailFiles,
+		"pprof_url":      project.PprofURL,
+		"ci_repo":        project.CIRepo,
+		"ci_branch":      project.CIBranch,
+		"owner":          project.Owner,
+		"team":           project.Team,
+		"repository_url": project.RepositoryURL,
+		"docs_url":       project.DocsURL,
+		"chat_channel":   project.ChatChannel,
+		"queue_backlog_limit": project.QueueBacklogLimit,
+		"queue_growth_limit":  project.QueueGrowthLimit,
+		"idle_timeout":        project.IdleTimeout,
+		"mock_spec":           project.MockSpec,
 		"max_restarts":   project.MaxRestarts,
+		"restart_strategy": project.RestartStrategy,
+		"drain_timeout":    project.DrainTimeout,
+		"instances":        project.Instances,
+		"instance_ports":   project.InstancePorts,
 		"cpu_limit":      project.CPULimit,
 		"memory_limit":   project.MemoryLimit,
+		"nice":           project.Nice,
+		"io_class":       project.IOClass,
+		"io_priority":    project.IOPriority,
+		"cpu_affinity":   project.CPUAffinity,
+		"memory_guard_mb":      project.MemoryGuardMB,
+		"memory_guard_samples": project.MemoryGuardSamples,
+		"memory_guard_restart": project.MemoryGuardRestart,
+		"smoke_tests":            project.SmokeTests,
+		"smoke_tests_on_start":   project.SmokeTestsOnStart,
+		"smoke_tests_fail_error": project.SmokeTestsFailError,
 	}
 
 	if project.GroupID != nil {
 		config["group_id"] = *project.GroupID
 	}
-
-	if format == "json" {
-		c.Header("Content-Type", "application/json")
-		jsonData, err := json.MarshalIndent(config, "", "  ")
-		if err != nil {
-			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to marshal JSON", err.Error()))
-			return
-		}
-		c.JSON(http.StatusOK, gin.H{"data": string(jsonData)})
-		return
-	}
-
-	// Convert to YAML
-	yamlData, err := yaml.Marshal(config)
-	if err != nil {
-		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to marshal YAML", err.Error()))
-		return
-	}
-
-	c.Header("Content-Type", "application/x-yaml")
-	c.JSON(http.StatusOK, gin.H{"data": string(yamlData)})
+	return config
 }
 
 // UpdateProjectFromConfigRequest represents the request to update project from config
 type UpdateProjectFromConfigRequest struct {
 	Config string `json:"config" binding:"required"`
 	Format string `json:"format"` // yaml or json
+	DryRun bool   `json:"dry_run"` // Only lint the configuration
 }
 
-// UpdateProjectFromConfig updates a project from YAML or JSON config
+// ConfigUpdateResult is a project updated from its configuration, with the
+// warnings of the configuration
+type ConfigUpdateResult struct {
+	Project
+	Warnings []LintIssue `json:"warnings,omitempty"`
+}
+
+// UpdateProjectFromConfig godoc
+// @Summary      Update project from configuration
+// @Description  Update a project from a YAML or JSON configuration string. The configuration is linted first like imports (unknown keys, values of the wrong type, missing paths, invalid env_vars JSON, ports used by other projects); nothing is saved while it has errors (422 with the report), and warnings are returned with the project. dry_run only returns the report.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                             true  "Project ID"
+// @Param        request  body      UpdateProjectFromConfigRequest  true  "Configuration"
+// @Success      200      {object}  types.DataMessageResponse{data=ConfigUpdateResult}  "Updated project, or LintReport with dry_run"
+// @Failure      400      {object}  middleware.ErrorResponse                            "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse                            "Project not found"
+// @Failure      422      {object}  middleware.ErrorResponse{details=LintReport}        "The configuration has errors"
+// @Router       /projects/{id}/config [put]
 func (h *Handler) UpdateProjectFromConfig(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -1306,6 +2114,7 @@ func (h *Handler) UpdateProjectFromConfig(c *gin.Context) {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error()))
 		return
 	}
+	before := project
 
 	// Parse config
 	format := req.Format
@@ -1331,6 +2140,21 @@ func (h *Handler) UpdateProjectFromConfig(c *gin.Context) {
 		}
 	}
 
+	linter := newConfigLinter(h.db)
+	linter.lintProject(project.Name, project.ID, configMap, project.SSHHost)
+	report := linter.result()
+	if req.DryRun {
+		c.JSON(http.StatusOK, types.DataMessageResponse{
+			Message: fmt.Sprintf("%d error(s), %d warning(s)", len(report.Errors), len(report.Warnings)),
+			Data:    report,
+		})
+		return
+	}
+	if !report.Valid {
+		middleware.HandleError(c, middleware.NewError(http.StatusUnprocessableEntity, "Configuration has errors", report))
+		return
+	}
+
 	// Update project fields
 	if name, ok := configMap["name"].(string); ok && name != "" {
 		project.Name = name
@@ -1366,6 +2190,9 @@ func (h *Handler) UpdateProjectFromConfig(c *gin.Context) {
 	if ports, ok := configMap["ports"].(string); ok {
 		project.Ports = ports
 	}
+	if socketPath, ok := configMap["socket_path"].(string); ok {
+		project.SocketPath = socketPath
+	}
 	if env, ok := configMap["environment"].(string); ok {
 		project.Environment = env
 	}
@@ -1387,18 +2214,183 @@ func (h *Handler) UpdateProjectFromConfig(c *gin.Context) {
 	if autoRestart, ok := configMap["auto_restart"].(bool); ok {
 		project.AutoRestart = autoRestart
 	}
+	if autostart, ok := configMap["autostart"].(bool); ok {
+		project.Autostart = autostart
+	}
+	if dependsOn, ok := configMap["depends_on"].(string); ok {
+		project.DependsOn = dependsOn
+	}
+	if waitFor, ok := configMap["wait_for"].(string); ok {
+		project.WaitFor = waitFor
+	}
+	if timeout, ok := configMap["wait_for_timeout"].(float64); ok {
+		project.WaitForTimeout = int(timeout)
+	}
+	if interval, ok := configMap["wait_for_interval"].(float64); ok {
+		project.WaitForInterval = int(interval)
+	}
+	if traceInjection, ok := configMap["trace_injection"].(bool); ok {
+		project.TraceInjection = traceInjection
+	}
+	if watchFiles, ok := configMap["watch_files"].(bool); ok {
+		project.WatchFiles = watchFiles
+	}
+	if optional, ok := configMap["optional"].(bool); ok {
+		project.Optional = optional
+	}
+	if mdnsAnnounce, ok := configMap["mdns"].(bool); ok {
+		project.MDNSAnnounce = mdnsAnnounce
+	}
+	if mdnsName, ok := configMap["mdns_name"].(string); ok {
+		project.MDNSName = mdnsName
+	}
+	if statusPage, ok := configMap["status_page"].(bool); ok {
+		project.StatusPage = statusPage
+	}
+	if statusPageName, ok := configMap["status_page_name"].(string); ok {
+		project.StatusPageName = statusPageName
+	}
+	if kubeContext, ok := configMap["kube_context"].(string); ok {
+		project.KubeContext = kubeContext
+	}
+	if kubeNamespace, ok := configMap["kube_namespace"].(string); ok {
+		project.KubeNamespace = kubeNamespace
+	}
+	if kubeDeployment, ok := configMap["kube_deployment"].(string); ok {
+		project.KubeDeployment = kubeDeployment
+	}
+	if kubeReplicas, ok := configMap["kube_replicas"].(int); ok {
+		project.KubeReplicas = kubeReplicas
+	} else if kubeReplicas, ok := configMap["kube_replicas"].(float64); ok {
+		project.KubeReplicas = int(kubeReplicas)
+	}
+	if systemdUnit, ok := configMap["systemd_unit"].(string); ok {
+		project.SystemdUnit = systemdUnit
+	}
+	if systemdUser, ok := configMap["systemd_user"].(bool); ok {
+		project.SystemdUser = systemdUser
+	}
+	if sshHost, ok := configMap["ssh_host"].(string); ok {
+		project.SSHHost = sshHost
+	}
+	if connString, ok := configMap["connection_string"].(string); ok {
+		project.ConnectionString = connString
+	}
+	if migrationCmd, ok := configMap["migration_command"].(string); ok {
+		project.MigrationCommand = migrationCmd
+	}
+	if testCmd, ok := configMap["test_command"].(string); ok {
+		project.TestCommand = testCmd
+	}
+	if queues, ok := configMap["queues"].(string); ok {
+		project.Queues = queues
+	}
+	if tailFiles, ok := configMap["tail_files"].(string); ok {
+		project.TailFiles = tailFiles
+	}
+	if pprofURL, ok := configMap["pprof_url"].(string); ok {
+		project.PprofURL = pprofURL
+	}
+	if ciRepo, ok := configMap["ci_repo"].(string); ok {
+		project.CIRepo = ciRepo
+	}
+	if ciBranch, ok := configMap["ci_branch"].(string); ok {
+		project.CIBranch = ciBranch
+	}
+	if owner, ok := configMap["owner"].(string); ok {
+		project.Owner = owner
+	}
+	if team, ok := configMap["team"].(string); ok {
+		project.Team = team
+	}
+	if repositoryURL, ok := configMap["repository_url"].(string); ok {
+		project.RepositoryURL = repositoryURL
+	}
+	if docsURL, ok := configMap["docs_url"].(string); ok {
+		project.DocsURL = docsURL
+	}
+	if chatChannel, ok := configMap["chat_channel"].(string); ok {
+		project.ChatChannel = chatChannel
+	}
+	if limit, ok := configMap["queue_backlog_limit"].(int); ok {
+		project.QueueBacklogLimit = int64(limit)
+	} else if limit, ok := configMap["queue_backlog_limit"].(float64); ok {
+		project.QueueBacklogLimit = int64(limit)
+	}
+	if limit, ok := configMap["queue_growth_limit"].(int); ok {
+		project.QueueGrowthLimit = int64(limit)
+	} else if limit, ok := configMap["queue_growth_limit"].(float64); ok {
+		project.QueueGrowthLimit = int64(limit)
+	}
+	if minutes, ok := configMap["idle_timeout"].(int); ok {
+		project.IdleTimeout = minutes
+	} else if minutes, ok := configMap["idle_timeout"].(float64); ok {
+		project.IdleTimeout = int(minutes)
+	}
+	if spec, ok := configMap["mock_spec"].(string); ok {
+		project.MockSpec = spec
+	}
 	if maxRestarts, ok := configMap["max_restarts"].(int); ok {
 		project.MaxRestarts = maxRestarts
 	} else if maxRestarts, ok := configMap["max_restarts"].(float64); ok {
 		project.MaxRestarts = int(maxRestarts)
 	}
+	if strategy, ok := configMap["restart_strategy"].(string); ok {
+		project.RestartStrategy = strategy
+	}
+	if drain, ok := configMap["drain_timeout"].(float64); ok {
+		project.DrainTimeout = int(drain)
+	}
+	if instances, ok := configMap["instances"].(float64); ok {
+		project.Instances = int(instances)
+	}
+	if ports, ok := configMap["instance_ports"].(string); ok {
+		project.InstancePorts = ports
+	}
 	if cpuLimit, ok := configMap["cpu_limit"].(string); ok {
 		project.CPULimit = cpuLimit
 	}
 	if memLimit, ok := configMap["memory_limit"].(string); ok {
 		project.MemoryLimit = memLimit
 	}
-	if groupID, ok := configMap["group_id"].(float64); ok {
+	if nice, ok := configMap["nice"].(float64); ok {
+		project.Nice = int(nice)
+	}
+	if ioClass, ok := configMap["io_class"].(string); ok {
+		project.IOClass = ioClass
+	}
+	if ioPriority, ok := configMap["io_priority"].(float64); ok {
+		project.IOPriority = int(ioPriority)
+	}
+	if cpuAffinity, ok := configMap["cpu_affinity"].(string); ok {
+		project.CPUAffinity = cpuAffinity
+	}
+	if guard, ok := configMap["memory_guard_mb"].(float64); ok {
+		project.MemoryGuardMB = int(guard)
+	}
+	if samples, ok := configMap["memory_guard_samples"].(float64); ok {
+		project.MemoryGuardSamples = int(samples)
+	}
+	if restart, ok := configMap["memory_guard_restart"].(bool); ok {
+		project.MemoryGuardRestart = restart
+	}
+	if smokeTests, ok := configMap["smoke_tests"].(string); ok {
+		project.SmokeTests = smokeTests
+	}
+	if onStart, ok := configMap["smoke_tests_on_start"].(bool); ok {
+		project.SmokeTestsOnStart = onStart
+	}
+	if failError, ok := configMap["smoke_tests_fail_error"].(bool); ok {
+		project.SmokeTestsFailError = failError
+	}
+	if groupName, ok := configMap["group"].(string); ok && groupName != "" {
+		groupID, err := h.groupIDByName(nil, groupName)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Group not found", groupName))
+			return
+		}
+		project.GroupID = &groupID
+	} else if groupID, ok := configMap["group_id"].(float64); ok {
 		gid := uint(groupID)
 		project.GroupID = &gid
 	} else if groupID, ok := configMap["group_id"].(int); ok {
@@ -1407,14 +2399,19 @@ func (h *Handler) UpdateProjectFromConfig(c *gin.Context) {
 	}
 
 	// Save project
+	project.Version++
 	if err := h.db.Save(&project).Error; err != nil {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to update project", err.Error()))
 		return
 	}
+	h.events.Publish(project.ID, "project_updated", project)
+	NewAnnotator(h.db, h.manager, h.events).ConfigChanged(&before, &project)
+	// A running project scales out or in right away
+	go h.manager.ScaleInstances(project.ID)
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Project updated successfully",
-		"data":    project,
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Message: "Project updated successfully",
+		Data:    ConfigUpdateResult{Project: project, Warnings: report.Warnings},
 	})
 }
 
@@ -1433,7 +2430,16 @@ type ServiceDetection struct {
 	PackageFile string `json:"package_file"`
 }
 
-// DetectServices detects services in a project path
+// DetectServices godoc
+// @Summary      Detect services
+// @Description  Scan a directory for Node.js, Go and Python services
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        request  body      DetectServicesRequest  true  "Path to scan"
+// @Success      200      {object}  types.DataResponse{data=[]ServiceDetection}  "Detected services"
+// @Failure      400      {object}  middleware.ErrorResponse                     "Bad request"
+// @Router       /projects/detect-services [post]
 func (h *Handler) DetectServices(c *gin.Context) {
 	var req DetectServicesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -1447,6 +2453,18 @@ func (h *Handler) DetectServices(c *gin.Context) {
 		return
 	}
 
+	services, err := detectServices(req.Path)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to scan path", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: services})
+}
+
+// detectServices scans a directory for Node.js, Go and Python services, up to
+// three levels deep. A directory without any yields one "other" service.
+func detectServices(root string) ([]ServiceDetection, error) {
 	services := []ServiceDetection{}
 
 	// Track visited directories to avoid duplicates
@@ -1481,13 +2499,13 @@ func (h *Handler) DetectServices(c *gin.Context) {
 	maxDepth := 3
 
 	// Scan for common service types
-	err := filepath.Walk(req.Path, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // Skip errors
 		}
 
 		// Calculate relative depth from base path
-		relPath, relErr := filepath.Rel(req.Path, path)
+		relPath, relErr := filepath.Rel(root, path)
 		if relErr != nil {
 			return nil // Skip if we can't calculate relative path
 		}
@@ -1538,7 +2556,7 @@ func (h *Handler) DetectServices(c *gin.Context) {
 			}
 			
 			// Skip if this package.json is inside a node_modules or other skipped directory
-			dirRelPath, err := filepath.Rel(req.Path, dir)
+			dirRelPath, err := filepath.Rel(root, dir)
 			if err == nil {
 				pathParts := strings.Split(dirRelPath, string(filepath.Separator))
 				for _, part := range pathParts {
@@ -1685,7 +2703,7 @@ func (h *Handler) DetectServices(c *gin.Context) {
 			}
 			
 			// Skip if this go.mod is inside a skipped directory
-			relPath, err := filepath.Rel(req.Path, dir)
+			relPath, err := filepath.Rel(root, dir)
 			if err == nil {
 				pathParts := strings.Split(relPath, string(filepath.Separator))
 				for _, part := range pathParts {
@@ -1783,7 +2801,7 @@ func (h *Handler) DetectServices(c *gin.Context) {
 			}
 			
 			// Skip if this requirements.txt is inside a skipped directory
-			relPath, err := filepath.Rel(req.Path, dir)
+			relPath, err := filepath.Rel(root, dir)
 			if err == nil {
 				pathParts := strings.Split(relPath, string(filepath.Separator))
 				for _, part := range pathParts {
@@ -1841,24 +2859,21 @@ func (h *Handler) DetectServices(c *gin.Context) {
 	})
 
 	if err != nil {
-		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to scan path", err.Error()))
-		return
+		return nil, err
 	}
 
 	// If no services found, create a default one for the root path
 	if len(services) == 0 {
 		services = append(services, ServiceDetection{
-			Name:    filepath.Base(req.Path),
+			Name:    filepath.Base(root),
 			Type:    "other",
-			Path:    req.Path,
+			Path:    root,
 			Command: "",
 			Port:    3000, // Default port for unknown services
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": services,
-	})
+	return services, nil
 }
 
 // Helper functions for port detection
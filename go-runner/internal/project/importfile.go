@@ -0,0 +1,260 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-runner/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// maxIncludeDepth bounds include chains
+const maxIncludeDepth = 10
+
+// readImportFile decodes the import file of a request: a file on the server
+// (?path=), whose path is returned for its includes, an uploaded file
+// (multipart field "file") or the JSON body
+func readImportFile(c *gin.Context) (interface{}, string, error) {
+	var data interface{}
+
+	if path := c.Query("path"); path != "" {
+		// Read a file on the server, whose includes can be relative
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, "", middleware.NewError(http.StatusBadRequest, "Invalid path", err.Error())
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", middleware.NewError(http.StatusBadRequest, "Failed to read file", err.Error())
+		}
+		if data, err = decodeImportFile(path, content); err != nil {
+			return nil, "", middleware.NewError(http.StatusBadRequest, "Failed to parse file", err.Error())
+		}
+		return data, path, nil
+	}
+
+	// Check if it's a file upload
+	if file, err := c.FormFile("file"); err == nil {
+		src, err := file.Open()
+		if err != nil {
+			return nil, "", middleware.NewError(http.StatusBadRequest, "Failed to open file", err.Error())
+		}
+		defer src.Close()
+
+		content, err := io.ReadAll(src)
+		if err != nil {
+			return nil, "", middleware.NewError(http.StatusBadRequest, "Failed to read file", err.Error())
+		}
+		if data, err = decodeImportFile(file.Filename, content); err != nil {
+			return nil, "", middleware.NewError(http.StatusBadRequest, "Failed to parse file", err.Error())
+		}
+		return data, "", nil
+	}
+
+	if err := c.ShouldBindJSON(&data); err != nil {
+		return nil, "", middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error())
+	}
+	return data, "", nil
+}
+
+// isYAMLFile reports whether an import file is YAML rather than JSON
+func isYAMLFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// decodeImportFile decodes an import file by its extension, YAML anchors and
+// merge keys (<<: *defaults) resolved
+func decodeImportFile(name string, content []byte) (interface{}, error) {
+	var data interface{}
+	if isYAMLFile(name) {
+		if err := yaml.Unmarshal(content, &data); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		return data, nil
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return data, nil
+}
+
+// expandImport resolves the include directive of an import file read from
+// path, "" for uploaded files and bodies, whose includes must be absolute.
+// Included groups and projects come before those of the including file, and
+// a project or group defined twice keeps its last definition. Top-level x-
+// keys, which only hold YAML anchors, are dropped, and env_vars written as
+// a mapping are turned into JSON.
+func (l *configLinter) expandImport(data interface{}, path string) interface{} {
+	var stack []string
+	dir := ""
+	if path != "" {
+		stack = []string{path}
+		dir = filepath.Dir(path)
+	}
+	data = l.expandIncludes(data, dir, stack)
+
+	root, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+	for _, key := range []string{"groups", "projects"} {
+		items, ok := root[key].([]interface{})
+		if !ok {
+			continue
+		}
+		items = l.dedupe(key, items)
+		if key == "projects" {
+			for _, item := range items {
+				if project, ok := item.(map[string]interface{}); ok {
+					if env, ok := project["env_vars"].(map[string]interface{}); ok {
+						encoded, _ := json.Marshal(env)
+						project["env_vars"] = string(encoded)
+					}
+				}
+			}
+		}
+		root[key] = items
+	}
+	return root
+}
+
+// expandIncludes merges the files listed under include, relative to dir,
+// into an import file. stack holds the files being included, to refuse
+// cycles.
+func (l *configLinter) expandIncludes(data interface{}, dir string, stack []string) interface{} {
+	root, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	merged := make(map[string]interface{}, len(root))
+	for key, value := range root {
+		if key != "include" && !strings.HasPrefix(key, "x-") {
+			merged[key] = value
+		}
+	}
+	patterns, ok := includePatterns(root["include"])
+	if !ok {
+		l.add(LintError, "", "include", "must be a file or a list of files, got %s", kindOf(root["include"]))
+		return merged
+	}
+
+	included := map[string][]interface{}{}
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			if dir == "" {
+				l.add(LintError, "", "include", "%s: relative includes need an import file on the server (?path=), use an absolute path", pattern)
+				continue
+			}
+			pattern = filepath.Join(dir, pattern)
+		}
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			l.add(LintError, "", "include", "%s: %v", pattern, err)
+			continue
+		}
+		if len(files) == 0 {
+			l.add(LintError, "", "include", "%s matches no file", pattern)
+			continue
+		}
+		for _, file := range files {
+			if containsString(stack, file) {
+				l.add(LintError, "", "include", "%s includes itself", file)
+				continue
+			}
+			if len(stack) >= maxIncludeDepth {
+				l.add(LintError, "", "include", "%s: includes nested more than %d deep", file, maxIncludeDepth)
+				continue
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				l.add(LintError, "", "include", "%v", err)
+				continue
+			}
+			data, err := decodeImportFile(file, content)
+			if err != nil {
+				l.add(LintError, "", "include", "%s: %v", file, err)
+				continue
+			}
+			child, ok := l.expandIncludes(data, filepath.Dir(file), append(stack, file)).(map[string]interface{})
+			if !ok {
+				l.add(LintError, "", "include", "%s: expected an object with projects and groups, got %s", file, kindOf(data))
+				continue
+			}
+			for _, key := range []string{"groups", "projects"} {
+				if child[key] == nil {
+					continue
+				}
+				items, ok := child[key].([]interface{})
+				if !ok {
+					l.add(LintError, "", "include", "%s: %s must be a list, got %s", file, key, kindOf(child[key]))
+					continue
+				}
+				included[key] = append(included[key], items...)
+			}
+		}
+	}
+
+	for key, items := range included {
+		own, ok := merged[key].([]interface{})
+		if merged[key] != nil && !ok {
+			// Left for lintImport to report
+			continue
+		}
+		merged[key] = append(items, own...)
+	}
+	return merged
+}
+
+// includePatterns returns the files of an include directive, a file or a
+// list of files, which may be glob patterns
+func includePatterns(raw interface{}) ([]string, bool) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, true
+	case string:
+		return []string{v}, true
+	case []interface{}:
+		patterns := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok || s == "" {
+				return nil, false
+			}
+			patterns = append(patterns, s)
+		}
+		return patterns, true
+	}
+	return nil, false
+}
+
+// dedupe keeps the last definition of each name, in the position of the
+// first
+func (l *configLinter) dedupe(key string, items []interface{}) []interface{} {
+	index := make(map[string]int)
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		object, _ := item.(map[string]interface{})
+		name, _ := object["name"].(string)
+		if name == "" {
+			result = append(result, item)
+			continue
+		}
+		if i, seen := index[name]; seen {
+			l.add(LintWarning, name, "", "defined more than once in %s, the last definition is used", key)
+			result[i] = item
+			continue
+		}
+		index[name] = len(result)
+		result = append(result, item)
+	}
+	return result
+}
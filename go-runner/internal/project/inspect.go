@@ -0,0 +1,98 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InspectRequest selects the inspector port
+type InspectRequest struct {
+	Port int `json:"port" binding:"omitempty,min=1024,max=65535"` // Free port from 9229 when empty
+}
+
+// EnableInspector godoc
+// @Summary      Debug a Node.js project
+// @Description  Restart a Node.js project with the inspector listening on 127.0.0.1 (--inspect for node, nodemon and tsx commands, NODE_OPTIONS for others such as npm scripts, where the first Node.js process started takes the port) and return the DevTools URL. The command is not changed: the inspector stays on across restarts until DELETE /projects/{id}/inspect or a manual stop, and is included as "inspector" in the project status.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true   "Project ID"
+// @Param        request  body      InspectRequest  false  "Inspector port"
+// @Success      200      {object}  types.DataResponse{data=service.NodeInspector}  "Restarted with the inspector; listening is false when it did not answer in time"
+// @Failure      400      {object}  middleware.ErrorResponse  "Not a local Node.js project"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Port in use or another operation in progress"
+// @Router       /projects/{id}/inspect [post]
+func (h *Handler) EnableInspector(c *gin.Context) {
+	var req InspectRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+			return
+		}
+	}
+
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	inspector, err := h.manager.EnableInspector(project.ID, req.Port)
+	if err != nil {
+		if h.handleOperationConflict(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrNotNodeProject), errors.Is(err, service.ErrInspectorUnsupported):
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, err.Error(), nil))
+		case errors.Is(err, service.ErrInspectorPortInUse):
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, err.Error(), req.Port))
+		default:
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to restart with the inspector", err.Error()))
+		}
+		return
+	}
+
+	h.events.Publish(project.ID, "status_update", gin.H{
+		"project_id": project.ID,
+		"status":     "restarting",
+		"message":    "Project restarted with the inspector",
+		"inspector":  inspector,
+	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: inspector})
+}
+
+// DisableInspector godoc
+// @Summary      Stop debugging a Node.js project
+// @Description  Turn the inspector off and restart the project without it when it is running
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.MessageResponse     "Inspector off"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409  {object}  middleware.ErrorResponse  "Another operation in progress"
+// @Router       /projects/{id}/inspect [delete]
+func (h *Handler) DisableInspector(c *gin.Context) {
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if err := h.manager.DisableInspector(project.ID); err != nil {
+		if h.handleOperationConflict(c, err) {
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to restart without the inspector", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Inspector off"})
+}
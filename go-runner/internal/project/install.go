@@ -0,0 +1,363 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// installWaitDelay is how long a cancelled install may take to release its output
+const installWaitDelay = 5 * time.Second
+
+// InstallPackagesRequest represents the request to install packages
+type InstallPackagesRequest struct {
+	PackageManager string   `json:"package_manager" binding:"required,oneof=npm yarn pnpm go pip"`
+	Packages       []string `json:"packages"`
+}
+
+// InstallLogLine is an output line of an install job, broadcast as "install_log"
+type InstallLogLine struct {
+	JobID  uint   `json:"job_id"`
+	Stream string `json:"stream"` // stdout or stderr
+	Line   string `json:"line"`
+}
+
+// InstallResult is the result of a finished install job
+type InstallResult struct {
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// installCommand returns the command installing the given packages, or all
+// dependencies when none are given
+func installCommand(packageManager string, packages []string) []string {
+	switch packageManager {
+	case "npm":
+		return append([]string{"npm", "install"}, packages...)
+	case "yarn":
+		if len(packages) > 0 {
+			return append([]string{"yarn", "add"}, packages...)
+		}
+		return []string{"yarn", "install"}
+	case "pnpm":
+		if len(packages) > 0 {
+			return append([]string{"pnpm", "add"}, packages...)
+		}
+		return []string{"pnpm", "install"}
+	case "go":
+		if len(packages) > 0 {
+			return append([]string{"go", "get"}, packages...)
+		}
+		return []string{"go", "mod", "download"}
+	case "pip":
+		if len(packages) > 0 {
+			return append([]string{"pip", "install"}, packages...)
+		}
+		return []string{"pip", "install", "-r", "requirements.txt"}
+	}
+	return nil
+}
+
+// InstallPackages godoc
+// @Summary      Install packages
+// @Description  Start installing dependencies or specific packages in the project directory as a background job and return the job immediately. Output is streamed to the project WebSocket as "install_log" messages (InstallLogLine) and job progress as "job_update". Only one install runs per project at a time; poll GET /jobs/{id} or cancel with POST /jobs/{id}/cancel.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                     true  "Project ID"
+// @Param        request  body      InstallPackagesRequest  true  "Package manager and packages"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Install job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "An install is already running"
+// @Failure      500      {object}  middleware.ErrorResponse  "Package manager not found"
+// @Router       /projects/{id}/install [post]
+func (h *Handler) InstallPackages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var req InstallPackagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	// Get project from database
+	var project Project
+	if err := h.db.First(&project, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			middleware.HandleError(c, middleware.ErrNotFound)
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error()))
+		return
+	}
+
+	// Determine working directory
+	workingDir := project.Path
+	if project.WorkingDir != "" {
+		workingDir = project.WorkingDir
+	}
+
+	h.submitInstall(c, project.ID, workingDir, installCommand(req.PackageManager, req.Packages), nil)
+}
+
+// submitInstall queues a package manager command as an install job and
+// responds with the job. after, when set, runs once the command succeeded.
+func (h *Handler) submitInstall(c *gin.Context, projectID uint, workingDir string, args []string, after func() error) {
+	if _, err := exec.LookPath(args[0]); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to start install", err.Error()))
+		return
+	}
+
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:      jobs.TypeInstall,
+		ProjectID: &projectID,
+		Key:       fmt.Sprintf("install:%d", projectID),
+		Message:   strings.Join(args, " "),
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		result, err := h.runInstall(ctx, run, projectID, workingDir, args)
+		if err == nil && after != nil {
+			err = after()
+		}
+		return result, err
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "An install is already running for this project", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// runInstall runs an install command, streaming its output to the job and the
+// project WebSocket
+func (h *Handler) runInstall(ctx context.Context, run *jobs.Run, projectID uint, workingDir string, args []string) (*InstallResult, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = workingDir
+	return h.streamCommand(run, projectID, "install_log", cmd)
+}
+
+// streamCommand runs a command, streaming each output line to the job and to
+// the project WebSocket as logType messages
+func (h *Handler) streamCommand(run *jobs.Run, projectID uint, logType string, cmd *exec.Cmd) (*InstallResult, error) {
+	return runCommandLines(cmd, func(stream, line string) {
+		run.Log(line)
+		h.hub.BroadcastToProject(projectID, logType, InstallLogLine{JobID: run.ID(), Stream: stream, Line: line})
+	})
+}
+
+// runCommandLines runs a command, calling onLine with each stdout and stderr
+// line. Calls are serialized.
+func runCommandLines(cmd *exec.Cmd, onLine func(stream, line string)) (*InstallResult, error) {
+	cmd.WaitDelay = installWaitDelay
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	stream := func(name string, r io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			mu.Lock()
+			onLine(name, scanner.Text())
+			mu.Unlock()
+		}
+	}
+
+	wg.Add(2)
+	go stream("stdout", stdout)
+	go stream("stderr", stderr)
+	wg.Wait()
+	err = cmd.Wait()
+
+	result := &InstallResult{Command: strings.Join(cmd.Args, " "), ExitCode: cmd.ProcessState.ExitCode()}
+	return result, err
+}
+
+// GetInstallJobs godoc
+// @Summary      List install jobs
+// @Description  Get the package install history of a project, newest first, without output. Use GET /jobs/{id} for the output of a job.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=[]jobs.Job}
+// @Failure      400  {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/install/jobs [get]
+func (h *Handler) GetInstallJobs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			middleware.HandleError(c, middleware.ErrNotFound)
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error()))
+		return
+	}
+
+	list, err := h.jobs.List(jobs.ListFilter{Type: jobs.TypeInstall, ProjectID: &project.ID})
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch install jobs", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: list})
+}
+
+// UpgradeDependency godoc
+// @Summary      Upgrade a dependency
+// @Description  Upgrade one direct dependency to the given or latest version as an install job (npm/yarn/pnpm add, go get, pip install). Pinned requirements.txt entries are updated after pip succeeds.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                       true  "Project ID"
+// @Param        request  body      UpgradeDependencyRequest  true  "Package and version"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Install job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project or dependency not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "An install is already running"
+// @Failure      502      {object}  middleware.ErrorResponse  "Latest version lookup failed"
+// @Router       /projects/{id}/dependencies/upgrade [post]
+func (h *Handler) UpgradeDependency(c *gin.Context) {
+	var req UpgradeDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	project, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	report, err := readDependencies(dir, "")
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	var dep *Dependency
+	for i := range report.Dependencies {
+		if report.Dependencies[i].Name == req.Package {
+			dep = &report.Dependencies[i]
+			break
+		}
+	}
+	if dep == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Not a direct dependency", req.Package))
+		return
+	}
+
+	version := strings.TrimSpace(req.Version)
+	if version == "" {
+		if version, err = h.registry.latest(c.Request.Context(), report.Ecosystem, dep.Name, true); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadGateway, "Failed to look up the latest version", err.Error()))
+			return
+		}
+	}
+
+	var args []string
+	var after func() error
+	switch report.Ecosystem {
+	case "npm":
+		args = npmAddCommand(dir, dep.Section == "devDependencies", dep.Name+"@"+version)
+	case "go":
+		args = []string{"go", "get", dep.Name + "@" + version}
+	case "pip":
+		args = []string{"pip", "install", dep.Name + "==" + version}
+		after = func() error { return pinRequirement(filepath.Join(dir, "requirements.txt"), dep.Name, version) }
+	}
+
+	h.submitInstall(c, project.ID, dir, args, after)
+}
+
+// npmAddCommand returns the command adding a package with the package manager
+// whose lockfile is present
+func npmAddCommand(dir string, dev bool, spec string) []string {
+	args := []string{"npm", "install"}
+	if pm := nodePackageManager(dir); pm != "npm" {
+		args = []string{pm, "add"}
+	}
+	if dev {
+		args = append(args, "-D")
+	}
+	return append(args, spec)
+}
+
+// pinRequirement updates the pinned version of a package in requirements.txt,
+// leaving unpinned entries alone
+func pinRequirement(path, name, version string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil // Nothing to update
+	}
+
+	lines := strings.Split(string(data), "\n")
+	changed := false
+	for i, line := range lines {
+		m := requirementPattern.FindStringSubmatchIndex(strings.TrimSpace(line))
+		if m == nil || m[4] < 0 {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if !strings.EqualFold(trimmed[m[2]:m[3]], name) || trimmed[m[4]:m[5]] != "==" {
+			continue
+		}
+		lines[i] = trimmed[:m[6]] + version + trimmed[m[7]:]
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode())
+}
@@ -0,0 +1,103 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultInstanceLogLines is how many lines of an instance output are returned
+const defaultInstanceLogLines = 200
+
+// InstanceLogsResponse is the output of an extra instance
+type InstanceLogsResponse struct {
+	Index int      `json:"index"`
+	Logs  []string `json:"logs"`
+	Count int      `json:"count"`
+}
+
+// GetInstances godoc
+// @Summary      Get the instances of a project
+// @Description  List the copies of the service of a project run side by side (instances), each with its own status, PID, port and restarts. Index 0 is the main process, the one start, stop and logs act on; the extra instances run the same command with PORT set to the port of the project plus their index (instance_ports offset) or to a free port (auto), and INSTANCE_INDEX to their index. They start and stop with the main process, the proxy balances requests over all of them, and changing instances scales a running project right away.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=[]service.ServiceInstance}  "Instances"
+// @Failure      400  {object}  middleware.ErrorResponse                            "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse                            "Project not found"
+// @Router       /projects/{id}/instances [get]
+func (h *Handler) GetInstances(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	instances, err := h.manager.Instances(uint(id))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: instances})
+}
+
+// GetInstanceLogs godoc
+// @Summary      Get the logs of an instance
+// @Description  Read the last lines of the output of an extra instance of a project, stdout and stderr combined, from its file in the log directory. The output of the main process (index 0) is at /projects/{id}/logs.
+// @Tags         logs
+// @Produce      json
+// @Param        id     path      int  true   "Project ID"
+// @Param        index  path      int  true   "Instance index, from 1"
+// @Param        lines  query     int  false  "Number of lines (default 200, max 10000)"
+// @Success      200    {object}  types.DataResponse{data=InstanceLogsResponse}  "Instance output"
+// @Failure      400    {object}  middleware.ErrorResponse                       "Bad request"
+// @Failure      404    {object}  middleware.ErrorResponse                       "Instance not found"
+// @Router       /projects/{id}/instances/{index}/logs [get]
+func (h *Handler) GetInstanceLogs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid instance index", nil))
+		return
+	}
+	if index == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "The logs of the main process are at /projects/:id/logs", nil))
+		return
+	}
+	lines := defaultInstanceLogLines
+	if v := c.Query("lines"); v != "" {
+		if lines, err = strconv.Atoi(v); err != nil || lines < 1 || lines > 10000 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid lines value", nil))
+			return
+		}
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	logs, err := h.manager.InstanceLogs(project.ID, index, lines)
+	switch {
+	case errors.Is(err, service.ErrInstanceNotFound):
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Instance not found", nil))
+		return
+	case err != nil:
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to read instance logs", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: InstanceLogsResponse{Index: index, Logs: logs, Count: len(logs)}})
+}
@@ -0,0 +1,78 @@
+package project
+
+import (
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+	"go-runner/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalsReport shows where go-runner spends its time delivering logs and
+// running services
+type InternalsReport struct {
+	Hub        websocket.HubStats         `json:"hub"`
+	LogStreams []service.LogChannelStats  `json:"log_streams"`
+	Operations []service.OperationLatency `json:"operations"`
+	Database   service.DBWriteStats       `json:"database"`
+	Goroutines int                        `json:"goroutines"`
+	CheckedAt  time.Time                  `json:"checked_at"`
+}
+
+// GetInternals godoc
+// @Summary      Report go-runner internals
+// @Description  Report WebSocket clients per project with their queued messages, the log channel backlog and dropped lines of each running service, recent start/stop/restart latencies and database write rates. A growing log channel backlog points at go-runner's consumers, a growing client queue at the browser, and neither at the service itself.
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=InternalsReport}
+// @Router       /system/internals [get]
+func (h *Handler) GetInternals(c *gin.Context) {
+	c.JSON(http.StatusOK, types.DataResponse{Data: InternalsReport{
+		Hub:        h.hub.Stats(),
+		LogStreams: h.manager.LogChannelStats(),
+		Operations: h.manager.OperationLatencies(),
+		Database:   h.manager.DatabaseWrites(),
+		Goroutines: runtime.NumGoroutine(),
+		CheckedAt:  time.Now(),
+	}})
+}
+
+// GetWebSocketClients godoc
+// @Summary      List WebSocket clients
+// @Description  List the clients connected to project WebSocket streams, oldest first, with the project each one is subscribed to, its remote IP, how long it has been connected, when it last answered, its queued messages and its log filter and display settings; for debugging why someone does not see the logs of a project
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]websocket.ClientInfo}
+// @Router       /ws/clients [get]
+func (h *Handler) GetWebSocketClients(c *gin.Context) {
+	c.JSON(http.StatusOK, types.DataResponse{Data: h.hub.Clients()})
+}
+
+// DisconnectWebSocketClient godoc
+// @Summary      Disconnect a WebSocket client
+// @Description  Close the connection of a WebSocket client; browsers of the dashboard reconnect on their own, with a new ID
+// @Tags         system
+// @Produce      json
+// @Param        client_id  path      int  true  "Client ID"
+// @Success      200        {object}  types.MessageResponse
+// @Failure      400        {object}  middleware.ErrorResponse  "Invalid client ID"
+// @Failure      404        {object}  middleware.ErrorResponse  "Client not connected"
+// @Router       /ws/clients/{client_id} [delete]
+func (h *Handler) DisconnectWebSocketClient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("client_id"), 10, 64)
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+	if !h.hub.Disconnect(id) {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Client not connected", id))
+		return
+	}
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Client disconnected"})
+}
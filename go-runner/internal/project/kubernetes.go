@@ -0,0 +1,157 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportKubeRequest imports deployments of a namespace as projects
+type ImportKubeRequest struct {
+	Context     string      `json:"context"` // kubeconfig context, the current one when empty
+	Namespace   string      `json:"namespace" binding:"required"`
+	Deployments []string    `json:"deployments" binding:"required,min=1,dive,required"`
+	GroupID     *uint       `json:"group_id"`
+	Type        ServiceType `json:"type"` // Project type, backend when empty
+}
+
+// ImportKubeResult lists the projects created by a Kubernetes import
+type ImportKubeResult struct {
+	Created []Project `json:"created"`
+	Skipped []string  `json:"skipped"` // Deployments already imported
+}
+
+// kubeError maps Kubernetes errors to responses
+func kubeError(err error) *middleware.CustomError {
+	if errors.Is(err, service.ErrKubernetesDisabled) {
+		return middleware.NewError(http.StatusServiceUnavailable, "Kubernetes mode is disabled", err.Error())
+	}
+	return middleware.NewError(http.StatusBadGateway, "kubectl failed", err.Error())
+}
+
+// GetKubeDeployments godoc
+// @Summary      List Kubernetes deployments
+// @Description  List the deployments of a namespace (all namespaces when empty) through kubectl, to pick the ones to import. Requires kubernetes.enabled.
+// @Tags         kubernetes
+// @Produce      json
+// @Param        context    query     string  false  "kubeconfig context, the current one when empty"
+// @Param        namespace  query     string  false  "Namespace, all when empty"
+// @Success      200        {object}  types.DataResponse{data=[]service.KubeDeploymentSummary}
+// @Failure      502        {object}  middleware.ErrorResponse  "kubectl failed"
+// @Failure      503        {object}  middleware.ErrorResponse  "Kubernetes mode is disabled"
+// @Router       /kubernetes/deployments [get]
+func (h *Handler) GetKubeDeployments(c *gin.Context) {
+	deployments, err := h.manager.ListKubeDeployments(c.Request.Context(), c.Query("context"), c.Query("namespace"))
+	if err != nil {
+		middleware.HandleError(c, kubeError(err))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: deployments})
+}
+
+// ImportKubeDeployments godoc
+// @Summary      Import Kubernetes deployments
+// @Description  Create a project per deployment, with kube_context, kube_namespace and kube_deployment set and its current replica count as kube_replicas. Start and stop then scale the deployment, status follows pod readiness and logs stream from the pods. Deployments already imported are skipped.
+// @Tags         kubernetes
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ImportKubeRequest  true  "Deployments to import"
+// @Success      201      {object}  types.DataResponse{data=ImportKubeResult}
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      502      {object}  middleware.ErrorResponse  "kubectl failed"
+// @Failure      503      {object}  middleware.ErrorResponse  "Kubernetes mode is disabled"
+// @Router       /kubernetes/import [post]
+func (h *Handler) ImportKubeDeployments(c *gin.Context) {
+	var req ImportKubeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if req.Type == "" {
+		req.Type = TypeBackend
+	}
+
+	available, err := h.manager.ListKubeDeployments(c.Request.Context(), req.Context, req.Namespace)
+	if err != nil {
+		middleware.HandleError(c, kubeError(err))
+		return
+	}
+	replicas := make(map[string]int, len(available))
+	for _, d := range available {
+		replicas[d.Name] = d.Replicas
+	}
+
+	result := ImportKubeResult{Created: []Project{}, Skipped: []string{}}
+	for _, name := range req.Deployments {
+		current, ok := replicas[name]
+		if !ok {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Deployment not found in namespace "+req.Namespace, name))
+			return
+		}
+
+		var existing int64
+		h.db.Model(&Project{}).Where("kube_context = ? AND kube_namespace = ? AND kube_deployment = ?", req.Context, req.Namespace, name).Count(&existing)
+		if existing > 0 {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+
+		if current <= 0 {
+			current = 1
+		}
+		project := Project{
+			Name:           name,
+			Description:    "Kubernetes deployment " + req.Namespace + "/" + name,
+			Type:           req.Type,
+			GroupID:        req.GroupID,
+			Path:           "kubernetes://" + req.Namespace + "/" + name,
+			Status:         StatusUnknown,
+			KubeContext:    req.Context,
+			KubeNamespace:  req.Namespace,
+			KubeDeployment: name,
+			KubeReplicas:   current,
+		}
+		if err := h.db.Create(&project).Error; err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to create project", err.Error()))
+			return
+		}
+		result.Created = append(result.Created, project)
+	}
+
+	c.JSON(http.StatusCreated, types.DataResponse{Data: result})
+}
+
+// GetProjectKubernetes godoc
+// @Summary      Get the deployment of a Kubernetes project
+// @Description  Read the deployment and pods of a project with a kube_deployment now and sync its status and health with pod readiness. The last observation is also included as "kubernetes" in the project status.
+// @Tags         kubernetes
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.KubeDeployment}
+// @Failure      400  {object}  middleware.ErrorResponse  "Not a Kubernetes project"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      503  {object}  middleware.ErrorResponse  "Kubernetes mode is disabled"
+// @Router       /projects/{id}/kubernetes [get]
+func (h *Handler) GetProjectKubernetes(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id, kube_deployment").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	if project.KubeDeployment == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project has no Kubernetes deployment", nil))
+		return
+	}
+
+	deployment, err := h.manager.CheckKube(c.Request.Context(), project.ID)
+	if err != nil {
+		middleware.HandleError(c, kubeError(err))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: deployment})
+}
@@ -0,0 +1,404 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Severities of configuration issues
+const (
+	LintError   = "error"   // The configuration is refused
+	LintWarning = "warning" // Saved, but probably not what was meant
+)
+
+// LintIssue is a problem found in an imported or edited configuration
+type LintIssue struct {
+	Severity string `json:"severity"`          // error, warning
+	Project  string `json:"project,omitempty"` // Project or group name, or its position in the file
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LintReport lists the problems found in a configuration. Nothing is saved
+// while it has errors.
+type LintReport struct {
+	Valid    bool        `json:"valid"`
+	Errors   []LintIssue `json:"errors"`
+	Warnings []LintIssue `json:"warnings"`
+}
+
+// configField describes a key of a configuration
+type configField struct {
+	kind  reflect.Kind
+	oneOf []string
+	min   int  // Of numbers, 0 unless the validate tag allows less
+	max   *int // Of numbers
+	zero  bool // 0 is allowed whatever the range (omitempty)
+}
+
+var (
+	projectConfigFields = configFields(reflect.TypeOf(CreateProjectRequest{}))
+	groupConfigFields   = configFields(reflect.TypeOf(CreateProjectGroupRequest{}))
+)
+
+// configFields returns the keys of a request struct with their kind and
+// allowed values, from its json and validate tags
+func configFields(t reflect.Type) map[string]configField {
+	fields := make(map[string]configField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		typ := f.Type
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		field := configField{kind: typ.Kind()}
+		for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+			if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+				field.oneOf = strings.Fields(values)
+			}
+			if field.kind == reflect.String {
+				continue
+			}
+			if rule == "omitempty" {
+				field.zero = true
+			}
+			if value, ok := strings.CutPrefix(rule, "min="); ok {
+				field.min, _ = strconv.Atoi(value)
+			}
+			if value, ok := strings.CutPrefix(rule, "max="); ok {
+				if n, err := strconv.Atoi(value); err == nil {
+					field.max = &n
+				}
+			}
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// configLinter checks configurations against the existing projects
+type configLinter struct {
+	db     *gorm.DB
+	report LintReport
+	ports  map[int]string // Ports taken by the projects checked so far
+}
+
+func newConfigLinter(db *gorm.DB) *configLinter {
+	return &configLinter{
+		db:     db,
+		report: LintReport{Errors: []LintIssue{}, Warnings: []LintIssue{}},
+		ports:  make(map[int]string),
+	}
+}
+
+func (l *configLinter) add(severity, project, field, format string, args ...interface{}) {
+	issue := LintIssue{Severity: severity, Project: project, Field: field, Message: fmt.Sprintf(format, args...)}
+	if severity == LintError {
+		l.report.Errors = append(l.report.Errors, issue)
+	} else {
+		l.report.Warnings = append(l.report.Warnings, issue)
+	}
+}
+
+// result returns the report of everything checked
+func (l *configLinter) result() LintReport {
+	l.report.Valid = len(l.report.Errors) == 0
+	return l.report
+}
+
+// lintImport checks an import file, decoded from JSON or YAML
+func (l *configLinter) lintImport(data interface{}) {
+	root, ok := data.(map[string]interface{})
+	if !ok {
+		l.add(LintError, "", "", "expected an object with projects and groups, got %s", kindOf(data))
+		return
+	}
+	for _, key := range sortedKeys(root) {
+		if key != "projects" && key != "groups" {
+			l.add(LintWarning, "", key, "unknown key, ignored (expected projects and groups)")
+		}
+	}
+
+	groups, ok := l.list(root, "groups")
+	if ok {
+		for i, raw := range groups {
+			label := fmt.Sprintf("groups[%d]", i)
+			group, ok := raw.(map[string]interface{})
+			if !ok {
+				l.add(LintError, label, "", "expected an object, got %s", kindOf(raw))
+				continue
+			}
+			if name, ok := group["name"].(string); ok && strings.TrimSpace(name) != "" {
+				label = name
+			} else {
+				l.add(LintError, label, "name", "required")
+			}
+			l.lintFields(label, group, groupConfigFields)
+		}
+	}
+
+	projects, ok := l.list(root, "projects")
+	if !ok {
+		return
+	}
+	for i, raw := range projects {
+		label := fmt.Sprintf("projects[%d]", i)
+		project, ok := raw.(map[string]interface{})
+		if !ok {
+			l.add(LintError, label, "", "expected an object, got %s", kindOf(raw))
+			continue
+		}
+		var id uint
+		if name, ok := project["name"].(string); ok && strings.TrimSpace(name) != "" {
+			label = name
+			// Imports update the project with the same name
+			var existing Project
+			if err := l.db.Select("id").Where("name = ?", name).Take(&existing).Error; err == nil {
+				id = existing.ID
+			}
+		} else {
+			l.add(LintError, label, "name", "required")
+		}
+		if path, _ := project["path"].(string); path == "" {
+			l.add(LintError, label, "path", "required")
+		}
+		l.lintProject(label, id, project, "")
+	}
+}
+
+// list returns the objects under key, which may be missing
+func (l *configLinter) list(root map[string]interface{}, key string) ([]interface{}, bool) {
+	raw, ok := root[key]
+	if !ok || raw == nil {
+		return nil, false
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		l.add(LintError, "", key, "expected a list, got %s", kindOf(raw))
+	}
+	return items, ok
+}
+
+// lintProject checks the configuration of a project. id is the project it
+// updates, 0 for a new one, and sshHost its current host when the
+// configuration does not set one.
+func (l *configLinter) lintProject(label string, id uint, config map[string]interface{}, sshHost string) {
+	l.lintFields(label, config, projectConfigFields)
+
+	if host, ok := config["ssh_host"].(string); ok {
+		sshHost = host
+	}
+	path, _ := config["path"].(string)
+	// Paths of remote projects are on their host
+	if sshHost == "" {
+		if path != "" {
+			if info, err := os.Stat(path); err != nil {
+				l.add(LintError, label, "path", "%s does not exist", path)
+				path = ""
+			} else if !info.IsDir() {
+				l.add(LintError, label, "path", "%s is not a directory", path)
+				path = ""
+			}
+		}
+		for _, field := range []string{"working_dir", "env_file"} {
+			value, _ := config[field].(string)
+			if value == "" {
+				continue
+			}
+			resolved := value
+			if !filepath.IsAbs(resolved) {
+				if path == "" {
+					continue
+				}
+				resolved = filepath.Join(path, resolved)
+			}
+			if _, err := os.Stat(resolved); err != nil {
+				l.add(LintWarning, label, field, "%s does not exist", resolved)
+			}
+		}
+	}
+
+	if raw, _ := config["env_vars"].(string); strings.TrimSpace(raw) != "" {
+		var env map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &env); err != nil {
+			l.add(LintError, label, "env_vars", "must be a JSON object of variables: %v", err)
+		} else {
+			for _, key := range sortedKeys(env) {
+				switch env[key].(type) {
+				case map[string]interface{}, []interface{}:
+					l.add(LintWarning, label, "env_vars", "%s is not a string, number or boolean and is ignored", key)
+				}
+			}
+		}
+	}
+	if raw, _ := config["ports"].(string); strings.TrimSpace(raw) != "" && len(parseLegacyPorts(raw)) == 0 {
+		l.add(LintWarning, label, "ports", "not a JSON array of ports, ignored")
+	}
+
+	port, ok := intValue(config["port"])
+	if !ok || port == 0 {
+		return
+	}
+	if port < 0 || port > 65535 {
+		l.add(LintError, label, "port", "%d is not between 1 and 65535", port)
+		return
+	}
+	for _, conflict := range findPortConflicts(l.db, id, []ProjectPort{{Number: port, Protocol: ProtocolTCP}}) {
+		l.add(LintWarning, label, "port", "%d is also used by project %q", port, conflict.ProjectName)
+	}
+	if other, taken := l.ports[port]; taken {
+		l.add(LintWarning, label, "port", "%d is also used by %s in this file", port, other)
+	} else {
+		l.ports[port] = label
+	}
+}
+
+// lintFields reports unknown keys and values of the wrong type
+func (l *configLinter) lintFields(label string, config map[string]interface{}, fields map[string]configField) {
+	for _, key := range sortedKeys(config) {
+		// x- keys hold YAML anchors
+		if strings.HasPrefix(key, "x-") {
+			continue
+		}
+		field, known := fields[key]
+		if !known {
+			if suggestion := closestKey(key, fields); suggestion != "" {
+				l.add(LintWarning, label, key, "unknown key, ignored (did you mean %s?)", suggestion)
+			} else {
+				l.add(LintWarning, label, key, "unknown key, ignored")
+			}
+			continue
+		}
+
+		value := config[key]
+		if value == nil {
+			continue
+		}
+		switch field.kind {
+		case reflect.String:
+			s, ok := value.(string)
+			if !ok {
+				l.add(LintError, label, key, "must be a string, got %s", kindOf(value))
+			} else if len(field.oneOf) > 0 && s != "" && !containsString(field.oneOf, s) {
+				l.add(LintError, label, key, "%q is not one of %s", s, strings.Join(field.oneOf, ", "))
+			}
+		case reflect.Bool:
+			if _, ok := value.(bool); !ok {
+				l.add(LintError, label, key, "must be true or false, got %s", kindOf(value))
+			}
+		case reflect.Int, reflect.Int64, reflect.Uint:
+			n, ok := intValue(value)
+			switch {
+			case !ok:
+				l.add(LintError, label, key, "must be a whole number, got %s", kindOf(value))
+			case n == 0 && field.zero:
+			case n < field.min && field.min == 0:
+				l.add(LintError, label, key, "must not be negative")
+			case n < field.min:
+				l.add(LintError, label, key, "must be at least %d", field.min)
+			case field.max != nil && n > *field.max:
+				l.add(LintError, label, key, "must be at most %d", *field.max)
+			}
+		}
+	}
+}
+
+// intValue returns a whole number decoded from JSON (float64) or YAML (int)
+func intValue(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		if n == math.Trunc(n) {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// kindOf names the type of a decoded value
+func kindOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", v)
+	case bool:
+		return fmt.Sprintf("boolean %t", v)
+	case int, int64, uint64, float64:
+		return fmt.Sprintf("number %v", v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// closestKey suggests the known key a misspelled one was meant to be
+func closestKey(key string, fields map[string]configField) string {
+	best, bestDistance := "", 3
+	normalized := strings.ToLower(strings.ReplaceAll(key, "-", "_"))
+	for name := range fields {
+		if d := editDistance(normalized, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,379 @@
+package project
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+	"go-runner/internal/websocket"
+
+	"github.com/gin-gonic/gin"
+	gorillaws "github.com/gorilla/websocket"
+)
+
+const (
+	// tailSessionTTL is how long a tail session waits for its stream connection
+	tailSessionTTL = time.Minute
+	// tailHeartbeat keeps idle streams alive through proxies
+	tailHeartbeat = 15 * time.Second
+)
+
+// tailColors are assigned to projects in request order
+var tailColors = []struct {
+	name string
+	ansi string
+}{
+	{"cyan", "\x1b[36m"},
+	{"magenta", "\x1b[35m"},
+	{"yellow", "\x1b[33m"},
+	{"green", "\x1b[32m"},
+	{"blue", "\x1b[34m"},
+	{"red", "\x1b[31m"},
+}
+
+const ansiReset = "\x1b[0m"
+
+// TailLogsRequest selects the projects and lines of a multiplexed log tail
+type TailLogsRequest struct {
+	ProjectIDs []uint `json:"project_ids" binding:"required,min=1,max=50"`
+	Level      string `json:"level"`                            // Minimum level: trace, debug, info, warn, error, fatal
+	Include    string `json:"include"`                          // Only lines matching this regex
+	Exclude    string `json:"exclude"`                          // Drop lines matching this regex
+	Color      bool   `json:"color"`                            // Add ANSI color codes to the source tag in text
+	Backlog    int    `json:"backlog" binding:"min=0,max=1000"` // Buffered lines to replay per project before live lines
+}
+
+// TailProject is a project included in a tail with its tag color
+type TailProject struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// TailSession is a created tail waiting for its stream connection
+type TailSession struct {
+	SessionID string        `json:"session_id"`
+	WSURL     string        `json:"ws_url"`
+	SSEURL    string        `json:"sse_url"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	Projects  []TailProject `json:"projects"`
+}
+
+// TailLine is one line of a multiplexed tail
+type TailLine struct {
+	ProjectID uint   `json:"project_id"`
+	Project   string `json:"project"`
+	Color     string `json:"color"`
+	Level     string `json:"level"`
+	Line      string `json:"line"`
+	Text      string `json:"text"` // "[project] line", with ANSI colors when requested
+	Backlog   bool   `json:"backlog,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// tailSession is the server-side state of a tail
+type tailSession struct {
+	id       string
+	projects []TailProject
+	filter   *websocket.LogFilter
+	color    bool
+	backlog  int
+	expires  time.Time
+}
+
+// TailLogs godoc
+// @Summary      Tail logs of several projects
+// @Description  Merge the live logs of several projects into one stream with per-line source tags, to follow a request across frontend, API and worker. With "Accept: text/event-stream" the response itself is the SSE stream. Otherwise a session is created; connect to ws_url (WebSocket) or sse_url (SSE) within a minute. Each event is a TailLine.
+// @Tags         logs
+// @Accept       json
+// @Produce      json
+// @Produce      text/event-stream
+// @Param        request  body      TailLogsRequest  true  "Projects and filters"
+// @Success      200      {object}  TailLine                                "SSE stream of lines (Accept: text/event-stream)"
+// @Success      201      {object}  types.DataResponse{data=TailSession}   "Tail session"
+// @Failure      400      {object}  middleware.ErrorResponse               "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse{details=[]uint}  "Project not found"
+// @Router       /logs/tail [post]
+func (h *Handler) TailLogs(c *gin.Context) {
+	var req TailLogsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	session, err := h.newTailSession(&req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		h.serveTailSSE(c, session)
+		return
+	}
+
+	h.tailMu.Lock()
+	h.tailSessions[session.id] = session
+	h.tailMu.Unlock()
+	time.AfterFunc(tailSessionTTL, func() { h.claimTailSession(session.id) })
+
+	base := "/api/v1/logs/tail/" + session.id
+	c.JSON(http.StatusCreated, types.DataResponse{Data: TailSession{
+		SessionID: session.id,
+		WSURL:     base + "/ws",
+		SSEURL:    base + "/sse",
+		ExpiresAt: session.expires,
+		Projects:  session.projects,
+	}})
+}
+
+// TailLogsWebSocket godoc
+// @Summary      Stream a log tail over WebSocket
+// @Description  Connect to a tail session created by POST /logs/tail. Messages are {"type":"projects"|"log","data":...}.
+// @Tags         logs
+// @Param        session  path  string  true  "Tail session ID"
+// @Success      101  "Switching protocols"
+// @Failure      404  {object}  middleware.ErrorResponse  "Session not found or expired"
+// @Router       /logs/tail/{session}/ws [get]
+func (h *Handler) TailLogsWebSocket(c *gin.Context) {
+	session := h.claimTailSession(c.Param("session"))
+	if session == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Tail session not found or expired", nil))
+		return
+	}
+
+	conn, err := websocket.Upgrade(c)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Read until the client goes away
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	h.runTail(ctx, session, func(event string, data interface{}) error {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteJSON(websocket.Message{Type: event, Data: data, Timestamp: time.Now().Unix()})
+	}, func() error {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteMessage(gorillaws.PingMessage, nil)
+	})
+}
+
+// TailLogsSSE godoc
+// @Summary      Stream a log tail over SSE
+// @Description  Connect to a tail session created by POST /logs/tail with EventSource. Events are "projects" then "log".
+// @Tags         logs
+// @Produce      text/event-stream
+// @Param        session  path  string  true  "Tail session ID"
+// @Success      200  {object}  TailLine                  "SSE stream of lines"
+// @Failure      404  {object}  middleware.ErrorResponse  "Session not found or expired"
+// @Router       /logs/tail/{session}/sse [get]
+func (h *Handler) TailLogsSSE(c *gin.Context) {
+	session := h.claimTailSession(c.Param("session"))
+	if session == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Tail session not found or expired", nil))
+		return
+	}
+	h.serveTailSSE(c, session)
+}
+
+// serveTailSSE streams a tail as server-sent events on the current response
+func (h *Handler) serveTailSSE(c *gin.Context, session *tailSession) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	h.runTail(c.Request.Context(), session, func(event string, data interface{}) error {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}, func() error {
+		if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+}
+
+// runTail sends the project list, the backlog and then live lines until ctx
+// is done or sending fails
+func (h *Handler) runTail(ctx context.Context, session *tailSession, send func(event string, data interface{}) error, ping func() error) {
+	// Subscribe before replaying the backlog so no line falls in between
+	ids := make([]uint, 0, len(session.projects))
+	byID := make(map[uint]int, len(session.projects))
+	for i, p := range session.projects {
+		ids = append(ids, p.ID)
+		byID[p.ID] = i
+	}
+	events, unsubscribe := h.manager.SubscribeLogs(ids, 1024)
+	defer unsubscribe()
+
+	if err := send("projects", session.projects); err != nil {
+		return
+	}
+
+	if session.backlog > 0 {
+		for i, p := range session.projects {
+			entries := h.manager.GetServiceLogEntries(p.ID)
+			if len(entries) > session.backlog {
+				entries = entries[len(entries)-session.backlog:]
+			}
+			for _, entry := range entries {
+				if !session.filter.Match(entry.Line) {
+					continue
+				}
+				at := entry.Time
+				if at.IsZero() {
+					at = time.Now()
+				}
+				tl := session.line(i, entry.Line, at)
+				tl.Backlog = true
+				if err := send("log", tl); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(tailHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if err := ping(); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !session.filter.Match(event.Line) {
+				continue
+			}
+			if err := send("log", session.line(byID[event.ProjectID], event.Line, event.Time)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// newTailSession validates a tail request and resolves its projects
+func (h *Handler) newTailSession(req *TailLogsRequest) (*tailSession, error) {
+	filter, err := websocket.LogFilterSpec{Level: req.Level, Include: req.Include, Exclude: req.Exclude}.Compile()
+	if err != nil {
+		return nil, middleware.NewError(http.StatusBadRequest, "Invalid filter", err.Error())
+	}
+
+	var projects []Project
+	if err := h.db.Select("id, name").Where("id IN ?", req.ProjectIDs).Find(&projects).Error; err != nil {
+		return nil, middleware.NewError(http.StatusInternalServerError, "Failed to load projects", err.Error())
+	}
+	names := make(map[uint]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+
+	session := &tailSession{
+		filter:  filter,
+		color:   req.Color,
+		backlog: req.Backlog,
+		expires: time.Now().Add(tailSessionTTL),
+	}
+
+	var missing []uint
+	seen := make(map[uint]bool)
+	for _, id := range req.ProjectIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		name, ok := names[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		session.projects = append(session.projects, TailProject{
+			ID:    id,
+			Name:  name,
+			Color: tailColors[len(session.projects)%len(tailColors)].name,
+		})
+	}
+	if len(missing) > 0 {
+		return nil, middleware.NewError(http.StatusNotFound, "Project not found", missing)
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, middleware.NewError(http.StatusInternalServerError, "Failed to create tail session", err.Error())
+	}
+	session.id = hex.EncodeToString(buf)
+
+	return session, nil
+}
+
+// claimTailSession removes and returns a pending session; sessions are single use
+func (h *Handler) claimTailSession(id string) *tailSession {
+	h.tailMu.Lock()
+	defer h.tailMu.Unlock()
+
+	session, ok := h.tailSessions[id]
+	if !ok {
+		return nil
+	}
+	delete(h.tailSessions, id)
+	if time.Now().After(session.expires) {
+		return nil
+	}
+	return session
+}
+
+// line builds the tagged line of the i-th project of the session
+func (s *tailSession) line(i int, line string, at time.Time) TailLine {
+	p := s.projects[i]
+	tag := "[" + p.Name + "]"
+	if s.color {
+		tag = tailColors[i%len(tailColors)].ansi + tag + ansiReset
+	}
+
+	return TailLine{
+		ProjectID: p.ID,
+		Project:   p.Name,
+		Color:     p.Color,
+		Level:     websocket.DetectLogLevel(line).String(),
+		Line:      line,
+		Text:      tag + " " + line,
+		Timestamp: at.UnixMilli(),
+	}
+}
@@ -0,0 +1,128 @@
+package project
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"go-runner/internal/events"
+	"go-runner/internal/service"
+
+	"gorm.io/gorm"
+)
+
+// defaultMemoryGuardSamples is how many consecutive samples above the
+// ceiling trip the memory guard when memory_guard_samples is not set
+const defaultMemoryGuardSamples = 3
+
+// MemoryGuard watches the resident memory of running projects against their
+// memory_guard_mb, independently of cgroups, for dev servers that leak until
+// the machine swaps
+type MemoryGuard struct {
+	db        *gorm.DB
+	manager   *service.Manager
+	events    *events.Bus
+	annotator *Annotator
+
+	mu         sync.Mutex
+	over       map[uint]int  // Consecutive samples above the ceiling
+	restarting map[uint]bool // Restarts in progress
+}
+
+// NewMemoryGuard creates a memory guard
+func NewMemoryGuard(db *gorm.DB, manager *service.Manager, bus *events.Bus, annotator *Annotator) *MemoryGuard {
+	return &MemoryGuard{
+		db:         db,
+		manager:    manager,
+		events:     bus,
+		annotator:  annotator,
+		over:       make(map[uint]int),
+		restarting: make(map[uint]bool),
+	}
+}
+
+// Record checks a process sample against the memory guard of its project.
+// Once the RSS of the process tree was above the ceiling for
+// memory_guard_samples consecutive samples, a critical project_memory alert
+// is raised and, with memory_guard_restart, the service is restarted with a
+// restart annotation giving the reason. The alert resolves once the RSS is
+// below the ceiling again.
+func (g *MemoryGuard) Record(sample service.ProcessSample) {
+	var project Project
+	if err := g.db.Select("id, name, memory_guard_mb, memory_guard_samples, memory_guard_restart").
+		First(&project, sample.ProjectID).Error; err != nil {
+		return
+	}
+	prefix := fmt.Sprintf("Memory %s: ", project.Name)
+	limit := uint64(project.MemoryGuardMB) << 20
+	if project.MemoryGuardMB <= 0 || sample.MemoryRSS <= limit {
+		g.mu.Lock()
+		delete(g.over, project.ID)
+		g.mu.Unlock()
+		resolveAlert(g.db, g.events, "project_memory", project.ID, prefix)
+		return
+	}
+
+	needed := project.MemoryGuardSamples
+	if needed <= 0 {
+		needed = defaultMemoryGuardSamples
+	}
+	g.mu.Lock()
+	g.over[project.ID]++
+	count := g.over[project.ID]
+	tripped := count >= needed && !g.restarting[project.ID]
+	if tripped && project.MemoryGuardRestart {
+		g.restarting[project.ID] = true
+		delete(g.over, project.ID)
+	}
+	g.mu.Unlock()
+	if count < needed {
+		return
+	}
+
+	rss := float64(sample.MemoryRSS) / (1 << 20)
+	message := fmt.Sprintf("RSS %.0f MiB above the memory guard of %d MiB for %d samples", rss, project.MemoryGuardMB, count)
+	raiseAlert(g.db, g.events, "project_memory", "critical", project.ID, prefix, message, rss, float64(project.MemoryGuardMB))
+	if !tripped || !project.MemoryGuardRestart {
+		return
+	}
+
+	log.Printf("🧠 Restarting project %d: %s", project.ID, message)
+	annotation := &ProjectAnnotation{
+		ProjectID: project.ID,
+		Kind:      AnnotationRestart,
+		Message:   "Restarted by the memory guard",
+		Details:   message,
+		Automatic: true,
+	}
+	if err := g.annotator.Add(annotation); err != nil {
+		log.Printf("Failed to add restart annotation of project %d: %v", project.ID, err)
+	}
+	go func() {
+		defer func() {
+			g.mu.Lock()
+			delete(g.restarting, project.ID)
+			g.mu.Unlock()
+		}()
+		if err := g.manager.RestartService(project.ID); err != nil {
+			log.Printf("Failed to restart project %d for its memory guard: %v", project.ID, err)
+		}
+	}()
+}
+
+// StatusChanged forgets the samples of a project that stopped and resolves
+// its project_memory alert
+func (g *MemoryGuard) StatusChanged(change service.StatusChange) {
+	if change.Status == string(StatusRunning) {
+		return
+	}
+	g.mu.Lock()
+	delete(g.over, change.ProjectID)
+	g.mu.Unlock()
+
+	var project Project
+	if err := g.db.Unscoped().Select("id, name").First(&project, change.ProjectID).Error; err != nil {
+		return
+	}
+	resolveAlert(g.db, g.events, "project_memory", project.ID, fmt.Sprintf("Memory %s: ", project.Name))
+}
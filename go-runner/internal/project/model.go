@@ -1,7 +1,9 @@
 package project
 
 import (
+	"go-runner/internal/service"
 	"go-runner/internal/types"
+	"go-runner/internal/websocket"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,6 +12,7 @@ import (
 // Re-export types for convenience
 type ServiceStatus = types.ServiceStatus
 type ServiceType = types.ServiceType
+type PortProtocol = types.PortProtocol
 
 const (
 	StatusStopped  = types.StatusStopped
@@ -17,7 +20,9 @@ const (
 	StatusRunning  = types.StatusRunning
 	StatusStopping = types.StatusStopping
 	StatusError    = types.StatusError
+	StatusPaused   = types.StatusPaused
 	StatusUnknown  = types.StatusUnknown
+	StatusUnreachable = types.StatusUnreachable
 )
 
 const (
@@ -26,9 +31,16 @@ const (
 	TypeWorker   = types.TypeWorker
 	TypeDatabase = types.TypeDatabase
 	TypeQueue    = types.TypeQueue
+	TypeSystemd  = types.TypeSystemd
+	TypeMock     = types.TypeMock
 	TypeOther    = types.TypeOther
 )
 
+const (
+	ProtocolTCP = types.ProtocolTCP
+	ProtocolUDP = types.ProtocolUDP
+)
+
 // ProjectGroup represents a group of related microservices
 type ProjectGroup struct {
 	ID          uint           `json:"id" gorm:"primarykey"`
@@ -48,6 +60,7 @@ type Project struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	Version     uint           `json:"version" gorm:"default:1"` // Incremented by each configuration change; updates sending an older one are refused
 	
 	// Basic info
 	Name        string `json:"name" gorm:"not null" binding:"required"`
@@ -64,7 +77,9 @@ type Project struct {
 	
 	// Network and ports
 	Port        int    `json:"port"`
-	Ports       string `json:"ports"` // JSON array of ports for complex services
+	Ports       string `json:"ports"` // Deprecated: legacy JSON array of ports, use DeclaredPorts
+	DeclaredPorts []ProjectPort `json:"declared_ports,omitempty" gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+	SocketPath  string `json:"socket_path"` // Unix domain socket the service listens on (readiness check)
 	
 	// Environment and configuration
 	Environment string `json:"environment"` // development, staging, production
@@ -81,6 +96,8 @@ type Project struct {
 	StartTime   *time.Time    `json:"start_time"`  // When service started
 	StopTime    *time.Time    `json:"stop_time"`   // When service stopped
 	LastError   string        `json:"last_error"`  // Last error message
+	ProcessStart *time.Time   `json:"process_start,omitempty"` // Creation time of the process, identifies it with the PID after a server restart
+	CommandHash  string       `json:"command_hash,omitempty"`  // Hash of its command line, checked with it
 	
 	// Health check
 	HealthCheckURL string `json:"health_check_url"` // URL for health checks
@@ -88,63 +105,276 @@ type Project struct {
 	
 	// Auto-restart settings
 	AutoRestart bool `json:"auto_restart" gorm:"default:false"`
+	Autostart   bool `json:"autostart" gorm:"default:false"` // Start when the go-runner server starts
+	DependsOn   string `json:"depends_on"` // Comma-separated names of projects that must start first
+	WaitFor         string `json:"wait_for"`          // Comma-separated conditions checked before the command runs: tcp://host:port, http(s) URLs, file:// paths, postgres://, mysql:// or redis:// servers
+	WaitForTimeout  int    `json:"wait_for_timeout"`  // Seconds before the start fails, 60 when 0
+	WaitForInterval int    `json:"wait_for_interval"` // Seconds between checks, 1 when 0
 	RestartCount int  `json:"restart_count" gorm:"default:0"`
 	MaxRestarts  int  `json:"max_restarts" gorm:"default:3"`
+	RestartStrategy string `json:"restart_strategy"` // stop_start (default), or blue_green: start the new instance on another port, switch the proxy, then stop the old one
+	DrainTimeout    int    `json:"drain_timeout"`    // Seconds the old instance of a blue/green restart keeps serving open proxied connections, 30 when 0
+	Instances       int    `json:"instances"`        // Copies of the service run side by side, the proxy balancing requests over them, 1 when 0
+	InstancePorts   string `json:"instance_ports"`   // Ports of the extra instances: offset (default) for port + index, or auto for free ports
+
+	// Tracing
+	TraceInjection bool `json:"trace_injection" gorm:"default:false"` // Inject TRACEPARENT / REQUEST_ID on each start
+
+	// File change feed
+	WatchFiles bool `json:"watch_files" gorm:"default:false"` // Record changes in the project directory
+
+	// Low-power mode
+	Optional bool `json:"optional" gorm:"default:false"` // Non-essential, paused while in low-power mode
+
+	// Wake on demand (/projects/:id/proxy)
+	IdleTimeout int `json:"idle_timeout" gorm:"default:0"` // Minutes without proxied requests before the project is stopped, 0 to keep it running; started again by the next request
+
+	// Mock projects (type mock)
+	MockSpec string `json:"mock_spec" gorm:"type:text"` // Routes as JSON, or the path of a routes or OpenAPI file (JSON or YAML)
+
+	// Local network announcement (mdns.enabled)
+	MDNSAnnounce bool   `json:"mdns" gorm:"column:mdns_announce;default:false"` // Announce as <mdns_name>.local while running
+	MDNSName     string `json:"mdns_name" gorm:"column:mdns_name"`               // Host label, the project name when empty
+
+	// Public status page (/status)
+	StatusPage     bool   `json:"status_page" gorm:"default:false"` // Listed on the public status page
+	StatusPageName string `json:"status_page_name"`                 // Display name there, the project name when empty
+
+	// Kubernetes deployment (kubernetes.enabled), start/stop scale it instead of running a process
+	KubeContext    string `json:"kube_context"`                  // kubeconfig context, the current one when empty
+	KubeNamespace  string `json:"kube_namespace"`                // "default" when empty
+	KubeDeployment string `json:"kube_deployment"`               // Deployment name, a local process when empty
+	KubeReplicas   int    `json:"kube_replicas" gorm:"default:1"` // Replicas restored on start, saved on stop
+
+	// systemd unit (type systemd), start/stop/restart run systemd jobs over D-Bus and logs come from journald
+	SystemdUnit string `json:"systemd_unit"`                     // e.g. nginx.service, the project name when empty
+	SystemdUser bool   `json:"systemd_user" gorm:"default:false"` // Unit of the user manager (systemctl --user)
+
+	// Remote host, start/stop/status run over SSH and logs are tailed from the host
+	SSHHost string `json:"ssh_host"` // [user@]host[:port], a local process when empty
+
+	// Database and queue (types database, queue)
+	ConnectionString string `json:"connection_string"` // Database or broker URL, checked as health
+	MigrationCommand string `json:"migration_command"` // Shell command run by the migrate action, with DATABASE_URL set
+	TestCommand      string `json:"test_command"`      // Test command, detected from the project files when empty
+	Queues           string `json:"queues"`            // Comma-separated queues, redis keys or Kafka groups/topics to watch
+	QueueBacklogLimit int64 `json:"queue_backlog_limit"` // Alert when a queue holds more messages (0 = off)
+	QueueGrowthLimit  int64 `json:"queue_growth_limit"`  // Alert when a queue grows faster, in messages per minute (0 = off)
 	
 	// Resource limits
 	CPULimit    string `json:"cpu_limit"`    // CPU limit (e.g., "500m")
 	MemoryLimit string `json:"memory_limit"` // Memory limit (e.g., "512Mi")
 	
+	// Log files the service writes itself, merged into its logs tagged with their path
+	TailFiles string `json:"tail_files"` // Comma-separated paths or glob patterns, relative to the working directory
+
+	// Node.js inspector port while debugging (POST /projects/:id/inspect), 0 when off;
+	// cleared by a manual stop
+	InspectPort int `json:"inspect_port"`
+
+	// Profiling (POST /projects/:id/profile)
+	PprofURL string `json:"pprof_url"` // Base URL of net/http/pprof, http://127.0.0.1:<port>/debug/pprof when empty
+
+	// CI pipeline status (ci.github_token, ci.gitlab_token)
+	CIRepo   string `json:"ci_repo"`   // github:owner/repo or gitlab:group/project, detected from the origin remote when empty
+	CIBranch string `json:"ci_branch"` // Branch whose pipelines are shown, the checked out one when empty
+
+	// CPU and IO scheduling priority and CPU pinning, applied at start (PUT /projects/:id/priority changes them at runtime)
+	Nice        int    `json:"nice"`         // -20 (highest) to 19 (lowest); below 0 needs root
+	IOClass     string `json:"io_class"`     // realtime, best-effort or idle; empty for the default
+	IOPriority  int    `json:"io_priority"`  // 0 (highest) to 7 within realtime and best-effort
+	CPUAffinity string `json:"cpu_affinity"` // CPU cores to run on, e.g. "0-3,8" (Linux); empty for all
+
+	// Memory guard: alert (and restart) when the RSS of the process tree stays above a ceiling
+	MemoryGuardMB      int  `json:"memory_guard_mb"`      // Ceiling in MiB, 0 to disable
+	MemoryGuardSamples int  `json:"memory_guard_samples"` // Consecutive samples (30s apart) above it, 3 when 0
+	MemoryGuardRestart bool `json:"memory_guard_restart"` // Restart the service once the guard trips
+
+	// HTTP smoke tests (POST /projects/:id/smoke-tests/run)
+	SmokeTests          string `json:"smoke_tests" gorm:"type:text"` // JSON array of {"name", "method", "path", "headers", "body", "status", "body_contains", "timeout"}
+	SmokeTestsOnStart   bool   `json:"smoke_tests_on_start"`         // Run them after every start
+	SmokeTestsFailError bool   `json:"smoke_tests_fail_error"`       // Set the status to error while they fail
+
+	// Service catalog, shown in listings and included in alert notifications
+	Owner         string `json:"owner"`         // Person responsible, e.g. a name or @handle
+	Team          string `json:"team"`
+	RepositoryURL string `json:"repository_url"`
+	DocsURL       string `json:"docs_url"`      // Documentation or runbook
+	ChatChannel   string `json:"chat_channel"`  // e.g. #payments-oncall, or a link to the channel
+
 	// Logs storage (JSON array of log lines, last 1000 lines)
 	Logs string `json:"logs" gorm:"type:text"` // JSON array of log lines
+	LogTimes string `json:"-" gorm:"type:text"` // JSON array of the capture times of Logs, in UTC
+
+	// Latest dependency audit, without findings (not stored on the project)
+	Audit *DependencyAudit `json:"audit,omitempty" gorm:"-"`
+}
+
+// ProjectPort is a port declared by a project, such as http, grpc or metrics
+type ProjectPort struct {
+	ID        uint         `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	ProjectID uint         `json:"project_id" gorm:"index;not null"`
+	Name      string       `json:"name"`                          // e.g. http, grpc, metrics
+	Number    int          `json:"number" gorm:"not null"`        // Port number (1-65535)
+	Protocol  PortProtocol `json:"protocol" gorm:"default:'tcp'"` // tcp or udp
+	Public    bool         `json:"public" gorm:"default:false"`   // Exposed beyond localhost
 }
 
 // CreateProjectRequest represents the request to create a new project
 type CreateProjectRequest struct {
 	Name           string      `json:"name" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
 	Description    string      `json:"description" binding:"max=500" validate:"max=500"`
-	Type           ServiceType `json:"type" binding:"oneof=backend frontend worker database queue other" validate:"oneof=backend frontend worker database queue other"`
+	Type           ServiceType `json:"type" binding:"omitempty,oneof=backend frontend worker database queue systemd mock other" validate:"omitempty,oneof=backend frontend worker database queue systemd mock other"`
 	GroupID        *uint       `json:"group_id" validate:"omitempty,min=1"`
+	Group          string      `json:"group" validate:"max=100"` // Group name, instead of group_id
 	Path           string      `json:"path" binding:"required" validate:"required,min=1"`
 	Command        string      `json:"command" validate:"max=500"`
 	Args           string      `json:"args" validate:"max=500"`
 	WorkingDir     string      `json:"working_dir" validate:"max=500"`
-	Port           int         `json:"port" binding:"min=1,max=65535" validate:"port"`
+	Port           int         `json:"port" binding:"omitempty,min=0,max=65535" validate:"omitempty,port"` // 0 for none
 	Ports          string      `json:"ports" validate:"max=200"`
-	Environment    string      `json:"environment" binding:"oneof=development staging production" validate:"oneof=development staging production"`
+	DeclaredPorts  []ProjectPort `json:"declared_ports,omitempty"`
+	SocketPath     string      `json:"socket_path" validate:"max=500"`
+	Environment    string      `json:"environment" binding:"omitempty,oneof=development staging production" validate:"omitempty,oneof=development staging production"`
 	EnvFile        string      `json:"env_file" validate:"max=500"`
 	EnvVars        string      `json:"env_vars" validate:"max=2000"`
 	Editor         string      `json:"editor" validate:"max=50"`
 	EditorArgs     string      `json:"editor_args" validate:"max=500"`
 	HealthCheckURL string      `json:"health_check_url" binding:"omitempty,url" validate:"omitempty,url"`
 	AutoRestart    bool        `json:"auto_restart"`
+	Autostart      bool        `json:"autostart"`
+	DependsOn      string      `json:"depends_on" validate:"max=500"`
+	WaitFor         string     `json:"wait_for" validate:"max=1000"`
+	WaitForTimeout  int        `json:"wait_for_timeout" validate:"min=0,max=3600"`
+	WaitForInterval int        `json:"wait_for_interval" validate:"min=0,max=300"`
+	TraceInjection bool        `json:"trace_injection"`
+	WatchFiles     bool        `json:"watch_files"`
+	Optional       bool        `json:"optional"`
+	MDNSAnnounce   bool        `json:"mdns"`
+	MDNSName       string      `json:"mdns_name" validate:"max=63"`
+	StatusPage     bool        `json:"status_page"`
+	StatusPageName string      `json:"status_page_name" validate:"max=100"`
+	KubeContext    string      `json:"kube_context" validate:"max=253"`
+	KubeNamespace  string      `json:"kube_namespace" validate:"max=63"`
+	KubeDeployment string      `json:"kube_deployment" validate:"max=253"`
+	KubeReplicas   int         `json:"kube_replicas" validate:"min=0,max=100"`
+	SystemdUnit    string      `json:"systemd_unit" validate:"max=256"`
+	SystemdUser    bool        `json:"systemd_user"`
+	SSHHost        string      `json:"ssh_host" validate:"max=300"`
+	ConnectionString string    `json:"connection_string" validate:"max=1000"`
+	MigrationCommand string    `json:"migration_command" validate:"max=500"`
+	TestCommand    string      `json:"test_command" validate:"max=500"`
+	Queues         string      `json:"queues" validate:"max=1000"`
+	TailFiles      string      `json:"tail_files" validate:"max=2000"`
+	PprofURL       string      `json:"pprof_url" validate:"max=500"`
+	CIRepo         string      `json:"ci_repo" validate:"max=300"`
+	CIBranch       string      `json:"ci_branch" validate:"max=255"`
+	Nice           int         `json:"nice" validate:"min=-20,max=19"`
+	IOClass        string      `json:"io_class" validate:"omitempty,oneof=realtime best-effort idle"`
+	IOPriority     int         `json:"io_priority" validate:"min=0,max=7"`
+	CPUAffinity    string      `json:"cpu_affinity" validate:"max=100"`
+	MemoryGuardMB      int     `json:"memory_guard_mb" validate:"min=0"`
+	MemoryGuardSamples int     `json:"memory_guard_samples" validate:"min=0,max=100"`
+	MemoryGuardRestart bool    `json:"memory_guard_restart"`
+	SmokeTests     string      `json:"smoke_tests" validate:"max=20000"`
+	SmokeTestsOnStart   bool   `json:"smoke_tests_on_start"`
+	SmokeTestsFailError bool   `json:"smoke_tests_fail_error"`
+	Owner          string      `json:"owner" validate:"max=100"`
+	Team           string      `json:"team" validate:"max=100"`
+	RepositoryURL  string      `json:"repository_url" validate:"omitempty,url"`
+	DocsURL        string      `json:"docs_url" validate:"omitempty,url"`
+	ChatChannel    string      `json:"chat_channel" validate:"max=255"`
+	QueueBacklogLimit int64    `json:"queue_backlog_limit" validate:"min=0"`
+	QueueGrowthLimit  int64    `json:"queue_growth_limit" validate:"min=0"`
+	IdleTimeout    int         `json:"idle_timeout" validate:"min=0"`
+	MockSpec       string      `json:"mock_spec" validate:"max=100000"`
 	MaxRestarts    int         `json:"max_restarts" binding:"min=0,max=10" validate:"min=0,max=10"`
+	RestartStrategy string     `json:"restart_strategy" validate:"omitempty,oneof=stop_start blue_green"`
+	DrainTimeout    int        `json:"drain_timeout" validate:"min=0,max=3600"`
+	Instances       int        `json:"instances" validate:"min=0,max=16"`
+	InstancePorts   string     `json:"instance_ports" validate:"omitempty,oneof=offset auto"`
 	CPULimit       string      `json:"cpu_limit" validate:"max=20"`
 	MemoryLimit    string      `json:"memory_limit" validate:"max=20"`
 }
 
-// UpdateProjectRequest represents the request to update a project
+// UpdateProjectRequest represents the request to update a project: fields
+// left out or null keep their value
 type UpdateProjectRequest struct {
-	Name           *string      `json:"name"`
-	Description    *string      `json:"description"`
-	Type           *ServiceType `json:"type"`
+	Version        *uint        `json:"version"` // Version the changes were made on, checked rather than set
+	Name           *string      `json:"name" binding:"omitnil,min=1,max=100" validate:"omitnil,min=1,max=100"`
+	Description    *string      `json:"description" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	Type           *ServiceType `json:"type" binding:"omitempty,oneof=backend frontend worker database queue systemd mock other" validate:"omitempty,oneof=backend frontend worker database queue systemd mock other"`
 	GroupID        *uint        `json:"group_id"`
-	Path           *string      `json:"path"`
-	Command        *string      `json:"command"`
-	Args           *string      `json:"args"`
-	WorkingDir     *string      `json:"working_dir"`
-	Port           *int         `json:"port"`
-	Ports          *string      `json:"ports"`
-	Environment    *string      `json:"environment"`
-	EnvFile        *string      `json:"env_file"`
-	EnvVars        *string      `json:"env_vars"`
-	Editor         *string      `json:"editor"`
-	EditorArgs     *string      `json:"editor_args"`
-	HealthCheckURL *string      `json:"health_check_url"`
+	Path           *string      `json:"path" binding:"omitnil,min=1" validate:"omitnil,min=1"`
+	Command        *string      `json:"command" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	Args           *string      `json:"args" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	WorkingDir     *string      `json:"working_dir" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	Port           *int         `json:"port" binding:"omitnil,min=0,max=65535" validate:"omitnil,min=0,max=65535"`
+	Ports          *string      `json:"ports" binding:"omitnil,max=200" validate:"omitnil,max=200"`
+	SocketPath     *string      `json:"socket_path" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	Environment    *string      `json:"environment" binding:"omitempty,oneof=development staging production" validate:"omitempty,oneof=development staging production"`
+	EnvFile        *string      `json:"env_file" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	EnvVars        *string      `json:"env_vars" binding:"omitnil,max=2000" validate:"omitnil,max=2000"`
+	Editor         *string      `json:"editor" binding:"omitnil,max=50" validate:"omitnil,max=50"`
+	EditorArgs     *string      `json:"editor_args" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	HealthCheckURL *string      `json:"health_check_url" binding:"omitempty,url" validate:"omitempty,url"`
 	AutoRestart    *bool        `json:"auto_restart"`
-	MaxRestarts    *int         `json:"max_restarts"`
-	CPULimit       *string      `json:"cpu_limit"`
-	MemoryLimit    *string      `json:"memory_limit"`
+	Autostart      *bool        `json:"autostart"`
+	DependsOn      *string      `json:"depends_on" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	WaitFor         *string     `json:"wait_for" binding:"omitnil,max=1000" validate:"omitnil,max=1000"`
+	WaitForTimeout  *int        `json:"wait_for_timeout" binding:"omitnil,min=0,max=3600" validate:"omitnil,min=0,max=3600"`
+	WaitForInterval *int        `json:"wait_for_interval" binding:"omitnil,min=0,max=300" validate:"omitnil,min=0,max=300"`
+	TraceInjection *bool        `json:"trace_injection"`
+	WatchFiles     *bool        `json:"watch_files"`
+	Optional       *bool        `json:"optional"`
+	MDNSAnnounce   *bool        `json:"mdns"`
+	MDNSName       *string      `json:"mdns_name" binding:"omitnil,max=63" validate:"omitnil,max=63"`
+	StatusPage     *bool        `json:"status_page"`
+	StatusPageName *string      `json:"status_page_name" binding:"omitnil,max=100" validate:"omitnil,max=100"`
+	KubeContext    *string      `json:"kube_context" binding:"omitnil,max=253" validate:"omitnil,max=253"`
+	KubeNamespace  *string      `json:"kube_namespace" binding:"omitnil,max=63" validate:"omitnil,max=63"`
+	KubeDeployment *string      `json:"kube_deployment" binding:"omitnil,max=253" validate:"omitnil,max=253"`
+	KubeReplicas   *int         `json:"kube_replicas" binding:"omitnil,min=0,max=100" validate:"omitnil,min=0,max=100"`
+	SystemdUnit    *string      `json:"systemd_unit" binding:"omitnil,max=256" validate:"omitnil,max=256"`
+	SystemdUser    *bool        `json:"systemd_user"`
+	SSHHost        *string      `json:"ssh_host" binding:"omitnil,max=300" validate:"omitnil,max=300"`
+	ConnectionString *string    `json:"connection_string" binding:"omitnil,max=1000" validate:"omitnil,max=1000"`
+	MigrationCommand *string    `json:"migration_command" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	TestCommand    *string      `json:"test_command" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	Queues         *string      `json:"queues" binding:"omitnil,max=1000" validate:"omitnil,max=1000"`
+	TailFiles      *string      `json:"tail_files" binding:"omitnil,max=2000" validate:"omitnil,max=2000"`
+	PprofURL       *string      `json:"pprof_url" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	CIRepo         *string      `json:"ci_repo" binding:"omitnil,max=300" validate:"omitnil,max=300"`
+	CIBranch       *string      `json:"ci_branch" binding:"omitnil,max=255" validate:"omitnil,max=255"`
+	Nice           *int         `json:"nice" binding:"omitnil,min=-20,max=19" validate:"omitnil,min=-20,max=19"`
+	IOClass        *string      `json:"io_class" binding:"omitempty,oneof=realtime best-effort idle" validate:"omitempty,oneof=realtime best-effort idle"`
+	IOPriority     *int         `json:"io_priority" binding:"omitnil,min=0,max=7" validate:"omitnil,min=0,max=7"`
+	CPUAffinity    *string      `json:"cpu_affinity" binding:"omitnil,max=100" validate:"omitnil,max=100"`
+	MemoryGuardMB      *int     `json:"memory_guard_mb" binding:"omitnil,min=0" validate:"omitnil,min=0"`
+	MemoryGuardSamples *int     `json:"memory_guard_samples" binding:"omitnil,min=0,max=100" validate:"omitnil,min=0,max=100"`
+	MemoryGuardRestart *bool    `json:"memory_guard_restart"`
+	SmokeTests     *string      `json:"smoke_tests" binding:"omitnil,max=20000" validate:"omitnil,max=20000"`
+	SmokeTestsOnStart   *bool   `json:"smoke_tests_on_start"`
+	SmokeTestsFailError *bool   `json:"smoke_tests_fail_error"`
+	Owner          *string      `json:"owner" binding:"omitnil,max=100" validate:"omitnil,max=100"`
+	Team           *string      `json:"team" binding:"omitnil,max=100" validate:"omitnil,max=100"`
+	RepositoryURL  *string      `json:"repository_url" binding:"omitempty,url" validate:"omitempty,url"`
+	DocsURL        *string      `json:"docs_url" binding:"omitempty,url" validate:"omitempty,url"`
+	ChatChannel    *string      `json:"chat_channel" binding:"omitnil,max=255" validate:"omitnil,max=255"`
+	QueueBacklogLimit *int64    `json:"queue_backlog_limit" binding:"omitnil,min=0" validate:"omitnil,min=0"`
+	QueueGrowthLimit  *int64    `json:"queue_growth_limit" binding:"omitnil,min=0" validate:"omitnil,min=0"`
+	IdleTimeout    *int         `json:"idle_timeout" binding:"omitnil,min=0" validate:"omitnil,min=0"`
+	MockSpec       *string      `json:"mock_spec" binding:"omitnil,max=100000" validate:"omitnil,max=100000"`
+	MaxRestarts    *int         `json:"max_restarts" binding:"omitnil,min=0,max=10" validate:"omitnil,min=0,max=10"`
+	RestartStrategy *string     `json:"restart_strategy" binding:"omitempty,oneof=stop_start blue_green" validate:"omitempty,oneof=stop_start blue_green"`
+	DrainTimeout    *int        `json:"drain_timeout" binding:"omitnil,min=0,max=3600" validate:"omitnil,min=0,max=3600"`
+	Instances       *int        `json:"instances" binding:"omitnil,min=0,max=16" validate:"omitnil,min=0,max=16"`
+	InstancePorts   *string     `json:"instance_ports" binding:"omitempty,oneof=offset auto" validate:"omitempty,oneof=offset auto"`
+	CPULimit       *string      `json:"cpu_limit" binding:"omitnil,max=20" validate:"omitnil,max=20"`
+	MemoryLimit    *string      `json:"memory_limit" binding:"omitnil,max=20" validate:"omitnil,max=20"`
+	DeclaredPorts  *[]ProjectPort `json:"declared_ports"` // Replace the declared ports
 }
 
 // CreateProjectGroupRequest represents the request to create a new project group
@@ -156,7 +386,42 @@ type CreateProjectGroupRequest struct {
 
 // UpdateProjectGroupRequest represents the request to update a project group
 type UpdateProjectGroupRequest struct {
-	Name        *string `json:"name"`
-	Description *string `json:"description"`
-	Color       *string `json:"color"`
-}
\ No newline at end of file
+	Name        *string `json:"name" binding:"omitnil,min=1,max=100" validate:"omitnil,min=1,max=100"`
+	Description *string `json:"description" binding:"omitnil,max=500" validate:"omitnil,max=500"`
+	Color       *string `json:"color" binding:"omitempty,hexcolor" validate:"omitempty,hexcolor"`
+}
+
+// LogsResponse represents buffered logs of a project
+type LogsResponse struct {
+	Logs     []string               `json:"logs"`     // Prefixed with their capture time when timestamps are requested
+	Entries  []service.LogEntry     `json:"entries"`  // Lines with their capture time in UTC
+	Count    int                    `json:"count"`
+	Timezone websocket.TimezoneInfo `json:"timezone"` // Timezone of the prefixes, UTC by default
+}
+
+// ImportResult summarizes the outcome of a project import
+type ImportResult struct {
+	GroupsCreated   int      `json:"groups_created"`
+	GroupsUpdated   int      `json:"groups_updated"`
+	ProjectsCreated int      `json:"projects_created"`
+	ProjectsUpdated int      `json:"projects_updated"`
+	Errors          []string `json:"errors"`
+	Warnings        []LintIssue `json:"warnings,omitempty"` // From linting the import file
+}
+
+// TerminalInfo describes how to open a terminal in the project directory
+type TerminalInfo struct {
+	Path          string            `json:"path"`
+	WorkingDir    string            `json:"working_dir"`
+	Command       string            `json:"command,omitempty"`
+	Commands      map[string]string `json:"commands,omitempty"`
+	SimpleCommand string            `json:"simple_command,omitempty"`
+	OS            string            `json:"os,omitempty"`
+	Instructions  string            `json:"instructions"`
+}
+
+// KillPortResponse represents the result of killing a process on a port
+type KillPortResponse struct {
+	Message string `json:"message"`
+	Port    int    `json:"port"`
+}
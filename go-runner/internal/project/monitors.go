@@ -0,0 +1,625 @@
+package project
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// monitorChecksRetention is how long synthetic monitor checks are kept
+const monitorChecksRetention = 7 * 24 * time.Hour
+
+// Synthetic monitor defaults
+const (
+	defaultMonitorInterval = 60 // Seconds
+	minMonitorInterval     = 10
+	defaultMonitorFailures = 2
+)
+
+// monitorLatencyBuckets are the upper bounds of the latency histogram, in
+// milliseconds; a last bucket holds the slower checks
+var monitorLatencyBuckets = []float64{50, 100, 250, 500, 1000, 2500, 5000}
+
+// ProjectMonitor is a synthetic monitor: a request sent to a project on a
+// schedule, with the answer it must give and how fast
+type ProjectMonitor struct {
+	ID                 uint              `json:"id" gorm:"primarykey"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	ProjectID          uint              `json:"project_id" gorm:"index;not null"`
+	Name               string            `json:"name"`
+	URL                string            `json:"url"`    // /path on the project port, or a full http(s) URL
+	Method             string            `json:"method"` // GET when empty
+	Headers            map[string]string `json:"headers,omitempty" gorm:"type:text;serializer:json"`
+	Body               string            `json:"body,omitempty" gorm:"type:text"`
+	ExpectedStatus     int               `json:"expected_status"`      // Any below 400 when 0
+	BodyContains       string            `json:"body_contains"`        // Substring the response body must contain
+	Timeout            int               `json:"timeout"`              // Seconds, 5 when 0
+	Interval           int               `json:"interval"`             // Seconds between checks, 60 when 0
+	LatencyThresholdMs int               `json:"latency_threshold_ms"` // Checks slower than this are slow, 0 to disable
+	FailureThreshold   int               `json:"failure_threshold"`    // Consecutive failed or slow checks that raise an alert, 2 when 0
+	Paused             bool              `json:"paused" gorm:"default:false"`
+}
+
+// MonitorCheck is one check of a synthetic monitor
+type MonitorCheck struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	MonitorID uint      `json:"monitor_id" gorm:"index:,composite:lookup"`
+	ProjectID uint      `json:"project_id" gorm:"index"`
+	Timestamp time.Time `json:"timestamp" gorm:"index:,composite:lookup"`
+	Up        bool      `json:"up"`   // The answer was the expected one
+	Slow      bool      `json:"slow"` // Up, but slower than the latency threshold
+	Status    int       `json:"status,omitempty"`
+	LatencyMs float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// MonitorRequest creates or replaces a synthetic monitor
+type MonitorRequest struct {
+	Name               string            `json:"name" binding:"required,max=100"`
+	URL                string            `json:"url" binding:"required,max=2000"`
+	Method             string            `json:"method"` // GET when empty
+	Headers            map[string]string `json:"headers"`
+	Body               string            `json:"body" binding:"max=10000"`
+	ExpectedStatus     int               `json:"expected_status" binding:"omitempty,min=100,max=599"`
+	BodyContains       string            `json:"body_contains" binding:"max=500"`
+	Timeout            int               `json:"timeout" binding:"min=0,max=60"`
+	Interval           int               `json:"interval" binding:"omitempty,min=10,max=86400"`
+	LatencyThresholdMs int               `json:"latency_threshold_ms" binding:"min=0"`
+	FailureThreshold   int               `json:"failure_threshold" binding:"min=0,max=100"`
+	Paused             bool              `json:"paused"`
+}
+
+// MonitorSummary is a synthetic monitor with its last check and its
+// availability over the last 24 hours
+type MonitorSummary struct {
+	ProjectMonitor
+	State               string        `json:"state"` // up, slow, down, paused, or pending before the first check
+	LastCheck           *MonitorCheck `json:"last_check,omitempty"`
+	AvailabilityPercent *float64      `json:"availability_percent"` // Last 24 hours, null without checks
+}
+
+// LatencyBucket counts the checks up to a latency
+type LatencyBucket struct {
+	Le    string `json:"le"` // Upper bound in milliseconds, +Inf for the last bucket
+	Count int    `json:"count"`
+}
+
+// MonitorStats are the availability and latency of a synthetic monitor over
+// a period
+type MonitorStats struct {
+	MonitorID           uint            `json:"monitor_id"`
+	From                time.Time       `json:"from"`
+	To                  time.Time       `json:"to"`
+	Checks              int             `json:"checks"`
+	Up                  int             `json:"up"`
+	Slow                int             `json:"slow"`
+	AvailabilityPercent *float64        `json:"availability_percent"` // null without checks
+	AvgMs               float64         `json:"avg_ms"`               // Latencies of the checks that got an answer
+	P50Ms               float64         `json:"p50_ms"`
+	P95Ms               float64         `json:"p95_ms"`
+	P99Ms               float64         `json:"p99_ms"`
+	MaxMs               float64         `json:"max_ms"`
+	Histogram           []LatencyBucket `json:"histogram"`
+}
+
+// smokeTest returns the request of the monitor
+func (m *ProjectMonitor) smokeTest() SmokeTest {
+	return SmokeTest{
+		Name:         m.Name,
+		Method:       m.Method,
+		Path:         m.URL,
+		Headers:      m.Headers,
+		Body:         m.Body,
+		Status:       m.ExpectedStatus,
+		BodyContains: m.BodyContains,
+		Timeout:      m.Timeout,
+	}
+}
+
+// interval returns the time between two checks of the monitor
+func (m *ProjectMonitor) interval() time.Duration {
+	seconds := m.Interval
+	if seconds <= 0 {
+		seconds = defaultMonitorInterval
+	}
+	if seconds < minMonitorInterval {
+		seconds = minMonitorInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// alertPrefix starts the messages of the project_monitor alerts of the
+// monitor
+func (m *ProjectMonitor) alertPrefix(projectName string) string {
+	return fmt.Sprintf("Monitor %s/%s: ", projectName, m.Name)
+}
+
+// MonitorScheduler checks the synthetic monitors of projects at their
+// interval
+type MonitorScheduler struct {
+	db      *gorm.DB
+	manager *service.Manager
+	events  *events.Bus
+
+	mu      sync.Mutex
+	next    map[uint]time.Time // Next check of each monitor
+	running map[uint]bool
+}
+
+// NewMonitorScheduler creates a monitor scheduler
+func NewMonitorScheduler(db *gorm.DB, manager *service.Manager, bus *events.Bus) *MonitorScheduler {
+	return &MonitorScheduler{db: db, manager: manager, events: bus, next: make(map[uint]time.Time), running: make(map[uint]bool)}
+}
+
+// Run checks the monitors that are due every tick. Monitors of projects that
+// are stopped, starting or stopping are skipped, as their service is not
+// expected to answer.
+func (s *MonitorScheduler) Run(tick time.Duration) {
+	for {
+		s.checkDue()
+		time.Sleep(tick)
+	}
+}
+
+func (s *MonitorScheduler) checkDue() {
+	var monitors []ProjectMonitor
+	if err := s.db.Where("paused = ?", false).Find(&monitors).Error; err != nil || len(monitors) == 0 {
+		return
+	}
+	ids := make([]uint, 0, len(monitors))
+	for _, monitor := range monitors {
+		ids = append(ids, monitor.ProjectID)
+	}
+	var projects []Project
+	s.db.Select("id, status").Where("id IN ?", ids).Find(&projects)
+	statuses := make(map[uint]ServiceStatus, len(projects))
+	for _, project := range projects {
+		statuses[project.ID] = project.Status
+	}
+
+	now := time.Now()
+	for _, monitor := range monitors {
+		status, ok := statuses[monitor.ProjectID]
+		if !ok || status == StatusStopped || status == StatusStarting || status == StatusStopping || status == StatusPaused {
+			continue
+		}
+		s.mu.Lock()
+		due := !s.running[monitor.ID] && !now.Before(s.next[monitor.ID])
+		if due {
+			s.running[monitor.ID] = true
+			s.next[monitor.ID] = now.Add(monitor.interval())
+		}
+		s.mu.Unlock()
+		if !due {
+			continue
+		}
+
+		go func(monitor ProjectMonitor) {
+			defer func() {
+				s.mu.Lock()
+				delete(s.running, monitor.ID)
+				s.mu.Unlock()
+			}()
+			if _, err := checkMonitor(s.db, s.manager, s.events, &monitor); err != nil {
+				log.Printf("Failed to check monitor %d: %v", monitor.ID, err)
+			}
+		}(monitor)
+	}
+}
+
+// checkMonitor sends the request of a monitor, stores the check, publishes it
+// as a "monitor_check" event and raises or resolves its alert. Once the last
+// failure_threshold checks all failed a critical project_monitor alert is
+// raised, once they were all slow a warning one; a check that is up and fast
+// resolves it.
+func checkMonitor(db *gorm.DB, manager *service.Manager, bus *events.Bus, monitor *ProjectMonitor) (*MonitorCheck, error) {
+	var project Project
+	if err := db.Select("id, name, port, health_check_url").First(&project, monitor.ProjectID).Error; err != nil {
+		return nil, err
+	}
+	test := monitor.smokeTest()
+	if err := normalizeSmokeTest(&test); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	result := runSmokeTest(test, projectBaseURL(db, manager, &project))
+	check := &MonitorCheck{
+		MonitorID: monitor.ID,
+		ProjectID: monitor.ProjectID,
+		Timestamp: started,
+		Up:        result.Passed,
+		Status:    result.Status,
+		LatencyMs: math.Round(float64(time.Since(started).Microseconds())/100) / 10,
+		Error:     result.Error,
+	}
+	if check.Up && monitor.LatencyThresholdMs > 0 && check.LatencyMs > float64(monitor.LatencyThresholdMs) {
+		check.Slow = true
+	}
+	if err := db.Create(check).Error; err != nil {
+		return nil, err
+	}
+	db.Where("monitor_id = ? AND timestamp < ?", monitor.ID, time.Now().Add(-monitorChecksRetention)).Delete(&MonitorCheck{})
+	bus.Publish(monitor.ProjectID, "monitor_check", check)
+
+	needed := monitor.FailureThreshold
+	if needed <= 0 {
+		needed = defaultMonitorFailures
+	}
+	var recent []MonitorCheck
+	db.Where("monitor_id = ?", monitor.ID).Order("timestamp DESC, id DESC").Limit(needed).Find(&recent)
+	down, slow := len(recent) == needed, len(recent) == needed
+	for _, c := range recent {
+		down = down && !c.Up
+		slow = slow && c.Slow
+	}
+
+	prefix := monitor.alertPrefix(project.Name)
+	switch {
+	case down:
+		message := fmt.Sprintf("Down for %d checks: %s", needed, check.Error)
+		raiseAlert(db, bus, "project_monitor", "critical", project.ID, prefix, message, 0, float64(needed))
+	case slow:
+		message := fmt.Sprintf("Latency %.0f ms above %d ms for %d checks", check.LatencyMs, monitor.LatencyThresholdMs, needed)
+		raiseAlert(db, bus, "project_monitor", "warning", project.ID, prefix, message, check.LatencyMs, float64(monitor.LatencyThresholdMs))
+	case check.Up && !check.Slow:
+		resolveAlert(db, bus, "project_monitor", project.ID, prefix)
+	}
+	return check, nil
+}
+
+// monitorStats computes the availability and latency of a monitor since a
+// time
+func monitorStats(db *gorm.DB, monitorID uint, from time.Time) (*MonitorStats, error) {
+	var checks []MonitorCheck
+	if err := db.Select("up, slow, status, latency_ms").Where("monitor_id = ? AND timestamp >= ?", monitorID, from).Find(&checks).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &MonitorStats{MonitorID: monitorID, From: from, To: time.Now(), Checks: len(checks)}
+	counts := make([]int, len(monitorLatencyBuckets)+1)
+	var latencies []float64
+	var total float64
+	for _, check := range checks {
+		if check.Up {
+			stats.Up++
+		}
+		if check.Slow {
+			stats.Slow++
+		}
+		if check.Status == 0 {
+			continue // No answer, e.g. refused or timed out
+		}
+		latencies = append(latencies, check.LatencyMs)
+		total += check.LatencyMs
+		counts[sort.SearchFloat64s(monitorLatencyBuckets, check.LatencyMs)]++
+	}
+	if stats.Checks > 0 {
+		availability := float64(stats.Up) * 100 / float64(stats.Checks)
+		stats.AvailabilityPercent = &availability
+	}
+	if len(latencies) > 0 {
+		sort.Float64s(latencies)
+		stats.AvgMs = math.Round(total/float64(len(latencies))*10) / 10
+		stats.P50Ms = latencyPercentile(latencies, 50)
+		stats.P95Ms = latencyPercentile(latencies, 95)
+		stats.P99Ms = latencyPercentile(latencies, 99)
+		stats.MaxMs = latencies[len(latencies)-1]
+	}
+	for i, count := range counts {
+		bucket := LatencyBucket{Le: "+Inf", Count: count}
+		if i < len(monitorLatencyBuckets) {
+			bucket.Le = strconv.FormatFloat(monitorLatencyBuckets[i], 'f', -1, 64)
+		}
+		stats.Histogram = append(stats.Histogram, bucket)
+	}
+	return stats, nil
+}
+
+// latencyPercentile returns the p-th percentile of sorted latencies (nearest
+// rank)
+func latencyPercentile(sorted []float64, p int) float64 {
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// projectMonitorSummaries returns the monitors of a project with their last
+// check and availability
+func projectMonitorSummaries(db *gorm.DB, projectID uint) ([]MonitorSummary, error) {
+	var monitors []ProjectMonitor
+	if err := db.Where("project_id = ?", projectID).Order("id").Find(&monitors).Error; err != nil {
+		return nil, err
+	}
+	summaries := make([]MonitorSummary, 0, len(monitors))
+	since := time.Now().Add(-24 * time.Hour)
+	for _, monitor := range monitors {
+		summary := MonitorSummary{ProjectMonitor: monitor, State: "pending"}
+		var last MonitorCheck
+		if err := db.Where("monitor_id = ?", monitor.ID).Order("timestamp DESC, id DESC").Take(&last).Error; err == nil {
+			summary.LastCheck = &last
+			switch {
+			case !last.Up:
+				summary.State = "down"
+			case last.Slow:
+				summary.State = "slow"
+			default:
+				summary.State = "up"
+			}
+		}
+		if monitor.Paused {
+			summary.State = "paused"
+		}
+		var counts struct {
+			Checks int64
+			Up     int64
+		}
+		db.Model(&MonitorCheck{}).Select("COUNT(*) AS checks, COALESCE(SUM(CASE WHEN up THEN 1 ELSE 0 END), 0) AS up").
+			Where("monitor_id = ? AND timestamp >= ?", monitor.ID, since).Scan(&counts)
+		if counts.Checks > 0 {
+			availability := float64(counts.Up) * 100 / float64(counts.Checks)
+			summary.AvailabilityPercent = &availability
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// loadMonitor returns the monitor of the request path, checking it belongs to
+// the project
+func (h *Handler) loadMonitor(c *gin.Context) (*ProjectMonitor, error) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, middleware.ErrBadRequest
+	}
+	monitorID, err := strconv.Atoi(c.Param("monitor_id"))
+	if err != nil {
+		return nil, middleware.ErrBadRequest
+	}
+	var monitor ProjectMonitor
+	if err := h.db.Where("id = ? AND project_id = ?", monitorID, projectID).First(&monitor).Error; err != nil {
+		return nil, middleware.NewError(http.StatusNotFound, "Monitor not found", nil)
+	}
+	return &monitor, nil
+}
+
+// applyMonitorRequest copies a request onto a monitor and checks its request
+func applyMonitorRequest(monitor *ProjectMonitor, req *MonitorRequest) error {
+	monitor.Name = strings.TrimSpace(req.Name)
+	monitor.URL = strings.TrimSpace(req.URL)
+	monitor.Method = strings.ToUpper(req.Method)
+	monitor.Headers = req.Headers
+	monitor.Body = req.Body
+	monitor.ExpectedStatus = req.ExpectedStatus
+	monitor.BodyContains = req.BodyContains
+	monitor.Timeout = req.Timeout
+	monitor.Interval = req.Interval
+	monitor.LatencyThresholdMs = req.LatencyThresholdMs
+	monitor.FailureThreshold = req.FailureThreshold
+	monitor.Paused = req.Paused
+
+	test := monitor.smokeTest()
+	if err := normalizeSmokeTest(&test); err != nil {
+		return middleware.NewError(http.StatusBadRequest, "Invalid monitor", err.Error())
+	}
+	monitor.Method = test.Method
+	return nil
+}
+
+// GetMonitors godoc
+// @Summary      List synthetic monitors
+// @Description  List the synthetic monitors of a project with their state (up, slow, down, paused or pending), last check and availability over the last 24 hours
+// @Tags         monitors
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=[]MonitorSummary}
+// @Failure      400  {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/monitors [get]
+func (h *Handler) GetMonitors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	summaries, err := projectMonitorSummaries(h.db, project.ID)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch monitors", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: summaries})
+}
+
+// CreateMonitor godoc
+// @Summary      Create a synthetic monitor
+// @Description  Add a synthetic monitor to a project: a request sent every interval seconds (60 by default, at least 10) while the project is running or in error. url is a /path sent to the project port, or its first declared TCP port, on 127.0.0.1 (or else to the host of its health_check_url), or a full http(s) URL. A check is up with the expected_status (any below 400 when 0) and a body containing body_contains, and slow when up but slower than latency_threshold_ms. After failure_threshold (2) consecutive failed checks a critical project_monitor alert is raised, after as many slow checks a warning one.
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true  "Project ID"
+// @Param        request  body      MonitorRequest  true  "Monitor"
+// @Success      201      {object}  types.DataResponse{data=ProjectMonitor}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid monitor"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/monitors [post]
+func (h *Handler) CreateMonitor(c *gin.Context) {
+	var req MonitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid monitor", err.Error()))
+		return
+	}
+	var project Project
+	if err := h.db.Select("id").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	monitor := ProjectMonitor{ProjectID: project.ID}
+	if err := applyMonitorRequest(&monitor, &req); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if err := h.db.Create(&monitor).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to create monitor", err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, types.DataResponse{Data: monitor})
+}
+
+// UpdateMonitor godoc
+// @Summary      Replace a synthetic monitor
+// @Description  Replace the request, schedule and thresholds of a synthetic monitor. Its checks are kept; its alert is resolved, as the monitor may have been renamed or paused.
+// @Tags         monitors
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int             true  "Project ID"
+// @Param        monitor_id  path      int             true  "Monitor ID"
+// @Param        request     body      MonitorRequest  true  "Monitor"
+// @Success      200         {object}  types.DataResponse{data=ProjectMonitor}
+// @Failure      400         {object}  middleware.ErrorResponse  "Invalid monitor"
+// @Failure      404         {object}  middleware.ErrorResponse  "Monitor not found"
+// @Router       /projects/{id}/monitors/{monitor_id} [put]
+func (h *Handler) UpdateMonitor(c *gin.Context) {
+	var req MonitorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid monitor", err.Error()))
+		return
+	}
+	monitor, err := h.loadMonitor(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	before := *monitor
+	if err := applyMonitorRequest(monitor, &req); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if err := h.db.Save(monitor).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to update monitor", err.Error()))
+		return
+	}
+	h.resolveMonitorAlert(&before)
+	c.JSON(http.StatusOK, types.DataResponse{Data: monitor})
+}
+
+// DeleteMonitor godoc
+// @Summary      Delete a synthetic monitor
+// @Description  Delete a synthetic monitor with its checks, resolving its alert
+// @Tags         monitors
+// @Param        id          path  int  true  "Project ID"
+// @Param        monitor_id  path  int  true  "Monitor ID"
+// @Success      204
+// @Failure      404  {object}  middleware.ErrorResponse  "Monitor not found"
+// @Router       /projects/{id}/monitors/{monitor_id} [delete]
+func (h *Handler) DeleteMonitor(c *gin.Context) {
+	monitor, err := h.loadMonitor(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("monitor_id = ?", monitor.ID).Delete(&MonitorCheck{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(monitor).Error
+	}); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete monitor", err.Error()))
+		return
+	}
+	h.resolveMonitorAlert(monitor)
+	c.Status(http.StatusNoContent)
+}
+
+// resolveMonitorAlert resolves the alert of a monitor under its name
+func (h *Handler) resolveMonitorAlert(monitor *ProjectMonitor) {
+	var project Project
+	if err := h.db.Select("id, name").First(&project, monitor.ProjectID).Error; err != nil {
+		return
+	}
+	resolveAlert(h.db, h.events, "project_monitor", project.ID, monitor.alertPrefix(project.Name))
+}
+
+// CheckMonitor godoc
+// @Summary      Check a synthetic monitor now
+// @Description  Send the request of a synthetic monitor right away, whatever the project status, and store the check like a scheduled one
+// @Tags         monitors
+// @Produce      json
+// @Param        id          path      int  true  "Project ID"
+// @Param        monitor_id  path      int  true  "Monitor ID"
+// @Success      200         {object}  types.DataResponse{data=MonitorCheck}  "Check, up or not"
+// @Failure      404         {object}  middleware.ErrorResponse  "Monitor not found"
+// @Failure      500         {object}  middleware.ErrorResponse  "Internal server error"
+// @Router       /projects/{id}/monitors/{monitor_id}/check [post]
+func (h *Handler) CheckMonitor(c *gin.Context) {
+	monitor, err := h.loadMonitor(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	check, err := checkMonitor(h.db, h.manager, h.events, monitor)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to check monitor", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: check})
+}
+
+// GetMonitorStats godoc
+// @Summary      Get synthetic monitor statistics
+// @Description  Get the availability of a synthetic monitor, the percentiles of its latency and a latency histogram (non-cumulative buckets up to 50, 100, 250, 500, 1000, 2500, 5000 ms and +Inf) over the last hours. Latencies only count checks that got an answer. The latency and availability over time are dashboard metrics (monitor_latency, monitor_availability).
+// @Tags         monitors
+// @Produce      json
+// @Param        id          path      int  true   "Project ID"
+// @Param        monitor_id  path      int  true   "Monitor ID"
+// @Param        hours       query     int  false  "Hours of checks (default 24, max 168)"
+// @Success      200         {object}  types.DataResponse{data=MonitorStats}
+// @Failure      400         {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404         {object}  middleware.ErrorResponse  "Monitor not found"
+// @Router       /projects/{id}/monitors/{monitor_id}/stats [get]
+func (h *Handler) GetMonitorStats(c *gin.Context) {
+	monitor, err := h.loadMonitor(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	hours := 24
+	if raw := c.Query("hours"); raw != "" {
+		hours, err = strconv.Atoi(raw)
+		if err != nil || hours < 1 || hours > 168 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "hours must be between 1 and 168", raw))
+			return
+		}
+	}
+
+	stats, err := monitorStats(h.db, monitor.ID, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch monitor checks", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: stats})
+}
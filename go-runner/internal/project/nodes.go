@@ -0,0 +1,29 @@
+package project
+
+import (
+	"net/http"
+
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNodes godoc
+// @Summary      List the hosts of SSH projects
+// @Description  List the hosts of projects with an ssh_host as last checked: status (up, down after nodes.failures consecutive failed checks, unknown before the first check), latency of a TCP connection to the SSH port and, when a WireGuard interface routes the host, its peer and last handshake. The projects of a node that is down are "unreachable" until it is back.
+// @Tags         nodes
+// @Produce      json
+// @Param        status  query     string  false  "Only nodes with this status (up, down, unknown)"
+// @Success      200     {object}  types.DataResponse{data=[]service.Node}
+// @Router       /nodes [get]
+func (h *Handler) GetNodes(c *gin.Context) {
+	status := c.Query("status")
+	nodes := []service.Node{}
+	for _, node := range h.manager.Nodes() {
+		if status == "" || node.Status == status {
+			nodes = append(nodes, node)
+		}
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: nodes})
+}
@@ -0,0 +1,717 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Onboarding steps, in order
+const (
+	OnboardingStepReview  = "review"  // Proposal waiting to be reviewed and run
+	OnboardingStepCreate  = "create"  // Creating the group and projects
+	OnboardingStepDoctor  = "doctor"  // Checking toolchains, env vars and databases
+	OnboardingStepInstall = "install" // Installing dependencies
+	OnboardingStepStart   = "start"   // Starting the projects
+	OnboardingStepDone    = "done"
+)
+
+// onboardingSteps are the steps a run goes through
+var onboardingSteps = []string{OnboardingStepCreate, OnboardingStepDoctor, OnboardingStepInstall, OnboardingStepStart}
+
+// Onboarding statuses
+const (
+	OnboardingPending   = "pending" // Waiting for the client to run it
+	OnboardingRunning   = "running"
+	OnboardingFailed    = "failed" // Run again to resume at the failed step
+	OnboardingCompleted = "completed"
+)
+
+// Default ports proposed for detected services without one
+var onboardingDefaultPorts = map[string]int{"frontend": 3000, "backend": 8080}
+
+// OnboardingDoctor summarizes the doctor report of a proposed service
+type OnboardingDoctor struct {
+	Healthy bool                  `json:"healthy"`
+	Summary service.DoctorSummary `json:"summary"`
+	Issues  []service.DoctorCheck `json:"issues,omitempty"` // Warnings and failures
+}
+
+// OnboardingService is a service proposed by the onboarding, with what each
+// step did for it
+type OnboardingService struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	Path           string            `json:"path"`
+	Command        string            `json:"command"`
+	Port           int               `json:"port"`
+	PackageManager string            `json:"package_manager,omitempty"` // npm, yarn, pnpm, go, pip; empty skips the install
+	Include        bool              `json:"include"`                   // Create, install and start this service
+	Notes          []string          `json:"notes,omitempty"`           // Why the proposal differs from what was detected
+	ProjectID      *uint             `json:"project_id,omitempty"`      // Existing project with this path, or the one created
+	Doctor         *OnboardingDoctor `json:"doctor,omitempty"`
+	Installed      bool              `json:"installed"`
+	Started        bool              `json:"started"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// OnboardingOptions tune an onboarding run
+type OnboardingOptions struct {
+	SkipInstall    bool `json:"skip_install"`
+	SkipStart      bool `json:"skip_start"`
+	RequireHealthy bool `json:"require_healthy"` // Stop at the doctor step when a check fails
+}
+
+// OnboardingSession is the resumable state of a "set up this repo" flow:
+// detected services are proposed for review, then a run creates, checks,
+// installs and starts them, recording each step
+type OnboardingSession struct {
+	ID        uint                `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	Path      string              `json:"path"`
+	GroupName string              `json:"group_name"`
+	GroupID   *uint               `json:"group_id,omitempty"` // Group created or reused by the run
+	Step      string              `json:"step"`               // review, create, doctor, install, start, done
+	Status    string              `json:"status"`             // pending, running, failed, completed
+	Error     string              `json:"error,omitempty"`
+	JobID     *uint               `json:"job_id,omitempty"` // Last run
+	Options   OnboardingOptions   `json:"options" gorm:"type:text;serializer:json"`
+	Services  []OnboardingService `json:"services" gorm:"type:text;serializer:json"`
+}
+
+// StartOnboardingRequest starts an onboarding flow for a repository
+type StartOnboardingRequest struct {
+	Path      string `json:"path" binding:"required"`
+	GroupName string `json:"group_name"` // The directory name when empty
+}
+
+// UpdateOnboardingRequest edits the proposal before it is run
+type UpdateOnboardingRequest struct {
+	GroupName *string             `json:"group_name"`
+	Services  []OnboardingService `json:"services"` // Replaces the proposed services
+	Options   *OnboardingOptions  `json:"options"`
+}
+
+// StartOnboarding godoc
+// @Summary      Start onboarding a repository
+// @Description  Scan a repository for Node.js, Go and Python services and propose a project for each: name, type, command, a free port (conflicts with other projects and listening processes are moved and explained in notes), package manager and a group named after the directory. Directories already registered as projects are reused. Review and edit the proposal with PUT /onboarding/{id}, then POST /onboarding/{id}/run.
+// @Tags         onboarding
+// @Accept       json
+// @Produce      json
+// @Param        request  body      StartOnboardingRequest  true  "Repository"
+// @Success      201      {object}  types.DataResponse{data=OnboardingSession}
+// @Failure      400      {object}  middleware.ErrorResponse  "Path does not exist"
+// @Router       /onboarding [post]
+func (h *Handler) StartOnboarding(c *gin.Context) {
+	var req StartOnboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	path, err := filepath.Abs(req.Path)
+	if err == nil {
+		var info os.FileInfo
+		if info, err = os.Stat(path); err == nil && !info.IsDir() {
+			err = fmt.Errorf("%s is not a directory", path)
+		}
+	}
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Path does not exist", err.Error()))
+		return
+	}
+
+	detected, err := detectServices(path)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to scan path", err.Error()))
+		return
+	}
+
+	session := OnboardingSession{
+		Path:      path,
+		GroupName: req.GroupName,
+		Step:      OnboardingStepReview,
+		Status:    OnboardingPending,
+		Services:  h.proposeServices(detected),
+	}
+	if session.GroupName == "" {
+		session.GroupName = filepath.Base(path)
+	}
+	if err := h.db.Create(&session).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to save onboarding", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.DataResponse{Data: session})
+}
+
+// GetOnboardings godoc
+// @Summary      List onboardings
+// @Description  Get the onboarding sessions, newest first
+// @Tags         onboarding
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]OnboardingSession}
+// @Router       /onboarding [get]
+func (h *Handler) GetOnboardings(c *gin.Context) {
+	var sessions []OnboardingSession
+	if err := h.db.Order("id DESC").Find(&sessions).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch onboardings", err.Error()))
+		return
+	}
+	for i := range sessions {
+		h.settleOnboarding(&sessions[i])
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: sessions})
+}
+
+// GetOnboarding godoc
+// @Summary      Get an onboarding
+// @Description  Get the state of an onboarding: the current step and status, and per service the project, doctor summary and whether it was installed and started. Progress of a run is also broadcast as "onboarding_update" messages.
+// @Tags         onboarding
+// @Produce      json
+// @Param        id   path      int  true  "Onboarding ID"
+// @Success      200  {object}  types.DataResponse{data=OnboardingSession}
+// @Failure      404  {object}  middleware.ErrorResponse  "Onboarding not found"
+// @Router       /onboarding/{id} [get]
+func (h *Handler) GetOnboarding(c *gin.Context) {
+	session, err := h.loadOnboarding(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: session})
+}
+
+// UpdateOnboarding godoc
+// @Summary      Edit an onboarding proposal
+// @Description  Change the group name, the run options or the proposed services (the list replaces the proposal: set include to false to leave a service out, or change its name, command, port or package manager). Only possible before the first run.
+// @Tags         onboarding
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                      true  "Onboarding ID"
+// @Param        request  body      UpdateOnboardingRequest  true  "Changes"
+// @Success      200      {object}  types.DataResponse{data=OnboardingSession}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid proposal"
+// @Failure      404      {object}  middleware.ErrorResponse  "Onboarding not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Onboarding already ran"
+// @Router       /onboarding/{id} [put]
+func (h *Handler) UpdateOnboarding(c *gin.Context) {
+	session, err := h.loadOnboarding(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	var req UpdateOnboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if session.Step != OnboardingStepReview || session.Status == OnboardingRunning {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Onboarding already ran", "the proposal can only be edited before the first run"))
+		return
+	}
+
+	if req.GroupName != nil {
+		session.GroupName = strings.TrimSpace(*req.GroupName)
+	}
+	if req.Options != nil {
+		session.Options = *req.Options
+	}
+	if req.Services != nil {
+		if err := validateOnboardingServices(req.Services); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid proposal", err.Error()))
+			return
+		}
+		session.Services = req.Services
+	}
+
+	if err := h.db.Save(session).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to save onboarding", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: session})
+}
+
+// RunOnboarding godoc
+// @Summary      Run an onboarding
+// @Description  Create the group and the included projects, run the doctor on each, install their dependencies and start them, as a background job. Each step is recorded, so a failed or cancelled run resumes at the step it stopped with POST /onboarding/{id}/run again; services already created, installed or started are skipped. Options given here replace the saved ones.
+// @Tags         onboarding
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                true   "Onboarding ID"
+// @Param        request  body      OnboardingOptions  false  "Run options"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Onboarding job queued"
+// @Failure      404      {object}  middleware.ErrorResponse  "Onboarding not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Onboarding running or completed"
+// @Router       /onboarding/{id}/run [post]
+func (h *Handler) RunOnboarding(c *gin.Context) {
+	session, err := h.loadOnboarding(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	var options OnboardingOptions
+	if err := c.ShouldBindJSON(&options); err == nil {
+		session.Options = options
+	} else if !errors.Is(err, io.EOF) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if session.Status == OnboardingCompleted {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Onboarding completed", "start a new onboarding to set the repository up again"))
+		return
+	}
+	if session.Status == OnboardingRunning {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Onboarding is already running", gin.H{"job_id": session.JobID}))
+		return
+	}
+	if err := h.db.Model(session).Select("options").Updates(session).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to save onboarding", err.Error()))
+		return
+	}
+
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:    jobs.TypeOnboarding,
+		Key:     fmt.Sprintf("onboarding:%d", session.ID),
+		Message: "Onboarding " + session.Path,
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		return h.runOnboarding(ctx, run, session.ID)
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Onboarding is already running", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// DeleteOnboarding godoc
+// @Summary      Delete an onboarding
+// @Description  Delete an onboarding session. Projects and groups it created are kept.
+// @Tags         onboarding
+// @Produce      json
+// @Param        id   path      int  true  "Onboarding ID"
+// @Success      200  {object}  types.MessageResponse
+// @Failure      404  {object}  middleware.ErrorResponse  "Onboarding not found"
+// @Failure      409  {object}  middleware.ErrorResponse  "Onboarding is running"
+// @Router       /onboarding/{id} [delete]
+func (h *Handler) DeleteOnboarding(c *gin.Context) {
+	session, err := h.loadOnboarding(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if session.Status == OnboardingRunning {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Onboarding is running", gin.H{"job_id": session.JobID}))
+		return
+	}
+
+	if err := h.db.Delete(session).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete onboarding", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Onboarding deleted"})
+}
+
+// loadOnboarding loads the onboarding of the :id parameter
+func (h *Handler) loadOnboarding(c *gin.Context) (*OnboardingSession, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, middleware.ErrBadRequest
+	}
+
+	var session OnboardingSession
+	if err := h.db.First(&session, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, middleware.ErrNotFound
+		}
+		return nil, middleware.NewError(http.StatusInternalServerError, "Failed to fetch onboarding", err.Error())
+	}
+	h.settleOnboarding(&session)
+	return &session, nil
+}
+
+// settleOnboarding marks a running onboarding whose job ended without
+// finishing it (server restart) as failed, so that it can be resumed
+func (h *Handler) settleOnboarding(session *OnboardingSession) {
+	if session.Status != OnboardingRunning || session.JobID == nil {
+		return
+	}
+	job, err := h.jobs.Get(*session.JobID)
+	if err == nil && !job.Status.Finished() {
+		return
+	}
+	session.Status = OnboardingFailed
+	if session.Error == "" {
+		session.Error = "onboarding run was interrupted"
+	}
+	h.db.Save(session)
+}
+
+// proposeServices turns detected services into a proposal: directories
+// already registered are reused, and names and ports that clash with other
+// projects or listening processes are changed
+func (h *Handler) proposeServices(detected []ServiceDetection) []OnboardingService {
+	var existing []Project
+	h.db.Select("id, name, path, port").Find(&existing)
+
+	names := make(map[string]bool)
+	ports := make(map[int]string) // Port -> owner
+	byPath := make(map[string]Project)
+	for _, p := range existing {
+		names[p.Name] = true
+		if p.Port > 0 {
+			ports[p.Port] = fmt.Sprintf("project %q", p.Name)
+		}
+		byPath[filepath.Clean(p.Path)] = p
+	}
+
+	proposal := make([]OnboardingService, 0, len(detected))
+	for _, d := range detected {
+		svc := OnboardingService{
+			Name:    d.Name,
+			Type:    d.Type,
+			Path:    d.Path,
+			Command: d.Command,
+			Port:    d.Port,
+			Include: d.Command != "",
+		}
+		if !svc.Include {
+			svc.Notes = append(svc.Notes, "No service detected; set a command to include it")
+		}
+
+		if p, ok := byPath[filepath.Clean(d.Path)]; ok {
+			id := p.ID
+			svc.ProjectID = &id
+			svc.Name, svc.Port = p.Name, p.Port
+			svc.Notes = append(svc.Notes, fmt.Sprintf("Already registered as project %d", p.ID))
+			proposal = append(proposal, svc)
+			continue
+		}
+
+		switch filepath.Base(d.PackageFile) {
+		case "package.json":
+			svc.PackageManager = nodePackageManager(d.Path)
+		case "go.mod":
+			svc.PackageManager = "go"
+		case "requirements.txt":
+			svc.PackageManager = "pip"
+		}
+
+		if names[svc.Name] {
+			base := svc.Name
+			for i := 2; names[svc.Name]; i++ {
+				svc.Name = fmt.Sprintf("%s-%d", base, i)
+			}
+			svc.Notes = append(svc.Notes, fmt.Sprintf("Renamed from %q, which another project uses", base))
+		}
+		names[svc.Name] = true
+
+		if svc.Port == 0 && svc.Include {
+			svc.Port = onboardingDefaultPorts[svc.Type]
+		}
+		if svc.Port > 0 {
+			wanted := svc.Port
+			owner := ports[svc.Port]
+			if owner == "" && !portFree(svc.Port) {
+				owner = "another process"
+			}
+			if owner != "" {
+				for ports[svc.Port] != "" || !portFree(svc.Port) {
+					svc.Port++
+				}
+				svc.Notes = append(svc.Notes, fmt.Sprintf("Port %d is used by %s, proposing %d", wanted, owner, svc.Port))
+			}
+			ports[svc.Port] = fmt.Sprintf("service %q", svc.Name)
+		}
+
+		proposal = append(proposal, svc)
+	}
+	return proposal
+}
+
+// validateOnboardingServices checks an edited proposal: included services
+// need a name, a valid type, a command and a port no other included service
+// uses
+func validateOnboardingServices(services []OnboardingService) error {
+	names := make(map[string]bool)
+	ports := make(map[int]string)
+	for _, svc := range services {
+		if !svc.Include {
+			continue
+		}
+		if strings.TrimSpace(svc.Name) == "" {
+			return fmt.Errorf("service at %s has no name", svc.Path)
+		}
+		if names[svc.Name] {
+			return fmt.Errorf("service name %q is used twice", svc.Name)
+		}
+		names[svc.Name] = true
+
+		switch ServiceType(svc.Type) {
+		case TypeBackend, TypeFrontend, TypeWorker, TypeDatabase, TypeQueue, TypeOther:
+		default:
+			return fmt.Errorf("service %q has invalid type %q", svc.Name, svc.Type)
+		}
+		if svc.ProjectID == nil && strings.TrimSpace(svc.Command) == "" {
+			return fmt.Errorf("service %q has no command", svc.Name)
+		}
+		if svc.Port < 0 || svc.Port > 65535 {
+			return fmt.Errorf("service %q has invalid port %d", svc.Name, svc.Port)
+		}
+		if other, ok := ports[svc.Port]; ok && svc.Port > 0 {
+			return fmt.Errorf("services %q and %q both use port %d", other, svc.Name, svc.Port)
+		}
+		ports[svc.Port] = svc.Name
+		if svc.PackageManager != "" && installCommand(svc.PackageManager, nil) == nil {
+			return fmt.Errorf("service %q has unknown package manager %q", svc.Name, svc.PackageManager)
+		}
+	}
+	return nil
+}
+
+// runOnboarding runs the steps of an onboarding from the one it stopped at,
+// saving and broadcasting the session after each change
+func (h *Handler) runOnboarding(ctx context.Context, run *jobs.Run, sessionID uint) (*OnboardingSession, error) {
+	var session OnboardingSession
+	if err := h.db.First(&session, sessionID).Error; err != nil {
+		return nil, fmt.Errorf("onboarding not found: %v", err)
+	}
+	save := func() {
+		h.db.Save(&session)
+		h.events.Publish(0, "onboarding_update", session)
+	}
+
+	jobID := run.ID()
+	session.JobID = &jobID
+	session.Status = OnboardingRunning
+	session.Error = ""
+
+	first := 0
+	for i, step := range onboardingSteps {
+		if step == session.Step {
+			first = i
+		}
+	}
+
+	for i := first; i < len(onboardingSteps); i++ {
+		step := onboardingSteps[i]
+		session.Step = step
+		save()
+		run.Progress(i*100/len(onboardingSteps), "Step "+step)
+
+		var err error
+		switch step {
+		case OnboardingStepCreate:
+			err = h.onboardingCreate(&session, save)
+		case OnboardingStepDoctor:
+			err = h.onboardingDoctor(ctx, &session, save)
+		case OnboardingStepInstall:
+			if !session.Options.SkipInstall {
+				err = h.onboardingInstall(ctx, run, &session, save)
+			}
+		case OnboardingStepStart:
+			if !session.Options.SkipStart {
+				err = h.onboardingStart(ctx, &session, save)
+			}
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			session.Status = OnboardingFailed
+			session.Error = err.Error()
+			save()
+			return &session, err
+		}
+	}
+
+	session.Step = OnboardingStepDone
+	session.Status = OnboardingCompleted
+	session.Error = ""
+	save()
+	return &session, nil
+}
+
+// onboardingCreate creates the group and the included projects not created yet
+func (h *Handler) onboardingCreate(session *OnboardingSession, save func()) error {
+	if session.GroupID == nil && session.GroupName != "" {
+		var group ProjectGroup
+		if err := h.db.Where("name = ?", session.GroupName).First(&group).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				return fmt.Errorf("failed to look up group %s: %v", session.GroupName, err)
+			}
+			group = ProjectGroup{Name: session.GroupName, Description: "Onboarded from " + session.Path}
+			if err := h.db.Create(&group).Error; err != nil {
+				return fmt.Errorf("failed to create group %s: %v", session.GroupName, err)
+			}
+		}
+		session.GroupID = &group.ID
+		save()
+	}
+
+	for i := range session.Services {
+		svc := &session.Services[i]
+		if !svc.Include || svc.ProjectID != nil {
+			continue
+		}
+		project := Project{
+			Name:    svc.Name,
+			Type:    ServiceType(svc.Type),
+			Path:    svc.Path,
+			Command: svc.Command,
+			Port:    svc.Port,
+			GroupID: session.GroupID,
+		}
+		if err := h.db.Create(&project).Error; err != nil {
+			svc.Error = err.Error()
+			save()
+			return fmt.Errorf("failed to create project %s: %v", svc.Name, err)
+		}
+		svc.ProjectID = &project.ID
+		svc.Error = ""
+		save()
+	}
+	return nil
+}
+
+// onboardingDoctor checks each included project. Failures only stop the run
+// with require_healthy.
+func (h *Handler) onboardingDoctor(ctx context.Context, session *OnboardingSession, save func()) error {
+	var unhealthy []string
+	for i := range session.Services {
+		svc := &session.Services[i]
+		if !svc.Include || svc.ProjectID == nil {
+			continue
+		}
+		report, err := h.manager.RunDoctor(ctx, *svc.ProjectID)
+		if err != nil {
+			return fmt.Errorf("doctor failed for %s: %v", svc.Name, err)
+		}
+
+		doctor := &OnboardingDoctor{Healthy: report.Healthy, Summary: report.Summary}
+		for _, check := range report.Checks {
+			if check.Status != service.DoctorPass {
+				doctor.Issues = append(doctor.Issues, check)
+			}
+		}
+		svc.Doctor = doctor
+		save()
+		if !report.Healthy {
+			unhealthy = append(unhealthy, svc.Name)
+		}
+	}
+
+	if len(unhealthy) > 0 && session.Options.RequireHealthy {
+		return fmt.Errorf("doctor checks failed for %s; fix them and run again", strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
+
+// onboardingInstall installs the dependencies of each included project with
+// a package manager, skipping those installed already
+func (h *Handler) onboardingInstall(ctx context.Context, run *jobs.Run, session *OnboardingSession, save func()) error {
+	for i := range session.Services {
+		svc := &session.Services[i]
+		if !svc.Include || svc.ProjectID == nil || svc.Installed {
+			continue
+		}
+		if svc.PackageManager == "" || (isNodePackageManager(svc.PackageManager) && fileExists(filepath.Join(svc.Path, "node_modules", ".package-lock.json"))) {
+			svc.Installed = true
+			save()
+			continue
+		}
+
+		args := installCommand(svc.PackageManager, nil)
+		if _, err := exec.LookPath(args[0]); err != nil {
+			svc.Error = err.Error()
+			save()
+			return fmt.Errorf("cannot install %s: %v", svc.Name, err)
+		}
+
+		run.Log(fmt.Sprintf("$ %s  (%s)", strings.Join(args, " "), svc.Name))
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = svc.Path
+		if _, err := h.streamCommand(run, *svc.ProjectID, "install_log", cmd); err != nil {
+			svc.Error = fmt.Sprintf("%s: %v", strings.Join(args, " "), err)
+			save()
+			return fmt.Errorf("install failed for %s: %v", svc.Name, err)
+		}
+		svc.Installed = true
+		svc.Error = ""
+		save()
+	}
+	return nil
+}
+
+// onboardingStart starts each included project that is not running. Every
+// project is attempted before the failures are reported.
+func (h *Handler) onboardingStart(ctx context.Context, session *OnboardingSession, save func()) error {
+	var failed []string
+	for i := range session.Services {
+		svc := &session.Services[i]
+		if !svc.Include || svc.ProjectID == nil || svc.Started {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if !h.manager.IsServiceRunning(*svc.ProjectID) {
+			if err := h.manager.StartService(*svc.ProjectID); err != nil {
+				svc.Error = err.Error()
+				save()
+				failed = append(failed, svc.Name)
+				continue
+			}
+		}
+		svc.Started = true
+		svc.Error = ""
+		save()
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to start %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// isNodePackageManager reports whether a package manager installs node_modules
+func isNodePackageManager(name string) bool {
+	return name == "npm" || name == "yarn" || name == "pnpm"
+}
+
+// portFree reports whether a TCP port can be listened on
+func portFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
@@ -0,0 +1,62 @@
+package project
+
+import (
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CleanupOrphansRequest selects the orphans to terminate
+type CleanupOrphansRequest struct {
+	PIDs []int32 `json:"pids"` // From GET /system/orphans, every marked orphan when empty
+}
+
+// GetOrphans godoc
+// @Summary      List zombie and orphaned processes
+// @Description  Scan for zombie processes and for service processes no longer under their project process: started by a previous go-runner (previous_session), left behind by a stop or daemonized (detached), or adopted by init while running in a project directory (reparented). Service processes are recognized by the GO_RUNNER_PROJECT_ID and GO_RUNNER_SESSION variables set on start. The host is also scanned every minute.
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=go-runner_internal_service.OrphanReport}
+// @Failure      500  {object}  middleware.ErrorResponse  "Processes not readable"
+// @Router       /system/orphans [get]
+func (h *Handler) GetOrphans(c *gin.Context) {
+	report, err := h.manager.ScanOrphans()
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to list processes", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: report})
+}
+
+// CleanupOrphans godoc
+// @Summary      Terminate orphaned processes
+// @Description  Send SIGTERM to the given orphans, or when pids is empty to every previous_session and detached orphan (reparented ones are matched by directory only and must be listed), and SIGKILL to those still running after 5 seconds. Only processes a fresh scan reports as orphans are signaled; zombies are reaped by their parent and cannot be cleaned.
+// @Tags         system
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CleanupOrphansRequest  false  "Processes to terminate"
+// @Success      200      {object}  types.DataMessageResponse{data=[]go-runner_internal_service.OrphanCleanup}
+// @Failure      500      {object}  middleware.ErrorResponse  "Processes not readable"
+// @Router       /system/orphans/cleanup [post]
+func (h *Handler) CleanupOrphans(c *gin.Context) {
+	var req CleanupOrphansRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+			return
+		}
+	}
+
+	results, err := h.manager.CleanupOrphans(req.PIDs)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to list processes", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Data:    results,
+		Message: "Orphaned processes cleaned up",
+	})
+}
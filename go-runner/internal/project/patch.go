@@ -0,0 +1,247 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errStaleProject is returned when a project changed between loading and
+// saving it
+var errStaleProject = errors.New("project changed since it was loaded")
+
+// projectUpdateColumns are the fields of Project saved by an update, those
+// of UpdateProjectRequest: runtime state such as the status, PID or logs is
+// left to the service manager
+var projectUpdateColumns = func() []string {
+	columns := []string{"Version"}
+	t := reflect.TypeOf(UpdateProjectRequest{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := t.Field(i).Name; name != "Version" && name != "DeclaredPorts" {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}()
+
+// PatchProject godoc
+// @Summary      Partially update a project
+// @Description  Change only the fields present in the body; fields left out or null keep their value, so two clients editing different fields do not undo each other. group_id 0 removes the project from its group. With version (or If-Match) set to the version the changes were made on, the update is refused with 409 and the current project if another change was saved since.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id        path      int                   true   "Project ID"
+// @Param        If-Match  header    string                false  "ETag of the project the changes were made on, e.g. \"3\""
+// @Param        project   body      UpdateProjectRequest  true   "Changed fields"
+// @Success      200       {object}  types.DataResponse{data=Project}  "Updated project"
+// @Failure      400       {object}  middleware.ErrorResponse          "Bad request"
+// @Failure      404       {object}  middleware.ErrorResponse          "Project not found"
+// @Failure      409       {object}  middleware.ErrorResponse          "Changed since the version of the request, with the current project in details"
+// @Failure      422       {object}  middleware.ErrorResponse          "Validation failed, with every failed rule in details"
+// @Failure      500       {object}  middleware.ErrorResponse          "Internal server error"
+// @Router       /projects/{id} [patch]
+func (h *Handler) PatchProject(c *gin.Context) {
+	h.updateProject(c)
+}
+
+// updateProject applies an UpdateProjectRequest to a project, for PUT and
+// PATCH alike
+func (h *Handler) updateProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var req UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request body", err.Error()))
+		return
+	}
+
+	var project Project
+	if err := h.db.First(&project, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			middleware.HandleError(c, middleware.ErrNotFound)
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project", err.Error()))
+		return
+	}
+	before := project
+
+	// Edits made on an older version would undo the changes since
+	var expected uint
+	if req.Version != nil {
+		expected = *req.Version
+	}
+	if !checkProjectVersion(c, &before, expected) {
+		return
+	}
+
+	req.Apply(&project)
+	if !validateProject(c, &project) {
+		return
+	}
+
+	// Declared ports are only replaced when the body includes them
+	var ports []ProjectPort
+	if req.DeclaredPorts != nil {
+		ports = append([]ProjectPort{}, *req.DeclaredPorts...)
+		if err := validateProjectPorts(h.db, before.ID, ports); err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+	}
+
+	h.saveProject(c, &before, &project, ports)
+}
+
+// Apply sets the fields present in the request on a project, each field
+// having the name and type of the Project field behind a pointer. Version
+// and DeclaredPorts are left to the caller.
+func (r *UpdateProjectRequest) Apply(project *Project) {
+	fields := reflect.ValueOf(r).Elem()
+	target := reflect.ValueOf(project).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		name := fields.Type().Field(i).Name
+		if field.IsNil() || name == "Version" || name == "DeclaredPorts" {
+			continue
+		}
+		dest := target.FieldByName(name)
+		switch {
+		case name == "GroupID" && *r.GroupID == 0:
+			project.GroupID = nil
+		case dest.Kind() == reflect.Pointer:
+			value := reflect.New(dest.Type().Elem())
+			value.Elem().Set(field.Elem())
+			dest.Set(value)
+		default:
+			dest.Set(field.Elem())
+		}
+	}
+}
+
+// Project returns the project a create request describes. Group, a group
+// name, is left to the caller.
+func (r *CreateProjectRequest) Project() Project {
+	var project Project
+	fields := reflect.ValueOf(r).Elem()
+	target := reflect.ValueOf(&project).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		dest := target.FieldByName(fields.Type().Field(i).Name)
+		if dest.IsValid() && dest.Type() == fields.Field(i).Type() {
+			dest.Set(fields.Field(i))
+		}
+	}
+	return project
+}
+
+// checkProjectVersion answers 409 with the current project when an update
+// was made on another version than current. The version comes from If-Match,
+// else expected; 0 skips the check.
+func checkProjectVersion(c *gin.Context, current *Project, expected uint) bool {
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && ifMatch != "*" {
+		version, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 32)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid If-Match", ifMatch))
+			return false
+		}
+		expected = uint(version)
+	}
+	if expected == 0 || expected == current.Version {
+		return true
+	}
+	staleProject(c, current, expected)
+	return false
+}
+
+// staleProject answers 409 with the current project
+func staleProject(c *gin.Context, current *Project, expected uint) {
+	c.Header("ETag", projectETag(current.Version))
+	middleware.HandleError(c, middleware.NewError(http.StatusConflict,
+		fmt.Sprintf("Project was changed since version %d, now at version %d", expected, current.Version), current))
+}
+
+// projectETag is the ETag of a project version
+func projectETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// validateProject checks the fields of a project that binding tags do not
+// cover, answering 400 when one is invalid
+func validateProject(c *gin.Context, project *Project) bool {
+	if project.SSHHost != "" {
+		if err := service.ValidateSSHHost(project.SSHHost); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid ssh_host", err.Error()))
+			return false
+		}
+	}
+	if err := service.ValidateWaitFor(project.WaitFor); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid wait_for", err.Error()))
+		return false
+	}
+	if _, err := ParseSmokeTests(project.SmokeTests); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid smoke_tests", err.Error()))
+		return false
+	}
+	if err := service.ValidateRestartStrategy(project.RestartStrategy, project.DrainTimeout); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid restart_strategy", err.Error()))
+		return false
+	}
+	if err := service.ValidateInstances(project.Instances, project.InstancePorts); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid instances", err.Error()))
+		return false
+	}
+	return true
+}
+
+// saveProject saves the changes made to a project as its next version, and
+// its declared ports unless nil. A change saved meanwhile by another request
+// answers 409 instead of being overwritten.
+func (h *Handler) saveProject(c *gin.Context, before, project *Project, ports []ProjectPort) {
+	project.ID = before.ID
+	project.Version = before.Version + 1
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(project).Select(projectUpdateColumns).
+			Where("version = ?", before.Version).Updates(project)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStaleProject
+		}
+		if ports != nil {
+			return replaceProjectPorts(tx, project.ID, ports)
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, errStaleProject) {
+			var current Project
+			if h.db.First(&current, before.ID).Error == nil {
+				staleProject(c, &current, before.Version)
+				return
+			}
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to update project", err.Error()))
+		return
+	}
+
+	h.events.Publish(project.ID, "project_updated", project)
+	NewAnnotator(h.db, h.manager, h.events).ConfigChanged(before, project)
+	// A running project scales out or in right away
+	go h.manager.ScaleInstances(project.ID)
+	c.Header("ETag", projectETag(project.Version))
+	c.JSON(http.StatusOK, types.DataResponse{Data: project})
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PauseRequest optionally limits how long a service stays paused
+type PauseRequest struct {
+	Duration int `json:"duration" binding:"min=0,max=86400"` // Seconds before it is resumed automatically, 0 to stay paused until resumed
+}
+
+// PauseProject godoc
+// @Summary      Pause a project
+// @Description  Freeze the running service of a project with SIGSTOP: its process, their descendants and its extra instances. They keep their memory, state and connections but use no CPU until resumed with POST /projects/{id}/resume, or after duration seconds when given. The project status is "paused" meanwhile; monitors are skipped and stopping or restarting the project resumes it first. Services run through Kubernetes, systemd or SSH cannot be paused.
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int           true   "Project ID"
+// @Param        request  body      PauseRequest  false  "Automatic resume"
+// @Success      200      {object}  types.DataResponse{data=service.ServicePause}  "Project paused"
+// @Failure      400      {object}  middleware.ErrorResponse                       "Bad request"
+// @Failure      409      {object}  middleware.ErrorResponse                       "Service not running or already paused"
+// @Failure      422      {object}  middleware.ErrorResponse                       "No local process to pause"
+// @Router       /projects/{id}/pause [post]
+func (h *Handler) PauseProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+	var req PauseRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	pause, err := h.manager.PauseService(uint(id), time.Duration(req.Duration)*time.Second)
+	switch {
+	case errors.Is(err, service.ErrServiceNotRunning):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is not running", err.Error()))
+		return
+	case errors.Is(err, service.ErrAlreadyPaused):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is already paused", err.Error()))
+		return
+	case errors.Is(err, service.ErrNotPausable):
+		middleware.HandleError(c, middleware.NewError(http.StatusUnprocessableEntity, "No local process to pause", err.Error()))
+		return
+	case err != nil:
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to pause service", err.Error()))
+		return
+	}
+
+	h.events.Publish(uint(id), "status_update", gin.H{
+		"project_id": id,
+		"status":     string(StatusPaused),
+		"message":    "Project paused",
+	})
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: pause})
+}
+
+// ResumeProject godoc
+// @Summary      Resume a paused project
+// @Description  Continue the processes of a project frozen by POST /projects/{id}/pause with SIGCONT; its status goes back to running.
+// @Tags         services
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.ProjectActionResponse  "Project resumed"
+// @Failure      400  {object}  middleware.ErrorResponse     "Bad request"
+// @Failure      409  {object}  middleware.ErrorResponse     "Service is not paused"
+// @Router       /projects/{id}/resume [post]
+func (h *Handler) ResumeProject(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	if !h.manager.ResumeService(uint(id)) {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is not paused", nil))
+		return
+	}
+
+	h.events.Publish(uint(id), "status_update", gin.H{
+		"project_id": id,
+		"status":     string(StatusRunning),
+		"message":    "Project resumed",
+	})
+
+	c.JSON(http.StatusOK, types.ProjectActionResponse{Message: "Project resumed successfully", ProjectID: id})
+}
@@ -0,0 +1,400 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// pm2Timeout bounds node and pm2 calls reading PM2 apps
+const pm2Timeout = 20 * time.Second
+
+// pm2DefaultMaxRestarts is used when PM2 apps keep its default (16), capped to
+// what projects allow
+const pm2DefaultMaxRestarts = 10
+
+// ImportPM2Request imports PM2 apps from one source: an ecosystem file on the
+// server, apps pasted inline (ecosystem apps or `pm2 jlist` output), or the
+// running PM2 daemon
+type ImportPM2Request struct {
+	ConfigPath string            `json:"config_path"` // ecosystem.config.js/.cjs (read with node), .json or .yaml
+	Apps       []json.RawMessage `json:"apps"`        // Ecosystem apps or `pm2 jlist` entries
+	FromPM2    bool              `json:"from_pm2"`    // Run `pm2 jlist` on this host
+	EnvName    string            `json:"env_name"`    // Apply env_<name> over env, e.g. production
+	GroupID    *uint             `json:"group_id"`
+	Type       ServiceType       `json:"type" binding:"omitempty,oneof=backend frontend worker other"` // Project type, backend when empty
+	DryRun     bool              `json:"dry_run"`                                                      // Only return the converted projects
+}
+
+// PM2ImportPreview is the result of a dry run
+type PM2ImportPreview struct {
+	Projects []CreateProjectRequest `json:"projects"`
+	Warnings []string               `json:"warnings"` // PM2 settings without an equivalent
+}
+
+// pm2App is a PM2 app, from an ecosystem file or `pm2 jlist` (pm2_env)
+type pm2App struct {
+	Name             string                 `json:"name"`
+	Script           string                 `json:"script"`
+	Args             interface{}            `json:"args"` // String or list
+	Cwd              string                 `json:"cwd"`
+	Interpreter      string                 `json:"interpreter"`
+	InterpreterArgs  interface{}            `json:"interpreter_args"`
+	NodeArgs         interface{}            `json:"node_args"`
+	Env              map[string]interface{} `json:"env"`
+	Autorestart      *bool                  `json:"autorestart"`
+	MaxRestarts      *int                   `json:"max_restarts"`
+	MaxMemoryRestart interface{}            `json:"max_memory_restart"` // "300M" or bytes
+	Instances        interface{}            `json:"instances"`
+	Watch            interface{}            `json:"watch"`
+	CronRestart      string                 `json:"cron_restart"`
+
+	// `pm2 jlist` names
+	PmExecPath      string `json:"pm_exec_path"`
+	PmCwd           string `json:"pm_cwd"`
+	ExecInterpreter string `json:"exec_interpreter"`
+
+	extra map[string]interface{} // env_<name> sections
+}
+
+// ImportPM2 godoc
+// @Summary      Import PM2 apps
+// @Description  Convert PM2 apps into projects with the equivalent command, working directory, environment (PORT sets the port) and restart settings (autorestart, max_restarts capped to 10, max_memory_restart as memory limit). Apps come from an ecosystem file on the server (ecosystem.config.js is evaluated with node), from the body (ecosystem apps or `pm2 jlist` output) or from `pm2 jlist` on this host. The import then runs like POST /projects/import (existing projects with the same name are updated). With dry_run the converted projects and the PM2 settings that have no equivalent (cluster instances, watch, cron_restart) are returned instead.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ImportPM2Request  true  "PM2 source"
+// @Success      200      {object}  types.DataMessageResponse{data=ImportResult}  "Import result"
+// @Success      202      {object}  types.DataMessageResponse{data=go-runner_internal_jobs.Job}      "Import still running; poll GET /jobs/{id}"
+// @Failure      400      {object}  middleware.ErrorResponse                      "Bad request"
+// @Failure      409      {object}  middleware.ErrorResponse                      "Another import is running"
+// @Router       /projects/import/pm2 [post]
+func (h *Handler) ImportPM2(c *gin.Context) {
+	var req ImportPM2Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	apps, baseDir, err := loadPM2Apps(c.Request.Context(), req)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Failed to read PM2 apps", err.Error()))
+		return
+	}
+	if len(apps) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "No PM2 apps found", nil))
+		return
+	}
+
+	preview := PM2ImportPreview{Projects: []CreateProjectRequest{}, Warnings: []string{}}
+	for _, app := range apps {
+		project, warnings := convertPM2App(app, baseDir, req)
+		preview.Projects = append(preview.Projects, project)
+		preview.Warnings = append(preview.Warnings, warnings...)
+	}
+
+	if req.DryRun {
+		c.JSON(http.StatusOK, types.DataResponse{Data: preview})
+		return
+	}
+	h.runImport(c, ImportProjectsRequest{Projects: preview.Projects}, nil)
+}
+
+// loadPM2Apps reads the apps of the requested source and the directory
+// relative paths are resolved against
+func loadPM2Apps(ctx context.Context, req ImportPM2Request) ([]pm2App, string, error) {
+	var raw []json.RawMessage
+	baseDir, _ := os.Getwd()
+
+	switch {
+	case req.ConfigPath != "":
+		path, err := filepath.Abs(req.ConfigPath)
+		if err != nil {
+			return nil, "", err
+		}
+		baseDir = filepath.Dir(path)
+		if raw, err = readEcosystemFile(ctx, path); err != nil {
+			return nil, "", err
+		}
+	case len(req.Apps) > 0:
+		raw = req.Apps
+	case req.FromPM2:
+		ctx, cancel := context.WithTimeout(ctx, pm2Timeout)
+		defer cancel()
+		out, err := exec.CommandContext(ctx, "pm2", "jlist").Output()
+		if err != nil {
+			return nil, "", fmt.Errorf("pm2 jlist: %v", err)
+		}
+		if err := json.Unmarshal(out, &raw); err != nil {
+			return nil, "", fmt.Errorf("invalid pm2 jlist output: %v", err)
+		}
+	default:
+		return nil, "", fmt.Errorf("one of config_path, apps or from_pm2 is required")
+	}
+
+	apps := make([]pm2App, 0, len(raw))
+	for _, r := range raw {
+		app, err := parsePM2App(r)
+		if err != nil {
+			return nil, "", err
+		}
+		apps = append(apps, app)
+	}
+	return apps, baseDir, nil
+}
+
+// readEcosystemFile returns the apps of an ecosystem file. JavaScript files
+// are evaluated with node since they may compute their settings.
+func readEcosystemFile(ctx context.Context, path string) ([]json.RawMessage, error) {
+	var content []byte
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".js", ".cjs", ".mjs":
+		ctx, cancel := context.WithTimeout(ctx, pm2Timeout)
+		defer cancel()
+		script := `Promise.resolve(import(require("url").pathToFileURL(process.argv[1]).href)).then(m => process.stdout.write(JSON.stringify(m.default || m)))`
+		content, err = exec.CommandContext(ctx, "node", "-e", script, path).Output()
+		if err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+				return nil, fmt.Errorf("node: %s", strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return nil, fmt.Errorf("node: %v", err)
+		}
+	case ".yaml", ".yml":
+		var data interface{}
+		if content, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(content, &data); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %v", err)
+		}
+		if content, err = json.Marshal(data); err != nil {
+			return nil, err
+		}
+	default:
+		if content, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	// Either {"apps": [...]}, a list of apps or a single app
+	var ecosystem struct {
+		Apps []json.RawMessage `json:"apps"`
+	}
+	if err := json.Unmarshal(content, &ecosystem); err == nil && len(ecosystem.Apps) > 0 {
+		return ecosystem.Apps, nil
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(content, &list); err == nil {
+		return list, nil
+	}
+	var single json.RawMessage
+	if err := json.Unmarshal(content, &single); err != nil {
+		return nil, fmt.Errorf("invalid ecosystem file: %v", err)
+	}
+	return []json.RawMessage{single}, nil
+}
+
+// parsePM2App reads an ecosystem app or a `pm2 jlist` entry, whose settings
+// are under pm2_env
+func parsePM2App(raw json.RawMessage) (pm2App, error) {
+	var entry struct {
+		Name   string          `json:"name"`
+		PM2Env json.RawMessage `json:"pm2_env"`
+	}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return pm2App{}, fmt.Errorf("invalid PM2 app: %v", err)
+	}
+	if len(entry.PM2Env) > 0 {
+		raw = entry.PM2Env
+	}
+
+	var app pm2App
+	if err := json.Unmarshal(raw, &app); err != nil {
+		return pm2App{}, fmt.Errorf("invalid PM2 app: %v", err)
+	}
+	if err := json.Unmarshal(raw, &app.extra); err != nil {
+		return pm2App{}, fmt.Errorf("invalid PM2 app: %v", err)
+	}
+	if app.Name == "" {
+		app.Name = entry.Name
+	}
+	if app.Script == "" {
+		app.Script = app.PmExecPath
+	}
+	if app.Cwd == "" {
+		app.Cwd = app.PmCwd
+	}
+	if app.Interpreter == "" {
+		app.Interpreter = app.ExecInterpreter
+	}
+	if app.Name == "" && app.Script != "" {
+		app.Name = strings.TrimSuffix(filepath.Base(app.Script), filepath.Ext(app.Script))
+	}
+	if app.Name == "" {
+		return pm2App{}, fmt.Errorf("PM2 app without name or script")
+	}
+	return app, nil
+}
+
+// convertPM2App maps a PM2 app to a project and lists the settings that were
+// dropped
+func convertPM2App(app pm2App, baseDir string, req ImportPM2Request) (CreateProjectRequest, []string) {
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, app.Name+": "+fmt.Sprintf(format, args...))
+	}
+
+	cwd := app.Cwd
+	if cwd == "" {
+		cwd = baseDir
+	} else if !filepath.IsAbs(cwd) {
+		cwd = filepath.Join(baseDir, cwd)
+	}
+
+	project := CreateProjectRequest{
+		Name:        app.Name,
+		Description: "Imported from PM2",
+		Type:        req.Type,
+		GroupID:     req.GroupID,
+		Path:        cwd,
+		Environment: "development",
+		AutoRestart: app.Autorestart == nil || *app.Autorestart,
+		MaxRestarts: pm2DefaultMaxRestarts,
+	}
+	if project.Type == "" {
+		project.Type = TypeBackend
+	}
+
+	// Command: interpreter, its arguments and the script
+	var command []string
+	interpreter := app.Interpreter
+	switch {
+	case interpreter == "none":
+		interpreter = ""
+	case interpreter == "":
+		switch filepath.Ext(app.Script) {
+		case ".js", ".cjs", ".mjs":
+			interpreter = "node"
+		case ".py":
+			interpreter = "python3"
+		}
+	}
+	if interpreter != "" {
+		command = append(command, interpreter)
+		command = append(command, pm2Args(app.InterpreterArgs)...)
+		command = append(command, pm2Args(app.NodeArgs)...)
+	}
+	command = append(command, app.Script)
+	project.Command = strings.Join(command, " ")
+	project.Args = strings.Join(pm2Args(app.Args), " ")
+
+	// Environment, with env_<name> over env
+	env := make(map[string]string)
+	for k, v := range app.Env {
+		env[k] = fmt.Sprint(v)
+	}
+	if req.EnvName != "" {
+		if section, ok := app.extra["env_"+req.EnvName].(map[string]interface{}); ok {
+			for k, v := range section {
+				env[k] = fmt.Sprint(v)
+			}
+		} else {
+			warn("no env_%s section", req.EnvName)
+		}
+		switch req.EnvName {
+		case "development", "staging", "production":
+			project.Environment = req.EnvName
+		}
+	}
+	for k, v := range env {
+		// `pm2 jlist` merges the daemon environment into env
+		if strings.HasPrefix(k, "PM2_") || strings.HasPrefix(k, "pm_") || os.Getenv(k) == v {
+			delete(env, k)
+		}
+	}
+	if port, err := strconv.Atoi(env["PORT"]); err == nil && port > 0 && port <= 65535 {
+		project.Port = port
+	}
+	if len(env) > 0 {
+		data, _ := json.Marshal(env)
+		project.EnvVars = string(data)
+	}
+
+	// Restart settings
+	if app.MaxRestarts != nil {
+		project.MaxRestarts = *app.MaxRestarts
+		if project.MaxRestarts > 10 {
+			warn("max_restarts %d capped to 10", *app.MaxRestarts)
+			project.MaxRestarts = 10
+		}
+	}
+	if app.MaxMemoryRestart != nil {
+		project.MemoryLimit = pm2Memory(app.MaxMemoryRestart)
+	}
+
+	// No equivalent
+	if instances := fmt.Sprint(app.Instances); app.Instances != nil && instances != "1" && instances != "0" {
+		warn("instances %s not supported, runs a single process", instances)
+	}
+	if watch, ok := app.Watch.(bool); (ok && watch) || (app.Watch != nil && !ok) {
+		warn("watch not supported")
+	}
+	if app.CronRestart != "" {
+		warn("cron_restart %q not supported", app.CronRestart)
+	}
+	if app.Script == "" {
+		warn("no script")
+	}
+	return project, warnings
+}
+
+// pm2Args returns PM2 arguments given as a string or a list
+func pm2Args(v interface{}) []string {
+	switch args := v.(type) {
+	case string:
+		return strings.Fields(args)
+	case []interface{}:
+		out := make([]string, 0, len(args))
+		for _, a := range args {
+			out = append(out, fmt.Sprint(a))
+		}
+		return out
+	}
+	return nil
+}
+
+// pm2Memory converts max_memory_restart ("300M", "1G" or bytes) to a
+// memory limit such as "300Mi"
+func pm2Memory(v interface{}) string {
+	switch limit := v.(type) {
+	case float64:
+		return strconv.Itoa(int(limit/(1<<20))) + "Mi"
+	case string:
+		limit = strings.TrimSpace(strings.ToUpper(limit))
+		for suffix, unit := range map[string]string{"K": "Ki", "M": "Mi", "G": "Gi"} {
+			if strings.HasSuffix(limit, suffix) {
+				return strings.TrimSuffix(limit, suffix) + unit
+			}
+		}
+		if bytes, err := strconv.Atoi(limit); err == nil {
+			return strconv.Itoa(bytes/(1<<20)) + "Mi"
+		}
+		return limit
+	}
+	return ""
+}
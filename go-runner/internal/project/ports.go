@@ -0,0 +1,273 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ProjectPortRequest describes one declared port in a request body
+type ProjectPortRequest struct {
+	Name     string       `json:"name" binding:"max=50"`
+	Number   int          `json:"number" binding:"required,min=1,max=65535"`
+	Protocol PortProtocol `json:"protocol" binding:"omitempty,oneof=tcp udp"`
+	Public   bool         `json:"public"`
+}
+
+// UpdateProjectPortsRequest replaces the declared ports of a project
+type UpdateProjectPortsRequest struct {
+	Ports []ProjectPortRequest `json:"ports" binding:"dive"`
+}
+
+// PortConflict describes a declared port already owned by another project
+type PortConflict struct {
+	Number      int          `json:"number"`
+	Protocol    PortProtocol `json:"protocol"`
+	ProjectID   uint         `json:"project_id"`
+	ProjectName string       `json:"project_name"`
+	PortName    string       `json:"port_name,omitempty"`
+}
+
+// GetProjectPorts godoc
+// @Summary      Get declared ports
+// @Description  List the ports declared by a project with whether each one is currently listening
+// @Tags         ports
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=[]service.DeclaredPortStatus}  "Declared ports"
+// @Failure      400  {object}  middleware.ErrorResponse                               "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse                               "Project not found"
+// @Router       /projects/{id}/ports [get]
+func (h *Handler) GetProjectPorts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	var statuses []service.DeclaredPortStatus = h.manager.GetDeclaredPortStatuses(project.ID)
+	c.JSON(http.StatusOK, types.DataResponse{Data: statuses})
+}
+
+// UpdateProjectPorts godoc
+// @Summary      Replace declared ports
+// @Description  Replace the declared ports of a project. Ports already declared by another project are rejected with 409.
+// @Tags         ports
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Project ID"
+// @Param        request  body      UpdateProjectPortsRequest  true  "Declared ports"
+// @Success      200      {object}  types.DataResponse{data=[]ProjectPort}  "Updated ports"
+// @Failure      400      {object}  middleware.ErrorResponse                "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse                "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse{details=[]PortConflict}  "Port owned by another project"
+// @Failure      500      {object}  middleware.ErrorResponse                "Internal server error"
+// @Router       /projects/{id}/ports [put]
+func (h *Handler) UpdateProjectPorts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var req UpdateProjectPortsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid ports", err.Error()))
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	ports := make([]ProjectPort, 0, len(req.Ports))
+	for _, p := range req.Ports {
+		ports = append(ports, ProjectPort{
+			Name:     p.Name,
+			Number:   p.Number,
+			Protocol: p.Protocol,
+			Public:   p.Public,
+		})
+	}
+
+	if err := validateProjectPorts(h.db, project.ID, ports); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		return replaceProjectPorts(tx, project.ID, ports)
+	}); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to update ports", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: ports})
+}
+
+// validateProjectPorts normalizes declared ports in place and checks them for
+// invalid values, duplicates and conflicts with ports owned by other projects
+func validateProjectPorts(db *gorm.DB, projectID uint, ports []ProjectPort) error {
+	seen := make(map[string]bool)
+	for i := range ports {
+		p := &ports[i]
+		p.Name = strings.TrimSpace(p.Name)
+		p.Protocol = PortProtocol(strings.ToLower(string(p.Protocol)))
+		if p.Protocol == "" {
+			p.Protocol = ProtocolTCP
+		}
+
+		if p.Number < 1 || p.Number > 65535 {
+			return middleware.NewError(http.StatusBadRequest, "Invalid port", fmt.Sprintf("port %d must be between 1 and 65535", p.Number))
+		}
+		if p.Protocol != ProtocolTCP && p.Protocol != ProtocolUDP {
+			return middleware.NewError(http.StatusBadRequest, "Invalid port", fmt.Sprintf("protocol %q must be tcp or udp", p.Protocol))
+		}
+
+		key := fmt.Sprintf("%d/%s", p.Number, p.Protocol)
+		if seen[key] {
+			return middleware.NewError(http.StatusBadRequest, "Invalid port", fmt.Sprintf("port %s is declared more than once", key))
+		}
+		seen[key] = true
+	}
+
+	conflicts := findPortConflicts(db, projectID, ports)
+	if len(conflicts) > 0 {
+		c := conflicts[0]
+		return middleware.NewError(http.StatusConflict,
+			fmt.Sprintf("Port %d/%s is already used by project %q", c.Number, c.Protocol, c.ProjectName),
+			conflicts)
+	}
+	return nil
+}
+
+// findPortConflicts returns the declared ports already owned by other
+// projects, either as a declared port or as their primary (TCP) port
+func findPortConflicts(db *gorm.DB, projectID uint, ports []ProjectPort) []PortConflict {
+	var conflicts []PortConflict
+
+	for _, p := range ports {
+		var declared []PortConflict
+		db.Table(tables.From("project_ports")).
+			Select("project_ports.number, project_ports.protocol, project_ports.project_id, projects.name AS project_name, project_ports.name AS port_name").
+			Joins("JOIN "+tables.From("projects")+" ON projects.id = project_ports.project_id AND projects.deleted_at IS NULL").
+			Where("project_ports.number = ? AND project_ports.protocol = ? AND project_ports.project_id <> ?", p.Number, p.Protocol, projectID).
+			Find(&declared)
+		conflicts = append(conflicts, declared...)
+
+		if p.Protocol != ProtocolTCP {
+			continue
+		}
+		var primary []Project
+		db.Select("id, name").Where("port = ? AND id <> ?", p.Number, projectID).Find(&primary)
+		for _, owner := range primary {
+			conflicts = append(conflicts, PortConflict{
+				Number:      p.Number,
+				Protocol:    ProtocolTCP,
+				ProjectID:   owner.ID,
+				ProjectName: owner.Name,
+			})
+		}
+	}
+
+	return conflicts
+}
+
+// replaceProjectPorts swaps the declared ports of a project for the given set
+func replaceProjectPorts(tx *gorm.DB, projectID uint, ports []ProjectPort) error {
+	if err := tx.Where("project_id = ?", projectID).Delete(&ProjectPort{}).Error; err != nil {
+		return err
+	}
+	for i := range ports {
+		ports[i].ID = 0
+		ports[i].ProjectID = projectID
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	return tx.Create(&ports).Error
+}
+
+// MigrateLegacyPorts converts the deprecated Ports JSON field into declared
+// ports for projects that don't have any yet. Both [3000, 3001] and
+// [{"name": "http", "port": 3000}] forms are understood.
+func MigrateLegacyPorts(db *gorm.DB) {
+	var projects []Project
+	if err := db.Select("id, name, ports").Where("ports <> ''").Find(&projects).Error; err != nil {
+		return
+	}
+
+	for _, project := range projects {
+		var count int64
+		db.Model(&ProjectPort{}).Where("project_id = ?", project.ID).Count(&count)
+		if count > 0 {
+			continue
+		}
+
+		ports := parseLegacyPorts(project.Ports)
+		if len(ports) == 0 {
+			continue
+		}
+		if err := validateProjectPorts(db, project.ID, ports); err != nil {
+			log.Printf("⚠️  Skipping legacy ports of project %q: %v", project.Name, err)
+			continue
+		}
+		if err := replaceProjectPorts(db, project.ID, ports); err != nil {
+			log.Printf("⚠️  Failed to migrate legacy ports of project %q: %v", project.Name, err)
+			continue
+		}
+		log.Printf("✅ Migrated %d legacy port(s) of project %q", len(ports), project.Name)
+	}
+}
+
+// parseLegacyPorts parses the deprecated Ports JSON field
+func parseLegacyPorts(raw string) []ProjectPort {
+	var numbers []int
+	if err := json.Unmarshal([]byte(raw), &numbers); err == nil {
+		ports := make([]ProjectPort, 0, len(numbers))
+		for _, n := range numbers {
+			ports = append(ports, ProjectPort{Number: n, Protocol: ProtocolTCP})
+		}
+		return ports
+	}
+
+	var objects []struct {
+		Name     string       `json:"name"`
+		Port     int          `json:"port"`
+		Number   int          `json:"number"`
+		Protocol PortProtocol `json:"protocol"`
+		Public   bool         `json:"public"`
+	}
+	if err := json.Unmarshal([]byte(raw), &objects); err != nil {
+		return nil
+	}
+
+	ports := make([]ProjectPort, 0, len(objects))
+	for _, o := range objects {
+		number := o.Number
+		if number == 0 {
+			number = o.Port
+		}
+		ports = append(ports, ProjectPort{Name: o.Name, Number: number, Protocol: o.Protocol, Public: o.Public})
+	}
+	return ports
+}
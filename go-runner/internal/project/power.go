@@ -0,0 +1,54 @@
+package project
+
+import (
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetPowerModeRequest switches low-power mode
+type SetPowerModeRequest struct {
+	Mode string `json:"mode" binding:"required" example:"on"` // auto, on, off
+}
+
+// GetPowerMode godoc
+// @Summary      Get low-power mode
+// @Description  Report whether low-power mode is active, the configured mode (auto follows the host power source, on and off force it), whether the host runs on battery and the optional projects suspended. In low-power mode metrics collection, alert checks, database, queue and file descriptor monitors and the watch_files refresh wait interval_factor times longer.
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=go-runner_internal_service.PowerStatus}
+// @Router       /system/power [get]
+func (h *Handler) GetPowerMode(c *gin.Context) {
+	c.JSON(http.StatusOK, types.DataResponse{Data: h.manager.PowerStatus()})
+}
+
+// SetPowerMode godoc
+// @Summary      Set low-power mode
+// @Description  Turn low-power mode on or off, or back to auto to follow the power source. Entering it suspends the running projects marked optional, leaving it resumes them. Lasts until go-runner restarts; set power.mode in config.yaml to keep it.
+// @Tags         system
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SetPowerModeRequest  true  "Mode"
+// @Success      200      {object}  types.DataMessageResponse{data=go-runner_internal_service.PowerStatus}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid mode"
+// @Router       /system/power [put]
+func (h *Handler) SetPowerMode(c *gin.Context) {
+	var req SetPowerModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	status, err := h.manager.SetPowerMode(req.Mode)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid power mode", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Data:    status,
+		Message: "Power mode set to " + status.Mode,
+	})
+}
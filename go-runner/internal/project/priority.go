@@ -0,0 +1,101 @@
+package project
+
+import (
+	"net/http"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetPriorityRequest changes the priority of a project; fields left out
+// keep their value
+type SetPriorityRequest struct {
+	Nice        *int    `json:"nice"`         // -20 (highest) to 19 (lowest); below 0 needs root
+	IOClass     *string `json:"io_class"`     // realtime, best-effort, idle, or empty for the default
+	IOPriority  *int    `json:"io_priority"`  // 0 (highest) to 7 within realtime and best-effort
+	CPUAffinity *string `json:"cpu_affinity"` // CPU cores such as "0-3,8", or empty for all
+}
+
+// GetPriority godoc
+// @Summary      Get the CPU and IO priority of a project
+// @Description  Get the nice value, IO scheduling class (ionice) and CPU affinity (taskset) of a project, applied at each start, and the ones the processes of its running tree have
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.PriorityStatus}
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/priority [get]
+func (h *Handler) GetPriority(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: h.manager.PriorityStatus(project.ID)})
+}
+
+// SetPriority godoc
+// @Summary      Change the CPU and IO priority of a project
+// @Description  Save the nice value, IO scheduling class and CPU affinity of a project and apply them right away to its running process tree with renice, ionice and taskset, so a background worker can be slowed down or pinned to a few cores during a big build without a restart. Raising a lowered priority again, a negative nice and the realtime class need root (CAP_SYS_NICE); failures to apply are listed in errors, the priority is saved for the next start anyway. ionice and taskset are only available on Linux.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                 true  "Project ID"
+// @Param        request  body      SetPriorityRequest  true  "Priority"
+// @Success      200      {object}  types.DataResponse{data=service.PriorityStatus}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid priority"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/priority [put]
+func (h *Handler) SetPriority(c *gin.Context) {
+	var req SetPriorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	var project Project
+	if err := h.db.First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	prio := service.Priority{Nice: project.Nice, IOClass: project.IOClass, IOPriority: project.IOPriority, CPUAffinity: project.CPUAffinity}
+	if req.Nice != nil {
+		prio.Nice = *req.Nice
+	}
+	if req.IOClass != nil {
+		prio.IOClass = *req.IOClass
+	}
+	if req.IOPriority != nil {
+		prio.IOPriority = *req.IOPriority
+	}
+	if req.CPUAffinity != nil {
+		prio.CPUAffinity = strings.ReplaceAll(*req.CPUAffinity, " ", "")
+	}
+	if err := prio.Validate(); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid priority", err.Error()))
+		return
+	}
+
+	if err := h.db.Model(&project).Updates(map[string]interface{}{
+		"nice":         prio.Nice,
+		"io_class":     prio.IOClass,
+		"io_priority":  prio.IOPriority,
+		"cpu_affinity": prio.CPUAffinity,
+	}).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to save priority", err.Error()))
+		return
+	}
+	status, err := h.manager.SetPriority(project.ID, prio)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid priority", err.Error()))
+		return
+	}
+
+	project.Nice, project.IOClass, project.IOPriority, project.CPUAffinity = prio.Nice, prio.IOClass, prio.IOPriority, prio.CPUAffinity
+	h.events.Publish(project.ID, "project_updated", project)
+	c.JSON(http.StatusOK, types.DataResponse{Data: status})
+}
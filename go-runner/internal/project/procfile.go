@@ -0,0 +1,228 @@
+package project
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// procfileBasePort is foreman's default first port; each process type gets
+// the next hundred
+const procfileBasePort = 5000
+
+// procfileLine matches "type: command" like foreman does
+var procfileLine = regexp.MustCompile(`^([A-Za-z0-9_-]+):\s*(.+)$`)
+
+// procfileInvalid matches characters not allowed in process types
+var procfileInvalid = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// shellSyntax marks commands that need a shell ($PORT, &&, pipes, redirects, quotes)
+var shellSyntax = regexp.MustCompile("[$&|;<>`'\"*?(){}]")
+
+// ImportProcfileRequest imports the process types of a Procfile
+type ImportProcfileRequest struct {
+	Path      string `json:"path" binding:"required"`                       // Procfile, or the directory holding it
+	GroupName string `json:"group_name"`                                    // Group of the projects, the directory name when empty
+	BasePort  int    `json:"base_port" binding:"omitempty,min=1,max=65000"` // PORT of the first process type, 5000 when empty
+	DryRun    bool   `json:"dry_run"`                                       // Only return the converted projects
+}
+
+// ProcfileImportPreview is the result of a dry run
+type ProcfileImportPreview struct {
+	Group    string                 `json:"group"`
+	Projects []CreateProjectRequest `json:"projects"`
+}
+
+// ImportProcfile godoc
+// @Summary      Import a Procfile
+// @Description  Turn each process type of a Procfile into a project named <group>-<type>, in a group named after the directory (created if missing). Like foreman, process types get PORT = base_port + 100 × their position (5000, 5100, ...), set as port and in env_vars so it wins over .env, which is loaded from the directory as usual. Commands using shell syntax such as $PORT run through sh -c. The import then runs like POST /projects/import (existing projects with the same name are updated).
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ImportProcfileRequest  true  "Procfile to import"
+// @Success      200      {object}  types.DataMessageResponse{data=ImportResult}  "Import result, or ProcfileImportPreview with dry_run"
+// @Success      202      {object}  types.DataMessageResponse{data=go-runner_internal_jobs.Job}  "Import still running; poll GET /jobs/{id}"
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      409      {object}  middleware.ErrorResponse  "Another import is running"
+// @Router       /projects/import/procfile [post]
+func (h *Handler) ImportProcfile(c *gin.Context) {
+	var req ImportProcfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	path, err := filepath.Abs(req.Path)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid path", err.Error()))
+		return
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "Procfile")
+	}
+	processes, err := parseProcfile(path)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Failed to read Procfile", err.Error()))
+		return
+	}
+	if len(processes) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "No process types in Procfile", path))
+		return
+	}
+
+	dir := filepath.Dir(path)
+	groupName := req.GroupName
+	if groupName == "" {
+		groupName = filepath.Base(dir)
+	}
+	basePort := req.BasePort
+	if basePort == 0 {
+		basePort = procfileBasePort
+	}
+
+	preview := ProcfileImportPreview{Group: groupName, Projects: []CreateProjectRequest{}}
+	for i, process := range processes {
+		port := basePort + i*100
+		env, _ := json.Marshal(map[string]string{"PORT": fmt.Sprint(port)})
+
+		command := process.command
+		if shellSyntax.MatchString(command) {
+			command = "sh -c " + command
+		}
+		projectType := TypeWorker
+		if process.name == "web" {
+			projectType = TypeBackend
+		}
+
+		preview.Projects = append(preview.Projects, CreateProjectRequest{
+			Name:        groupName + "-" + process.name,
+			Description: "Procfile process " + process.name,
+			Type:        projectType,
+			Path:        dir,
+			Command:     command,
+			Port:        port,
+			EnvVars:     string(env),
+			Environment: "development",
+		})
+	}
+
+	if req.DryRun {
+		c.JSON(http.StatusOK, types.DataResponse{Data: preview})
+		return
+	}
+
+	var group ProjectGroup
+	if err := h.db.Where("name = ?", groupName).First(&group).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch group", err.Error()))
+			return
+		}
+		group = ProjectGroup{Name: groupName, Description: "Imported from " + path}
+		if err := h.db.Create(&group).Error; err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to create group", err.Error()))
+			return
+		}
+	}
+	for i := range preview.Projects {
+		preview.Projects[i].GroupID = &group.ID
+	}
+
+	h.runImport(c, ImportProjectsRequest{Projects: preview.Projects}, nil)
+}
+
+// procfileProcess is a process type of a Procfile
+type procfileProcess struct {
+	name    string
+	command string
+}
+
+// parseProcfile reads the process types of a Procfile in order
+func parseProcfile(path string) ([]procfileProcess, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var processes []procfileProcess
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		match := procfileLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		processes = append(processes, procfileProcess{name: match[1], command: strings.TrimSpace(match[2])})
+	}
+	return processes, scanner.Err()
+}
+
+// ExportProcfile godoc
+// @Summary      Export a group as a Procfile
+// @Description  Write the projects of a group as a Procfile, one process type per project (the project name without the "<group>-" prefix added by the Procfile import). Projects without a command are listed as comments. Add download=true to get it as an attachment.
+// @Tags         groups
+// @Produce      plain
+// @Param        id        path      int   true   "Group ID"
+// @Param        download  query     bool  false  "Send as a Procfile attachment"
+// @Success      200       {string}  string  "Procfile"
+// @Failure      404       {object}  middleware.ErrorResponse  "Group not found"
+// @Router       /groups/{id}/procfile [get]
+func (h *Handler) ExportProcfile(c *gin.Context) {
+	var group ProjectGroup
+	if err := h.db.First(&group, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	var projects []Project
+	if err := h.db.Select("id, name, command, args").Where("group_id = ?", group.ID).Order("id").Find(&projects).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
+		return
+	}
+
+	var b strings.Builder
+	seen := make(map[string]bool)
+	for _, p := range projects {
+		name := procfileName(strings.TrimPrefix(p.Name, group.Name+"-"))
+		for base, i := name, 2; seen[name]; i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+		}
+		seen[name] = true
+
+		command := strings.TrimSpace(p.Command + " " + p.Args)
+		for _, shell := range []string{"sh -c ", "bash -c "} {
+			command = strings.TrimPrefix(command, shell)
+		}
+		if command == "" {
+			fmt.Fprintf(&b, "# %s: no command\n", name)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", name, command)
+	}
+
+	if c.Query("download") == "true" {
+		c.Header("Content-Disposition", `attachment; filename="Procfile"`)
+	}
+	c.String(http.StatusOK, b.String())
+}
+
+// procfileName turns a project name into a process type
+func procfileName(name string) string {
+	name = procfileInvalid.ReplaceAllString(strings.ToLower(name), "_")
+	if name == "" {
+		return "process"
+	}
+	return name
+}
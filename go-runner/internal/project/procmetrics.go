@@ -0,0 +1,111 @@
+package project
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// processMetricsRetention is how long process samples are kept
+const processMetricsRetention = 7 * 24 * time.Hour
+
+// ProcessMetric is the CPU, memory, disk I/O and TCP traffic of the process
+// tree of a running project at one point in time
+type ProcessMetric struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProjectID   uint      `json:"project_id" gorm:"index:,composite:lookup"`
+	Timestamp   time.Time `json:"timestamp" gorm:"index:,composite:lookup"`
+	CPUPercent  *float64  `json:"cpu_percent"` // Of one core since the previous sample, null for the first sample of a process
+	MemoryBytes uint64    `json:"memory_bytes"`
+	Processes   int       `json:"processes"` // Size of the process tree
+
+	// Bytes read from and written to disk per second since the previous
+	// sample, null for the first sample of a process
+	DiskReadBytesPerSec  *float64 `json:"disk_read_bytes_per_sec"`
+	DiskWriteBytesPerSec *float64 `json:"disk_write_bytes_per_sec"`
+
+	// TCP bytes sent and received per second since the previous sample, null
+	// for the first sample of a process or where sockets cannot be
+	// attributed to processes
+	NetSentBytesPerSec     *float64 `json:"net_sent_bytes_per_sec"`
+	NetReceivedBytesPerSec *float64 `json:"net_received_bytes_per_sec"`
+}
+
+// ProcessMetricRecorder stores CPU, memory, disk and network samples of
+// running projects
+type ProcessMetricRecorder struct {
+	db *gorm.DB
+}
+
+// NewProcessMetricRecorder creates a process metric recorder
+func NewProcessMetricRecorder(db *gorm.DB) *ProcessMetricRecorder {
+	return &ProcessMetricRecorder{db: db}
+}
+
+// Record stores a sample and deletes the samples of the project past the
+// retention period
+func (r *ProcessMetricRecorder) Record(sample service.ProcessSample) {
+	metric := ProcessMetric{
+		ProjectID:   sample.ProjectID,
+		Timestamp:   sample.Time,
+		CPUPercent:  sample.CPUPercent,
+		MemoryBytes: sample.MemoryRSS,
+		Processes:   sample.Processes,
+
+		DiskReadBytesPerSec:  sample.DiskIO.ReadBytesPerSec,
+		DiskWriteBytesPerSec: sample.DiskIO.WriteBytesPerSec,
+	}
+	if sample.Network != nil {
+		metric.NetSentBytesPerSec = sample.Network.SentBytesPerSec
+		metric.NetReceivedBytesPerSec = sample.Network.ReceivedBytesPerSec
+	}
+	if err := r.db.Create(&metric).Error; err != nil {
+		log.Printf("Failed to store process metric: %v", err)
+	}
+
+	r.db.Where("project_id = ? AND timestamp < ?", sample.ProjectID, time.Now().Add(-processMetricsRetention)).Delete(&ProcessMetric{})
+}
+
+// GetProcessMetrics godoc
+// @Summary      Get process metrics history
+// @Description  Get the samples of the process tree of a project taken every 30 seconds while it runs, oldest first: CPU, memory, number of processes and the bytes per second it reads from and writes to disk (storage I/O, not page cache hits), and the TCP bytes per second it sends and receives, to chart which service is busy, thrashing the disk or using the network. TCP traffic is attributed on Linux by matching the sockets the processes hold to the counters of the kernel; connections opened and closed between two samples are missed. Samples are kept for 7 days.
+// @Tags         projects
+// @Produce      json
+// @Param        id     path      int  true   "Project ID"
+// @Param        hours  query     int  false  "Hours of history (default 1, max 168)"
+// @Success      200    {object}  types.DataResponse{data=[]ProcessMetric}
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /projects/{id}/process/metrics [get]
+func (h *Handler) GetProcessMetrics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	hours := 1
+	if raw := c.Query("hours"); raw != "" {
+		hours, err = strconv.Atoi(raw)
+		if err != nil || hours < 1 || hours > 168 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "hours must be between 1 and 168", raw))
+			return
+		}
+	}
+
+	metrics := []ProcessMetric{}
+	if err := h.db.Where("project_id = ? AND timestamp >= ?", id, time.Now().Add(-time.Duration(hours)*time.Hour)).
+		Order("timestamp asc").Find(&metrics).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch process metrics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: metrics})
+}
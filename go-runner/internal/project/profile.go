@@ -0,0 +1,346 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/profiling"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// profileHistory is the number of profiles kept per project
+const profileHistory = 20
+
+// maxProfileDownload bounds the size of a fetched profile
+const maxProfileDownload = 64 << 20
+
+// Profile types, each served by net/http/pprof under its name
+const (
+	ProfileCPU       = "cpu" // /debug/pprof/profile?seconds=N
+	ProfileHeap      = "heap"
+	ProfileAllocs    = "allocs"
+	ProfileGoroutine = "goroutine"
+)
+
+// ProjectProfile is a pprof profile captured from a project
+type ProjectProfile struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"created_at"`
+	ProjectID     uint      `json:"project_id" gorm:"index;not null"`
+	JobID         uint      `json:"job_id"`
+	Type          string    `json:"type"`    // cpu, heap, allocs, goroutine
+	Seconds       int       `json:"seconds"` // CPU profiling duration
+	URL           string    `json:"url"`
+	Size          int       `json:"size"`        // Bytes of the gzipped profile
+	SampleType    string    `json:"sample_type"` // e.g. cpu/nanoseconds, inuse_space/bytes
+	Samples       int       `json:"samples"`
+	Total         int64     `json:"total"` // Sum of the sample values, in the sample type unit
+	HasFlameGraph bool      `json:"has_flame_graph"`
+	Data          []byte    `json:"-"`
+	FlameGraph    []byte    `json:"-"`
+}
+
+// ProfileRequest selects the profile to capture
+type ProfileRequest struct {
+	Type       string `json:"type"`       // cpu (default), heap, allocs or goroutine
+	Seconds    int    `json:"seconds"`    // CPU profiling duration, default 10, max 300
+	FlameGraph bool   `json:"flamegraph"` // Render the flame graph right away
+}
+
+// CaptureProfile godoc
+// @Summary      Capture a pprof profile
+// @Description  Fetch a CPU, heap, allocs or goroutine profile from a running Go project exposing net/http/pprof, as a background job, and store it. The pprof base URL is pprof_url, or http://127.0.0.1:<port>/debug/pprof. With flamegraph the SVG flame graph is rendered as well; it is otherwise rendered on first request. The job result is the stored ProjectProfile; the newest 20 profiles are kept per project.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int             true   "Project ID"
+// @Param        request  body      ProfileRequest  false  "Profile to capture"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Profile job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request or no pprof URL"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Service is not running or a profile is already being captured"
+// @Router       /projects/{id}/profile [post]
+func (h *Handler) CaptureProfile(c *gin.Context) {
+	var req ProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if req.Type == "" {
+		req.Type = ProfileCPU
+	}
+	switch req.Type {
+	case ProfileCPU:
+		if req.Seconds == 0 {
+			req.Seconds = 10
+		}
+		if req.Seconds < 1 || req.Seconds > 300 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "seconds must be between 1 and 300", req.Seconds))
+			return
+		}
+	case ProfileHeap, ProfileAllocs, ProfileGoroutine:
+		req.Seconds = 0
+	default:
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "type must be cpu, heap, allocs or goroutine", req.Type))
+		return
+	}
+
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	base := pprofBaseURL(project)
+	if base == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "No pprof URL", "set pprof_url, or a port to use http://127.0.0.1:<port>/debug/pprof"))
+		return
+	}
+	if project.Status != StatusRunning {
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is not running", string(project.Status)))
+		return
+	}
+
+	target := base + "/" + req.Type
+	if req.Type == ProfileCPU {
+		target = fmt.Sprintf("%s/profile?seconds=%d", base, req.Seconds)
+	}
+	projectID := project.ID
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:      jobs.TypeProfile,
+		ProjectID: &projectID,
+		Key:       fmt.Sprintf("profile:%d", projectID),
+		Message:   "GET " + target,
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		profile, err := fetchProfile(ctx, run, target, req.Seconds)
+		if err != nil {
+			return nil, err
+		}
+		profile.CreatedAt = time.Now()
+		profile.ProjectID = projectID
+		profile.JobID = run.ID()
+		profile.Type = req.Type
+		profile.Seconds = req.Seconds
+		if req.FlameGraph {
+			if parsed, err := profiling.Parse(profile.Data); err == nil {
+				profile.FlameGraph = profiling.FlameGraph(parsed, profileTitle(project.Name, profile))
+				profile.HasFlameGraph = true
+			}
+		}
+		if err := h.saveProfile(profile); err != nil {
+			return nil, err
+		}
+		run.Progress(100, fmt.Sprintf("%d samples of %s", profile.Samples, profile.SampleType))
+		return profile, nil
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "A profile is already being captured for this project", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// GetProfiles godoc
+// @Summary      List profiles
+// @Description  Get the pprof profiles captured from a project, newest first, without their data
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=[]ProjectProfile}
+// @Failure      400  {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /projects/{id}/profiles [get]
+func (h *Handler) GetProfiles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	profiles := []ProjectProfile{}
+	if err := h.db.Omit("data", "flame_graph").Where("project_id = ?", id).Order("id DESC").Find(&profiles).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch profiles", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: profiles})
+}
+
+// DownloadProfile godoc
+// @Summary      Download a profile
+// @Description  Download a captured profile in the gzipped pprof format, for go tool pprof
+// @Tags         projects
+// @Produce      application/octet-stream
+// @Param        id          path      int  true  "Project ID"
+// @Param        profile_id  path      int  true  "Profile ID"
+// @Success      200         {file}    file  "pprof profile"
+// @Failure      404         {object}  middleware.ErrorResponse  "Profile not found"
+// @Router       /projects/{id}/profiles/{profile_id}/download [get]
+func (h *Handler) DownloadProfile(c *gin.Context) {
+	profile, err := h.loadProfile(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	filename := fmt.Sprintf("%s-%d-%s.pb.gz", profile.Type, profile.ProjectID, profile.CreatedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/octet-stream", profile.Data)
+}
+
+// GetProfileFlameGraph godoc
+// @Summary      Get the flame graph of a profile
+// @Description  Get the SVG flame graph of a captured profile, rendered and stored on first request. Callers are drawn below their callees; hovering a frame shows its value.
+// @Tags         projects
+// @Produce      image/svg+xml
+// @Param        id          path      int  true  "Project ID"
+// @Param        profile_id  path      int  true  "Profile ID"
+// @Success      200         {file}    file  "SVG flame graph"
+// @Failure      404         {object}  middleware.ErrorResponse  "Profile not found"
+// @Failure      422         {object}  middleware.ErrorResponse  "Profile cannot be read"
+// @Router       /projects/{id}/profiles/{profile_id}/flamegraph [get]
+func (h *Handler) GetProfileFlameGraph(c *gin.Context) {
+	profile, err := h.loadProfile(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if !profile.HasFlameGraph {
+		parsed, err := profiling.Parse(profile.Data)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusUnprocessableEntity, "Profile cannot be read", err.Error()))
+			return
+		}
+		var project Project
+		h.db.Select("name").First(&project, profile.ProjectID)
+		profile.FlameGraph = profiling.FlameGraph(parsed, profileTitle(project.Name, profile))
+		profile.HasFlameGraph = true
+		h.db.Model(profile).Updates(map[string]interface{}{"flame_graph": profile.FlameGraph, "has_flame_graph": true})
+	}
+	c.Data(http.StatusOK, "image/svg+xml", profile.FlameGraph)
+}
+
+// DeleteProfile godoc
+// @Summary      Delete a profile
+// @Description  Delete a captured profile and its flame graph
+// @Tags         projects
+// @Produce      json
+// @Param        id          path      int  true  "Project ID"
+// @Param        profile_id  path      int  true  "Profile ID"
+// @Success      200         {object}  types.MessageResponse
+// @Failure      404         {object}  middleware.ErrorResponse  "Profile not found"
+// @Router       /projects/{id}/profiles/{profile_id} [delete]
+func (h *Handler) DeleteProfile(c *gin.Context) {
+	result := h.db.Where("project_id = ?", c.Param("id")).Delete(&ProjectProfile{}, c.Param("profile_id"))
+	if result.Error != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete profile", result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Profile deleted"})
+}
+
+// loadProfile returns the profile of the request path
+func (h *Handler) loadProfile(c *gin.Context) (*ProjectProfile, error) {
+	var profile ProjectProfile
+	if err := h.db.Where("project_id = ?", c.Param("id")).First(&profile, c.Param("profile_id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, middleware.ErrNotFound
+		}
+		return nil, middleware.NewError(http.StatusInternalServerError, "Failed to fetch profile", err.Error())
+	}
+	return &profile, nil
+}
+
+// saveProfile stores a profile and prunes the project's oldest profiles
+func (h *Handler) saveProfile(profile *ProjectProfile) error {
+	if err := h.db.Create(profile).Error; err != nil {
+		return fmt.Errorf("failed to save profile: %v", err)
+	}
+
+	var ids []uint
+	h.db.Model(&ProjectProfile{}).Where("project_id = ?", profile.ProjectID).Order("id DESC").Pluck("id", &ids)
+	if len(ids) > profileHistory {
+		h.db.Delete(&ProjectProfile{}, ids[profileHistory:])
+	}
+	return nil
+}
+
+// pprofBaseURL returns the net/http/pprof base URL of a project, empty when
+// it has neither pprof_url nor a port
+func pprofBaseURL(project *Project) string {
+	if project.PprofURL != "" {
+		return strings.TrimRight(project.PprofURL, "/")
+	}
+	if project.Port > 0 {
+		return fmt.Sprintf("http://127.0.0.1:%d/debug/pprof", project.Port)
+	}
+	return ""
+}
+
+// fetchProfile downloads a profile and checks it can be read
+func fetchProfile(ctx context.Context, run *jobs.Run, target string, seconds int) (*ProjectProfile, error) {
+	if seconds > 0 {
+		run.Progress(0, fmt.Sprintf("Profiling CPU for %ds", seconds))
+	}
+	client := &http.Client{Timeout: time.Duration(seconds)*time.Second + 30*time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch profile: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxProfileDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s: %s", target, resp.Status, strings.TrimSpace(string(data[:min(len(data), 200)])))
+	}
+	if len(data) > maxProfileDownload {
+		return nil, fmt.Errorf("profile larger than %d MB", maxProfileDownload>>20)
+	}
+
+	parsed, err := profiling.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%v (is net/http/pprof imported?)", err)
+	}
+	sampleType := parsed.SampleType()
+	return &ProjectProfile{
+		URL:        target,
+		Size:       len(data),
+		SampleType: sampleType.Type + "/" + sampleType.Unit,
+		Samples:    len(parsed.Samples),
+		Total:      parsed.Total(),
+		Data:       data,
+	}, nil
+}
+
+// profileTitle is the title of the flame graph of a profile
+func profileTitle(projectName string, profile *ProjectProfile) string {
+	title := fmt.Sprintf("%s %s profile, %s", projectName, profile.Type, profile.CreatedAt.Format(time.RFC3339))
+	if profile.Seconds > 0 {
+		title += fmt.Sprintf(" (%ds)", profile.Seconds)
+	}
+	return title
+}
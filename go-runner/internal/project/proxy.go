@@ -0,0 +1,76 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProxyProject godoc
+// @Summary      Proxy a request to the project
+// @Description  Forward a request of any method, WebSocket upgrades included, to the project port on 127.0.0.1, with the path after /proxy and the query string. The prefix is passed in X-Forwarded-Prefix. A project with an idle_timeout is started when it is not running, the request waiting until its port accepts connections (idle.wake_timeout), and is stopped again after idle_timeout minutes without proxied requests. During a blue/green restart (restart_strategy) new requests go to the new instance while those in flight finish on the old one. Projects with more than one instance (instances) get requests round robin. Requests are held first while latency is injected (POST /projects/{id}/chaos/latency). Requests are counted in GET /projects/{id}/traffic.
+// @Tags         projects
+// @Param        id    path  int     true  "Project ID"
+// @Param        path  path  string  true  "Path on the project"
+// @Success      200  "Response of the project"
+// @Failure      400  {object}  middleware.ErrorResponse  "Project has no port"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      502  {object}  middleware.ErrorResponse  "Project not reachable"
+// @Failure      503  {object}  middleware.ErrorResponse  "Project failed to start"
+// @Router       /projects/{id}/proxy/{path} [get]
+func (h *Handler) ProxyProject(c *gin.Context) {
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if project.Port == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project has no port to proxy to", nil))
+		return
+	}
+
+	start := time.Now()
+	end := h.manager.BeginProxiedRequest(project.ID)
+	defer func() {
+		end()
+		h.manager.RecordProxiedRequest(project.ID, c.Writer.Status(), time.Since(start))
+	}()
+
+	if project.IdleTimeout > 0 {
+		if err := h.manager.WakeProject(project.ID, project.Port); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusServiceUnavailable, "Project failed to start", err.Error()))
+			return
+		}
+	}
+
+	// Latency injected for chaos testing
+	if delay := h.manager.ProxyDelay(project.ID); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+
+	// Blue/green restarts move the service to another port
+	port, done := h.manager.ProxyPort(project.ID, project.Port)
+	defer done()
+	target := &url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", port)}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadGateway, "Project not reachable", err.Error()))
+	}
+
+	path := c.Param("path")
+	c.Request.Header.Set("X-Forwarded-Prefix", strings.TrimSuffix(c.Request.URL.Path, path))
+	c.Request.URL.Path = path
+	c.Request.URL.RawPath = ""
+	proxy.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,253 @@
+package project
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/maintenance"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/system"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// queueGrowthWindow is the period over which queue growth is measured
+	queueGrowthWindow = 5 * time.Minute
+	// queueMetricsRetention is how long queue depth samples are kept
+	queueMetricsRetention = 7 * 24 * time.Hour
+)
+
+// QueueMetric is a queue depth sample of a queue project
+type QueueMetric struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"index:,composite:lookup"`
+	Queue     string    `json:"queue" gorm:"index:,composite:lookup"`
+	Depth     int64     `json:"depth"`
+	Consumers int       `json:"consumers"`
+	Timestamp time.Time `json:"timestamp" gorm:"index"`
+}
+
+// QueueRecorder stores queue checks as metrics and raises backlog alerts
+type QueueRecorder struct {
+	db     *gorm.DB
+	events *events.Bus
+}
+
+// NewQueueRecorder creates a queue recorder
+func NewQueueRecorder(db *gorm.DB, bus *events.Bus) *QueueRecorder {
+	return &QueueRecorder{db: db, events: bus}
+}
+
+// Record stores a queue check, evaluates the alert rules of the project and
+// publishes the check as a "queue_update" event
+func (r *QueueRecorder) Record(projectID uint, stats *service.QueueStats) {
+	r.events.Publish(projectID, "queue_update", stats)
+	if !stats.Healthy {
+		return
+	}
+
+	var project Project
+	if err := r.db.Select("id, name, queue_backlog_limit, queue_growth_limit").First(&project, projectID).Error; err != nil {
+		return
+	}
+
+	for _, q := range stats.Queues {
+		r.checkBacklog(&project, q)
+		r.checkGrowth(&project, q, stats.CheckedAt)
+
+		metric := QueueMetric{ProjectID: projectID, Queue: q.Name, Depth: q.Depth, Consumers: q.Consumers, Timestamp: stats.CheckedAt}
+		if err := r.db.Create(&metric).Error; err != nil {
+			log.Printf("Failed to store queue metric: %v", err)
+		}
+	}
+
+	r.db.Where("project_id = ? AND timestamp < ?", projectID, time.Now().Add(-queueMetricsRetention)).Delete(&QueueMetric{})
+}
+
+// checkBacklog raises a queue_backlog alert while a queue holds more messages
+// than the project limit
+func (r *QueueRecorder) checkBacklog(project *Project, q service.QueueDepth) {
+	if project.QueueBacklogLimit <= 0 {
+		return
+	}
+	limit := project.QueueBacklogLimit
+	if q.Depth <= limit {
+		resolveAlert(r.db, r.events, "queue_backlog", project.ID, alertPrefix(project, q.Name))
+		return
+	}
+
+	level := "warning"
+	if q.Depth >= 2*limit {
+		level = "critical"
+	}
+	raiseAlert(r.db, r.events, "queue_backlog", level, project.ID, alertPrefix(project, q.Name),
+		fmt.Sprintf("%d messages waiting (threshold: %d)", q.Depth, limit), float64(q.Depth), float64(limit))
+}
+
+// checkGrowth raises a queue_growth alert while a queue grows faster than
+// the project limit, measured against the oldest sample of the growth window
+func (r *QueueRecorder) checkGrowth(project *Project, q service.QueueDepth, now time.Time) {
+	if project.QueueGrowthLimit <= 0 {
+		return
+	}
+
+	var oldest QueueMetric
+	if err := r.db.Where("project_id = ? AND queue = ? AND timestamp >= ?", project.ID, q.Name, now.Add(-queueGrowthWindow)).
+		Order("timestamp asc").First(&oldest).Error; err != nil {
+		return
+	}
+	minutes := now.Sub(oldest.Timestamp).Minutes()
+	if minutes < 1 {
+		return // Not enough history yet
+	}
+
+	rate := float64(q.Depth-oldest.Depth) / minutes
+	limit := float64(project.QueueGrowthLimit)
+	if rate <= limit {
+		resolveAlert(r.db, r.events, "queue_growth", project.ID, alertPrefix(project, q.Name))
+		return
+	}
+
+	raiseAlert(r.db, r.events, "queue_growth", "warning", project.ID, alertPrefix(project, q.Name),
+		fmt.Sprintf("growing by %.1f messages/min (threshold: %.0f)", rate, limit), rate, limit)
+}
+
+// alertPrefix identifies the alerts of a queue in their message
+func alertPrefix(project *Project, queue string) string {
+	return fmt.Sprintf("Queue %s/%s: ", project.Name, queue)
+}
+
+// raiseAlert creates an alert, or updates the level and value of the active
+// alert of this type whose message starts with prefix, unless a maintenance
+// window is open for the project. New alerts, and alerts changing level,
+// are published as "alert_raised" events; the prefix without its colon is
+// the resource of the alert.
+func raiseAlert(db *gorm.DB, bus *events.Bus, alertType, level string, projectID uint, prefix, message string, value, threshold float64) {
+	if window := maintenance.SuppressingAlerts(db, projectID); window != nil {
+		return
+	}
+
+	var existing system.SystemAlert
+	err := db.Where("type = ? AND is_active = ? AND message LIKE ?", alertType, true, prefix+"%").First(&existing).Error
+	if err == nil {
+		changed := existing.Level != level
+		db.Model(&existing).Updates(map[string]interface{}{"level": level, "message": prefix + message, "value": value})
+		if changed {
+			existing.Level, existing.Message, existing.Value = level, prefix+message, value
+			bus.Publish(projectID, "alert_raised", existing)
+		}
+		return
+	}
+
+	alert := system.SystemAlert{
+		Type:      alertType,
+		Level:     level,
+		Message:   prefix + message,
+		Resource:  strings.TrimSuffix(prefix, ": "),
+		Value:     value,
+		Threshold: threshold,
+		IsActive:  true,
+	}
+	if err := db.Create(&alert).Error; err != nil {
+		log.Printf("Failed to create alert: %v", err)
+		return
+	}
+	log.Printf("Created %s alert: %s", alert.Level, alert.Message)
+	bus.Publish(projectID, "alert_raised", alert)
+}
+
+// resolveAlert resolves the active alerts of this type whose message starts
+// with prefix, publishing each as an "alert_resolved" event
+func resolveAlert(db *gorm.DB, bus *events.Bus, alertType string, projectID uint, prefix string) {
+	var alerts []system.SystemAlert
+	if err := db.Where("type = ? AND is_active = ? AND message LIKE ?", alertType, true, prefix+"%").Find(&alerts).Error; err != nil || len(alerts) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, alert := range alerts {
+		if err := db.Model(&alert).Updates(map[string]interface{}{"is_active": false, "resolved_at": &now}).Error; err != nil {
+			log.Printf("Failed to resolve alert: %v", err)
+			continue
+		}
+		alert.IsActive, alert.ResolvedAt = false, &now
+		bus.Publish(projectID, "alert_resolved", alert)
+	}
+}
+
+// GetQueueStatus godoc
+// @Summary      Check a queue project
+// @Description  Poll the broker of a queue project for queue depth and consumers: RabbitMQ through its management API (amqp:// or http:// connection string), Redis lists, sorted sets and stream consumer groups (redis://, keys listed in queues) or Kafka consumer group lag (kafka://, needs kafka-consumer-groups on PATH). Queue projects are also checked every 30 seconds; each check is stored as metrics and the last one is included as "queue_stats" in the project status.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.QueueStats}  "Check result, healthy or not"
+// @Failure      400  {object}  middleware.ErrorResponse  "No connection string"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/queues [get]
+func (h *Handler) GetQueueStatus(c *gin.Context) {
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	if project.ConnectionString == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project has no connection string", nil))
+		return
+	}
+
+	stats := h.manager.CheckQueues(c.Request.Context(), project.ID, project.ConnectionString, service.SplitQueueNames(project.Queues))
+	h.queues.Record(project.ID, stats)
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: stats})
+}
+
+// GetQueueMetrics godoc
+// @Summary      Get queue depth history
+// @Description  Get the queue depth samples of a queue project, oldest first
+// @Tags         projects
+// @Produce      json
+// @Param        id     path      int     true   "Project ID"
+// @Param        queue  query     string  false  "Queue name"
+// @Param        hours  query     int     false  "Hours of history (default 1, max 168)"
+// @Success      200    {object}  types.DataResponse{data=[]QueueMetric}
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /projects/{id}/queues/metrics [get]
+func (h *Handler) GetQueueMetrics(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	hours := 1
+	if raw := c.Query("hours"); raw != "" {
+		hours, err = strconv.Atoi(raw)
+		if err != nil || hours < 1 || hours > 168 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "hours must be between 1 and 168", raw))
+			return
+		}
+	}
+
+	query := h.db.Where("project_id = ? AND timestamp >= ?", id, time.Now().Add(-time.Duration(hours)*time.Hour))
+	if queue := c.Query("queue"); queue != "" {
+		query = query.Where("queue = ?", queue)
+	}
+
+	metrics := []QueueMetric{}
+	if err := query.Order("timestamp asc").Find(&metrics).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch queue metrics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: metrics})
+}
@@ -0,0 +1,78 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReconcileResults godoc
+// @Summary      Get reconcile results
+// @Description  Get what happened, when the server started, to the processes of projects that were running: adopted when their PID, start time and command line matched the ones recorded on start, stopped when they exited or their PID was reused, unverified otherwise. Unverified processes keep running until adopted (POST /services/{id}/adopt) or stopped.
+// @Tags         services
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=service.ReconcileSummary}  "Reconcile results"
+// @Failure      404  {object}  middleware.ErrorResponse                           "Reconcile has not run yet"
+// @Router       /services/reconcile [get]
+func (h *Handler) GetReconcileResults(c *gin.Context) {
+	summary := h.manager.LastReconcile()
+	if summary == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Reconcile has not run yet", nil))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: summary})
+}
+
+// AdoptProcess godoc
+// @Summary      Adopt a running process
+// @Description  Monitor the recorded process of a project again after a server restart, even if its identity could not be verified. Its output is tailed when it goes to a file, and its identity is recorded for the next restart.
+// @Tags         services
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataMessageResponse{data=service.ReconciledProcess}
+// @Failure      400  {object}  middleware.ErrorResponse  "Invalid project ID"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409  {object}  middleware.ErrorResponse  "No running process, or already monitored"
+// @Router       /services/{id}/adopt [post]
+func (h *Handler) AdoptProcess(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	result, err := h.manager.AdoptProcess(project.ID)
+	switch {
+	case errors.Is(err, service.ErrServiceNotRunning):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is not running", err.Error()))
+		return
+	case errors.Is(err, service.ErrProcessMonitored):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Process is already monitored", err.Error()))
+		return
+	case err != nil:
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to adopt process", err.Error()))
+		return
+	}
+
+	h.events.Publish(project.ID, "status_update", gin.H{
+		"project_id": project.ID,
+		"status":     string(StatusRunning),
+		"message":    "Process adopted",
+	})
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Data:    result,
+		Message: "Process adopted",
+	})
+}
@@ -0,0 +1,243 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRollingReadyTimeout is how long a batch has to be ready, in seconds
+const defaultRollingReadyTimeout = 60
+
+// Outcomes of a project in a rolling restart
+const (
+	RollingRestarted  = "restarted"
+	RollingFailed     = "failed"
+	RollingSkipped    = "skipped"     // Not running, and include_stopped not set
+	RollingNotReached = "not_reached" // Left alone after an earlier batch failed, or the job was cancelled
+)
+
+// RollingRestartRequest configures a rolling restart
+type RollingRestartRequest struct {
+	BatchSize      int  `json:"batch_size" binding:"omitempty,min=1,max=50"`      // Projects restarted at once (default 1)
+	ReadyTimeout   int  `json:"ready_timeout" binding:"omitempty,min=1,max=3600"` // Seconds a batch has to be ready (default 60)
+	Pause          int  `json:"pause" binding:"omitempty,min=0,max=600"`          // Seconds to wait between batches
+	IncludeStopped bool `json:"include_stopped"`                                  // Also start members that are not running
+}
+
+// RollingRestartStep is the outcome of one project of a rolling restart
+type RollingRestartStep struct {
+	ProjectID   uint   `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Batch       int    `json:"batch"` // 1-based, 0 for skipped projects
+	Result      string `json:"result"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms,omitempty"` // Restart until ready
+}
+
+// RollingRestartResult is the result of a rolling restart job
+type RollingRestartResult struct {
+	GroupID   uint                 `json:"group_id"`
+	GroupName string               `json:"group_name"`
+	BatchSize int                  `json:"batch_size"`
+	Batches   int                  `json:"batches"`
+	Aborted   bool                 `json:"aborted"` // A batch failed and the next ones were not restarted
+	Steps     []RollingRestartStep `json:"steps"`
+}
+
+// RollingRestart godoc
+// @Summary      Rolling restart of a group
+// @Description  Restart the projects of a group as a background job, batch_size at a time (default 1), the projects they depend on first. After each batch it waits until the restarted projects are ready: port accepting connections, socket_path listening and health_check_url answering below 400, whichever they have, or still running after a few seconds when they have none. The first project that fails to restart or is not ready within ready_timeout seconds aborts the remaining batches, which are reported as not_reached. Members that are not running are skipped unless include_stopped is set. Each restarted project gets a restart annotation. The job result is a RollingRestartResult.
+// @Tags         groups
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                    true   "Group ID"
+// @Param        request  body      RollingRestartRequest  false  "Batching and timeouts"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Rolling restart job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request or group without projects"
+// @Failure      404      {object}  middleware.ErrorResponse  "Group not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "A rolling restart of the group is already running"
+// @Router       /groups/{id}/rolling-restart [post]
+func (h *Handler) RollingRestart(c *gin.Context) {
+	var req RollingRestartRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	if req.BatchSize == 0 {
+		req.BatchSize = 1
+	}
+	if req.ReadyTimeout == 0 {
+		req.ReadyTimeout = defaultRollingReadyTimeout
+	}
+
+	var group ProjectGroup
+	if err := h.db.First(&group, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	var memberIDs []uint
+	if err := h.db.Model(&Project{}).Where("group_id = ?", group.ID).Pluck("id", &memberIDs).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
+		return
+	}
+	if len(memberIDs) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Group has no projects", nil))
+		return
+	}
+
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:    jobs.TypeRollingRestart,
+		Key:     fmt.Sprintf("rolling-restart:%d", group.ID),
+		Message: fmt.Sprintf("Rolling restart of %s", group.Name),
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		return h.rollingRestart(ctx, run, group, req)
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "A rolling restart of this group is already running", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// rollingRestart restarts the projects of a group batch by batch, stopping at
+// the first failure
+func (h *Handler) rollingRestart(ctx context.Context, run *jobs.Run, group ProjectGroup, req RollingRestartRequest) (*RollingRestartResult, error) {
+	var projects []Project
+	if err := h.db.Select("id, name").Where("group_id = ?", group.ID).Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]Project, len(projects))
+	ids := make([]uint, 0, len(projects))
+	for _, p := range projects {
+		byID[p.ID] = p
+		ids = append(ids, p.ID)
+	}
+	ordered, err := h.manager.RestartOrder(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RollingRestartResult{GroupID: group.ID, GroupName: group.Name, BatchSize: req.BatchSize, Steps: []RollingRestartStep{}}
+	var targets []uint
+	for _, id := range ordered {
+		if req.IncludeStopped || h.manager.IsServiceRunning(id) {
+			targets = append(targets, id)
+			continue
+		}
+		result.Steps = append(result.Steps, RollingRestartStep{ProjectID: id, ProjectName: byID[id].Name, Result: RollingSkipped, Error: "not running"})
+		run.Log(fmt.Sprintf("Skipping %s: not running", byID[id].Name))
+	}
+	result.Batches = (len(targets) + req.BatchSize - 1) / req.BatchSize
+	if len(targets) == 0 {
+		run.Progress(100, "No running projects to restart")
+		return result, nil
+	}
+
+	timeout := time.Duration(req.ReadyTimeout) * time.Second
+	var failures []string
+	for batch := 0; batch*req.BatchSize < len(targets); batch++ {
+		start := batch * req.BatchSize
+		end := start + req.BatchSize
+		if end > len(targets) {
+			end = len(targets)
+		}
+		if batch > 0 && req.Pause > 0 && len(failures) == 0 {
+			select {
+			case <-time.After(time.Duration(req.Pause) * time.Second):
+			case <-ctx.Done():
+			}
+		}
+		if len(failures) > 0 || ctx.Err() != nil {
+			for _, id := range targets[start:end] {
+				result.Steps = append(result.Steps, RollingRestartStep{ProjectID: id, ProjectName: byID[id].Name, Batch: batch + 1, Result: RollingNotReached})
+			}
+			continue
+		}
+
+		names := make([]string, 0, end-start)
+		for _, id := range targets[start:end] {
+			names = append(names, byID[id].Name)
+		}
+		run.Progress(start*100/len(targets), fmt.Sprintf("Batch %d/%d: restarting %s", batch+1, result.Batches, strings.Join(names, ", ")))
+
+		steps := make([]RollingRestartStep, end-start)
+		var wg sync.WaitGroup
+		for i, id := range targets[start:end] {
+			wg.Add(1)
+			go func(i int, project Project) {
+				defer wg.Done()
+				steps[i] = h.rollingRestartProject(project, group, batch+1, timeout)
+			}(i, byID[id])
+		}
+		wg.Wait()
+
+		for _, step := range steps {
+			if step.Result == RollingFailed {
+				failures = append(failures, fmt.Sprintf("%s: %s", step.ProjectName, step.Error))
+				run.Log(fmt.Sprintf("❌ %s failed: %s", step.ProjectName, step.Error))
+			} else {
+				run.Log(fmt.Sprintf("✅ %s ready after %s", step.ProjectName, time.Duration(step.DurationMs)*time.Millisecond))
+			}
+		}
+		result.Steps = append(result.Steps, steps...)
+		if len(failures) > 0 && end < len(targets) {
+			result.Aborted = true
+			run.Log(fmt.Sprintf("Aborting: %d project(s) left as they are", len(targets)-end))
+		}
+	}
+
+	if len(failures) > 0 {
+		return result, fmt.Errorf("rolling restart failed: %s", strings.Join(failures, "; "))
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	run.Progress(100, fmt.Sprintf("Restarted %d project(s)", len(targets)))
+	return result, nil
+}
+
+// rollingRestartProject restarts one project and waits until it is ready
+func (h *Handler) rollingRestartProject(project Project, group ProjectGroup, batch int, timeout time.Duration) RollingRestartStep {
+	step := RollingRestartStep{ProjectID: project.ID, ProjectName: project.Name, Batch: batch}
+	annotation := &ProjectAnnotation{
+		ProjectID: project.ID,
+		Kind:      AnnotationRestart,
+		Message:   fmt.Sprintf("Rolling restart of group %s", group.Name),
+		Automatic: true,
+	}
+	if err := NewAnnotator(h.db, h.manager, h.events).Add(annotation); err != nil {
+		log.Printf("Failed to add restart annotation of project %d: %v", project.ID, err)
+	}
+
+	started := time.Now()
+	err := h.manager.RestartService(project.ID)
+	if err == nil {
+		err = h.manager.WaitReady(project.ID, timeout)
+	}
+	step.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		step.Result, step.Error = RollingFailed, err.Error()
+		return step
+	}
+	step.Result = RollingRestarted
+	return step
+}
@@ -0,0 +1,55 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRuntimeInfo godoc
+// @Summary      Get runtime info of a project
+// @Description  Read live details of the running process of a project and its descendants to debug it without leaving the dashboard: the sockets they listen on, TCP connections by state, threads, open file descriptors against the limit, working directory, executable, the size of the environment (not its values) and the child processes. Details the runner may not read, e.g. of processes of another user, are listed in errors.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.RuntimeInfo}  "Runtime info"
+// @Failure      400  {object}  middleware.ErrorResponse                      "Bad request"
+// @Failure      404  {object}  middleware.ErrorResponse                      "Project not found"
+// @Failure      409  {object}  middleware.ErrorResponse                      "Service is not running"
+// @Failure      422  {object}  middleware.ErrorResponse                      "Process runs on another host"
+// @Failure      500  {object}  middleware.ErrorResponse                      "Internal server error"
+// @Router       /projects/{id}/runtime [get]
+func (h *Handler) GetRuntimeInfo(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id").First(&project, id).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	info, err := h.manager.GetRuntimeInfo(project.ID)
+	switch {
+	case errors.Is(err, service.ErrServiceNotRunning):
+		middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Service is not running", err.Error()))
+		return
+	case errors.Is(err, service.ErrRemoteProcess):
+		middleware.HandleError(c, middleware.NewError(http.StatusUnprocessableEntity, "Process runs on another host", err.Error()))
+		return
+	case err != nil:
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to read runtime info", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: info})
+}
@@ -0,0 +1,292 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Script sources
+const (
+	ScriptSourceMake = "make"
+	ScriptSourceNpm  = "npm"
+)
+
+// makefileNames are the files GNU make reads, in its order
+var makefileNames = []string{"GNUmakefile", "makefile", "Makefile"}
+
+// makeTarget matches a rule line "target other: prerequisites ## description"
+var makeTarget = regexp.MustCompile(`^([A-Za-z0-9_./ -]+?)\s*::?(.*)$`)
+
+// ProjectScript is a Makefile target or package.json script of a project
+type ProjectScript struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`                // make or npm
+	Command     string `json:"command"`               // Script body or target recipe
+	Description string `json:"description,omitempty"` // "## text" comment of a Makefile target
+	Run         string `json:"run"`                   // Command line used to run it
+}
+
+// ProjectScripts lists the scripts found in a project directory
+type ProjectScripts struct {
+	Dir            string          `json:"dir"`
+	Makefile       string          `json:"makefile,omitempty"`
+	PackageManager string          `json:"package_manager,omitempty"` // npm, yarn or pnpm, from the lockfile
+	Scripts        []ProjectScript `json:"scripts"`
+}
+
+// RunScriptRequest runs a discovered script
+type RunScriptRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Source string   `json:"source" binding:"omitempty,oneof=make npm"` // Needed when a target and a script share the name
+	Args   []string `json:"args"`                                      // Extra arguments (VAR=value for make)
+}
+
+// GetScripts godoc
+// @Summary      List project scripts
+// @Description  Discover the targets of the project Makefile (GNUmakefile, makefile or Makefile; special and pattern rules excluded, "## text" comments used as descriptions) and the scripts of package.json, run with the package manager whose lockfile is present.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=ProjectScripts}
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/scripts [get]
+func (h *Handler) GetScripts(c *gin.Context) {
+	_, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	scripts, err := discoverScripts(dir)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to read scripts", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: scripts})
+}
+
+// RunScript godoc
+// @Summary      Run a project script
+// @Description  Run a Makefile target or package.json script in the project directory as a background job and return the job immediately, with the variables of a project start (.env, env_vars, PORT). Output is streamed to the project WebSocket as "script_log" messages (InstallLogLine) and job progress as "job_update". The same script does not run twice at a time; list past runs with GET /jobs?type=script&project_id={id}.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int               true  "Project ID"
+// @Param        request  body      RunScriptRequest  true  "Script to run"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Script job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request or ambiguous name"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project or script not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "The script is already running"
+// @Failure      500      {object}  middleware.ErrorResponse  "make or the package manager not found"
+// @Router       /projects/{id}/scripts/run [post]
+func (h *Handler) RunScript(c *gin.Context) {
+	var req RunScriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	project, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	scripts, err := discoverScripts(dir)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to read scripts", err.Error()))
+		return
+	}
+	var matches []ProjectScript
+	for _, s := range scripts.Scripts {
+		if s.Name == req.Name && (req.Source == "" || s.Source == req.Source) {
+			matches = append(matches, s)
+		}
+	}
+	if len(matches) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Script not found", req.Name))
+		return
+	}
+	if len(matches) > 1 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Both a make target and an npm script have this name, set source", req.Name))
+		return
+	}
+	script := matches[0]
+
+	args := strings.Fields(script.Run)
+	if script.Source == ScriptSourceNpm && scripts.PackageManager == "npm" && len(req.Args) > 0 {
+		args = append(args, "--") // npm run passes only what follows -- to the script
+	}
+	args = append(args, req.Args...)
+	if _, err := exec.LookPath(args[0]); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to start script", err.Error()))
+		return
+	}
+
+	env := os.Environ()
+	if spec, err := h.manager.ProjectLaunchSpec(project.ID); err == nil {
+		for k, v := range spec.Env {
+			env = append(env, k+"="+v)
+		}
+	}
+
+	projectID := project.ID
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:      jobs.TypeScript,
+		ProjectID: &projectID,
+		Key:       fmt.Sprintf("script:%d:%s:%s", projectID, script.Source, script.Name),
+		Message:   strings.Join(args, " "),
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = env
+		return h.streamCommand(run, projectID, "script_log", cmd)
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "This script is already running", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// discoverScripts reads the Makefile targets and package.json scripts of a directory
+func discoverScripts(dir string) (*ProjectScripts, error) {
+	result := &ProjectScripts{Dir: dir, Scripts: []ProjectScript{}}
+
+	for _, name := range makefileNames {
+		path := filepath.Join(dir, name)
+		if !fileExists(path) {
+			continue
+		}
+		targets, err := parseMakefile(path)
+		if err != nil {
+			return nil, err
+		}
+		result.Makefile = path
+		result.Scripts = append(result.Scripts, targets...)
+		break
+	}
+
+	path := filepath.Join(dir, "package.json")
+	if fileExists(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var manifest struct {
+			Scripts map[string]string `json:"scripts"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("package.json: %v", err)
+		}
+
+		result.PackageManager = nodePackageManager(dir)
+		names := make([]string, 0, len(manifest.Scripts))
+		for name := range manifest.Scripts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			result.Scripts = append(result.Scripts, ProjectScript{
+				Name:    name,
+				Source:  ScriptSourceNpm,
+				Command: manifest.Scripts[name],
+				Run:     result.PackageManager + " run " + name,
+			})
+		}
+	}
+	return result, nil
+}
+
+// parseMakefile lists the targets of a Makefile in order with their recipes
+func parseMakefile(path string) ([]ProjectScript, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var targets []ProjectScript
+	var current []int // Indexes of the targets of the rule being read
+	var comment string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "\t") {
+			recipe := strings.TrimSpace(line)
+			for _, i := range current {
+				targets[i].Command = strings.TrimSpace(targets[i].Command + "\n" + recipe)
+			}
+			continue
+		}
+		current = nil
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "##") {
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+		match := makeTarget.FindStringSubmatch(line)
+		// Variable assignments (:=, ::=) are not rules
+		if match == nil || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(match[2], "=") {
+			comment = ""
+			continue
+		}
+
+		description := comment
+		if _, after, ok := strings.Cut(match[2], "##"); ok {
+			description = strings.TrimSpace(after)
+		}
+		comment = ""
+		for _, name := range strings.Fields(match[1]) {
+			// Special targets (.PHONY) and pattern rules are not runnable by name
+			if strings.HasPrefix(name, ".") || strings.Contains(name, "%") || seen[name] {
+				continue
+			}
+			seen[name] = true
+			current = append(current, len(targets))
+			targets = append(targets, ProjectScript{
+				Name:        name,
+				Source:      ScriptSourceMake,
+				Description: description,
+				Run:         "make " + name,
+			})
+		}
+	}
+	return targets, scanner.Err()
+}
+
+// nodePackageManager returns the package manager whose lockfile is present
+func nodePackageManager(dir string) string {
+	switch {
+	case fileExists(filepath.Join(dir, "pnpm-lock.yaml")):
+		return "pnpm"
+	case fileExists(filepath.Join(dir, "yarn.lock")):
+		return "yarn"
+	}
+	return "npm"
+}
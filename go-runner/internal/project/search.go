@@ -0,0 +1,396 @@
+package project
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/system"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Search categories
+const (
+	SearchProjects = "projects"
+	SearchGroups   = "groups"
+	SearchLogs     = "logs"
+	SearchAlerts   = "alerts"
+)
+
+var searchCategories = []string{SearchProjects, SearchGroups, SearchLogs, SearchAlerts}
+
+const (
+	maxSearchLimit       = 50
+	logMatchesPerProject = 5   // So one noisy service does not fill the log results
+	maxSnippetLength     = 300 // Bytes of a log line or alert message returned
+	minLogQueryLength    = 3   // Shorter queries match too many log lines to be useful
+)
+
+// SearchResults are the matches of a query by category, best first
+type SearchResults struct {
+	Query    string           `json:"query"`
+	Projects []SearchHit      `json:"projects"`
+	Groups   []SearchHit      `json:"groups"`
+	Logs     []LogSearchHit   `json:"logs"`
+	Alerts   []AlertSearchHit `json:"alerts"`
+}
+
+// SearchHit is a matching project or group
+type SearchHit struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Field   string `json:"field"`             // Field that matched best: name, description, path, owner or team
+	Snippet string `json:"snippet,omitempty"` // Value of the field, when not the name
+	Status  string `json:"status,omitempty"`  // Of projects
+	GroupID *uint  `json:"group_id,omitempty"`
+	Score   int    `json:"score"`
+}
+
+// LogSearchHit is a recent log line of a project containing the query
+type LogSearchHit struct {
+	ProjectID   uint      `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	Line        string    `json:"line"`
+	Time        time.Time `json:"time"` // Zero for lines saved before capture times were recorded
+	Score       int       `json:"score"`
+}
+
+// AlertSearchHit is an alert whose message or resource contains the query
+type AlertSearchHit struct {
+	ID        uint      `json:"id"`
+	Type      string    `json:"type"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	Resource  string    `json:"resource,omitempty"`
+	ProjectID uint      `json:"project_id,omitempty"` // Project of the process or queue of the alert
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	Score     int       `json:"score"`
+}
+
+// Search godoc
+// @Summary      Search projects, groups, logs and alerts
+// @Description  Search everything go-runner knows for a command palette: projects by name, description, path, owner and team, groups by name and description, the recent logs of each project (at most 5 lines per project, for queries of 3 characters or more) and alert messages. Matching ignores case; names also match by their initials or letters in order (pa for payments-api). Results are ranked within each category: exact names, then prefixes, word prefixes and substrings, name matches before other fields; newer log lines and active, newer alerts first.
+// @Tags         search
+// @Produce      json
+// @Param        q      query     string  true   "Text to search"
+// @Param        types  query     string  false  "Comma-separated categories to search: projects, groups, logs, alerts (default all)"
+// @Param        limit  query     int     false  "Results per category (default 10, max 50)"
+// @Success      200    {object}  types.DataResponse{data=SearchResults}
+// @Failure      400    {object}  middleware.ErrorResponse  "Missing query or invalid category"
+// @Router       /search [get]
+func (h *Handler) Search(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Missing query", "Set q"))
+		return
+	}
+	limit := 10
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid limit", raw))
+			return
+		}
+		limit = min(n, maxSearchLimit)
+	}
+	categories := make(map[string]bool)
+	if raw := c.Query("types"); raw != "" {
+		for _, category := range strings.Split(raw, ",") {
+			category = strings.TrimSpace(category)
+			if !containsString(searchCategories, category) {
+				middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid category",
+					"Expected "+strings.Join(searchCategories, ", ")))
+				return
+			}
+			categories[category] = true
+		}
+	} else {
+		for _, category := range searchCategories {
+			categories[category] = true
+		}
+	}
+
+	results := SearchResults{
+		Query:    query,
+		Projects: []SearchHit{},
+		Groups:   []SearchHit{},
+		Logs:     []LogSearchHit{},
+		Alerts:   []AlertSearchHit{},
+	}
+	needle := strings.ToLower(query)
+
+	var projects []Project
+	if categories[SearchProjects] || categories[SearchLogs] || categories[SearchAlerts] {
+		if err := h.db.Select("id, name, description, path, owner, team, status, group_id").Order("name").Find(&projects).Error; err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to search projects", err.Error()))
+			return
+		}
+	}
+	if categories[SearchProjects] {
+		for _, p := range projects {
+			hit, ok := bestMatch(needle, p.Name, map[string]string{
+				"description": p.Description,
+				"path":        p.Path,
+				"owner":       p.Owner,
+				"team":        p.Team,
+			})
+			if ok {
+				hit.ID, hit.Status, hit.GroupID = p.ID, string(p.Status), p.GroupID
+				results.Projects = append(results.Projects, hit)
+			}
+		}
+		results.Projects = rankHits(results.Projects, limit)
+	}
+	if categories[SearchGroups] {
+		var groups []ProjectGroup
+		if err := h.db.Select("id, name, description").Order("name").Find(&groups).Error; err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to search groups", err.Error()))
+			return
+		}
+		for _, g := range groups {
+			if hit, ok := bestMatch(needle, g.Name, map[string]string{"description": g.Description}); ok {
+				hit.ID = g.ID
+				results.Groups = append(results.Groups, hit)
+			}
+		}
+		results.Groups = rankHits(results.Groups, limit)
+	}
+	if categories[SearchLogs] && utf8.RuneCountInString(query) >= minLogQueryLength {
+		results.Logs = h.searchLogs(projects, needle, limit)
+	}
+	if categories[SearchAlerts] {
+		alerts, err := h.searchAlerts(projects, needle, limit)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to search alerts", err.Error()))
+			return
+		}
+		results.Alerts = alerts
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: results})
+}
+
+// searchLogs returns the newest log lines containing needle, a few per
+// project
+func (h *Handler) searchLogs(projects []Project, needle string, limit int) []LogSearchHit {
+	hits := []LogSearchHit{}
+	for _, p := range projects {
+		entries := h.manager.GetLogEntries(p.ID)
+		found := 0
+		for i := len(entries) - 1; i >= 0 && found < logMatchesPerProject; i-- {
+			line := entries[i].Line
+			index := strings.Index(strings.ToLower(line), needle)
+			if index < 0 {
+				continue
+			}
+			hits = append(hits, LogSearchHit{
+				ProjectID:   p.ID,
+				ProjectName: p.Name,
+				Line:        snippet(line, index, len(needle)),
+				Time:        entries[i].Time,
+				Score:       matchScore(line, needle),
+			})
+			found++
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if !hits[i].Time.Equal(hits[j].Time) {
+			return hits[i].Time.After(hits[j].Time)
+		}
+		return hits[i].Score > hits[j].Score
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// searchAlerts returns the alerts whose message or resource contains
+// needle, active ones first
+func (h *Handler) searchAlerts(projects []Project, needle string, limit int) ([]AlertSearchHit, error) {
+	var alerts []system.SystemAlert
+	pattern := "%" + escapeLike(needle) + "%"
+	if err := h.db.
+		Where("LOWER(message) LIKE ? ESCAPE '!' OR LOWER(resource) LIKE ? ESCAPE '!'", pattern, pattern).
+		Order("is_active desc, created_at desc").
+		Limit(limit).
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]uint, len(projects))
+	for _, p := range projects {
+		ids[p.Name] = p.ID
+	}
+	hits := make([]AlertSearchHit, 0, len(alerts))
+	for _, alert := range alerts {
+		hit := AlertSearchHit{
+			ID:        alert.ID,
+			Type:      alert.Type,
+			Level:     alert.Level,
+			Message:   alert.Message,
+			Resource:  alert.Resource,
+			Active:    alert.IsActive,
+			CreatedAt: alert.CreatedAt,
+			Score:     max(matchScore(alert.Message, needle), matchScore(alert.Resource, needle)),
+		}
+		if index := strings.Index(strings.ToLower(alert.Message), needle); index >= 0 {
+			hit.Message = snippet(alert.Message, index, len(needle))
+		}
+		// Alerts of processes ("Process <name>") and queues ("Queue <name>/...")
+		if name, ok := strings.CutPrefix(alert.Resource, "Process "); ok {
+			hit.ProjectID = ids[name]
+		} else if rest, ok := strings.CutPrefix(alert.Resource, "Queue "); ok {
+			if i := strings.LastIndex(rest, "/"); i > 0 {
+				hit.ProjectID = ids[rest[:i]]
+			}
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// bestMatch scores a project or group by its name and other fields; names
+// count double, so a project named after the query comes before one that
+// mentions it
+func bestMatch(needle, name string, fields map[string]string) (SearchHit, bool) {
+	hit := SearchHit{Name: name, Field: "name", Score: 2 * matchScore(name, needle)}
+	if hit.Score == 0 {
+		hit.Score = fuzzyScore(name, needle)
+	}
+	for _, field := range sortedKeys(fields) {
+		value := fields[field]
+		if score := matchScore(value, needle); score > hit.Score {
+			hit.Field, hit.Score = field, score
+			hit.Snippet = value
+			if index := strings.Index(strings.ToLower(value), needle); index >= 0 {
+				hit.Snippet = snippet(value, index, len(needle))
+			}
+		}
+	}
+	return hit, hit.Score > 0
+}
+
+// rankHits sorts hits best first, by name on ties, and keeps limit of them
+func rankHits(hits []SearchHit, limit int) []SearchHit {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Name < hits[j].Name
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// matchScore ranks how text contains needle, lower case: 100 when equal, 80
+// as a prefix, 60 at the start of a word, 40 elsewhere and 0 when it does
+// not
+func matchScore(text, needle string) int {
+	text = strings.ToLower(text)
+	switch {
+	case needle == "" || text == "":
+		return 0
+	case text == needle:
+		return 100
+	case strings.HasPrefix(text, needle):
+		return 80
+	}
+	index := strings.Index(text, needle)
+	if index < 0 {
+		return 0
+	}
+	for ; index >= 0; index = nextIndex(text, needle, index) {
+		previous, _ := utf8.DecodeLastRuneInString(text[:index])
+		if !unicode.IsLetter(previous) && !unicode.IsDigit(previous) {
+			return 60
+		}
+	}
+	return 40
+}
+
+// nextIndex finds needle in text after the match at index, -1 when there is
+// no other
+func nextIndex(text, needle string, index int) int {
+	next := strings.Index(text[index+1:], needle)
+	if next < 0 {
+		return -1
+	}
+	return index + 1 + next
+}
+
+// fuzzyScore matches needle as letters of name in order, as command palettes
+// do: 30 when they are the initials of its words (pa for payments-api), 10
+// otherwise, and 0 when they are not in name
+func fuzzyScore(name, needle string) int {
+	name = strings.ToLower(name)
+	var initials []rune
+	atWord := true
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if atWord {
+				initials = append(initials, r)
+			}
+			atWord = false
+		} else {
+			atWord = true
+		}
+	}
+	if strings.HasPrefix(string(initials), needle) {
+		return 30
+	}
+
+	rest := needle
+	for _, r := range name {
+		if rest == "" {
+			break
+		}
+		if first, size := utf8.DecodeRuneInString(rest); r == first {
+			rest = rest[size:]
+		}
+	}
+	if rest == "" {
+		return 10
+	}
+	return 0
+}
+
+// snippet cuts long text around the match at index, on rune boundaries
+func snippet(text string, index, length int) string {
+	if len(text) <= maxSnippetLength {
+		return text
+	}
+	start := max(0, index+length/2-maxSnippetLength/2)
+	end := min(len(text), start+maxSnippetLength)
+	start = max(0, end-maxSnippetLength)
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	result := text[start:end]
+	if start > 0 {
+		result = "..." + result
+	}
+	if end < len(text) {
+		result += "..."
+	}
+	return result
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern with !, an escape
+// character that needs no quoting in any of the supported databases
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
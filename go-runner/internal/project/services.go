@@ -0,0 +1,139 @@
+package project
+
+import (
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusStale is counted for projects the database marks running whose
+// process is gone
+const statusStale = "stale"
+
+// ServicesSummary counts the services by status and group, with the
+// resources of those running
+type ServicesSummary struct {
+	Total       int                   `json:"total"`
+	Running     int                   `json:"running"`
+	ByStatus    map[string]int        `json:"by_status"` // "stale" for projects marked running whose process is gone
+	Groups      []ServiceGroupSummary `json:"groups"`    // Ungrouped projects last, with a null group_id
+	CPUPercent  float64               `json:"cpu_percent"`
+	MemoryBytes uint64                `json:"memory_bytes"`
+
+	DiskReadBytesPerSec  float64 `json:"disk_read_bytes_per_sec"`
+	DiskWriteBytesPerSec float64 `json:"disk_write_bytes_per_sec"`
+
+	NetSentBytesPerSec     float64 `json:"net_sent_bytes_per_sec"`
+	NetReceivedBytesPerSec float64 `json:"net_received_bytes_per_sec"`
+}
+
+// ServiceGroupSummary counts the services of a group by status
+type ServiceGroupSummary struct {
+	GroupID     *uint          `json:"group_id"`
+	Name        string         `json:"name"`
+	Total       int            `json:"total"`
+	Running     int            `json:"running"`
+	ByStatus    map[string]int `json:"by_status"`
+	CPUPercent  float64        `json:"cpu_percent"`
+	MemoryBytes uint64         `json:"memory_bytes"`
+
+	DiskReadBytesPerSec  float64 `json:"disk_read_bytes_per_sec"`
+	DiskWriteBytesPerSec float64 `json:"disk_write_bytes_per_sec"`
+
+	NetSentBytesPerSec     float64 `json:"net_sent_bytes_per_sec"`
+	NetReceivedBytesPerSec float64 `json:"net_received_bytes_per_sec"`
+}
+
+// GetServicesSummary godoc
+// @Summary      Summarize services
+// @Description  Count the services by status and group, with the CPU, memory, disk I/O and TCP traffic of those running. A project counts as running when GET /services/running lists it, and as "stale" when the database still marks it running but its process is gone.
+// @Tags         services
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=ServicesSummary}
+// @Router       /services/summary [get]
+func (h *Handler) GetServicesSummary(c *gin.Context) {
+	var projects []Project
+	if err := h.db.Select("id, status, group_id").Find(&projects).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
+		return
+	}
+	var groups []ProjectGroup
+	if err := h.db.Select("id, name").Order("name").Find(&groups).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch project groups", err.Error()))
+		return
+	}
+
+	running := make(map[uint]int)
+	services := h.manager.GetRunningServices()
+	for i, service := range services {
+		running[service.ProjectID] = i
+	}
+
+	summary := ServicesSummary{ByStatus: make(map[string]int), Groups: []ServiceGroupSummary{}}
+	byGroup := make(map[uint]*ServiceGroupSummary, len(groups))
+	for _, group := range groups {
+		id := group.ID
+		summary.Groups = append(summary.Groups, ServiceGroupSummary{GroupID: &id, Name: group.Name, ByStatus: make(map[string]int)})
+	}
+	for i := range summary.Groups {
+		byGroup[*summary.Groups[i].GroupID] = &summary.Groups[i]
+	}
+	ungrouped := &ServiceGroupSummary{Name: "Ungrouped", ByStatus: make(map[string]int)}
+
+	for _, project := range projects {
+		group := ungrouped
+		if project.GroupID != nil && byGroup[*project.GroupID] != nil {
+			group = byGroup[*project.GroupID]
+		}
+		status := string(project.Status)
+		if status == "" {
+			status = string(StatusStopped)
+		}
+		i, isRunning := running[project.ID]
+		if isRunning {
+			status = string(StatusRunning)
+		} else if status == string(StatusRunning) {
+			status = statusStale
+		}
+
+		summary.Total++
+		group.Total++
+		summary.ByStatus[status]++
+		group.ByStatus[status]++
+		if isRunning {
+			service := services[i]
+			summary.Running++
+			group.Running++
+			summary.MemoryBytes += service.MemoryBytes
+			group.MemoryBytes += service.MemoryBytes
+			if service.CPUPercent != nil {
+				summary.CPUPercent += *service.CPUPercent
+				group.CPUPercent += *service.CPUPercent
+			}
+			if service.DiskReadBytesPerSec != nil {
+				summary.DiskReadBytesPerSec += *service.DiskReadBytesPerSec
+				group.DiskReadBytesPerSec += *service.DiskReadBytesPerSec
+			}
+			if service.DiskWriteBytesPerSec != nil {
+				summary.DiskWriteBytesPerSec += *service.DiskWriteBytesPerSec
+				group.DiskWriteBytesPerSec += *service.DiskWriteBytesPerSec
+			}
+			if service.NetSentBytesPerSec != nil {
+				summary.NetSentBytesPerSec += *service.NetSentBytesPerSec
+				group.NetSentBytesPerSec += *service.NetSentBytesPerSec
+			}
+			if service.NetReceivedBytesPerSec != nil {
+				summary.NetReceivedBytesPerSec += *service.NetReceivedBytesPerSec
+				group.NetReceivedBytesPerSec += *service.NetReceivedBytesPerSec
+			}
+		}
+	}
+	if ungrouped.Total > 0 {
+		summary.Groups = append(summary.Groups, *ungrouped)
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: summary})
+}
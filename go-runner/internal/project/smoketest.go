@@ -0,0 +1,428 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// smokeTestHistory is the number of smoke test runs kept per project
+const smokeTestHistory = 50
+
+// Smoke test defaults
+const (
+	defaultSmokeTestTimeout = 5 * time.Second
+	maxSmokeTestBody        = 1 << 20 // Bytes of the response searched for body_contains
+	// After a start, requests the service does not answer yet are sent again
+	// for this long, as it may still be binding its port
+	smokeTestStartGrace = 30 * time.Second
+)
+
+// smokeTestFailure prefixes the last_error of projects set to error by a
+// failing smoke test run, so a passing run can clear it
+const smokeTestFailure = "Smoke tests failed: "
+
+// errSmokeTestsRunning is returned when a run of the smoke tests of the
+// project is in progress
+var errSmokeTestsRunning = errors.New("smoke tests are already running")
+
+// Smoke test run triggers
+const (
+	SmokeTriggerManual = "manual" // POST /projects/:id/smoke-tests/run
+	SmokeTriggerStart  = "start"  // After a start, with smoke_tests_on_start
+)
+
+// SmokeTest is a request sent to a running project, with the answer it must
+// give
+type SmokeTest struct {
+	Name         string            `json:"name,omitempty"`
+	Method       string            `json:"method,omitempty"` // GET when empty
+	Path         string            `json:"path"`             // /path on the project port, or a full http(s) URL
+	Headers      map[string]string `json:"headers,omitempty"`
+	Body         string            `json:"body,omitempty"`
+	Status       int               `json:"status,omitempty"`        // Expected status, any below 400 when 0
+	BodyContains string            `json:"body_contains,omitempty"` // Substring the response body must contain
+	Timeout      int               `json:"timeout,omitempty"`       // Seconds, 5 when 0
+}
+
+// SmokeTestResult is the outcome of one smoke test
+type SmokeTestResult struct {
+	Name       string `json:"name"`
+	Method     string `json:"method"`
+	URL        string `json:"url"`
+	Passed     bool   `json:"passed"`
+	Status     int    `json:"status,omitempty"` // Status answered, 0 when the request failed
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"` // Why it failed
+}
+
+// SmokeTestRun is a run of the smoke tests of a project
+type SmokeTestRun struct {
+	ID         uint              `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ProjectID  uint              `json:"project_id" gorm:"index;not null"`
+	Trigger    string            `json:"trigger"` // manual, start
+	Passed     bool              `json:"passed"`
+	Total      int               `json:"total"`
+	Failed     int               `json:"failed"`
+	DurationMs int64             `json:"duration_ms"`
+	Results    []SmokeTestResult `json:"results" gorm:"type:text;serializer:json"`
+}
+
+// ParseSmokeTests parses the smoke_tests of a project, a JSON array of
+// requests
+func ParseSmokeTests(spec string) ([]SmokeTest, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var tests []SmokeTest
+	if err := json.Unmarshal([]byte(spec), &tests); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of {\"method\", \"path\", \"status\", \"body_contains\"}: %v", err)
+	}
+	for i := range tests {
+		if err := normalizeSmokeTest(&tests[i]); err != nil {
+			return nil, fmt.Errorf("test %d: %v", i+1, err)
+		}
+	}
+	return tests, nil
+}
+
+// normalizeSmokeTest checks a request and fills in its defaults
+func normalizeSmokeTest(t *SmokeTest) error {
+	t.Method = strings.ToUpper(strings.TrimSpace(t.Method))
+	if t.Method == "" {
+		t.Method = http.MethodGet
+	}
+	switch t.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		return fmt.Errorf("unsupported method %q", t.Method)
+	}
+	t.Path = strings.TrimSpace(t.Path)
+	if !strings.HasPrefix(t.Path, "/") && !strings.HasPrefix(t.Path, "http://") && !strings.HasPrefix(t.Path, "https://") {
+		return errors.New("path must start with / or be an http(s) URL")
+	}
+	if t.Status != 0 && (t.Status < 100 || t.Status > 599) {
+		return fmt.Errorf("status %d must be between 100 and 599", t.Status)
+	}
+	if t.Timeout < 0 || t.Timeout > 300 {
+		return errors.New("timeout must be between 0 and 300 seconds")
+	}
+	if t.Name == "" {
+		t.Name = t.Method + " " + t.Path
+	}
+	return nil
+}
+
+// SmokeTester runs the smoke tests of projects, after each start of those
+// with smoke_tests_on_start and on demand
+type SmokeTester struct {
+	db      *gorm.DB
+	manager *service.Manager
+	events  *events.Bus
+}
+
+// smokeTestsRunning holds the projects whose smoke tests are running, shared
+// by the testers of the status listener and of the handler
+var smokeTestsRunning = struct {
+	sync.Mutex
+	projects map[uint]bool
+}{projects: make(map[uint]bool)}
+
+// NewSmokeTester creates a smoke tester
+func NewSmokeTester(db *gorm.DB, manager *service.Manager, bus *events.Bus) *SmokeTester {
+	return &SmokeTester{db: db, manager: manager, events: bus}
+}
+
+// StatusChanged runs the smoke tests of a project with smoke_tests_on_start
+// once it is running, not when it is resumed from a pause
+func (s *SmokeTester) StatusChanged(change service.StatusChange) {
+	if change.Status != string(StatusRunning) || change.PreviousStatus == string(StatusRunning) || change.PreviousStatus == string(StatusPaused) {
+		return
+	}
+	var project Project
+	if err := s.db.Select("id, smoke_tests, smoke_tests_on_start").First(&project, change.ProjectID).Error; err != nil ||
+		!project.SmokeTestsOnStart || strings.TrimSpace(project.SmokeTests) == "" {
+		return
+	}
+	if _, err := s.Run(project.ID, SmokeTriggerStart); err != nil {
+		log.Printf("Failed to run smoke tests of project %d: %v", project.ID, err)
+	}
+}
+
+// Run sends the smoke tests of a project, stores the run and publishes it as
+// a "smoke_test" event. With smoke_tests_fail_error, a failed run sets the
+// project status to error while its process keeps running, and a passed run
+// sets it back to running.
+func (s *SmokeTester) Run(projectID uint, trigger string) (*SmokeTestRun, error) {
+	var project Project
+	if err := s.db.Select("id, name, port, health_check_url, smoke_tests, smoke_tests_fail_error").First(&project, projectID).Error; err != nil {
+		return nil, err
+	}
+	tests, err := ParseSmokeTests(project.SmokeTests)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smoke_tests: %v", err)
+	}
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("project %s has no smoke_tests", project.Name)
+	}
+
+	smokeTestsRunning.Lock()
+	if smokeTestsRunning.projects[projectID] {
+		smokeTestsRunning.Unlock()
+		return nil, errSmokeTestsRunning
+	}
+	smokeTestsRunning.projects[projectID] = true
+	smokeTestsRunning.Unlock()
+	defer func() {
+		smokeTestsRunning.Lock()
+		delete(smokeTestsRunning.projects, projectID)
+		smokeTestsRunning.Unlock()
+	}()
+
+	base := projectBaseURL(s.db, s.manager, &project)
+	run := &SmokeTestRun{ProjectID: projectID, Trigger: trigger, Total: len(tests), Results: []SmokeTestResult{}}
+	started := time.Now()
+	for _, test := range tests {
+		result := runSmokeTest(test, base)
+		sent := base != "" || !strings.HasPrefix(test.Path, "/")
+		for trigger == SmokeTriggerStart && sent && result.Status == 0 && time.Since(started) < smokeTestStartGrace {
+			time.Sleep(time.Second)
+			result = runSmokeTest(test, base)
+		}
+		if !result.Passed {
+			run.Failed++
+		}
+		run.Results = append(run.Results, result)
+	}
+	run.Passed = run.Failed == 0
+	run.DurationMs = time.Since(started).Milliseconds()
+
+	if err := s.db.Create(run).Error; err != nil {
+		return nil, fmt.Errorf("failed to save smoke test run: %v", err)
+	}
+	var stale []uint
+	s.db.Model(&SmokeTestRun{}).Where("project_id = ?", projectID).
+		Order("id DESC").Offset(smokeTestHistory).Pluck("id", &stale)
+	if len(stale) > 0 {
+		s.db.Delete(&SmokeTestRun{}, stale)
+	}
+	s.events.Publish(projectID, "smoke_test", run)
+
+	if project.SmokeTestsFailError {
+		s.applyStatus(projectID, run)
+	}
+	return run, nil
+}
+
+// applyStatus sets a running project to error when its smoke tests failed,
+// and back to running when they pass again
+func (s *SmokeTester) applyStatus(projectID uint, run *SmokeTestRun) {
+	var current Project
+	if err := s.db.Select("id, status, last_error").First(&current, projectID).Error; err != nil {
+		return
+	}
+	if !run.Passed && current.Status == StatusRunning && s.manager.IsServiceRunning(projectID) {
+		var failed []string
+		for _, result := range run.Results {
+			if !result.Passed {
+				failed = append(failed, result.Name+": "+result.Error)
+			}
+		}
+		reason := smokeTestFailure + strings.Join(failed, "; ")
+		s.db.Model(&Project{}).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":     string(StatusError),
+			"last_error": reason,
+		})
+		s.manager.RecordStatus(projectID, string(StatusError), reason)
+	} else if run.Passed && current.Status == StatusError && strings.HasPrefix(current.LastError, smokeTestFailure) && s.manager.IsServiceRunning(projectID) {
+		s.db.Model(&Project{}).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":     string(StatusRunning),
+			"last_error": "",
+		})
+		s.manager.RecordStatus(projectID, string(StatusRunning), "Smoke tests passed")
+	}
+}
+
+// projectBaseURL returns the URL paths of smoke tests and monitors are
+// resolved against: the project port on localhost, else its first declared
+// TCP port, else the host of its health check URL
+func projectBaseURL(db *gorm.DB, manager *service.Manager, project *Project) string {
+	// After a blue/green restart the service may listen on another port
+	port := manager.ActivePort(project.ID, project.Port)
+	if port <= 0 {
+		var declared ProjectPort
+		if err := db.Where("project_id = ? AND protocol = ?", project.ID, ProtocolTCP).Order("id").Take(&declared).Error; err == nil {
+			port = declared.Number
+		}
+	}
+	if port > 0 {
+		return "http://127.0.0.1:" + strconv.Itoa(port)
+	}
+	if u, err := url.Parse(project.HealthCheckURL); err == nil && u.Host != "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return ""
+}
+
+// runSmokeTest sends one smoke test and checks the answer
+func runSmokeTest(test SmokeTest, base string) SmokeTestResult {
+	result := SmokeTestResult{Name: test.Name, Method: test.Method, URL: test.Path}
+	if strings.HasPrefix(test.Path, "/") {
+		if base == "" {
+			result.Error = "the project has no port, declared port or health_check_url to send " + test.Path + " to"
+			return result
+		}
+		result.URL = base + test.Path
+	}
+	timeout := defaultSmokeTestTimeout
+	if test.Timeout > 0 {
+		timeout = time.Duration(test.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var body io.Reader
+	if test.Body != "" {
+		body = strings.NewReader(test.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, test.Method, result.URL, body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for name, value := range test.Headers {
+		req.Header.Set(name, value)
+	}
+	if test.Body != "" && req.Header.Get("Content-Type") == "" {
+		if json.Valid([]byte(test.Body)) {
+			req.Header.Set("Content-Type", "application/json")
+		} else {
+			req.Header.Set("Content-Type", "text/plain")
+		}
+	}
+
+	started := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		result.DurationMs = time.Since(started).Milliseconds()
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxSmokeTestBody))
+	result.DurationMs = time.Since(started).Milliseconds()
+	result.Status = resp.StatusCode
+
+	switch {
+	case test.Status != 0 && resp.StatusCode != test.Status:
+		result.Error = fmt.Sprintf("expected status %d, got %d", test.Status, resp.StatusCode)
+	case test.Status == 0 && resp.StatusCode >= 400:
+		result.Error = fmt.Sprintf("expected a status below 400, got %d", resp.StatusCode)
+	case err != nil:
+		result.Error = "failed to read the body: " + err.Error()
+	case test.BodyContains != "" && !strings.Contains(string(content), test.BodyContains):
+		result.Error = fmt.Sprintf("body does not contain %q", test.BodyContains)
+	default:
+		result.Passed = true
+	}
+	return result
+}
+
+// latestSmokeTestRun returns the last smoke test run of a project, nil when
+// its smoke tests never ran
+func latestSmokeTestRun(db *gorm.DB, projectID uint) *SmokeTestRun {
+	var run SmokeTestRun
+	if err := db.Where("project_id = ?", projectID).Order("id DESC").Take(&run).Error; err != nil {
+		return nil
+	}
+	return &run
+}
+
+// RunSmokeTests godoc
+// @Summary      Run project smoke tests
+// @Description  Send the smoke_tests of a project (a JSON array of {"name", "method", "path", "headers", "body", "status", "body_contains", "timeout"}) to it and store the run. Paths are sent to the project port, or its first declared TCP port, on 127.0.0.1, or else to the host of its health_check_url; full http(s) URLs are sent as-is. A test passes with the expected status (any below 400 when 0) and a body containing body_contains. With smoke_tests_on_start, the tests also run after every start (requests without an answer are sent again for up to 30 seconds), and with smoke_tests_fail_error a failed run sets the project status to error while a passed run sets it back to running. The run is published as a "smoke_test" event, and the last one is shown as smoke_test_run in the project status.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=SmokeTestRun}  "Smoke test run, passed or not"
+// @Failure      400  {object}  middleware.ErrorResponse  "No or invalid smoke tests"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409  {object}  middleware.ErrorResponse  "Smoke tests are already running"
+// @Router       /projects/{id}/smoke-tests/run [post]
+func (h *Handler) RunSmokeTests(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id, name, smoke_tests").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	tests, err := ParseSmokeTests(project.SmokeTests)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid smoke_tests", err.Error()))
+		return
+	}
+	if len(tests) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "No smoke tests", "set smoke_tests on project "+project.Name))
+		return
+	}
+
+	run, err := NewSmokeTester(h.db, h.manager, h.events).Run(project.ID, SmokeTriggerManual)
+	if err != nil {
+		if errors.Is(err, errSmokeTestsRunning) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Smoke tests are already running", err.Error()))
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to run smoke tests", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: run})
+}
+
+// GetSmokeTestRuns godoc
+// @Summary      List smoke test runs
+// @Description  Get the smoke test runs of a project, newest first
+// @Tags         projects
+// @Produce      json
+// @Param        id     path      int  true   "Project ID"
+// @Param        limit  query     int  false  "Maximum number of runs (default 20, max 50)"
+// @Success      200    {object}  types.DataResponse{data=[]SmokeTestRun}
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /projects/{id}/smoke-tests [get]
+func (h *Handler) GetSmokeTestRuns(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > smokeTestHistory {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "limit must be between 1 and 50", raw))
+			return
+		}
+	}
+
+	runs := []SmokeTestRun{}
+	if err := h.db.Where("project_id = ?", id).Order("id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch smoke test runs", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: runs})
+}
@@ -0,0 +1,249 @@
+package project
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/maintenance"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// statusPageDays is how many days of uptime the status page shows
+const statusPageDays = 90
+
+// Public states of the status page
+const (
+	StateOperational = "operational"
+	StateDegraded    = "degraded"
+	StateDown        = "down"
+	StateMaintenance = "maintenance"
+	StateNoData      = "no_data"
+)
+
+// StatusPage is the public status of the projects listed on the status page
+type StatusPage struct {
+	Title     string              `json:"title"`
+	State     string              `json:"state"` // Worst state of the services
+	UpdatedAt time.Time           `json:"updated_at"`
+	Services  []StatusPageService `json:"services"`
+}
+
+// StatusPageService is a project as shown on the status page, without any
+// internal details
+type StatusPageService struct {
+	Name          string          `json:"name"`
+	State         string          `json:"state"`
+	UptimePercent *float64        `json:"uptime_percent,omitempty"` // Over the shown days
+	Days          []StatusPageDay `json:"days"`                     // Oldest first, today last
+}
+
+// StatusPageDay is the uptime of a service on one day
+type StatusPageDay struct {
+	Date          string   `json:"date"` // YYYY-MM-DD, server local time
+	State         string   `json:"state"`
+	UptimePercent *float64 `json:"uptime_percent,omitempty"`
+}
+
+// stateSeverity orders public states from worst to best
+var stateSeverity = map[string]int{
+	StateDown:        0,
+	StateDegraded:    1,
+	StateMaintenance: 2,
+	StateOperational: 3,
+	StateNoData:      4,
+}
+
+// statusPageHandler serves the public status page
+type statusPageHandler struct {
+	db    *gorm.DB
+	title string
+}
+
+// RegisterStatusPage registers the public, read-only status page at /status
+// (HTML) and /status.json
+func RegisterStatusPage(r gin.IRouter, db *gorm.DB, title string) {
+	h := &statusPageHandler{db: db, title: title}
+	r.GET("/status", h.page)
+	r.GET("/status.json", h.GetStatusPage)
+}
+
+// GetStatusPage godoc
+// @Summary      Public status page data
+// @Description  Get the state and daily uptime over the last 90 days of the projects with "status_page": true, under their status_page_name. Read-only and meant to be public: no IDs, paths or errors are included. The HTML page is served at /status.
+// @Tags         status
+// @Produce      json
+// @Success      200  {object}  StatusPage
+// @Router       /status.json [get]
+func (h *statusPageHandler) GetStatusPage(c *gin.Context) {
+	page, err := h.build()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Status unavailable"})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *statusPageHandler) page(c *gin.Context) {
+	page, err := h.build()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Status unavailable")
+		return
+	}
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := statusPageTemplate.Execute(c.Writer, page); err != nil {
+		log.Printf("Failed to render status page: %v", err)
+	}
+}
+
+// build computes the status page from the projects and their timelines
+func (h *statusPageHandler) build() (*StatusPage, error) {
+	var projects []Project
+	if err := h.db.Select("id, name, status, health_status, status_page_name").
+		Where("status_page = ?", true).Order("name").Find(&projects).Error; err != nil {
+		log.Printf("Failed to load status page projects: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := midnight.AddDate(0, 0, -(statusPageDays - 1))
+
+	page := &StatusPage{Title: h.title, State: StateNoData, UpdatedAt: now, Services: []StatusPageService{}}
+	for i := range projects {
+		p := &projects[i]
+		timeline, err := buildTimeline(h.db, p, from, now, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		service := StatusPageService{
+			Name:          p.StatusPageName,
+			State:         h.currentState(p),
+			UptimePercent: timeline.UptimePercent,
+			Days:          make([]StatusPageDay, 0, statusPageDays),
+		}
+		if service.Name == "" {
+			service.Name = p.Name
+		}
+		for day := from; day.Before(now); day = day.AddDate(0, 0, 1) {
+			end := day.AddDate(0, 0, 1)
+			if end.After(now) {
+				end = now
+			}
+			_, uptime := summarize(timeline.Segments, day, end)
+			service.Days = append(service.Days, StatusPageDay{Date: day.Format("2006-01-02"), State: uptimeState(uptime), UptimePercent: uptime})
+		}
+
+		if stateSeverity[service.State] < stateSeverity[page.State] {
+			page.State = service.State
+		}
+		page.Services = append(page.Services, service)
+	}
+	return page, nil
+}
+
+// currentState maps the status and health of a project to a public state
+func (h *statusPageHandler) currentState(p *Project) string {
+	if window := maintenance.Open(h.db, p.ID); window != nil {
+		return StateMaintenance
+	}
+	switch p.Status {
+	case StatusRunning:
+		if p.HealthStatus == "unhealthy" {
+			return StateDegraded
+		}
+		return StateOperational
+	case StatusStarting, StatusStopping, StatusPaused:
+		return StateDegraded
+	case StatusUnknown, StatusUnreachable:
+		return StateNoData
+	}
+	return StateDown
+}
+
+// uptimeState maps the uptime of a day to a public state
+func uptimeState(uptime *float64) string {
+	switch {
+	case uptime == nil:
+		return StateNoData
+	case *uptime >= 99.9:
+		return StateOperational
+	case *uptime >= 95:
+		return StateDegraded
+	}
+	return StateDown
+}
+
+var statusPageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"percent": func(v *float64) string {
+		if v == nil {
+			return "no data"
+		}
+		return formatPercent(*v)
+	},
+	"label": func(state string) string {
+		switch state {
+		case StateOperational:
+			return "Operational"
+		case StateDegraded:
+			return "Degraded"
+		case StateDown:
+			return "Down"
+		case StateMaintenance:
+			return "Under maintenance"
+		}
+		return "No data"
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta http-equiv="refresh" content="60">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; background: #f6f7f9; color: #1f2933; margin: 0; }
+main { max-width: 860px; margin: 40px auto; padding: 0 16px; }
+h1 { font-size: 24px; }
+.banner { padding: 16px; border-radius: 8px; color: #fff; font-weight: 600; margin-bottom: 24px; }
+.service { background: #fff; border-radius: 8px; padding: 16px; margin-bottom: 12px; box-shadow: 0 1px 2px rgba(0,0,0,.06); }
+.head { display: flex; justify-content: space-between; margin-bottom: 10px; }
+.bars { display: flex; gap: 2px; height: 32px; }
+.bars span { flex: 1; border-radius: 2px; }
+.foot { display: flex; justify-content: space-between; font-size: 12px; color: #7b8794; margin-top: 6px; }
+.operational { background: #3ebd93; } .degraded { background: #f0b429; } .down { background: #e12d39; }
+.maintenance { background: #4098d7; } .no_data { background: #cbd2d9; }
+.text-operational { color: #199473; } .text-degraded { color: #cb6e17; } .text-down { color: #cf1124; }
+.text-maintenance { color: #186faf; } .text-no_data { color: #7b8794; }
+footer { font-size: 12px; color: #7b8794; text-align: center; margin-top: 24px; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<div class="banner {{.State}}">{{if eq .State "operational"}}All systems operational{{else}}{{label .State}}{{end}}</div>
+{{range .Services}}
+<section class="service">
+  <div class="head"><strong>{{.Name}}</strong><span class="text-{{.State}}">{{label .State}}</span></div>
+  <div class="bars">{{range .Days}}<span class="{{.State}}" title="{{.Date}}: {{percent .UptimePercent}}"></span>{{end}}</div>
+  <div class="foot"><span>90 days ago</span><span>{{percent .UptimePercent}} uptime</span><span>Today</span></div>
+</section>
+{{else}}
+<p>No services are listed on this status page.</p>
+{{end}}
+<footer>Updated {{.UpdatedAt.Format "2006-01-02 15:04:05 MST"}}</footer>
+</main>
+</body>
+</html>
+`))
+
+// formatPercent formats an uptime with two decimals, e.g. 99.95%
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64) + "%"
+}
@@ -0,0 +1,224 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/system"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectsSummary is what the overview page shows about all projects,
+// computed with aggregate queries
+type ProjectsSummary struct {
+	Total         int                 `json:"total"`
+	ByStatus      map[string]int      `json:"by_status"` // Status as stored, see GET /services/summary for the live one
+	ByType        map[string]int      `json:"by_type"`
+	ByGroup       []GroupCount        `json:"by_group"` // Ungrouped projects last, with a null group_id
+	Restarts24h   int                 `json:"restarts_24h"`
+	Crashes24h    int                 `json:"crashes_24h"`
+	ActiveAlerts  int                 `json:"active_alerts"` // Including host-wide alerts
+	ProjectAlerts []ProjectAlertCount `json:"project_alerts"`
+	RecentCrashes []RecentCrash       `json:"recent_crashes"`
+	GeneratedAt   time.Time           `json:"generated_at"`
+}
+
+// GroupCount is the number of projects of a group
+type GroupCount struct {
+	GroupID *uint  `json:"group_id"`
+	Name    string `json:"name"`
+	Count   int    `json:"count"`
+}
+
+// ProjectAlertCount is the number of active alerts of a project
+type ProjectAlertCount struct {
+	ProjectID uint   `json:"project_id"`
+	Name      string `json:"name"`
+	Count     int    `json:"count"`
+}
+
+// RecentCrash is the last crash of a project
+type RecentCrash struct {
+	ProjectID uint      `json:"project_id"`
+	Name      string    `json:"name"`
+	Time      time.Time `json:"time"`
+	Reason    string    `json:"reason"`
+	Status    string    `json:"status"` // Current status
+}
+
+// GetProjectsSummary godoc
+// @Summary      Summarize projects
+// @Description  Get everything the overview page needs in one request: the number of projects by status, type and group, the restarts (starts of a project after its first one of the period) and crashes (transitions to error) of the last 24 hours, the active alerts per project, and the services that crashed most recently, one entry per project
+// @Tags         projects
+// @Produce      json
+// @Param        crashes  query     int  false  "Recent crashes to return (default 5, max 50)"
+// @Success      200      {object}  types.DataResponse{data=ProjectsSummary}
+// @Router       /projects/summary [get]
+func (h *Handler) GetProjectsSummary(c *gin.Context) {
+	limit := 5
+	if raw := c.Query("crashes"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			limit = n
+		}
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	summary := ProjectsSummary{
+		ByStatus:      make(map[string]int),
+		ByType:        make(map[string]int),
+		ByGroup:       []GroupCount{},
+		ProjectAlerts: []ProjectAlertCount{},
+		RecentCrashes: []RecentCrash{},
+		GeneratedAt:   time.Now(),
+	}
+	if err := h.summarizeProjects(&summary, limit); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to summarize projects", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: summary})
+}
+
+func (h *Handler) summarizeProjects(summary *ProjectsSummary, crashLimit int) error {
+	var counts []struct {
+		Label string
+		Count int
+	}
+	for _, column := range []struct {
+		name   string
+		target map[string]int
+		empty  string
+	}{{"status", summary.ByStatus, string(StatusStopped)}, {"type", summary.ByType, string(types.TypeOther)}} {
+		counts = nil
+		if err := h.db.Model(&Project{}).Select(column.name + " AS label, COUNT(*) AS count").Group(column.name).Scan(&counts).Error; err != nil {
+			return err
+		}
+		for _, row := range counts {
+			key := row.Label
+			if key == "" {
+				key = column.empty
+			}
+			column.target[key] += row.Count
+			if column.name == "status" {
+				summary.Total += row.Count
+			}
+		}
+	}
+
+	var groups []struct {
+		GroupID *uint
+		Name    string
+		Count   int
+	}
+	if err := h.db.Model(&Project{}).Table(tables.From("projects")).
+		Select("projects.group_id, COALESCE(project_groups.name, '') AS name, COUNT(*) AS count").
+		Joins("LEFT JOIN " + tables.From("project_groups") + " ON project_groups.id = projects.group_id AND project_groups.deleted_at IS NULL").
+		Group("projects.group_id, project_groups.name").
+		Order("project_groups.name IS NULL, project_groups.name").
+		Scan(&groups).Error; err != nil {
+		return err
+	}
+	var ungrouped *GroupCount
+	for _, row := range groups {
+		if row.GroupID == nil || row.Name == "" {
+			// Projects of deleted groups count as ungrouped
+			if ungrouped == nil {
+				ungrouped = &GroupCount{Name: "Ungrouped"}
+			}
+			ungrouped.Count += row.Count
+			continue
+		}
+		summary.ByGroup = append(summary.ByGroup, GroupCount{GroupID: row.GroupID, Name: row.Name, Count: row.Count})
+	}
+	if ungrouped != nil {
+		summary.ByGroup = append(summary.ByGroup, *ungrouped)
+	}
+
+	// A restart is a start of a project after its first one of the period,
+	// resuming from a pause is not a start
+	since := time.Now().Add(-24 * time.Hour)
+	var transitions struct {
+		Starts   int
+		Projects int
+		Crashes  int
+	}
+	if err := h.db.Model(&ProjectStatusHistory{}).
+		Select("COALESCE(SUM(CASE WHEN status = ? AND previous_status <> ? THEN 1 ELSE 0 END), 0) AS starts, COUNT(DISTINCT CASE WHEN status = ? AND previous_status <> ? THEN project_id END) AS projects, COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS crashes",
+			string(StatusRunning), string(StatusPaused), string(StatusRunning), string(StatusPaused), string(StatusError)).
+		Where("timestamp >= ?", since).
+		Scan(&transitions).Error; err != nil {
+		return err
+	}
+	summary.Restarts24h = transitions.Starts - transitions.Projects
+	summary.Crashes24h = transitions.Crashes
+
+	if err := h.summarizeAlerts(summary); err != nil {
+		return err
+	}
+
+	if crashLimit == 0 {
+		return nil
+	}
+	latest := h.db.Model(&ProjectStatusHistory{}).Select("MAX(id)").Where("status = ?", string(StatusError)).Group("project_id")
+	if err := h.db.Model(&ProjectStatusHistory{}).Table(tables.From("project_status_histories")).
+		Select("project_status_histories.project_id, projects.name, project_status_histories.timestamp AS time, project_status_histories.reason, projects.status").
+		Joins("JOIN "+tables.From("projects")+" ON projects.id = project_status_histories.project_id AND projects.deleted_at IS NULL").
+		Where("project_status_histories.id IN (?)", latest).
+		Order("project_status_histories.timestamp desc, project_status_histories.id desc").
+		Limit(crashLimit).
+		Scan(&summary.RecentCrashes).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// summarizeAlerts counts the active alerts, and those of each project: the
+// alerts of its processes ("Process <name>") and queues ("Queue <name>/...")
+func (h *Handler) summarizeAlerts(summary *ProjectsSummary) error {
+	var resources []struct {
+		Resource string
+		Count    int
+	}
+	if err := h.db.Model(&system.SystemAlert{}).
+		Select("COALESCE(resource, '') AS resource, COUNT(*) AS count").
+		Where("is_active = ?", true).
+		Group("resource").
+		Scan(&resources).Error; err != nil {
+		return err
+	}
+
+	byName := make(map[string]int)
+	for _, row := range resources {
+		summary.ActiveAlerts += row.Count
+		if name, ok := strings.CutPrefix(row.Resource, "Process "); ok {
+			byName[name] += row.Count
+		} else if rest, ok := strings.CutPrefix(row.Resource, "Queue "); ok {
+			if i := strings.LastIndex(rest, "/"); i > 0 {
+				byName[rest[:i]] += row.Count
+			}
+		}
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	var projects []Project
+	if err := h.db.Select("id, name").Where("name IN ?", names).Order("name").Find(&projects).Error; err != nil {
+		return err
+	}
+	for _, project := range projects {
+		summary.ProjectAlerts = append(summary.ProjectAlerts, ProjectAlertCount{ProjectID: project.ID, Name: project.Name, Count: byName[project.Name]})
+	}
+	return nil
+}
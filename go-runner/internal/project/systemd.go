@@ -0,0 +1,68 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSystemdUnits godoc
+// @Summary      List systemd service units
+// @Description  List the service units of the host (or of the user manager with user=true), to create "systemd" projects for daemons such as postgres or nginx.
+// @Tags         systemd
+// @Produce      json
+// @Param        user  query     bool  false  "Units of the user manager (systemctl --user)"
+// @Success      200   {object}  types.DataResponse{data=[]service.SystemdUnitSummary}
+// @Failure      502   {object}  middleware.ErrorResponse  "systemd request failed"
+// @Failure      503   {object}  middleware.ErrorResponse  "systemd is not available"
+// @Router       /systemd/units [get]
+func (h *Handler) GetSystemdUnits(c *gin.Context) {
+	units, err := h.manager.ListSystemdUnits(c.Request.Context(), c.Query("user") == "true")
+	if err != nil {
+		middleware.HandleError(c, systemdError(err))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: units})
+}
+
+// GetProjectSystemd godoc
+// @Summary      Get the unit of a systemd project
+// @Description  Read the unit of a "systemd" project now (ActiveState, SubState, main PID) and sync the project status with it. systemd projects are also checked every 15 seconds; the last result is included as "systemd" in the project status.
+// @Tags         systemd
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.SystemdUnit}
+// @Failure      400  {object}  middleware.ErrorResponse  "Not a systemd project"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/systemd [get]
+func (h *Handler) GetProjectSystemd(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id, type").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	if project.Type != TypeSystemd {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project is not a systemd service", string(project.Type)))
+		return
+	}
+
+	unit, err := h.manager.CheckSystemd(c.Request.Context(), project.ID)
+	if err != nil {
+		middleware.HandleError(c, systemdError(err))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: unit})
+}
+
+// systemdError maps systemd errors to responses
+func systemdError(err error) *middleware.CustomError {
+	if errors.Is(err, service.ErrSystemdUnavailable) {
+		return middleware.NewError(http.StatusServiceUnavailable, "systemd is not available", err.Error())
+	}
+	return middleware.NewError(http.StatusBadGateway, "systemd request failed", err.Error())
+}
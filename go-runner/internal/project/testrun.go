@@ -0,0 +1,625 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// testRunHistory is the number of test runs kept per project
+const testRunHistory = 50
+
+// testFailureOutput bounds the output kept per failed test
+const testFailureOutput = 4096
+
+// Test frameworks, which decide how results are parsed
+const (
+	TestFrameworkGo     = "go"     // go test -json
+	TestFrameworkJest   = "jest"   // Jest --json report
+	TestFrameworkVitest = "vitest" // Vitest JSON reporter (Jest format)
+	TestFrameworkPytest = "pytest" // JUnit XML report
+	TestFrameworkOther  = "other"  // Exit code only
+)
+
+// Test run statuses
+const (
+	TestRunPassed = "passed"
+	TestRunFailed = "failed" // Some tests failed
+	TestRunError  = "error"  // The command failed without failed tests (build error, crash...)
+)
+
+// TestSummary counts the tests of a run
+type TestSummary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// TestFailure is a failed test with its output
+type TestFailure struct {
+	Name    string `json:"name"`
+	Suite   string `json:"suite,omitempty"` // Go package, test file or class
+	Message string `json:"message,omitempty"`
+}
+
+// TestRun is the result of a project test run
+type TestRun struct {
+	ID         uint          `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ProjectID  uint          `json:"project_id" gorm:"index;not null"`
+	JobID      uint          `json:"job_id"`
+	Framework  string        `json:"framework"`
+	Command    string        `json:"command"`
+	Status     string        `json:"status"` // passed, failed, error
+	ExitCode   int           `json:"exit_code"`
+	DurationMs int64         `json:"duration_ms"`
+	Summary    TestSummary   `json:"summary" gorm:"embedded;embeddedPrefix:summary_"`
+	Failures   []TestFailure `json:"failures,omitempty" gorm:"type:text;serializer:json"`
+}
+
+// TestRequest adds arguments to the test command
+type TestRequest struct {
+	Args []string `json:"args"` // e.g. ["-run", "TestLogin"] for go test, a file for pytest
+}
+
+// TestProgress is broadcast as "test_progress" each time a test finishes
+// (go test), or once the report is parsed
+type TestProgress struct {
+	JobID   uint        `json:"job_id"`
+	Test    string      `json:"test,omitempty"`
+	Result  string      `json:"result,omitempty"` // pass, fail, skip
+	Summary TestSummary `json:"summary"`
+}
+
+// testCommand is a test command and how to read its results
+type testCommand struct {
+	framework string
+	args      []string // Run directly, or through the shell when shell is set
+	shell     string
+	report    string // Report file written by the command
+}
+
+// RunTests godoc
+// @Summary      Run project tests
+// @Description  Run the project test_command, or the detected one (go test -json ./... for go.mod, the package.json test script with a Jest or Vitest JSON report, pytest with a JUnit XML report) as a background job, and store the pass/fail counts and failed tests as a test run. Output is streamed to the project WebSocket as "test_log" messages (InstallLogLine), progress as "test_progress" (TestProgress) and the stored run as "test_result". The job fails when tests fail; its result is the TestRun either way.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int          true   "Project ID"
+// @Param        request  body      TestRequest  false  "Extra arguments"
+// @Success      202      {object}  types.DataResponse{data=jobs.Job}  "Test job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "No test command found"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Tests are already running"
+// @Router       /projects/{id}/test [post]
+func (h *Handler) RunTests(c *gin.Context) {
+	var req TestRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	project, dir, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	command := resolveTestCommand(dir, project.TestCommand, req.Args)
+	if command == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "No test command found",
+			"set test_command, or add go.mod, a package.json test script or pytest configuration to "+dir))
+		return
+	}
+
+	env := os.Environ()
+	if spec, err := h.manager.ProjectLaunchSpec(project.ID); err == nil {
+		for k, v := range spec.Env {
+			env = append(env, k+"="+v)
+		}
+	}
+
+	projectID := project.ID
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:      jobs.TypeTest,
+		ProjectID: &projectID,
+		Key:       fmt.Sprintf("test:%d", projectID),
+		Message:   command.String(),
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		result, err := h.runTests(ctx, run, projectID, dir, env, command)
+		if result == nil {
+			return nil, err
+		}
+		if saveErr := h.saveTestRun(result); saveErr != nil && err == nil {
+			err = saveErr
+		}
+		h.events.Publish(projectID, "test_result", result)
+		return result, err
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Tests are already running for this project", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// GetTestRuns godoc
+// @Summary      List test runs
+// @Description  Get the test run history of a project, newest first, without the failed tests
+// @Tags         projects
+// @Produce      json
+// @Param        id     path      int  true   "Project ID"
+// @Param        limit  query     int  false  "Maximum number of runs (default 20, max 50)"
+// @Success      200    {object}  types.DataResponse{data=[]TestRun}
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /projects/{id}/test/runs [get]
+func (h *Handler) GetTestRuns(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return
+	}
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > testRunHistory {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "limit must be between 1 and 50", raw))
+			return
+		}
+	}
+
+	runs := []TestRun{}
+	if err := h.db.Omit("failures").Where("project_id = ?", id).Order("id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch test runs", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: runs})
+}
+
+// GetTestRun godoc
+// @Summary      Get a test run
+// @Description  Get a test run of a project with its failed tests
+// @Tags         projects
+// @Produce      json
+// @Param        id      path      int  true  "Project ID"
+// @Param        run_id  path      int  true  "Test run ID"
+// @Success      200     {object}  types.DataResponse{data=TestRun}
+// @Failure      404     {object}  middleware.ErrorResponse  "Test run not found"
+// @Router       /projects/{id}/test/runs/{run_id} [get]
+func (h *Handler) GetTestRun(c *gin.Context) {
+	var run TestRun
+	if err := h.db.Where("project_id = ?", c.Param("id")).First(&run, c.Param("run_id")).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			middleware.HandleError(c, middleware.ErrNotFound)
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch test run", err.Error()))
+		return
+	}
+	if run.Failures == nil {
+		run.Failures = []TestFailure{}
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: run})
+}
+
+// saveTestRun stores a test run and prunes the project's test history
+func (h *Handler) saveTestRun(run *TestRun) error {
+	if err := h.db.Create(run).Error; err != nil {
+		return fmt.Errorf("failed to save test run: %v", err)
+	}
+
+	var stale []uint
+	h.db.Model(&TestRun{}).Where("project_id = ?", run.ProjectID).
+		Order("id DESC").Offset(testRunHistory).Pluck("id", &stale)
+	if len(stale) > 0 {
+		h.db.Delete(&TestRun{}, stale)
+	}
+	return nil
+}
+
+// resolveTestCommand returns the configured test command, with the flags its
+// framework needs to report results, or the detected one
+func resolveTestCommand(dir, configured string, extra []string) *testCommand {
+	report := filepath.Join(os.TempDir(), fmt.Sprintf("go-runner-test-%d", time.Now().UnixNano()))
+
+	if configured = strings.TrimSpace(configured); configured != "" {
+		command := &testCommand{framework: TestFrameworkOther, shell: configured}
+		switch {
+		case strings.HasPrefix(configured, "go test"):
+			command.framework = TestFrameworkGo
+			if !strings.Contains(configured, "-json") {
+				command.shell = "go test -json" + strings.TrimPrefix(configured, "go test")
+			}
+		case strings.Contains(configured, "pytest") && !strings.Contains(configured, "--junitxml"):
+			command.framework = TestFrameworkPytest
+			command.report = report + ".xml"
+			command.shell += " --junitxml=" + command.report
+		}
+		for _, arg := range extra {
+			command.shell += " " + shellQuote(arg)
+		}
+		return command
+	}
+
+	switch {
+	case fileExists(filepath.Join(dir, "go.mod")):
+		return &testCommand{framework: TestFrameworkGo, args: append([]string{"go", "test", "-json", "./..."}, extra...)}
+
+	case fileExists(filepath.Join(dir, "package.json")):
+		var manifest struct {
+			Scripts         map[string]string `json:"scripts"`
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+		if json.Unmarshal(data, &manifest) != nil {
+			return nil
+		}
+		script := manifest.Scripts["test"]
+		if script == "" || strings.Contains(script, "no test specified") {
+			return nil
+		}
+
+		command := &testCommand{framework: TestFrameworkOther}
+		var flags []string
+		has := func(name string) bool {
+			_, dep := manifest.Dependencies[name]
+			_, dev := manifest.DevDependencies[name]
+			return dep || dev || strings.Contains(script, name)
+		}
+		switch {
+		case has("vitest"):
+			command.framework = TestFrameworkVitest
+			command.report = report + ".json"
+			flags = []string{"--reporter=default", "--reporter=json", "--outputFile.json=" + command.report}
+		case has("jest"):
+			command.framework = TestFrameworkJest
+			command.report = report + ".json"
+			flags = []string{"--json", "--outputFile=" + command.report}
+		}
+		flags = append(flags, extra...)
+
+		pm := nodePackageManager(dir)
+		command.args = []string{pm, "test"}
+		if pm == "npm" && len(flags) > 0 {
+			command.args = append(command.args, "--") // npm test passes only what follows -- to the script
+		}
+		command.args = append(command.args, flags...)
+		return command
+
+	case fileExists(filepath.Join(dir, "pytest.ini")), fileExists(filepath.Join(dir, "conftest.py")),
+		fileExists(filepath.Join(dir, "pyproject.toml")), fileExists(filepath.Join(dir, "setup.cfg")),
+		fileExists(filepath.Join(dir, "tox.ini")), fileExists(filepath.Join(dir, "requirements.txt")):
+		command := &testCommand{framework: TestFrameworkPytest, report: report + ".xml"}
+		command.args = append([]string{"pytest", "--junitxml=" + command.report}, extra...)
+		return command
+	}
+	return nil
+}
+
+// String returns the command line
+func (t *testCommand) String() string {
+	if t.shell != "" {
+		return t.shell
+	}
+	return strings.Join(t.args, " ")
+}
+
+// shellQuote quotes an argument for sh
+func shellQuote(arg string) string {
+	if arg != "" && !shellSyntax.MatchString(arg) && !strings.ContainsAny(arg, " \t\n") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// runTests runs a test command and collects its results. The error is set
+// when tests failed or the command could not run; the run is returned
+// whenever the command started.
+func (h *Handler) runTests(ctx context.Context, run *jobs.Run, projectID uint, dir string, env []string, command *testCommand) (*TestRun, error) {
+	var cmd *exec.Cmd
+	switch {
+	case command.shell != "" && runtime.GOOS == "windows":
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command.shell)
+	case command.shell != "":
+		cmd = exec.CommandContext(ctx, "sh", "-c", command.shell)
+	default:
+		if _, err := exec.LookPath(command.args[0]); err != nil {
+			return nil, fmt.Errorf("%s is not installed", command.args[0])
+		}
+		cmd = exec.CommandContext(ctx, command.args[0], command.args[1:]...)
+	}
+	cmd.Dir = dir
+	cmd.Env = env
+	if command.report != "" {
+		defer os.Remove(command.report)
+	}
+
+	result := &TestRun{ProjectID: projectID, JobID: run.ID(), Framework: command.framework, Command: command.String()}
+	progress := func(test, outcome string) {
+		run.Progress(0, fmt.Sprintf("%d passed, %d failed, %d skipped", result.Summary.Passed, result.Summary.Failed, result.Summary.Skipped))
+		h.events.Publish(projectID, "test_progress", TestProgress{JobID: run.ID(), Test: test, Result: outcome, Summary: result.Summary})
+	}
+	logLine := func(stream, line string) {
+		run.Log(line)
+		h.hub.BroadcastToProject(projectID, "test_log", InstallLogLine{JobID: run.ID(), Stream: stream, Line: line})
+	}
+
+	onLine := logLine
+	var goEvents *goTestEvents
+	if command.framework == TestFrameworkGo {
+		goEvents = newGoTestEvents(result, progress)
+		onLine = func(stream, line string) {
+			if output, ok := goEvents.add(line); ok {
+				if output != "" {
+					logLine(stream, output)
+				}
+				return
+			}
+			logLine(stream, line)
+		}
+	}
+
+	start := time.Now()
+	finished, err := runCommandLines(cmd, onLine)
+	if finished == nil {
+		return nil, err
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.ExitCode = finished.ExitCode
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	switch command.framework {
+	case TestFrameworkGo:
+		goEvents.finish()
+	case TestFrameworkJest, TestFrameworkVitest:
+		if parseErr := parseJestReport(command.report, result); parseErr != nil {
+			logLine("stderr", "Failed to read the test report: "+parseErr.Error())
+		}
+		progress("", "")
+	case TestFrameworkPytest:
+		if parseErr := parseJUnitReport(command.report, result); parseErr != nil {
+			logLine("stderr", "Failed to read the test report: "+parseErr.Error())
+		}
+		progress("", "")
+	}
+
+	switch {
+	case result.Summary.Failed > 0:
+		result.Status = TestRunFailed
+		return result, fmt.Errorf("%d of %d tests failed", result.Summary.Failed, result.Summary.Total)
+	case err != nil:
+		result.Status = TestRunError
+		return result, fmt.Errorf("test command failed: %v", err)
+	}
+	result.Status = TestRunPassed
+	run.Progress(100, fmt.Sprintf("%d passed, %d skipped", result.Summary.Passed, result.Summary.Skipped))
+	return result, nil
+}
+
+// goTestEvents collects go test -json (test2json) events
+type goTestEvents struct {
+	result   *TestRun
+	progress func(test, outcome string)
+	output   map[string]*strings.Builder // Output per package and per test
+	failed   map[string]bool             // Packages with a failed test
+}
+
+func newGoTestEvents(result *TestRun, progress func(test, outcome string)) *goTestEvents {
+	return &goTestEvents{result: result, progress: progress, output: make(map[string]*strings.Builder), failed: make(map[string]bool)}
+}
+
+// add handles an output line, returning the test output it carries and
+// whether it was an event
+func (g *goTestEvents) add(line string) (string, bool) {
+	var event struct {
+		Action  string `json:"Action"`
+		Package string `json:"Package"`
+		Test    string `json:"Test"`
+		Output  string `json:"Output"`
+	}
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil || event.Action == "" {
+		return "", false
+	}
+
+	key := event.Package + " " + event.Test
+	switch event.Action {
+	case "output":
+		b := g.output[key]
+		if b == nil {
+			b = &strings.Builder{}
+			g.output[key] = b
+		}
+		if b.Len() < testFailureOutput {
+			b.WriteString(event.Output)
+		}
+		return strings.TrimRight(event.Output, "\n"), true
+	case "pass", "fail", "skip":
+	default:
+		return "", true
+	}
+
+	if event.Test == "" {
+		// A package failing without failed tests did not build or crashed
+		if event.Action == "fail" && !g.failed[event.Package] {
+			g.result.Summary.Failed++
+			g.result.Summary.Total++
+			g.result.Failures = append(g.result.Failures, TestFailure{Name: event.Package, Suite: event.Package, Message: g.message(key)})
+		}
+		delete(g.output, key)
+		return "", true
+	}
+
+	g.result.Summary.Total++
+	switch event.Action {
+	case "pass":
+		g.result.Summary.Passed++
+	case "skip":
+		g.result.Summary.Skipped++
+	case "fail":
+		g.result.Summary.Failed++
+		g.failed[event.Package] = true
+		g.result.Failures = append(g.result.Failures, TestFailure{Name: event.Test, Suite: event.Package, Message: g.message(key)})
+	}
+	delete(g.output, key)
+	g.progress(event.Test, event.Action)
+	return "", true
+}
+
+// message returns the collected output of a test
+func (g *goTestEvents) message(key string) string {
+	if b := g.output[key]; b != nil {
+		return strings.TrimSpace(b.String())
+	}
+	return ""
+}
+
+// finish reports the final counts
+func (g *goTestEvents) finish() {
+	g.progress("", "")
+}
+
+// parseJestReport reads a Jest --json report, which Vitest also writes
+func parseJestReport(path string, result *TestRun) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var report struct {
+		TestResults []struct {
+			Name             string `json:"name"`
+			Status           string `json:"status"`
+			Message          string `json:"message"`
+			AssertionResults []struct {
+				FullName        string   `json:"fullName"`
+				Title           string   `json:"title"`
+				Status          string   `json:"status"` // passed, failed, pending, skipped, todo, disabled
+				FailureMessages []string `json:"failureMessages"`
+			} `json:"assertionResults"`
+		} `json:"testResults"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return err
+	}
+
+	for _, file := range report.TestResults {
+		// A file that failed to load has no assertions
+		if file.Status == "failed" && len(file.AssertionResults) == 0 {
+			result.Summary.Total++
+			result.Summary.Failed++
+			result.Failures = append(result.Failures, TestFailure{Name: filepath.Base(file.Name), Suite: file.Name, Message: truncateOutput(file.Message)})
+			continue
+		}
+		for _, test := range file.AssertionResults {
+			result.Summary.Total++
+			switch test.Status {
+			case "passed":
+				result.Summary.Passed++
+			case "failed":
+				result.Summary.Failed++
+				name := test.FullName
+				if name == "" {
+					name = test.Title
+				}
+				result.Failures = append(result.Failures, TestFailure{Name: name, Suite: file.Name, Message: truncateOutput(strings.Join(test.FailureMessages, "\n"))})
+			default:
+				result.Summary.Skipped++
+			}
+		}
+	}
+	return nil
+}
+
+// junitSuite is a <testsuite> or <testsuites> element of a JUnit XML report
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Failure   *junitMessage `xml:"failure"`
+		Error     *junitMessage `xml:"error"`
+		Skipped   *junitMessage `xml:"skipped"`
+	} `xml:"testcase"`
+}
+
+// junitMessage is a failure, error or skipped element
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// parseJUnitReport reads a JUnit XML report (pytest --junitxml)
+func parseJUnitReport(path string, result *TestRun) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+
+	var walk func(suite junitSuite)
+	walk = func(suite junitSuite) {
+		for _, test := range suite.Cases {
+			result.Summary.Total++
+			failure := test.Failure
+			if failure == nil {
+				failure = test.Error
+			}
+			switch {
+			case failure != nil:
+				result.Summary.Failed++
+				message := strings.TrimSpace(failure.Text)
+				if message == "" {
+					message = failure.Message
+				}
+				result.Failures = append(result.Failures, TestFailure{Name: test.Name, Suite: test.ClassName, Message: truncateOutput(message)})
+			case test.Skipped != nil:
+				result.Summary.Skipped++
+			default:
+				result.Summary.Passed++
+			}
+		}
+		for _, child := range suite.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return nil
+}
+
+// truncateOutput bounds the output kept for a failed test
+func truncateOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > testFailureOutput {
+		return output[:testFailureOutput] + "\n..."
+	}
+	return output
+}
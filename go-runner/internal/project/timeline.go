@@ -0,0 +1,310 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ProjectStatusHistory is a status transition of a project, recorded by the
+// service manager
+type ProjectStatusHistory struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ProjectID      uint      `json:"project_id" gorm:"index:,composite:lookup"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	Reason         string    `json:"reason"`
+	Timestamp      time.Time `json:"timestamp" gorm:"index:,composite:lookup"`
+}
+
+// StatusSegment is a period a project spent in one status
+type StatusSegment struct {
+	Status          string    `json:"status"`
+	Reason          string    `json:"reason,omitempty"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+// UptimeBucket is one bar of an uptime chart
+type UptimeBucket struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Status        string    `json:"status"`                   // Status with the longest time in the bucket (worst one for groups)
+	UptimePercent *float64  `json:"uptime_percent,omitempty"` // Time running over known time, absent when nothing is known
+}
+
+// StatusTimeline is the status history of a project over a period
+type StatusTimeline struct {
+	ProjectID     uint                `json:"project_id"`
+	ProjectName   string              `json:"project_name"`
+	From          time.Time           `json:"from"`
+	To            time.Time           `json:"to"`
+	UptimePercent *float64            `json:"uptime_percent,omitempty"`
+	Segments      []StatusSegment     `json:"segments"`
+	Buckets       []UptimeBucket      `json:"buckets"`
+	Annotations   []ProjectAnnotation `json:"annotations"` // Markers: deploys, config changes and notes
+}
+
+// GroupTimeline overlays the timelines of the projects of a group
+type GroupTimeline struct {
+	GroupID       uint             `json:"group_id"`
+	From          time.Time        `json:"from"`
+	To            time.Time        `json:"to"`
+	UptimePercent *float64         `json:"uptime_percent,omitempty"` // Average of the projects
+	Buckets       []UptimeBucket   `json:"buckets"`                  // Average uptime, worst status
+	Projects      []StatusTimeline `json:"projects"`
+}
+
+// statusSeverity orders statuses from worst to best for group buckets
+var statusSeverity = map[string]int{
+	string(StatusError):       0,
+	string(StatusStopped):     1,
+	string(StatusStopping):    2,
+	string(StatusPaused):      3,
+	string(StatusStarting):    4,
+	string(StatusRunning):     5,
+	string(StatusUnreachable): 6,
+	string(StatusUnknown):     7,
+}
+
+// GetProjectTimeline godoc
+// @Summary      Get the status timeline of a project
+// @Description  Get the status transitions of a project as segments, with uptime (time running over known time) overall and per bucket to render an uptime bar. Time before the first recorded transition is "unknown". Transitions are kept for 90 days.
+// @Tags         projects
+// @Produce      json
+// @Param        id       path      int  true   "Project ID"
+// @Param        hours    query     int  false  "Hours of history (default 24, max 2160)"
+// @Param        buckets  query     int  false  "Number of uptime buckets (default 24, max 200)"
+// @Success      200      {object}  types.DataResponse{data=StatusTimeline}
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/timeline [get]
+func (h *Handler) GetProjectTimeline(c *gin.Context) {
+	from, to, buckets, ok := timelineRange(c)
+	if !ok {
+		return
+	}
+
+	var project Project
+	if err := h.db.Select("id, name").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	timeline, err := buildTimeline(h.db, &project, from, to, buckets)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch status history", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: timeline})
+}
+
+// GetGroupTimeline godoc
+// @Summary      Get the status timelines of a group
+// @Description  Overlay the status timelines of the projects of a group. Group buckets average the uptime of the projects and take the worst status.
+// @Tags         groups
+// @Produce      json
+// @Param        id       path      int  true   "Group ID"
+// @Param        hours    query     int  false  "Hours of history (default 24, max 2160)"
+// @Param        buckets  query     int  false  "Number of uptime buckets (default 24, max 200)"
+// @Success      200      {object}  types.DataResponse{data=GroupTimeline}
+// @Failure      400      {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404      {object}  middleware.ErrorResponse  "Group not found"
+// @Router       /groups/{id}/timeline [get]
+func (h *Handler) GetGroupTimeline(c *gin.Context) {
+	from, to, buckets, ok := timelineRange(c)
+	if !ok {
+		return
+	}
+
+	var group ProjectGroup
+	if err := h.db.First(&group, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	var projects []Project
+	if err := h.db.Select("id, name").Where("group_id = ?", group.ID).Order("name").Find(&projects).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
+		return
+	}
+
+	result := GroupTimeline{GroupID: group.ID, From: from, To: to, Projects: []StatusTimeline{}}
+	for i := range projects {
+		timeline, err := buildTimeline(h.db, &projects[i], from, to, buckets)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch status history", err.Error()))
+			return
+		}
+		result.Projects = append(result.Projects, *timeline)
+	}
+
+	result.UptimePercent = averageUptime(result.Projects, func(t StatusTimeline) *float64 { return t.UptimePercent })
+	result.Buckets = make([]UptimeBucket, buckets)
+	for i := range result.Buckets {
+		bucket := UptimeBucket{Status: string(StatusUnknown)}
+		for j, t := range result.Projects {
+			b := t.Buckets[i]
+			bucket.Start, bucket.End = b.Start, b.End
+			if j == 0 || statusSeverity[b.Status] < statusSeverity[bucket.Status] {
+				bucket.Status = b.Status
+			}
+		}
+		bucket.UptimePercent = averageUptime(result.Projects, func(t StatusTimeline) *float64 { return t.Buckets[i].UptimePercent })
+		result.Buckets[i] = bucket
+	}
+	if len(result.Projects) == 0 {
+		result.Buckets = splitBuckets(from, to, buckets, nil)
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: result})
+}
+
+// timelineRange reads the hours and buckets query parameters
+func timelineRange(c *gin.Context) (from, to time.Time, buckets int, ok bool) {
+	hours, buckets := 24, 24
+	var err error
+	if raw := c.Query("hours"); raw != "" {
+		hours, err = strconv.Atoi(raw)
+		if err != nil || hours < 1 || hours > 2160 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "hours must be between 1 and 2160", raw))
+			return
+		}
+	}
+	if raw := c.Query("buckets"); raw != "" {
+		buckets, err = strconv.Atoi(raw)
+		if err != nil || buckets < 1 || buckets > 200 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "buckets must be between 1 and 200", raw))
+			return
+		}
+	}
+
+	to = time.Now()
+	return to.Add(-time.Duration(hours) * time.Hour), to, buckets, true
+}
+
+// buildTimeline turns the transitions of a project into segments and buckets
+func buildTimeline(db *gorm.DB, project *Project, from, to time.Time, buckets int) (*StatusTimeline, error) {
+	// Status at the start of the period
+	current := StatusSegment{Status: string(StatusUnknown), Start: from}
+	var before ProjectStatusHistory
+	if err := db.Where("project_id = ? AND timestamp < ?", project.ID, from).
+		Order("timestamp desc, id desc").Limit(1).Find(&before).Error; err != nil {
+		return nil, err
+	}
+	if before.ID != 0 {
+		current.Status, current.Reason = before.Status, before.Reason
+	}
+
+	var transitions []ProjectStatusHistory
+	if err := db.Where("project_id = ? AND timestamp >= ? AND timestamp <= ?", project.ID, from, to).
+		Order("timestamp asc, id asc").Find(&transitions).Error; err != nil {
+		return nil, err
+	}
+
+	segments := []StatusSegment{}
+	for _, t := range transitions {
+		if t.Timestamp.After(current.Start) {
+			current.End = t.Timestamp
+			segments = append(segments, current)
+		}
+		current = StatusSegment{Status: t.Status, Reason: t.Reason, Start: t.Timestamp}
+	}
+	current.End = to
+	segments = append(segments, current)
+	for i := range segments {
+		segments[i].DurationSeconds = segments[i].End.Sub(segments[i].Start).Seconds()
+	}
+
+	annotations, err := findAnnotations(db, project.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	_, uptime := summarize(segments, from, to)
+	return &StatusTimeline{
+		ProjectID:     project.ID,
+		ProjectName:   project.Name,
+		From:          from,
+		To:            to,
+		UptimePercent: uptime,
+		Segments:      segments,
+		Buckets:       splitBuckets(from, to, buckets, segments),
+		Annotations:   annotations,
+	}, nil
+}
+
+// splitBuckets divides a period into equal buckets summarizing the segments
+func splitBuckets(from, to time.Time, n int, segments []StatusSegment) []UptimeBucket {
+	size := to.Sub(from) / time.Duration(n)
+	buckets := make([]UptimeBucket, n)
+	for i := range buckets {
+		start := from.Add(time.Duration(i) * size)
+		end := start.Add(size)
+		if i == n-1 {
+			end = to
+		}
+		status, uptime := summarize(segments, start, end)
+		buckets[i] = UptimeBucket{Start: start, End: end, Status: status, UptimePercent: uptime}
+	}
+	return buckets
+}
+
+// summarize returns the status with the most time between start and end and
+// the running share of the known time
+func summarize(segments []StatusSegment, start, end time.Time) (string, *float64) {
+	durations := make(map[string]time.Duration)
+	for _, s := range segments {
+		from, to := s.Start, s.End
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			durations[s.Status] += to.Sub(from)
+		}
+	}
+
+	dominant := string(StatusUnknown)
+	var known time.Duration
+	for status, d := range durations {
+		if d > durations[dominant] || (d == durations[dominant] && statusSeverity[status] < statusSeverity[dominant]) {
+			dominant = status
+		}
+		if status != string(StatusUnknown) && status != string(StatusUnreachable) {
+			known += d
+		}
+	}
+	if known == 0 {
+		return dominant, nil
+	}
+	uptime := float64(durations[string(StatusRunning)]) / float64(known) * 100
+	return dominant, &uptime
+}
+
+// averageUptime averages the known uptimes of timelines
+func averageUptime(timelines []StatusTimeline, uptime func(StatusTimeline) *float64) *float64 {
+	var sum float64
+	var count int
+	for _, t := range timelines {
+		if u := uptime(t); u != nil {
+			sum += *u
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	average := sum / float64(count)
+	return &average
+}
@@ -0,0 +1,37 @@
+package project
+
+import (
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrace godoc
+// @Summary      Get the log lines of a trace
+// @Description  Gather the log lines mentioning a trace or request ID across all projects. IDs are parsed from W3C traceparent values and trace_id=, request_id=, X-Request-ID: style fields as lines are logged; buffered and stored logs are also searched for the raw ID. Projects started with trace_injection get a fresh ID per start, exposed as trace_id in their status.
+// @Tags         logs
+// @Produce      json
+// @Param        traceID  path      string  true  "Trace or request ID"
+// @Success      200      {object}  types.DataResponse{data=service.TraceResult}
+// @Failure      404      {object}  middleware.ErrorResponse  "No lines found for the trace"
+// @Failure      500      {object}  middleware.ErrorResponse  "Internal server error"
+// @Router       /logs/trace/{traceID} [get]
+func (h *Handler) GetTrace(c *gin.Context) {
+	var result *service.TraceResult
+	result, err := h.manager.FindTrace(c.Param("traceID"))
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to search logs", err.Error()))
+		return
+	}
+
+	if len(result.Projects) == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "No log lines found for trace", result.TraceID))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: result})
+}
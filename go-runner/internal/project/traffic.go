@@ -0,0 +1,128 @@
+package project
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// trafficMetricsRetention is how long traffic samples are kept
+const trafficMetricsRetention = 7 * 24 * time.Hour
+
+// TrafficMetric is the traffic proxied to a project over one flush interval
+type TrafficMetric struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"index:,composite:lookup"`
+	Timestamp time.Time `json:"timestamp" gorm:"index:,composite:lookup"` // End of the interval
+	Requests  uint64    `json:"requests"`
+	Errors    uint64    `json:"errors"`
+	Status2xx uint64    `json:"status_2xx" gorm:"column:status_2xx"`
+	Status3xx uint64    `json:"status_3xx" gorm:"column:status_3xx"`
+	Status4xx uint64    `json:"status_4xx" gorm:"column:status_4xx"`
+	Status5xx uint64    `json:"status_5xx" gorm:"column:status_5xx"`
+	P50Ms     float64   `json:"p50_ms"`
+	P95Ms     float64   `json:"p95_ms"`
+	P99Ms     float64   `json:"p99_ms"`
+	MaxMs     float64   `json:"max_ms"`
+}
+
+// TrafficReport is the traffic of a project: totals since go-runner started
+// and the stored history
+type TrafficReport struct {
+	Stats       types.ServiceStats    `json:"stats"`
+	Live        *service.TrafficStats `json:"live"` // Null until a request is proxied
+	History     []TrafficMetric       `json:"history"`
+	Annotations []ProjectAnnotation   `json:"annotations"` // Markers over the history: deploys, config changes and notes
+}
+
+// TrafficRecorder stores the proxied traffic of projects as metrics
+type TrafficRecorder struct {
+	db     *gorm.DB
+	events *events.Bus
+}
+
+// NewTrafficRecorder creates a traffic recorder
+func NewTrafficRecorder(db *gorm.DB, bus *events.Bus) *TrafficRecorder {
+	return &TrafficRecorder{db: db, events: bus}
+}
+
+// Record stores the traffic of a flush interval and publishes it as a
+// "traffic_update" event
+func (r *TrafficRecorder) Record(window service.TrafficWindow) {
+	metric := TrafficMetric{
+		ProjectID: window.ProjectID,
+		Timestamp: window.End,
+		Requests:  window.Requests,
+		Errors:    window.Errors,
+		Status2xx: window.StatusClasses["2xx"],
+		Status3xx: window.StatusClasses["3xx"],
+		Status4xx: window.StatusClasses["4xx"],
+		Status5xx: window.StatusClasses["5xx"],
+		P50Ms:     window.P50Ms,
+		P95Ms:     window.P95Ms,
+		P99Ms:     window.P99Ms,
+		MaxMs:     window.MaxMs,
+	}
+	if err := r.db.Create(&metric).Error; err != nil {
+		log.Printf("Failed to store traffic metric: %v", err)
+	}
+	r.events.Publish(window.ProjectID, "traffic_update", metric)
+
+	r.db.Where("project_id = ? AND timestamp < ?", window.ProjectID, time.Now().Add(-trafficMetricsRetention)).Delete(&TrafficMetric{})
+}
+
+// GetTraffic godoc
+// @Summary      Get proxied traffic
+// @Description  Get the requests proxied to a project through /projects/{id}/proxy: request and error counts with the CPU, memory and uptime of its process (stats), status codes and latency percentiles of the recent requests since go-runner started (live), and per-minute samples with status classes and latency percentiles (history, oldest first). Errors are 5xx responses, including requests the project could not be reached or started for.
+// @Tags         projects
+// @Produce      json
+// @Param        id     path      int  true   "Project ID"
+// @Param        hours  query     int  false  "Hours of history (default 1, max 168)"
+// @Success      200    {object}  types.DataResponse{data=TrafficReport}
+// @Failure      400    {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      404    {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/traffic [get]
+func (h *Handler) GetTraffic(c *gin.Context) {
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	hours := 1
+	if raw := c.Query("hours"); raw != "" {
+		hours, err = strconv.Atoi(raw)
+		if err != nil || hours < 1 || hours > 168 {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "hours must be between 1 and 168", raw))
+			return
+		}
+	}
+
+	history := []TrafficMetric{}
+	if err := h.db.Where("project_id = ? AND timestamp >= ?", project.ID, time.Now().Add(-time.Duration(hours)*time.Hour)).
+		Order("timestamp asc").Find(&history).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch traffic metrics", err.Error()))
+		return
+	}
+	annotations, err := findAnnotations(h.db, project.ID, time.Now().Add(-time.Duration(hours)*time.Hour), time.Now())
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch annotations", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: TrafficReport{
+		Stats:       h.manager.GetServiceStats(project.ID),
+		Live:        h.manager.ProjectTraffic(project.ID),
+		History:     history,
+		Annotations: annotations,
+	}})
+}
@@ -0,0 +1,118 @@
+package project
+
+import (
+	"errors"
+	"net/http"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartTunnelRequest selects the tunnel provider and port
+type StartTunnelRequest struct {
+	Provider string `json:"provider" binding:"omitempty,oneof=cloudflared ngrok ssh"` // Configured or detected when empty
+	Port     int    `json:"port" binding:"omitempty,min=1,max=65535"`                 // Project port when empty
+}
+
+// StartTunnel godoc
+// @Summary      Open a public tunnel
+// @Description  Expose the project port publicly through cloudflared (quick tunnel), ngrok or an ssh remote forward to the configured relay host, and return the public URL. The tunnel process is closed when the project is stopped and is included as "tunnel" in the project status.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                 true   "Project ID"
+// @Param        request  body      StartTunnelRequest  false  "Provider and port"
+// @Success      201      {object}  types.DataResponse{data=service.Tunnel}  "Tunnel open"
+// @Failure      400      {object}  middleware.ErrorResponse  "No port"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Failure      409      {object}  middleware.ErrorResponse  "Tunnel already open"
+// @Failure      502      {object}  middleware.ErrorResponse  "Tunnel failed to start"
+// @Router       /projects/{id}/tunnel [post]
+func (h *Handler) StartTunnel(c *gin.Context) {
+	var req StartTunnelRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+			return
+		}
+	}
+
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	port := req.Port
+	if port == 0 {
+		port = project.Port
+	}
+	if port == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Project has no port to expose", nil))
+		return
+	}
+
+	tunnel, err := h.manager.StartTunnel(project.ID, req.Provider, port)
+	if err != nil {
+		if errors.Is(err, service.ErrTunnelExists) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "Tunnel already open", tunnel))
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusBadGateway, "Failed to open tunnel", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.DataResponse{Data: tunnel})
+}
+
+// GetTunnel godoc
+// @Summary      Get the public tunnel
+// @Description  Get the open tunnel of a project
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=service.Tunnel}
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found or no tunnel"
+// @Router       /projects/{id}/tunnel [get]
+func (h *Handler) GetTunnel(c *gin.Context) {
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	tunnel := h.manager.GetTunnel(project.ID)
+	if tunnel == nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "No tunnel for this project", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: tunnel})
+}
+
+// StopTunnel godoc
+// @Summary      Close the public tunnel
+// @Description  Stop the tunnel process of a project
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.MessageResponse     "Tunnel closed"
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found or no tunnel"
+// @Router       /projects/{id}/tunnel [delete]
+func (h *Handler) StopTunnel(c *gin.Context) {
+	project, _, err := h.loadProjectDir(c)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if err := h.manager.StopTunnel(project.ID); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "No tunnel for this project", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Tunnel closed"})
+}
@@ -0,0 +1,274 @@
+package project
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/service"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VSCodeExport holds the contents of .vscode/tasks.json and .vscode/launch.json
+type VSCodeExport struct {
+	Tasks  VSCodeTasks  `json:"tasks"`
+	Launch VSCodeLaunch `json:"launch"`
+	Notes  []string     `json:"notes,omitempty"` // Projects without a debugger configuration
+}
+
+// VSCodeTasks is a tasks.json file
+type VSCodeTasks struct {
+	Version string       `json:"version"`
+	Tasks   []VSCodeTask `json:"tasks"`
+}
+
+// VSCodeTask runs a project command in the integrated terminal
+type VSCodeTask struct {
+	Label          string             `json:"label"`
+	Type           string             `json:"type,omitempty"`
+	Command        string             `json:"command,omitempty"`
+	Args           []string           `json:"args,omitempty"`
+	Options        *VSCodeTaskOptions `json:"options,omitempty"`
+	DependsOn      []string           `json:"dependsOn,omitempty"`
+	DependsOrder   string             `json:"dependsOrder,omitempty"`
+	IsBackground   bool               `json:"isBackground,omitempty"`
+	ProblemMatcher []string           `json:"problemMatcher"`
+	Detail         string             `json:"detail,omitempty"`
+}
+
+// VSCodeTaskOptions sets the directory and variables of a task
+type VSCodeTaskOptions struct {
+	Cwd string            `json:"cwd"`
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// VSCodeLaunch is a launch.json file. Configurations depend on the debugger
+// (go, node, debugpy), so they are kept as objects.
+type VSCodeLaunch struct {
+	Version        string                   `json:"version"`
+	Configurations []map[string]interface{} `json:"configurations"`
+	Compounds      []VSCodeCompound         `json:"compounds,omitempty"`
+}
+
+// VSCodeCompound launches several configurations together
+type VSCodeCompound struct {
+	Name           string   `json:"name"`
+	Configurations []string `json:"configurations"`
+	StopAll        bool     `json:"stopAll"`
+}
+
+// ExportVSCode godoc
+// @Summary      Export a project to VS Code
+// @Description  Generate a tasks.json task running the project command and a launch.json debug configuration (Go via Delve, Node.js including npm/yarn/pnpm scripts, Python via debugpy) with the same working directory and variables as a start: the .env file, env_vars with placeholders resolved, PORT and ENVIRONMENT. Paste them into .vscode/ of the project. Commands without a known debugger only get a task, explained in notes.
+// @Tags         projects
+// @Produce      json
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  types.DataResponse{data=VSCodeExport}
+// @Failure      404  {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /projects/{id}/export/vscode [get]
+func (h *Handler) ExportVSCode(c *gin.Context) {
+	var project Project
+	if err := h.db.Select("id, name, type").First(&project, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	export := newVSCodeExport()
+	if err := h.addVSCodeProject(&export, &project); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to resolve project command", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: export})
+}
+
+// ExportGroupVSCode godoc
+// @Summary      Export a group to VS Code
+// @Description  Generate the tasks and debug configurations of every project of a group (see GET /projects/{id}/export/vscode), plus a compound launch debugging them all at once and a task starting them all, both named after the group.
+// @Tags         groups
+// @Produce      json
+// @Param        id   path      int  true  "Group ID"
+// @Success      200  {object}  types.DataResponse{data=VSCodeExport}
+// @Failure      404  {object}  middleware.ErrorResponse  "Group not found"
+// @Router       /groups/{id}/export/vscode [get]
+func (h *Handler) ExportGroupVSCode(c *gin.Context) {
+	var group ProjectGroup
+	if err := h.db.First(&group, c.Param("id")).Error; err != nil {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+
+	var projects []Project
+	if err := h.db.Select("id, name, type").Where("group_id = ?", group.ID).Order("name").Find(&projects).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
+		return
+	}
+
+	export := newVSCodeExport()
+	for i := range projects {
+		if err := h.addVSCodeProject(&export, &projects[i]); err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to resolve project command", err.Error()))
+			return
+		}
+	}
+
+	if len(export.Tasks.Tasks) > 1 {
+		var labels []string
+		for _, t := range export.Tasks.Tasks {
+			labels = append(labels, t.Label)
+		}
+		export.Tasks.Tasks = append(export.Tasks.Tasks, VSCodeTask{
+			Label:          group.Name,
+			DependsOn:      labels,
+			DependsOrder:   "parallel",
+			ProblemMatcher: []string{},
+			Detail:         "Start all projects of the group",
+		})
+	}
+	if len(export.Launch.Configurations) > 1 {
+		compound := VSCodeCompound{Name: group.Name, StopAll: true}
+		for _, config := range export.Launch.Configurations {
+			compound.Configurations = append(compound.Configurations, config["name"].(string))
+		}
+		export.Launch.Compounds = []VSCodeCompound{compound}
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: export})
+}
+
+func newVSCodeExport() VSCodeExport {
+	return VSCodeExport{
+		Tasks:  VSCodeTasks{Version: "2.0.0", Tasks: []VSCodeTask{}},
+		Launch: VSCodeLaunch{Version: "0.2.0", Configurations: []map[string]interface{}{}},
+	}
+}
+
+// addVSCodeProject adds the task and debug configuration of a project
+func (h *Handler) addVSCodeProject(export *VSCodeExport, project *Project) error {
+	spec, err := h.manager.ProjectLaunchSpec(project.ID)
+	if err != nil {
+		return err
+	}
+
+	// Same defaults as a start without command
+	if spec.Command == "" {
+		switch project.Type {
+		case TypeBackend:
+			spec.Command, spec.Args = "go", append([]string{"run", "main.go"}, spec.Args...)
+		case TypeFrontend:
+			spec.Command, spec.Args = "npm", append([]string{"start"}, spec.Args...)
+		default:
+			export.Notes = append(export.Notes, project.Name+": no command")
+			return nil
+		}
+	}
+
+	export.Tasks.Tasks = append(export.Tasks.Tasks, VSCodeTask{
+		Label:          project.Name,
+		Type:           "process",
+		Command:        spec.Command,
+		Args:           spec.Args,
+		Options:        &VSCodeTaskOptions{Cwd: spec.Dir, Env: spec.Env},
+		IsBackground:   true,
+		ProblemMatcher: []string{},
+	})
+
+	config := vscodeLaunchConfig(spec)
+	if config == nil {
+		export.Notes = append(export.Notes, project.Name+": no debugger configuration for "+spec.Command+", use the task")
+		return nil
+	}
+	config["name"] = project.Name
+	config["request"] = "launch"
+	config["cwd"] = spec.Dir
+	if len(spec.Env) > 0 {
+		config["env"] = spec.Env
+	}
+	if spec.EnvFile != "" {
+		config["envFile"] = spec.EnvFile
+	}
+	export.Launch.Configurations = append(export.Launch.Configurations, config)
+	return nil
+}
+
+// vscodeLaunchConfig maps a command to a debug configuration, or nil when no
+// debugger is known for it
+func vscodeLaunchConfig(spec *service.LaunchSpec) map[string]interface{} {
+	args := spec.Args
+	switch filepath.Base(spec.Command) {
+	case "go":
+		if len(args) < 2 || args[0] != "run" {
+			return nil
+		}
+		program, rest := splitProgram(args[1:])
+		if program == "" {
+			return nil
+		}
+		if !filepath.IsAbs(program) {
+			program = filepath.Join(spec.Dir, program)
+		}
+		// Delve builds packages, not single files
+		if strings.HasSuffix(program, "main.go") {
+			program = filepath.Dir(program)
+		}
+		return map[string]interface{}{"type": "go", "mode": "auto", "program": program, "args": rest}
+
+	case "node", "nodemon", "ts-node", "tsx":
+		flags, program, rest := splitRuntime(args)
+		if program == "" {
+			return nil
+		}
+		config := map[string]interface{}{"type": "node", "program": joinDir(spec.Dir, program), "args": rest, "skipFiles": []string{"<node_internals>/**"}}
+		if spec.Command != "node" {
+			config["runtimeExecutable"] = spec.Command
+		}
+		if len(flags) > 0 {
+			config["runtimeArgs"] = flags
+		}
+		return config
+
+	case "npm", "yarn", "pnpm", "npx", "bun":
+		return map[string]interface{}{"type": "node", "runtimeExecutable": spec.Command, "runtimeArgs": args, "console": "integratedTerminal", "skipFiles": []string{"<node_internals>/**"}}
+
+	case "python", "python3":
+		if len(args) >= 2 && args[0] == "-m" {
+			return map[string]interface{}{"type": "debugpy", "module": args[1], "args": args[2:], "console": "integratedTerminal"}
+		}
+		_, program, rest := splitRuntime(args)
+		if program == "" {
+			return nil
+		}
+		return map[string]interface{}{"type": "debugpy", "program": joinDir(spec.Dir, program), "args": rest, "console": "integratedTerminal"}
+	}
+	return nil
+}
+
+// splitProgram returns the first non-flag argument of go run (the package or
+// file) and the program arguments after it
+func splitProgram(args []string) (string, []string) {
+	for i, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			return a, args[i+1:]
+		}
+	}
+	return "", nil
+}
+
+// splitRuntime splits interpreter flags, the script and its arguments
+func splitRuntime(args []string) (flags []string, program string, rest []string) {
+	for i, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			return args[:i], a, args[i+1:]
+		}
+	}
+	return args, "", nil
+}
+
+func joinDir(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
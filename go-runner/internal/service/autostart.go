@@ -0,0 +1,240 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// Autostart outcomes
+const (
+	AutostartStarted        = "started"
+	AutostartAlreadyRunning = "already_running"
+	AutostartFailed         = "failed"
+	AutostartSkipped        = "skipped"
+)
+
+// autostartReadyTimeout bounds how long dependents wait for a dependency's port
+const autostartReadyTimeout = 30 * time.Second
+
+// AutostartResult reports what happened to one project during autostart
+type AutostartResult struct {
+	ProjectID   uint      `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	Result      string    `json:"result"` // started, already_running, failed, skipped
+	Error       string    `json:"error,omitempty"`
+	Dependency  bool      `json:"dependency"` // Started only because a flagged project depends on it
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// AutostartSummary is the outcome of a whole autostart run
+type AutostartSummary struct {
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt time.Time         `json:"finished_at"`
+	Results    []AutostartResult `json:"results"`
+}
+
+// autostartProject is the subset of a project row needed for autostart
+type autostartProject struct {
+	ID        uint
+	Name      string
+	Port      int
+	Autostart bool
+	DependsOn string
+}
+
+// Autostart starts every project flagged with autostart, together with the
+// projects they depend on, in dependency order. Dependents of a project that
+// failed to start are skipped. notify is called after each project.
+func (m *Manager) Autostart(notify func(AutostartResult)) *AutostartSummary {
+	summary := &AutostartSummary{StartedAt: time.Now(), Results: []AutostartResult{}}
+
+	var projects []autostartProject
+	if err := m.db.Table(tables.Name("projects")).Select("id, name, port, autostart, depends_on").
+		Where("deleted_at IS NULL").Find(&projects).Error; err != nil {
+		log.Printf("⚠️  Autostart: failed to load projects: %v", err)
+		summary.FinishedAt = time.Now()
+		return summary
+	}
+
+	order, cyclic := autostartOrder(projects)
+	failed := make(map[uint]string)
+	byName := make(map[string]autostartProject, len(projects))
+	for _, p := range projects {
+		byName[strings.ToLower(p.Name)] = p
+	}
+
+	record := func(result AutostartResult) {
+		result.Timestamp = time.Now()
+		summary.Results = append(summary.Results, result)
+		if notify != nil {
+			notify(result)
+		}
+	}
+
+	for _, p := range cyclic {
+		failed[p.ID] = "dependency cycle"
+		record(AutostartResult{ProjectID: p.ID, ProjectName: p.Name, Result: AutostartFailed, Error: "dependency cycle", Dependency: !p.Autostart})
+	}
+
+	for _, p := range order {
+		result := AutostartResult{ProjectID: p.ID, ProjectName: p.Name, Dependency: !p.Autostart}
+
+		// Skip if a dependency failed, otherwise wait for dependencies to be ready
+		var blocked string
+		for _, dep := range parseDependsOn(p.DependsOn) {
+			d, ok := byName[strings.ToLower(dep)]
+			if !ok {
+				continue
+			}
+			if reason, ok := failed[d.ID]; ok {
+				blocked = fmt.Sprintf("dependency %s failed: %s", d.Name, reason)
+				break
+			}
+			m.waitForPort(d.Port, autostartReadyTimeout)
+		}
+		if blocked != "" {
+			failed[p.ID] = blocked
+			result.Result = AutostartSkipped
+			result.Error = blocked
+			record(result)
+			continue
+		}
+
+		if m.IsServiceRunning(p.ID) {
+			result.Result = AutostartAlreadyRunning
+			record(result)
+			continue
+		}
+
+		if err := m.StartService(p.ID); err != nil {
+			failed[p.ID] = err.Error()
+			result.Result = AutostartFailed
+			result.Error = err.Error()
+		} else {
+			result.Result = AutostartStarted
+		}
+		record(result)
+	}
+
+	summary.FinishedAt = time.Now()
+
+	m.autostartMu.Lock()
+	m.lastAutostart = summary
+	m.autostartMu.Unlock()
+
+	return summary
+}
+
+// LastAutostart returns the outcome of the last autostart run, or nil if it
+// has not run yet
+func (m *Manager) LastAutostart() *AutostartSummary {
+	m.autostartMu.RLock()
+	defer m.autostartMu.RUnlock()
+	return m.lastAutostart
+}
+
+// waitForPort polls until a port accepts connections or the timeout expires
+func (m *Manager) waitForPort(port int, timeout time.Duration) bool {
+	if port <= 0 {
+		return true
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if m.isPortInUse(port) {
+			return true
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return false
+}
+
+// autostartOrder returns the flagged projects and their transitive
+// dependencies, dependencies first. Projects caught in a dependency cycle are
+// returned separately.
+func autostartOrder(projects []autostartProject) ([]autostartProject, []autostartProject) {
+	byName := make(map[string]autostartProject, len(projects))
+	for _, p := range projects {
+		byName[strings.ToLower(p.Name)] = p
+	}
+
+	// Collect flagged projects and everything they depend on
+	selected := make(map[uint]autostartProject)
+	var visit func(p autostartProject)
+	visit = func(p autostartProject) {
+		if _, ok := selected[p.ID]; ok {
+			return
+		}
+		selected[p.ID] = p
+		for _, dep := range parseDependsOn(p.DependsOn) {
+			if d, ok := byName[strings.ToLower(dep)]; ok {
+				visit(d)
+			}
+		}
+	}
+	for _, p := range projects {
+		if p.Autostart {
+			visit(p)
+		}
+	}
+
+	// Kahn's algorithm; ties broken by ID for a stable order
+	inDegree := make(map[uint]int, len(selected))
+	dependents := make(map[uint][]uint)
+	for id, p := range selected {
+		inDegree[id] += 0
+		for _, dep := range parseDependsOn(p.DependsOn) {
+			if d, ok := byName[strings.ToLower(dep)]; ok && d.ID != id {
+				inDegree[id]++
+				dependents[d.ID] = append(dependents[d.ID], id)
+			}
+		}
+	}
+
+	var ready []uint
+	for id, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	var order []autostartProject
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i] < ready[j] })
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, selected[id])
+
+		for _, dependent := range dependents[id] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+		delete(inDegree, id)
+	}
+
+	var cyclic []autostartProject
+	for id := range inDegree {
+		cyclic = append(cyclic, selected[id])
+	}
+	sort.Slice(cyclic, func(i, j int) bool { return cyclic[i].ID < cyclic[j].ID })
+
+	return order, cyclic
+}
+
+// parseDependsOn splits the comma-separated DependsOn field into project names
+func parseDependsOn(dependsOn string) []string {
+	var names []string
+	for _, name := range strings.Split(dependsOn, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// Restart strategies (restart_strategy)
+const (
+	RestartStopStart = "stop_start" // Stop, then start on the same port (default)
+	RestartBlueGreen = "blue_green" // Start a new instance on another port, switch the proxy, then stop the old one
+)
+
+// Blue/green restart defaults
+const (
+	defaultDrainTimeout   = 30 * time.Second // Open proxied connections the old instance keeps serving
+	maxDrainTimeout       = 3600             // Seconds
+	blueGreenReadyTimeout = 60 * time.Second
+)
+
+// ValidateRestartStrategy checks restart_strategy and drain_timeout as saved
+func ValidateRestartStrategy(strategy string, drainTimeout int) error {
+	switch strategy {
+	case "", RestartStopStart, RestartBlueGreen:
+	default:
+		return fmt.Errorf("unknown restart_strategy %q, expected %s or %s", strategy, RestartStopStart, RestartBlueGreen)
+	}
+	if drainTimeout < 0 || drainTimeout > maxDrainTimeout {
+		return fmt.Errorf("drain_timeout must be between 0 and %d seconds", maxDrainTimeout)
+	}
+	return nil
+}
+
+// blueGreenState tracks blue/green restarts: the instances running on
+// another port than the one of their project, the old instances draining
+// and the proxied requests in flight per port
+type blueGreenState struct {
+	mu       sync.Mutex
+	next     map[uint]int                   // Port of the instance being started
+	active   map[uint]blueGreenInstance     // Instances not on the port of their project
+	draining map[*ProcessInfo]chan struct{} // Closed to stop the instance without waiting
+	inFlight map[uint]map[int]int           // Proxied requests by project and port
+}
+
+// blueGreenInstance is the process of a project and the port it listens on
+type blueGreenInstance struct {
+	info *ProcessInfo
+	port int
+}
+
+// ActivePort returns the port the running process of a project listens on:
+// the port of the project, unless a blue/green restart moved it to another
+// one
+func (m *Manager) ActivePort(projectID uint, port int) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.activePortOf(projectID, port, m.processes[projectID])
+}
+
+// activePortOf returns the port current, the process of a project, listens
+// on; callers hold m.mu
+func (m *Manager) activePortOf(projectID uint, port int, current *ProcessInfo) int {
+	m.blueGreen.mu.Lock()
+	defer m.blueGreen.mu.Unlock()
+	if instance, ok := m.blueGreen.active[projectID]; ok && current != nil && instance.info == current {
+		return instance.port
+	}
+	return port
+}
+
+// ProxyPort returns the port to proxy a request to, round robin over the
+// instances of a project scaled out, and a function to call once the request
+// (or WebSocket connection) is done so that a blue/green restart knows when
+// the old instance is drained
+func (m *Manager) ProxyPort(projectID uint, port int) (int, func()) {
+	port = m.balancedPort(projectID, m.ActivePort(projectID, port))
+
+	m.blueGreen.mu.Lock()
+	if m.blueGreen.inFlight == nil {
+		m.blueGreen.inFlight = make(map[uint]map[int]int)
+	}
+	if m.blueGreen.inFlight[projectID] == nil {
+		m.blueGreen.inFlight[projectID] = make(map[int]int)
+	}
+	m.blueGreen.inFlight[projectID][port]++
+	m.blueGreen.mu.Unlock()
+
+	return port, func() {
+		m.blueGreen.mu.Lock()
+		defer m.blueGreen.mu.Unlock()
+		if m.blueGreen.inFlight[projectID][port]--; m.blueGreen.inFlight[projectID][port] <= 0 {
+			delete(m.blueGreen.inFlight[projectID], port)
+		}
+	}
+}
+
+// restartStrategy returns the restart_strategy of a project, blue_green
+// only when it can be applied: a local process running with a port
+func (m *Manager) restartStrategy(projectID uint) string {
+	var p struct {
+		Port            int
+		RestartStrategy string
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("port, restart_strategy").Where("id = ?", projectID).Take(&p).Error; err != nil ||
+		p.RestartStrategy != RestartBlueGreen || p.Port <= 0 {
+		return RestartStopStart
+	}
+	if m.kubeTarget(projectID) != nil || m.systemdTarget(projectID) != nil || m.sshTarget(projectID) != nil || m.isMockProject(projectID) {
+		return RestartStopStart
+	}
+	m.mu.RLock()
+	info, running := m.processes[projectID]
+	m.mu.RUnlock()
+	if !running || info.LogFollower || info.Process == nil || info.Process.Process == nil {
+		return RestartStopStart
+	}
+	return RestartBlueGreen
+}
+
+// blueGreenRestart starts a new instance of a project on a free port (its
+// own port when the running one was moved away from it), waits until the
+// port accepts connections, switches the proxy to it and stops the old
+// instance once its proxied connections are closed or drain_timeout passed.
+// When the new instance fails, it is stopped and the old one keeps running.
+func (m *Manager) blueGreenRestart(projectID uint) error {
+	var p struct {
+		Port         int
+		DrainTimeout int
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("port, drain_timeout").Where("id = ?", projectID).Take(&p).Error; err != nil {
+		return fmt.Errorf("project not found: %v", err)
+	}
+	drain := defaultDrainTimeout
+	if p.DrainTimeout > 0 {
+		drain = time.Duration(p.DrainTimeout) * time.Second
+	}
+
+	m.mu.RLock()
+	old, running := m.processes[projectID]
+	oldPort := m.activePortOf(projectID, p.Port, old)
+	m.mu.RUnlock()
+	if !running {
+		return m.startService(projectID)
+	}
+	oldPID := old.Process.Process.Pid
+	newPort := p.Port
+	if oldPort == p.Port || m.isPortInUse(p.Port) {
+		port, err := freePort()
+		if err != nil {
+			return fmt.Errorf("no free port for the new instance: %v", err)
+		}
+		newPort = port
+	}
+
+	// The old instance leaves the process table, its exit no longer owns the
+	// status of the project
+	m.mu.Lock()
+	if m.processes[projectID] != old {
+		m.mu.Unlock()
+		return fmt.Errorf("service %d changed during the restart", projectID)
+	}
+	m.blueGreen.mu.Lock()
+	if m.blueGreen.next == nil {
+		m.blueGreen.next = make(map[uint]int)
+		m.blueGreen.active = make(map[uint]blueGreenInstance)
+		m.blueGreen.draining = make(map[*ProcessInfo]chan struct{})
+	}
+	stop := make(chan struct{})
+	m.blueGreen.next[projectID] = newPort
+	m.blueGreen.draining[old] = stop
+	m.blueGreen.mu.Unlock()
+	delete(m.processes, projectID)
+	m.mu.Unlock()
+
+	log.Printf("🔵 Project %d: blue/green restart, new instance on port %d (old on %d)", projectID, newPort, oldPort)
+	err := m.startProcess(projectID)
+	var instance *ProcessInfo
+	if err == nil {
+		instance, err = m.waitInstanceReady(projectID, newPort, blueGreenReadyTimeout)
+	}
+	if err != nil {
+		m.rollbackBlueGreen(projectID, old, oldPID, err)
+		return fmt.Errorf("blue/green restart failed: %v", err)
+	}
+
+	// Switch the proxy
+	m.blueGreen.mu.Lock()
+	if newPort == p.Port {
+		delete(m.blueGreen.active, projectID)
+	} else {
+		m.blueGreen.active[projectID] = blueGreenInstance{info: instance, port: newPort}
+	}
+	m.blueGreen.mu.Unlock()
+	log.Printf("🔀 Project %d: switched to the new instance on port %d (PID %d)", projectID, newPort, instance.Process.Process.Pid)
+
+	go m.drainInstance(projectID, old, oldPort, drain, stop)
+	return nil
+}
+
+// waitInstanceReady waits until the new instance of a project accepts
+// connections on its port, failing when it exits first
+func (m *Manager) waitInstanceReady(projectID uint, port int, timeout time.Duration) (*ProcessInfo, error) {
+	m.mu.RLock()
+	instance := m.processes[projectID]
+	m.mu.RUnlock()
+	if instance == nil {
+		return nil, fmt.Errorf("the new instance exited")
+	}
+
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	deadline := time.Now().Add(timeout)
+	for {
+		m.mu.RLock()
+		current := m.processes[projectID]
+		m.mu.RUnlock()
+		if current != instance {
+			var lastError string
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Pluck("last_error", &lastError)
+			if lastError != "" {
+				return nil, fmt.Errorf("the new instance exited: %s", lastError)
+			}
+			return nil, fmt.Errorf("the new instance exited")
+		}
+		if conn, err := net.DialTimeout("tcp", address, time.Second); err == nil {
+			conn.Close()
+			return instance, nil
+		}
+		if time.Now().After(deadline) {
+			return instance, fmt.Errorf("port %d not accepting connections after %s", port, timeout)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// rollbackBlueGreen stops the new instance of a failed blue/green restart
+// and gives the project back to the old one
+func (m *Manager) rollbackBlueGreen(projectID uint, old *ProcessInfo, oldPID int, cause error) {
+	m.mu.Lock()
+	instance, started := m.processes[projectID]
+	m.processes[projectID] = old
+	m.blueGreen.mu.Lock()
+	delete(m.blueGreen.next, projectID)
+	delete(m.blueGreen.draining, old)
+	if started {
+		m.blueGreen.draining[instance] = make(chan struct{})
+	}
+	m.blueGreen.mu.Unlock()
+	m.mu.Unlock()
+	if started {
+		m.stopInstance(instance)
+	}
+
+	if !processAlive(oldPID) {
+		// The old instance ended meanwhile: nothing is running anymore
+		m.mu.Lock()
+		if m.processes[projectID] == old {
+			delete(m.processes, projectID)
+		}
+		m.mu.Unlock()
+		old.safeCloseChannel()
+		now := time.Now()
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":     string(types.StatusError),
+			"stop_time":  &now,
+			"p_id":       0,
+			"last_error": cause.Error(),
+		})
+		m.RecordStatus(projectID, string(types.StatusError), "Blue/green restart failed: "+cause.Error())
+		return
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+		"status":     string(types.StatusRunning),
+		"p_id":       oldPID,
+		"start_time": old.StartTime,
+		"last_error": "Blue/green restart failed: " + cause.Error(),
+	})
+	m.RecordStatus(projectID, string(types.StatusRunning), "Blue/green restart failed, kept the old instance: "+cause.Error())
+}
+
+// drainInstance stops the old instance of a blue/green restart once no
+// proxied request is in flight on its port, or after the drain timeout
+func (m *Manager) drainInstance(projectID uint, old *ProcessInfo, port int, timeout time.Duration, stop <-chan struct{}) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	started := time.Now()
+wait:
+	for {
+		m.blueGreen.mu.Lock()
+		open := m.blueGreen.inFlight[projectID][port]
+		m.blueGreen.mu.Unlock()
+		if open == 0 {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			log.Printf("⏱️  Project %d: stopping the old instance with %d proxied connection(s) still open", projectID, open)
+			break wait
+		case <-stop:
+			break wait
+		}
+	}
+	log.Printf("🟢 Project %d: stopping the old instance on port %d after %s", projectID, port, time.Since(started).Round(time.Millisecond))
+	m.stopInstance(old)
+}
+
+// stopInstance stops the process of an instance that no longer owns the
+// status of its project: SIGTERM, then SIGKILL after a grace period
+func (m *Manager) stopInstance(info *ProcessInfo) {
+	if info.Process != nil && info.Process.Process != nil {
+		if _, err := terminateProcess(int32(info.Process.Process.Pid)); err != nil && processAlive(info.Process.Process.Pid) {
+			log.Printf("Failed to stop instance PID %d: %v", info.Process.Process.Pid, err)
+		}
+	}
+	info.Cancel()
+	info.safeCloseChannel()
+
+	m.blueGreen.mu.Lock()
+	delete(m.blueGreen.draining, info)
+	m.blueGreen.mu.Unlock()
+}
+
+// stopDrainingInstances stops the old instances of a project left by
+// blue/green restarts right away, when the project itself stops
+func (m *Manager) stopDrainingInstances(projectID uint) {
+	m.blueGreen.mu.Lock()
+	defer m.blueGreen.mu.Unlock()
+	for info, stop := range m.blueGreen.draining {
+		if info.ProjectID == projectID {
+			select {
+			case <-stop:
+			default:
+				close(stop)
+			}
+		}
+	}
+}
+
+// isDrainingInstance reports whether a process is an old instance of a
+// blue/green restart, whose exit says nothing about the project
+func (m *Manager) isDrainingInstance(info *ProcessInfo) bool {
+	m.blueGreen.mu.Lock()
+	defer m.blueGreen.mu.Unlock()
+	_, ok := m.blueGreen.draining[info]
+	return ok
+}
+
+// takeBlueGreenPort returns the port of the instance a blue/green restart
+// is starting, 0 for a normal start
+func (m *Manager) takeBlueGreenPort(projectID uint) int {
+	m.blueGreen.mu.Lock()
+	defer m.blueGreen.mu.Unlock()
+	port := m.blueGreen.next[projectID]
+	delete(m.blueGreen.next, projectID)
+	return port
+}
+
+// freePort returns a TCP port nothing listens on
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// processAlive reports whether a process exists
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	return err == nil && proc.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProxyLatency is a delay the proxy adds to the requests of a project
+type ProxyLatency struct {
+	DelayMs  int       `json:"delay_ms"`
+	JitterMs int       `json:"jitter_ms"` // Up to this much more, at random
+	Until    time.Time `json:"until"`
+}
+
+// ChaosStatus is the chaos currently applied to a project
+type ChaosStatus struct {
+	Latency *ProxyLatency `json:"latency,omitempty"`
+	Pause   *ServicePause `json:"pause,omitempty"`
+}
+
+// ChaosTarget is an instance a random kill can hit
+type ChaosTarget struct {
+	ProjectID uint `json:"project_id"`
+	Index     int  `json:"index"` // Instance, 0 for the main process
+	PID       int  `json:"pid"`
+}
+
+// chaosState holds the latency injected into projects
+type chaosState struct {
+	mu      sync.Mutex
+	latency map[uint]ProxyLatency
+}
+
+// InjectLatency delays the requests proxied to a project by delay, plus up
+// to jitter at random, for duration
+func (m *Manager) InjectLatency(projectID uint, delay, jitter, duration time.Duration) ProxyLatency {
+	latency := ProxyLatency{
+		DelayMs:  int(delay / time.Millisecond),
+		JitterMs: int(jitter / time.Millisecond),
+		Until:    time.Now().Add(duration),
+	}
+	m.chaos.mu.Lock()
+	defer m.chaos.mu.Unlock()
+	if m.chaos.latency == nil {
+		m.chaos.latency = make(map[uint]ProxyLatency)
+	}
+	m.chaos.latency[projectID] = latency
+	return latency
+}
+
+// ClearLatency stops delaying the requests of a project and reports whether
+// they were
+func (m *Manager) ClearLatency(projectID uint) bool {
+	m.chaos.mu.Lock()
+	defer m.chaos.mu.Unlock()
+	latency, ok := m.chaos.latency[projectID]
+	delete(m.chaos.latency, projectID)
+	return ok && time.Now().Before(latency.Until)
+}
+
+// ProxyDelay returns how long to hold a proxied request of a project before
+// forwarding it, 0 without injected latency
+func (m *Manager) ProxyDelay(projectID uint) time.Duration {
+	m.chaos.mu.Lock()
+	defer m.chaos.mu.Unlock()
+	latency, ok := m.chaos.latency[projectID]
+	if !ok {
+		return 0
+	}
+	if time.Now().After(latency.Until) {
+		delete(m.chaos.latency, projectID)
+		return 0
+	}
+	delay := time.Duration(latency.DelayMs) * time.Millisecond
+	if latency.JitterMs > 0 {
+		delay += time.Duration(rand.Intn(latency.JitterMs+1)) * time.Millisecond
+	}
+	return delay
+}
+
+// ChaosStatus returns the latency and pause applied to a project
+func (m *Manager) ChaosStatus(projectID uint) ChaosStatus {
+	status := ChaosStatus{Pause: m.Pause(projectID)}
+	m.chaos.mu.Lock()
+	defer m.chaos.mu.Unlock()
+	if latency, ok := m.chaos.latency[projectID]; ok && time.Now().Before(latency.Until) {
+		status.Latency = &latency
+	}
+	return status
+}
+
+// ChaosTargets lists the running instances of projects a random kill can
+// hit: local processes, the main one and the extra instances
+func (m *Manager) ChaosTargets(projectIDs []uint) []ChaosTarget {
+	var targets []ChaosTarget
+	m.mu.RLock()
+	for _, id := range projectIDs {
+		info, ok := m.processes[id]
+		if !ok || info.LogFollower || info.Process == nil || info.Process.Process == nil {
+			continue
+		}
+		targets = append(targets, ChaosTarget{ProjectID: id, Index: 0, PID: info.Process.Process.Pid})
+	}
+	m.mu.RUnlock()
+
+	m.instances.mu.Lock()
+	defer m.instances.mu.Unlock()
+	for _, id := range projectIDs {
+		for _, instance := range m.instances.projects[id] {
+			if instance.cmd != nil && instance.cmd.Process != nil {
+				targets = append(targets, ChaosTarget{ProjectID: id, Index: instance.Index, PID: instance.cmd.Process.Pid})
+			}
+		}
+	}
+	return targets
+}
+
+// KillInstance sends SIGKILL to an instance, as a crash: auto_restart
+// brings it back like after any other crash
+func (m *Manager) KillInstance(target ChaosTarget) error {
+	if target.Index == 0 {
+		// The pause of a project ends with its process
+		m.ResumeService(target.ProjectID)
+	}
+	p, err := process.NewProcess(int32(target.PID))
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// PickChaosTarget returns one of targets at random
+func PickChaosTarget(targets []ChaosTarget) ChaosTarget {
+	return targets[rand.Intn(len(targets))]
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionNumber matches the first version in command output: v18.17.1,
+// go1.22.1, Python 3.11.4
+var versionNumber = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+
+// toolVersion is a major.minor.patch version
+type toolVersion [3]int
+
+func (v toolVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// compare returns -1, 0 or 1 as v is older, equal or newer than o
+func (v toolVersion) compare(o toolVersion) int {
+	for i := range v {
+		if v[i] != o[i] {
+			if v[i] < o[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// bump returns the smallest version above every version starting with the
+// first n parts of v: 1.2.3 bumped at 2 is 1.3.0
+func (v toolVersion) bump(n int) toolVersion {
+	switch n {
+	case 1:
+		return toolVersion{v[0] + 1, 0, 0}
+	case 2:
+		return toolVersion{v[0], v[1] + 1, 0}
+	}
+	return toolVersion{v[0], v[1], v[2] + 1}
+}
+
+// parseToolVersion finds the version in the output of a --version command
+func parseToolVersion(s string) (toolVersion, bool) {
+	m := versionNumber.FindStringSubmatch(s)
+	if m == nil {
+		return toolVersion{}, false
+	}
+	var v toolVersion
+	for i := range v {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+	return v, true
+}
+
+// parsePartialVersion parses the version of a comparator, where trailing
+// parts may be missing or wildcards (18, 18.x, 3.11.*), and returns how many
+// parts were given
+func parsePartialVersion(s string) (toolVersion, int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+"); i > 0 {
+		s = s[:i] // Prerelease and build metadata are not compared
+	}
+
+	var v toolVersion
+	n := 0
+	for _, part := range strings.Split(s, ".") {
+		if part == "x" || part == "X" || part == "*" || part == "" {
+			break
+		}
+		if n == len(v) {
+			return v, 0, fmt.Errorf("invalid version %q", s)
+		}
+		num, err := strconv.Atoi(part)
+		if err != nil {
+			return v, 0, fmt.Errorf("invalid version %q", s)
+		}
+		v[n] = num
+		n++
+	}
+	return v, n, nil
+}
+
+// versionSatisfies reports whether version matches a constraint, written as
+// an npm range (>=18 <21, ^18.2.0, 18.x || 20.x, 1.2 - 2.3) or a PEP 440
+// specifier (>=3.9,<4, ~=3.10, ==3.11.*)
+func versionSatisfies(version toolVersion, constraint string) (bool, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" || constraint == "*" || constraint == "x" {
+		return true, nil
+	}
+
+	for _, alternative := range strings.Split(constraint, "||") {
+		comparators := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		if len(comparators) == 3 && comparators[1] == "-" {
+			comparators = []string{">=" + comparators[0], "<=" + comparators[2]}
+		}
+
+		ok := true
+		for i := 0; i < len(comparators); i++ {
+			comparator := comparators[i]
+			if strings.Trim(comparator, "<>=!~^") == "" && i+1 < len(comparators) {
+				i++ // Operator separated from its version: ">= 18"
+				comparator += comparators[i]
+			}
+			matches, err := versionMatches(version, comparator)
+			if err != nil {
+				return false, err
+			}
+			if !matches {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// versionMatches checks version against a single comparator
+func versionMatches(version toolVersion, comparator string) (bool, error) {
+	op := ""
+	for _, prefix := range []string{"===", "==", "~=", ">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(comparator, prefix) {
+			op = prefix
+			break
+		}
+	}
+	base, n, err := parsePartialVersion(comparator[len(op):])
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return op != "!=" && op != "<" && op != ">", nil // Wildcard
+	}
+
+	cmp := version.compare(base)
+	inRange := func(upper toolVersion) bool { return cmp >= 0 && version.compare(upper) < 0 }
+	switch op {
+	case "", "=", "==", "===":
+		if n == len(base) {
+			return cmp == 0, nil
+		}
+		return inRange(base.bump(n)), nil
+	case "!=":
+		if n == len(base) {
+			return cmp != 0, nil
+		}
+		return !inRange(base.bump(n)), nil
+	case ">":
+		if n == len(base) {
+			return cmp > 0, nil
+		}
+		return version.compare(base.bump(n)) >= 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		if n == len(base) {
+			return cmp <= 0, nil
+		}
+		return version.compare(base.bump(n)) < 0, nil
+	case "^":
+		// Changes that do not modify the left-most non-zero part
+		switch {
+		case base[0] > 0 || n == 1:
+			return inRange(base.bump(1)), nil
+		case base[1] > 0 || n == 2:
+			return inRange(base.bump(2)), nil
+		}
+		return inRange(base.bump(3)), nil
+	case "~":
+		if n == 1 {
+			return inRange(base.bump(1)), nil
+		}
+		return inRange(base.bump(2)), nil
+	case "~=":
+		// Compatible release: ~=3.10 is >=3.10,<4 and ~=3.10.2 is >=3.10.2,<3.11
+		if n < 2 {
+			return false, fmt.Errorf("invalid compatible release %q", comparator)
+		}
+		return inRange(base.bump(n - 1)), nil
+	}
+	return false, fmt.Errorf("invalid version constraint %q", comparator)
+}
@@ -0,0 +1,801 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Doctor check statuses
+const (
+	DoctorPass = "pass"
+	DoctorWarn = "warn" // Likely to work, but differs from what the project asks for
+	DoctorFail = "fail" // The project will not start or run correctly
+)
+
+// Doctor check categories
+const (
+	DoctorCategoryProject        = "project"
+	DoctorCategoryToolchain      = "toolchain"
+	DoctorCategoryPackageManager = "package_manager"
+	DoctorCategoryEnv            = "env"
+	DoctorCategoryDatabase       = "database"
+)
+
+// envExampleFiles list the variables a project expects to be set
+var envExampleFiles = []string{".env.example", ".env.sample", ".env.template", ".env.dist"}
+
+var (
+	// requiresPythonPattern matches requires-python in pyproject.toml
+	requiresPythonPattern = regexp.MustCompile(`(?m)^\s*requires-python\s*=\s*["']([^"']+)["']`)
+	// poetryPythonPattern matches the python dependency of [tool.poetry.dependencies]
+	poetryPythonPattern = regexp.MustCompile(`(?m)^\s*python\s*=\s*["']([^"']+)["']`)
+)
+
+// asdfDoctorTools maps asdf plugins to the tools checked by the doctor
+var asdfDoctorTools = map[string]string{"nodejs": "node", "golang": "go"}
+
+// networkServicePorts are the default ports of the services whose URLs are
+// checked by connecting, without a protocol-level probe
+var networkServicePorts = map[string]string{
+	"mongodb":   "27017",
+	"amqp":      "5672",
+	"amqps":     "5671",
+	"nats":      "4222",
+	"memcached": "11211",
+}
+
+// DoctorCheck is one verified requirement of a project
+type DoctorCheck struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`         // project, toolchain, package_manager, env, database
+	Status   string `json:"status"`           // pass, warn, fail
+	Wanted   string `json:"wanted,omitempty"` // Required version, variable or URL
+	Found    string `json:"found,omitempty"`  // What is installed or configured
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"` // How to resolve a warning or failure
+}
+
+// DoctorSummary counts checks per status
+type DoctorSummary struct {
+	Pass int `json:"pass"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
+}
+
+// DoctorReport is the result of verifying that the environment can run a
+// project
+type DoctorReport struct {
+	ProjectID uint          `json:"project_id"`
+	Dir       string        `json:"dir"`
+	Healthy   bool          `json:"healthy"` // No check failed
+	Summary   DoctorSummary `json:"summary"`
+	Checks    []DoctorCheck `json:"checks"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// doctorProject is what the checks need of a project
+type doctorProject struct {
+	ID               uint
+	Name             string
+	Type             string
+	GroupID          *uint
+	Path             string
+	WorkingDir       string
+	Command          string
+	Args             string
+	Port             int
+	Environment      string
+	EnvFile          string
+	EnvVars          string
+	ConnectionString string
+}
+
+// doctorRun collects the checks of one report
+type doctorRun struct {
+	m   *Manager
+	ctx context.Context
+	p   doctorProject
+	dir string
+	env map[string]string // Environment the project would be started with
+
+	managed map[string]ToolchainResolution // Runtimes selected by version managers, by tool
+
+	mu     sync.Mutex
+	checks []DoctorCheck
+}
+
+// RunDoctor verifies that the toolchains, package manager, environment
+// variables and databases a project needs are present and reachable, and
+// reports each failure with how to fix it
+func (m *Manager) RunDoctor(ctx context.Context, projectID uint) (*DoctorReport, error) {
+	var p doctorProject
+	if err := m.db.Table(tables.Name("projects")).Where("id = ? AND deleted_at IS NULL", projectID).First(&p).Error; err != nil {
+		return nil, fmt.Errorf("project not found: %v", err)
+	}
+
+	dir := p.Path
+	if p.WorkingDir != "" {
+		dir = p.WorkingDir
+	}
+	d := &doctorRun{m: m, ctx: ctx, p: p, dir: dir}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		d.add(DoctorCheck{
+			Name:     "directory",
+			Category: DoctorCategoryProject,
+			Status:   DoctorFail,
+			Wanted:   dir,
+			Message:  "Project directory does not exist",
+			Fix:      "Clone or move the project to " + dir + ", or update the project path",
+		})
+		return d.report(), nil
+	}
+
+	tmpl := m.buildTemplateContext(p.ID, p.Name, p.Path, p.Environment, p.Port, p.GroupID)
+	d.env = make(map[string]string)
+	for _, entry := range m.prepareEnvironment(&struct {
+		Port        int
+		Environment string
+		EnvFile     string
+		EnvVars     string
+		Path        string
+		Template    *TemplateContext
+	}{
+		Port:        p.Port,
+		Environment: p.Environment,
+		EnvFile:     p.EnvFile,
+		EnvVars:     p.EnvVars,
+		Path:        p.Path,
+		Template:    tmpl,
+	}) {
+		if key, value, ok := strings.Cut(entry, "="); ok && key != "" {
+			d.env[key] = value
+		}
+	}
+
+	if p.Type != string(types.TypeDatabase) && p.Type != string(types.TypeSystemd) && p.Type != string(types.TypeMock) {
+		d.checkCommand(tmpl)
+	}
+	d.checkVersionManagers()
+	if d.exists("package.json") || d.exists(".nvmrc") || d.exists(".node-version") {
+		d.checkNode()
+	}
+	if d.exists("go.mod") {
+		d.checkGo()
+	}
+	if d.exists("requirements.txt") || d.exists("pyproject.toml") || d.exists("Pipfile") ||
+		d.exists(".python-version") || d.exists("runtime.txt") {
+		d.checkPython()
+	}
+	required := d.checkEnv()
+	d.checkDatabases(required)
+
+	return d.report(), nil
+}
+
+// add records a check
+func (d *doctorRun) add(check DoctorCheck) {
+	d.mu.Lock()
+	d.checks = append(d.checks, check)
+	d.mu.Unlock()
+}
+
+// report summarizes the checks
+func (d *doctorRun) report() *DoctorReport {
+	report := &DoctorReport{
+		ProjectID: d.p.ID,
+		Dir:       d.dir,
+		Checks:    d.checks,
+		CheckedAt: time.Now(),
+	}
+	if report.Checks == nil {
+		report.Checks = []DoctorCheck{}
+	}
+	for _, check := range report.Checks {
+		switch check.Status {
+		case DoctorPass:
+			report.Summary.Pass++
+		case DoctorWarn:
+			report.Summary.Warn++
+		case DoctorFail:
+			report.Summary.Fail++
+		}
+	}
+	report.Healthy = report.Summary.Fail == 0
+	return report
+}
+
+// exists reports whether a file exists in the project directory
+func (d *doctorRun) exists(name string) bool {
+	_, err := os.Stat(filepath.Join(d.dir, name))
+	return err == nil
+}
+
+// read returns a file of the project directory, empty when missing
+func (d *doctorRun) read(name string) string {
+	data, err := os.ReadFile(filepath.Join(d.dir, name))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// lookPath finds an executable on the PATH the project is started with
+func (d *doctorRun) lookPath(name string) (string, bool) {
+	return lookPathIn(name, d.env["PATH"])
+}
+
+// environ returns the environment of the project as a list
+func (d *doctorRun) environ() []string {
+	env := make([]string, 0, len(d.env)+1)
+	for k, v := range d.env {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+// version runs an executable with args in the project directory and parses
+// the version it prints
+func (d *doctorRun) version(path string, args ...string) (toolVersion, error) {
+	// Never let go download the toolchain asked for by go.mod just to print a version
+	env := append(d.environ(), "GOTOOLCHAIN=local")
+	return toolVersionOf(d.ctx, d.dir, env, path, args...)
+}
+
+// managedVersion returns the version of a tool selected by a version manager
+// at start, if any
+func (d *doctorRun) managedVersion(name string) (toolVersion, bool) {
+	r, ok := d.managed[name]
+	if !ok {
+		return toolVersion{}, false
+	}
+	return parseToolVersion(r.Resolved)
+}
+
+// checkVersionManagers reports the runtimes nvm, asdf and the go.mod
+// toolchain directive select for the start command; the toolchain checks
+// then use these versions
+func (d *doctorRun) checkVersionManagers() {
+	d.managed = make(map[string]ToolchainResolution)
+	for _, r := range resolveToolchains(&exec.Cmd{Dir: d.dir, Env: d.environ()}) {
+		check := DoctorCheck{
+			Name:     r.Manager + " " + r.Tool,
+			Category: DoctorCategoryToolchain,
+			Wanted:   r.Source + ": " + r.Requested,
+			Found:    r.Resolved,
+		}
+		if r.Warning != "" {
+			check.Status = DoctorWarn
+			check.Message = r.Warning
+			check.Fix = fmt.Sprintf("Install %s %s with the version manager", r.Tool, r.Requested)
+		} else {
+			check.Status = DoctorPass
+			check.Message = fmt.Sprintf("%s selects %s %s", r.Manager, r.Tool, r.Resolved)
+		}
+		d.add(check)
+
+		if r.Manager != ToolchainSystem && r.Warning == "" && r.Resolved != "" {
+			tool := r.Tool
+			if name, ok := asdfDoctorTools[tool]; ok {
+				tool = name
+			}
+			d.managed[tool] = r
+		}
+	}
+}
+
+// versionRequirement is a version constraint and the file it comes from
+type versionRequirement struct {
+	source     string
+	constraint string
+	strict     bool // A mismatch fails rather than warns
+}
+
+// checkToolchain finds a tool, reads its version and checks it against the
+// requirements of the project
+func (d *doctorRun) checkToolchain(name string, candidates []string, versionArgs []string, requirements []versionRequirement, install string) {
+	check := DoctorCheck{Name: name, Category: DoctorCategoryToolchain}
+	var wanted []string
+	for _, r := range requirements {
+		wanted = append(wanted, r.source+": "+r.constraint)
+	}
+	check.Wanted = strings.Join(wanted, "; ")
+
+	v, ok := d.managedVersion(name)
+	if ok {
+		check.Found = v.String() + " (" + d.managed[name].Manager + ")"
+	} else {
+		var path string
+		for _, candidate := range candidates {
+			if p, ok := d.lookPath(candidate); ok {
+				path = p
+				break
+			}
+		}
+		if path == "" {
+			check.Status = DoctorFail
+			check.Message = fmt.Sprintf("%s is not installed or not on the PATH of the project", candidates[0])
+			check.Fix = install
+			d.add(check)
+			return
+		}
+
+		var err error
+		v, err = d.version(path, versionArgs...)
+		if err != nil {
+			check.Status = DoctorFail
+			check.Found = path
+			check.Message = "Cannot read the installed version: " + err.Error()
+			check.Fix = install
+			d.add(check)
+			return
+		}
+		check.Found = v.String() + " (" + path + ")"
+	}
+
+	check.Status = DoctorPass
+	check.Message = fmt.Sprintf("%s %s is installed", name, v)
+	for _, r := range requirements {
+		ok, err := versionSatisfies(v, r.constraint)
+		if err != nil {
+			if check.Status == DoctorPass {
+				check.Status = DoctorWarn
+				check.Message = fmt.Sprintf("Cannot check %s %q: %v", r.source, r.constraint, err)
+			}
+			continue
+		}
+		if ok {
+			continue
+		}
+		status := DoctorWarn
+		if r.strict {
+			status = DoctorFail
+		}
+		if check.Status != DoctorFail {
+			check.Status = status
+			check.Message = fmt.Sprintf("%s %s does not satisfy %q from %s", name, v, r.constraint, r.source)
+			check.Fix = fmt.Sprintf("Install %s %s (%s)", name, r.constraint, install)
+		}
+	}
+	d.add(check)
+}
+
+// checkCommand verifies that the executable of the start command exists
+func (d *doctorRun) checkCommand(tmpl *TemplateContext) {
+	cmd := d.m.prepareCommand(context.Background(), &struct {
+		Command string
+		Args    string
+		Type    string
+	}{
+		Command: tmpl.Expand(d.p.Command),
+		Args:    tmpl.Expand(d.p.Args),
+		Type:    d.p.Type,
+	})
+	if cmd == nil || len(cmd.Args) == 0 {
+		return
+	}
+
+	name := cmd.Args[0]
+	check := DoctorCheck{Name: "command", Category: DoctorCategoryProject, Wanted: name}
+	switch {
+	case strings.ContainsRune(name, filepath.Separator):
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(d.dir, path)
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			check.Status = DoctorFail
+			check.Message = path + " is missing or not executable"
+			check.Fix = "Build the project or chmod +x " + name
+		} else {
+			check.Status = DoctorPass
+			check.Found = path
+			check.Message = "Start command is executable"
+		}
+	case cmd.Err != nil:
+		// The start command is looked up on the PATH of go-runner itself
+		check.Status = DoctorFail
+		check.Message = fmt.Sprintf("%s is not on the PATH of go-runner", name)
+		check.Fix = "Install " + name + " or use its absolute path in the project command"
+	default:
+		check.Status = DoctorPass
+		check.Found = cmd.Path
+		check.Message = "Start command is installed"
+	}
+	d.add(check)
+}
+
+// checkNode checks the node version against engines, .nvmrc and
+// .node-version, and the package manager of the project
+func (d *doctorRun) checkNode() {
+	var pkg struct {
+		Engines         map[string]string `json:"engines"`
+		PackageManager  string            `json:"packageManager"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if data := d.read("package.json"); data != "" {
+		if err := json.Unmarshal([]byte(data), &pkg); err != nil {
+			d.add(DoctorCheck{
+				Name:     "package.json",
+				Category: DoctorCategoryProject,
+				Status:   DoctorFail,
+				Message:  "Cannot parse package.json: " + err.Error(),
+				Fix:      "Fix the syntax of package.json",
+			})
+			return
+		}
+	}
+
+	var requirements []versionRequirement
+	if engine := pkg.Engines["node"]; engine != "" {
+		requirements = append(requirements, versionRequirement{"package.json engines", engine, true})
+	}
+	for _, name := range []string{".nvmrc", ".node-version"} {
+		if wanted := pinnedVersion(d.read(name)); wanted != "" {
+			requirements = append(requirements, versionRequirement{name, wanted, false})
+		}
+	}
+	d.checkToolchain("node", []string{"node"}, []string{"--version"}, requirements,
+		"https://nodejs.org or a version manager such as nvm, fnm or volta")
+
+	if !d.exists("package.json") {
+		return
+	}
+
+	// packageManager pins the package manager through corepack: "pnpm@8.6.0+sha512..."
+	manager, pinned := "", ""
+	if pkg.PackageManager != "" {
+		manager, pinned, _ = strings.Cut(pkg.PackageManager, "@")
+		pinned, _, _ = strings.Cut(pinned, "+")
+	} else {
+		switch {
+		case d.exists("pnpm-lock.yaml"):
+			manager = "pnpm"
+		case d.exists("yarn.lock"):
+			manager = "yarn"
+		case d.exists("bun.lockb"), d.exists("bun.lock"):
+			manager = "bun"
+		default:
+			manager = "npm"
+		}
+	}
+
+	d.checkNodePackageManager(manager, pinned)
+
+	if len(pkg.Dependencies)+len(pkg.DevDependencies) > 0 && !d.exists("node_modules") {
+		d.add(DoctorCheck{
+			Name:     "node_modules",
+			Category: DoctorCategoryPackageManager,
+			Status:   DoctorWarn,
+			Message:  "Dependencies are not installed",
+			Fix:      fmt.Sprintf("Run %s install (POST /projects/%d/install)", manager, d.p.ID),
+		})
+	}
+}
+
+// checkNodePackageManager checks that the package manager of a node project
+// is installed, at the version pinned by packageManager if any
+func (d *doctorRun) checkNodePackageManager(manager, pinned string) {
+	check := DoctorCheck{Name: manager, Category: DoctorCategoryPackageManager, Wanted: pinned}
+	path, ok := d.lookPath(manager)
+	if !ok {
+		check.Status = DoctorFail
+		check.Message = manager + " is not installed"
+		switch {
+		case manager == "npm":
+			check.Fix = "Install npm with node from https://nodejs.org"
+		case manager == "bun":
+			check.Fix = "Install bun from https://bun.sh"
+		case d.hasExecutable("corepack"):
+			check.Fix = "Run corepack enable"
+		default:
+			check.Fix = "Run npm install -g " + manager
+		}
+		d.add(check)
+		return
+	}
+
+	v, err := d.version(path, "--version")
+	switch {
+	case err != nil:
+		check.Status = DoctorWarn
+		check.Found = path
+		check.Message = "Cannot read the installed version: " + err.Error()
+	case pinned != "":
+		check.Found = v.String()
+		if ok, _ := versionSatisfies(v, pinned); ok {
+			check.Status = DoctorPass
+			check.Message = fmt.Sprintf("%s %s matches packageManager", manager, v)
+		} else {
+			check.Status = DoctorWarn
+			check.Message = fmt.Sprintf("%s %s is installed but package.json pins %s", manager, v, pinned)
+			check.Fix = "Run corepack enable so the pinned version is used"
+		}
+	default:
+		check.Status = DoctorPass
+		check.Found = v.String()
+		check.Message = fmt.Sprintf("%s %s is installed", manager, v)
+	}
+	d.add(check)
+}
+
+// checkGo checks the go version against the go directive of go.mod
+func (d *doctorRun) checkGo() {
+	file, err := modfile.ParseLax("go.mod", []byte(d.read("go.mod")), nil)
+	if err != nil {
+		d.add(DoctorCheck{
+			Name:     "go.mod",
+			Category: DoctorCategoryProject,
+			Status:   DoctorFail,
+			Message:  "Cannot parse go.mod: " + err.Error(),
+			Fix:      "Fix the syntax of go.mod",
+		})
+		return
+	}
+
+	var requirements []versionRequirement
+	if file.Go != nil {
+		requirements = append(requirements, versionRequirement{"go.mod go directive", ">=" + file.Go.Version, true})
+	}
+	before := len(d.checks)
+	d.checkToolchain("go", []string{"go"}, []string{"env", "GOVERSION"}, requirements, "https://go.dev/dl")
+
+	// Since go 1.21 the go command downloads a newer toolchain when go.mod
+	// asks for it, unless GOTOOLCHAIN=local
+	check := &d.checks[before]
+	if check.Status != DoctorFail || file.Go == nil {
+		return
+	}
+	installed, ok := parseToolVersion(check.Found)
+	if ok && installed.compare(toolVersion{1, 21, 0}) >= 0 && d.env["GOTOOLCHAIN"] != "local" {
+		check.Status = DoctorWarn
+		check.Message = fmt.Sprintf("go %s will download go %s on first use", installed, file.Go.Version)
+		check.Fix = fmt.Sprintf("Install go %s to avoid the download", file.Go.Version)
+	}
+}
+
+// checkPython checks the python version against pyproject.toml,
+// .python-version and runtime.txt, and the package manager of the project
+func (d *doctorRun) checkPython() {
+	pyproject := d.read("pyproject.toml")
+
+	var requirements []versionRequirement
+	if m := requiresPythonPattern.FindStringSubmatch(pyproject); m != nil {
+		requirements = append(requirements, versionRequirement{"pyproject.toml requires-python", m[1], true})
+	} else if m := poetryPythonPattern.FindStringSubmatch(pyproject); m != nil {
+		requirements = append(requirements, versionRequirement{"pyproject.toml poetry python", m[1], true})
+	}
+	if wanted := pinnedVersion(d.read(".python-version")); wanted != "" {
+		requirements = append(requirements, versionRequirement{".python-version", wanted, false})
+	}
+	if runtimeTxt := strings.TrimPrefix(strings.TrimSpace(d.read("runtime.txt")), "python-"); runtimeTxt != "" {
+		if wanted := pinnedVersion(runtimeTxt); wanted != "" {
+			requirements = append(requirements, versionRequirement{"runtime.txt", wanted, false})
+		}
+	}
+	d.checkToolchain("python", []string{"python3", "python"}, []string{"--version"}, requirements,
+		"https://www.python.org/downloads or a version manager such as pyenv or uv")
+
+	var manager, install string
+	switch {
+	case d.exists("uv.lock"):
+		manager, install = "uv", "Install uv from https://docs.astral.sh/uv"
+	case d.exists("poetry.lock") || strings.Contains(pyproject, "[tool.poetry]"):
+		manager, install = "poetry", "Run pipx install poetry"
+	case d.exists("Pipfile"):
+		manager, install = "pipenv", "Run pipx install pipenv"
+	case d.exists("requirements.txt") || pyproject != "":
+		manager, install = "pip", "Run python3 -m ensurepip --upgrade"
+	default:
+		return
+	}
+
+	check := DoctorCheck{Name: manager, Category: DoctorCategoryPackageManager}
+	candidates := []string{manager}
+	if manager == "pip" {
+		candidates = []string{"pip3", "pip"}
+	}
+	for _, candidate := range candidates {
+		if path, ok := d.lookPath(candidate); ok {
+			check.Found = path
+			break
+		}
+	}
+	if check.Found == "" {
+		check.Status = DoctorFail
+		check.Message = manager + " is not installed"
+		check.Fix = install
+	} else {
+		check.Status = DoctorPass
+		check.Message = manager + " is installed"
+	}
+	d.add(check)
+}
+
+// hasExecutable reports whether an executable is on the PATH of the project
+func (d *doctorRun) hasExecutable(name string) bool {
+	_, ok := d.lookPath(name)
+	return ok
+}
+
+// checkEnv verifies that the variables listed in the .env example of the
+// project are set, and returns their names
+func (d *doctorRun) checkEnv() []string {
+	var example string
+	var lines []EnvFileLine
+	for _, name := range envExampleFiles {
+		if content := d.read(name); content != "" {
+			example, lines = name, ParseEnvFile(content)
+			break
+		}
+	}
+	if example == "" {
+		return nil
+	}
+
+	var required []string
+	for _, line := range lines {
+		if line.Key == "" {
+			continue
+		}
+		required = append(required, line.Key)
+
+		check := DoctorCheck{Name: line.Key, Category: DoctorCategoryEnv, Wanted: "listed in " + example}
+		value, ok := d.env[line.Key]
+		switch {
+		case !ok:
+			check.Status = DoctorFail
+			check.Message = line.Key + " is not set"
+			check.Fix = "Add " + line.Key + " to the .env file or env_vars of the project"
+			if line.Value != "" {
+				check.Fix += fmt.Sprintf(" (example: %s)", line.Value)
+			}
+		case value == "":
+			check.Status = DoctorWarn
+			check.Found = "empty"
+			check.Message = line.Key + " is set but empty"
+			check.Fix = "Set a value for " + line.Key
+		default:
+			check.Status = DoctorPass
+			check.Found = "set"
+			check.Message = line.Key + " is set"
+		}
+		d.add(check)
+	}
+	return required
+}
+
+// checkDatabases probes the database and service URLs the project is
+// configured with: its connection string, and the variables of its .env
+// file, env_vars and .env example
+func (d *doctorRun) checkDatabases(required []string) {
+	targets := make(map[string]string) // Name -> URL
+	if d.p.Type == string(types.TypeDatabase) && d.p.ConnectionString != "" {
+		targets["connection_string"] = d.p.ConnectionString
+	}
+
+	keys := append([]string{}, required...)
+	for key := range d.m.loadEnvFile(ResolveEnvFilePath(d.p.EnvFile, d.p.Path)) {
+		keys = append(keys, key)
+	}
+	for key := range d.m.parseEnvVarsJSON(d.p.EnvVars) {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		if value := d.env[key]; strings.Contains(value, "://") {
+			targets[key] = value
+		}
+	}
+
+	var wg sync.WaitGroup
+	for name, target := range targets {
+		u, err := url.Parse(target)
+		if err != nil {
+			continue
+		}
+		engine, err := DatabaseEngine(target)
+		if err != nil || engine == "sqlite" {
+			engine = ""
+		}
+		if engine == "" && networkServicePorts[u.Scheme] == "" {
+			continue // Not a database or service URL
+		}
+
+		wg.Add(1)
+		go func(name, target string, u *url.URL, engine string) {
+			defer wg.Done()
+			check := DoctorCheck{Name: name, Category: DoctorCategoryDatabase, Wanted: u.Redacted()}
+
+			var probeErr string
+			if engine != "" {
+				stats := ProbeDatabase(d.ctx, target)
+				if !stats.Healthy {
+					probeErr = stats.Error
+				} else {
+					check.Found = strings.TrimSpace(engine + " " + stats.Version)
+				}
+			} else {
+				host := u.Host
+				if u.Port() == "" {
+					host = net.JoinHostPort(u.Hostname(), networkServicePorts[u.Scheme])
+				}
+				conn, err := net.DialTimeout("tcp", host, databaseProbeTimeout)
+				if err != nil {
+					probeErr = err.Error()
+				} else {
+					conn.Close()
+					check.Found = u.Scheme + " reachable"
+				}
+			}
+
+			if probeErr == "" {
+				check.Status = DoctorPass
+				check.Message = "Reachable"
+			} else {
+				check.Status = DoctorFail
+				check.Message = "Unreachable: " + probeErr
+				check.Fix = d.databaseFix(name, u)
+			}
+			d.add(check)
+		}(name, target, u, engine)
+	}
+	wg.Wait()
+}
+
+// databaseFix suggests how to make a database reachable, naming the
+// go-runner project that serves its port when there is one
+func (d *doctorRun) databaseFix(name string, u *url.URL) string {
+	if port, err := strconv.Atoi(u.Port()); err == nil && isLocalHost(u.Hostname()) {
+		var owner struct {
+			ID   uint
+			Name string
+		}
+		err := d.m.db.Table(tables.Name("projects")).Select("id, name").
+			Where("port = ? AND id <> ? AND deleted_at IS NULL", port, d.p.ID).
+			First(&owner).Error
+		if err == nil && d.m.IsServiceRunning(owner.ID) {
+			return fmt.Sprintf("Project %q serves port %d but is not answering, check its logs (GET /projects/%d/logs)", owner.Name, port, owner.ID)
+		}
+		if err == nil {
+			return fmt.Sprintf("Start project %q (POST /projects/%d/start), which serves port %d", owner.Name, owner.ID, port)
+		}
+	}
+	return fmt.Sprintf("Start the service at %s or correct %s", u.Host, name)
+}
+
+// isLocalHost reports whether a host name refers to this machine
+func isLocalHost(host string) bool {
+	if host == "localhost" || host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// pinnedVersion reads a version file such as .nvmrc or .python-version,
+// ignoring aliases (lts/*, system) that do not name a version
+func pinnedVersion(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	line = strings.TrimPrefix(strings.TrimSpace(line), "v")
+	if _, n, err := parsePartialVersion(line); err != nil || n == 0 {
+		return ""
+	}
+	return line
+}
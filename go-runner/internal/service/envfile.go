@@ -0,0 +1,393 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go-runner/internal/tables"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// .env line types
+const (
+	EnvLineVariable = "variable"
+	EnvLineComment  = "comment"
+	EnvLineBlank    = "blank"
+	EnvLineInvalid  = "invalid"
+)
+
+// Environment variable diff statuses
+const (
+	EnvDiffAdded   = "added"
+	EnvDiffChanged = "changed"
+	EnvDiffRemoved = "removed"
+)
+
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// EnvFileLine is one line of a .env file
+type EnvFileLine struct {
+	Number int    `json:"number"`
+	Type   string `json:"type"` // variable, comment, blank, invalid
+	Raw    string `json:"raw"`
+	Key    string `json:"key,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Export bool   `json:"export,omitempty"` // Line uses the `export KEY=value` form
+	Quote  string `json:"-"`                // Quote character around the value, if any
+	Error  string `json:"error,omitempty"`
+}
+
+// EnvFileError describes an invalid .env line
+type EnvFileError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// EnvVarDiff describes one variable that differs between two environments
+type EnvVarDiff struct {
+	Key    string  `json:"key"`
+	Status string  `json:"status"` // added, changed, removed
+	Old    *string `json:"old,omitempty"`
+	New    *string `json:"new,omitempty"`
+}
+
+// EnvFileProcessStatus compares .env variables with the running process
+type EnvFileProcessStatus struct {
+	Running         bool         `json:"running"`
+	PID             int          `json:"pid,omitempty"`
+	RestartRequired bool         `json:"restart_required"`
+	Changes         []EnvVarDiff `json:"changes"`              // Old is the process value, New the file value
+	Overridden      []string     `json:"overridden,omitempty"` // File keys overridden by env_vars
+	Warning         string       `json:"warning,omitempty"`
+}
+
+// ResolveEnvFilePath returns the .env file used for a project: the configured
+// env_file (relative paths are resolved against the project path) or .env in
+// the project path
+func ResolveEnvFilePath(envFile, projectPath string) string {
+	if envFile == "" {
+		if projectPath == "" {
+			return ""
+		}
+		return filepath.Join(projectPath, ".env")
+	}
+	if !filepath.IsAbs(envFile) && projectPath != "" {
+		return filepath.Join(projectPath, envFile)
+	}
+	return envFile
+}
+
+// ParseEnvFile parses .env content line by line, keeping comments and blank
+// lines so the file can be written back unchanged
+func ParseEnvFile(content string) []EnvFileLine {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return []EnvFileLine{}
+	}
+
+	rawLines := strings.Split(content, "\n")
+	lines := make([]EnvFileLine, 0, len(rawLines))
+	for i, raw := range rawLines {
+		lines = append(lines, parseEnvLine(i+1, raw))
+	}
+	return lines
+}
+
+func parseEnvLine(number int, raw string) EnvFileLine {
+	line := EnvFileLine{Number: number, Raw: raw}
+	trimmed := strings.TrimSpace(raw)
+
+	switch {
+	case trimmed == "":
+		line.Type = EnvLineBlank
+		return line
+	case strings.HasPrefix(trimmed, "#"):
+		line.Type = EnvLineComment
+		return line
+	}
+
+	if strings.HasPrefix(trimmed, "export ") {
+		line.Export = true
+		trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "export "))
+	}
+
+	parts := strings.SplitN(trimmed, "=", 2)
+	if len(parts) != 2 {
+		line.Type = EnvLineInvalid
+		line.Error = "expected KEY=VALUE"
+		return line
+	}
+
+	key := strings.TrimSpace(parts[0])
+	if !envKeyPattern.MatchString(key) {
+		line.Type = EnvLineInvalid
+		line.Error = fmt.Sprintf("invalid variable name %q", key)
+		return line
+	}
+
+	value := strings.TrimSpace(parts[1])
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		quote := value[:1]
+		if len(value) < 2 || !strings.HasSuffix(value, quote) {
+			line.Type = EnvLineInvalid
+			line.Error = "unterminated quoted value"
+			return line
+		}
+		line.Quote = quote
+		value = value[1 : len(value)-1]
+	}
+
+	line.Type = EnvLineVariable
+	line.Key = key
+	line.Value = value
+	return line
+}
+
+// ValidateEnvFile returns the invalid lines of a parsed .env file
+func ValidateEnvFile(lines []EnvFileLine) []EnvFileError {
+	var errs []EnvFileError
+	for _, line := range lines {
+		if line.Type == EnvLineInvalid {
+			errs = append(errs, EnvFileError{Line: line.Number, Message: line.Error})
+		}
+	}
+	return errs
+}
+
+// EnvFileVars returns the variables of a parsed .env file; later lines win
+func EnvFileVars(lines []EnvFileLine) map[string]string {
+	vars := make(map[string]string)
+	for _, line := range lines {
+		if line.Type == EnvLineVariable {
+			vars[line.Key] = line.Value
+		}
+	}
+	return vars
+}
+
+// DuplicateEnvKeys returns keys defined more than once
+func DuplicateEnvKeys(lines []EnvFileLine) []string {
+	seen := make(map[string]int)
+	var duplicates []string
+	for _, line := range lines {
+		if line.Type != EnvLineVariable {
+			continue
+		}
+		seen[line.Key]++
+		if seen[line.Key] == 2 {
+			duplicates = append(duplicates, line.Key)
+		}
+	}
+	return duplicates
+}
+
+// ApplyEnvChanges sets and removes variables in a parsed .env file and
+// renders it. Existing lines keep their position, comments and quoting; new
+// variables are appended.
+func ApplyEnvChanges(lines []EnvFileLine, set map[string]string, unset []string) (string, error) {
+	for key := range set {
+		if !envKeyPattern.MatchString(key) {
+			return "", fmt.Errorf("invalid variable name %q", key)
+		}
+	}
+
+	remove := make(map[string]bool, len(unset))
+	for _, key := range unset {
+		remove[key] = true
+	}
+
+	var b strings.Builder
+	written := make(map[string]bool)
+	for _, line := range lines {
+		if line.Type == EnvLineVariable {
+			if remove[line.Key] {
+				continue
+			}
+			if value, ok := set[line.Key]; ok {
+				if written[line.Key] {
+					// Drop later duplicates so the new value is the only one
+					continue
+				}
+				written[line.Key] = true
+				b.WriteString(formatEnvLine(line.Key, value, line.Quote, line.Export))
+				b.WriteString("\n")
+				continue
+			}
+		}
+		b.WriteString(line.Raw)
+		b.WriteString("\n")
+	}
+
+	// Append new variables in a stable order
+	var added []string
+	for key := range set {
+		if !written[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	for _, key := range added {
+		b.WriteString(formatEnvLine(key, set[key], "", false))
+		b.WriteString("\n")
+	}
+
+	return b.String(), nil
+}
+
+// formatEnvLine renders KEY=value, quoting the value when needed
+func formatEnvLine(key, value, quote string, export bool) string {
+	if quote == "" && (strings.ContainsAny(value, " \t#'\"") || value != strings.TrimSpace(value)) {
+		quote = `"`
+	}
+	if quote == `"` && strings.Contains(value, `"`) {
+		quote = "'"
+	}
+
+	line := key + "=" + quote + value + quote
+	if export {
+		line = "export " + line
+	}
+	return line
+}
+
+// DiffEnvVars compares two sets of variables
+func DiffEnvVars(oldVars, newVars map[string]string) []EnvVarDiff {
+	diffs := []EnvVarDiff{}
+	for key, newValue := range newVars {
+		newValue := newValue
+		oldValue, ok := oldVars[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, EnvVarDiff{Key: key, Status: EnvDiffAdded, New: &newValue})
+		case oldValue != newValue:
+			oldValue := oldValue
+			diffs = append(diffs, EnvVarDiff{Key: key, Status: EnvDiffChanged, Old: &oldValue, New: &newValue})
+		}
+	}
+	for key, oldValue := range oldVars {
+		if _, ok := newVars[key]; !ok {
+			oldValue := oldValue
+			diffs = append(diffs, EnvVarDiff{Key: key, Status: EnvDiffRemoved, Old: &oldValue})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
+
+// CompareEnvFile diffs .env variables against the environment of the running
+// process. removed lists keys that were dropped from the file and are stale
+// if the process still has them. Keys overridden by the project's env_vars
+// JSON are reported separately since the file does not decide their value.
+func (m *Manager) CompareEnvFile(projectID uint, fileVars map[string]string, envVarsJSON string, removed []string) *EnvFileProcessStatus {
+	status := &EnvFileProcessStatus{Changes: []EnvVarDiff{}}
+
+	if !m.IsServiceRunning(projectID) {
+		return status
+	}
+	status.Running = true
+
+	pid := m.RunningPID(projectID)
+	if pid <= 0 {
+		status.Warning = "service is running but its process could not be identified"
+		return status
+	}
+	status.PID = pid
+
+	processEnv, err := ProcessEnv(pid)
+	if err != nil {
+		status.Warning = fmt.Sprintf("cannot read environment of process %d: %v", pid, err)
+		return status
+	}
+
+	overrides := m.parseEnvVarsJSON(envVarsJSON)
+	expected := make(map[string]string, len(fileVars))
+	for key, value := range fileVars {
+		if _, ok := overrides[key]; ok {
+			status.Overridden = append(status.Overridden, key)
+			continue
+		}
+		expected[key] = value
+	}
+	sort.Strings(status.Overridden)
+
+	// Only compare keys the file controls; the process also inherits the
+	// go-runner environment
+	actual := make(map[string]string)
+	for key := range expected {
+		if value, ok := processEnv[key]; ok {
+			actual[key] = value
+		}
+	}
+	for _, key := range removed {
+		if _, overridden := overrides[key]; overridden {
+			continue
+		}
+		if value, ok := processEnv[key]; ok && value != os.Getenv(key) {
+			actual[key] = value
+		}
+	}
+
+	status.Changes = DiffEnvVars(actual, expected)
+	status.RestartRequired = len(status.Changes) > 0
+	if status.RestartRequired {
+		status.Warning = "the running service uses a different environment; restart it to apply the .env file"
+	}
+	return status
+}
+
+// RunningPID returns the PID of a project's running process, or 0
+func (m *Manager) RunningPID(projectID uint) int {
+	m.mu.RLock()
+	if info, ok := m.processes[projectID]; ok && info.Process != nil && info.Process.Process != nil {
+		pid := info.Process.Process.Pid
+		m.mu.RUnlock()
+		return pid
+	}
+	m.mu.RUnlock()
+
+	var p struct {
+		PID  int `gorm:"column:p_id"`
+		Port int
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("p_id, port").Where("id = ?", projectID).First(&p).Error; err != nil {
+		return 0
+	}
+	if p.PID > 0 {
+		if exists, _ := process.PidExists(int32(p.PID)); exists {
+			return p.PID
+		}
+	}
+	if p.Port > 0 {
+		if pid, err := m.getPIDByPort(p.Port); err == nil {
+			return pid
+		}
+	}
+	return 0
+}
+
+// ProcessEnv reads the environment a process was started with
+// (/proc/<pid>/environ on Linux)
+func ProcessEnv(pid int) (map[string]string, error) {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil, err
+	}
+	environ, err := proc.Environ()
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string, len(environ))
+	for _, entry := range environ {
+		if key, value, ok := strings.Cut(entry, "="); ok && key != "" {
+			env[key] = value
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessFileDescriptors is the open files of one process against its ulimit
+type ProcessFileDescriptors struct {
+	PID       int32   `json:"pid"`
+	Name      string  `json:"name"`
+	Open      int32   `json:"open"`
+	SoftLimit uint64  `json:"soft_limit"` // RLIMIT_NOFILE, 0 when unknown
+	HardLimit uint64  `json:"hard_limit"`
+	Usage     float64 `json:"usage"` // Percentage of the soft limit
+}
+
+// FileDescriptorStats is the open files of the process tree of a running project
+type FileDescriptorStats struct {
+	Open      int32                    `json:"open"`      // Whole process tree
+	Usage     float64                  `json:"usage"`     // Highest usage of a process in the tree, as each has its own limit
+	Limit     uint64                   `json:"limit"`     // Soft limit of that process
+	Processes []ProcessFileDescriptors `json:"processes"` // Main process first, then its descendants
+	CheckedAt time.Time                `json:"checked_at"`
+}
+
+// fdStats holds the last file descriptor check of each running project
+type fdStats struct {
+	mu    sync.RWMutex
+	stats map[uint]*FileDescriptorStats
+}
+
+// FileDescriptorStatus returns the last file descriptor check of a project, if any
+func (m *Manager) FileDescriptorStatus(projectID uint) *FileDescriptorStats {
+	m.fds.mu.RLock()
+	defer m.fds.mu.RUnlock()
+	return m.fds.stats[projectID]
+}
+
+// CountFileDescriptors counts the open files of a process and its
+// descendants. It returns nil when the main process is gone or the platform
+// does not expose descriptor counts.
+func CountFileDescriptors(pid int) *FileDescriptorStats {
+	root, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil
+	}
+
+	stats := &FileDescriptorStats{Processes: []ProcessFileDescriptors{}, CheckedAt: time.Now()}
+	seen := make(map[int32]bool)
+	queue := []*process.Process{root}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p.Pid] {
+			continue
+		}
+		seen[p.Pid] = true
+
+		open, err := p.NumFDs()
+		if err != nil {
+			if p == root {
+				return nil
+			}
+			continue // Exited meanwhile or not readable
+		}
+		entry := ProcessFileDescriptors{PID: p.Pid, Open: open}
+		entry.Name, _ = p.Name()
+		if limits, err := p.Rlimit(); err == nil {
+			for _, limit := range limits {
+				if limit.Resource == process.RLIMIT_NOFILE {
+					entry.SoftLimit, entry.HardLimit = limit.Soft, limit.Hard
+				}
+			}
+		}
+		if entry.SoftLimit > 0 {
+			entry.Usage = float64(open) / float64(entry.SoftLimit) * 100
+		}
+
+		stats.Processes = append(stats.Processes, entry)
+		stats.Open += open
+		if p == root || entry.Usage > stats.Usage {
+			stats.Usage, stats.Limit = entry.Usage, entry.SoftLimit
+		}
+
+		children, _ := p.Children()
+		queue = append(queue, children...)
+	}
+	return stats
+}
+
+// MonitorFileDescriptors counts the open files of running projects every
+// interval and calls onCheck with each result, and with nil for projects
+// that stopped since the previous check
+func (m *Manager) MonitorFileDescriptors(interval time.Duration, onCheck func(projectID uint, stats *FileDescriptorStats)) {
+	for {
+		var projects []struct {
+			ID  uint
+			PID int `gorm:"column:p_id"`
+		}
+		m.db.Table(tables.Name("projects")).
+			Select("id, p_id").
+			Where("status = ? AND p_id > 0 AND deleted_at IS NULL", string(types.StatusRunning)).
+			Find(&projects)
+
+		current := make(map[uint]*FileDescriptorStats, len(projects))
+		for _, p := range projects {
+			if stats := CountFileDescriptors(p.PID); stats != nil {
+				current[p.ID] = stats
+			}
+		}
+
+		m.fds.mu.Lock()
+		previous := m.fds.stats
+		m.fds.stats = current
+		m.fds.mu.Unlock()
+
+		if onCheck != nil {
+			for id, stats := range current {
+				onCheck(id, stats)
+			}
+			for id := range previous {
+				if _, ok := current[id]; !ok {
+					onCheck(id, nil)
+				}
+			}
+		}
+
+		time.Sleep(m.pollInterval(interval))
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// fileTailPoll is how often a tailed file is checked for new output
+const fileTailPoll = 100 * time.Millisecond
+
+// fileTail reads a file that is still being written, like tail -f, until its
+// context is canceled
+type fileTail struct {
+	ctx  context.Context
+	file *os.File
+	path string // Followed by name (tail -F) when set: reopened when replaced or created
+
+	opened    atomic.Bool  // file is open, a followed file may not exist yet
+	rotations atomic.Int32 // Times a followed file was replaced
+}
+
+// openFileTail opens a file for tailing from its current end
+func openFileTail(ctx context.Context, path string) (*fileTail, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return nil, err
+	}
+	t := &fileTail{ctx: ctx, file: file}
+	t.opened.Store(true)
+	return t, nil
+}
+
+// followFile tails path from its current end, like tail -F: a file renamed
+// away by log rotation is read to its end before the new file is read from
+// its start, and a missing file is read once it is created
+func followFile(ctx context.Context, path string) *fileTail {
+	t := &fileTail{ctx: ctx, path: path}
+	if file, err := os.Open(path); err == nil {
+		file.Seek(0, io.SeekEnd)
+		t.file = file
+		t.opened.Store(true)
+	}
+	return t
+}
+
+// replaced reports whether another file is now at the path of a followed file
+func (t *fileTail) replaced() bool {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return false
+	}
+	current, err := t.file.Stat()
+	return err == nil && !os.SameFile(info, current)
+}
+
+// reopen opens the file now at the path of a followed file, reporting
+// whether it did
+func (t *fileTail) reopen() bool {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return false
+	}
+	if t.file != nil {
+		t.file.Close()
+		t.rotations.Add(1)
+	}
+	t.file = file
+	t.opened.Store(true)
+	return true
+}
+
+// Read waits for new output instead of returning io.EOF at the end of the
+// file, until the context is canceled
+func (t *fileTail) Read(p []byte) (int, error) {
+	for {
+		if t.file == nil {
+			if !t.reopen() {
+				select {
+				case <-t.ctx.Done():
+					return 0, io.EOF
+				case <-time.After(fileTailPoll):
+				}
+			}
+			continue
+		}
+
+		n, err := t.file.Read(p)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		// Truncated in place (copytruncate rotation): read from the start
+		if info, err := t.file.Stat(); err == nil {
+			if offset, err := t.file.Seek(0, io.SeekCurrent); err == nil && offset > info.Size() {
+				t.file.Seek(0, io.SeekStart)
+				continue
+			}
+		}
+		// Renamed away (create rotation): read what was written since, then
+		// the new file
+		if t.path != "" && t.replaced() {
+			if n, _ := t.file.Read(p); n > 0 {
+				return n, nil
+			}
+			t.reopen()
+			continue
+		}
+
+		select {
+		case <-t.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(fileTailPoll):
+		}
+	}
+}
+
+// Close closes the tailed file
+func (t *fileTail) Close() error {
+	if t.file == nil {
+		return nil
+	}
+	return t.file.Close()
+}
+
+// processOutputFile returns the regular file a file descriptor of a process
+// (1 for stdout, 2 for stderr) writes to, or "" for pipes, terminals and
+// sockets
+func processOutputFile(pid, fd int) string {
+	var path string
+	if runtime.GOOS == "linux" {
+		link := filepath.Join("/proc", strconv.Itoa(pid), "fd", strconv.Itoa(fd))
+		if info, err := os.Stat(link); err != nil || !info.Mode().IsRegular() {
+			return ""
+		}
+		path, _ = os.Readlink(link)
+	} else {
+		// lsof -F prints one field per line, the file name prefixed with n
+		out, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-d", strconv.Itoa(fd), "-Fn").Output()
+		if err != nil {
+			return ""
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if name, ok := strings.CutPrefix(line, "n"); ok {
+				path = name
+			}
+		}
+	}
+
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fileChangeHistory is the number of changes kept per project
+const fileChangeHistory = 200
+
+// fileWatchMaxDirs bounds the directories watched per project, as each one
+// uses an inotify watch
+const fileWatchMaxDirs = 2000
+
+// fileChangeCoalesce merges repeated events on a path (editors write files
+// in several steps)
+const fileChangeCoalesce = 200 * time.Millisecond
+
+// fileWatchSkipDirs are dependency, build and VCS directories never watched
+var fileWatchSkipDirs = map[string]bool{
+	".git": true, ".hg": true, ".svn": true, ".idea": true, ".vscode": true,
+	"node_modules": true, "vendor": true, "dist": true, "build": true, "target": true,
+	".next": true, ".nuxt": true, ".cache": true, "__pycache__": true, ".venv": true, "venv": true,
+	".pytest_cache": true, ".terraform": true, "coverage": true, "tmp": true,
+}
+
+// FileChange is a change in a project directory
+type FileChange struct {
+	Path string    `json:"path"` // Relative to the project directory
+	Op   string    `json:"op"`   // create, write, remove, rename, chmod
+	Time time.Time `json:"time"`
+}
+
+// FileChangeFeed is the recent changes in a project directory
+type FileChangeFeed struct {
+	Watching     bool         `json:"watching"`
+	Dir          string       `json:"dir,omitempty"`
+	Directories  int          `json:"directories"`     // Watched directories
+	Truncated    bool         `json:"truncated"`       // More directories than watched
+	LastModified *time.Time   `json:"last_modified"`   // Time of the last change
+	Error        string       `json:"error,omitempty"` // Why the directory cannot be watched
+	Changes      []FileChange `json:"changes"`         // Newest first
+}
+
+// projectFileWatcher watches the directory tree of a project
+type projectFileWatcher struct {
+	dir       string
+	watcher   *fsnotify.Watcher
+	mu        sync.RWMutex
+	dirs      int
+	truncated bool
+	changes   []FileChange // Oldest first
+}
+
+// fileWatchers holds the file watchers of projects with watch_files
+type fileWatchers struct {
+	mu       sync.Mutex
+	watchers map[uint]*projectFileWatcher
+	errors   map[uint]string
+}
+
+// FileChanges returns the recent changes of a project, newest first
+func (m *Manager) FileChanges(projectID uint, limit int) *FileChangeFeed {
+	m.files.mu.Lock()
+	w := m.files.watchers[projectID]
+	watchErr := m.files.errors[projectID]
+	m.files.mu.Unlock()
+
+	feed := &FileChangeFeed{Error: watchErr, Changes: []FileChange{}}
+	if w == nil {
+		return feed
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	feed.Watching = true
+	feed.Dir = w.dir
+	feed.Directories = w.dirs
+	feed.Truncated = w.truncated
+	for i := len(w.changes) - 1; i >= 0 && len(feed.Changes) < limit; i-- {
+		feed.Changes = append(feed.Changes, w.changes[i])
+	}
+	if n := len(w.changes); n > 0 {
+		last := w.changes[n-1].Time
+		feed.LastModified = &last
+	}
+	return feed
+}
+
+// LastFileChange returns the last change of a watched project, or nil
+func (m *Manager) LastFileChange(projectID uint) *FileChange {
+	m.files.mu.Lock()
+	w := m.files.watchers[projectID]
+	m.files.mu.Unlock()
+	if w == nil {
+		return nil
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if len(w.changes) == 0 {
+		return nil
+	}
+	last := w.changes[len(w.changes)-1]
+	return &last
+}
+
+// WatchProjectFiles keeps a file watcher running for each project with
+// watch_files, re-reading the projects every interval. onChange is called
+// with each recorded change.
+func (m *Manager) WatchProjectFiles(interval time.Duration, onChange func(projectID uint, change FileChange)) {
+	for {
+		var projects []struct {
+			ID         uint
+			Path       string
+			WorkingDir string
+		}
+		m.db.Table(tables.Name("projects")).Select("id, path, working_dir").
+			Where("watch_files = ? AND deleted_at IS NULL", true).
+			Find(&projects)
+
+		wanted := make(map[uint]string, len(projects))
+		for _, p := range projects {
+			dir := p.Path
+			if p.WorkingDir != "" {
+				dir = p.WorkingDir
+			}
+			wanted[p.ID] = dir
+		}
+		m.syncFileWatchers(wanted, onChange)
+
+		time.Sleep(m.pollInterval(interval))
+	}
+}
+
+// syncFileWatchers starts and stops watchers to match the wanted directories
+func (m *Manager) syncFileWatchers(wanted map[uint]string, onChange func(projectID uint, change FileChange)) {
+	m.files.mu.Lock()
+	defer m.files.mu.Unlock()
+	if m.files.watchers == nil {
+		m.files.watchers = make(map[uint]*projectFileWatcher)
+		m.files.errors = make(map[uint]string)
+	}
+
+	for id, w := range m.files.watchers {
+		if dir, ok := wanted[id]; !ok || dir != w.dir {
+			w.watcher.Close()
+			delete(m.files.watchers, id)
+		}
+	}
+	for id := range m.files.errors {
+		if _, ok := wanted[id]; !ok {
+			delete(m.files.errors, id)
+		}
+	}
+
+	for id, dir := range wanted {
+		if _, ok := m.files.watchers[id]; ok {
+			continue
+		}
+		w, err := newProjectFileWatcher(dir)
+		if err != nil {
+			// Retried on the next sync, logged once
+			if m.files.errors[id] != err.Error() {
+				log.Printf("Failed to watch files of project %d: %v", id, err)
+			}
+			m.files.errors[id] = err.Error()
+			continue
+		}
+		delete(m.files.errors, id)
+		m.files.watchers[id] = w
+
+		projectID := id
+		go w.run(func(change FileChange) {
+			if onChange != nil {
+				onChange(projectID, change)
+			}
+		})
+	}
+}
+
+// newProjectFileWatcher watches dir and its subdirectories
+func newProjectFileWatcher(dir string) (*projectFileWatcher, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &projectFileWatcher{dir: dir, watcher: watcher}
+	if err := w.addTree(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// addTree watches a directory and its subdirectories, skipping dependency
+// and build directories
+func (w *projectFileWatcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil // Unreadable subdirectory
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && fileWatchSkipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.dirs >= fileWatchMaxDirs {
+			w.truncated = true
+			return filepath.SkipAll
+		}
+		if err := w.watcher.Add(path); err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		w.dirs++
+		return nil
+	})
+}
+
+// run records events until the watcher is closed
+func (w *projectFileWatcher) run(onChange func(FileChange)) {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if change, ok := w.record(event); ok {
+				onChange(change)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("File watcher error in %s: %v", w.dir, err)
+		}
+	}
+}
+
+// record stores an event, watching new directories. Events repeating the
+// last change of a path are dropped.
+func (w *projectFileWatcher) record(event fsnotify.Event) (FileChange, bool) {
+	rel, err := filepath.Rel(w.dir, event.Name)
+	if err != nil {
+		rel = event.Name
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if fileWatchSkipDirs[part] {
+			return FileChange{}, false
+		}
+	}
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			w.addTree(event.Name)
+		}
+	}
+
+	change := FileChange{Path: filepath.ToSlash(rel), Op: fileChangeOp(event.Op), Time: time.Now()}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i := len(w.changes) - 1; i >= 0; i-- {
+		prev := w.changes[i]
+		if change.Time.Sub(prev.Time) > fileChangeCoalesce {
+			break
+		}
+		if prev.Path == change.Path && (prev.Op == change.Op || prev.Op == "create" && change.Op == "write") {
+			return FileChange{}, false
+		}
+	}
+	w.changes = append(w.changes, change)
+	if len(w.changes) > fileChangeHistory {
+		w.changes = w.changes[len(w.changes)-fileChangeHistory:]
+	}
+	return change, true
+}
+
+// fileChangeOp names the main operation of an event
+func fileChangeOp(op fsnotify.Op) string {
+	switch {
+	case op.Has(fsnotify.Create):
+		return "create"
+	case op.Has(fsnotify.Remove):
+		return "remove"
+	case op.Has(fsnotify.Rename):
+		return "rename"
+	case op.Has(fsnotify.Write):
+		return "write"
+	}
+	return "chmod"
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// followLogs runs a command streaming the logs of a project that is not a
+// local process (Kubernetes pods, journald) and feeds its output to the usual
+// log buffer and subscribers. Nothing is done if the project already has a
+// process or follower.
+func (m *Manager) followLogs(projectID uint, name string, args ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.processes[projectID]; exists {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, name, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return err
+	}
+
+	processInfo := &ProcessInfo{
+		ProjectID:   projectID,
+		Process:     cmd,
+		Context:     ctx,
+		Cancel:      cancel,
+		StartTime:   time.Now(),
+		Logs:        make(chan LogEntry, 1000),
+		LogBuffer:   make([]LogEntry, 0, 1000),
+		LogFollower: true,
+	}
+	m.processes[projectID] = processInfo
+
+	go m.captureOutputWithBuffer(stdout, processInfo, false)
+	go m.captureOutputWithBuffer(stderr, processInfo, true)
+	go m.monitorProcess(processInfo)
+	return nil
+}
+
+// stopLogFollower stops the log follower of a project
+func (m *Manager) stopLogFollower(projectID uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if processInfo, exists := m.processes[projectID]; exists && processInfo.LogFollower {
+		processInfo.Cancel()
+		processInfo.safeCloseChannel()
+		delete(m.processes, projectID)
+	}
+}
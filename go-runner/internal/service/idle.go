@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// defaultWakeTimeout is how long a proxied request waits for its project to start
+const defaultWakeTimeout = 60 * time.Second
+
+// IdleOptions configures starting projects on demand
+type IdleOptions struct {
+	WakeTimeout time.Duration // How long a proxied request waits for the port of a stopped project
+}
+
+// idleTracker records proxied requests per project and the starts they
+// triggered
+type idleTracker struct {
+	mu       sync.Mutex
+	opts     IdleOptions
+	last     map[uint]time.Time // Last proxied request, or when idle tracking began
+	inflight map[uint]int       // Proxied requests in progress
+	waking   map[uint]*wakeCall // Starts in progress, shared by concurrent requests
+}
+
+// wakeCall is an on-demand start that requests wait for
+type wakeCall struct {
+	done chan struct{}
+	err  error
+}
+
+// SetIdleOptions sets how long proxied requests wait for on-demand starts
+func (m *Manager) SetIdleOptions(opts IdleOptions) {
+	if opts.WakeTimeout <= 0 {
+		opts.WakeTimeout = defaultWakeTimeout
+	}
+	m.idle.mu.Lock()
+	m.idle.opts = opts
+	m.idle.mu.Unlock()
+}
+
+// BeginProxiedRequest records a request proxied to a project and returns the
+// function that ends it. Projects are not stopped for being idle while
+// requests are in progress.
+func (m *Manager) BeginProxiedRequest(projectID uint) func() {
+	t := &m.idle
+	t.mu.Lock()
+	if t.last == nil {
+		t.last = make(map[uint]time.Time)
+		t.inflight = make(map[uint]int)
+	}
+	t.last[projectID] = time.Now()
+	t.inflight[projectID]++
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			t.last[projectID] = time.Now()
+			if t.inflight[projectID]--; t.inflight[projectID] <= 0 {
+				delete(t.inflight, projectID)
+			}
+			t.mu.Unlock()
+		})
+	}
+}
+
+// LastProxiedRequest returns when the last proxied request to a project
+// ended, or nil when there was none
+func (m *Manager) LastProxiedRequest(projectID uint) *time.Time {
+	m.idle.mu.Lock()
+	defer m.idle.mu.Unlock()
+	last, ok := m.idle.last[projectID]
+	if !ok {
+		return nil
+	}
+	return &last
+}
+
+// WakeProject starts a stopped project for a proxied request and waits until
+// its port accepts connections, for at most the wake timeout. Concurrent
+// requests share one start.
+func (m *Manager) WakeProject(projectID uint, port int) error {
+	if portAccepts(port) {
+		return nil
+	}
+
+	t := &m.idle
+	t.mu.Lock()
+	if call, ok := t.waking[projectID]; ok {
+		t.mu.Unlock()
+		<-call.done
+		return call.err
+	}
+	if t.waking == nil {
+		t.waking = make(map[uint]*wakeCall)
+	}
+	call := &wakeCall{done: make(chan struct{})}
+	t.waking[projectID] = call
+	timeout := t.opts.WakeTimeout
+	t.mu.Unlock()
+	if timeout <= 0 {
+		timeout = defaultWakeTimeout
+	}
+
+	call.err = m.wake(projectID, port, timeout)
+
+	t.mu.Lock()
+	delete(t.waking, projectID)
+	t.mu.Unlock()
+	close(call.done)
+	return call.err
+}
+
+// wake starts a project unless it is already starting, then waits for its port
+func (m *Manager) wake(projectID uint, port int, timeout time.Duration) error {
+	m.mu.RLock()
+	_, running := m.processes[projectID]
+	m.mu.RUnlock()
+
+	if !running {
+		log.Printf("☕ Starting project %d for a proxied request", projectID)
+		err := m.StartService(projectID)
+		var inProgress *OperationInProgressError
+		if err != nil && !(errors.As(err, &inProgress) && inProgress.Current.Name == OperationStart) {
+			return fmt.Errorf("failed to start project: %v", err)
+		}
+	}
+
+	if !m.waitForPort(port, timeout) {
+		return fmt.Errorf("port %d not accepting connections after %s", port, timeout)
+	}
+	return nil
+}
+
+// MonitorIdle stops running projects with an idle_timeout once they went that
+// many minutes without proxied requests, checking every interval. onStop is
+// called with each project stopped and how long it was idle.
+func (m *Manager) MonitorIdle(interval time.Duration, onStop func(projectID uint, idle time.Duration)) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	for {
+		var projects []struct {
+			ID          uint
+			IdleTimeout int
+			StartTime   *time.Time
+		}
+		m.db.Table(tables.Name("projects")).
+			Select("id, idle_timeout, start_time").
+			Where("idle_timeout > 0 AND status = ? AND deleted_at IS NULL", string(types.StatusRunning)).
+			Find(&projects)
+
+		for _, p := range projects {
+			idle, ok := m.idleFor(p.ID, p.StartTime)
+			if !ok || idle < time.Duration(p.IdleTimeout)*time.Minute {
+				continue
+			}
+
+			idle = idle.Round(time.Second)
+			log.Printf("💤 Stopping project %d after %s without proxied requests", p.ID, idle)
+			if err := m.StopService(p.ID); err != nil {
+				log.Printf("Failed to stop idle project %d: %v", p.ID, err)
+				continue
+			}
+			if onStop != nil {
+				onStop(p.ID, idle)
+			}
+		}
+
+		time.Sleep(m.pollInterval(interval))
+	}
+}
+
+// idleFor returns how long a project went without proxied requests, counted
+// from its start when it has not had any. ok is false while requests are in
+// progress.
+func (m *Manager) idleFor(projectID uint, startTime *time.Time) (time.Duration, bool) {
+	t := &m.idle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.inflight[projectID] > 0 {
+		return 0, false
+	}
+	since := t.last[projectID]
+	if startTime != nil && startTime.After(since) {
+		since = *startTime
+	}
+	if since.IsZero() {
+		// Started before go-runner, count from now
+		if t.last == nil {
+			t.last = make(map[uint]time.Time)
+			t.inflight = make(map[uint]int)
+		}
+		t.last[projectID] = time.Now()
+		return 0, true
+	}
+	return time.Since(since), true
+}
+
+// portAccepts reports whether a local port accepts connections, cheaper than
+// isPortInUse for the requests to a running project
+func portAccepts(port int) bool {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 200*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
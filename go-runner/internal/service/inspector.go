@@ -0,0 +1,230 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// Inspector ports are picked from this range when none is requested
+const (
+	inspectorBasePort = 9229
+	inspectorPorts    = 100
+)
+
+// inspectorStartTimeout is how long a restart waits for the inspector
+const inspectorStartTimeout = 15 * time.Second
+
+var (
+	// ErrNotNodeProject is returned for projects that are not run by Node.js
+	ErrNotNodeProject = errors.New("not a Node.js project")
+	// ErrInspectorUnsupported is returned for projects not run as a local process
+	ErrInspectorUnsupported = errors.New("the inspector is only available for local processes")
+	// ErrInspectorPortInUse is returned when the requested port is taken
+	ErrInspectorPortInUse = errors.New("inspector port is in use")
+)
+
+// nodeRuntimes take --inspect as their own flag; other commands, such as
+// package managers, get it through NODE_OPTIONS
+var nodeRuntimes = map[string]bool{"node": true, "nodemon": true, "tsx": true}
+
+// nodeCommands start Node.js processes
+var nodeCommands = map[string]bool{"node": true, "nodemon": true, "tsx": true, "ts-node": true, "npm": true, "npx": true, "yarn": true, "pnpm": true}
+
+// NodeInspector is the Node.js inspector of a project restarted for debugging
+type NodeInspector struct {
+	Port         int    `json:"port"`
+	Listening    bool   `json:"listening"`               // The inspector answered on the port
+	Title        string `json:"title,omitempty"`         // Script being debugged
+	DevToolsURL  string `json:"devtools_url,omitempty"`  // Open in Chrome, or chrome://inspect
+	WebSocketURL string `json:"websocket_url,omitempty"` // For editors attaching by URL
+}
+
+// EnableInspector restarts a Node.js project with the inspector on port, a
+// free port from 9229 when 0, and waits for the DevTools URL. The inspector
+// stays on across restarts until DisableInspector or a manual stop.
+func (m *Manager) EnableInspector(projectID uint, port int) (*NodeInspector, error) {
+	if err := m.checkInspectable(projectID); err != nil {
+		return nil, err
+	}
+
+	var current int
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Select("inspect_port").Scan(&current)
+	switch {
+	case port == 0 && current > 0:
+		port = current
+	case port == 0:
+		if port = m.freeInspectorPort(); port == 0 {
+			return nil, fmt.Errorf("no free port between %d and %d", inspectorBasePort, inspectorBasePort+inspectorPorts-1)
+		}
+	case port != current && !inspectorPortFree(port):
+		return nil, ErrInspectorPortInUse
+	}
+
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("inspect_port", port).Error; err != nil {
+		return nil, err
+	}
+	if err := m.RestartService(projectID); err != nil {
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("inspect_port", current)
+		return nil, err
+	}
+
+	deadline := time.Now().Add(inspectorStartTimeout)
+	for {
+		inspector := readInspector(port)
+		if inspector.Listening || time.Now().After(deadline) {
+			return inspector, nil
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// DisableInspector turns the inspector off and restarts the project without
+// it when it is running
+func (m *Manager) DisableInspector(projectID uint) error {
+	var p struct {
+		InspectPort int
+		Status      string
+	}
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Select("inspect_port, status").Take(&p).Error; err != nil {
+		return fmt.Errorf("project not found: %v", err)
+	}
+	if p.InspectPort == 0 {
+		return nil
+	}
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("inspect_port", 0).Error; err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	_, running := m.processes[projectID]
+	m.mu.RUnlock()
+	if !running {
+		return nil
+	}
+	return m.RestartService(projectID)
+}
+
+// InspectorStatus returns the inspector of a project, or nil when it is off
+func (m *Manager) InspectorStatus(projectID uint) *NodeInspector {
+	var port int
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Select("inspect_port").Scan(&port)
+	if port == 0 {
+		return nil
+	}
+	return readInspector(port)
+}
+
+// clearInspector turns the inspector off without a restart, so that a
+// stopped project starts normally next time
+func (m *Manager) clearInspector(projectID uint) {
+	m.db.Table(tables.Name("projects")).Where("id = ? AND inspect_port <> 0", projectID).Update("inspect_port", 0)
+}
+
+// checkInspectable returns an error unless the project is a local process
+// started by a Node.js command, or with a package.json
+func (m *Manager) checkInspectable(projectID uint) error {
+	if m.kubeTarget(projectID) != nil || m.systemdTarget(projectID) != nil || m.sshTarget(projectID) != nil || m.isMockProject(projectID) {
+		return ErrInspectorUnsupported
+	}
+	var p struct {
+		Path       string
+		Command    string
+		WorkingDir string
+	}
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Select("path, command, working_dir").Take(&p).Error; err != nil {
+		return fmt.Errorf("project not found: %v", err)
+	}
+	if words := strings.Fields(p.Command); len(words) > 0 && nodeCommands[filepath.Base(words[0])] {
+		return nil
+	}
+	dir := p.Path
+	if p.WorkingDir != "" {
+		dir = p.WorkingDir
+	}
+	if _, err := os.Stat(filepath.Join(dir, "package.json")); err == nil {
+		return nil
+	}
+	return ErrNotNodeProject
+}
+
+// applyInspector enables the inspector of a command being started, as a flag
+// of node, nodemon and tsx, else through NODE_OPTIONS
+func (m *Manager) applyInspector(cmd *exec.Cmd, port int) {
+	flag := fmt.Sprintf("--inspect=127.0.0.1:%d", port)
+	if len(cmd.Args) > 0 && nodeRuntimes[filepath.Base(cmd.Args[0])] {
+		cmd.Args = append([]string{cmd.Args[0], flag}, cmd.Args[1:]...)
+		return
+	}
+	options := flag
+	for _, kv := range cmd.Env {
+		if value, ok := strings.CutPrefix(kv, "NODE_OPTIONS="); ok && value != "" {
+			options = value + " " + flag
+		}
+	}
+	cmd.Env = m.removeEnvVar(cmd.Env, "NODE_OPTIONS")
+	cmd.Env = append(cmd.Env, "NODE_OPTIONS="+options)
+}
+
+// freeInspectorPort returns a free port from 9229 not used by the inspector
+// of another project, or 0
+func (m *Manager) freeInspectorPort() int {
+	var used []int
+	m.db.Table(tables.Name("projects")).Where("inspect_port > 0 AND deleted_at IS NULL").Pluck("inspect_port", &used)
+	taken := make(map[int]bool, len(used))
+	for _, port := range used {
+		taken[port] = true
+	}
+	for port := inspectorBasePort; port < inspectorBasePort+inspectorPorts; port++ {
+		if !taken[port] && inspectorPortFree(port) {
+			return port
+		}
+	}
+	return 0
+}
+
+// inspectorPortFree reports whether a loopback port can be listened on
+func inspectorPortFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// readInspector asks the inspector on port for its debugging target
+func readInspector(port int) *NodeInspector {
+	inspector := &NodeInspector{Port: port}
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/json/list", port))
+	if err != nil {
+		return inspector
+	}
+	defer resp.Body.Close()
+
+	var targets []struct {
+		Title                string `json:"title"`
+		DevtoolsFrontendURL  string `json:"devtoolsFrontendUrl"`
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&targets) != nil {
+		return inspector
+	}
+	inspector.Listening = true
+	if len(targets) > 0 {
+		inspector.Title = targets[0].Title
+		inspector.DevToolsURL = targets[0].DevtoolsFrontendURL
+		inspector.WebSocketURL = targets[0].WebSocketDebuggerURL
+	}
+	return inspector
+}
@@ -0,0 +1,461 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// Instance port modes (instance_ports)
+const (
+	InstancePortsOffset = "offset" // Port of the project plus the instance index
+	InstancePortsAuto   = "auto"   // A free port picked on each start
+)
+
+// EnvInstanceIndex tells an instance which copy of its project it is, 1 and
+// up; the main process does not get it
+const EnvInstanceIndex = "INSTANCE_INDEX"
+
+// Instance limits
+const (
+	MaxInstances         = 16
+	instanceRestartDelay = 2 * time.Second
+	instanceStopGrace    = 10 * time.Second
+	instanceLogTailBytes = 256 << 10
+)
+
+// ErrInstanceNotFound is returned for an instance index a project does not have
+var ErrInstanceNotFound = errors.New("instance not found")
+
+// ServiceInstance is a copy of the service of a project. Index 0 is the main
+// process, managed like a project with one instance; the others run the same
+// command on their own port.
+type ServiceInstance struct {
+	Index     int        `json:"index"`
+	Status    string     `json:"status"` // running, stopped, error
+	PID       int        `json:"pid,omitempty"`
+	Port      int        `json:"port,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+	Restarts  int        `json:"restarts"` // Automatic restarts after crashes (auto_restart)
+	LastError string     `json:"last_error,omitempty"`
+	LogFile   string     `json:"log_file,omitempty"` // Combined stdout and stderr, for indexes from 1
+
+	cmd      *exec.Cmd
+	cancel   context.CancelFunc
+	stopping bool
+}
+
+// instanceState holds the extra instances of projects and where the proxy
+// sent the last request
+type instanceState struct {
+	mu       sync.Mutex
+	projects map[uint][]*ServiceInstance // Index 1 at position 0
+	next     map[uint]int                // Round robin position of the proxy
+}
+
+// ValidateInstances checks instances and instance_ports as saved
+func ValidateInstances(instances int, ports string) error {
+	if instances < 0 || instances > MaxInstances {
+		return fmt.Errorf("instances must be between 1 and %d", MaxInstances)
+	}
+	switch ports {
+	case "", InstancePortsOffset, InstancePortsAuto:
+		return nil
+	}
+	return fmt.Errorf("unknown instance_ports %q, expected %s or %s", ports, InstancePortsOffset, InstancePortsAuto)
+}
+
+// instanceLogPath returns the output file of an extra instance
+func (m *Manager) instanceLogPath(projectID uint, index int) string {
+	return filepath.Join(m.logOptions().Dir, fmt.Sprintf("project-%d.instance-%d.log", projectID, index))
+}
+
+// ScaleInstances starts the missing extra instances of a running project and
+// stops the ones beyond its instances count. A project that is not running
+// gets its instances on the next start.
+func (m *Manager) ScaleInstances(projectID uint) {
+	var p struct {
+		Instances     int
+		InstancePorts string
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("instances, instance_ports").Where("id = ?", projectID).Take(&p).Error; err != nil {
+		return
+	}
+	m.mu.RLock()
+	info, running := m.processes[projectID]
+	m.mu.RUnlock()
+	if !running || info.LogFollower {
+		m.stopInstances(projectID)
+		return
+	}
+	want := p.Instances - 1
+	if want < 0 {
+		want = 0
+	}
+
+	m.instances.mu.Lock()
+	if m.instances.projects == nil {
+		m.instances.projects = make(map[uint][]*ServiceInstance)
+		m.instances.next = make(map[uint]int)
+	}
+	current := m.instances.projects[projectID]
+	var surplus []*ServiceInstance
+	if len(current) > want {
+		surplus = current[want:]
+		current = current[:want]
+	}
+	for len(current) < want {
+		current = append(current, &ServiceInstance{Index: len(current) + 1, Status: string(types.StatusStopped)})
+	}
+	m.instances.projects[projectID] = current
+	var start []*ServiceInstance
+	for _, instance := range current {
+		// Concurrent scales leave the instances being started to the first
+		if instance.cmd == nil && instance.Status != string(types.StatusStarting) {
+			instance.Status = string(types.StatusStarting)
+			start = append(start, instance)
+		}
+	}
+	m.instances.mu.Unlock()
+
+	for _, instance := range surplus {
+		m.stopInstanceProcess(instance)
+	}
+	for _, instance := range start {
+		if err := m.startInstance(projectID, instance, p.InstancePorts); err != nil {
+			log.Printf("⚠️  Project %d: failed to start instance %d: %v", projectID, instance.Index, err)
+		}
+	}
+}
+
+// startInstance runs one extra instance of a project, like the main process
+// but on its own port and output file, with INSTANCE_INDEX set
+func (m *Manager) startInstance(projectID uint, instance *ServiceInstance, portMode string) error {
+	var p struct {
+		ID          uint
+		Name        string
+		Type        string
+		GroupID     *uint
+		Path        string
+		Command     string
+		Args        string
+		WorkingDir  string
+		Port        int
+		Environment string
+		EnvFile     string
+		EnvVars     string
+	}
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Take(&p).Error; err != nil {
+		return fmt.Errorf("project not found: %v", err)
+	}
+
+	port := 0
+	if p.Port > 0 {
+		if portMode == InstancePortsAuto {
+			free, err := freePort()
+			if err != nil {
+				return m.instanceFailed(projectID, instance, fmt.Errorf("no free port: %v", err))
+			}
+			port = free
+		} else {
+			port = p.Port + instance.Index
+			if m.isPortInUse(port) {
+				return m.instanceFailed(projectID, instance, fmt.Errorf("port %d is already in use", port))
+			}
+		}
+	}
+
+	tmpl := m.buildTemplateContext(p.ID, p.Name, p.Path, p.Environment, port, p.GroupID)
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := m.prepareCommand(ctx, &struct {
+		Command string
+		Args    string
+		Type    string
+	}{
+		Command: tmpl.Expand(p.Command),
+		Args:    tmpl.Expand(p.Args),
+		Type:    p.Type,
+	})
+	cmd.Dir = p.Path
+	if p.WorkingDir != "" {
+		cmd.Dir = p.WorkingDir
+	}
+	cmd.Env = m.prepareEnvironment(&struct {
+		Port        int
+		Environment string
+		EnvFile     string
+		EnvVars     string
+		Path        string
+		Template    *TemplateContext
+	}{
+		Port:        port,
+		Environment: p.Environment,
+		EnvFile:     p.EnvFile,
+		EnvVars:     p.EnvVars,
+		Path:        p.Path,
+		Template:    tmpl,
+	})
+	if port > 0 {
+		cmd.Env = append(m.removeEnvVar(cmd.Env, "PORT"), fmt.Sprintf("PORT=%d", port))
+	}
+	cmd.Env = append(m.removeEnvVar(cmd.Env, EnvInstanceIndex), fmt.Sprintf("%s=%d", EnvInstanceIndex, instance.Index))
+	cmd.Env = m.sessionEnv(cmd.Env, projectID)
+	m.applyToolchains(projectID, cmd)
+
+	logPath := m.instanceLogPath(projectID, instance.Index)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		cancel()
+		return m.instanceFailed(projectID, instance, err)
+	}
+	rotateLogFile(logPath, m.logOptions().MaxSize)
+	output, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		cancel()
+		return m.instanceFailed(projectID, instance, err)
+	}
+	defer output.Close()
+	fmt.Fprintf(output, "[go-runner] %s (instance %d)\n", startupBanner(p.Name, cmd, 0), instance.Index)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return m.instanceFailed(projectID, instance, err)
+	}
+
+	now := time.Now()
+	m.instances.mu.Lock()
+	instance.cmd, instance.cancel, instance.stopping = cmd, cancel, false
+	instance.Status, instance.PID, instance.Port = string(types.StatusRunning), cmd.Process.Pid, port
+	instance.StartedAt, instance.StoppedAt, instance.LastError, instance.LogFile = &now, nil, "", logPath
+	m.instances.mu.Unlock()
+	log.Printf("🧩 Project %d: instance %d started (PID %d, port %d)", projectID, instance.Index, cmd.Process.Pid, port)
+
+	go m.monitorInstance(projectID, instance, cmd, portMode)
+	return nil
+}
+
+// instanceFailed records why an instance could not start
+func (m *Manager) instanceFailed(projectID uint, instance *ServiceInstance, err error) error {
+	now := time.Now()
+	m.instances.mu.Lock()
+	instance.Status, instance.LastError, instance.StoppedAt = string(types.StatusError), err.Error(), &now
+	instance.cmd, instance.PID = nil, 0
+	m.instances.mu.Unlock()
+	return err
+}
+
+// monitorInstance records the exit of an instance and restarts it after a
+// crash when the project has auto_restart, up to max_restarts times
+func (m *Manager) monitorInstance(projectID uint, instance *ServiceInstance, cmd *exec.Cmd, portMode string) {
+	err := cmd.Wait()
+
+	now := time.Now()
+	m.instances.mu.Lock()
+	if instance.cmd != cmd {
+		m.instances.mu.Unlock()
+		return
+	}
+	stopped := instance.stopping
+	instance.cmd, instance.cancel, instance.PID, instance.StoppedAt = nil, nil, 0, &now
+	instance.Status = string(types.StatusStopped)
+	if !stopped && err != nil {
+		instance.Status, instance.LastError = string(types.StatusError), err.Error()
+	}
+	restarts := instance.Restarts
+	m.instances.mu.Unlock()
+	if stopped {
+		return
+	}
+	log.Printf("⚠️  Project %d: instance %d exited: %v", projectID, instance.Index, err)
+
+	var p struct {
+		AutoRestart bool
+		MaxRestarts int
+	}
+	m.db.Table(tables.Name("projects")).Select("auto_restart, max_restarts").Where("id = ?", projectID).Take(&p)
+	if !p.AutoRestart || restarts >= p.MaxRestarts {
+		return
+	}
+	time.Sleep(instanceRestartDelay)
+	if !m.IsServiceRunning(projectID) || !m.hasInstance(projectID, instance) {
+		return
+	}
+	m.instances.mu.Lock()
+	instance.Restarts++
+	m.instances.mu.Unlock()
+	if err := m.startInstance(projectID, instance, portMode); err != nil {
+		log.Printf("⚠️  Project %d: failed to restart instance %d: %v", projectID, instance.Index, err)
+	}
+}
+
+// hasInstance reports whether an instance is still one of its project
+func (m *Manager) hasInstance(projectID uint, instance *ServiceInstance) bool {
+	m.instances.mu.Lock()
+	defer m.instances.mu.Unlock()
+	for _, current := range m.instances.projects[projectID] {
+		if current == instance {
+			return true
+		}
+	}
+	return false
+}
+
+// stopInstances stops and forgets the extra instances of a project
+func (m *Manager) stopInstances(projectID uint) {
+	m.stopInstanceProcesses(m.detachInstances(projectID))
+}
+
+// detachInstances forgets the extra instances of a project and returns them
+func (m *Manager) detachInstances(projectID uint) []*ServiceInstance {
+	m.instances.mu.Lock()
+	defer m.instances.mu.Unlock()
+	instances := m.instances.projects[projectID]
+	delete(m.instances.projects, projectID)
+	return instances
+}
+
+// stopInstanceProcesses stops instances at once and waits for them
+func (m *Manager) stopInstanceProcesses(instances []*ServiceInstance) {
+	var wg sync.WaitGroup
+	for _, instance := range instances {
+		wg.Add(1)
+		go func(instance *ServiceInstance) {
+			defer wg.Done()
+			m.stopInstanceProcess(instance)
+		}(instance)
+	}
+	wg.Wait()
+}
+
+// stopInstanceProcess stops the process of an instance: SIGTERM, then
+// SIGKILL after a grace period
+func (m *Manager) stopInstanceProcess(instance *ServiceInstance) {
+	m.instances.mu.Lock()
+	cmd, cancel := instance.cmd, instance.cancel
+	instance.stopping = true
+	m.instances.mu.Unlock()
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	cmd.Process.Signal(os.Interrupt)
+	deadline := time.Now().Add(instanceStopGrace)
+	for time.Now().Before(deadline) && processAlive(cmd.Process.Pid) {
+		time.Sleep(100 * time.Millisecond)
+		m.instances.mu.Lock()
+		exited := instance.cmd != cmd
+		m.instances.mu.Unlock()
+		if exited {
+			return
+		}
+	}
+	cancel()
+}
+
+// Instances returns the instances of a project: the main process as index
+// 0, then the extra ones
+func (m *Manager) Instances(projectID uint) ([]ServiceInstance, error) {
+	var p struct {
+		Status    string
+		PID       int `gorm:"column:p_id"`
+		Port      int
+		StartTime *time.Time
+		StopTime  *time.Time
+		LastError string
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("status, p_id, port, start_time, stop_time, last_error").
+		Where("id = ? AND deleted_at IS NULL", projectID).Take(&p).Error; err != nil {
+		return nil, err
+	}
+	main := ServiceInstance{Index: 0, Status: p.Status, PID: p.PID, Port: m.ActivePort(projectID, p.Port), LastError: p.LastError}
+	if p.Status == string(types.StatusRunning) {
+		main.StartedAt = p.StartTime
+	} else {
+		main.StoppedAt = p.StopTime
+	}
+
+	result := []ServiceInstance{main}
+	m.instances.mu.Lock()
+	for _, instance := range m.instances.projects[projectID] {
+		result = append(result, *instance)
+	}
+	m.instances.mu.Unlock()
+	return result, nil
+}
+
+// InstanceLogs returns the last lines of the output of an extra instance
+func (m *Manager) InstanceLogs(projectID uint, index, lines int) ([]string, error) {
+	if index < 1 {
+		return nil, ErrInstanceNotFound
+	}
+	path := m.instanceLogPath(projectID, index)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrInstanceNotFound
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	// The last lines are in the end of the file
+	if info, err := file.Stat(); err == nil && info.Size() > instanceLogTailBytes {
+		file.Seek(-instanceLogTailBytes, io.SeekEnd)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+	all := strings.Split(string(data), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return all, nil
+}
+
+// instancePorts returns the ports of the running extra instances of a project
+func (m *Manager) instancePorts(projectID uint) []int {
+	m.instances.mu.Lock()
+	defer m.instances.mu.Unlock()
+	var ports []int
+	for _, instance := range m.instances.projects[projectID] {
+		if instance.cmd != nil && instance.Port > 0 {
+			ports = append(ports, instance.Port)
+		}
+	}
+	return ports
+}
+
+// balancedPort picks the port of the next request to a project round robin
+// over the main process and the running extra instances
+func (m *Manager) balancedPort(projectID uint, port int) int {
+	ports := append([]int{port}, m.instancePorts(projectID)...)
+	if len(ports) == 1 {
+		return port
+	}
+	m.instances.mu.Lock()
+	defer m.instances.mu.Unlock()
+	if m.instances.next == nil {
+		m.instances.next = make(map[uint]int)
+	}
+	i := m.instances.next[projectID] % len(ports)
+	m.instances.next[projectID] = i + 1
+	return ports[i]
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// operationSamples is how many recent durations are kept per operation
+const operationSamples = 100
+
+// dbWriteWindow is the span write rates are computed over
+const dbWriteWindow = time.Minute
+
+// LogChannelStats reports the log delivery of one service process
+type LogChannelStats struct {
+	ProjectID  uint       `json:"project_id"`
+	Captured   uint64     `json:"captured"` // Lines read from the process
+	Backlog    int        `json:"backlog"`  // Lines waiting in the log channel
+	Capacity   int        `json:"capacity"` // Size of the log channel
+	Dropped    uint64     `json:"dropped"`  // Lines not sent because the channel was full
+	Buffered   int        `json:"buffered"` // Lines in the log buffer
+	LastLineAt *time.Time `json:"last_line_at,omitempty"`
+}
+
+// OperationLatency summarizes recent durations of a lifecycle operation
+type OperationLatency struct {
+	Name   string  `json:"name"`  // start, stop, restart
+	Count  uint64  `json:"count"` // Since go-runner started
+	LastMs float64 `json:"last_ms"`
+	AvgMs  float64 `json:"avg_ms"` // Over the recent samples
+	P95Ms  float64 `json:"p95_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// DBWriteStats reports the writes go-runner makes to its database
+type DBWriteStats struct {
+	Total     uint64            `json:"total"`
+	Errors    uint64            `json:"errors"`
+	LastMin   uint64            `json:"last_minute"`
+	PerSecond float64           `json:"per_second"` // Over the last minute
+	ByTable   map[string]uint64 `json:"by_table"`   // Since go-runner started, "raw" for Exec statements
+}
+
+// operationTimings holds recent durations per lifecycle operation
+type operationTimings struct {
+	mu      sync.Mutex
+	counts  map[string]uint64
+	samples map[string][]time.Duration
+}
+
+// record adds the duration of a finished operation
+func (t *operationTimings) record(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.counts == nil {
+		t.counts = make(map[string]uint64)
+		t.samples = make(map[string][]time.Duration)
+	}
+	t.counts[name]++
+	samples := append(t.samples[name], d)
+	if len(samples) > operationSamples {
+		samples = samples[len(samples)-operationSamples:]
+	}
+	t.samples[name] = samples
+}
+
+// dbWriteCounter counts database writes per second over the last minute
+type dbWriteCounter struct {
+	mu      sync.Mutex
+	total   uint64
+	errors  uint64
+	byTable map[string]uint64
+	seconds [60]uint64 // Writes per second, indexed by Unix second modulo 60
+	stamps  [60]int64  // Unix second each slot was last written
+}
+
+// add counts one write to a table
+func (c *dbWriteCounter) add(table string, failed bool) {
+	now := time.Now().Unix()
+	slot := now % int64(len(c.seconds))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byTable == nil {
+		c.byTable = make(map[string]uint64)
+	}
+	c.total++
+	if failed {
+		c.errors++
+	}
+	if table == "" {
+		table = "raw" // Exec with hand-written SQL
+	}
+	c.byTable[table]++
+	if c.stamps[slot] != now {
+		c.stamps[slot], c.seconds[slot] = now, 0
+	}
+	c.seconds[slot]++
+}
+
+// TrackDatabaseWrites counts the creates, updates, deletes and raw statements
+// run on the database, for GET /system/internals
+func (m *Manager) TrackDatabaseWrites() {
+	count := func(db *gorm.DB) {
+		m.dbWrites.add(db.Statement.Table, db.Error != nil)
+	}
+	callbacks := m.db.Callback()
+	callbacks.Create().After("gorm:create").Register("internals:count_writes", count)
+	callbacks.Update().After("gorm:update").Register("internals:count_writes", count)
+	callbacks.Delete().After("gorm:delete").Register("internals:count_writes", count)
+	callbacks.Raw().After("gorm:raw").Register("internals:count_writes", count)
+}
+
+// LogChannelStats reports the log delivery of each running service process
+func (m *Manager) LogChannelStats() []LogChannelStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make([]LogChannelStats, 0, len(m.processes))
+	for projectID, info := range m.processes {
+		s := LogChannelStats{
+			ProjectID: projectID,
+			Captured:  info.logCaptured.Load(),
+			Backlog:   len(info.Logs),
+			Capacity:  cap(info.Logs),
+			Dropped:   info.logDropped.Load(),
+		}
+		info.logMu.Lock()
+		s.Buffered = len(info.LogBuffer)
+		if n := len(info.LogBuffer); n > 0 {
+			at := info.LogBuffer[n-1].Time
+			s.LastLineAt = &at
+		}
+		info.logMu.Unlock()
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ProjectID < stats[j].ProjectID })
+	return stats
+}
+
+// OperationLatencies summarizes the recent durations of start, stop and
+// restart
+func (m *Manager) OperationLatencies() []OperationLatency {
+	m.opTimings.mu.Lock()
+	defer m.opTimings.mu.Unlock()
+
+	latencies := make([]OperationLatency, 0, len(m.opTimings.samples))
+	for name, samples := range m.opTimings.samples {
+		if len(samples) == 0 {
+			continue
+		}
+		sorted := sortedDurations(samples)
+		var sum time.Duration
+		for _, d := range sorted {
+			sum += d
+		}
+		latencies = append(latencies, OperationLatency{
+			Name:   name,
+			Count:  m.opTimings.counts[name],
+			LastMs: durationMs(samples[len(samples)-1]),
+			AvgMs:  durationMs(sum / time.Duration(len(sorted))),
+			P95Ms:  durationMs(percentile(sorted, 95)),
+			MaxMs:  durationMs(sorted[len(sorted)-1]),
+		})
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i].Name < latencies[j].Name })
+	return latencies
+}
+
+// DatabaseWrites reports the writes counted since TrackDatabaseWrites
+func (m *Manager) DatabaseWrites() DBWriteStats {
+	c := &m.dbWrites
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := DBWriteStats{Total: c.total, Errors: c.errors, ByTable: make(map[string]uint64, len(c.byTable))}
+	for table, n := range c.byTable {
+		stats.ByTable[table] = n
+	}
+	since := time.Now().Add(-dbWriteWindow).Unix()
+	for i, stamp := range c.stamps {
+		if stamp > since {
+			stats.LastMin += c.seconds[i]
+		}
+	}
+	stats.PerSecond = float64(stats.LastMin) / dbWriteWindow.Seconds()
+	return stats
+}
+
+// durationMs converts a duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
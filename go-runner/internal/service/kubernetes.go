@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// ErrKubernetesDisabled is returned for Kubernetes projects when
+// kubernetes.enabled is off
+var ErrKubernetesDisabled = errors.New("kubernetes mode is disabled (kubernetes.enabled)")
+
+// kubectlTimeout bounds the kubectl calls that do not stream
+const kubectlTimeout = 20 * time.Second
+
+// kubeFailureReasons are container waiting reasons that mean the deployment
+// is broken rather than still starting
+var kubeFailureReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// KubeOptions configures the Kubernetes mode
+type KubeOptions struct {
+	Enabled    bool
+	Kubectl    string // kubectl binary
+	Kubeconfig string // Kubeconfig file, kubectl's default when empty
+}
+
+// KubeTarget is the deployment a Kubernetes project controls
+type KubeTarget struct {
+	Context    string `json:"context,omitempty"`
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment"`
+}
+
+// KubeDeployment is the observed state of a deployment and its pods
+type KubeDeployment struct {
+	KubeTarget
+	Replicas          int       `json:"replicas"` // Desired
+	ReadyReplicas     int       `json:"ready_replicas"`
+	AvailableReplicas int       `json:"available_replicas"`
+	UpdatedReplicas   int       `json:"updated_replicas"`
+	Status            string    `json:"status"`            // Project status derived from readiness
+	Message           string    `json:"message,omitempty"` // Why the deployment is failing
+	Pods              []KubePod `json:"pods"`
+	Error             string    `json:"error,omitempty"`
+	CheckedAt         time.Time `json:"checked_at"`
+
+	selector string
+}
+
+// KubePod is a pod of a deployment
+type KubePod struct {
+	Name      string     `json:"name"`
+	Phase     string     `json:"phase"`
+	Ready     bool       `json:"ready"`
+	Restarts  int        `json:"restarts"`
+	Reason    string     `json:"reason,omitempty"` // Waiting or termination reason
+	Node      string     `json:"node,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+}
+
+// KubeDeploymentSummary is a deployment found in a namespace, for import
+type KubeDeploymentSummary struct {
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	Replicas      int    `json:"replicas"`
+	ReadyReplicas int    `json:"ready_replicas"`
+	Images        string `json:"images"`
+}
+
+// kubeState holds the Kubernetes options and the last observation per project
+type kubeState struct {
+	mu       sync.RWMutex
+	opts     KubeOptions
+	observed map[uint]*KubeDeployment
+}
+
+// kubeDeploymentJSON is the part of a kubectl deployment object we read
+type kubeDeploymentJSON struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Replicas *int `json:"replicas"`
+		Selector struct {
+			MatchLabels map[string]string `json:"matchLabels"`
+		} `json:"selector"`
+		Template struct {
+			Spec struct {
+				Containers []struct {
+					Image string `json:"image"`
+				} `json:"containers"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+	Status struct {
+		Replicas          int `json:"replicas"`
+		ReadyReplicas     int `json:"readyReplicas"`
+		AvailableReplicas int `json:"availableReplicas"`
+		UpdatedReplicas   int `json:"updatedReplicas"`
+		Conditions        []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// kubePodJSON is the part of a kubectl pod object we read
+type kubePodJSON struct {
+	Metadata struct {
+		Name              string     `json:"name"`
+		DeletionTimestamp *time.Time `json:"deletionTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		NodeName string `json:"nodeName"`
+	} `json:"spec"`
+	Status struct {
+		Phase             string     `json:"phase"`
+		StartTime         *time.Time `json:"startTime"`
+		ContainerStatuses []struct {
+			Ready        bool `json:"ready"`
+			RestartCount int  `json:"restartCount"`
+			State        struct {
+				Waiting *struct {
+					Reason string `json:"reason"`
+				} `json:"waiting"`
+				Terminated *struct {
+					Reason string `json:"reason"`
+				} `json:"terminated"`
+			} `json:"state"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// SetKubeOptions sets the Kubernetes configuration
+func (m *Manager) SetKubeOptions(opts KubeOptions) {
+	if opts.Kubectl == "" {
+		opts.Kubectl = "kubectl"
+	}
+	m.kube.mu.Lock()
+	defer m.kube.mu.Unlock()
+	m.kube.opts = opts
+}
+
+// KubeStatus returns the last observation of a Kubernetes project, or nil
+func (m *Manager) KubeStatus(projectID uint) *KubeDeployment {
+	m.kube.mu.RLock()
+	defer m.kube.mu.RUnlock()
+	return m.kube.observed[projectID]
+}
+
+// kubeTarget returns the deployment of a Kubernetes project, or nil for a
+// local process
+func (m *Manager) kubeTarget(projectID uint) *KubeTarget {
+	var p struct {
+		KubeContext    string
+		KubeNamespace  string
+		KubeDeployment string
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("kube_context, kube_namespace, kube_deployment").
+		Where("id = ?", projectID).Take(&p).Error; err != nil || p.KubeDeployment == "" {
+		return nil
+	}
+	return &KubeTarget{Context: p.KubeContext, Namespace: kubeNamespace(p.KubeNamespace), Deployment: p.KubeDeployment}
+}
+
+func kubeNamespace(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
+
+// kubectlArgs prefixes args with the context, kubeconfig and namespace
+func (m *Manager) kubectlArgs(target KubeTarget, args ...string) (string, []string) {
+	m.kube.mu.RLock()
+	opts := m.kube.opts
+	m.kube.mu.RUnlock()
+
+	var full []string
+	if opts.Kubeconfig != "" {
+		full = append(full, "--kubeconfig", opts.Kubeconfig)
+	}
+	if target.Context != "" {
+		full = append(full, "--context", target.Context)
+	}
+	if target.Namespace != "" {
+		full = append(full, "--namespace", target.Namespace)
+	}
+	return opts.Kubectl, append(full, args...)
+}
+
+// kubectl runs a kubectl command and returns its output
+func (m *Manager) kubectl(ctx context.Context, target KubeTarget, args ...string) ([]byte, error) {
+	name, full := m.kubectlArgs(target, args...)
+	ctx, cancel := context.WithTimeout(ctx, kubectlTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, name, full...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("kubectl %s: %s", args[0], firstLine(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("kubectl %s: %v", args[0], err)
+	}
+	return out, nil
+}
+
+func (m *Manager) kubeEnabled() bool {
+	m.kube.mu.RLock()
+	defer m.kube.mu.RUnlock()
+	return m.kube.opts.Enabled
+}
+
+// ListKubeDeployments lists the deployments of a namespace, or of all
+// namespaces when namespace is empty
+func (m *Manager) ListKubeDeployments(ctx context.Context, kubeContext, namespace string) ([]KubeDeploymentSummary, error) {
+	if !m.kubeEnabled() {
+		return nil, ErrKubernetesDisabled
+	}
+
+	args := []string{"get", "deployments", "-o", "json"}
+	if namespace == "" {
+		args = append(args, "--all-namespaces")
+	}
+	out, err := m.kubectl(ctx, KubeTarget{Context: kubeContext, Namespace: namespace}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Items []kubeDeploymentJSON `json:"items"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("invalid kubectl output: %v", err)
+	}
+
+	deployments := make([]KubeDeploymentSummary, 0, len(list.Items))
+	for _, d := range list.Items {
+		var images []string
+		for _, c := range d.Spec.Template.Spec.Containers {
+			images = append(images, c.Image)
+		}
+		replicas := 1
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		deployments = append(deployments, KubeDeploymentSummary{
+			Namespace:     d.Metadata.Namespace,
+			Name:          d.Metadata.Name,
+			Replicas:      replicas,
+			ReadyReplicas: d.Status.ReadyReplicas,
+			Images:        strings.Join(images, ", "),
+		})
+	}
+	return deployments, nil
+}
+
+// observeKube reads a deployment and its pods
+func (m *Manager) observeKube(ctx context.Context, target KubeTarget) *KubeDeployment {
+	d := &KubeDeployment{KubeTarget: target, Status: string(types.StatusUnknown), Pods: []KubePod{}, CheckedAt: time.Now()}
+
+	out, err := m.kubectl(ctx, target, "get", "deployment", target.Deployment, "-o", "json")
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+	var dep kubeDeploymentJSON
+	if err := json.Unmarshal(out, &dep); err != nil {
+		d.Error = "invalid kubectl output: " + err.Error()
+		return d
+	}
+
+	d.Replicas = 1
+	if dep.Spec.Replicas != nil {
+		d.Replicas = *dep.Spec.Replicas
+	}
+	d.ReadyReplicas = dep.Status.ReadyReplicas
+	d.AvailableReplicas = dep.Status.AvailableReplicas
+	d.UpdatedReplicas = dep.Status.UpdatedReplicas
+	d.selector = kubeSelector(dep.Spec.Selector.MatchLabels)
+
+	failure := ""
+	for _, c := range dep.Status.Conditions {
+		if c.Type == "Progressing" && c.Reason == "ProgressDeadlineExceeded" {
+			failure = c.Message
+		}
+	}
+
+	if d.selector != "" {
+		if out, err := m.kubectl(ctx, target, "get", "pods", "-l", d.selector, "-o", "json"); err == nil {
+			var pods struct {
+				Items []kubePodJSON `json:"items"`
+			}
+			if json.Unmarshal(out, &pods) == nil {
+				for _, p := range pods.Items {
+					pod := KubePod{Name: p.Metadata.Name, Phase: p.Status.Phase, Node: p.Spec.NodeName, StartedAt: p.Status.StartTime, Ready: len(p.Status.ContainerStatuses) > 0}
+					if p.Metadata.DeletionTimestamp != nil {
+						pod.Phase = "Terminating"
+					}
+					for _, cs := range p.Status.ContainerStatuses {
+						pod.Ready = pod.Ready && cs.Ready
+						pod.Restarts += cs.RestartCount
+						if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+							pod.Reason = cs.State.Waiting.Reason
+						} else if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" && pod.Reason == "" {
+							pod.Reason = cs.State.Terminated.Reason
+						}
+					}
+					if kubeFailureReasons[pod.Reason] && failure == "" {
+						failure = fmt.Sprintf("pod %s: %s", pod.Name, pod.Reason)
+					}
+					d.Pods = append(d.Pods, pod)
+				}
+				sort.Slice(d.Pods, func(i, j int) bool { return d.Pods[i].Name < d.Pods[j].Name })
+			}
+		}
+	}
+
+	switch {
+	case d.Replicas == 0 && len(d.Pods) == 0:
+		d.Status = string(types.StatusStopped)
+	case d.Replicas == 0:
+		d.Status = string(types.StatusStopping)
+	case failure != "":
+		d.Status = string(types.StatusError)
+		d.Message = failure
+	case d.ReadyReplicas > 0:
+		d.Status = string(types.StatusRunning)
+	default:
+		d.Status = string(types.StatusStarting)
+	}
+	return d
+}
+
+// kubeSelector turns match labels into a label selector
+func kubeSelector(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// CheckKube observes the deployment of a Kubernetes project, stores the
+// result and syncs the project status and health with pod readiness
+func (m *Manager) CheckKube(ctx context.Context, projectID uint) (*KubeDeployment, error) {
+	if !m.kubeEnabled() {
+		return nil, ErrKubernetesDisabled
+	}
+	target := m.kubeTarget(projectID)
+	if target == nil {
+		return nil, fmt.Errorf("project %d has no Kubernetes deployment", projectID)
+	}
+
+	d := m.observeKube(ctx, *target)
+	m.kube.mu.Lock()
+	if m.kube.observed == nil {
+		m.kube.observed = make(map[uint]*KubeDeployment)
+	}
+	m.kube.observed[projectID] = d
+	m.kube.mu.Unlock()
+
+	if d.Error != "" {
+		return d, nil
+	}
+
+	health := "unknown"
+	if d.Replicas > 0 {
+		health = "unhealthy"
+		if d.ReadyReplicas >= d.Replicas {
+			health = "healthy"
+		}
+	}
+	var current struct{ Status string }
+	m.db.Table(tables.Name("projects")).Select("status").Where("id = ?", projectID).Take(&current)
+	updates := map[string]interface{}{"status": d.Status, "health_status": health}
+	if d.Status == string(types.StatusError) {
+		updates["last_error"] = d.Message
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(updates)
+	if current.Status != d.Status {
+		reason := fmt.Sprintf("%d/%d pods ready", d.ReadyReplicas, d.Replicas)
+		if d.Message != "" {
+			reason = d.Message
+		}
+		m.RecordStatus(projectID, d.Status, reason)
+	}
+
+	// Keep following the logs while pods are wanted (rollouts end kubectl logs -f)
+	if d.Replicas > 0 && d.selector != "" {
+		m.followKubeLogs(projectID, *target, d.selector)
+	}
+	return d, nil
+}
+
+// startKube scales the deployment of a project back up and follows its logs
+func (m *Manager) startKube(projectID uint, target KubeTarget) error {
+	if !m.kubeEnabled() {
+		return ErrKubernetesDisabled
+	}
+
+	var p struct{ KubeReplicas int }
+	m.db.Table(tables.Name("projects")).Select("kube_replicas").Where("id = ?", projectID).Take(&p)
+	replicas := p.KubeReplicas
+	if replicas <= 0 {
+		replicas = 1
+	}
+
+	if _, err := m.kubectl(context.Background(), target, "scale", "deployment", target.Deployment, "--replicas", strconv.Itoa(replicas)); err != nil {
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":     string(types.StatusError),
+			"last_error": err.Error(),
+		})
+		m.RecordStatus(projectID, string(types.StatusError), "Failed to scale up: "+err.Error())
+		return err
+	}
+
+	now := time.Now()
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+		"status":     string(types.StatusStarting),
+		"start_time": &now,
+		"last_error": "",
+	})
+	m.RecordStatus(projectID, string(types.StatusStarting), fmt.Sprintf("Scaled to %d replicas", replicas))
+
+	_, err := m.CheckKube(context.Background(), projectID)
+	return err
+}
+
+// stopKube remembers the replica count of a deployment and scales it to zero
+func (m *Manager) stopKube(projectID uint, target KubeTarget) error {
+	if !m.kubeEnabled() {
+		return ErrKubernetesDisabled
+	}
+
+	if out, err := m.kubectl(context.Background(), target, "get", "deployment", target.Deployment, "-o", "jsonpath={.spec.replicas}"); err == nil {
+		if replicas, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil && replicas > 0 {
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("kube_replicas", replicas)
+		}
+	}
+
+	if _, err := m.kubectl(context.Background(), target, "scale", "deployment", target.Deployment, "--replicas", "0"); err != nil {
+		return err
+	}
+	m.stopLogFollower(projectID)
+
+	now := time.Now()
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+		"status":    string(types.StatusStopping),
+		"stop_time": &now,
+	})
+	m.RecordStatus(projectID, string(types.StatusStopping), "Scaled to 0 replicas")
+
+	_, err := m.CheckKube(context.Background(), projectID)
+	return err
+}
+
+// followKubeLogs streams the logs of the deployment pods into the project
+// logs, unless a follower is already running
+func (m *Manager) followKubeLogs(projectID uint, target KubeTarget, selector string) {
+	name, args := m.kubectlArgs(target, "logs", "-f", "-l", selector, "--all-containers", "--prefix", "--tail", "100", "--max-log-requests", "20")
+	if err := m.followLogs(projectID, name, args...); err != nil {
+		log.Printf("Failed to follow logs of %s/%s: %v", target.Namespace, target.Deployment, err)
+	}
+}
+
+// MonitorKubernetes syncs the Kubernetes projects with their deployments
+// every interval. onCheck is called with each observation whose status changed.
+func (m *Manager) MonitorKubernetes(interval time.Duration, onCheck func(projectID uint, d *KubeDeployment)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var projects []struct{ ID uint }
+		m.db.Table(tables.Name("projects")).Select("id").
+			Where("kube_deployment <> '' AND deleted_at IS NULL").
+			Find(&projects)
+
+		for _, p := range projects {
+			previous := m.KubeStatus(p.ID)
+			d, err := m.CheckKube(context.Background(), p.ID)
+			if err != nil {
+				continue
+			}
+			if onCheck != nil && (previous == nil || previous.Status != d.Status || previous.ReadyReplicas != d.ReadyReplicas) {
+				onCheck(p.ID, d)
+			}
+		}
+
+		<-ticker.C
+	}
+}
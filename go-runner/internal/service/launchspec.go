@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-runner/internal/tables"
+)
+
+// LaunchSpec is how a project would be started, with placeholders resolved,
+// for exporting it to other tools such as editors
+type LaunchSpec struct {
+	Command string            `json:"command"` // Executable and its fixed arguments
+	Args    []string          `json:"args"`    // Command words after the executable, plus args
+	Dir     string            `json:"dir"`
+	Env     map[string]string `json:"env"`                // Project variables only, without the server environment
+	EnvFile string            `json:"env_file,omitempty"` // .env file loaded on start, when it exists
+}
+
+// ProjectLaunchSpec resolves the command, working directory and variables a
+// start of the project would use
+func (m *Manager) ProjectLaunchSpec(projectID uint) (*LaunchSpec, error) {
+	return m.launchSpec(projectID, true)
+}
+
+// launchSpec resolves the launch of a project; references to other projects
+// are left as is without resolveReferences, so that they cannot loop
+func (m *Manager) launchSpec(projectID uint, resolveReferences bool) (*LaunchSpec, error) {
+	var p struct {
+		ID          uint
+		Name        string
+		Path        string
+		Command     string
+		Args        string
+		WorkingDir  string
+		Port        int
+		Environment string
+		EnvFile     string
+		EnvVars     string
+		GroupID     *uint
+	}
+	if err := m.db.Table(tables.Name("projects")).Where("id = ? AND deleted_at IS NULL", projectID).Take(&p).Error; err != nil {
+		return nil, fmt.Errorf("project not found: %v", err)
+	}
+
+	tmpl := m.buildTemplateContext(p.ID, p.Name, p.Path, p.Environment, p.Port, p.GroupID)
+	if !resolveReferences {
+		tmpl.references = nil
+	}
+	spec := &LaunchSpec{Dir: p.Path, Env: make(map[string]string)}
+	if p.WorkingDir != "" {
+		spec.Dir = p.WorkingDir
+	}
+
+	words := strings.Fields(tmpl.Expand(p.Command))
+	if len(words) > 0 {
+		spec.Command = words[0]
+		spec.Args = words[1:]
+	}
+	spec.Args = append(spec.Args, strings.Fields(tmpl.Expand(p.Args))...)
+
+	// Same precedence as a start: .env, then env_vars, then defaults
+	if path := ResolveEnvFilePath(p.EnvFile, p.Path); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			spec.EnvFile = path
+			for k, v := range m.loadEnvFile(path) {
+				spec.Env[k] = v
+			}
+		}
+	}
+	for k, v := range m.parseEnvVarsJSON(p.EnvVars) {
+		spec.Env[k] = tmpl.Expand(v)
+	}
+	if _, ok := spec.Env["PORT"]; !ok && p.Port > 0 {
+		spec.Env["PORT"] = strconv.Itoa(p.Port)
+	}
+	if _, ok := spec.Env["ENVIRONMENT"]; !ok && p.Environment != "" {
+		spec.Env["ENVIRONMENT"] = p.Environment
+	}
+	return spec, nil
+}
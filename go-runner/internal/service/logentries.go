@@ -0,0 +1,85 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// LogEntry is a captured log line with the time go-runner read it
+type LogEntry struct {
+	Time time.Time `json:"time"` // UTC, zero for lines saved before capture times were recorded
+	Line string    `json:"line"`
+}
+
+// newLogEntry stamps a captured line with the current time in UTC
+func newLogEntry(line string) LogEntry {
+	return LogEntry{Time: time.Now().UTC(), Line: line}
+}
+
+// logLines returns the lines of entries
+func logLines(entries []LogEntry) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.Line
+	}
+	return lines
+}
+
+// parseStoredLogs pairs the saved lines of a project with their capture
+// times. Both are JSON arrays; lines saved without times get a zero time.
+func parseStoredLogs(logs, times string) []LogEntry {
+	var lines []string
+	if logs == "" || json.Unmarshal([]byte(logs), &lines) != nil {
+		return nil
+	}
+	var stamps []time.Time
+	if times != "" {
+		json.Unmarshal([]byte(times), &stamps)
+	}
+	if len(stamps) != len(lines) {
+		stamps = nil // Saved by different writers, the pairing can't be trusted
+	}
+
+	entries := make([]LogEntry, len(lines))
+	for i, line := range lines {
+		entries[i].Line = line
+		if stamps != nil {
+			entries[i].Time = stamps[i].UTC()
+		}
+	}
+	return entries
+}
+
+// GetServiceLogEntries returns the buffered log entries of a service
+func (m *Manager) GetServiceLogEntries(projectID uint) []LogEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if processInfo, exists := m.processes[projectID]; exists {
+		return processInfo.getLogEntries()
+	}
+	if entries := m.mockLogEntries(projectID); entries != nil {
+		return entries
+	}
+	return []LogEntry{}
+}
+
+// GetLogEntries returns the buffered log entries of a service, or the ones
+// saved to the database when nothing is buffered
+func (m *Manager) GetLogEntries(projectID uint) []LogEntry {
+	if entries := m.GetServiceLogEntries(projectID); len(entries) > 0 {
+		return entries
+	}
+
+	var p struct {
+		Logs     string
+		LogTimes string
+	}
+	m.db.Table(tables.Name("projects")).Select("logs, log_times").Where("id = ? AND deleted_at IS NULL", projectID).Take(&p)
+	if entries := parseStoredLogs(p.Logs, p.LogTimes); entries != nil {
+		return entries
+	}
+	return []LogEntry{}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultLogDir holds the output files when no directory is configured
+const defaultLogDir = "./data/logs"
+
+// LogOptions configures the output files of service processes
+type LogOptions struct {
+	Dir         string // One stdout and one stderr file per project
+	MaxSize     int64  // Bytes before a file is rotated to <file>.1, 0 for no limit
+	MaxLineSize int    // Bytes of a log line kept, the rest is truncated; 256 KB when 0
+}
+
+// logFiles holds the output file options
+type logFiles struct {
+	mu       sync.RWMutex
+	opts     LogOptions
+	onRotate func(projectID uint, stream, rotated string)
+}
+
+// SetLogOptions sets where service output is written
+func (m *Manager) SetLogOptions(opts LogOptions) {
+	if opts.Dir == "" {
+		opts.Dir = defaultLogDir
+	}
+	if dir, err := filepath.Abs(opts.Dir); err == nil {
+		opts.Dir = dir
+	}
+	m.logFiles.mu.Lock()
+	defer m.logFiles.mu.Unlock()
+	m.logFiles.opts = opts
+}
+
+// SetLogRotationListener sets a function called after an output file of a
+// project is rotated, with its stream (stdout or stderr) and the rotated
+// file. It runs before the next rotation can replace the file, so it should
+// copy the file rather than hold on to its path.
+func (m *Manager) SetLogRotationListener(listener func(projectID uint, stream, rotated string)) {
+	m.logFiles.mu.Lock()
+	defer m.logFiles.mu.Unlock()
+	m.logFiles.onRotate = listener
+}
+
+// rotateLogs rotates the output files of a project over maxSize and tells
+// the rotation listener
+func (m *Manager) rotateLogs(projectID uint, maxSize int64) {
+	stdout, stderr := m.LogFilePaths(projectID)
+	m.logFiles.mu.RLock()
+	listener := m.logFiles.onRotate
+	m.logFiles.mu.RUnlock()
+
+	for stream, path := range map[string]string{"stdout": stdout, "stderr": stderr} {
+		if rotateLogFile(path, maxSize) && listener != nil {
+			listener(projectID, stream, path+".1")
+		}
+	}
+}
+
+// logOptions returns the output file options
+func (m *Manager) logOptions() LogOptions {
+	m.logFiles.mu.RLock()
+	defer m.logFiles.mu.RUnlock()
+	opts := m.logFiles.opts
+	if opts.Dir == "" {
+		opts.Dir = defaultLogDir
+	}
+	return opts
+}
+
+// LogFilePaths returns the files the stdout and stderr of a project go to
+func (m *Manager) LogFilePaths(projectID uint) (string, string) {
+	dir := m.logOptions().Dir
+	return filepath.Join(dir, fmt.Sprintf("project-%d.out.log", projectID)),
+		filepath.Join(dir, fmt.Sprintf("project-%d.err.log", projectID))
+}
+
+// AppendOutput writes a go-runner line to the stdout file of a running
+// project, where it is tailed into the logs like the output of the service.
+// It reports whether the project was running.
+func (m *Manager) AppendOutput(projectID uint, line string) bool {
+	m.mu.RLock()
+	_, running := m.processes[projectID]
+	m.mu.RUnlock()
+	if !running {
+		return false
+	}
+	stdout, _ := m.LogFilePaths(projectID)
+	f, err := os.OpenFile(stdout, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "[go-runner] %s\n", line)
+	return err == nil
+}
+
+// openLogFiles opens the output files of a project for appending, rotating
+// the ones over the size limit first
+func (m *Manager) openLogFiles(projectID uint) (*os.File, *os.File, error) {
+	opts := m.logOptions()
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, nil, err
+	}
+
+	m.rotateLogs(projectID, opts.MaxSize)
+	stdoutPath, stderrPath := m.LogFilePaths(projectID)
+
+	stdout, err := os.OpenFile(stdoutPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, nil, err
+	}
+	stderr, err := os.OpenFile(stderrPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		stdout.Close()
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// rotateLogFile copies a file over maxSize to <file>.1 and truncates it. The
+// file is truncated rather than renamed because a running process keeps
+// appending to it; lines written between the copy and the truncation are lost.
+// It reports whether the file was rotated.
+func rotateLogFile(path string, maxSize int64) bool {
+	info, err := os.Stat(path)
+	if err != nil || maxSize <= 0 || info.Size() < maxSize {
+		return false
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer src.Close()
+	dst, err := os.Create(path + ".1")
+	if err != nil {
+		return false
+	}
+	_, err = io.Copy(dst, src)
+	dst.Close()
+	if err != nil {
+		log.Printf("⚠️  Failed to rotate %s: %v", path, err)
+		return false
+	}
+	return os.Truncate(path, 0) == nil
+}
+
+// RotateServiceLogs rotates the output files of running services over the
+// size limit every interval
+func (m *Manager) RotateServiceLogs(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		maxSize := m.logOptions().MaxSize
+		if maxSize <= 0 {
+			continue
+		}
+
+		var projectIDs []uint
+		m.mu.RLock()
+		for id, info := range m.processes {
+			if !info.LogFollower {
+				projectIDs = append(projectIDs, id)
+			}
+		}
+		m.mu.RUnlock()
+
+		for _, id := range projectIDs {
+			m.rotateLogs(id, maxSize)
+		}
+	}
+}
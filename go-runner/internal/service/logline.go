@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultMaxLineSize is the longest captured log line kept whole
+const defaultMaxLineSize = 256 << 10
+
+// logLineReader splits service output into log lines. Unlike bufio.Scanner it
+// does not stop at long lines: lines over maxSize are truncated, with the
+// number of bytes dropped appended. Runs of binary output are replaced by a
+// single line saying how much was skipped.
+type logLineReader struct {
+	r       *bufio.Reader
+	maxSize int
+	binary  int    // Bytes of binary output skipped since the last text line
+	pending string // Text line to return after the binary marker
+	err     error  // Read error, returned once the lines read before it are
+}
+
+// newLogLineReader reads lines of at most maxSize bytes, the default when 0
+func newLogLineReader(r io.Reader, maxSize int) *logLineReader {
+	if maxSize <= 0 {
+		maxSize = defaultMaxLineSize
+	}
+	return &logLineReader{r: bufio.NewReaderSize(r, 64<<10), maxSize: maxSize}
+}
+
+// Next returns the next line without its line ending, or io.EOF (or the read
+// error) once the output is exhausted
+func (l *logLineReader) Next() (string, error) {
+	if l.pending != "" {
+		line := l.pending
+		l.pending = ""
+		return line, nil
+	}
+
+	for l.err == nil {
+		line, dropped, err := l.readLine()
+		l.err = err
+		if len(line) == 0 && dropped == 0 {
+			if err == nil {
+				return "", nil // Empty line
+			}
+			break
+		}
+
+		if isBinaryOutput(line) {
+			l.binary += len(line) + dropped
+			continue
+		}
+
+		text := strings.ToValidUTF8(string(line), "�")
+		if dropped > 0 {
+			text += fmt.Sprintf(" … [truncated %d bytes]", dropped)
+		}
+		if l.binary > 0 {
+			l.pending = text
+			return l.binaryMarker(), nil
+		}
+		return text, nil
+	}
+
+	if l.binary > 0 {
+		return l.binaryMarker(), nil
+	}
+	return "", l.err
+}
+
+// binaryMarker returns the line standing for the binary output skipped
+func (l *logLineReader) binaryMarker() string {
+	marker := fmt.Sprintf("[binary output skipped: %d bytes]", l.binary)
+	l.binary = 0
+	return marker
+}
+
+// readLine reads one line, keeping at most maxSize bytes of it and counting
+// the rest as dropped
+func (l *logLineReader) readLine() ([]byte, int, error) {
+	var line []byte
+	dropped := 0
+	for {
+		chunk, err := l.r.ReadSlice('\n')
+		if err == nil {
+			chunk = bytes.TrimSuffix(bytes.TrimSuffix(chunk, []byte("\n")), []byte("\r"))
+		}
+		if room := l.maxSize - len(line); len(chunk) > room {
+			dropped += len(chunk) - room
+			chunk = chunk[:room]
+		}
+		line = append(line, chunk...)
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if dropped > 0 {
+			kept := trimPartialRune(line)
+			dropped += len(line) - len(kept)
+			line = kept
+		}
+		return line, dropped, err
+	}
+}
+
+// trimPartialRune drops a UTF-8 sequence cut off at the end of b
+func trimPartialRune(b []byte) []byte {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i]
+			}
+			break
+		}
+	}
+	return b
+}
+
+// isBinaryOutput reports whether a line of output is binary data rather than
+// text: it contains NUL bytes, or over a tenth of it is invalid UTF-8 or
+// control characters other than tabs and escape sequences
+func isBinaryOutput(b []byte) bool {
+	if bytes.IndexByte(b, 0) >= 0 {
+		return true
+	}
+
+	runes, bad := 0, 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		runes++
+		if (r == utf8.RuneError && size == 1) || r == 0x7f || (r < 0x20 && r != '\t' && r != '\r' && r != 0x1b) {
+			bad++
+		}
+	}
+	return bad*10 > runes
+}
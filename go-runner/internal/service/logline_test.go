@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// readLogLines returns the lines of output and the error ending them
+func readLogLines(r io.Reader, maxSize int) ([]string, error) {
+	reader := newLogLineReader(r, maxSize)
+	var lines []string
+	for {
+		line, err := reader.Next()
+		if err != nil {
+			return lines, err
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestLogLineReader(t *testing.T) {
+	binary := string([]byte{0x00, 0x01, 0x02, 0xff, 0xfe})
+
+	tests := []struct {
+		name    string
+		output  string
+		maxSize int
+		want    []string
+	}{
+		{"empty", "", 0, nil},
+		{"lines", "one\ntwo\n", 0, []string{"one", "two"}},
+		{"last line without newline", "one\ntwo", 0, []string{"one", "two"}},
+		{"crlf", "one\r\ntwo\r\n", 0, []string{"one", "two"}},
+		{"empty lines kept", "one\n\ntwo\n", 0, []string{"one", "", "two"}},
+		{"tabs and colors are text", "\tat main\n\x1b[31merror\x1b[0m\n", 0, []string{"\tat main", "\x1b[31merror\x1b[0m"}},
+		{"long line truncated", "abcdefghij\nok\n", 4, []string{"abcd … [truncated 6 bytes]", "ok"}},
+		{"line of max size kept", "abcd\n", 4, []string{"abcd"}},
+		{"truncated inside a rune", "abcé\n", 4, []string{"abc … [truncated 2 bytes]"}},
+		{"invalid utf-8 replaced", "caf\xe9 au lait ok\n", 0, []string{"caf� au lait ok"}},
+		{"binary skipped", "start\n" + binary + "\n" + binary + "\nend\n", 0, []string{"start", "[binary output skipped: 10 bytes]", "end"}},
+		{"binary at the end", "start\n" + binary, 0, []string{"start", "[binary output skipped: 5 bytes]"}},
+		{"truncated binary counted whole", binary + binary + "\n", 4, []string{"[binary output skipped: 10 bytes]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := readLogLines(strings.NewReader(tt.output), tt.maxSize)
+			if err != io.EOF {
+				t.Errorf("error = %v, want io.EOF", err)
+			}
+			if strings.Join(lines, "|") != strings.Join(tt.want, "|") || len(lines) != len(tt.want) {
+				t.Errorf("lines = %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogLineReaderLongLines(t *testing.T) {
+	// Longer than the 64 KiB buffer of the reader
+	long := strings.Repeat("x", 100<<10)
+
+	lines, err := readLogLines(strings.NewReader(long+"\nend\n"), 0)
+	if err != io.EOF || len(lines) != 2 || lines[0] != long || lines[1] != "end" {
+		t.Errorf("default max size: %d lines, error %v", len(lines), err)
+	}
+
+	lines, _ = readLogLines(strings.NewReader(long+"\nend\n"), 10)
+	want := []string{"xxxxxxxxxx … [truncated 102390 bytes]", "end"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
+
+func TestLogLineReaderError(t *testing.T) {
+	failure := errors.New("pipe closed")
+	r := io.MultiReader(strings.NewReader("one\ntwo"), iotest.ErrReader(failure))
+
+	lines, err := readLogLines(r, 0)
+	if err != failure {
+		t.Errorf("error = %v, want %v", err, failure)
+	}
+	if strings.Join(lines, "|") != "one|two" {
+		t.Errorf("lines = %q, want the lines read before the error", lines)
+	}
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// LogEvent is a live log line of a project
+type LogEvent struct {
+	ProjectID uint
+	Line      string
+	Time      time.Time
+}
+
+// logSubscriber receives live log lines of a set of projects
+type logSubscriber struct {
+	projects map[uint]bool
+	ch       chan LogEvent
+}
+
+// logSubscribers fans live log lines out to subscribers. Subscriptions are
+// keyed by project, not process, so they survive restarts.
+type logSubscribers struct {
+	mu   sync.RWMutex
+	subs map[*logSubscriber]struct{}
+}
+
+// SubscribeLogs returns a channel receiving live log lines of the given
+// projects, and a function that ends the subscription and closes the channel.
+// Lines are dropped, not queued, when the subscriber falls behind.
+func (m *Manager) SubscribeLogs(projectIDs []uint, buffer int) (<-chan LogEvent, func()) {
+	if buffer <= 0 {
+		buffer = 256
+	}
+
+	sub := &logSubscriber{
+		projects: make(map[uint]bool, len(projectIDs)),
+		ch:       make(chan LogEvent, buffer),
+	}
+	for _, id := range projectIDs {
+		sub.projects[id] = true
+	}
+
+	m.logSubs.mu.Lock()
+	if m.logSubs.subs == nil {
+		m.logSubs.subs = make(map[*logSubscriber]struct{})
+	}
+	m.logSubs.subs[sub] = struct{}{}
+	m.logSubs.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			m.logSubs.mu.Lock()
+			delete(m.logSubs.subs, sub)
+			m.logSubs.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// publishLog sends a captured log entry to subscribers of its project
+func (m *Manager) publishLog(projectID uint, entry LogEntry) {
+	m.logSubs.mu.RLock()
+	defer m.logSubs.mu.RUnlock()
+
+	if len(m.logSubs.subs) == 0 {
+		return
+	}
+
+	event := LogEvent{ProjectID: projectID, Line: entry.Line, Time: entry.Time}
+	for sub := range m.logSubs.subs {
+		if !sub.projects[projectID] {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
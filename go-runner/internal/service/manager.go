@@ -4,17 +4,21 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"go-runner/internal/maintenance"
+	"go-runner/internal/tables"
 	"go-runner/internal/types"
 
 	"gorm.io/gorm"
@@ -25,6 +29,104 @@ type Manager struct {
 	db       *gorm.DB
 	processes map[uint]*ProcessInfo
 	mu       sync.RWMutex
+
+	// In-flight lifecycle operations per project (start, stop, restart)
+	operations map[uint]*Operation
+	opMu       sync.Mutex
+
+	// Outcome of the last autostart run
+	lastAutostart *AutostartSummary
+	autostartMu   sync.RWMutex
+
+	// Live log subscribers (multiplexed tails)
+	logSubs logSubscribers
+
+	// Log lines by trace / request ID
+	traces traceIndex
+
+	// Last connectivity check of database projects
+	databases databaseHealth
+
+	// Last depth check of queue projects
+	queues queueHealth
+
+	// Public tunnels to project ports
+	tunnels tunnelRegistry
+
+	// Last recorded status per project (timeline)
+	history statusHistory
+
+	// Kubernetes options and last observed deployments
+	kube kubeState
+
+	// Last observed units of systemd projects
+	systemd systemdState
+
+	// SSH options and last observed remote processes
+	ssh sshState
+
+	// Last checks of the hosts of SSH projects
+	nodes nodeState
+
+	// File watchers of projects with watch_files
+	files fileWatchers
+
+	// Last open file counts of running projects
+	fds fdStats
+
+	// CPU time of running projects at their last sample
+	procStats procStats
+
+	// Identifies this run in the environment of services (GO_RUNNER_SESSION)
+	session string
+	// Last zombie and orphan scan
+	orphans orphanScans
+
+	// Processes re-attached after a restart
+	reconcile reconcileState
+
+	// Ports of blue/green instances and old instances draining
+	blueGreen blueGreenState
+
+	// Extra instances of projects scaled out (instances)
+	instances instanceState
+
+	// Latency injected for chaos testing
+	chaos chaosState
+
+	// Projects frozen with SIGSTOP
+	pauses pauseState
+
+	// Output files of service processes
+	logFiles logFiles
+
+	// Starts waiting for a slot (start_queue)
+	starts startQueue
+
+	// Starts waiting for their wait_for conditions
+	waits waitStates
+
+	// Starts not spawned yet, cancelled by a stop
+	pending pendingStarts
+
+	// Recent durations of lifecycle operations and database write counts
+	opTimings operationTimings
+	dbWrites  dbWriteCounter
+
+	// Low-power mode and the optional projects it suspended
+	power powerState
+
+	// Proxied traffic and on-demand starts of projects with idle_timeout
+	idle idleTracker
+
+	// Requests proxied to each project
+	traffic trafficCounter
+
+	// Stub servers of mock projects
+	mocks mockServers
+
+	// Runtime versions selected by nvm, asdf or go.mod at the last start
+	toolchains toolchainState
 }
 
 // ProcessInfo holds information about a running process
@@ -34,28 +136,117 @@ type ProcessInfo struct {
 	Context   context.Context
 	Cancel    context.CancelFunc
 	StartTime time.Time
-	Logs      chan string
-	LogBuffer []string // Buffer to store recent logs (last 1000 lines)
+	Logs      chan LogEntry
+	LogBuffer []LogEntry // Buffer to store recent logs (last 1000 lines)
 	logMu     sync.Mutex
 	closed    bool     // Track if channel is closed
 	closeMu   sync.Mutex
+	TraceID   string   // Trace injected on start (trace_injection)
+	LogFollower bool   // Follows logs from elsewhere (Kubernetes pods, journald), not a service process
+	Adopted     bool   // Re-attached after a server restart, not a child of this go-runner
+	stopOutput  context.CancelFunc // Ends the tails of the output files once the process exited
+	tailFiles   *tailFiles         // Log files of the service tailed with its output (tail_files)
+	logCaptured atomic.Uint64      // Lines read from the process
+	logDropped  atomic.Uint64      // Lines not sent because Logs was full
 }
 
 // NewManager creates a new service manager
 func NewManager(db *gorm.DB) *Manager {
 	return &Manager{
-		db:        db,
-		processes: make(map[uint]*ProcessInfo),
+		db:         db,
+		processes:  make(map[uint]*ProcessInfo),
+		operations: make(map[uint]*Operation),
+		session:    newSessionID(),
 	}
 }
 
 // StartService starts a microservice
 func (m *Manager) StartService(projectID uint) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	if err := m.beginOperation(projectID, OperationStart); err != nil {
+		return err
+	}
+	defer m.endOperation(projectID)
+
+	if err := m.startService(projectID); err != nil {
+		return err
+	}
+
+	// A manual start gives auto_restart a fresh budget
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("restart_count", 0)
+	return nil
+}
+
+// startService starts a microservice without operation tracking
+func (m *Manager) startService(projectID uint) error {
+	// Kubernetes projects scale their deployment instead
+	if target := m.kubeTarget(projectID); target != nil {
+		return m.startKube(projectID, *target)
+	}
+	// systemd projects start their unit
+	if unit := m.systemdTarget(projectID); unit != nil {
+		return m.systemdAction(projectID, *unit, "start")
+	}
+	// Remote projects start over SSH
+	if target := m.sshTarget(projectID); target != nil {
+		return m.sshAction(projectID, target, "start")
+	}
+	// Mock projects are served by go-runner itself
+	if m.isMockProject(projectID) {
+		return m.startMock(projectID)
+	}
+
+	m.mu.RLock()
+	_, exists := m.processes[projectID]
+	m.mu.RUnlock()
+	if exists {
+		return fmt.Errorf("service %d is already running", projectID)
+	}
+
+	// A stop cancels the start while it waits
+	start, done := m.trackStart(projectID)
+	defer done()
+
+	// Wait for the databases and services it needs (wait_for), before
+	// taking a start slot
+	if err := m.waitForConditions(start, projectID); err != nil {
+		if errors.Is(err, ErrStartCancelled) {
+			return err
+		}
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":     string(types.StatusError),
+			"last_error": err.Error(),
+		})
+		m.RecordStatus(projectID, string(types.StatusError), err.Error())
+		return err
+	}
+
+	// Wait for a slot when starts are limited (start_queue)
+	release, err := m.acquireStartSlot(start, projectID)
+	if err != nil {
+		if !errors.Is(err, ErrStartCancelled) {
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+				"status":     string(types.StatusError),
+				"last_error": err.Error(),
+			})
+			m.RecordStatus(projectID, string(types.StatusError), err.Error())
+		}
+		return err
+	}
+	if err := m.startProcess(projectID); err != nil {
+		release()
+		return err
+	}
+	go m.holdStartSlot(projectID, release)
+	return nil
+}
 
+// startProcess spawns the process of a microservice
+func (m *Manager) startProcess(projectID uint) error {
 	// Check if service is already running
-	if _, exists := m.processes[projectID]; exists {
+	m.mu.RLock()
+	_, exists := m.processes[projectID]
+	m.mu.RUnlock()
+	if exists {
 		return fmt.Errorf("service %d is already running", projectID)
 	}
 
@@ -65,6 +256,7 @@ func (m *Manager) StartService(projectID uint) error {
 		Name        string
 		Description string
 		Type        string
+		GroupID     *uint
 		Path        string
 		Command     string
 		Args        string
@@ -78,14 +270,30 @@ func (m *Manager) StartService(projectID uint) error {
 		StartTime   *time.Time
 		StopTime    *time.Time
 		LastError   string
+		TraceInjection bool
+		InspectPort int
+		Nice        int
+		IOClass     string
+		IOPriority  int
+		CPUAffinity string
 	}
 
-	if err := m.db.Table("projects").Where("id = ?", projectID).First(&p).Error; err != nil {
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).First(&p).Error; err != nil {
 		return fmt.Errorf("project not found: %v", err)
 	}
 
+	// A blue/green restart runs the new instance on another port
+	bluePort := m.takeBlueGreenPort(projectID)
+	if bluePort > 0 {
+		p.Port = bluePort
+	}
+
+	// Resolve {{port}}, {{project.name}}, {{group.name}}, {{node.ip}}, ... placeholders
+	tmpl := m.buildTemplateContext(p.ID, p.Name, p.Path, p.Environment, p.Port, p.GroupID)
+
 	// Update status to starting
-	m.db.Table("projects").Where("id = ?", projectID).Update("status", string(types.StatusStarting))
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(types.StatusStarting))
+	m.RecordStatus(projectID, string(types.StatusStarting), "Start requested")
 
 	// Create context for the process
 	ctx, cancel := context.WithCancel(context.Background())
@@ -96,8 +304,8 @@ func (m *Manager) StartService(projectID uint) error {
 		Args    string
 		Type    string
 	}{
-		Command: p.Command,
-		Args:    p.Args,
+		Command: tmpl.Expand(p.Command),
+		Args:    tmpl.Expand(p.Args),
 		Type:    p.Type,
 	})
 
@@ -115,25 +323,92 @@ func (m *Manager) StartService(projectID uint) error {
 		EnvFile     string
 		EnvVars     string
 		Path        string
+		Template    *TemplateContext
 	}{
 		Port:        p.Port,
 		Environment: p.Environment,
 		EnvFile:     p.EnvFile,
 		EnvVars:     p.EnvVars,
 		Path:        p.Path,
+		Template:    tmpl,
 	})
+	if bluePort > 0 {
+		cmd.Env = m.removeEnvVar(cmd.Env, "PORT")
+		cmd.Env = append(cmd.Env, fmt.Sprintf("PORT=%d", bluePort))
+	}
+
+	// Inject a fresh trace context so the log lines of this run can be correlated
+	var traceID string
+	if p.TraceInjection {
+		if tc, err := newTraceContext(); err == nil {
+			for k, v := range tc.Env() {
+				cmd.Env = m.removeEnvVar(cmd.Env, k)
+				cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+			}
+			traceID = tc.TraceID
+		}
+	}
+
+	// Mark the process tree so that leftovers can be found after a stop
+	cmd.Env = m.sessionEnv(cmd.Env, projectID)
+
+	// Node.js inspector while debugging (POST /projects/:id/inspect)
+	if p.InspectPort > 0 {
+		m.applyInspector(cmd, p.InspectPort)
+	}
+
+	// Run with the node, go, ... versions the project asks for. The version
+	// lookups run commands, so they happen before m.mu is taken.
+	toolchains := m.applyToolchains(projectID, cmd)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.processes[projectID]; exists {
+		cancel()
+		return fmt.Errorf("service %d is already running", projectID)
+	}
 
 	// Create logs channel with larger buffer to avoid dropping logs
-	logs := make(chan string, 1000)
+	logs := make(chan LogEntry, 1000)
 
-	// Capture stdout and stderr
-	stdout, err := cmd.StdoutPipe()
+	// Capture stdout and stderr in files, tailed for the log buffer, so that
+	// output is neither lost nor blocking when go-runner restarts
+	stdoutFile, stderrFile, err := m.openLogFiles(projectID)
 	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %v", err)
+		cancel()
+		return fmt.Errorf("failed to open log files: %v", err)
 	}
-	stderr, err := cmd.StderrPipe()
+	// The process gets its own descriptors on start
+	defer stdoutFile.Close()
+	defer stderrFile.Close()
+	cmd.Stdout = stdoutFile
+	cmd.Stderr = stderrFile
+
+	outputCtx, stopOutput := context.WithCancel(context.Background())
+	stdout, err := openFileTail(outputCtx, stdoutFile.Name())
 	if err != nil {
-		return fmt.Errorf("failed to create stderr pipe: %v", err)
+		cancel()
+		stopOutput()
+		return fmt.Errorf("failed to read log file: %v", err)
+	}
+	stderr, err := openFileTail(outputCtx, stderrFile.Name())
+	if err != nil {
+		cancel()
+		stopOutput()
+		stdout.Close()
+		return fmt.Errorf("failed to read log file: %v", err)
+	}
+	// The startup banner marks where each run begins in the output
+	fmt.Fprintf(stdoutFile, "[go-runner] %s\n", startupBanner(p.Name, cmd, p.InspectPort))
+	// And references to other projects that could not be resolved
+	for _, warning := range tmpl.Warnings {
+		fmt.Fprintf(stderrFile, "[go-runner] %s\n", warning)
+	}
+	// Toolchain warnings show up in the service output
+	for _, t := range toolchains {
+		if t.Warning != "" {
+			fmt.Fprintf(stderrFile, "[go-runner] %s\n", t.Warning)
+		}
 	}
 
 	// Store process info
@@ -144,7 +419,9 @@ func (m *Manager) StartService(projectID uint) error {
 		Cancel:    cancel,
 		StartTime: time.Now(),
 		Logs:      logs,
-		LogBuffer: make([]string, 0, 1000), // Buffer for last 1000 lines
+		LogBuffer: make([]LogEntry, 0, 1000), // Buffer for last 1000 lines
+		TraceID:   traceID,
+		stopOutput: stopOutput,
 	}
 	m.processes[projectID] = processInfo
 
@@ -152,11 +429,15 @@ func (m *Manager) StartService(projectID uint) error {
 	if err := cmd.Start(); err != nil {
 		delete(m.processes, projectID)
 		cancel()
+		stopOutput()
+		stdout.Close()
+		stderr.Close()
 		close(logs)
-		m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 			"status":      string(types.StatusError),
 			"last_error":  err.Error(),
 		})
+		m.RecordStatus(projectID, string(types.StatusError), "Failed to start: "+err.Error())
 		return fmt.Errorf("failed to start service: %v", err)
 	}
 
@@ -165,43 +446,112 @@ func (m *Manager) StartService(projectID uint) error {
 	if pid <= 0 {
 		delete(m.processes, projectID)
 		cancel()
+		stopOutput()
+		stdout.Close()
+		stderr.Close()
 		close(logs)
 		errorMsg := "Process started but PID is invalid."
-		m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 			"status":      string(types.StatusError),
 			"last_error":  errorMsg,
 			"p_id":        0,
 		})
+		m.RecordStatus(projectID, string(types.StatusError), errorMsg)
 		return fmt.Errorf("process started but PID is invalid")
 	}
 
+	// Lower (or raise) the CPU and IO priority and pin the CPUs right away,
+	// so the processes it starts inherit them, and again for those started
+	// meanwhile
+	if prio := (Priority{Nice: p.Nice, IOClass: p.IOClass, IOPriority: p.IOPriority, CPUAffinity: p.CPUAffinity}); !prio.IsDefault() {
+		if err := setPriority([]int32{int32(pid)}, prio, false); err != nil {
+			fmt.Fprintf(stderrFile, "[go-runner] Failed to set the priority: %v\n", err)
+		}
+		go m.recheckPriority(projectID, int32(pid))
+	}
+
 	// Start goroutines to capture stdout and stderr immediately
 	// This allows us to see any errors that occur during startup
 	// Use captureOutputWithBuffer to also store logs in buffer
 	go m.captureOutputWithBuffer(stdout, processInfo, false)
 	go m.captureOutputWithBuffer(stderr, processInfo, true)
+	// And the log files it writes itself, merged with its output
+	m.startTailFiles(outputCtx, processInfo)
 
 	// Start monitoring goroutine immediately
 	// This will detect when process exits and update status accordingly
 	// If process exits quickly, monitorProcess will catch it
 	go m.monitorProcess(processInfo)
 
+	// Remember what was started, to re-attach after a server restart
+	go m.recordProcessIdentity(processInfo)
+
 	// Update project with PID and start time
 	// We assume process started successfully if we got a valid PID
 	// monitorProcess will update status to "error" or "stopped" if process exits
 	now := time.Now()
-	m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
-		"status":      string(types.StatusRunning),
-		"p_id":        pid,
-		"start_time":  &now,
-		"last_error":  "",
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+		"status":        string(types.StatusRunning),
+		"p_id":          pid,
+		"start_time":    &now,
+		"last_error":    "",
+		"process_start": nil,
+		"command_hash":  "",
 	})
+	m.RecordStatus(projectID, string(types.StatusRunning), fmt.Sprintf("Process started (PID %d)", pid))
+
+	// And the other instances of a project scaled out
+	go m.ScaleInstances(projectID)
 
 	return nil
 }
 
 // StopService stops a microservice
 func (m *Manager) StopService(projectID uint) error {
+	// A start still waiting for its conditions or a slot is cancelled rather than
+	// making the stop fail as a conflict
+	if m.cancelPendingStart(projectID) {
+		m.waitOperationDone(projectID, pendingStartCancelWait)
+	}
+
+	if err := m.beginOperation(projectID, OperationStop); err != nil {
+		return err
+	}
+	defer m.endOperation(projectID)
+
+	// Tunnels live alongside the service
+	m.StopTunnel(projectID)
+	// And the old instances left by blue/green restarts
+	m.stopDrainingInstances(projectID)
+	// And debugging ends with it, so the next start is a normal one
+	m.clearInspector(projectID)
+
+	return m.stopService(projectID)
+}
+
+// stopService stops a microservice without operation tracking
+func (m *Manager) stopService(projectID uint) error {
+	if target := m.kubeTarget(projectID); target != nil {
+		return m.stopKube(projectID, *target)
+	}
+	if unit := m.systemdTarget(projectID); unit != nil {
+		return m.systemdAction(projectID, *unit, "stop")
+	}
+	if target := m.sshTarget(projectID); target != nil {
+		return m.sshAction(projectID, target, "stop")
+	}
+	if m.isMockProject(projectID) {
+		return m.stopMock(projectID)
+	}
+
+	// The extra instances stop with the main process
+	m.stopInstances(projectID)
+
+	// A project suspended by low-power mode or paused would not handle the
+	// stop signals
+	m.resumePausedProject(projectID)
+	m.ResumeService(projectID)
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -211,7 +561,7 @@ func (m *Manager) StopService(projectID uint) error {
 		PID    int `gorm:"column:p_id"`
 		Status string
 	}
-	if err := m.db.Table("projects").Where("id = ?", projectID).First(&p).Error; err != nil {
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).First(&p).Error; err != nil {
 		return fmt.Errorf("project not found: %v", err)
 	}
 
@@ -221,7 +571,7 @@ func (m *Manager) StopService(projectID uint) error {
 		proc, err := os.FindProcess(p.PID)
 		if err == nil {
 			// Try to signal the process (signal 0 doesn't kill, just checks existence)
-			err = proc.Signal(os.Signal(nil))
+			err = proc.Signal(syscall.Signal(0))
 			if err == nil {
 				processRunning = true
 			}
@@ -235,19 +585,27 @@ func (m *Manager) StopService(projectID uint) error {
 	if !processRunning && !exists {
 		// Process is already stopped, just update DB
 		now := time.Now()
-		m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 			"status":     string(types.StatusStopped),
 			"stop_time":  &now,
 			"p_id":       0,
 		})
+		m.RecordStatus(projectID, string(types.StatusStopped), "Process not running")
 		return nil // Not an error, process is already stopped
 	}
 
 	// Update status to stopping
-	m.db.Table("projects").Where("id = ?", projectID).Update("status", string(types.StatusStopping))
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(types.StatusStopping))
+	m.RecordStatus(projectID, string(types.StatusStopping), "Stop requested")
 
 	// If we have process info in memory, stop it properly
-	if exists {
+	if exists && processInfo.Adopted {
+		// Not a child of this go-runner: signal it, monitorAdopted sees it exit
+		processInfo.Cancel()
+		terminateProcess(int32(processInfo.Process.Process.Pid))
+		processInfo.safeCloseChannel()
+		delete(m.processes, projectID)
+	} else if exists {
 		// Cancel context to stop the process
 		processInfo.Cancel()
 
@@ -289,7 +647,7 @@ func (m *Manager) StopService(projectID uint) error {
 			// Wait a bit for process to die
 			time.Sleep(1 * time.Second)
 			// Try again if still alive
-			if err := proc.Signal(os.Signal(nil)); err == nil {
+			if err := proc.Signal(syscall.Signal(0)); err == nil {
 				proc.Kill()
 				time.Sleep(500 * time.Millisecond)
 			}
@@ -298,17 +656,35 @@ func (m *Manager) StopService(projectID uint) error {
 
 	// Update project status
 	now := time.Now()
-	m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 		"status":     string(types.StatusStopped),
 		"stop_time":  &now,
 		"p_id":       0,
 	})
+	m.RecordStatus(projectID, string(types.StatusStopped), "Stopped")
+	m.settleReconcile(ReconciledProcess{ProjectID: projectID, PID: p.PID, Result: ReconcileStopped, Reason: "Stopped", Timestamp: now})
 
 	return nil
 }
 
 // ForceKillService forcefully kills a service process
 func (m *Manager) ForceKillService(projectID uint) error {
+	m.StopTunnel(projectID)
+	m.stopDrainingInstances(projectID)
+	m.stopInstances(projectID)
+
+	// There is no process to kill, scaling to zero is the hardest stop
+	if target := m.kubeTarget(projectID); target != nil {
+		return m.stopKube(projectID, *target)
+	}
+	// systemd kills every process of the unit
+	if unit := m.systemdTarget(projectID); unit != nil {
+		return m.systemdAction(projectID, *unit, "kill")
+	}
+	if target := m.sshTarget(projectID); target != nil {
+		return m.sshAction(projectID, target, "kill")
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -317,7 +693,7 @@ func (m *Manager) ForceKillService(projectID uint) error {
 		ID  uint
 		PID int `gorm:"column:p_id"`
 	}
-	if err := m.db.Table("projects").Where("id = ?", projectID).First(&p).Error; err != nil {
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).First(&p).Error; err != nil {
 		return fmt.Errorf("project not found: %v", err)
 	}
 
@@ -329,7 +705,7 @@ func (m *Manager) ForceKillService(projectID uint) error {
 			proc.Kill()
 			time.Sleep(500 * time.Millisecond)
 			// Check if still alive and force kill
-			if err := proc.Signal(os.Signal(nil)); err == nil {
+			if err := proc.Signal(syscall.Signal(0)); err == nil {
 				proc.Kill()
 				time.Sleep(500 * time.Millisecond)
 			}
@@ -345,21 +721,42 @@ func (m *Manager) ForceKillService(projectID uint) error {
 
 	// Update database
 	now := time.Now()
-	m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 		"status":     string(types.StatusStopped),
 		"stop_time":  &now,
 		"p_id":       0,
 		"last_error": "Force killed",
 	})
+	m.RecordStatus(projectID, string(types.StatusStopped), "Force killed")
+	m.settleReconcile(ReconciledProcess{ProjectID: projectID, PID: p.PID, Result: ReconcileStopped, Reason: "Force killed", Timestamp: now})
 
 	return nil
 }
 
 // RestartService restarts a microservice
 func (m *Manager) RestartService(projectID uint) error {
+	if err := m.beginOperation(projectID, OperationRestart); err != nil {
+		return err
+	}
+	defer m.endOperation(projectID)
+
+	if unit := m.systemdTarget(projectID); unit != nil {
+		return m.systemdAction(projectID, *unit, "restart")
+	}
+	if target := m.sshTarget(projectID); target != nil {
+		return m.sshAction(projectID, target, "restart")
+	}
+	// blue_green projects start the new instance before stopping the old one
+	if m.restartStrategy(projectID) == RestartBlueGreen {
+		return m.blueGreenRestart(projectID)
+	}
+
 	// Stop if running
-	if _, exists := m.processes[projectID]; exists {
-		if err := m.StopService(projectID); err != nil {
+	m.mu.RLock()
+	_, exists := m.processes[projectID]
+	m.mu.RUnlock()
+	if exists {
+		if err := m.stopService(projectID); err != nil {
 			return fmt.Errorf("failed to stop service: %v", err)
 		}
 		// Wait a bit before restarting
@@ -367,7 +764,7 @@ func (m *Manager) RestartService(projectID uint) error {
 	}
 
 	// Start the service
-	return m.StartService(projectID)
+	return m.startService(projectID)
 }
 
 // GetServiceStatus returns the current status of a service
@@ -390,6 +787,7 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 		WorkingDir  string         `gorm:"column:working_dir"`
 		Port        int            `gorm:"column:port"`
 		Ports       string         `gorm:"column:ports"`
+		SocketPath  string         `gorm:"column:socket_path"`
 		Environment string         `gorm:"column:environment"`
 		EnvFile     string         `gorm:"column:env_file"`
 		EnvVars     string         `gorm:"column:env_vars"`
@@ -403,16 +801,64 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 		HealthCheckURL string       `gorm:"column:health_check_url"`
 		HealthStatus   string       `gorm:"column:health_status"`
 		AutoRestart   bool         `gorm:"column:auto_restart"`
+		Autostart     bool         `gorm:"column:autostart"`
+		DependsOn     string       `gorm:"column:depends_on"`
+		TraceInjection bool        `gorm:"column:trace_injection"`
+		WatchFiles    bool         `gorm:"column:watch_files"`
+		Optional      bool         `gorm:"column:optional"`
+		ConnectionString string    `gorm:"column:connection_string"`
+		MigrationCommand string    `gorm:"column:migration_command"`
+		TestCommand   string       `gorm:"column:test_command"`
+		Queues        string       `gorm:"column:queues"`
+		TailFiles     string       `gorm:"column:tail_files"`
+		QueueBacklogLimit int64    `gorm:"column:queue_backlog_limit"`
+		QueueGrowthLimit  int64    `gorm:"column:queue_growth_limit"`
+		IdleTimeout   int          `gorm:"column:idle_timeout"`
+		MockSpec      string       `gorm:"column:mock_spec"`
+		MDNSAnnounce  bool         `gorm:"column:mdns_announce"`
+		MDNSName      string       `gorm:"column:mdns_name"`
+		StatusPage    bool         `gorm:"column:status_page"`
+		StatusPageName string      `gorm:"column:status_page_name"`
+		KubeContext   string       `gorm:"column:kube_context"`
+		KubeNamespace string       `gorm:"column:kube_namespace"`
+		KubeDeployment string      `gorm:"column:kube_deployment"`
+		KubeReplicas  int          `gorm:"column:kube_replicas"`
+		SystemdUnit   string       `gorm:"column:systemd_unit"`
+		SystemdUser   bool         `gorm:"column:systemd_user"`
+		SSHHost       string       `gorm:"column:ssh_host"`
 		MaxRestarts   int          `gorm:"column:max_restarts"`
+		RestartStrategy string     `gorm:"column:restart_strategy"`
+		DrainTimeout  int          `gorm:"column:drain_timeout"`
+		Instances     int          `gorm:"column:instances"`
+		InstancePorts string       `gorm:"column:instance_ports"`
 		CPULimit      string       `gorm:"column:cpu_limit"`
 		MemoryLimit   string       `gorm:"column:memory_limit"`
+		Nice          int          `gorm:"column:nice"`
+		IOClass       string       `gorm:"column:io_class"`
+		IOPriority    int          `gorm:"column:io_priority"`
+		CPUAffinity   string       `gorm:"column:cpu_affinity"`
+		MemoryGuardMB      int     `gorm:"column:memory_guard_mb"`
+		MemoryGuardSamples int     `gorm:"column:memory_guard_samples"`
+		MemoryGuardRestart bool    `gorm:"column:memory_guard_restart"`
+		SmokeTests          string `gorm:"column:smoke_tests"`
+		SmokeTestsOnStart   bool   `gorm:"column:smoke_tests_on_start"`
+		SmokeTestsFailError bool   `gorm:"column:smoke_tests_fail_error"`
+		WaitFor         string     `gorm:"column:wait_for"`
+		WaitForTimeout  int        `gorm:"column:wait_for_timeout"`
+		WaitForInterval int        `gorm:"column:wait_for_interval"`
+		Owner         string       `gorm:"column:owner"`
+		Team          string       `gorm:"column:team"`
+		RepositoryURL string       `gorm:"column:repository_url"`
+		DocsURL       string       `gorm:"column:docs_url"`
+		ChatChannel   string       `gorm:"column:chat_channel"`
 		GroupID       *uint        `gorm:"column:group_id"`
 		CreatedAt     time.Time    `gorm:"column:created_at"`
 		UpdatedAt     time.Time    `gorm:"column:updated_at"`
+		Version       uint         `gorm:"column:version"`
 		Logs          string       `gorm:"column:logs"`
 	}
 	
-	if err := m.db.Table("projects").Where("id = ?", projectID).First(&p).Error; err != nil {
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).First(&p).Error; err != nil {
 		m.mu.RUnlock()
 		return nil, err
 	}
@@ -429,6 +875,7 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 		"working_dir":   p.WorkingDir,
 		"port":          p.Port,
 		"ports":         p.Ports,
+		"socket_path":   p.SocketPath,
 		"environment":   p.Environment,
 		"env_file":      p.EnvFile,
 		"env_vars":      p.EnvVars,
@@ -442,13 +889,163 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 		"health_check_url": p.HealthCheckURL,
 		"health_status":    p.HealthStatus,
 		"auto_restart":     p.AutoRestart,
+		"autostart":        p.Autostart,
+		"depends_on":       p.DependsOn,
+		"trace_injection":  p.TraceInjection,
+		"watch_files":      p.WatchFiles,
+		"optional":         p.Optional,
+		"connection_string": p.ConnectionString,
+		"migration_command": p.MigrationCommand,
+		"test_command":     p.TestCommand,
+		"queues":           p.Queues,
+		"tail_files":       p.TailFiles,
+		"queue_backlog_limit": p.QueueBacklogLimit,
+		"queue_growth_limit":  p.QueueGrowthLimit,
+		"idle_timeout":        p.IdleTimeout,
+		"mock_spec":           p.MockSpec,
+		"mdns":             p.MDNSAnnounce,
+		"mdns_name":        p.MDNSName,
+		"status_page":      p.StatusPage,
+		"status_page_name": p.StatusPageName,
+		"kube_context":     p.KubeContext,
+		"kube_namespace":   p.KubeNamespace,
+		"kube_deployment":  p.KubeDeployment,
+		"kube_replicas":    p.KubeReplicas,
+		"systemd_unit":     p.SystemdUnit,
+		"systemd_user":     p.SystemdUser,
+		"ssh_host":         p.SSHHost,
 		"max_restarts":     p.MaxRestarts,
+		"restart_strategy": p.RestartStrategy,
+		"drain_timeout":    p.DrainTimeout,
+		"instances":        p.Instances,
+		"instance_ports":   p.InstancePorts,
 		"cpu_limit":        p.CPULimit,
 		"memory_limit":     p.MemoryLimit,
+		"nice":             p.Nice,
+		"io_class":         p.IOClass,
+		"io_priority":      p.IOPriority,
+		"cpu_affinity":     p.CPUAffinity,
+		"memory_guard_mb":      p.MemoryGuardMB,
+		"memory_guard_samples": p.MemoryGuardSamples,
+		"memory_guard_restart": p.MemoryGuardRestart,
+		"smoke_tests":            p.SmokeTests,
+		"smoke_tests_on_start":   p.SmokeTestsOnStart,
+		"smoke_tests_fail_error": p.SmokeTestsFailError,
+		"wait_for":             p.WaitFor,
+		"wait_for_timeout":     p.WaitForTimeout,
+		"wait_for_interval":    p.WaitForInterval,
+		"owner":            p.Owner,
+		"team":             p.Team,
+		"repository_url":   p.RepositoryURL,
+		"docs_url":         p.DocsURL,
+		"chat_channel":     p.ChatChannel,
 		"group_id":         p.GroupID,
 		"created_at":       p.CreatedAt,
 		"updated_at":       p.UpdatedAt,
+		"version":          p.Version,
 		"logs":             p.Logs,
+		"operation":        m.GetCurrentOperation(projectID),
+	}
+	if info, ok := m.processes[projectID]; ok && info.TraceID != "" {
+		result["trace_id"] = info.TraceID
+	}
+	if last := m.LastProxiedRequest(projectID); last != nil {
+		result["last_request_at"] = last
+	}
+	if m.IsPowerPaused(projectID) {
+		result["power_paused"] = true
+	}
+	if io := m.LatestDiskIO(projectID); io != nil {
+		result["disk_io"] = io
+	}
+	if network := m.LatestNetwork(projectID); network != nil {
+		result["network"] = network
+	}
+	if pause := m.Pause(projectID); pause != nil {
+		result["pause"] = pause
+	}
+	if toolchains := m.ResolvedToolchains(projectID); toolchains != nil {
+		result["toolchains"] = toolchains
+	}
+	queued := m.StartQueuePosition(projectID)
+	if queued != nil {
+		result["start_queue"] = queued
+	}
+	waiting := m.WaitForStatus(projectID)
+	if waiting != nil {
+		result["wait_status"] = waiting
+	}
+	if stats := m.DatabaseStatus(projectID); stats != nil {
+		result["database"] = stats
+	}
+	if stats := m.QueueStatus(projectID); stats != nil {
+		result["queue_stats"] = stats
+	}
+	if tunnel := m.GetTunnel(projectID); tunnel != nil {
+		result["tunnel"] = tunnel
+	}
+	if inspector := m.InspectorStatus(projectID); inspector != nil {
+		result["inspector"] = inspector
+	}
+	if window := maintenance.Open(m.db, projectID); window != nil {
+		result["maintenance"] = window
+	}
+	if change := m.LastFileChange(projectID); change != nil {
+		result["last_file_change"] = change
+	}
+	if stats := m.FileDescriptorStatus(projectID); stats != nil {
+		result["file_descriptors"] = stats
+	}
+	if stdout, stderr := m.LogFilePaths(projectID); p.KubeDeployment == "" && p.SSHHost == "" && p.Type != string(types.TypeSystemd) && p.Type != string(types.TypeMock) {
+		if _, err := os.Stat(stdout); err == nil {
+			result["log_files"] = map[string]string{"stdout": stdout, "stderr": stderr}
+		}
+	}
+
+	// Kubernetes projects are synced from pod readiness, not from a local process
+	if p.KubeDeployment != "" {
+		if deployment := m.KubeStatus(projectID); deployment != nil {
+			result["kubernetes"] = deployment
+		}
+		m.mu.RUnlock()
+		return result, nil
+	}
+	// And systemd projects from their unit
+	if p.Type == string(types.TypeSystemd) {
+		if unit := m.SystemdStatus(projectID); unit != nil {
+			result["systemd"] = unit
+		}
+		m.mu.RUnlock()
+		return result, nil
+	}
+	// And remote projects from their process on the SSH host
+	if p.SSHHost != "" {
+		if remote := m.SSHStatus(projectID); remote != nil {
+			result["remote"] = remote
+		}
+		if node := m.NodeStatus(p.SSHHost); node != nil {
+			result["node"] = node
+		}
+		m.mu.RUnlock()
+		return result, nil
+	}
+	// And mock projects from their stub server
+	if p.Type == string(types.TypeMock) {
+		m.mu.RUnlock()
+		if mock := m.MockStatus(projectID); mock != nil {
+			result["mock"] = mock
+		} else if p.Status == string(types.StatusRunning) {
+			// Not served since go-runner restarted
+			now := time.Now()
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+				"status":    string(types.StatusStopped),
+				"stop_time": &now,
+			})
+			m.RecordStatus(projectID, string(types.StatusStopped), "Mock server not running")
+			result["status"] = string(types.StatusStopped)
+			result["stop_time"] = &now
+		}
+		return result, nil
 	}
 
 	// Use IsServiceRunning to check actual status (checks port, PID, child processes)
@@ -473,18 +1070,21 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 			var project struct {
 				Port int
 			}
-			if err := m.db.Table("projects").Where("id = ?", projectID).Select("port").First(&project).Error; err == nil && project.Port > 0 {
+			if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Select("port").First(&project).Error; err == nil && project.Port > 0 {
 				if pidFromPort, err := m.getPIDByPort(project.Port); err == nil {
 					actualPID = pidFromPort
 				}
 			}
 		}
 		
-		m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
-			"status":     string(types.StatusRunning),
-			"p_id":       actualPID,
-			"start_time": &now,
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":        string(types.StatusRunning),
+			"p_id":          actualPID,
+			"start_time":    &now,
+			"process_start": nil, // Not started by go-runner, cannot be verified after a restart
+			"command_hash":  "",
 		})
+		m.RecordStatus(projectID, string(types.StatusRunning), "Detected running")
 		result["status"] = string(types.StatusRunning)
 		result["p_id"] = actualPID
 		result["start_time"] = &now
@@ -497,11 +1097,12 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 			if processInfo.Process.ProcessState != nil && processInfo.Process.ProcessState.Exited() {
 				// Process has exited, update status
 				now := time.Now()
-				m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+				m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 					"status":     string(types.StatusStopped),
 					"stop_time":  &now,
 					"p_id":       0,
 				})
+				m.RecordStatus(projectID, string(types.StatusStopped), "Process exited")
 				processInfo.safeCloseChannel()
 				delete(m.processes, projectID)
 				result["status"] = string(types.StatusStopped)
@@ -510,15 +1111,16 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 			} else {
 				// Check if process is really dead
 				if processInfo.Process != nil && processInfo.Process.Process != nil {
-					err := processInfo.Process.Process.Signal(os.Signal(nil))
+					err := processInfo.Process.Process.Signal(syscall.Signal(0))
 					if err != nil {
 						// Process is dead
 						now := time.Now()
-						m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+						m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 							"status":     string(types.StatusStopped),
 							"stop_time":  &now,
 							"p_id":       0,
 						})
+						m.RecordStatus(projectID, string(types.StatusStopped), "Process no longer running")
 						processInfo.safeCloseChannel()
 						delete(m.processes, projectID)
 						result["status"] = string(types.StatusStopped)
@@ -528,15 +1130,16 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 				}
 			}
 			m.mu.Unlock()
-		} else if currentStatus == string(types.StatusRunning) || currentStatus == string(types.StatusStarting) {
+		} else if (currentStatus == string(types.StatusRunning) || currentStatus == string(types.StatusStarting)) && queued == nil && waiting == nil {
 			// Process not in memory and not running, but status says it is
 			m.mu.Lock()
 			now := time.Now()
-			m.db.Table("projects").Where("id = ?", projectID).Updates(map[string]interface{}{
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
 				"status":     string(types.StatusStopped),
 				"stop_time":  &now,
 				"p_id":       0,
 			})
+			m.RecordStatus(projectID, string(types.StatusStopped), "Process no longer running")
 			result["status"] = string(types.StatusStopped)
 			result["p_id"] = 0
 			result["stop_time"] = &now
@@ -547,24 +1150,9 @@ func (m *Manager) GetServiceStatus(projectID uint) (map[string]interface{}, erro
 	return result, nil
 }
 
-// GetRunningServices returns all currently running services
-func (m *Manager) GetRunningServices() []map[string]interface{} {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	var services []map[string]interface{}
-	for _, processInfo := range m.processes {
-		var p map[string]interface{}
-		if err := m.db.Table("projects").Where("id = ?", processInfo.ProjectID).First(&p).Error; err == nil {
-			services = append(services, p)
-		}
-	}
-	return services
-}
-
 // GetServiceLogs returns logs for a service
 // It returns the logs channel if service is running, or nil if not
-func (m *Manager) GetServiceLogs(projectID uint) <-chan string {
+func (m *Manager) GetServiceLogs(projectID uint) <-chan LogEntry {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -572,7 +1160,7 @@ func (m *Manager) GetServiceLogs(projectID uint) <-chan string {
 		// Double-check that process is actually running
 		if processInfo.Process != nil && processInfo.Process.Process != nil {
 			// Check if process is still alive
-			err := processInfo.Process.Process.Signal(os.Signal(nil))
+			err := processInfo.Process.Process.Signal(syscall.Signal(0))
 			if err == nil {
 				return processInfo.Logs
 			}
@@ -589,11 +1177,24 @@ func (m *Manager) IsServiceRunning(projectID uint) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	// Kubernetes projects run while a pod is ready
+	if deployment := m.KubeStatus(projectID); deployment != nil {
+		return deployment.ReadyReplicas > 0
+	}
+	// systemd projects while their unit is active
+	if unit := m.SystemdStatus(projectID); unit != nil {
+		return unit.ActiveState == "active" || unit.ActiveState == "reloading"
+	}
+	// Remote projects while their process runs on the SSH host
+	if remote := m.SSHStatus(projectID); remote != nil {
+		return remote.Status == string(types.StatusRunning)
+	}
+
 	// Check if in memory
 	if processInfo, exists := m.processes[projectID]; exists {
 		if processInfo.Process != nil && processInfo.Process.Process != nil {
 			// Check if process is still alive
-			err := processInfo.Process.Process.Signal(os.Signal(nil))
+			err := processInfo.Process.Process.Signal(syscall.Signal(0))
 			if err == nil {
 				return true
 			}
@@ -602,14 +1203,20 @@ func (m *Manager) IsServiceRunning(projectID uint) bool {
 
 	// Get project info including PID and Port
 	var project struct {
-		PID  int
-		Port int
-		Path string
+		PID        int
+		Port       int
+		Path       string
+		SocketPath string
 	}
-	if err := m.db.Table("projects").Where("id = ?", projectID).Select("p_id, port, path").First(&project).Error; err != nil {
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Select("p_id, port, path, socket_path").First(&project).Error; err != nil {
 		return false
 	}
 
+	// Services listening on a Unix socket (gRPC over UDS, ...) are ready once it accepts connections
+	if project.SocketPath != "" && m.isUnixSocketListening(project.SocketPath) {
+		return true
+	}
+
 	// Priority 1: Check by port if port is specified
 	// This is the most reliable indicator for services like vite, node, etc.
 	// Parent process (npm/yarn) may exit, but child process (vite) is still running on port
@@ -623,7 +1230,7 @@ func (m *Manager) IsServiceRunning(projectID uint) bool {
 	if project.PID > 0 {
 		proc, err := os.FindProcess(project.PID)
 		if err == nil {
-			err = proc.Signal(os.Signal(nil))
+			err = proc.Signal(syscall.Signal(0))
 			if err == nil {
 				return true
 			}
@@ -707,10 +1314,14 @@ type PortInfo struct {
 	User        string `json:"user"`
 	Command     string `json:"command"`
 	Status      string `json:"status"`
+	Protocol    string `json:"protocol"`                 // tcp or udp
+	ProjectID   *uint  `json:"project_id,omitempty"`   // Project owning this port, if any
+	ProjectName string `json:"project_name,omitempty"`
+	PortName    string `json:"port_name,omitempty"` // Name of the declared port (http, grpc, ...)
 }
 
-// GetPortsInUse returns list of all ports in use with process information
-func (m *Manager) GetPortsInUse() ([]PortInfo, error) {
+// getTCPPortsInUse returns list of all listening TCP ports with process information
+func (m *Manager) getTCPPortsInUse() ([]PortInfo, error) {
 	var ports []PortInfo
 
 	// Use lsof to get all listening ports
@@ -926,7 +1537,7 @@ func (m *Manager) KillPort(port int) error {
 
 	// Wait a bit and check if process is still alive
 	time.Sleep(500 * time.Millisecond)
-	if err := proc.Signal(os.Signal(nil)); err == nil {
+	if err := proc.Signal(syscall.Signal(0)); err == nil {
 		// Process still alive, force kill
 		proc.Kill()
 	}
@@ -1067,7 +1678,7 @@ func (m *Manager) GetServiceLogBuffer(projectID uint) []string {
 	defer m.mu.RUnlock()
 
 	if processInfo, exists := m.processes[projectID]; exists {
-		return processInfo.getLogBuffer()
+		return logLines(processInfo.getLogEntries())
 	}
 	return []string{}
 }
@@ -1083,7 +1694,14 @@ func (m *Manager) prepareCommand(ctx context.Context, p *struct {
 	if p.Command != "" {
 		// Split command and arguments
 		parts := strings.Fields(p.Command)
-		if len(parts) > 1 {
+		if len(parts) > 2 && (parts[0] == "sh" || parts[0] == "bash") && parts[1] == "-c" {
+			// Shell commands keep their script as one argument: sh -c bundle exec puma -p $PORT
+			script := strings.TrimSpace(strings.SplitN(p.Command, "-c", 2)[1])
+			if len(script) > 1 && (script[0] == '"' || script[0] == '\'') && script[len(script)-1] == script[0] {
+				script = script[1 : len(script)-1]
+			}
+			cmd = exec.CommandContext(ctx, parts[0], "-c", script)
+		} else if len(parts) > 1 {
 			cmd = exec.CommandContext(ctx, parts[0], parts[1:]...)
 		} else {
 			cmd = exec.CommandContext(ctx, parts[0])
@@ -1109,6 +1727,19 @@ func (m *Manager) prepareCommand(ctx context.Context, p *struct {
 	return cmd
 }
 
+// startupBanner is the line written to the output of a service before it
+// starts: its name, time, command, directory and git revision
+func startupBanner(name string, cmd *exec.Cmd, inspectPort int) string {
+	banner := fmt.Sprintf("==== %s started at %s: %s (in %s", name, time.Now().Format(time.RFC3339), strings.Join(cmd.Args, " "), cmd.Dir)
+	if revision := GitRevision(cmd.Dir); revision != "" {
+		banner += ", revision " + ShortRevision(revision)
+	}
+	if inspectPort > 0 {
+		banner += fmt.Sprintf(", inspector on port %d", inspectPort)
+	}
+	return banner + ") ===="
+}
+
 // prepareEnvironment sets up environment variables
 func (m *Manager) prepareEnvironment(p *struct {
 	Port        int
@@ -1116,17 +1747,14 @@ func (m *Manager) prepareEnvironment(p *struct {
 	EnvFile     string
 	EnvVars     string
 	Path        string
+	Template    *TemplateContext
 }) []string {
 	env := os.Environ()
 
 	// Add project-specific environment variables from .env file
-	// First, try EnvFile path if specified
-	envFilePath := p.EnvFile
-	if envFilePath == "" && p.Path != "" {
-		// Default to .env in project path
-		envFilePath = filepath.Join(p.Path, ".env")
-	}
-	
+	// (configured EnvFile, or .env in the project path)
+	envFilePath := ResolveEnvFilePath(p.EnvFile, p.Path)
+
 	if envFilePath != "" {
 		envVarsFromFile := m.loadEnvFile(envFilePath)
 		// Merge env vars from file (file takes precedence over system env)
@@ -1143,7 +1771,7 @@ func (m *Manager) prepareEnvironment(p *struct {
 		for k, v := range envVarsFromJSON {
 			// Remove existing env var with same key
 			env = m.removeEnvVar(env, k)
-			env = append(env, fmt.Sprintf("%s=%s", k, v))
+			env = append(env, fmt.Sprintf("%s=%s", k, p.Template.Expand(v)))
 		}
 	}
 
@@ -1190,29 +1818,9 @@ func (m *Manager) loadEnvFile(envPath string) map[string]string {
 	if err != nil {
 		return envVars
 	}
-	
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		
-		// Parse KEY=VALUE format
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			// Remove quotes if present
-			if len(value) >= 2 && ((value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'')) {
-				value = value[1 : len(value)-1]
-			}
-			envVars[key] = value
-		}
-	}
-	
-	return envVars
+
+	// Invalid lines are skipped
+	return EnvFileVars(ParseEnvFile(string(content)))
 }
 
 // parseEnvVarsJSON parses JSON string of environment variables
@@ -1261,30 +1869,85 @@ func (m *Manager) removeEnvVar(env []string, key string) []string {
 func (m *Manager) monitorProcess(processInfo *ProcessInfo) {
 	// Wait for process to finish
 	err := processInfo.Process.Wait()
+	m.processExited(processInfo, err)
+}
+
+// processExited updates the status of a project whose process ended, err
+// being the result of waiting for it
+func (m *Manager) processExited(processInfo *ProcessInfo, err error) {
+	// The output files are complete: tails read what is left and end
+	if processInfo.stopOutput != nil {
+		processInfo.stopOutput()
+	}
+
+	// Tell an out of memory kill apart from a plain "signal: killed", before
+	// taking the lock as it reads the kernel log
+	var oomKill *OOMKill
+	if err != nil && processInfo.Context.Err() == nil && !processInfo.LogFollower && processInfo.Process.Process != nil {
+		oomKill = m.findOOMKill(processInfo.ProjectID, int32(processInfo.Process.Process.Pid), processInfo.StartTime)
+	}
 
 	// Update project status
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// Old instances of blue/green restarts stop on their own
+	if m.isDrainingInstance(processInfo) {
+		processInfo.safeCloseChannel()
+		return
+	}
+
+	// The project was already started again: its new process owns the status
+	if current, ok := m.processes[processInfo.ProjectID]; ok && current != processInfo {
+		return
+	}
+
+	// A log follower ending says nothing about the service, the Kubernetes
+	// and systemd monitors restart it
+	if processInfo.LogFollower {
+		processInfo.safeCloseChannel()
+		if current, ok := m.processes[processInfo.ProjectID]; ok && current == processInfo {
+			delete(m.processes, processInfo.ProjectID)
+		}
+		return
+	}
+
+	// A pause ends with the main process, the processes left continue
+	m.endPause(processInfo.ProjectID)
+	// And the extra instances do not outlive it
+	go m.stopInstanceProcesses(m.detachInstances(processInfo.ProjectID))
+
 	now := time.Now()
 	status := string(types.StatusStopped)
 	lastError := ""
+	reason := "Process exited"
 
-	if err != nil {
+	// A stop cancels the context and kills the process: not a crash
+	crashed := err != nil && processInfo.Context.Err() == nil
+	if crashed {
 		status = string(types.StatusError)
 		lastError = err.Error()
+		if oomKill != nil {
+			lastError = oomKill.describe(int32(processInfo.Process.Process.Pid), err)
+		}
+		reason = "Process exited: " + lastError
 	}
 
-	m.db.Table("projects").Where("id = ?", processInfo.ProjectID).Updates(map[string]interface{}{
+	m.db.Table(tables.Name("projects")).Where("id = ?", processInfo.ProjectID).Updates(map[string]interface{}{
 		"status":      status,
 		"stop_time":   &now,
 		"p_id":        0, // Use p_id (snake_case) as GORM converts PID to p_id
 		"last_error":  lastError,
 	})
+	m.RecordStatus(processInfo.ProjectID, status, reason)
 
 	// Clean up
 	processInfo.safeCloseChannel()
 	delete(m.processes, processInfo.ProjectID)
+
+	if crashed {
+		m.scheduleAutoRestart(processInfo.ProjectID)
+	}
 }
 
 // captureOutput reads from a pipe and sends lines to the logs channel
@@ -1348,7 +2011,7 @@ func stripANSI(s string) string {
 }
 
 // safeSendLog safely sends a log line to the channel, handling closed channel gracefully
-func safeSendLog(ch chan<- string, logLine string) bool {
+func safeSendLog(ch chan<- LogEntry, entry LogEntry) bool {
 	defer func() {
 		// Recover from panic if channel is closed
 		if r := recover(); r != nil {
@@ -1357,7 +2020,7 @@ func safeSendLog(ch chan<- string, logLine string) bool {
 	}()
 	
 	select {
-	case ch <- logLine:
+	case ch <- entry:
 		return true
 	default:
 		// Channel full, skip sending
@@ -1387,6 +2050,17 @@ func (p *ProcessInfo) safeCloseChannel() {
 
 // captureOutputWithBuffer reads from a pipe, sends to channel, and buffers logs
 func (m *Manager) captureOutputWithBuffer(pipe io.ReadCloser, processInfo *ProcessInfo, isStderr bool) {
+	// Add prefix to distinguish stderr
+	prefix := ""
+	if isStderr {
+		prefix = "[ERROR] "
+	}
+	m.captureTaggedOutput(pipe, processInfo, prefix)
+}
+
+// captureTaggedOutput reads from a pipe like captureOutputWithBuffer, with a
+// prefix tagging the source of its lines
+func (m *Manager) captureTaggedOutput(pipe io.ReadCloser, processInfo *ProcessInfo, prefix string) {
 	defer func() {
 		// Recover from any panic (e.g., sending to closed channel)
 		if r := recover(); r != nil {
@@ -1396,11 +2070,19 @@ func (m *Manager) captureOutputWithBuffer(pipe io.ReadCloser, processInfo *Proce
 		pipe.Close()
 	}()
 	
-	scanner := bufio.NewScanner(pipe)
+	// Long lines are truncated and binary output skipped, instead of ending capture
+	reader := newLogLineReader(pipe, m.logOptions().MaxLineSize)
 	lastSaveTime := time.Now()
 	
-	for scanner.Scan() {
-		line := scanner.Text()
+	var readErr error
+	for {
+		line, err := reader.Next()
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
+		}
 		
 		// Strip ANSI escape codes
 		cleanLine := stripANSI(line)
@@ -1410,43 +2092,46 @@ func (m *Manager) captureOutputWithBuffer(pipe io.ReadCloser, processInfo *Proce
 			continue
 		}
 		
-		// Add prefix to distinguish stderr
-		var logLine string
-		if isStderr {
-			logLine = fmt.Sprintf("[ERROR] %s", cleanLine)
-		} else {
-			logLine = cleanLine
-		}
+		logLine := prefix + cleanLine
+		
+		// Stamp with the capture time, kept apart from the line
+		entry := newLogEntry(logLine)
 		
 		// Add to buffer
-		processInfo.addToLogBuffer(logLine)
+		processInfo.addToLogBuffer(entry)
+		m.publishLog(processInfo.ProjectID, entry)
+		m.traces.add(processInfo.ProjectID, logLine, entry.Time)
 		
 		// Send to channel safely (handles closed channel)
-		safeSendLog(processInfo.Logs, logLine)
+		processInfo.logCaptured.Add(1)
+		if !safeSendLog(processInfo.Logs, entry) {
+			processInfo.logDropped.Add(1)
+		}
 		
 		// Save logs to database every 2 seconds
 		if time.Since(lastSaveTime) > 2*time.Second {
-			m.saveLogsToDatabase(processInfo.ProjectID, processInfo.getLogBuffer())
+			m.saveLogsToDatabase(processInfo.ProjectID, processInfo.getLogEntries())
 			lastSaveTime = time.Now()
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		errorMsg := fmt.Sprintf("[ERROR] Error reading output: %v", err)
-		processInfo.addToLogBuffer(errorMsg)
+	if readErr != nil {
+		entry := newLogEntry(fmt.Sprintf("[ERROR] Error reading output: %v", readErr))
+		processInfo.addToLogBuffer(entry)
+		m.publishLog(processInfo.ProjectID, entry)
 		// Send safely (handles closed channel)
-		safeSendLog(processInfo.Logs, errorMsg)
-		// Save final logs
-		m.saveLogsToDatabase(processInfo.ProjectID, processInfo.getLogBuffer())
+		safeSendLog(processInfo.Logs, entry)
 	}
+	// Save final logs
+	m.saveLogsToDatabase(processInfo.ProjectID, processInfo.getLogEntries())
 }
 
-// addToLogBuffer adds a log line to the buffer (thread-safe)
-func (p *ProcessInfo) addToLogBuffer(line string) {
+// addToLogBuffer adds a log entry to the buffer (thread-safe)
+func (p *ProcessInfo) addToLogBuffer(entry LogEntry) {
 	p.logMu.Lock()
 	defer p.logMu.Unlock()
 	
 	// Add to buffer
-	p.LogBuffer = append(p.LogBuffer, line)
+	p.LogBuffer = append(p.LogBuffer, entry)
 	
 	// Keep only last 1000 lines
 	maxBufferSize := 1000
@@ -1455,33 +2140,46 @@ func (p *ProcessInfo) addToLogBuffer(line string) {
 	}
 }
 
-// getLogBuffer returns a copy of the log buffer (thread-safe)
-func (p *ProcessInfo) getLogBuffer() []string {
+// getLogEntries returns a copy of the log buffer (thread-safe)
+func (p *ProcessInfo) getLogEntries() []LogEntry {
 	p.logMu.Lock()
 	defer p.logMu.Unlock()
 	
 	// Return a copy
-	result := make([]string, len(p.LogBuffer))
+	result := make([]LogEntry, len(p.LogBuffer))
 	copy(result, p.LogBuffer)
 	return result
 }
 
-// saveLogsToDatabase saves logs to database
-func (m *Manager) saveLogsToDatabase(projectID uint, logs []string) {
-	if len(logs) == 0 {
+// saveLogsToDatabase saves logs to database, the lines and their capture
+// times as two parallel JSON arrays
+func (m *Manager) saveLogsToDatabase(projectID uint, entries []LogEntry) {
+	if len(entries) == 0 {
 		return
 	}
 	
+	times := make([]time.Time, len(entries))
+	for i, entry := range entries {
+		times[i] = entry.Time
+	}
+	
 	// Convert to JSON
-	logsJSON, err := json.Marshal(logs)
+	logsJSON, err := json.Marshal(logLines(entries))
 	if err != nil {
 		return // Silently fail, don't block log capture
 	}
+	timesJSON, err := json.Marshal(times)
+	if err != nil {
+		return
+	}
 	
 	// Save to database (non-blocking, no lock needed as we're in a goroutine)
 	go func() {
-		// Use Update without lock since we're in a separate goroutine
+		// Use Updates without lock since we're in a separate goroutine
 		// and database operations are thread-safe
-		m.db.Table("projects").Where("id = ?", projectID).Update("logs", string(logsJSON))
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"logs":      string(logsJSON),
+			"log_times": string(timesJSON),
+		})
 	}()
 }
\ No newline at end of file
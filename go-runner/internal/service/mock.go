@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mockLogLines is how many request lines a mock server keeps
+const mockLogLines = 1000
+
+// MockRoute is a canned response of a mock project
+type MockRoute struct {
+	Method  string            `json:"method" yaml:"method"`             // Any method when empty
+	Path    string            `json:"path" yaml:"path"`                 // /users/:id or /users/{id}; a final * matches the rest
+	Status  int               `json:"status" yaml:"status"`             // 200 when 0
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers"` // Content-Type defaults to application/json for non-string bodies
+	Body    interface{}       `json:"body,omitempty" yaml:"body"`       // A string is sent as is, anything else as JSON
+	DelayMs int               `json:"delay_ms,omitempty" yaml:"delay_ms"`
+}
+
+// MockServer reports the stub server of a running mock project
+type MockServer struct {
+	Port      int         `json:"port"`
+	Source    string      `json:"source"` // inline, or the routes or OpenAPI file
+	Routes    []MockRoute `json:"routes"`
+	Requests  uint64      `json:"requests"`
+	Unmatched uint64      `json:"unmatched"` // Requests answered 404 for lack of a route
+	StartedAt time.Time   `json:"started_at"`
+}
+
+// mockServer is the stub server of a mock project
+type mockServer struct {
+	srv  *http.Server
+	mu   sync.Mutex
+	info MockServer
+	logs []LogEntry
+}
+
+// mockServers holds the running mock servers
+type mockServers struct {
+	mu      sync.RWMutex
+	servers map[uint]*mockServer
+}
+
+// isMockProject reports whether a project is of type mock
+func (m *Manager) isMockProject(projectID uint) bool {
+	var p struct{ Type string }
+	return m.db.Table(tables.Name("projects")).Select("type").Where("id = ?", projectID).Take(&p).Error == nil &&
+		p.Type == string(types.TypeMock)
+}
+
+// MockStatus returns the stub server of a mock project, or nil when it is
+// not running
+func (m *Manager) MockStatus(projectID uint) *MockServer {
+	m.mocks.mu.RLock()
+	s, ok := m.mocks.servers[projectID]
+	m.mocks.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := s.info
+	return &info
+}
+
+// startMock serves the routes of a mock project on its port from go-runner
+func (m *Manager) startMock(projectID uint) error {
+	var p struct {
+		Port     int
+		Path     string
+		MockSpec string
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("port, path, mock_spec").Where("id = ?", projectID).Take(&p).Error; err != nil {
+		return fmt.Errorf("project not found: %v", err)
+	}
+
+	m.mocks.mu.Lock()
+	defer m.mocks.mu.Unlock()
+	if _, exists := m.mocks.servers[projectID]; exists {
+		return fmt.Errorf("service %d is already running", projectID)
+	}
+
+	fail := func(err error) error {
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":     string(types.StatusError),
+			"last_error": err.Error(),
+		})
+		m.RecordStatus(projectID, string(types.StatusError), err.Error())
+		return err
+	}
+	if p.Port <= 0 {
+		return fail(errors.New("mock project has no port"))
+	}
+	routes, source, err := LoadMockRoutes(p.MockSpec, p.Path)
+	if err != nil {
+		return fail(err)
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", p.Port))
+	if err != nil {
+		return fail(fmt.Errorf("failed to listen on port %d: %v", p.Port, err))
+	}
+
+	now := time.Now()
+	s := &mockServer{info: MockServer{Port: p.Port, Source: source, Routes: routes, StartedAt: now}}
+	s.srv = &http.Server{Handler: m.mockHandler(projectID, s), ReadHeaderTimeout: 10 * time.Second}
+	if m.mocks.servers == nil {
+		m.mocks.servers = make(map[uint]*mockServer)
+	}
+	m.mocks.servers[projectID] = s
+	go func() {
+		if err := s.srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("Mock server of project %d failed: %v", projectID, err)
+		}
+	}()
+
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+		"status":        string(types.StatusRunning),
+		"start_time":    &now,
+		"p_id":          0, // Served by go-runner itself
+		"process_start": nil,
+		"last_error":    "",
+	})
+	m.RecordStatus(projectID, string(types.StatusRunning), fmt.Sprintf("Mock serving %d routes from %s", len(routes), source))
+	log.Printf("🎭 Mock project %d serving %d routes on port %d", projectID, len(routes), p.Port)
+	return nil
+}
+
+// stopMock shuts the stub server of a mock project down and saves its
+// request log
+func (m *Manager) stopMock(projectID uint) error {
+	m.mocks.mu.Lock()
+	s, exists := m.mocks.servers[projectID]
+	delete(m.mocks.servers, projectID)
+	m.mocks.mu.Unlock()
+
+	if exists {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.srv.Shutdown(ctx)
+
+		s.mu.Lock()
+		entries := append([]LogEntry(nil), s.logs...)
+		s.mu.Unlock()
+		m.saveLogsToDatabase(projectID, entries)
+	}
+
+	now := time.Now()
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+		"status":    string(types.StatusStopped),
+		"stop_time": &now,
+		"p_id":      0,
+	})
+	m.RecordStatus(projectID, string(types.StatusStopped), "Mock stopped")
+	return nil
+}
+
+// mockLogEntries returns the request log of a running mock project
+func (m *Manager) mockLogEntries(projectID uint) []LogEntry {
+	m.mocks.mu.RLock()
+	s, ok := m.mocks.servers[projectID]
+	m.mocks.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]LogEntry(nil), s.logs...)
+}
+
+// mockHandler answers requests with the first matching route and logs each
+// request like service output
+func (m *Manager) mockHandler(projectID uint, s *mockServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		s.mu.Lock()
+		routes := s.info.Routes
+		s.mu.Unlock()
+
+		status := http.StatusNotFound
+		route := matchMockRoute(routes, r.Method, r.URL.Path)
+		if route == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("no mock route for %s %s", r.Method, r.URL.Path)})
+		} else {
+			status = writeMockResponse(w, route)
+		}
+
+		entry := newLogEntry(fmt.Sprintf("%s %s → %d (%.1fms)", r.Method, r.URL.RequestURI(), status, durationMs(time.Since(start))))
+		s.mu.Lock()
+		s.info.Requests++
+		if route == nil {
+			s.info.Unmatched++
+		}
+		s.logs = append(s.logs, entry)
+		if len(s.logs) > mockLogLines {
+			s.logs = s.logs[len(s.logs)-mockLogLines:]
+		}
+		s.mu.Unlock()
+		m.publishLog(projectID, entry)
+	})
+}
+
+// writeMockResponse writes the canned response of a route and returns its status
+func writeMockResponse(w http.ResponseWriter, route *MockRoute) int {
+	if route.DelayMs > 0 {
+		time.Sleep(time.Duration(route.DelayMs) * time.Millisecond)
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	var body []byte
+	switch b := route.Body.(type) {
+	case nil:
+	case string:
+		body = []byte(b)
+	default:
+		body, _ = json.Marshal(b)
+		w.Header().Set("Content-Type", "application/json")
+	}
+	for name, value := range route.Headers {
+		w.Header().Set(name, value)
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+	return status
+}
+
+// matchMockRoute returns the first route matching a request, nil when none does
+func matchMockRoute(routes []MockRoute, method, path string) *MockRoute {
+	segments := splitMockPath(path)
+	for i := range routes {
+		route := &routes[i]
+		if route.Method != "" && !strings.EqualFold(route.Method, method) {
+			continue
+		}
+		if mockPathMatches(splitMockPath(route.Path), segments) {
+			return route
+		}
+	}
+	return nil
+}
+
+// mockPathMatches matches path segments against a route pattern, where :name
+// and {name} match one segment and a final * the rest
+func mockPathMatches(pattern, segments []string) bool {
+	for i, part := range pattern {
+		if part == "*" && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if strings.HasPrefix(part, ":") || (strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}")) {
+			continue
+		}
+		if part != segments[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
+
+func splitMockPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// LoadMockRoutes reads the routes of a mock project: mock_spec holds them as
+// JSON (a list of routes, or an object with "routes"), or names a routes file
+// or an OpenAPI document, in JSON or YAML, relative to the project path.
+// Returns the routes and where they came from.
+func LoadMockRoutes(spec, projectPath string) ([]MockRoute, string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, "", errors.New("mock_spec is empty: give routes as JSON or the path of a routes or OpenAPI file")
+	}
+
+	source := "inline"
+	data := []byte(spec)
+	if spec[0] != '[' && spec[0] != '{' {
+		source = spec
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(projectPath, source)
+		}
+		var err error
+		if data, err = os.ReadFile(source); err != nil {
+			return nil, "", fmt.Errorf("failed to read mock spec: %v", err)
+		}
+	}
+
+	// YAML is a superset of JSON, so one decoder reads both
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, "", fmt.Errorf("invalid mock spec: %v", err)
+	}
+
+	var routes []MockRoute
+	var err error
+	if obj, ok := doc.(map[string]interface{}); ok && (obj["openapi"] != nil || obj["swagger"] != nil) {
+		routes = openAPIMockRoutes(obj)
+	} else {
+		routes, err = decodeMockRoutes(data, doc)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if len(routes) == 0 {
+		return nil, "", errors.New("mock spec defines no routes")
+	}
+	for i, route := range routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return nil, "", fmt.Errorf("route %d: path %q must start with /", i+1, route.Path)
+		}
+	}
+	return routes, source, nil
+}
+
+// decodeMockRoutes decodes a list of routes or an object with "routes"
+func decodeMockRoutes(data []byte, doc interface{}) ([]MockRoute, error) {
+	if _, ok := doc.(map[string]interface{}); ok {
+		var wrapped struct {
+			Routes []MockRoute `yaml:"routes"`
+		}
+		if err := yaml.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("invalid mock routes: %v", err)
+		}
+		return wrapped.Routes, nil
+	}
+	var routes []MockRoute
+	if err := yaml.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("invalid mock routes: %v", err)
+	}
+	return routes, nil
+}
+
+// openAPIMethods are the operations of an OpenAPI path item, in route order
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// openAPIMockRoutes turns the operations of an OpenAPI 3 or Swagger 2
+// document into routes answering with the lowest 2xx response and its
+// example. Paths without parameters come first so they win over templated ones.
+func openAPIMockRoutes(doc map[string]interface{}) []MockRoute {
+	paths, _ := doc["paths"].(map[string]interface{})
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ti, tj := strings.Contains(names[i], "{"), strings.Contains(names[j], "{")
+		if ti != tj {
+			return !ti
+		}
+		return names[i] < names[j]
+	})
+
+	var routes []MockRoute
+	for _, name := range names {
+		item, _ := paths[name].(map[string]interface{})
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			status, response := openAPIResponse(op)
+			routes = append(routes, MockRoute{
+				Method: strings.ToUpper(method),
+				Path:   name,
+				Status: status,
+				Body:   openAPIExample(response),
+			})
+		}
+	}
+	return routes
+}
+
+// openAPIResponse picks the lowest 2xx response of an operation, or the
+// default response answered with 200
+func openAPIResponse(op map[string]interface{}) (int, map[string]interface{}) {
+	responses, _ := op["responses"].(map[string]interface{})
+	best := 0
+	var response map[string]interface{}
+	for code, r := range responses {
+		n, err := strconv.Atoi(code)
+		if err != nil || n < 200 || n > 299 || (best != 0 && n > best) {
+			continue
+		}
+		best = n
+		response, _ = r.(map[string]interface{})
+	}
+	if best == 0 {
+		response, _ = responses["default"].(map[string]interface{})
+		best = http.StatusOK
+	}
+	return best, response
+}
+
+// openAPIExample returns the example body of a response: OpenAPI 3
+// content.<json>.example(s) or Swagger 2 examples, then the schema example
+func openAPIExample(response map[string]interface{}) interface{} {
+	if response == nil {
+		return nil
+	}
+	var media map[string]interface{}
+	if content, ok := response["content"].(map[string]interface{}); ok {
+		for mediaType, v := range content {
+			media, _ = v.(map[string]interface{})
+			if strings.Contains(mediaType, "json") {
+				break
+			}
+		}
+	}
+	if media != nil {
+		if example, ok := media["example"]; ok {
+			return example
+		}
+		if examples, ok := media["examples"].(map[string]interface{}); ok {
+			for _, v := range examples {
+				if e, ok := v.(map[string]interface{}); ok && e["value"] != nil {
+					return e["value"]
+				}
+			}
+		}
+		if schema, ok := media["schema"].(map[string]interface{}); ok && schema["example"] != nil {
+			return schema["example"]
+		}
+	}
+	if examples, ok := response["examples"].(map[string]interface{}); ok {
+		for _, v := range examples {
+			return v
+		}
+	}
+	if schema, ok := response["schema"].(map[string]interface{}); ok && schema["example"] != nil {
+		return schema["example"]
+	}
+	return nil
+}
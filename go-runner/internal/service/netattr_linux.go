@@ -0,0 +1,157 @@
+//go:build linux
+
+package service
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// sock_diag constants from linux/sock_diag.h and linux/inet_diag.h
+const (
+	sockDiagByFamily = 20
+	inetDiagInfo     = 2
+
+	inetDiagMsgLen = 72  // struct inet_diag_msg
+	inetDiagInode  = 68  // Offset of idiag_inode
+	tcpInfoAcked   = 120 // Offset of tcpi_bytes_acked in struct tcp_info, Linux 4.1
+	tcpInfoRecv    = 128 // Offset of tcpi_bytes_received
+)
+
+// inetDiagReq is struct inet_diag_req_v2
+type inetDiagReq struct {
+	Family   uint8
+	Protocol uint8
+	Ext      uint8
+	Pad      uint8
+	States   uint32
+	ID       [48]byte // struct inet_diag_sockid, zero to dump every socket
+}
+
+// socketCounters dumps the TCP sockets of the host through sock_diag with
+// the byte counters of their tcp_info, by inode: /proc/net/tcp lists the
+// sockets but not how much went through them
+func socketCounters() (map[uint32]socketBytes, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.NETLINK_INET_DIAG)
+	if err != nil {
+		return nil, fmt.Errorf("open sock_diag socket: %w", err)
+	}
+	defer syscall.Close(fd)
+
+	counters := make(map[uint32]socketBytes)
+	for _, family := range []uint8{syscall.AF_INET, syscall.AF_INET6} {
+		if err := dumpTCPSockets(fd, family, counters); err != nil {
+			return nil, err
+		}
+	}
+	return counters, nil
+}
+
+// dumpTCPSockets adds the TCP sockets of an address family to counters
+func dumpTCPSockets(fd int, family uint8, counters map[uint32]socketBytes) error {
+	req := inetDiagReq{Family: family, Protocol: syscall.IPPROTO_TCP, Ext: 1 << (inetDiagInfo - 1), States: 0xffffffff}
+	header := syscall.NlMsghdr{
+		Len:   uint32(syscall.SizeofNlMsghdr + unsafe.Sizeof(req)),
+		Type:  sockDiagByFamily,
+		Flags: syscall.NLM_F_REQUEST | syscall.NLM_F_DUMP,
+		Seq:   uint32(family),
+	}
+	msg := make([]byte, 0, header.Len)
+	msg = append(msg, (*[syscall.SizeofNlMsghdr]byte)(unsafe.Pointer(&header))[:]...)
+	msg = append(msg, (*[unsafe.Sizeof(req)]byte)(unsafe.Pointer(&req))[:]...)
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return fmt.Errorf("query sock_diag: %w", err)
+	}
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return fmt.Errorf("read sock_diag: %w", err)
+		}
+		messages, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return fmt.Errorf("parse sock_diag: %w", err)
+		}
+		for _, m := range messages {
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return nil
+			case syscall.NLMSG_ERROR:
+				if len(m.Data) >= 4 {
+					if errno := int32(binary.NativeEndian.Uint32(m.Data)); errno != 0 {
+						return fmt.Errorf("sock_diag: %w", syscall.Errno(-errno))
+					}
+				}
+				return errors.New("sock_diag: error without code")
+			}
+			if len(m.Data) < inetDiagMsgLen {
+				continue
+			}
+			inode := binary.NativeEndian.Uint32(m.Data[inetDiagInode:])
+			if inode == 0 {
+				continue // Time-wait sockets have no owner left
+			}
+			if bytes, ok := tcpInfoBytes(m.Data[inetDiagMsgLen:]); ok {
+				counters[inode] = bytes
+			}
+		}
+	}
+}
+
+// tcpInfoBytes reads the byte counters of the tcp_info attribute of a socket
+func tcpInfoBytes(attrs []byte) (socketBytes, bool) {
+	for len(attrs) >= syscall.SizeofRtAttr {
+		length := int(binary.NativeEndian.Uint16(attrs))
+		kind := binary.NativeEndian.Uint16(attrs[2:])
+		if length < syscall.SizeofRtAttr || length > len(attrs) {
+			return socketBytes{}, false
+		}
+		if kind == inetDiagInfo {
+			info := attrs[syscall.SizeofRtAttr:length]
+			if len(info) < tcpInfoRecv+8 {
+				return socketBytes{}, false // Kernel older than 4.1
+			}
+			return socketBytes{
+				sent:     binary.NativeEndian.Uint64(info[tcpInfoAcked:]),
+				received: binary.NativeEndian.Uint64(info[tcpInfoRecv:]),
+			}, true
+		}
+		attrs = attrs[(length+syscall.RTA_ALIGNTO-1) & ^(syscall.RTA_ALIGNTO-1):]
+	}
+	return socketBytes{}, false
+}
+
+// socketInodes returns the inodes of the sockets open by processes, from
+// their /proc/<pid>/fd links
+func socketInodes(pids []int32) []uint32 {
+	seen := make(map[uint32]bool)
+	var inodes []uint32
+	for _, pid := range pids {
+		dir := "/proc/" + strconv.Itoa(int(pid)) + "/fd"
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // Exited meanwhile
+		}
+		for _, entry := range entries {
+			link, err := os.Readlink(dir + "/" + entry.Name())
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err != nil || seen[uint32(inode)] {
+				continue
+			}
+			// sock_diag reports the low 32 bits of the inode
+			seen[uint32(inode)] = true
+			inodes = append(inodes, uint32(inode))
+		}
+	}
+	return inodes
+}
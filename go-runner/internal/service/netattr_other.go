@@ -0,0 +1,15 @@
+//go:build !linux
+
+package service
+
+import "errors"
+
+// socketCounters is only available on Linux, through sock_diag
+func socketCounters() (map[uint32]socketBytes, error) {
+	return nil, errors.New("network attribution is only supported on Linux")
+}
+
+// socketInodes is only available on Linux, through /proc
+func socketInodes(pids []int32) []uint32 {
+	return nil
+}
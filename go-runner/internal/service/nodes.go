@@ -0,0 +1,367 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// Node states; a node starts "unknown" until its first check
+const (
+	NodeUp      = "up"
+	NodeDown    = "down"
+	NodeUnknown = "unknown"
+)
+
+// NodeOptions configures the checks of the hosts of SSH projects
+type NodeOptions struct {
+	WG               string        // WireGuard CLI, tunnels are not tracked when missing
+	HandshakeTimeout time.Duration // Age of the last handshake after which a tunnel is down
+	Failures         int           // Consecutive failed checks before a node is down
+}
+
+// Node is a host running SSH projects, as reached from go-runner
+type Node struct {
+	Host       string         `json:"host"`
+	Address    string         `json:"address"` // host:port checked
+	Status     string         `json:"status"`  // up, down or unknown
+	Since      time.Time      `json:"since"`   // Time of the last status change
+	LatencyMs  *float64       `json:"latency_ms,omitempty"`
+	Tunnel     *WireGuardPeer `json:"tunnel,omitempty"` // WireGuard peer routing the host
+	Failures   int            `json:"failures"`         // Consecutive failed checks
+	Error      string         `json:"error,omitempty"`
+	ProjectIDs []uint         `json:"project_ids"`
+	CheckedAt  time.Time      `json:"checked_at"`
+}
+
+// WireGuardPeer is the WireGuard peer whose allowed IPs contain a node
+type WireGuardPeer struct {
+	Interface       string     `json:"interface"`
+	PublicKey       string     `json:"public_key"`
+	Endpoint        string     `json:"endpoint,omitempty"`
+	AllowedIPs      []string   `json:"allowed_ips"`
+	LatestHandshake *time.Time `json:"latest_handshake,omitempty"`
+	HandshakeAge    *float64   `json:"handshake_age_seconds,omitempty"`
+	Status          string     `json:"status"` // up, or down when the last handshake is older than the timeout
+	RxBytes         int64      `json:"rx_bytes"`
+	TxBytes         int64      `json:"tx_bytes"`
+}
+
+// nodeState holds the node options and the last check per host
+type nodeState struct {
+	mu    sync.RWMutex
+	opts  NodeOptions
+	nodes map[string]*Node
+}
+
+// SetNodeOptions configures node checks
+func (m *Manager) SetNodeOptions(opts NodeOptions) {
+	if opts.Failures < 1 {
+		opts.Failures = 1
+	}
+	if opts.HandshakeTimeout <= 0 {
+		// WireGuard rekeys every 2 minutes while traffic flows
+		opts.HandshakeTimeout = 3 * time.Minute
+	}
+	m.nodes.mu.Lock()
+	m.nodes.opts = opts
+	m.nodes.mu.Unlock()
+}
+
+// Nodes returns the last check of every node, by host
+func (m *Manager) Nodes() []Node {
+	m.nodes.mu.RLock()
+	defer m.nodes.mu.RUnlock()
+
+	nodes := make([]Node, 0, len(m.nodes.nodes))
+	for _, node := range m.nodes.nodes {
+		nodes = append(nodes, *node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Host < nodes[j].Host })
+	return nodes
+}
+
+// NodeStatus returns the last check of the node of an ssh_host, or nil
+func (m *Manager) NodeStatus(sshHost string) *Node {
+	_, host, _ := splitSSHHost(sshHost)
+	m.nodes.mu.RLock()
+	defer m.nodes.mu.RUnlock()
+	if node, ok := m.nodes.nodes[host]; ok {
+		n := *node
+		return &n
+	}
+	return nil
+}
+
+// nodeDown reports whether the node of an ssh_host is down
+func (m *Manager) nodeDown(sshHost string) bool {
+	node := m.NodeStatus(sshHost)
+	return node != nil && node.Status == NodeDown
+}
+
+// nodeAddress returns the address ssh connects to for a host, asking ssh
+// for the HostName and Port of its config when the host does not resolve
+func (m *Manager) nodeAddress(ctx context.Context, host, port string) string {
+	if port == "" {
+		port = "22"
+	}
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err == nil {
+		return net.JoinHostPort(host, port)
+	}
+
+	m.ssh.mu.RLock()
+	binary := m.ssh.opts.Binary
+	m.ssh.mu.RUnlock()
+	out, err := exec.CommandContext(ctx, binary, "-G", "-p", port, host).Output()
+	if err != nil {
+		return net.JoinHostPort(host, port)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "hostname":
+			host = value
+		case "port":
+			port = value
+		}
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// wireGuardPeers lists the peers of every WireGuard interface, nil when wg
+// is missing or not permitted
+func (m *Manager) wireGuardPeers(ctx context.Context) []WireGuardPeer {
+	m.nodes.mu.RLock()
+	wg := m.nodes.opts.WG
+	timeout := m.nodes.opts.HandshakeTimeout
+	m.nodes.mu.RUnlock()
+	if wg == "" {
+		return nil
+	}
+	if _, err := exec.LookPath(wg); err != nil {
+		return nil
+	}
+	out, err := exec.CommandContext(ctx, wg, "show", "all", "dump").Output()
+	if err != nil {
+		return nil
+	}
+
+	// Peer lines: interface, public key, preshared key, endpoint, allowed
+	// IPs, latest handshake, rx, tx, keepalive; interface lines have 5 fields
+	var peers []WireGuardPeer
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 9 {
+			continue
+		}
+		peer := WireGuardPeer{Interface: fields[0], PublicKey: fields[1], Status: NodeDown, AllowedIPs: []string{}}
+		if fields[3] != "(none)" {
+			peer.Endpoint = fields[3]
+		}
+		if fields[4] != "(none)" {
+			peer.AllowedIPs = strings.Split(fields[4], ",")
+		}
+		if seconds, _ := strconv.ParseInt(fields[5], 10, 64); seconds > 0 {
+			handshake := time.Unix(seconds, 0)
+			age := time.Since(handshake).Seconds()
+			peer.LatestHandshake, peer.HandshakeAge = &handshake, &age
+			if time.Since(handshake) <= timeout {
+				peer.Status = NodeUp
+			}
+		}
+		peer.RxBytes, _ = strconv.ParseInt(fields[6], 10, 64)
+		peer.TxBytes, _ = strconv.ParseInt(fields[7], 10, 64)
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
+// peerOf returns the peer whose allowed IPs contain the address, preferring
+// the most specific prefix
+func peerOf(peers []WireGuardPeer, address string) *WireGuardPeer {
+	host, _, _ := net.SplitHostPort(address)
+	ips, err := net.LookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return nil
+	}
+
+	var best *WireGuardPeer
+	bestBits := -1
+	for i := range peers {
+		for _, allowed := range peers[i].AllowedIPs {
+			_, network, err := net.ParseCIDR(strings.TrimSpace(allowed))
+			if err != nil {
+				continue
+			}
+			bits, _ := network.Mask.Size()
+			for _, ip := range ips {
+				if network.Contains(ip) && bits > bestBits {
+					best, bestBits = &peers[i], bits
+				}
+			}
+		}
+	}
+	return best
+}
+
+// checkNode dials the SSH port of a node, timing the connection, and reads
+// the WireGuard peer routing it afterwards, as the dial itself triggers a
+// handshake on an idle tunnel
+func (m *Manager) checkNode(ctx context.Context, host, port string) (address string, latency *float64, tunnel *WireGuardPeer, err error) {
+	m.ssh.mu.RLock()
+	timeout := m.ssh.opts.ConnectTimeout
+	m.ssh.mu.RUnlock()
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	address = m.nodeAddress(ctx, host, port)
+	started := time.Now()
+	conn, dialErr := net.DialTimeout("tcp", address, timeout)
+	if dialErr == nil {
+		ms := float64(time.Since(started).Microseconds()) / 1000
+		latency = &ms
+		conn.Close()
+	}
+
+	tunnel = peerOf(m.wireGuardPeers(ctx), address)
+	if dialErr != nil {
+		err = dialErr
+		if tunnel != nil && tunnel.Status == NodeDown {
+			err = fmt.Errorf("WireGuard tunnel %s down (no handshake with peer %s): %v", tunnel.Interface, tunnel.PublicKey, dialErr)
+		}
+	}
+	return address, latency, tunnel, err
+}
+
+// MonitorNodes checks the hosts of SSH projects every interval. A node is
+// down after the configured number of consecutive failed checks: its
+// projects become "unreachable", distinct from stopped, and are left alone
+// by the SSH monitor. A node is up again on its first successful check,
+// which resyncs its projects with their remote processes. onChange is
+// called when a node changes status.
+func (m *Manager) MonitorNodes(interval time.Duration, onChange func(node Node)) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var projects []struct {
+			ID      uint
+			SSHHost string `gorm:"column:ssh_host"`
+		}
+		m.db.Table(tables.Name("projects")).Select("id, ssh_host").
+			Where("ssh_host <> '' AND deleted_at IS NULL").
+			Find(&projects)
+
+		hosts := make(map[string][]uint)
+		ports := make(map[string]string)
+		for _, p := range projects {
+			_, host, port := splitSSHHost(p.SSHHost)
+			hosts[host] = append(hosts[host], p.ID)
+			if port != "" {
+				ports[host] = port
+			}
+		}
+
+		m.nodes.mu.Lock()
+		if m.nodes.nodes == nil {
+			m.nodes.nodes = make(map[string]*Node)
+		}
+		for host := range m.nodes.nodes {
+			if _, ok := hosts[host]; !ok {
+				delete(m.nodes.nodes, host)
+			}
+		}
+		m.nodes.mu.Unlock()
+
+		for host, ids := range hosts {
+			if node, changed := m.updateNode(host, ports[host], ids); changed {
+				m.applyNodeStatus(node)
+				if onChange != nil {
+					onChange(node)
+				}
+			}
+		}
+
+		<-ticker.C
+	}
+}
+
+// updateNode checks a node and stores the result, reporting whether the
+// node changed status
+func (m *Manager) updateNode(host, port string, projectIDs []uint) (Node, bool) {
+	address, latency, tunnel, err := m.checkNode(context.Background(), host, port)
+
+	m.nodes.mu.Lock()
+	defer m.nodes.mu.Unlock()
+	node, ok := m.nodes.nodes[host]
+	if !ok {
+		node = &Node{Host: host, Status: NodeUnknown, Since: time.Now()}
+		m.nodes.nodes[host] = node
+	}
+	node.Address, node.LatencyMs, node.Tunnel = address, latency, tunnel
+	node.ProjectIDs = projectIDs
+	node.CheckedAt = time.Now()
+
+	status := node.Status
+	if err != nil {
+		node.Failures++
+		node.Error = err.Error()
+		if node.Failures >= m.nodes.opts.Failures {
+			status = NodeDown
+		}
+	} else {
+		node.Failures = 0
+		node.Error = ""
+		status = NodeUp
+	}
+	if status == node.Status {
+		return *node, false
+	}
+	previous := node.Status
+	node.Status, node.Since = status, node.CheckedAt
+	// A node seen for the first time changes nothing when up
+	return *node, status == NodeDown || previous == NodeDown
+}
+
+// applyNodeStatus marks the projects of a node that went down unreachable,
+// and resyncs them with their remote processes when it is back
+func (m *Manager) applyNodeStatus(node Node) {
+	if node.Status == NodeUp {
+		for _, id := range node.ProjectIDs {
+			m.CheckSSH(context.Background(), id)
+		}
+		return
+	}
+
+	// Stopped and failed projects stay as they were, the others may or may
+	// not run behind the dropped tunnel
+	var projects []struct {
+		ID     uint
+		Status string
+	}
+	m.db.Table(tables.Name("projects")).Select("id, status").
+		Where("id IN ? AND status NOT IN ?", node.ProjectIDs, []string{string(types.StatusStopped), string(types.StatusError), string(types.StatusUnreachable)}).
+		Find(&projects)
+	for _, p := range projects {
+		m.stopLogFollower(p.ID)
+		m.db.Table(tables.Name("projects")).Where("id = ?", p.ID).Update("status", string(types.StatusUnreachable))
+		m.RecordStatus(p.ID, string(types.StatusUnreachable), fmt.Sprintf("Node %s down: %s", node.Host, node.Error))
+	}
+}
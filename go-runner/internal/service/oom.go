@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// OOMKill is a process killed by the kernel OOM killer
+type OOMKill struct {
+	PID    int32     `json:"pid"`
+	Name   string    `json:"name"`
+	Time   time.Time `json:"time"`
+	RSS    uint64    `json:"rss"`    // Anonymous resident memory of the process when killed, bytes
+	Cgroup bool      `json:"cgroup"` // Killed for a cgroup memory limit rather than the host running out
+}
+
+// oomKillPattern matches the kernel line of a kill, e.g. "Out of memory:
+// Killed process 1234 (node) total-vm:..., anon-rss:1843200kB, ..." or
+// "Memory cgroup out of memory: Killed process ..."
+var oomKillPattern = regexp.MustCompile(`Killed process (\d+) \(([^)]*)\)(?:.*?anon-rss:(\d+)kB)?`)
+
+// describe explains a crash caused by the kill, err being how the main
+// process ended
+func (k OOMKill) describe(mainPID int32, err error) string {
+	what := "the kernel OOM killer"
+	if k.Cgroup {
+		what = "the OOM killer of its memory cgroup"
+	}
+	process := fmt.Sprintf("%s, PID %d", k.Name, k.PID)
+	if k.RSS > 0 {
+		process += fmt.Sprintf(", %d MiB resident", k.RSS>>20)
+	}
+	if k.PID == mainPID {
+		return fmt.Sprintf("Killed by %s (%s)", what, process)
+	}
+	return fmt.Sprintf("%v after %s killed a child process (%s)", err, what, process)
+}
+
+// parseOOMKill parses a kernel log line, ok is false when it is not a kill
+func parseOOMKill(line string, at time.Time) (OOMKill, bool) {
+	match := oomKillPattern.FindStringSubmatch(line)
+	if match == nil {
+		return OOMKill{}, false
+	}
+	pid, _ := strconv.ParseInt(match[1], 10, 32)
+	kill := OOMKill{
+		PID:    int32(pid),
+		Name:   match[2],
+		Time:   at,
+		Cgroup: strings.Contains(line, "cgroup"),
+	}
+	if match[3] != "" {
+		kb, _ := strconv.ParseUint(match[3], 10, 64)
+		kill.RSS = kb << 10
+	}
+	return kill, true
+}
+
+// KernelOOMKills returns the kills of the OOM killer logged by the kernel
+// since a time, read from the journal or else from dmesg. Only Linux has
+// them; reading dmesg may need root (kernel.dmesg_restrict).
+func KernelOOMKills(since time.Time) []OOMKill {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	if output, err := exec.Command("journalctl", "-k", "-q", "--no-pager", "-o", "short-unix",
+		"--since", fmt.Sprintf("@%d", since.Unix())).Output(); err == nil {
+		return parseJournalOOMKills(output, since)
+	}
+	output, err := exec.Command("dmesg").Output()
+	if err != nil {
+		return nil
+	}
+	return parseDmesgOOMKills(output, since)
+}
+
+// parseJournalOOMKills parses "1697460000.123456 host kernel: message" lines
+func parseJournalOOMKills(output []byte, since time.Time) []OOMKill {
+	var kills []OOMKill
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		stamp, message, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(stamp, 64)
+		if err != nil {
+			continue
+		}
+		at := time.Unix(0, int64(seconds*float64(time.Second)))
+		if kill, ok := parseOOMKill(message, at); ok && !at.Before(since) {
+			kills = append(kills, kill)
+		}
+	}
+	return kills
+}
+
+// parseDmesgOOMKills parses "[12345.678901] message" lines, timestamped in
+// seconds since boot
+func parseDmesgOOMKills(output []byte, since time.Time) []OOMKill {
+	boot, err := host.BootTime()
+	if err != nil {
+		return nil
+	}
+	var kills []OOMKill
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+		stamp, message, ok := strings.Cut(line[1:], "]")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(strings.TrimSpace(stamp), 64)
+		if err != nil {
+			continue
+		}
+		at := time.Unix(int64(boot), 0).Add(time.Duration(seconds * float64(time.Second)))
+		// The boot time is only precise to the second
+		if kill, ok := parseOOMKill(message, at); ok && !at.Before(since.Add(-time.Second)) {
+			kills = append(kills, kill)
+		}
+	}
+	return kills
+}
+
+// findOOMKill returns the last kill of the OOM killer of the main process of
+// a project or of a process of its tree at the last sample, nil when none of
+// them was killed since the start
+func (m *Manager) findOOMKill(projectID uint, mainPID int32, started time.Time) *OOMKill {
+	managed := map[int32]bool{mainPID: true}
+	m.procStats.mu.Lock()
+	for _, pid := range m.procStats.pids[projectID] {
+		managed[pid] = true
+	}
+	delete(m.procStats.pids, projectID)
+	m.procStats.mu.Unlock()
+
+	var found *OOMKill
+	for _, kill := range KernelOOMKills(started) {
+		if managed[kill.PID] {
+			kill := kill
+			found = &kill
+		}
+	}
+	return found
+}
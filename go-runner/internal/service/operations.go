@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// Lifecycle operation names tracked per project
+const (
+	OperationStart   = "start"
+	OperationStop    = "stop"
+	OperationRestart = "restart"
+)
+
+// Operation describes a lifecycle operation currently in flight for a project
+type Operation struct {
+	Name      string    `json:"name"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// OperationInProgressError is returned when a lifecycle operation is requested
+// while another one is still running for the same project
+type OperationInProgressError struct {
+	ProjectID uint
+	Requested string
+	Current   Operation
+}
+
+func (e *OperationInProgressError) Error() string {
+	return fmt.Sprintf("cannot %s service %d: %s already in progress since %s",
+		e.Requested, e.ProjectID, e.Current.Name, e.Current.StartedAt.Format(time.RFC3339))
+}
+
+// beginOperation marks an operation as in flight for a project.
+// It fails if another operation has not finished yet, so start/stop/restart
+// on the same project never overlap.
+func (m *Manager) beginOperation(projectID uint, name string) error {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	if current, exists := m.operations[projectID]; exists {
+		return &OperationInProgressError{
+			ProjectID: projectID,
+			Requested: name,
+			Current:   *current,
+		}
+	}
+
+	m.operations[projectID] = &Operation{
+		Name:      name,
+		StartedAt: time.Now(),
+	}
+	return nil
+}
+
+// endOperation clears the in-flight operation for a project and records
+// how long it took
+func (m *Manager) endOperation(projectID uint) {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	if current, exists := m.operations[projectID]; exists {
+		m.opTimings.record(current.Name, time.Since(current.StartedAt))
+	}
+	delete(m.operations, projectID)
+}
+
+// waitOperationDone waits up to timeout for the operation in flight for a
+// project to finish
+func (m *Manager) waitOperationDone(projectID uint, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for m.GetCurrentOperation(projectID) != nil && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// GetCurrentOperation returns the operation in flight for a project, or nil if idle
+func (m *Manager) GetCurrentOperation(projectID uint) *Operation {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	if current, exists := m.operations[projectID]; exists {
+		op := *current
+		return &op
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Variables set on every service process, so that processes left behind by
+// a stop or by a previous go-runner can be recognized
+const (
+	EnvProjectID = "GO_RUNNER_PROJECT_ID"
+	EnvSession   = "GO_RUNNER_SESSION"
+)
+
+// orphanTerminateGrace is how long an orphan has to exit after SIGTERM
+const orphanTerminateGrace = 5 * time.Second
+
+// Stray process reasons
+const (
+	StrayZombie          = "zombie"           // Exited, not yet reaped by its parent
+	StrayPreviousSession = "previous_session" // Started by a go-runner that is gone
+	StrayDetached        = "detached"         // Started by this go-runner, no longer under its project process
+	StrayReparented      = "reparented"       // Adopted by init, running in a project directory
+)
+
+// StrayProcess is a zombie or orphaned process
+type StrayProcess struct {
+	PID         int32     `json:"pid"`
+	PPID        int32     `json:"ppid"`
+	ParentName  string    `json:"parent_name,omitempty"`
+	Name        string    `json:"name"`
+	Command     string    `json:"command"`
+	Reason      string    `json:"reason"` // zombie, previous_session, detached, reparented
+	ProjectID   *uint     `json:"project_id,omitempty"`
+	ProjectName string    `json:"project_name,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	Cleanable   bool      `json:"cleanable"` // Zombies are reaped by their parent only
+}
+
+// OrphanReport lists the zombie and orphaned processes of the host
+type OrphanReport struct {
+	Zombies   []StrayProcess `json:"zombies"`
+	Orphans   []StrayProcess `json:"orphans"`
+	CheckedAt time.Time      `json:"checked_at"`
+}
+
+// OrphanCleanup is the result of terminating one orphan
+type OrphanCleanup struct {
+	PID    int32  `json:"pid"`
+	Action string `json:"action,omitempty"` // terminated, killed
+	Error  string `json:"error,omitempty"`
+}
+
+// orphanScans holds the last scan
+type orphanScans struct {
+	mu   sync.RWMutex
+	last *OrphanReport
+}
+
+// newSessionID identifies this go-runner run in the environment of services
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// sessionEnv tags the environment of a service process with its project and
+// this go-runner session
+func (m *Manager) sessionEnv(env []string, projectID uint) []string {
+	env = m.removeEnvVar(m.removeEnvVar(env, EnvProjectID), EnvSession)
+	return append(env, fmt.Sprintf("%s=%d", EnvProjectID, projectID), EnvSession+"="+m.session)
+}
+
+// LastOrphanScan returns the last periodic scan, if any
+func (m *Manager) LastOrphanScan() *OrphanReport {
+	m.orphans.mu.RLock()
+	defer m.orphans.mu.RUnlock()
+	return m.orphans.last
+}
+
+// MonitorOrphans scans for zombie and orphaned processes every interval and
+// calls onScan with each report
+func (m *Manager) MonitorOrphans(interval time.Duration, onScan func(report *OrphanReport)) {
+	for {
+		report, err := m.ScanOrphans()
+		if err == nil && onScan != nil {
+			onScan(report)
+		}
+		time.Sleep(m.pollInterval(interval))
+	}
+}
+
+// ScanOrphans lists zombie processes, and processes of services that are no
+// longer under a project process: marked with another session, marked with
+// this session but detached, or adopted by init in a project directory
+// (services started before the markers existed)
+func (m *Manager) ScanOrphans() (*OrphanReport, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []struct {
+		ID         uint
+		Name       string
+		Path       string
+		WorkingDir string
+	}
+	m.db.Table(tables.Name("projects")).Select("id, name, path, working_dir").Where("deleted_at IS NULL").Find(&projects)
+	names := make(map[uint]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+
+	managed := m.managedPIDs()
+	self := int32(os.Getpid())
+	report := &OrphanReport{Zombies: []StrayProcess{}, Orphans: []StrayProcess{}, CheckedAt: time.Now()}
+
+	for _, p := range procs {
+		if p.Pid == self || managed[p.Pid] {
+			continue
+		}
+		stray := StrayProcess{PID: p.Pid}
+		stray.PPID, _ = p.Ppid()
+
+		if status, err := p.Status(); err == nil && len(status) > 0 && status[0] == process.Zombie {
+			stray.Reason = StrayZombie
+			stray.Name, _ = p.Name()
+			if parent, err := process.NewProcess(stray.PPID); err == nil {
+				stray.ParentName, _ = parent.Name()
+			}
+			fillStrayProcess(p, &stray)
+			report.Zombies = append(report.Zombies, stray)
+			continue
+		}
+
+		var projectID uint
+		var session string
+		if env, err := p.Environ(); err == nil {
+			for _, kv := range env {
+				if value, ok := strings.CutPrefix(kv, EnvProjectID+"="); ok {
+					id, _ := strconv.ParseUint(value, 10, 32)
+					projectID = uint(id)
+				} else if value, ok := strings.CutPrefix(kv, EnvSession+"="); ok {
+					session = value
+				}
+			}
+		}
+
+		switch {
+		case projectID != 0 && session != m.session:
+			stray.Reason = StrayPreviousSession
+		case projectID != 0:
+			stray.Reason = StrayDetached
+		case stray.PPID == 1:
+			cwd, err := p.Cwd()
+			if err != nil {
+				continue
+			}
+			for _, project := range projects {
+				dir := project.Path
+				if project.WorkingDir != "" {
+					dir = project.WorkingDir
+				}
+				dir = filepath.Clean(dir)
+				if dir == "." || dir == string(filepath.Separator) {
+					continue // Would match any process
+				}
+				if cwd == dir || strings.HasPrefix(cwd, dir+string(filepath.Separator)) {
+					projectID = project.ID
+					break
+				}
+			}
+			if projectID == 0 {
+				continue
+			}
+			stray.Reason = StrayReparented
+		default:
+			continue
+		}
+
+		stray.ProjectID = &projectID
+		stray.ProjectName = names[projectID]
+		stray.Name, _ = p.Name()
+		stray.Cleanable = true
+		fillStrayProcess(p, &stray)
+		report.Orphans = append(report.Orphans, stray)
+	}
+
+	m.orphans.mu.Lock()
+	m.orphans.last = report
+	m.orphans.mu.Unlock()
+	return report, nil
+}
+
+// fillStrayProcess reads the command line and start time of a process
+func fillStrayProcess(p *process.Process, stray *StrayProcess) {
+	stray.Command, _ = p.Cmdline()
+	if created, err := p.CreateTime(); err == nil {
+		stray.StartedAt = time.UnixMilli(created)
+	}
+}
+
+// managedPIDs returns the process trees of the running services
+func (m *Manager) managedPIDs() map[int32]bool {
+	var roots []int32
+	m.mu.RLock()
+	for _, info := range m.processes {
+		if info.Process != nil && info.Process.Process != nil {
+			roots = append(roots, int32(info.Process.Process.Pid))
+		}
+	}
+	m.mu.RUnlock()
+
+	// Services detected running from their port have no process in memory
+	var pids []int
+	m.db.Table(tables.Name("projects")).Where("p_id > 0 AND deleted_at IS NULL").Pluck("p_id", &pids)
+	for _, pid := range pids {
+		roots = append(roots, int32(pid))
+	}
+
+	managed := make(map[int32]bool)
+	for len(roots) > 0 {
+		pid := roots[0]
+		roots = roots[1:]
+		if managed[pid] {
+			continue
+		}
+		managed[pid] = true
+		if p, err := process.NewProcess(pid); err == nil {
+			children, _ := p.Children()
+			for _, child := range children {
+				roots = append(roots, child.Pid)
+			}
+		}
+	}
+	return managed
+}
+
+// CleanupOrphans terminates orphans found by a fresh scan. When pids is empty
+// it terminates every orphan carrying the session markers; reparented ones
+// are only matched by directory and must be selected explicitly. Processes
+// that ignore SIGTERM are killed after a grace period.
+func (m *Manager) CleanupOrphans(pids []int32) ([]OrphanCleanup, error) {
+	report, err := m.ScanOrphans()
+	if err != nil {
+		return nil, err
+	}
+
+	selectAll := len(pids) == 0
+	orphans := make(map[int32]bool, len(report.Orphans))
+	for _, o := range report.Orphans {
+		orphans[o.PID] = o.Cleanable
+		if selectAll && o.Cleanable && o.Reason != StrayReparented {
+			pids = append(pids, o.PID)
+		}
+	}
+
+	results := make([]OrphanCleanup, len(pids))
+	var wg sync.WaitGroup
+	for i, pid := range pids {
+		results[i].PID = pid
+		if !orphans[pid] {
+			results[i].Error = "not an orphaned process"
+			continue
+		}
+		wg.Add(1)
+		go func(result *OrphanCleanup) {
+			defer wg.Done()
+			action, err := terminateProcess(result.PID)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Action = action
+		}(&results[i])
+	}
+	wg.Wait()
+
+	m.ScanOrphans()
+	return results, nil
+}
+
+// terminateProcess sends SIGTERM, then SIGKILL if the process outlives the
+// grace period
+func terminateProcess(pid int32) (string, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return "", err
+	}
+	if err := p.Terminate(); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(orphanTerminateGrace)
+	for time.Now().Before(deadline) {
+		if running, err := p.IsRunning(); err != nil || !running {
+			return "terminated", nil
+		}
+		if status, err := p.Status(); err == nil && len(status) > 0 && status[0] == process.Zombie {
+			return "terminated", nil // Exited, left for its parent to reap
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err := p.Kill(); err != nil {
+		return "", err
+	}
+	return "killed", nil
+}
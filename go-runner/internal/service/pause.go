@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ErrNotPausable is returned for a pause of a project without a local process
+var ErrNotPausable = errors.New("service has no local process to pause")
+
+// ErrAlreadyPaused is returned for a pause of a project already suspended
+var ErrAlreadyPaused = errors.New("service is already paused")
+
+// ServicePause is a service frozen with SIGSTOP
+type ServicePause struct {
+	PIDs     []int32    `json:"pids"` // Processes suspended: the service, its descendants and extra instances
+	PausedAt time.Time  `json:"paused_at"`
+	Until    *time.Time `json:"until,omitempty"` // Resumed automatically then; paused until resumed when empty
+
+	timer *time.Timer
+}
+
+// pauseState holds the paused projects
+type pauseState struct {
+	mu     sync.Mutex
+	paused map[uint]*ServicePause
+}
+
+// PauseService freezes a running project with SIGSTOP: its process, their
+// descendants and its extra instances, parents first. They keep their memory
+// and connections and continue with SIGCONT on ResumeService, or after
+// duration when it is not 0. The project status is paused meanwhile.
+func (m *Manager) PauseService(projectID uint, duration time.Duration) (*ServicePause, error) {
+	m.mu.RLock()
+	info, running := m.processes[projectID]
+	m.mu.RUnlock()
+	if !running {
+		return nil, ErrServiceNotRunning
+	}
+	if info.LogFollower || info.Process == nil || info.Process.Process == nil {
+		return nil, ErrNotPausable
+	}
+	pid := info.Process.Process.Pid
+
+	m.pauses.mu.Lock()
+	defer m.pauses.mu.Unlock()
+	if _, ok := m.pauses.paused[projectID]; ok || m.IsPowerPaused(projectID) {
+		return nil, ErrAlreadyPaused
+	}
+
+	pids := suspendProcessTree(int32(pid))
+	if len(pids) == 0 {
+		return nil, ErrNotPausable
+	}
+	for _, instancePID := range m.instancePIDs(projectID) {
+		pids = append(pids, suspendProcessTree(int32(instancePID))...)
+	}
+
+	pause := &ServicePause{PIDs: pids, PausedAt: time.Now()}
+	reason := "Paused (SIGSTOP)"
+	if duration > 0 {
+		until := pause.PausedAt.Add(duration)
+		pause.Until = &until
+		reason = fmt.Sprintf("Paused (SIGSTOP) for %s", duration)
+		pause.timer = time.AfterFunc(duration, func() {
+			if m.resumePause(projectID, pause, "Resumed after "+duration.String()) {
+				log.Printf("▶️ Resumed project %d after %s", projectID, duration)
+			}
+		})
+	}
+	if m.pauses.paused == nil {
+		m.pauses.paused = make(map[uint]*ServicePause)
+	}
+	m.pauses.paused[projectID] = pause
+
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(types.StatusPaused))
+	m.RecordStatus(projectID, string(types.StatusPaused), reason)
+	log.Printf("⏸️ %s project %d", reason, projectID)
+	return pause, nil
+}
+
+// ResumeService continues a project paused by PauseService and reports
+// whether it was paused
+func (m *Manager) ResumeService(projectID uint) bool {
+	m.pauses.mu.Lock()
+	pause := m.pauses.paused[projectID]
+	m.pauses.mu.Unlock()
+	return pause != nil && m.resumePause(projectID, pause, "Resumed (SIGCONT)")
+}
+
+// resumePause continues the processes of a pause, unless it already ended
+func (m *Manager) resumePause(projectID uint, pause *ServicePause, reason string) bool {
+	m.pauses.mu.Lock()
+	if m.pauses.paused[projectID] != pause {
+		m.pauses.mu.Unlock()
+		return false
+	}
+	delete(m.pauses.paused, projectID)
+	m.pauses.mu.Unlock()
+
+	if pause.timer != nil {
+		pause.timer.Stop()
+	}
+	resumeProcesses(pause.PIDs)
+
+	// Unless the process exited meanwhile, which set the status
+	result := m.db.Table(tables.Name("projects")).Where("id = ? AND status = ?", projectID, string(types.StatusPaused)).
+		Update("status", string(types.StatusRunning))
+	if result.RowsAffected > 0 {
+		m.RecordStatus(projectID, string(types.StatusRunning), reason)
+	}
+	return true
+}
+
+// endPause continues what is left of a paused project whose process exited,
+// leaving its status to the exit
+func (m *Manager) endPause(projectID uint) {
+	m.pauses.mu.Lock()
+	pause, ok := m.pauses.paused[projectID]
+	delete(m.pauses.paused, projectID)
+	m.pauses.mu.Unlock()
+
+	if ok {
+		if pause.timer != nil {
+			pause.timer.Stop()
+		}
+		resumeProcesses(pause.PIDs)
+	}
+}
+
+// Pause returns the pause of a project, nil when it is not paused
+func (m *Manager) Pause(projectID uint) *ServicePause {
+	m.pauses.mu.Lock()
+	defer m.pauses.mu.Unlock()
+	pause, ok := m.pauses.paused[projectID]
+	if !ok {
+		return nil
+	}
+	copied := *pause
+	return &copied
+}
+
+// resumeProcessTree continues a process and its descendants
+func resumeProcessTree(pid int32) {
+	root, err := process.NewProcess(pid)
+	if err != nil {
+		return
+	}
+	queue := []*process.Process{root}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		p.Resume()
+		children, _ := p.Children()
+		queue = append(queue, children...)
+	}
+}
+
+// instancePIDs returns the processes of the running extra instances of a
+// project
+func (m *Manager) instancePIDs(projectID uint) []int {
+	m.instances.mu.Lock()
+	defer m.instances.mu.Unlock()
+	var pids []int
+	for _, instance := range m.instances.projects[projectID] {
+		if instance.cmd != nil && instance.cmd.Process != nil {
+			pids = append(pids, instance.cmd.Process.Pid)
+		}
+	}
+	return pids
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// How long a stop waits for a cancelled start to give its operation back
+const pendingStartCancelWait = 15 * time.Second
+
+// ErrStartCancelled is returned by a start stopped while it was waiting for
+// its wait_for conditions or a start slot
+var ErrStartCancelled = errors.New("start cancelled")
+
+// pendingStart is a start that has not spawned its process yet and may wait
+// for its wait_for conditions or a start slot. Stopping the project cancels it.
+type pendingStart struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiting chan struct{} // Closed once the start waits
+	once    sync.Once
+}
+
+// pendingStarts holds the starts of projects not spawned yet
+type pendingStarts struct {
+	mu     sync.Mutex
+	active map[uint]*pendingStart
+}
+
+// markWaiting tells StartServiceAsync that the start waits, so that the
+// request can be answered
+func (s *pendingStart) markWaiting() {
+	s.once.Do(func() { close(s.waiting) })
+}
+
+// sleep waits for d and reports false when the start was cancelled first
+func (s *pendingStart) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// trackStart returns the pending start of a project, registering one when
+// there is none. done unregisters it when it was registered here.
+func (m *Manager) trackStart(projectID uint) (start *pendingStart, done func()) {
+	m.pending.mu.Lock()
+	defer m.pending.mu.Unlock()
+
+	if existing, ok := m.pending.active[projectID]; ok {
+		return existing, func() {}
+	}
+	if m.pending.active == nil {
+		m.pending.active = make(map[uint]*pendingStart)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	start = &pendingStart{ctx: ctx, cancel: cancel, waiting: make(chan struct{})}
+	m.pending.active[projectID] = start
+	return start, func() {
+		m.pending.mu.Lock()
+		if m.pending.active[projectID] == start {
+			delete(m.pending.active, projectID)
+		}
+		m.pending.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancelPendingStart cancels the start of a project that did not spawn its
+// process yet and reports whether there was one
+func (m *Manager) cancelPendingStart(projectID uint) bool {
+	m.pending.mu.Lock()
+	defer m.pending.mu.Unlock()
+
+	start, ok := m.pending.active[projectID]
+	if ok {
+		start.cancel()
+	}
+	return ok
+}
+
+// StartServiceAsync starts a microservice like StartService but returns as
+// soon as the start waits for its wait_for conditions or a start slot, with
+// pending true. The start then goes on in the background until it spawns the
+// process, fails or the project is stopped.
+func (m *Manager) StartServiceAsync(projectID uint) (pending bool, err error) {
+	if err := m.beginOperation(projectID, OperationStart); err != nil {
+		return false, err
+	}
+	start, done := m.trackStart(projectID)
+
+	result := make(chan error, 1)
+	go func() {
+		defer done()
+		defer m.endOperation(projectID)
+
+		err := m.startService(projectID)
+		if err == nil {
+			// A manual start gives auto_restart a fresh budget
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("restart_count", 0)
+		}
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		return false, err
+	case <-start.waiting:
+		go func() {
+			if err := <-result; err != nil && !errors.Is(err, ErrStartCancelled) {
+				log.Printf("❌ Start of project %d failed: %v", projectID, err)
+			}
+		}()
+		return true, nil
+	}
+}
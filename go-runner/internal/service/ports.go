@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// Declared port states reported by GetDeclaredPortStatuses
+const (
+	PortStatusListening = "listening"
+	PortStatusFree      = "free"
+	PortStatusUnchecked = "unchecked"
+)
+
+// DeclaredPortStatus reports whether a port declared by a project is bound
+type DeclaredPortStatus struct {
+	Name     string `json:"name"`
+	Number   int    `json:"number"`
+	Protocol string `json:"protocol"`
+	Public   bool   `json:"public"`
+	Status   string `json:"status"` // listening, free or unchecked
+}
+
+// declaredPort is a row of the project_ports table
+type declaredPort struct {
+	ProjectID uint
+	Name      string
+	Number    int
+	Protocol  string
+	Public    bool
+}
+
+// GetDeclaredPortStatuses checks every port declared by a project
+func (m *Manager) GetDeclaredPortStatuses(projectID uint) []DeclaredPortStatus {
+	var ports []declaredPort
+	m.db.Table(tables.Name("project_ports")).Where("project_id = ?", projectID).Order("number").Find(&ports)
+
+	statuses := make([]DeclaredPortStatus, 0, len(ports))
+	for _, p := range ports {
+		statuses = append(statuses, DeclaredPortStatus{
+			Name:     p.Name,
+			Number:   p.Number,
+			Protocol: p.Protocol,
+			Public:   p.Public,
+			Status:   m.declaredPortStatus(p.Number, p.Protocol),
+		})
+	}
+	return statuses
+}
+
+// declaredPortStatus checks whether a single TCP or UDP port is bound
+func (m *Manager) declaredPortStatus(number int, protocol string) string {
+	var inUse bool
+	switch protocol {
+	case "", string(types.ProtocolTCP):
+		inUse = m.isPortInUse(number)
+	case string(types.ProtocolUDP):
+		inUse = m.isUDPPortInUse(number)
+	default:
+		return PortStatusUnchecked
+	}
+
+	if inUse {
+		return PortStatusListening
+	}
+	return PortStatusFree
+}
+
+// portOwner identifies the project that declared a port
+type portOwner struct {
+	ProjectID   uint
+	ProjectName string
+	PortName    string
+}
+
+// AttachPortOwners fills in which project owns each port, based on the
+// project's primary port and its declared ports
+func (m *Manager) AttachPortOwners(ports []PortInfo) {
+	if len(ports) == 0 {
+		return
+	}
+
+	// Keyed by "number/protocol"
+	owners := make(map[string]portOwner)
+
+	var primary []struct {
+		ID   uint
+		Name string
+		Port int
+	}
+	m.db.Table(tables.Name("projects")).Select("id, name, port").
+		Where("deleted_at IS NULL AND port > 0").Find(&primary)
+	for _, p := range primary {
+		owners[portKey(p.Port, string(types.ProtocolTCP))] = portOwner{ProjectID: p.ID, ProjectName: p.Name}
+	}
+
+	// Declared ports take precedence since they carry a name
+	var declared []struct {
+		ProjectID   uint
+		ProjectName string
+		Name        string
+		Number      int
+		Protocol    string
+	}
+	m.db.Table(tables.From("project_ports")).
+		Select("project_ports.project_id, projects.name AS project_name, project_ports.name, project_ports.number, project_ports.protocol").
+		Joins("JOIN " + tables.From("projects") + " ON projects.id = project_ports.project_id AND projects.deleted_at IS NULL").
+		Find(&declared)
+	for _, p := range declared {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = string(types.ProtocolTCP)
+		}
+		owners[portKey(p.Number, protocol)] = portOwner{ProjectID: p.ProjectID, ProjectName: p.ProjectName, PortName: p.Name}
+	}
+
+	for i := range ports {
+		protocol := ports[i].Protocol
+		if protocol == "" {
+			protocol = string(types.ProtocolTCP)
+		}
+		if owner, ok := owners[portKey(ports[i].Port, protocol)]; ok {
+			projectID := owner.ProjectID
+			ports[i].ProjectID = &projectID
+			ports[i].ProjectName = owner.ProjectName
+			ports[i].PortName = owner.PortName
+		}
+	}
+}
+
+func portKey(number int, protocol string) string {
+	return fmt.Sprintf("%d/%s", number, protocol)
+}
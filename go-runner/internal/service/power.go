@@ -0,0 +1,287 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Low-power modes
+const (
+	PowerModeAuto = "auto" // Low power while the host runs on battery
+	PowerModeOn   = "on"   // Always low power
+	PowerModeOff  = "off"  // Never low power
+)
+
+// defaultPowerIntervalFactor is how much longer monitors wait in low-power mode
+const defaultPowerIntervalFactor = 4
+
+// PowerOptions configures low-power mode
+type PowerOptions struct {
+	Mode           string               // auto, on, off
+	IntervalFactor int                  // Monitor intervals are multiplied by this in low-power mode
+	OnBattery      func() (bool, error) // Whether the host runs on battery, for auto
+}
+
+// PowerStatus reports whether low-power mode is active
+type PowerStatus struct {
+	Mode           string     `json:"mode"`
+	LowPower       bool       `json:"low_power"`
+	OnBattery      *bool      `json:"on_battery,omitempty"` // Unset when the power source cannot be read
+	IntervalFactor int        `json:"interval_factor"`
+	Paused         []uint     `json:"paused"` // Optional projects suspended until low-power mode ends
+	Since          *time.Time `json:"since,omitempty"`
+	Error          string     `json:"error,omitempty"` // Why the power source could not be read
+}
+
+// powerState holds the low-power mode and the processes it suspended
+type powerState struct {
+	applyMu   sync.Mutex // Serializes evaluatePower
+	mu        sync.Mutex
+	opts      PowerOptions
+	lowPower  bool
+	onBattery *bool
+	err       string
+	since     time.Time
+	paused    map[uint][]int32 // Suspended processes per project
+	onChange  func(status PowerStatus)
+}
+
+// SetPowerOptions sets the low-power mode and how much it slows monitors
+func (m *Manager) SetPowerOptions(opts PowerOptions) {
+	if opts.Mode == "" {
+		opts.Mode = PowerModeAuto
+	}
+	if opts.IntervalFactor < 1 {
+		opts.IntervalFactor = defaultPowerIntervalFactor
+	}
+	m.power.mu.Lock()
+	m.power.opts = opts
+	m.power.mu.Unlock()
+}
+
+// SetPowerMode switches low-power mode on, off or back to following the
+// power source, and applies it right away
+func (m *Manager) SetPowerMode(mode string) (PowerStatus, error) {
+	switch mode {
+	case PowerModeAuto, PowerModeOn, PowerModeOff:
+	default:
+		return PowerStatus{}, fmt.Errorf("invalid power mode %q, expected auto, on or off", mode)
+	}
+	m.power.mu.Lock()
+	m.power.opts.Mode = mode
+	m.power.mu.Unlock()
+
+	m.evaluatePower()
+	return m.PowerStatus(), nil
+}
+
+// PowerStatus returns the current low-power mode
+func (m *Manager) PowerStatus() PowerStatus {
+	s := &m.power
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := PowerStatus{
+		Mode:           s.opts.Mode,
+		LowPower:       s.lowPower,
+		OnBattery:      s.onBattery,
+		IntervalFactor: s.opts.IntervalFactor,
+		Paused:         make([]uint, 0, len(s.paused)),
+		Error:          s.err,
+	}
+	if status.Mode == "" {
+		status.Mode = PowerModeAuto
+	}
+	if !s.since.IsZero() {
+		since := s.since
+		status.Since = &since
+	}
+	for projectID := range s.paused {
+		status.Paused = append(status.Paused, projectID)
+	}
+	sort.Slice(status.Paused, func(i, j int) bool { return status.Paused[i] < status.Paused[j] })
+	return status
+}
+
+// MonitorPower reads the power source every interval and enters or leaves
+// low-power mode. onChange is called each time it does.
+func (m *Manager) MonitorPower(interval time.Duration, onChange func(status PowerStatus)) {
+	m.power.mu.Lock()
+	m.power.onChange = onChange
+	m.power.mu.Unlock()
+
+	for {
+		m.evaluatePower()
+		time.Sleep(interval)
+	}
+}
+
+// evaluatePower reads the power source and applies the mode: entering
+// low-power mode suspends the running optional projects, leaving it resumes
+// them
+func (m *Manager) evaluatePower() {
+	s := &m.power
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+
+	s.mu.Lock()
+	opts := s.opts
+	s.mu.Unlock()
+
+	var onBattery *bool
+	var readErr string
+	if opts.OnBattery != nil {
+		if battery, err := opts.OnBattery(); err == nil {
+			onBattery = &battery
+		} else {
+			readErr = err.Error()
+		}
+	}
+
+	lowPower := opts.Mode == PowerModeOn || (opts.Mode != PowerModeOff && onBattery != nil && *onBattery)
+
+	s.mu.Lock()
+	s.onBattery, s.err = onBattery, readErr
+	changed := s.lowPower != lowPower
+	if changed {
+		s.lowPower = lowPower
+		s.since = time.Now()
+	}
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if lowPower {
+		log.Printf("🔋 Low-power mode on (mode %s), monitors slowed %dx", opts.Mode, opts.IntervalFactor)
+		m.pauseOptionalProjects()
+	} else {
+		log.Printf("🔌 Low-power mode off (mode %s)", opts.Mode)
+		m.resumePausedProjects()
+	}
+	if onChange != nil {
+		onChange(m.PowerStatus())
+	}
+}
+
+// pauseOptionalProjects suspends the process trees of running projects
+// marked optional
+func (m *Manager) pauseOptionalProjects() {
+	var ids []uint
+	m.db.Table(tables.Name("projects")).
+		Where("optional = ? AND status = ? AND deleted_at IS NULL", true, string(types.StatusRunning)).
+		Pluck("id", &ids)
+
+	for _, projectID := range ids {
+		m.mu.RLock()
+		info, ok := m.processes[projectID]
+		m.mu.RUnlock()
+		if !ok || info.LogFollower || info.Process == nil || info.Process.Process == nil {
+			continue // Kubernetes, systemd and followed projects are not ours to suspend
+		}
+
+		pids := suspendProcessTree(int32(info.Process.Process.Pid))
+		if len(pids) == 0 {
+			continue
+		}
+		m.power.mu.Lock()
+		if m.power.paused == nil {
+			m.power.paused = make(map[uint][]int32)
+		}
+		m.power.paused[projectID] = pids
+		m.power.mu.Unlock()
+		log.Printf("⏸️ Paused optional project %d for low-power mode", projectID)
+	}
+}
+
+// resumePausedProjects resumes every project suspended by low-power mode
+func (m *Manager) resumePausedProjects() {
+	m.power.mu.Lock()
+	paused := m.power.paused
+	m.power.paused = nil
+	m.power.mu.Unlock()
+
+	for projectID, pids := range paused {
+		resumeProcesses(pids)
+		log.Printf("▶️ Resumed project %d after low-power mode", projectID)
+	}
+}
+
+// resumePausedProject resumes one project suspended by low-power mode, so
+// that it can handle the signals of a stop
+func (m *Manager) resumePausedProject(projectID uint) {
+	m.power.mu.Lock()
+	pids, ok := m.power.paused[projectID]
+	delete(m.power.paused, projectID)
+	m.power.mu.Unlock()
+
+	if ok {
+		resumeProcesses(pids)
+	}
+}
+
+// IsPowerPaused reports whether a project is suspended by low-power mode
+func (m *Manager) IsPowerPaused(projectID uint) bool {
+	m.power.mu.Lock()
+	defer m.power.mu.Unlock()
+	_, ok := m.power.paused[projectID]
+	return ok
+}
+
+// PowerIntervalFactor returns how much longer monitors wait, 1 outside
+// low-power mode
+func (m *Manager) PowerIntervalFactor() int {
+	m.power.mu.Lock()
+	defer m.power.mu.Unlock()
+	if !m.power.lowPower || m.power.opts.IntervalFactor < 1 {
+		return 1
+	}
+	return m.power.opts.IntervalFactor
+}
+
+// pollInterval returns the wait of a monitor, lengthened in low-power mode
+func (m *Manager) pollInterval(interval time.Duration) time.Duration {
+	return interval * time.Duration(m.PowerIntervalFactor())
+}
+
+// suspendProcessTree stops a process and its descendants, parents first so
+// that they do not react to their children stopping, and returns the pids
+// suspended
+func suspendProcessTree(pid int32) []int32 {
+	root, err := process.NewProcess(pid)
+	if err != nil {
+		return nil
+	}
+
+	var suspended []int32
+	queue := []*process.Process{root}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if err := p.Suspend(); err == nil {
+			suspended = append(suspended, p.Pid)
+		}
+		children, _ := p.Children()
+		queue = append(queue, children...)
+	}
+	return suspended
+}
+
+// resumeProcesses continues suspended processes, children first; processes
+// that exited meanwhile are skipped
+func resumeProcesses(pids []int32) {
+	for i := len(pids) - 1; i >= 0; i-- {
+		if p, err := process.NewProcess(pids[i]); err == nil {
+			p.Resume()
+		}
+	}
+}
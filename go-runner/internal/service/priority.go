@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/tables"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Priority is the CPU (nice) and IO (ionice) scheduling priority a project
+// runs with, so that background workers do not starve interactive services,
+// and the CPU cores it is pinned to (taskset)
+type Priority struct {
+	Nice        int    `json:"nice"`         // -20 (highest) to 19 (lowest), 0 by default; below 0 needs root
+	IOClass     string `json:"io_class"`     // realtime (root only), best-effort or idle; empty for the default
+	IOPriority  int    `json:"io_priority"`  // 0 (highest) to 7 (lowest) within realtime and best-effort
+	CPUAffinity string `json:"cpu_affinity"` // CPU list such as "0-3,8"; empty for all cores
+}
+
+// ProcessPriority is the priority a process of a project runs with
+type ProcessPriority struct {
+	PID  int32  `json:"pid"`
+	Name string `json:"name"`
+	Nice *int   `json:"nice"`           // Null when it could not be read
+	IO   string `json:"io,omitempty"`   // As ionice reports it, e.g. "idle" or "best-effort: prio 7"
+	CPUs string `json:"cpus,omitempty"` // CPU list the process may run on, as taskset reports it
+}
+
+// PriorityStatus is the configured priority of a project and the one its
+// processes run with
+type PriorityStatus struct {
+	Priority
+	Running   bool              `json:"running"`
+	Processes []ProcessPriority `json:"processes"` // Main process first, then its descendants
+	Errors    []string          `json:"errors,omitempty"`
+}
+
+// ioClasses are the ionice classes by name
+var ioClasses = map[string]string{"": "0", "realtime": "1", "best-effort": "2", "idle": "3"}
+
+// priorityRecheckDelay is when the priority is applied again to the
+// processes a service started before it was set on its main process
+const priorityRecheckDelay = 5 * time.Second
+
+// IsDefault reports whether the priority leaves the scheduler defaults
+func (p Priority) IsDefault() bool {
+	return p.Nice == 0 && p.IOClass == "" && p.CPUAffinity == ""
+}
+
+// Validate checks the ranges of the priority
+func (p Priority) Validate() error {
+	if p.Nice < -20 || p.Nice > 19 {
+		return fmt.Errorf("nice must be between -20 and 19, got %d", p.Nice)
+	}
+	if _, ok := ioClasses[p.IOClass]; !ok {
+		return fmt.Errorf("io_class must be realtime, best-effort or idle, got %q", p.IOClass)
+	}
+	if p.IOPriority < 0 || p.IOPriority > 7 {
+		return fmt.Errorf("io_priority must be between 0 and 7, got %d", p.IOPriority)
+	}
+	if p.CPUAffinity != "" {
+		if _, err := ParseCPUList(p.CPUAffinity); err != nil {
+			return fmt.Errorf("cpu_affinity: %v", err)
+		}
+	}
+	return nil
+}
+
+// ParseCPUList parses a CPU list such as "0-3,8,10-11" into the CPU numbers,
+// which must exist on this host
+func ParseCPUList(list string) ([]int, error) {
+	var cpus []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(first)
+		if err != nil || from < 0 {
+			return nil, fmt.Errorf("invalid CPU %q, expected a list such as 0-3,8", part)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(last); err != nil || to < from {
+				return nil, fmt.Errorf("invalid CPU range %q", part)
+			}
+		}
+		if to >= runtime.NumCPU() {
+			return nil, fmt.Errorf("CPU %d does not exist, this host has CPUs 0-%d", to, runtime.NumCPU()-1)
+		}
+		for cpu := from; cpu <= to; cpu++ {
+			if !seen[cpu] {
+				seen[cpu] = true
+				cpus = append(cpus, cpu)
+			}
+		}
+	}
+	return cpus, nil
+}
+
+// setPriority applies the priority to processes with renice and ionice.
+// With reset, default values are applied too, to undo a previous priority.
+func setPriority(pids []int32, prio Priority, reset bool) error {
+	if len(pids) == 0 {
+		return nil
+	}
+	ids := make([]string, len(pids))
+	for i, pid := range pids {
+		ids[i] = strconv.Itoa(int(pid))
+	}
+
+	var errs []error
+	if prio.Nice != 0 || reset {
+		// The absolute form, as -n is an increment on BSD and macOS
+		args := append([]string{strconv.Itoa(prio.Nice), "-p"}, ids...)
+		if output, err := exec.Command("renice", args...).CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Errorf("renice: %s", commandError(output, err)))
+		}
+	}
+	if prio.IOClass != "" || reset {
+		args := []string{"-c", ioClasses[prio.IOClass]}
+		if prio.IOClass == "realtime" || prio.IOClass == "best-effort" {
+			args = append(args, "-n", strconv.Itoa(prio.IOPriority))
+		}
+		args = append(args, "-p")
+		args = append(args, ids...)
+		if output, err := exec.Command("ionice", args...).CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Errorf("ionice: %s", commandError(output, err)))
+		}
+	}
+	if prio.CPUAffinity != "" || reset {
+		cpus := prio.CPUAffinity
+		if cpus == "" {
+			cpus = fmt.Sprintf("0-%d", runtime.NumCPU()-1)
+		}
+		// taskset takes one process at a time; -a pins all its threads
+		for _, id := range ids {
+			if output, err := exec.Command("taskset", "-a", "-p", "-c", cpus, id).CombinedOutput(); err != nil {
+				errs = append(errs, fmt.Errorf("taskset: %s", commandError(output, err)))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func commandError(output []byte, err error) string {
+	if message := strings.TrimSpace(string(output)); message != "" {
+		return message
+	}
+	return err.Error()
+}
+
+// processTreePIDs returns a process and its descendants, the process first
+func processTreePIDs(pid int32) []int32 {
+	root, err := process.NewProcess(pid)
+	if err != nil {
+		return nil
+	}
+	var pids []int32
+	seen := make(map[int32]bool)
+	queue := []*process.Process{root}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p.Pid] {
+			continue
+		}
+		seen[p.Pid] = true
+		pids = append(pids, p.Pid)
+		children, _ := p.Children()
+		queue = append(queue, children...)
+	}
+	return pids
+}
+
+// projectPriority reads the configured priority of a project
+func (m *Manager) projectPriority(projectID uint) Priority {
+	var prio Priority
+	m.db.Table(tables.Name("projects")).Select("nice, io_class, io_priority, cpu_affinity").Where("id = ?", projectID).Take(&prio)
+	return prio
+}
+
+// localPID returns the main process of a project started by this server,
+// 0 when it is not running here
+func (m *Manager) localPID(projectID uint) int32 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	info, ok := m.processes[projectID]
+	if !ok || info.LogFollower || info.Process == nil || info.Process.Process == nil {
+		return 0
+	}
+	return int32(info.Process.Process.Pid)
+}
+
+// recheckPriority applies the priority of a project again to its whole
+// process tree a few seconds after its start, for the processes the main
+// process started before it was reniced
+func (m *Manager) recheckPriority(projectID uint, pid int32) {
+	time.Sleep(priorityRecheckDelay)
+	if m.localPID(projectID) != pid {
+		return
+	}
+	if prio := m.projectPriority(projectID); !prio.IsDefault() {
+		setPriority(processTreePIDs(pid), prio, false)
+	}
+}
+
+// SetPriority applies a priority to the running process tree of a project,
+// the defaults included so that a lowered priority can be undone. Nothing
+// is applied when the project is not running here.
+func (m *Manager) SetPriority(projectID uint, prio Priority) (*PriorityStatus, error) {
+	if err := prio.Validate(); err != nil {
+		return nil, err
+	}
+	var applyErr error
+	if pid := m.localPID(projectID); pid > 0 {
+		applyErr = setPriority(processTreePIDs(pid), prio, true)
+	}
+	status := m.PriorityStatus(projectID)
+	status.Priority = prio
+	if applyErr != nil {
+		status.Errors = append(status.Errors, strings.Split(applyErr.Error(), "\n")...)
+	}
+	return status, nil
+}
+
+// PriorityStatus returns the configured priority of a project and the one
+// of each process of its running tree
+func (m *Manager) PriorityStatus(projectID uint) *PriorityStatus {
+	status := &PriorityStatus{Priority: m.projectPriority(projectID), Processes: []ProcessPriority{}}
+	pid := m.localPID(projectID)
+	if pid == 0 {
+		return status
+	}
+	status.Running = true
+	for _, child := range processTreePIDs(pid) {
+		entry := ProcessPriority{PID: child}
+		if p, err := process.NewProcess(child); err == nil {
+			entry.Name, _ = p.Name()
+		}
+		if output, err := exec.Command("ps", "-o", "ni=", "-p", strconv.Itoa(int(child))).Output(); err == nil {
+			if nice, err := strconv.Atoi(strings.TrimSpace(string(output))); err == nil {
+				entry.Nice = &nice
+			}
+		}
+		if output, err := exec.Command("ionice", "-p", strconv.Itoa(int(child))).Output(); err == nil {
+			entry.IO = strings.TrimSpace(string(output))
+		}
+		// "pid 123's current affinity list: 0-3"
+		if output, err := exec.Command("taskset", "-p", "-c", strconv.Itoa(int(child))).Output(); err == nil {
+			if _, cpus, ok := strings.Cut(strings.TrimSpace(string(output)), ": "); ok {
+				entry.CPUs = cpus
+			}
+		}
+		status.Processes = append(status.Processes, entry)
+	}
+	return status
+}
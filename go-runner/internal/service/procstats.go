@@ -0,0 +1,271 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessSample is the resource use of the process tree of a running
+// project at one point in time
+type ProcessSample struct {
+	ProjectID  uint
+	Time       time.Time
+	CPUPercent *float64 // Of one core, since the previous sample; nil for the first sample of a process
+	MemoryRSS  uint64   // Bytes, whole process tree
+	Processes  int
+	DiskIO     ProcessIO
+	Network    *ProcessNetwork // Nil where sockets cannot be attributed to processes
+}
+
+// ProcessIO is the storage I/O of the process tree of a project: bytes read
+// from and written to disk, not served from or left in the page cache
+type ProcessIO struct {
+	ReadBytes        uint64   `json:"read_bytes"`          // Since the processes alive started
+	WriteBytes       uint64   `json:"write_bytes"`         // Since the processes alive started
+	ReadBytesPerSec  *float64 `json:"read_bytes_per_sec"`  // Since the previous sample; null for the first sample of a process
+	WriteBytesPerSec *float64 `json:"write_bytes_per_sec"` // Since the previous sample; null for the first sample of a process
+}
+
+// ProcessNetwork is the TCP traffic of the process tree of a project, summed
+// over the sockets its processes hold at each sample. Connections opened and
+// closed between two samples are missed, and so is the traffic of a
+// connection after its last sample.
+type ProcessNetwork struct {
+	SentBytes           uint64   `json:"sent_bytes"`             // Since the process was first sampled
+	ReceivedBytes       uint64   `json:"received_bytes"`         // Since the process was first sampled
+	SentBytesPerSec     *float64 `json:"sent_bytes_per_sec"`     // Since the previous sample; null for the first sample of a process
+	ReceivedBytesPerSec *float64 `json:"received_bytes_per_sec"` // Since the previous sample; null for the first sample of a process
+	Connections         int      `json:"connections"`            // TCP sockets open at the sample, listening ones included
+}
+
+// socketBytes is what went through a TCP socket since it was opened
+type socketBytes struct {
+	sent     uint64 // Acknowledged by the peer
+	received uint64
+}
+
+// procStats holds the CPU time of each project at its previous sample, and
+// that sample
+type procStats struct {
+	mu     sync.Mutex
+	last   map[uint]cpuReading
+	latest map[uint]ProcessSample
+	pids   map[uint][]int32 // Process tree at the last sample, kept after an exit to find OOM kills
+
+	sockets map[uint]map[uint32]socketBytes // TCP sockets of each project at its last sample
+	netErr  sync.Once                       // Logs once why sockets cannot be attributed
+}
+
+type cpuReading struct {
+	pid        int
+	seconds    float64 // User and system CPU time of the tree
+	readBytes  uint64
+	writeBytes uint64
+	netSent    uint64 // TCP bytes since the process was first sampled
+	netRecv    uint64
+	at         time.Time
+}
+
+// treeUsage is the resources used by a process and its descendants
+type treeUsage struct {
+	cpuSeconds float64
+	rss        uint64
+	readBytes  uint64 // Storage I/O, when /proc/<pid>/io can be read
+	writeBytes uint64
+	pids       []int32
+}
+
+// sampleProcessTree sums the CPU time, resident memory and storage I/O of a
+// process and its descendants; ok is false when the process is gone
+func sampleProcessTree(pid int) (usage treeUsage, ok bool) {
+	root, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return usage, false
+	}
+	seen := make(map[int32]bool)
+	queue := []*process.Process{root}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p.Pid] {
+			continue
+		}
+		seen[p.Pid] = true
+
+		times, err := p.Times()
+		if err != nil {
+			if p == root {
+				return treeUsage{}, false
+			}
+			continue // Exited meanwhile
+		}
+		usage.cpuSeconds += times.User + times.System
+		if mem, err := p.MemoryInfo(); err == nil {
+			usage.rss += mem.RSS
+		}
+		if io, err := p.IOCounters(); err == nil {
+			usage.readBytes += io.ReadBytes
+			usage.writeBytes += io.WriteBytes
+		}
+		usage.pids = append(usage.pids, p.Pid)
+
+		children, _ := p.Children()
+		queue = append(queue, children...)
+	}
+	return usage, true
+}
+
+// perSecond returns the rate of a counter between two readings, nil when it
+// went down, e.g. after a child process exited
+func perSecond(current, last uint64, elapsed time.Duration) *float64 {
+	if current < last {
+		return nil
+	}
+	rate := float64(current-last) / elapsed.Seconds()
+	return &rate
+}
+
+// LatestDiskIO returns the disk I/O of a project at its last process sample,
+// nil when it has none
+func (m *Manager) LatestDiskIO(projectID uint) *ProcessIO {
+	m.procStats.mu.Lock()
+	defer m.procStats.mu.Unlock()
+	sample, ok := m.procStats.latest[projectID]
+	if !ok {
+		return nil
+	}
+	io := sample.DiskIO
+	return &io
+}
+
+// LatestNetwork returns the TCP traffic of a project at its last process
+// sample, nil when it has none
+func (m *Manager) LatestNetwork(projectID uint) *ProcessNetwork {
+	m.procStats.mu.Lock()
+	defer m.procStats.mu.Unlock()
+	sample, ok := m.procStats.latest[projectID]
+	if !ok || sample.Network == nil {
+		return nil
+	}
+	network := *sample.Network
+	return &network
+}
+
+// attributeSockets sums what went through the sockets of a project since
+// their previous sample, or since they were opened for new ones
+func attributeSockets(inodes []uint32, counters, previous map[uint32]socketBytes) (sent, received uint64, current map[uint32]socketBytes) {
+	current = make(map[uint32]socketBytes, len(inodes))
+	for _, inode := range inodes {
+		bytes, ok := counters[inode]
+		if !ok {
+			continue // Not TCP
+		}
+		last := previous[inode]
+		if bytes.sent >= last.sent {
+			sent += bytes.sent - last.sent
+		}
+		if bytes.received >= last.received {
+			received += bytes.received - last.received
+		}
+		current[inode] = bytes
+	}
+	return sent, received, current
+}
+
+// MonitorProcessStats samples the CPU, memory, disk I/O and TCP traffic of
+// running projects every interval and calls onSample with each sample
+func (m *Manager) MonitorProcessStats(interval time.Duration, onSample func(ProcessSample)) {
+	for {
+		var projects []struct {
+			ID  uint
+			PID int `gorm:"column:p_id"`
+		}
+		m.db.Table(tables.Name("projects")).
+			Select("id, p_id").
+			Where("status = ? AND p_id > 0 AND deleted_at IS NULL", string(types.StatusRunning)).
+			Find(&projects)
+
+		// Every TCP socket of the host at once, attributed below by inode
+		var counters map[uint32]socketBytes
+		if len(projects) > 0 {
+			var err error
+			if counters, err = socketCounters(); err != nil {
+				m.procStats.netErr.Do(func() { log.Printf("Network usage of projects unavailable: %v", err) })
+			}
+		}
+
+		current := make(map[uint]cpuReading, len(projects))
+		latest := make(map[uint]ProcessSample, len(projects))
+		sockets := make(map[uint]map[uint32]socketBytes, len(projects))
+		var samples []ProcessSample
+		m.procStats.mu.Lock()
+		if m.procStats.pids == nil {
+			m.procStats.pids = make(map[uint][]int32)
+		}
+		for _, p := range projects {
+			usage, ok := sampleProcessTree(p.PID)
+			if !ok {
+				continue
+			}
+			m.procStats.pids[p.ID] = usage.pids
+			now := time.Now()
+			sample := ProcessSample{
+				ProjectID: p.ID,
+				Time:      now,
+				MemoryRSS: usage.rss,
+				Processes: len(usage.pids),
+				DiskIO:    ProcessIO{ReadBytes: usage.readBytes, WriteBytes: usage.writeBytes},
+			}
+			last, continued := m.procStats.last[p.ID]
+			continued = continued && last.pid == p.PID
+			reading := cpuReading{pid: p.PID, seconds: usage.cpuSeconds, readBytes: usage.readBytes, writeBytes: usage.writeBytes, at: now}
+			if counters != nil {
+				var previous map[uint32]socketBytes
+				if continued {
+					previous = m.procStats.sockets[p.ID]
+					reading.netSent, reading.netRecv = last.netSent, last.netRecv
+				}
+				sent, received, held := attributeSockets(socketInodes(usage.pids), counters, previous)
+				sockets[p.ID] = held
+				reading.netSent += sent
+				reading.netRecv += received
+				sample.Network = &ProcessNetwork{SentBytes: reading.netSent, ReceivedBytes: reading.netRecv, Connections: len(held)}
+				if continued && previous != nil && now.After(last.at) {
+					sentRate := float64(sent) / now.Sub(last.at).Seconds()
+					receivedRate := float64(received) / now.Sub(last.at).Seconds()
+					sample.Network.SentBytesPerSec = &sentRate
+					sample.Network.ReceivedBytesPerSec = &receivedRate
+				}
+			}
+			if continued && now.After(last.at) {
+				if usage.cpuSeconds >= last.seconds {
+					cpu := (usage.cpuSeconds - last.seconds) / now.Sub(last.at).Seconds() * 100
+					sample.CPUPercent = &cpu
+				}
+				sample.DiskIO.ReadBytesPerSec = perSecond(usage.readBytes, last.readBytes, now.Sub(last.at))
+				sample.DiskIO.WriteBytesPerSec = perSecond(usage.writeBytes, last.writeBytes, now.Sub(last.at))
+			}
+			current[p.ID] = reading
+			latest[p.ID] = sample
+			samples = append(samples, sample)
+		}
+		m.procStats.last = current
+		m.procStats.latest = latest
+		m.procStats.sockets = sockets
+		m.procStats.mu.Unlock()
+
+		if onSample != nil {
+			for _, sample := range samples {
+				onSample(sample)
+			}
+		}
+
+		time.Sleep(m.pollInterval(interval))
+	}
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// queueProbeTimeout bounds a single queue depth check
+const queueProbeTimeout = 10 * time.Second
+
+// QueueDepth is the backlog of one queue, stream consumer group or Kafka
+// consumer group / topic
+type QueueDepth struct {
+	Name      string `json:"name"`
+	Depth     int64  `json:"depth"` // Messages waiting, or consumer lag for streams and Kafka
+	Consumers int    `json:"consumers"`
+}
+
+// QueueStats is the result of a queue depth check
+type QueueStats struct {
+	Engine     string       `json:"engine"` // rabbitmq, redis, kafka
+	Healthy    bool         `json:"healthy"`
+	TotalDepth int64        `json:"total_depth"`
+	Queues     []QueueDepth `json:"queues"`
+	Error      string       `json:"error,omitempty"`
+	CheckedAt  time.Time    `json:"checked_at"`
+}
+
+// queueHealth keeps the last check of each queue project
+type queueHealth struct {
+	mu    sync.RWMutex
+	stats map[uint]*QueueStats
+}
+
+// QueueEngine returns the broker of a connection string: an amqp:// URL or
+// http:// management URL for RabbitMQ, a redis:// URL or a kafka:// bootstrap
+// server
+func QueueEngine(connString string) (string, error) {
+	u, err := url.Parse(connString)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "amqp", "amqps", "http", "https":
+		return "rabbitmq", nil
+	case "redis":
+		return "redis", nil
+	case "kafka":
+		return "kafka", nil
+	}
+	return "", fmt.Errorf("unsupported queue scheme %q", u.Scheme)
+}
+
+// ProbeQueues collects the depth of the named queues, or of all queues when
+// the broker can list them (RabbitMQ, Kafka consumer groups)
+func ProbeQueues(ctx context.Context, connString string, names []string) *QueueStats {
+	stats := &QueueStats{CheckedAt: time.Now(), Queues: []QueueDepth{}}
+
+	engine, err := QueueEngine(connString)
+	if err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+	stats.Engine = engine
+
+	ctx, cancel := context.WithTimeout(ctx, queueProbeTimeout)
+	defer cancel()
+
+	var queues []QueueDepth
+	switch engine {
+	case "rabbitmq":
+		queues, err = probeRabbitMQ(ctx, connString)
+	case "redis":
+		queues, err = probeRedisQueues(ctx, connString, names)
+	case "kafka":
+		queues, err = probeKafka(ctx, connString)
+	}
+	if err != nil {
+		stats.Error = err.Error()
+		return stats
+	}
+
+	for _, q := range queues {
+		if len(names) > 0 && !queueSelected(q.Name, names) {
+			continue
+		}
+		stats.Queues = append(stats.Queues, q)
+		stats.TotalDepth += q.Depth
+	}
+	sort.Slice(stats.Queues, func(i, j int) bool { return stats.Queues[i].Name < stats.Queues[j].Name })
+	stats.Healthy = true
+	return stats
+}
+
+// queueSelected reports whether a queue, or one part of a "group/topic"
+// name, is listed
+func queueSelected(name string, names []string) bool {
+	for _, n := range names {
+		if n == name || strings.HasPrefix(name, n+"/") || strings.HasSuffix(name, "/"+n) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeRabbitMQ lists queues through the management API. amqp:// URLs use
+// the management plugin on port 15672 (or ?management_port=) of the same
+// host, with the URL credentials (guest by default) and virtual host.
+func probeRabbitMQ(ctx context.Context, connString string) ([]QueueDepth, error) {
+	u, err := url.Parse(connString)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/api/queues"}
+	vhost := u.Query().Get("vhost")
+	if u.Scheme == "amqp" || u.Scheme == "amqps" {
+		endpoint.Scheme = "http"
+		if u.Scheme == "amqps" {
+			endpoint.Scheme = "https"
+		}
+		port := u.Query().Get("management_port")
+		if port == "" {
+			port = "15672"
+		}
+		endpoint.Host = net.JoinHostPort(u.Hostname(), port)
+		if path := strings.TrimPrefix(u.Path, "/"); path != "" {
+			vhost = path
+		}
+	}
+	if vhost != "" {
+		endpoint.Path += "/" + vhost
+		endpoint.RawPath = "/api/queues/" + url.PathEscape(vhost)
+	}
+	endpoint.RawQuery = "columns=name,messages,consumers"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	username, password := "guest", "guest"
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	req.SetBasicAuth(username, password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("management API returned %s", resp.Status)
+	}
+
+	var result []struct {
+		Name      string `json:"name"`
+		Messages  int64  `json:"messages"`
+		Consumers int    `json:"consumers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid management API response: %v", err)
+	}
+
+	queues := make([]QueueDepth, 0, len(result))
+	for _, q := range result {
+		queues = append(queues, QueueDepth{Name: q.Name, Depth: q.Messages, Consumers: q.Consumers})
+	}
+	return queues, nil
+}
+
+// probeRedisQueues measures lists (LLEN), sorted sets (ZCARD) and streams.
+// Streams report the lag of each consumer group as "stream/group", or their
+// length when they have no group.
+func probeRedisQueues(ctx context.Context, connString string, names []string) ([]QueueDepth, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("list the redis keys to watch in queues")
+	}
+
+	conn, err := dialRedis(ctx, connString)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var queues []QueueDepth
+	for _, name := range names {
+		kind, err := conn.String("TYPE", name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case "list":
+			n, err := conn.Int("LLEN", name)
+			if err != nil {
+				return nil, err
+			}
+			queues = append(queues, QueueDepth{Name: name, Depth: n})
+		case "zset":
+			n, err := conn.Int("ZCARD", name)
+			if err != nil {
+				return nil, err
+			}
+			queues = append(queues, QueueDepth{Name: name, Depth: n})
+		case "stream":
+			groups, err := redisStreamGroups(conn, name)
+			if err != nil {
+				return nil, err
+			}
+			if len(groups) == 0 {
+				n, err := conn.Int("XLEN", name)
+				if err != nil {
+					return nil, err
+				}
+				groups = append(groups, QueueDepth{Name: name, Depth: n})
+			}
+			queues = append(queues, groups...)
+		default:
+			// Missing keys are empty queues
+			queues = append(queues, QueueDepth{Name: name})
+		}
+	}
+	return queues, nil
+}
+
+// redisStreamGroups returns the lag of the consumer groups of a stream, or
+// their pending count on servers older than redis 7
+func redisStreamGroups(conn *redisConn, stream string) ([]QueueDepth, error) {
+	reply, err := conn.Do("XINFO", "GROUPS", stream)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := reply.([]interface{})
+
+	var groups []QueueDepth
+	for _, item := range items {
+		fields, _ := item.([]interface{})
+		group := QueueDepth{}
+		var lag, pending int64
+		hasLag := false
+		for i := 0; i+1 < len(fields); i += 2 {
+			key, _ := fields[i].(string)
+			switch key {
+			case "name":
+				name, _ := fields[i+1].(string)
+				group.Name = stream + "/" + name
+			case "consumers":
+				n, _ := fields[i+1].(int64)
+				group.Consumers = int(n)
+			case "pending":
+				pending, _ = fields[i+1].(int64)
+			case "lag":
+				lag, hasLag = fields[i+1].(int64)
+			}
+		}
+		group.Depth = pending
+		if hasLag {
+			group.Depth = lag
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// probeKafka reads consumer group lag with the kafka-consumer-groups tool
+// (kafka-consumer-groups or kafka-consumer-groups.sh on PATH)
+func probeKafka(ctx context.Context, connString string) ([]QueueDepth, error) {
+	u, err := url.Parse(connString)
+	if err != nil {
+		return nil, err
+	}
+
+	var tool string
+	for _, name := range []string{"kafka-consumer-groups", "kafka-consumer-groups.sh"} {
+		if path, err := exec.LookPath(name); err == nil {
+			tool = path
+			break
+		}
+	}
+	if tool == "" {
+		return nil, fmt.Errorf("kafka-consumer-groups not found on PATH")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool, "--bootstrap-server", u.Host, "--describe", "--all-groups")
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, firstLine(msg))
+		}
+		return nil, err
+	}
+	return parseKafkaConsumerGroups(output), nil
+}
+
+// parseKafkaConsumerGroups sums the lag per group and topic of
+// kafka-consumer-groups --describe output
+func parseKafkaConsumerGroups(output []byte) []QueueDepth {
+	byName := make(map[string]*QueueDepth)
+	consumers := make(map[string]map[string]bool)
+	var order []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		// GROUP TOPIC PARTITION CURRENT-OFFSET LOG-END-OFFSET LAG CONSUMER-ID HOST CLIENT-ID
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 || fields[0] == "GROUP" {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[2]); err != nil {
+			continue // Not a partition row
+		}
+
+		name := fields[0] + "/" + fields[1]
+		q, ok := byName[name]
+		if !ok {
+			q = &QueueDepth{Name: name}
+			byName[name] = q
+			consumers[name] = make(map[string]bool)
+			order = append(order, name)
+		}
+		if lag, err := strconv.ParseInt(fields[5], 10, 64); err == nil {
+			q.Depth += lag
+		}
+		if fields[6] != "-" {
+			consumers[name][fields[6]] = true
+		}
+	}
+
+	queues := make([]QueueDepth, 0, len(order))
+	for _, name := range order {
+		q := byName[name]
+		q.Consumers = len(consumers[name])
+		queues = append(queues, *q)
+	}
+	return queues
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// CheckQueues probes the broker of a project, records the result as its
+// health status and returns it
+func (m *Manager) CheckQueues(ctx context.Context, projectID uint, connString string, names []string) *QueueStats {
+	stats := ProbeQueues(ctx, connString, names)
+
+	health := "unhealthy"
+	if stats.Healthy {
+		health = "healthy"
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("health_status", health)
+
+	m.queues.mu.Lock()
+	if m.queues.stats == nil {
+		m.queues.stats = make(map[uint]*QueueStats)
+	}
+	m.queues.stats[projectID] = stats
+	m.queues.mu.Unlock()
+
+	return stats
+}
+
+// QueueStatus returns the last check of a queue project, if any
+func (m *Manager) QueueStatus(projectID uint) *QueueStats {
+	m.queues.mu.RLock()
+	defer m.queues.mu.RUnlock()
+	return m.queues.stats[projectID]
+}
+
+// SplitQueueNames splits the comma-separated queues setting of a project
+func SplitQueueNames(queues string) []string {
+	var names []string
+	for _, name := range strings.Split(queues, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// MonitorQueues checks the queue projects with a connection string every
+// interval and calls onCheck with each result
+func (m *Manager) MonitorQueues(interval time.Duration, onCheck func(projectID uint, stats *QueueStats)) {
+	for {
+		var projects []struct {
+			ID               uint
+			ConnectionString string
+			Queues           string
+		}
+		m.db.Table(tables.Name("projects")).
+			Select("id, connection_string, queues").
+			Where("type = ? AND connection_string <> '' AND deleted_at IS NULL", string(types.TypeQueue)).
+			Find(&projects)
+
+		for _, p := range projects {
+			stats := m.CheckQueues(context.Background(), p.ID, p.ConnectionString, SplitQueueNames(p.Queues))
+			if onCheck != nil {
+				onCheck(p.ID, stats)
+			}
+		}
+
+		time.Sleep(m.pollInterval(interval))
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// readyStableFor is how long a project without a port, socket or health
+// check must keep running after a start to count as ready
+const readyStableFor = 3 * time.Second
+
+// WaitReady waits until a project started by a restart is ready: its port
+// accepts connections, its socket_path listens and its health_check_url
+// answers below 400, whichever of them it has. A project with none of them
+// is ready once it kept running for a few seconds. It fails when the process
+// exits or the timeout passes first.
+func (m *Manager) WaitReady(projectID uint, timeout time.Duration) error {
+	var p struct {
+		Port           int
+		SocketPath     string
+		HealthCheckURL string
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("port, socket_path, health_check_url").
+		Where("id = ?", projectID).Take(&p).Error; err != nil {
+		return err
+	}
+	p.Port = m.ActivePort(projectID, p.Port)
+	var health *WaitCondition
+	if p.HealthCheckURL != "" {
+		if condition, err := parseWaitCondition(p.HealthCheckURL); err == nil && condition.Kind == "http" {
+			health = &condition
+		}
+	}
+
+	started := time.Now()
+	deadline := started.Add(timeout)
+	for {
+		var status string
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Pluck("status", &status)
+		if status == string(types.StatusError) || status == string(types.StatusStopped) {
+			var lastError string
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Pluck("last_error", &lastError)
+			if lastError != "" {
+				return fmt.Errorf("exited: %s", lastError)
+			}
+			return errors.New("exited before being ready")
+		}
+
+		var pending []string
+		if status != string(types.StatusRunning) {
+			pending = append(pending, "status "+status)
+		}
+		if p.Port > 0 && !m.isPortInUse(p.Port) {
+			pending = append(pending, fmt.Sprintf("port %d not listening", p.Port))
+		}
+		if p.SocketPath != "" && !m.isUnixSocketListening(p.SocketPath) {
+			pending = append(pending, p.SocketPath+" not listening")
+		}
+		if health != nil {
+			if err := health.Check(context.Background(), ""); err != nil {
+				pending = append(pending, fmt.Sprintf("health check: %v", err))
+			}
+		}
+		if p.Port <= 0 && p.SocketPath == "" && health == nil && time.Since(started) < readyStableFor {
+			pending = append(pending, "settling")
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("not ready after %s: %s", timeout, strings.Join(pending, "; "))
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// RestartOrder orders projects for a restart one after another, the projects
+// they depend on first, ties broken by ID. Projects caught in a dependency
+// cycle come last.
+func (m *Manager) RestartOrder(projectIDs []uint) ([]uint, error) {
+	var projects []autostartProject
+	if err := m.db.Table(tables.Name("projects")).Select("id, name, port, depends_on").
+		Where("deleted_at IS NULL").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	members := make(map[uint]bool, len(projectIDs))
+	for _, id := range projectIDs {
+		members[id] = true
+	}
+	// Flag the members so that autostartOrder selects them
+	for i := range projects {
+		projects[i].Autostart = members[projects[i].ID]
+	}
+
+	order, cyclic := autostartOrder(projects)
+	var ids []uint
+	for _, p := range append(order, cyclic...) {
+		if members[p.ID] {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids, nil
+}
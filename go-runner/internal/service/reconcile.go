@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Reconcile outcomes
+const (
+	ReconcileAdopted    = "adopted"    // Identity verified, monitored again
+	ReconcileUnverified = "unverified" // Still running, identity not confirmed: adopt or stop it
+	ReconcileStopped    = "stopped"    // Exited, or its PID now belongs to another process
+)
+
+// processIdentityDelay lets a service exec its final command (sh -c, npx)
+// before its command line is recorded
+const processIdentityDelay = 2 * time.Second
+
+// processStartTolerance absorbs rounding of process start times, which are
+// derived from the boot time
+const processStartTolerance = 2 * time.Second
+
+// ErrProcessMonitored is returned when adopting a process that is already monitored
+var ErrProcessMonitored = errors.New("the process of this project is already monitored")
+
+// ReconciledProcess is what happened to the process of one project that was
+// running when go-runner stopped
+type ReconciledProcess struct {
+	ProjectID   uint      `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	PID         int       `json:"pid"`
+	Result      string    `json:"result"` // adopted, unverified, stopped
+	Reason      string    `json:"reason,omitempty"`
+	Command     string    `json:"command,omitempty"`
+	LogFile     string    `json:"log_file,omitempty"` // Output file tailed again, logs are lost when output went to a pipe
+	Actions     []string  `json:"actions,omitempty"`  // adopt, stop
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// ReconcileSummary is the outcome of re-attaching to processes on startup
+type ReconcileSummary struct {
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt time.Time           `json:"finished_at"`
+	Results    []ReconciledProcess `json:"results"`
+}
+
+// reconcileState holds the last reconciliation
+type reconcileState struct {
+	mu   sync.RWMutex
+	last *ReconcileSummary
+}
+
+// commandHash fingerprints a command line
+func commandHash(argv []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(argv, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// recordProcessIdentity stores the start time and command hash of a started
+// process once it settled, so that it can be recognized after a restart
+func (m *Manager) recordProcessIdentity(processInfo *ProcessInfo) {
+	time.Sleep(processIdentityDelay)
+
+	m.mu.RLock()
+	current := m.processes[processInfo.ProjectID] == processInfo
+	m.mu.RUnlock()
+	if !current {
+		return
+	}
+	m.storeProcessIdentity(processInfo.ProjectID, processInfo.Process.Process.Pid)
+}
+
+// storeProcessIdentity stores the start time and command hash of a process
+func (m *Manager) storeProcessIdentity(projectID uint, pid int) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return
+	}
+	argv, _ := p.CmdlineSlice()
+	startedAt := time.UnixMilli(created)
+	m.db.Table(tables.Name("projects")).Where("id = ? AND p_id = ?", projectID, pid).Updates(map[string]interface{}{
+		"process_start": &startedAt,
+		"command_hash":  commandHash(argv),
+	})
+}
+
+// LastReconcile returns the outcome of the startup reconciliation, or nil if
+// it has not run yet
+func (m *Manager) LastReconcile() *ReconcileSummary {
+	m.reconcile.mu.RLock()
+	defer m.reconcile.mu.RUnlock()
+
+	if m.reconcile.last == nil {
+		return nil
+	}
+	// Results change as unverified processes are adopted or stopped
+	summary := *m.reconcile.last
+	summary.Results = append([]ReconciledProcess(nil), summary.Results...)
+	return &summary
+}
+
+// ReconcileProcesses re-attaches to the processes of projects recorded as
+// running, which keep running when go-runner restarts. A process whose PID,
+// start time and command line match what was recorded on start is adopted:
+// it is monitored again and its output tailed when it goes to a file. A
+// process that exited, or whose PID was reused, is marked stopped. Others,
+// such as processes detected from their port, are left running for the user
+// to adopt or stop. notify is called after each project.
+func (m *Manager) ReconcileProcesses(notify func(ReconciledProcess)) *ReconcileSummary {
+	summary := &ReconcileSummary{StartedAt: time.Now(), Results: []ReconciledProcess{}}
+
+	var projects []struct {
+		ID           uint
+		Name         string
+		Status       string
+		PID          int `gorm:"column:p_id"`
+		ProcessStart *time.Time
+		CommandHash  string
+	}
+	// Exits clear the PID, so error projects with one were set to error while
+	// their process kept running, e.g. by failing smoke tests
+	m.db.Table(tables.Name("projects")).
+		Select("id, name, status, p_id, process_start, command_hash").
+		Where("p_id > 0 AND status IN ? AND type <> ? AND (kube_deployment IS NULL OR kube_deployment = '') AND deleted_at IS NULL",
+			[]string{string(types.StatusRunning), string(types.StatusStarting), string(types.StatusStopping), string(types.StatusError), string(types.StatusPaused)}, string(types.TypeSystemd)).
+		Find(&projects)
+
+	for _, p := range projects {
+		result := ReconciledProcess{ProjectID: p.ID, ProjectName: p.Name, PID: p.PID}
+		result.Result, result.Reason = m.verifyProcess(p.ID, p.PID, p.ProcessStart, p.CommandHash)
+		if result.Result != ReconcileStopped {
+			result.Command = m.getProcessCommand(p.PID)
+		}
+
+		switch result.Result {
+		case ReconcileAdopted:
+			logFile, err := m.adoptProcess(p.ID, p.PID)
+			if err != nil {
+				result.Result, result.Reason = ReconcileUnverified, err.Error()
+				result.Actions = []string{"adopt", "stop"}
+				break
+			}
+			result.LogFile = logFile
+			// The pause is not kept across restarts
+			if p.Status == string(types.StatusPaused) {
+				resumeProcessTree(int32(p.PID))
+			}
+			m.db.Table(tables.Name("projects")).Where("id = ? AND status IN ?", p.ID, []string{string(types.StatusError), string(types.StatusPaused)}).
+				Updates(map[string]interface{}{"status": string(types.StatusRunning), "last_error": ""})
+			m.RecordStatus(p.ID, string(types.StatusRunning), fmt.Sprintf("Process re-attached after restart (PID %d)", p.PID))
+		case ReconcileStopped:
+			now := time.Now()
+			m.db.Table(tables.Name("projects")).Where("id = ?", p.ID).Updates(map[string]interface{}{
+				"status":    string(types.StatusStopped),
+				"stop_time": &now,
+				"p_id":      0,
+			})
+			m.RecordStatus(p.ID, string(types.StatusStopped), result.Reason)
+		default:
+			result.Actions = []string{"adopt", "stop"}
+		}
+
+		result.Timestamp = time.Now()
+		summary.Results = append(summary.Results, result)
+		if notify != nil {
+			notify(result)
+		}
+	}
+
+	summary.FinishedAt = time.Now()
+
+	m.reconcile.mu.Lock()
+	m.reconcile.last = summary
+	m.reconcile.mu.Unlock()
+
+	return summary
+}
+
+// verifyProcess checks that a PID still belongs to the process recorded for
+// a project, returning a reconcile outcome and its reason
+func (m *Manager) verifyProcess(projectID uint, pid int, startedAt *time.Time, hash string) (string, string) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return ReconcileStopped, "Process exited while go-runner was down"
+	}
+	if status, err := p.Status(); err == nil && len(status) > 0 && status[0] == process.Zombie {
+		return ReconcileStopped, "Process exited while go-runner was down"
+	}
+
+	if startedAt != nil {
+		if created, err := p.CreateTime(); err == nil {
+			if diff := time.UnixMilli(created).Sub(*startedAt); diff > processStartTolerance || diff < -processStartTolerance {
+				return ReconcileStopped, fmt.Sprintf("PID %d now belongs to another process", pid)
+			}
+		}
+	}
+	if env, err := p.Environ(); err == nil {
+		for _, kv := range env {
+			if value, ok := strings.CutPrefix(kv, EnvProjectID+"="); ok && value != strconv.FormatUint(uint64(projectID), 10) {
+				return ReconcileStopped, fmt.Sprintf("PID %d now belongs to project %s", pid, value)
+			}
+		}
+	}
+
+	if startedAt == nil || hash == "" {
+		return ReconcileUnverified, "No process identity was recorded (detected from its port, or started by an older go-runner)"
+	}
+	if argv, err := p.CmdlineSlice(); err != nil || commandHash(argv) != hash {
+		return ReconcileUnverified, "Command line differs from the one recorded on start"
+	}
+	return ReconcileAdopted, "Process identity verified"
+}
+
+// adoptProcess monitors a process that is not a child of this go-runner and
+// tails its output when it goes to a file. It returns the tailed file.
+func (m *Manager) adoptProcess(projectID uint, pid int) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.processes[projectID]; exists {
+		return "", ErrProcessMonitored
+	}
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return "", ErrServiceNotRunning
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	outputCtx, stopOutput := context.WithCancel(context.Background())
+	processInfo := &ProcessInfo{
+		ProjectID:  projectID,
+		Process:    &exec.Cmd{Process: proc},
+		Context:    ctx,
+		Cancel:     cancel,
+		StartTime:  time.Now(),
+		Logs:       make(chan LogEntry, 1000),
+		LogBuffer:  m.storedLogs(projectID),
+		Adopted:    true,
+		stopOutput: stopOutput,
+	}
+	if created, err := p.CreateTime(); err == nil {
+		processInfo.StartTime = time.UnixMilli(created)
+	}
+	m.processes[projectID] = processInfo
+
+	stdout := processOutputFile(pid, 1)
+	if stdout != "" {
+		if tail, err := openFileTail(outputCtx, stdout); err == nil {
+			go m.captureOutputWithBuffer(tail, processInfo, false)
+		}
+	}
+	if stderr := processOutputFile(pid, 2); stderr != "" && stderr != stdout {
+		if tail, err := openFileTail(outputCtx, stderr); err == nil {
+			go m.captureOutputWithBuffer(tail, processInfo, true)
+		}
+	}
+	m.startTailFiles(outputCtx, processInfo)
+	go m.monitorAdopted(processInfo, p)
+
+	return stdout, nil
+}
+
+// storedLogs returns the logs saved for a project, so that an adopted process
+// continues its log buffer
+func (m *Manager) storedLogs(projectID uint) []LogEntry {
+	var p struct {
+		Logs     string
+		LogTimes string
+	}
+	m.db.Table(tables.Name("projects")).Select("logs, log_times").Where("id = ?", projectID).Take(&p)
+
+	logs := make([]LogEntry, 0, 1000)
+	return append(logs, parseStoredLogs(p.Logs, p.LogTimes)...)
+}
+
+// monitorAdopted polls an adopted process, which cannot be waited for, and
+// handles its exit like monitorProcess
+func (m *Manager) monitorAdopted(processInfo *ProcessInfo, p *process.Process) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		// IsRunning also compares the start time, so a reused PID is not mistaken for it
+		if running, err := p.IsRunning(); err == nil && running {
+			if status, err := p.Status(); err != nil || len(status) == 0 || status[0] != process.Zombie {
+				continue
+			}
+		}
+		break
+	}
+
+	m.processExited(processInfo, nil)
+}
+
+// AdoptProcess monitors the recorded process of a project again after a
+// restart, whether or not its identity could be verified, and records its
+// identity for the next restart
+func (m *Manager) AdoptProcess(projectID uint) (*ReconciledProcess, error) {
+	var p struct {
+		Name string
+		PID  int `gorm:"column:p_id"`
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("name, p_id").Where("id = ?", projectID).Take(&p).Error; err != nil {
+		return nil, fmt.Errorf("project not found: %v", err)
+	}
+	if p.PID <= 0 {
+		return nil, ErrServiceNotRunning
+	}
+
+	logFile, err := m.adoptProcess(projectID, p.PID)
+	if err != nil {
+		return nil, err
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(types.StatusRunning))
+	m.storeProcessIdentity(projectID, p.PID)
+	m.RecordStatus(projectID, string(types.StatusRunning), fmt.Sprintf("Process adopted (PID %d)", p.PID))
+
+	result := ReconciledProcess{
+		ProjectID:   projectID,
+		ProjectName: p.Name,
+		PID:         p.PID,
+		Result:      ReconcileAdopted,
+		Reason:      "Adopted manually",
+		Command:     m.getProcessCommand(p.PID),
+		LogFile:     logFile,
+		Timestamp:   time.Now(),
+	}
+	m.settleReconcile(result)
+	return &result, nil
+}
+
+// settleReconcile replaces the unverified result of a project once it was
+// adopted or stopped
+func (m *Manager) settleReconcile(result ReconciledProcess) {
+	m.reconcile.mu.Lock()
+	defer m.reconcile.mu.Unlock()
+
+	if m.reconcile.last == nil {
+		return
+	}
+	for i, r := range m.reconcile.last.Results {
+		if r.ProjectID == result.ProjectID && r.Result == ReconcileUnverified {
+			if result.ProjectName == "" {
+				result.ProjectName = r.ProjectName
+			}
+			m.reconcile.last.Results[i] = result
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// redisConn is a minimal RESP client for health and queue checks
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialRedis connects to a redis:// URL, authenticating and selecting the
+// database of the URL path. The context deadline applies to the whole session.
+func dialRedis(ctx context.Context, connString string) (*redisConn, error) {
+	u, err := url.Parse(connString)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "6379")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+
+	if u.User != nil {
+		args := []interface{}{"AUTH"}
+		if password, ok := u.User.Password(); ok {
+			if u.User.Username() != "" {
+				args = append(args, u.User.Username())
+			}
+			args = append(args, password)
+		} else {
+			args = append(args, u.User.Username())
+		}
+		if _, err := c.Do(args...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if _, err := c.Do("SELECT", db); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Close closes the connection
+func (c *redisConn) Close() error {
+	return c.conn.Close()
+}
+
+// Do sends a command and returns its reply: a string, an int64, nil or a
+// []interface{} of replies
+func (c *redisConn) Do(args ...interface{}) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		s := fmt.Sprint(arg)
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(s), s)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// String sends a command whose reply is a string
+func (c *redisConn) String(args ...interface{}) (string, error) {
+	reply, err := c.Do(args...)
+	if err != nil {
+		return "", err
+	}
+	s, ok := reply.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected redis reply %v", reply)
+	}
+	return s, nil
+}
+
+// Int sends a command whose reply is an integer
+func (c *redisConn) Int(args ...interface{}) (int64, error) {
+	reply, err := c.Do(args...)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected redis reply %v", reply)
+	}
+	return n, nil
+}
+
+// read reads one reply
+func (c *redisConn) read() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := c.read()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"go-runner/internal/maintenance"
+	"go-runner/internal/tables"
+)
+
+// autoRestartDelay is how long a crashed service waits before it is restarted
+const autoRestartDelay = 2 * time.Second
+
+// scheduleAutoRestart restarts a crashed service flagged with auto_restart,
+// up to max_restarts times, unless a maintenance window pauses auto-restart
+func (m *Manager) scheduleAutoRestart(projectID uint) {
+	var p struct {
+		Name         string
+		AutoRestart  bool `gorm:"column:auto_restart"`
+		RestartCount int  `gorm:"column:restart_count"`
+		MaxRestarts  int  `gorm:"column:max_restarts"`
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("name, auto_restart, restart_count, max_restarts").
+		Where("id = ?", projectID).Take(&p).Error; err != nil || !p.AutoRestart {
+		return
+	}
+
+	if p.RestartCount >= p.MaxRestarts {
+		log.Printf("⚠️  %s crashed and reached max_restarts (%d), not restarting", p.Name, p.MaxRestarts)
+		return
+	}
+	if window := maintenance.PausingAutoRestart(m.db, projectID); window != nil {
+		log.Printf("⏸️  %s crashed, auto-restart paused by maintenance window %q", p.Name, window.Name)
+		return
+	}
+
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("restart_count", p.RestartCount+1)
+	log.Printf("🔄 %s crashed, restarting (%d/%d)", p.Name, p.RestartCount+1, p.MaxRestarts)
+
+	go func() {
+		time.Sleep(autoRestartDelay)
+		if err := m.beginOperation(projectID, OperationRestart); err != nil {
+			return
+		}
+		defer m.endOperation(projectID)
+
+		if err := m.startService(projectID); err != nil {
+			log.Printf("❌ Failed to restart %s: %v", p.Name, err)
+		}
+	}()
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// commitPattern matches a full git object name
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// GitRevision returns the commit checked out in the git repository holding
+// dir, read from .git without running git, or "" outside a repository
+func GitRevision(dir string) string {
+	gitDir := findGitDir(dir)
+	if gitDir == "" {
+		return ""
+	}
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	ref, isRef := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if !isRef {
+		return validCommit(ref) // Detached HEAD
+	}
+
+	// Worktrees keep their HEAD apart but share the refs
+	common := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common = resolvePath(gitDir, strings.TrimSpace(string(data)))
+	}
+	for _, base := range []string{gitDir, common} {
+		if data, err := os.ReadFile(filepath.Join(base, filepath.FromSlash(ref))); err == nil {
+			return validCommit(strings.TrimSpace(string(data)))
+		}
+	}
+	return packedRef(filepath.Join(common, "packed-refs"), ref)
+}
+
+// GitBranch returns the branch checked out in the git repository holding
+// dir, or "" outside a repository or on a detached HEAD
+func GitBranch(dir string) string {
+	gitDir := findGitDir(dir)
+	if gitDir == "" {
+		return ""
+	}
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	ref, isRef := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if !isRef {
+		return ""
+	}
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
+
+// GitRemoteURL returns the URL of a remote of the git repository holding
+// dir, read from its config, or "" when there is none
+func GitRemoteURL(dir, remote string) string {
+	gitDir := findGitDir(dir)
+	if gitDir == "" {
+		return ""
+	}
+	common := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common = resolvePath(gitDir, strings.TrimSpace(string(data)))
+	}
+	f, err := os.Open(filepath.Join(common, "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	section := `[remote "` + remote + `"]`
+	inSection := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSection = line == section
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && inSection && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// ShortRevision abbreviates a commit like git does by default
+func ShortRevision(revision string) string {
+	if len(revision) > 7 {
+		return revision[:7]
+	}
+	return revision
+}
+
+// findGitDir returns the .git directory of the repository holding dir,
+// following "gitdir:" files of worktrees and submodules
+func findGitDir(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ".git")
+		if info, err := os.Stat(path); err == nil {
+			if info.IsDir() {
+				return path
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return ""
+			}
+			if target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+				return resolvePath(dir, target)
+			}
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// packedRef looks a ref up in a packed-refs file
+func packedRef(path, ref string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if commit, name, ok := strings.Cut(scanner.Text(), " "); ok && name == ref {
+			return validCommit(commit)
+		}
+	}
+	return ""
+}
+
+func validCommit(s string) string {
+	if commitPattern.MatchString(s) {
+		return s
+	}
+	return ""
+}
+
+func resolvePath(base, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// RunningService is a service running now, from the processes of this
+// server and the projects the database knows as running
+type RunningService struct {
+	ProjectID     uint       `json:"project_id"`
+	Name          string     `json:"name"`
+	GroupID       *uint      `json:"group_id"`
+	Owner         string     `json:"owner,omitempty"`
+	Team          string     `json:"team,omitempty"`
+	Status        string     `json:"status"`
+	PID           int        `json:"pid"`
+	Port          int        `json:"port,omitempty"`
+	StartTime     *time.Time `json:"start_time"`
+	UptimeSeconds int64      `json:"uptime_seconds"`
+	CPUPercent    *float64   `json:"cpu_percent"` // Of one core at the last process sample, null until two samples were taken
+	MemoryBytes   uint64     `json:"memory_bytes"`
+	Processes     int        `json:"processes"` // Size of the local process tree, 0 for remote services
+	Managed       bool       `json:"managed"`   // Held in memory by this server, rather than only marked running in the database
+	Adopted       bool       `json:"adopted,omitempty"`
+	LogFollower   bool       `json:"log_follower,omitempty"` // Logs followed from Kubernetes or journald, not a process of this server
+	Remote        bool       `json:"remote,omitempty"`       // Runs on an SSH host
+
+	// Disk I/O of the process tree at the last process sample, null until
+	// two samples were taken
+	DiskReadBytesPerSec  *float64 `json:"disk_read_bytes_per_sec"`
+	DiskWriteBytesPerSec *float64 `json:"disk_write_bytes_per_sec"`
+
+	// TCP traffic of the process tree at the last process sample, null until
+	// two samples were taken or where it cannot be attributed (Linux only)
+	NetSentBytesPerSec     *float64 `json:"net_sent_bytes_per_sec"`
+	NetReceivedBytesPerSec *float64 `json:"net_received_bytes_per_sec"`
+}
+
+// runningProcess is what GetRunningServices keeps of a process of the
+// manager, copied under the lock
+type runningProcess struct {
+	pid         int
+	startTime   time.Time
+	adopted     bool
+	logFollower bool
+}
+
+// GetRunningServices returns the services running now, sorted by name: the
+// processes of this server, and the projects marked running in the database
+// whose local process is alive or which run elsewhere
+func (m *Manager) GetRunningServices() []RunningService {
+	m.mu.RLock()
+	processes := make(map[uint]runningProcess, len(m.processes))
+	ids := make([]uint, 0, len(m.processes))
+	for id, processInfo := range m.processes {
+		rp := runningProcess{startTime: processInfo.StartTime, adopted: processInfo.Adopted, logFollower: processInfo.LogFollower}
+		if processInfo.Process != nil && processInfo.Process.Process != nil && !processInfo.LogFollower {
+			rp.pid = processInfo.Process.Process.Pid
+		}
+		processes[id] = rp
+		ids = append(ids, id)
+	}
+	m.mu.RUnlock()
+
+	var projects []struct {
+		ID        uint
+		Name      string
+		GroupID   *uint
+		Owner     string
+		Team      string
+		Status    string
+		PID       int `gorm:"column:p_id"`
+		Port      int
+		StartTime *time.Time
+		SSHHost   string
+	}
+	query := m.db.Table(tables.Name("projects")).
+		Select("id, name, group_id, owner, team, status, p_id, port, start_time, ssh_host").
+		Where("deleted_at IS NULL")
+	if len(ids) > 0 {
+		query = query.Where("status = ? OR id IN ?", string(types.StatusRunning), ids)
+	} else {
+		query = query.Where("status = ?", string(types.StatusRunning))
+	}
+	if err := query.Find(&projects).Error; err != nil {
+		return []RunningService{}
+	}
+
+	// Last samples of MonitorProcessStats, with the process they were taken of
+	m.procStats.mu.Lock()
+	samples := make(map[uint]ProcessSample, len(m.procStats.latest))
+	sampledPIDs := make(map[uint]int, len(m.procStats.latest))
+	for id, sample := range m.procStats.latest {
+		samples[id] = sample
+		sampledPIDs[id] = m.procStats.last[id].pid
+	}
+	m.procStats.mu.Unlock()
+
+	now := time.Now()
+	services := make([]RunningService, 0, len(projects))
+	for _, p := range projects {
+		rp, managed := processes[p.ID]
+		service := RunningService{
+			ProjectID:   p.ID,
+			Name:        p.Name,
+			GroupID:     p.GroupID,
+			Owner:       p.Owner,
+			Team:        p.Team,
+			Status:      p.Status,
+			PID:         p.PID,
+			Port:        p.Port,
+			StartTime:   p.StartTime,
+			Managed:     managed,
+			Adopted:     rp.adopted,
+			LogFollower: rp.logFollower,
+			Remote:      p.SSHHost != "",
+		}
+		if managed {
+			if rp.pid > 0 {
+				service.PID = rp.pid
+			}
+			if !rp.startTime.IsZero() {
+				start := rp.startTime
+				service.StartTime = &start
+			}
+		}
+
+		// Local processes: skip those gone since, which the database or the
+		// process table may still hold, and take the resources of the tree
+		if !service.Remote && service.PID > 0 {
+			if sample, ok := samples[p.ID]; ok && sampledPIDs[p.ID] == service.PID {
+				service.CPUPercent = sample.CPUPercent
+				service.MemoryBytes = sample.MemoryRSS
+				service.Processes = sample.Processes
+				service.DiskReadBytesPerSec = sample.DiskIO.ReadBytesPerSec
+				service.DiskWriteBytesPerSec = sample.DiskIO.WriteBytesPerSec
+				if sample.Network != nil {
+					service.NetSentBytesPerSec = sample.Network.SentBytesPerSec
+					service.NetReceivedBytesPerSec = sample.Network.ReceivedBytesPerSec
+				}
+			} else if usage, ok := sampleProcessTree(service.PID); ok {
+				service.MemoryBytes = usage.rss
+				service.Processes = len(usage.pids)
+			} else if exists, _ := process.PidExists(int32(service.PID)); !exists {
+				continue
+			}
+		}
+
+		if service.StartTime != nil {
+			service.UptimeSeconds = int64(now.Sub(*service.StartTime).Seconds())
+		}
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Name != services[j].Name {
+			return services[i].Name < services[j].Name
+		}
+		return services[i].ProjectID < services[j].ProjectID
+	})
+	return services
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// Runtime environment variable statuses
+const (
+	RuntimeEnvUnchanged  = "unchanged"  // Process has the configured value
+	RuntimeEnvStale      = "stale"      // Process has an outdated value
+	RuntimeEnvMissing    = "missing"    // Configured but not in the process
+	RuntimeEnvExtra      = "extra"      // In the process but not configured (set by the service or a wrapper)
+	RuntimeEnvOverridden = "overridden" // Inherited value replaced by a wrapper (version manager shims, ...)
+)
+
+// Sources of a configured environment variable
+const (
+	EnvSourceSystem  = "system"   // Inherited from the go-runner environment
+	EnvSourceEnvFile = "env_file" // Project .env file
+	EnvSourceEnvVars = "env_vars" // Project env_vars JSON
+	EnvSourceDefault = "default"  // PORT / ENVIRONMENT set by go-runner
+)
+
+var (
+	// ErrServiceNotRunning is returned when an operation needs a running process
+	ErrServiceNotRunning = errors.New("service is not running")
+	// ErrRuntimeEnvUnsupported is returned where process environments cannot be read
+	ErrRuntimeEnvUnsupported = errors.New("reading the environment of a running process is only supported on Linux")
+)
+
+// shellManagedEnv are variables shells rewrite on startup; they always differ
+// when a service runs through a shell and are not reported as stale
+var shellManagedEnv = map[string]bool{"PWD": true, "OLDPWD": true, "SHLVL": true, "_": true}
+
+// RuntimeEnvVar compares one variable of the running process with the
+// configuration
+type RuntimeEnvVar struct {
+	Key        string  `json:"key"`
+	Status     string  `json:"status"`           // unchanged, stale, missing, extra, overridden
+	Source     string  `json:"source,omitempty"` // system, env_file, env_vars, default
+	Configured *string `json:"configured,omitempty"`
+	Actual     *string `json:"actual,omitempty"`
+}
+
+// RuntimeEnvSummary counts variables per status
+type RuntimeEnvSummary struct {
+	Unchanged  int `json:"unchanged"`
+	Stale      int `json:"stale"`
+	Missing    int `json:"missing"`
+	Extra      int `json:"extra"`
+	Overridden int `json:"overridden"`
+}
+
+// RuntimeEnvReport is the environment of a running process diffed against the
+// environment the project would be started with now
+type RuntimeEnvReport struct {
+	ProjectID       uint              `json:"project_id"`
+	PID             int               `json:"pid"`
+	StartTime       *time.Time        `json:"start_time,omitempty"`
+	EnvFile         string            `json:"env_file,omitempty"`
+	RestartRequired bool              `json:"restart_required"` // Some configured values are stale or missing
+	Summary         RuntimeEnvSummary `json:"summary"`
+	Variables       []RuntimeEnvVar   `json:"variables"`
+}
+
+// GetRuntimeEnv reads the environment of a project's running process and
+// diffs it against its configured environment (go-runner environment, .env
+// file, env_vars and defaults). Unchanged variables are only listed when
+// includeUnchanged is set.
+func (m *Manager) GetRuntimeEnv(projectID uint, includeUnchanged bool) (*RuntimeEnvReport, error) {
+	var p struct {
+		ID          uint
+		Name        string
+		GroupID     *uint
+		Path        string
+		Port        int
+		Environment string
+		EnvFile     string
+		EnvVars     string
+		StartTime   *time.Time
+	}
+	if err := m.db.Table(tables.Name("projects")).Where("id = ? AND deleted_at IS NULL", projectID).First(&p).Error; err != nil {
+		return nil, fmt.Errorf("project not found: %v", err)
+	}
+
+	if runtime.GOOS != "linux" {
+		return nil, ErrRuntimeEnvUnsupported
+	}
+	if !m.IsServiceRunning(projectID) {
+		return nil, ErrServiceNotRunning
+	}
+	pid := m.RunningPID(projectID)
+	if pid <= 0 {
+		return nil, ErrServiceNotRunning
+	}
+
+	actual, err := ProcessEnv(pid)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read environment of process %d: %v", pid, err)
+	}
+
+	// Build the environment exactly as a start would now
+	tmpl := m.buildTemplateContext(p.ID, p.Name, p.Path, p.Environment, p.Port, p.GroupID)
+	configured := make(map[string]string)
+	for _, entry := range m.prepareEnvironment(&struct {
+		Port        int
+		Environment string
+		EnvFile     string
+		EnvVars     string
+		Path        string
+		Template    *TemplateContext
+	}{
+		Port:        p.Port,
+		Environment: p.Environment,
+		EnvFile:     p.EnvFile,
+		EnvVars:     p.EnvVars,
+		Path:        p.Path,
+		Template:    tmpl,
+	}) {
+		if key, value, ok := strings.Cut(entry, "="); ok && key != "" {
+			configured[key] = value
+		}
+	}
+
+	envFile := ResolveEnvFilePath(p.EnvFile, p.Path)
+	fileVars := m.loadEnvFile(envFile)
+	jsonVars := m.parseEnvVarsJSON(p.EnvVars)
+	source := func(key string) string {
+		if _, ok := jsonVars[key]; ok {
+			return EnvSourceEnvVars
+		}
+		if _, ok := fileVars[key]; ok {
+			return EnvSourceEnvFile
+		}
+		if _, ok := os.LookupEnv(key); !ok && (key == "PORT" || key == "ENVIRONMENT") {
+			return EnvSourceDefault
+		}
+		return EnvSourceSystem
+	}
+
+	report := &RuntimeEnvReport{
+		ProjectID: p.ID,
+		PID:       pid,
+		StartTime: p.StartTime,
+		EnvFile:   envFile,
+		Variables: []RuntimeEnvVar{},
+	}
+
+	for key, want := range configured {
+		if shellManagedEnv[key] {
+			continue
+		}
+		want := want
+		v := RuntimeEnvVar{Key: key, Source: source(key), Configured: &want}
+		got, ok := actual[key]
+		if (!ok || got != want) && v.Source == EnvSourceSystem {
+			// go-runner's own environment has not changed since the start, so
+			// the service or a wrapper script replaced the value
+			v.Status = RuntimeEnvOverridden
+			if ok {
+				v.Actual = &got
+			}
+			report.Summary.Overridden++
+		} else if !ok {
+			v.Status = RuntimeEnvMissing
+			report.Summary.Missing++
+		} else if got != want {
+			v.Status = RuntimeEnvStale
+			v.Actual = &got
+			report.Summary.Stale++
+		} else {
+			v.Status = RuntimeEnvUnchanged
+			v.Actual = &got
+			report.Summary.Unchanged++
+			if !includeUnchanged {
+				continue
+			}
+		}
+		report.Variables = append(report.Variables, v)
+	}
+
+	for key, got := range actual {
+		if _, ok := configured[key]; ok || shellManagedEnv[key] {
+			continue
+		}
+		got := got
+		report.Variables = append(report.Variables, RuntimeEnvVar{Key: key, Status: RuntimeEnvExtra, Actual: &got})
+		report.Summary.Extra++
+	}
+
+	// Stale and missing first, then by key
+	rank := map[string]int{RuntimeEnvStale: 0, RuntimeEnvMissing: 1, RuntimeEnvOverridden: 2, RuntimeEnvExtra: 3, RuntimeEnvUnchanged: 4}
+	sort.Slice(report.Variables, func(i, j int) bool {
+		a, b := report.Variables[i], report.Variables[j]
+		if rank[a.Status] != rank[b.Status] {
+			return rank[a.Status] < rank[b.Status]
+		}
+		return a.Key < b.Key
+	})
+
+	report.RestartRequired = report.Summary.Stale > 0 || report.Summary.Missing > 0
+	return report, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ErrRemoteProcess is returned for projects whose process runs elsewhere:
+// over SSH or in Kubernetes
+var ErrRemoteProcess = errors.New("the process of this project does not run on this host")
+
+// RuntimeProcess is a process of the tree of a running project
+type RuntimeProcess struct {
+	PID        int32   `json:"pid"`
+	PPID       int32   `json:"ppid"`
+	Name       string  `json:"name"`
+	Command    string  `json:"command"`
+	Status     string  `json:"status,omitempty"` // e.g. running, sleep, zombie
+	Threads    int32   `json:"threads"`
+	OpenFiles  int32   `json:"open_files"`  // -1 when it could not be read
+	MemoryRSS  uint64  `json:"memory_rss"`  // Bytes
+	CPUPercent float64 `json:"cpu_percent"` // Average since the process started, of one core
+}
+
+// ListeningSocket is a socket a process of a project listens on
+type ListeningSocket struct {
+	PID      int32  `json:"pid"`
+	Process  string `json:"process"`
+	Protocol string `json:"protocol"` // tcp, tcp6, udp, udp6, unix
+	Address  string `json:"address"`  // IP, or path of unix sockets
+	Port     uint32 `json:"port,omitempty"`
+}
+
+// RuntimeInfo is a live look at the running process of a project
+type RuntimeInfo struct {
+	PID        int32     `json:"pid"`
+	Name       string    `json:"name"`
+	Command    string    `json:"command"`
+	Executable string    `json:"executable,omitempty"`
+	Cwd        string    `json:"cwd,omitempty"`
+	Username   string    `json:"username,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	Threads    int32     `json:"threads"`    // Main process
+	OpenFiles  int32     `json:"open_files"` // Main process, -1 when it could not be read
+	FileLimit  uint64    `json:"file_limit"` // Soft RLIMIT_NOFILE of the main process, 0 when unknown
+	// Environment of the main process, without the values
+	EnvironmentVars  int `json:"environment_vars"`
+	EnvironmentBytes int `json:"environment_bytes"`
+
+	Listening   []ListeningSocket `json:"listening"`   // Whole process tree
+	Connections map[string]int    `json:"connections"` // TCP connections of the tree by state, e.g. ESTABLISHED
+	Children    []RuntimeProcess  `json:"children"`    // Descendants, breadth first
+	Total       RuntimeProcess    `json:"total"`       // Sums over the tree; PID, names and status left empty
+
+	Errors    []string  `json:"errors,omitempty"` // Details that could not be read, e.g. without permission
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// GetRuntimeInfo reads live details of the running process of a project and
+// its descendants: listening sockets, connections, threads, open files, cwd,
+// environment size and child processes
+func (m *Manager) GetRuntimeInfo(projectID uint) (*RuntimeInfo, error) {
+	if m.sshTarget(projectID) != nil || m.kubeTarget(projectID) != nil {
+		return nil, ErrRemoteProcess
+	}
+	if !m.IsServiceRunning(projectID) {
+		return nil, ErrServiceNotRunning
+	}
+	pid := m.RunningPID(projectID)
+	if pid <= 0 {
+		return nil, ErrServiceNotRunning
+	}
+	root, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil, ErrServiceNotRunning
+	}
+
+	info := &RuntimeInfo{
+		PID:         root.Pid,
+		Listening:   []ListeningSocket{},
+		Connections: map[string]int{},
+		Children:    []RuntimeProcess{},
+		CheckedAt:   time.Now(),
+	}
+	addError := func(what string, err error) {
+		info.Errors = append(info.Errors, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	mainEntry := describeRuntimeProcess(root)
+	info.Name, info.Command, info.Threads, info.OpenFiles = mainEntry.Name, mainEntry.Command, mainEntry.Threads, mainEntry.OpenFiles
+	if created, err := root.CreateTime(); err == nil {
+		info.StartedAt = time.UnixMilli(created)
+	}
+	if info.Executable, err = root.Exe(); err != nil {
+		addError("executable", err)
+	}
+	if info.Cwd, err = root.Cwd(); err != nil {
+		addError("cwd", err)
+	}
+	info.Username, _ = root.Username()
+	if limits, err := root.Rlimit(); err == nil {
+		for _, limit := range limits {
+			if limit.Resource == process.RLIMIT_NOFILE {
+				info.FileLimit = limit.Soft
+			}
+		}
+	}
+	if environ, err := root.Environ(); err == nil {
+		info.EnvironmentVars = len(environ)
+		for _, entry := range environ {
+			info.EnvironmentBytes += len(entry) + 1
+		}
+	} else {
+		addError("environment", err)
+	}
+
+	// Breadth first over the tree, the main process included in the totals
+	seen := map[int32]bool{root.Pid: true}
+	tree := []*process.Process{root}
+	for i := 0; i < len(tree); i++ {
+		children, _ := tree[i].Children()
+		for _, child := range children {
+			if !seen[child.Pid] {
+				seen[child.Pid] = true
+				tree = append(tree, child)
+			}
+		}
+	}
+	for i, p := range tree {
+		entry := mainEntry
+		if i > 0 {
+			entry = describeRuntimeProcess(p)
+			info.Children = append(info.Children, entry)
+		}
+		info.Total.Threads += entry.Threads
+		info.Total.MemoryRSS += entry.MemoryRSS
+		info.Total.CPUPercent += entry.CPUPercent
+		if entry.OpenFiles > 0 {
+			info.Total.OpenFiles += entry.OpenFiles
+		}
+
+		connections, err := p.Connections()
+		if err != nil {
+			if i == 0 {
+				addError("sockets", err)
+			}
+			continue
+		}
+		for _, conn := range connections {
+			if socket, ok := listeningSocket(conn); ok {
+				socket.PID, socket.Process = p.Pid, entry.Name
+				info.Listening = append(info.Listening, socket)
+			} else if conn.Type == syscall.SOCK_STREAM && conn.Family != syscall.AF_UNIX && conn.Status != "" {
+				info.Connections[conn.Status]++
+			}
+		}
+	}
+	sort.SliceStable(info.Listening, func(i, j int) bool {
+		if info.Listening[i].Protocol != info.Listening[j].Protocol {
+			return info.Listening[i].Protocol < info.Listening[j].Protocol
+		}
+		return info.Listening[i].Port < info.Listening[j].Port
+	})
+	return info, nil
+}
+
+// describeRuntimeProcess reads the summary of one process
+func describeRuntimeProcess(p *process.Process) RuntimeProcess {
+	entry := RuntimeProcess{PID: p.Pid, OpenFiles: -1}
+	entry.PPID, _ = p.Ppid()
+	entry.Name, _ = p.Name()
+	entry.Command, _ = p.Cmdline()
+	if status, err := p.Status(); err == nil && len(status) > 0 {
+		entry.Status = status[0]
+	}
+	entry.Threads, _ = p.NumThreads()
+	if open, err := p.NumFDs(); err == nil {
+		entry.OpenFiles = open
+	}
+	if mem, err := p.MemoryInfo(); err == nil {
+		entry.MemoryRSS = mem.RSS
+	}
+	entry.CPUPercent, _ = p.CPUPercent()
+	return entry
+}
+
+// listeningSocket reports whether a socket listens: TCP in the LISTEN
+// state, UDP bound without a peer, and unix sockets bound to a path
+func listeningSocket(conn net.ConnectionStat) (ListeningSocket, bool) {
+	socket := ListeningSocket{Address: conn.Laddr.IP, Port: conn.Laddr.Port}
+	switch {
+	case conn.Family == syscall.AF_UNIX:
+		if conn.Laddr.IP == "" || conn.Status != "LISTEN" && conn.Type == syscall.SOCK_STREAM {
+			return socket, false
+		}
+		socket.Protocol, socket.Port = "unix", 0
+	case conn.Type == syscall.SOCK_STREAM && conn.Status == "LISTEN":
+		socket.Protocol = "tcp"
+	case conn.Type == syscall.SOCK_DGRAM && conn.Laddr.Port > 0 && conn.Raddr.Port == 0:
+		socket.Protocol = "udp"
+	default:
+		return socket, false
+	}
+	if conn.Family == syscall.AF_INET6 {
+		socket.Protocol += "6"
+	}
+	return socket, true
+}
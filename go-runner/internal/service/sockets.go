@@ -0,0 +1,304 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// UnixSocketInfo represents a listening Unix domain socket
+type UnixSocketInfo struct {
+	Path        string `json:"path"`
+	PID         int    `json:"pid"`
+	ProcessName string `json:"process_name"`
+	Command     string `json:"command"`
+	ProjectID   *uint  `json:"project_id,omitempty"` // Project declaring this socket path, if any
+	ProjectName string `json:"project_name,omitempty"`
+}
+
+// GetPortsInUse returns all listening TCP ports and bound UDP ports with
+// process information
+func (m *Manager) GetPortsInUse() ([]PortInfo, error) {
+	ports, err := m.getTCPPortsInUse()
+	if err != nil {
+		return ports, err
+	}
+	for i := range ports {
+		ports[i].Protocol = string(types.ProtocolTCP)
+	}
+
+	return append(ports, m.getUDPPortsInUse()...), nil
+}
+
+// isUDPPortInUse checks if a UDP port is bound
+func (m *Manager) isUDPPortInUse(port int) bool {
+	// lsof works on macOS and Linux
+	cmd := exec.Command("lsof", "-iUDP:"+strconv.Itoa(port), "-P", "-n")
+	if err := cmd.Run(); err == nil {
+		return true
+	}
+
+	// Fallback: ss / netstat (Linux)
+	for _, args := range [][]string{{"ss", "-uln"}, {"netstat", "-uln"}} {
+		output, err := exec.Command(args[0], args[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			for _, field := range strings.Fields(line) {
+				if strings.Contains(field, ":") && extractPortFromAddr(field) == port {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+// isUnixSocketListening checks if something accepts connections on a Unix socket
+func (m *Manager) isUnixSocketListening(path string) bool {
+	if path == "" {
+		return false
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// getUDPPortsInUse lists bound UDP ports
+func (m *Manager) getUDPPortsInUse() []PortInfo {
+	var ports []PortInfo
+	seen := make(map[string]bool)
+
+	output, err := exec.Command("lsof", "-iUDP", "-P", "-n").Output()
+	if err != nil {
+		return m.getUDPPortsFromSS()
+	}
+
+	// Format: COMMAND PID USER FD TYPE DEVICE SIZE/OFF NODE NAME
+	// Example: mDNSResponder 123 _mdns 8u IPv4 0x... 0t0 UDP *:5353
+	lines := strings.Split(string(output), "\n")
+	for i, line := range lines {
+		if i == 0 || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 9 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+
+		// Skip connected sockets (local->remote); only bound ones are "listening"
+		name := fields[len(fields)-1]
+		if strings.Contains(name, "->") {
+			continue
+		}
+		port := extractPortFromLsofName(name)
+		if port == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%d/%d", pid, port)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		ports = append(ports, PortInfo{
+			Port:        port,
+			PID:         pid,
+			ProcessName: fields[0],
+			User:        fields[2],
+			Command:     m.getProcessCommand(pid),
+			Status:      "BOUND",
+			Protocol:    string(types.ProtocolUDP),
+		})
+	}
+
+	return ports
+}
+
+// getUDPPortsFromSS is a fallback to list UDP ports using ss
+func (m *Manager) getUDPPortsFromSS() []PortInfo {
+	var ports []PortInfo
+
+	output, err := exec.Command("ss", "-ulnp").Output()
+	if err != nil {
+		return ports
+	}
+
+	// Format: State Recv-Q Send-Q Local:Port Peer:Port Process
+	lines := strings.Split(string(output), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 5 {
+			continue
+		}
+
+		port := extractPortFromAddr(fields[3])
+		if port == 0 {
+			continue
+		}
+
+		pid := 0
+		processName := "unknown"
+		if len(fields) >= 6 {
+			pid, processName = parseSSUsers(fields[5])
+		}
+
+		info := PortInfo{
+			Port:        port,
+			PID:         pid,
+			ProcessName: processName,
+			User:        "unknown",
+			Status:      "BOUND",
+			Protocol:    string(types.ProtocolUDP),
+		}
+		if pid > 0 {
+			info.Command = m.getProcessCommand(pid)
+		}
+		ports = append(ports, info)
+	}
+
+	return ports
+}
+
+// GetUnixSocketsInUse lists listening Unix domain sockets with a filesystem path
+func (m *Manager) GetUnixSocketsInUse() ([]UnixSocketInfo, error) {
+	sockets, err := m.getUnixSocketsFromSS()
+	if err != nil {
+		sockets, err = m.getUnixSocketsFromLsof()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Attach owning projects by declared socket path
+	var projects []struct {
+		ID         uint
+		Name       string
+		SocketPath string
+	}
+	m.db.Table(tables.Name("projects")).Select("id, name, socket_path").
+		Where("deleted_at IS NULL AND socket_path <> ''").Find(&projects)
+	for i := range sockets {
+		for _, p := range projects {
+			if p.SocketPath == sockets[i].Path {
+				projectID := p.ID
+				sockets[i].ProjectID = &projectID
+				sockets[i].ProjectName = p.Name
+				break
+			}
+		}
+	}
+
+	return sockets, nil
+}
+
+// getUnixSocketsFromSS lists Unix sockets using ss (Linux)
+func (m *Manager) getUnixSocketsFromSS() ([]UnixSocketInfo, error) {
+	output, err := exec.Command("ss", "-xlnp").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unix sockets: %v", err)
+	}
+
+	var sockets []UnixSocketInfo
+	// Format: Netid State Recv-Q Send-Q Local-Address Port Peer-Address Port Process
+	// Example: u_str LISTEN 0 4096 /run/app.sock 12345 * 0 users:(("app",pid=42,fd=3))
+	lines := strings.Split(string(output), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 5 {
+			continue
+		}
+
+		path := fields[4]
+		if !strings.HasPrefix(path, "/") {
+			continue // Abstract or unnamed socket
+		}
+
+		info := UnixSocketInfo{Path: path, ProcessName: "unknown"}
+		if len(fields) >= 9 {
+			info.PID, info.ProcessName = parseSSUsers(fields[8])
+		}
+		if info.PID > 0 {
+			info.Command = m.getProcessCommand(info.PID)
+		}
+		sockets = append(sockets, info)
+	}
+
+	return sockets, nil
+}
+
+// getUnixSocketsFromLsof lists Unix sockets using lsof (macOS fallback)
+func (m *Manager) getUnixSocketsFromLsof() ([]UnixSocketInfo, error) {
+	output, err := exec.Command("lsof", "-U", "-P", "-n").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unix sockets: %v", err)
+	}
+
+	var sockets []UnixSocketInfo
+	seen := make(map[string]bool)
+	lines := strings.Split(string(output), "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 8 {
+			continue
+		}
+
+		path := fields[len(fields)-1]
+		if !strings.HasPrefix(path, "/") || seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		pid, _ := strconv.Atoi(fields[1])
+		info := UnixSocketInfo{Path: path, PID: pid, ProcessName: fields[0]}
+		if pid > 0 {
+			info.Command = m.getProcessCommand(pid)
+		}
+		sockets = append(sockets, info)
+	}
+
+	return sockets, nil
+}
+
+// parseSSUsers extracts the PID and process name from an ss process column
+// Format: users:(("node",pid=1234,fd=20))
+func parseSSUsers(field string) (int, string) {
+	pid := 0
+	name := "unknown"
+
+	if start := strings.Index(field, "((\""); start != -1 {
+		rest := field[start+3:]
+		if end := strings.Index(rest, "\""); end != -1 {
+			name = rest[:end]
+		}
+	}
+	if idx := strings.Index(field, "pid="); idx != -1 {
+		rest := field[idx+4:]
+		if end := strings.IndexAny(rest, ",)"); end != -1 {
+			rest = rest[:end]
+		}
+		pid, _ = strconv.Atoi(rest)
+	}
+
+	return pid, name
+}
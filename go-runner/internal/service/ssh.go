@@ -0,0 +1,471 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// sshTimeout bounds the ssh calls that do not stream; a stop waits up to
+// sshStopGrace for the remote process to exit
+const (
+	sshTimeout   = 30 * time.Second
+	sshStopGrace = 10
+)
+
+// SSHOptions configures projects run on a remote host over SSH
+type SSHOptions struct {
+	Binary         string // ssh client
+	IdentityFile   string // Private key, ssh's default keys when empty
+	KnownHostsFile string // ssh's default when empty
+	HostKeyCheck   string // StrictHostKeyChecking: yes, accept-new or no
+	User           string // Login user of hosts given without one
+	ConnectTimeout time.Duration
+	StateDir       string // Remote directory of pid, exit and log files, relative to the home directory
+}
+
+// RemoteProcess is the observed state of the remote process of an SSH project
+type RemoteProcess struct {
+	Host      string    `json:"host"`
+	PID       int       `json:"pid,omitempty"` // On the remote host
+	ExitCode  *int      `json:"exit_code,omitempty"`
+	LogFile   string    `json:"log_file"` // On the remote host
+	Status    string    `json:"status"`   // Project status derived from the process
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// sshState holds the SSH options and the last observation per project
+type sshState struct {
+	mu       sync.RWMutex
+	opts     SSHOptions
+	observed map[uint]*RemoteProcess
+}
+
+// sshProject is what running a project remotely needs
+type sshProject struct {
+	Host        string
+	Name        string
+	Command     string
+	Args        string
+	Path        string
+	WorkingDir  string
+	Port        int
+	EnvVars     string
+	Environment string
+}
+
+// SetSSHOptions configures remote projects
+func (m *Manager) SetSSHOptions(opts SSHOptions) {
+	if opts.Binary == "" {
+		opts.Binary = "ssh"
+	}
+	if opts.StateDir == "" {
+		opts.StateDir = ".go-runner"
+	}
+	m.ssh.mu.Lock()
+	m.ssh.opts = opts
+	m.ssh.mu.Unlock()
+}
+
+// sshTarget returns the remote settings of an SSH project, or nil for
+// projects without ssh_host
+func (m *Manager) sshTarget(projectID uint) *sshProject {
+	var p sshProject
+	if err := m.db.Table(tables.Name("projects")).
+		Select("ssh_host AS host, name, command, args, path, working_dir, port, env_vars, environment").
+		Where("id = ?", projectID).Take(&p).Error; err != nil || p.Host == "" {
+		return nil
+	}
+	return &p
+}
+
+// SSHStatus returns the last observation of an SSH project, or nil
+func (m *Manager) SSHStatus(projectID uint) *RemoteProcess {
+	m.ssh.mu.RLock()
+	defer m.ssh.mu.RUnlock()
+	return m.ssh.observed[projectID]
+}
+
+// sshArgs builds the ssh arguments running a remote command on host, given
+// as [user@]host[:port] or a Host of the ssh config
+func (m *Manager) sshArgs(host, remoteCommand string) (string, []string) {
+	m.ssh.mu.RLock()
+	opts := m.ssh.opts
+	m.ssh.mu.RUnlock()
+
+	args := []string{"-o", "BatchMode=yes", "-o", "ServerAliveInterval=15"}
+	if opts.ConnectTimeout > 0 {
+		args = append(args, "-o", fmt.Sprintf("ConnectTimeout=%d", int(opts.ConnectTimeout.Seconds())))
+	}
+	if opts.HostKeyCheck != "" {
+		args = append(args, "-o", "StrictHostKeyChecking="+opts.HostKeyCheck)
+	}
+	if opts.KnownHostsFile != "" {
+		args = append(args, "-o", "UserKnownHostsFile="+opts.KnownHostsFile)
+	}
+	if opts.IdentityFile != "" {
+		args = append(args, "-i", opts.IdentityFile, "-o", "IdentitiesOnly=yes")
+	}
+
+	user, address, port := splitSSHHost(host)
+	if user == "" {
+		user = opts.User
+	}
+	if port != "" {
+		args = append(args, "-p", port)
+	}
+	if user != "" {
+		address = user + "@" + address
+	}
+	// The login shell of the remote user may not be sh
+	return opts.Binary, append(args, address, "sh -c "+shQuote(remoteCommand))
+}
+
+// splitSSHHost splits [user@]host[:port], host being a name, an IPv4 address
+// or a bracketed IPv6 address
+func splitSSHHost(destination string) (user, host, port string) {
+	if at := strings.LastIndex(destination, "@"); at >= 0 {
+		user, destination = destination[:at], destination[at+1:]
+	}
+	if h, p, err := net.SplitHostPort(destination); err == nil {
+		return user, h, p
+	}
+	return user, strings.Trim(destination, "[]"), ""
+}
+
+// ValidateSSHHost checks an ssh_host setting
+func ValidateSSHHost(destination string) error {
+	user, host, port := splitSSHHost(destination)
+	if host == "" || strings.ContainsAny(host, " \t/") || strings.HasPrefix(host, "-") {
+		return fmt.Errorf("invalid SSH host %q, expected [user@]host[:port]", destination)
+	}
+	if strings.ContainsAny(user, " \t:") || strings.HasPrefix(user, "-") {
+		return fmt.Errorf("invalid SSH user in %q", destination)
+	}
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("invalid SSH port in %q", destination)
+		}
+	}
+	return nil
+}
+
+// shQuote quotes s as a single sh word
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// remoteName is the project name usable as a remote file name
+var remoteName = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// remoteFiles returns the sh expressions of the pid, exit code and log files
+// of a project on the remote host
+func (m *Manager) remoteFiles(p *sshProject) (pid, exit, logFile string) {
+	m.ssh.mu.RLock()
+	dir := m.ssh.opts.StateDir
+	m.ssh.mu.RUnlock()
+
+	base := `"$HOME"/` + shQuote(strings.TrimSuffix(dir, "/")+"/"+strings.Trim(remoteName.ReplaceAllString(strings.ToLower(p.Name), "-"), "-"))
+	return base + ".pid", base + ".exit", base + ".log"
+}
+
+// runSSH runs a remote script and returns its output
+func (m *Manager) runSSH(ctx context.Context, host, script string) (string, error) {
+	name, args := m.sshArgs(host, script)
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("ssh client %s not found", name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sshTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("ssh %s: %s", host, lastLine(msg))
+		}
+		return stdout.String(), fmt.Errorf("ssh %s: %v", host, err)
+	}
+	return stdout.String(), nil
+}
+
+// lastLine returns the last line of s; ssh prints warnings before errors
+func lastLine(s string) string {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// startScript starts the project command detached from the SSH session,
+// in a session of its own so a stop reaches its children, and prints its
+// PID. A process still running from an earlier start is kept.
+func (m *Manager) startScript(p *sshProject) (string, error) {
+	command := strings.TrimSpace(p.Command + " " + p.Args)
+	if command == "" {
+		return "", errors.New("SSH projects need a command")
+	}
+	pidFile, exitFile, logFile := m.remoteFiles(p)
+
+	env := m.parseEnvVarsJSON(p.EnvVars)
+	if _, ok := env["PORT"]; !ok && p.Port > 0 {
+		env["PORT"] = strconv.Itoa(p.Port)
+	}
+	if _, ok := env["ENVIRONMENT"]; !ok && p.Environment != "" {
+		env["ENVIRONMENT"] = p.Environment
+	}
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var script strings.Builder
+	fmt.Fprintf(&script, "mkdir -p \"$(dirname %s)\" || exit 1\n", pidFile)
+	fmt.Fprintf(&script, "if [ -f %[1]s ] && kill -0 \"$(cat %[1]s)\" 2>/dev/null; then cat %[1]s; exit 0; fi\n", pidFile)
+	if dir := p.WorkingDir; dir != "" || p.Path != "" {
+		if dir == "" {
+			dir = p.Path
+		}
+		fmt.Fprintf(&script, "cd %s || exit 1\n", shQuote(dir))
+	}
+	for _, key := range keys {
+		if validEnvName.MatchString(key) {
+			fmt.Fprintf(&script, "export %s=%s\n", key, shQuote(env[key]))
+		}
+	}
+	fmt.Fprintf(&script, "rm -f %s\n", exitFile)
+	// The wrapper records the exit code, telling a crash from a stop
+	wrapper := shQuote(command + "\necho $? > " + exitFile)
+	fmt.Fprintf(&script, "echo \"--- started $(date) ---\" >> %s\n", logFile)
+	fmt.Fprintf(&script, "if command -v setsid >/dev/null 2>&1; then nohup setsid sh -c %[1]s >> %[2]s 2>&1 < /dev/null &\nelse nohup sh -c %[1]s >> %[2]s 2>&1 < /dev/null &\nfi\n", wrapper, logFile)
+	fmt.Fprintf(&script, "echo $! > %s\necho $!\n", pidFile)
+	return script.String(), nil
+}
+
+// validEnvName matches the variable names sh can export
+var validEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// stopScript signals the process group of the project, or the process when
+// setsid was missing, and kills it after grace seconds
+func (m *Manager) stopScript(p *sshProject, signal string, grace int) string {
+	pidFile, _, _ := m.remoteFiles(p)
+	return fmt.Sprintf(`[ -f %[1]s ] || exit 0
+p=$(cat %[1]s)
+kill -s %[2]s -- "-$p" 2>/dev/null || kill -s %[2]s "$p" 2>/dev/null
+i=0
+while kill -0 "$p" 2>/dev/null && [ $i -lt %[3]d ]; do sleep 1; i=$((i+1)); done
+if kill -0 "$p" 2>/dev/null; then kill -s KILL -- "-$p" 2>/dev/null || kill -s KILL "$p"; fi
+rm -f %[1]s
+`, pidFile, signal, grace)
+}
+
+// statusScript prints "running <pid>", "exited <code>" or "stopped"
+func (m *Manager) statusScript(p *sshProject) string {
+	pidFile, exitFile, _ := m.remoteFiles(p)
+	return fmt.Sprintf(`if [ -f %[1]s ] && kill -0 "$(cat %[1]s)" 2>/dev/null; then echo "running $(cat %[1]s)"
+elif [ -f %[1]s ]; then echo "exited $(cat %[2]s 2>/dev/null || echo unknown)"
+else echo stopped; fi
+`, pidFile, exitFile)
+}
+
+// observeSSH reads the state of the remote process of a project
+func (m *Manager) observeSSH(ctx context.Context, p *sshProject) *RemoteProcess {
+	_, _, logFile := m.remoteFiles(p)
+	r := &RemoteProcess{
+		Host:      p.Host,
+		LogFile:   strings.ReplaceAll(strings.ReplaceAll(logFile, `"$HOME"/`, "~/"), "'", ""),
+		Status:    string(types.StatusUnknown),
+		CheckedAt: time.Now(),
+	}
+
+	out, err := m.runSSH(ctx, p.Host, m.statusScript(p))
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	state, value, _ := strings.Cut(strings.TrimSpace(lastLine(strings.TrimSpace(out))), " ")
+	switch state {
+	case "running":
+		r.Status = string(types.StatusRunning)
+		r.PID, _ = strconv.Atoi(value)
+	case "exited":
+		r.Status = string(types.StatusStopped)
+		if code, err := strconv.Atoi(value); err == nil {
+			r.ExitCode = &code
+			if code != 0 {
+				r.Status = string(types.StatusError)
+				r.Error = fmt.Sprintf("remote process exited with code %d", code)
+			}
+		} else {
+			r.Status = string(types.StatusError)
+			r.Error = "remote process ended without an exit code"
+		}
+	case "stopped":
+		r.Status = string(types.StatusStopped)
+	default:
+		r.Error = "unexpected status output: " + strings.TrimSpace(out)
+	}
+	return r
+}
+
+// CheckSSH observes the remote process of an SSH project, stores the result
+// and syncs the project status with it
+func (m *Manager) CheckSSH(ctx context.Context, projectID uint) (*RemoteProcess, error) {
+	target := m.sshTarget(projectID)
+	if target == nil {
+		return nil, fmt.Errorf("project %d has no ssh_host", projectID)
+	}
+
+	r := m.observeSSH(ctx, target)
+	m.ssh.mu.Lock()
+	if m.ssh.observed == nil {
+		m.ssh.observed = make(map[uint]*RemoteProcess)
+	}
+	m.ssh.observed[projectID] = r
+	m.ssh.mu.Unlock()
+
+	// An unreachable host says nothing about the process
+	if r.Status == string(types.StatusUnknown) {
+		return r, nil
+	}
+
+	var current struct{ Status string }
+	m.db.Table(tables.Name("projects")).Select("status").Where("id = ?", projectID).Take(&current)
+	updates := map[string]interface{}{"status": r.Status}
+	if r.Status == string(types.StatusError) {
+		updates["last_error"] = r.Error
+	}
+	if r.Status != string(types.StatusRunning) && current.Status == string(types.StatusRunning) {
+		now := time.Now()
+		updates["stop_time"] = &now
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(updates)
+	if current.Status != r.Status {
+		reason := fmt.Sprintf("Remote process on %s %s", r.Host, r.Status)
+		if r.Error != "" {
+			reason += ": " + r.Error
+		}
+		m.RecordStatus(projectID, r.Status, reason)
+	}
+
+	if r.Status == string(types.StatusRunning) {
+		m.followRemoteLog(projectID, target)
+	} else {
+		m.stopLogFollower(projectID)
+	}
+	return r, nil
+}
+
+// followRemoteLog tails the remote log file into the project logs
+func (m *Manager) followRemoteLog(projectID uint, p *sshProject) {
+	_, _, logFile := m.remoteFiles(p)
+	name, args := m.sshArgs(p.Host, "tail -n 100 -F "+logFile)
+	if err := m.followLogs(projectID, name, args...); err != nil {
+		log.Printf("Failed to follow the remote log of %s: %v", p.Name, err)
+	}
+}
+
+// sshAction runs start, stop, restart or kill on the remote host of a
+// project and syncs the project with the result
+func (m *Manager) sshAction(projectID uint, p *sshProject, action string) error {
+	if m.nodeDown(p.Host) {
+		return fmt.Errorf("node of %s is unreachable", p.Host)
+	}
+
+	stopping := action != "start"
+	transient := types.StatusStarting
+	if stopping {
+		transient = types.StatusStopping
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(transient))
+	m.RecordStatus(projectID, string(transient), fmt.Sprintf("ssh %s: %s", p.Host, action))
+
+	var err error
+	if stopping {
+		m.stopLogFollower(projectID)
+		signal, grace := "TERM", sshStopGrace
+		if action == "kill" {
+			signal, grace = "KILL", 0
+		}
+		_, err = m.runSSH(context.Background(), p.Host, m.stopScript(p, signal, grace))
+		now := time.Now()
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("stop_time", &now)
+	}
+	if err == nil && (action == "start" || action == "restart") {
+		var script string
+		if script, err = m.startScript(p); err == nil {
+			_, err = m.runSSH(context.Background(), p.Host, script)
+		}
+		if err == nil {
+			now := time.Now()
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+				"start_time": &now,
+				"last_error": "",
+			})
+		}
+	}
+
+	if err != nil {
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"status":     string(types.StatusError),
+			"last_error": err.Error(),
+		})
+		m.RecordStatus(projectID, string(types.StatusError), err.Error())
+		return err
+	}
+	_, err = m.CheckSSH(context.Background(), projectID)
+	return err
+}
+
+// MonitorSSH syncs the SSH projects with their remote processes every
+// interval. onCheck is called with each observation whose state changed.
+func (m *Manager) MonitorSSH(interval time.Duration, onCheck func(projectID uint, r *RemoteProcess)) {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var projects []struct {
+			ID      uint
+			SSHHost string `gorm:"column:ssh_host"`
+		}
+		m.db.Table(tables.Name("projects")).Select("id, ssh_host").
+			Where("ssh_host <> '' AND deleted_at IS NULL").
+			Find(&projects)
+
+		for _, p := range projects {
+			// Projects of a node that is down wait for it to come back
+			if m.nodeDown(p.SSHHost) {
+				continue
+			}
+			previous := m.SSHStatus(p.ID)
+			r, err := m.CheckSSH(context.Background(), p.ID)
+			if err != nil {
+				continue
+			}
+			if onCheck != nil && (previous == nil || previous.Status != r.Status || previous.PID != r.PID || previous.Error != r.Error) {
+				onCheck(p.ID, r)
+			}
+		}
+
+		<-ticker.C
+	}
+}
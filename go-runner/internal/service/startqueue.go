@@ -0,0 +1,225 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// Start queue defaults
+const (
+	defaultStartSettle  = 30 * time.Second
+	defaultStartMaxWait = 10 * time.Minute
+	startCPUCacheTTL    = 2 * time.Second
+	startCPURetry       = 2 * time.Second
+)
+
+// StartOptions limits how many projects start at once, so that starting a
+// whole group does not run every install and build in parallel
+type StartOptions struct {
+	MaxConcurrent int                     // Starts holding a slot at once, 0 for no limit
+	CPUThreshold  float64                 // CPU percent above which queued starts wait for running ones, 0 disables
+	Settle        time.Duration           // How long a start holds its slot unless its port opens or it exits first
+	MaxWait       time.Duration           // How long a start waits in line before it fails
+	CPULoad       func() (float64, error) // Current CPU usage in percent
+}
+
+// StartQueueEntry is a start waiting for a slot
+type StartQueueEntry struct {
+	ProjectID  uint      `json:"project_id"`
+	Position   int       `json:"position"` // 1 for the next start
+	QueuedAt   time.Time `json:"queued_at"`
+	WaitingFor string    `json:"waiting_for"` // slot, cpu
+}
+
+// startWaiter is a start waiting in the queue
+type startWaiter struct {
+	projectID uint
+	queuedAt  time.Time
+	ready     chan struct{}
+}
+
+// startQueue hands out start slots in order
+type startQueue struct {
+	mu        sync.Mutex
+	opts      StartOptions
+	running   int
+	waiting   []*startWaiter
+	cpuBusy   bool // Whether the last dispatch was held back by CPU load
+	retrying  bool
+	cpu       float64
+	cpuReadAt time.Time
+}
+
+// SetStartOptions sets the start concurrency limit
+func (m *Manager) SetStartOptions(opts StartOptions) {
+	if opts.Settle <= 0 {
+		opts.Settle = defaultStartSettle
+	}
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = defaultStartMaxWait
+	}
+	m.starts.mu.Lock()
+	defer m.starts.mu.Unlock()
+	m.starts.opts = opts
+}
+
+// acquireStartSlot waits until the project may start and returns the
+// function that gives the slot back. It fails with ErrStartCancelled when the
+// project is stopped meanwhile, or once it waited max_wait seconds.
+func (m *Manager) acquireStartSlot(start *pendingStart, projectID uint) (func(), error) {
+	q := &m.starts
+	q.mu.Lock()
+	if q.opts.MaxConcurrent <= 0 && q.opts.CPUThreshold <= 0 {
+		q.mu.Unlock()
+		return func() {}, nil
+	}
+	maxWait := q.opts.MaxWait
+	w := &startWaiter{projectID: projectID, queuedAt: time.Now(), ready: make(chan struct{})}
+	q.waiting = append(q.waiting, w)
+	q.mu.Unlock()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			q.mu.Lock()
+			q.running--
+			q.mu.Unlock()
+			m.dispatchStarts()
+		})
+	}
+
+	m.dispatchStarts()
+	select {
+	case <-w.ready:
+		return release, nil
+	default:
+	}
+
+	log.Printf("⏳ Start of project %d queued", projectID)
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(types.StatusStarting))
+	m.RecordStatus(projectID, string(types.StatusStarting), "Queued for start")
+	start.markWaiting()
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-w.ready:
+		return release, nil
+	case <-start.ctx.Done():
+		err = ErrStartCancelled
+	case <-timer.C:
+		err = fmt.Errorf("no start slot after waiting %s in the start queue", maxWait)
+	}
+
+	// Leave the line, or give back the slot handed out meanwhile
+	q.mu.Lock()
+	for i, queued := range q.waiting {
+		if queued == w {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			q.mu.Unlock()
+			return nil, err
+		}
+	}
+	q.mu.Unlock()
+	release()
+	return nil, err
+}
+
+// dispatchStarts hands free slots to queued starts in order. When the CPU is
+// busy only one start runs at a time; the queue is checked again shortly.
+func (m *Manager) dispatchStarts() {
+	q := &m.starts
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.waiting) > 0 {
+		if q.opts.MaxConcurrent > 0 && q.running >= q.opts.MaxConcurrent {
+			q.cpuBusy = false
+			return
+		}
+		if q.running > 0 && q.overCPUThreshold() {
+			q.cpuBusy = true
+			if !q.retrying {
+				q.retrying = true
+				time.AfterFunc(startCPURetry, func() {
+					q.mu.Lock()
+					q.retrying = false
+					q.mu.Unlock()
+					m.dispatchStarts()
+				})
+			}
+			return
+		}
+		w := q.waiting[0]
+		q.waiting = q.waiting[1:]
+		q.running++
+		close(w.ready)
+	}
+	q.cpuBusy = false
+}
+
+// overCPUThreshold reports whether the CPU usage is over the threshold,
+// reading it at most every few seconds. Called with q.mu held.
+func (q *startQueue) overCPUThreshold() bool {
+	if q.opts.CPUThreshold <= 0 || q.opts.CPULoad == nil {
+		return false
+	}
+	if time.Since(q.cpuReadAt) > startCPUCacheTTL {
+		if usage, err := q.opts.CPULoad(); err == nil {
+			q.cpu = usage
+		}
+		q.cpuReadAt = time.Now()
+	}
+	return q.cpu >= q.opts.CPUThreshold
+}
+
+// holdStartSlot keeps the slot of a started project until its port accepts
+// connections, its process exits or the settle time is over, since installs
+// and builds run by the command happen after the process started
+func (m *Manager) holdStartSlot(projectID uint, release func()) {
+	defer release()
+
+	m.starts.mu.Lock()
+	settle := m.starts.opts.Settle
+	m.starts.mu.Unlock()
+
+	var port int
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Pluck("port", &port)
+
+	deadline := time.Now().Add(settle)
+	for time.Now().Before(deadline) {
+		m.mu.RLock()
+		_, running := m.processes[projectID]
+		m.mu.RUnlock()
+		if !running || (port > 0 && m.isPortInUse(port)) {
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// StartQueuePosition returns the place of a project in the start queue, or
+// nil when it is not waiting
+func (m *Manager) StartQueuePosition(projectID uint) *StartQueueEntry {
+	q := &m.starts
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, w := range q.waiting {
+		if w.projectID != projectID {
+			continue
+		}
+		entry := &StartQueueEntry{ProjectID: projectID, Position: i + 1, QueuedAt: w.queuedAt, WaitingFor: "slot"}
+		if q.cpuBusy {
+			entry.WaitingFor = "cpu"
+		}
+		return entry
+	}
+	return nil
+}
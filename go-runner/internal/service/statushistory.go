@@ -0,0 +1,82 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// statusHistoryRetention is how long status transitions are kept
+const statusHistoryRetention = 90 * 24 * time.Hour
+
+// statusHistory remembers the last recorded status of each project, so only
+// transitions are stored
+type statusHistory struct {
+	mu       sync.Mutex
+	last     map[uint]string
+	listener func(StatusChange)
+}
+
+// StatusChange is a recorded status transition of a project
+type StatusChange struct {
+	ProjectID      uint      `json:"project_id"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	Reason         string    `json:"reason"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// SetStatusListener sets a function called with every recorded status
+// transition: starts, stops, crashes and restarts
+func (m *Manager) SetStatusListener(listener func(StatusChange)) {
+	m.history.mu.Lock()
+	m.history.listener = listener
+	m.history.mu.Unlock()
+}
+
+// RecordStatus stores a status transition in the timeline of a project
+// (project_status_histories), skipping repeats of the last recorded status
+func (m *Manager) RecordStatus(projectID uint, status, reason string) {
+	change, listener := m.recordStatus(projectID, status, reason)
+	if change != nil && listener != nil {
+		listener(*change)
+	}
+}
+
+func (m *Manager) recordStatus(projectID uint, status, reason string) (*StatusChange, func(StatusChange)) {
+	m.history.mu.Lock()
+	defer m.history.mu.Unlock()
+
+	if m.history.last == nil {
+		m.history.last = make(map[uint]string)
+	}
+	previous, known := m.history.last[projectID]
+	if !known {
+		var last struct{ Status string }
+		m.db.Table(tables.Name("project_status_histories")).Select("status").
+			Where("project_id = ?", projectID).Order("timestamp desc, id desc").Limit(1).Scan(&last)
+		previous = last.Status
+	}
+	if previous == status {
+		m.history.last[projectID] = status
+		return nil, nil
+	}
+
+	now := time.Now()
+	if err := m.db.Table(tables.Name("project_status_histories")).Create(map[string]interface{}{
+		"project_id":      projectID,
+		"status":          status,
+		"previous_status": previous,
+		"reason":          reason,
+		"timestamp":       now,
+	}).Error; err != nil {
+		log.Printf("Failed to record status of project %d: %v", projectID, err)
+		return nil, nil
+	}
+	m.history.last[projectID] = status
+
+	m.db.Exec("DELETE FROM "+tables.Name("project_status_histories")+" WHERE project_id = ? AND timestamp < ?", projectID, now.Add(-statusHistoryRetention))
+	return &StatusChange{ProjectID: projectID, Status: status, PreviousStatus: previous, Reason: reason, Timestamp: now}, m.history.listener
+}
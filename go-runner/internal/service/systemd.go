@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+)
+
+// ErrSystemdUnavailable is returned when systemd cannot be reached on this host
+var ErrSystemdUnavailable = errors.New("systemd is not available on this host")
+
+// systemdTimeout bounds systemd calls; start and stop wait for their job
+const systemdTimeout = 90 * time.Second
+
+// SystemdUnit is the observed state of the unit of a systemd project
+type SystemdUnit struct {
+	Unit        string     `json:"unit"`
+	User        bool       `json:"user"`         // Unit of the user manager (systemctl --user)
+	LoadState   string     `json:"load_state"`   // loaded, not-found, masked...
+	ActiveState string     `json:"active_state"` // active, activating, deactivating, inactive, failed
+	SubState    string     `json:"sub_state"`    // running, exited, dead...
+	Result      string     `json:"result,omitempty"`
+	Description string     `json:"description,omitempty"`
+	MainPID     int        `json:"main_pid"`
+	Since       *time.Time `json:"since,omitempty"` // Last change of the active state
+	Status      string     `json:"status"`          // Project status derived from the active state
+	Error       string     `json:"error,omitempty"`
+	CheckedAt   time.Time  `json:"checked_at"`
+}
+
+// SystemdUnitSummary is a service unit of the host, to pick the ones to add
+type SystemdUnitSummary struct {
+	Unit        string `json:"unit"`
+	LoadState   string `json:"load_state"`
+	ActiveState string `json:"active_state"`
+	SubState    string `json:"sub_state"`
+	Description string `json:"description"`
+}
+
+// systemdState holds the last observation per systemd project
+type systemdState struct {
+	mu       sync.RWMutex
+	observed map[uint]*SystemdUnit
+}
+
+// systemdActiveStatus maps a unit ActiveState to a project status
+var systemdActiveStatus = map[string]types.ServiceStatus{
+	"active":       types.StatusRunning,
+	"reloading":    types.StatusRunning,
+	"activating":   types.StatusStarting,
+	"deactivating": types.StatusStopping,
+	"inactive":     types.StatusStopped,
+	"failed":       types.StatusError,
+}
+
+// systemdTarget returns the unit of a systemd project, or nil for other types
+func (m *Manager) systemdTarget(projectID uint) *SystemdUnit {
+	var p struct {
+		Name        string
+		Type        string
+		SystemdUnit string
+		SystemdUser bool
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("name, type, systemd_unit, systemd_user").
+		Where("id = ?", projectID).Take(&p).Error; err != nil || p.Type != string(types.TypeSystemd) {
+		return nil
+	}
+	return &SystemdUnit{Unit: systemdUnitName(p.SystemdUnit, p.Name), User: p.SystemdUser}
+}
+
+// systemdUnitName defaults the unit to the project name and the suffix to .service
+func systemdUnitName(unit, name string) string {
+	if unit == "" {
+		unit = name
+	}
+	if !strings.Contains(unit, ".") {
+		unit += ".service"
+	}
+	return unit
+}
+
+// SystemdStatus returns the last observation of a systemd project, or nil
+func (m *Manager) SystemdStatus(projectID uint) *SystemdUnit {
+	m.systemd.mu.RLock()
+	defer m.systemd.mu.RUnlock()
+	return m.systemd.observed[projectID]
+}
+
+// systemdConn connects to the system or user manager of systemd over D-Bus
+func systemdConn(ctx context.Context, user bool) (*dbus.Conn, error) {
+	if runtime.GOOS != "linux" {
+		return nil, ErrSystemdUnavailable
+	}
+	connect := dbus.NewSystemConnectionContext
+	if user {
+		connect = dbus.NewUserConnectionContext
+	}
+	conn, err := connect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSystemdUnavailable, err)
+	}
+	return conn, nil
+}
+
+// ListSystemdUnits lists the service units of the system or user manager
+func (m *Manager) ListSystemdUnits(ctx context.Context, user bool) ([]SystemdUnitSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, systemdTimeout)
+	defer cancel()
+	conn, err := systemdConn(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	listed, err := conn.ListUnitsByPatternsContext(ctx, nil, []string{"*.service"})
+	if err != nil {
+		return nil, fmt.Errorf("systemd: %v", err)
+	}
+	units := make([]SystemdUnitSummary, 0, len(listed))
+	for _, u := range listed {
+		units = append(units, SystemdUnitSummary{
+			Unit:        u.Name,
+			LoadState:   u.LoadState,
+			ActiveState: u.ActiveState,
+			SubState:    u.SubState,
+			Description: u.Description,
+		})
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].Unit < units[j].Unit })
+	return units, nil
+}
+
+// observeSystemd reads the state of a unit from its D-Bus properties
+func observeSystemd(ctx context.Context, unit SystemdUnit) *SystemdUnit {
+	u := &SystemdUnit{Unit: unit.Unit, User: unit.User, Status: string(types.StatusUnknown), CheckedAt: time.Now()}
+
+	ctx, cancel := context.WithTimeout(ctx, systemdTimeout)
+	defer cancel()
+	conn, err := systemdConn(ctx, unit.User)
+	if err != nil {
+		u.Error = err.Error()
+		return u
+	}
+	defer conn.Close()
+
+	props, err := conn.GetUnitPropertiesContext(ctx, unit.Unit)
+	if err != nil {
+		u.Error = "systemd: " + err.Error()
+		return u
+	}
+	u.LoadState, _ = props["LoadState"].(string)
+	u.ActiveState, _ = props["ActiveState"].(string)
+	u.SubState, _ = props["SubState"].(string)
+	u.Description, _ = props["Description"].(string)
+	if usec, ok := props["StateChangeTimestamp"].(uint64); ok && usec > 0 {
+		since := time.UnixMicro(int64(usec))
+		u.Since = &since
+	}
+	if strings.HasSuffix(unit.Unit, ".service") {
+		if service, err := conn.GetUnitTypePropertiesContext(ctx, unit.Unit, "Service"); err == nil {
+			u.Result, _ = service["Result"].(string)
+			if pid, ok := service["MainPID"].(uint32); ok {
+				u.MainPID = int(pid)
+			}
+		}
+	}
+
+	if u.LoadState == "not-found" {
+		u.Error = "unit " + unit.Unit + " not found"
+		u.Status = string(types.StatusError)
+		return u
+	}
+	if status, ok := systemdActiveStatus[u.ActiveState]; ok {
+		u.Status = string(status)
+	}
+	return u
+}
+
+// runSystemdJob runs start, stop, restart or kill on a unit over D-Bus and
+// waits for the job to finish
+func runSystemdJob(ctx context.Context, unit SystemdUnit, action string) error {
+	ctx, cancel := context.WithTimeout(ctx, systemdTimeout)
+	defer cancel()
+	conn, err := systemdConn(ctx, unit.User)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	result := make(chan string, 1)
+	switch action {
+	case "start":
+		_, err = conn.StartUnitContext(ctx, unit.Unit, "replace", result)
+	case "stop":
+		_, err = conn.StopUnitContext(ctx, unit.Unit, "replace", result)
+	case "restart":
+		_, err = conn.RestartUnitContext(ctx, unit.Unit, "replace", result)
+	case "kill":
+		return conn.KillUnitWithTarget(ctx, unit.Unit, dbus.All, int32(syscall.SIGKILL))
+	default:
+		return fmt.Errorf("unknown systemd action %q", action)
+	}
+	if err != nil {
+		return fmt.Errorf("systemd: %v", err)
+	}
+
+	select {
+	case done := <-result:
+		if done != "done" {
+			return fmt.Errorf("systemd: %s of %s %s", action, unit.Unit, done)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("systemd: %s of %s did not finish: %v", action, unit.Unit, ctx.Err())
+	}
+}
+
+// CheckSystemd observes the unit of a systemd project, stores the result and
+// syncs the project status and PID with the unit
+func (m *Manager) CheckSystemd(ctx context.Context, projectID uint) (*SystemdUnit, error) {
+	target := m.systemdTarget(projectID)
+	if target == nil {
+		return nil, fmt.Errorf("project %d is not a systemd service", projectID)
+	}
+
+	u := observeSystemd(ctx, *target)
+	m.systemd.mu.Lock()
+	if m.systemd.observed == nil {
+		m.systemd.observed = make(map[uint]*SystemdUnit)
+	}
+	m.systemd.observed[projectID] = u
+	m.systemd.mu.Unlock()
+
+	if u.Status == string(types.StatusUnknown) {
+		return u, nil
+	}
+
+	var current struct{ Status string }
+	m.db.Table(tables.Name("projects")).Select("status").Where("id = ?", projectID).Take(&current)
+	updates := map[string]interface{}{"status": u.Status, "p_id": u.MainPID}
+	if u.Status == string(types.StatusError) {
+		lastError := u.Error
+		if lastError == "" {
+			lastError = "unit failed: " + u.Result
+		}
+		updates["last_error"] = lastError
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(updates)
+	if current.Status != u.Status {
+		m.RecordStatus(projectID, u.Status, fmt.Sprintf("Unit %s %s (%s)", u.Unit, u.ActiveState, u.SubState))
+	}
+
+	// Journald keeps the logs of stopped units, follow them while it runs
+	if u.Status == string(types.StatusRunning) || u.Status == string(types.StatusStarting) {
+		m.followJournal(projectID, *target)
+	}
+	return u, nil
+}
+
+// followJournal streams the journal of a unit into the project logs
+func (m *Manager) followJournal(projectID uint, unit SystemdUnit) {
+	args := []string{"--follow", "--lines", "100", "--output", "short-iso", "--no-pager"}
+	if unit.User {
+		args = append(args, "--user-unit", unit.Unit)
+	} else {
+		args = append(args, "--unit", unit.Unit)
+	}
+	if err := m.followLogs(projectID, "journalctl", args...); err != nil {
+		log.Printf("Failed to follow the journal of %s: %v", unit.Unit, err)
+	}
+}
+
+// systemdAction runs start, stop, restart or kill on the unit of a project
+// over D-Bus and syncs the project with the result
+func (m *Manager) systemdAction(projectID uint, unit SystemdUnit, action string) error {
+	stopping := action == "stop" || action == "kill"
+	transient := types.StatusStarting
+	if stopping {
+		transient = types.StatusStopping
+	}
+	m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(transient))
+	m.RecordStatus(projectID, string(transient), "systemd "+action+" "+unit.Unit)
+
+	err := runSystemdJob(context.Background(), unit, action)
+
+	now := time.Now()
+	if stopping {
+		m.stopLogFollower(projectID)
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("stop_time", &now)
+	} else if err == nil {
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Updates(map[string]interface{}{
+			"start_time": &now,
+			"last_error": "",
+		})
+	}
+
+	if err != nil {
+		m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("last_error", err.Error())
+	}
+	if _, checkErr := m.CheckSystemd(context.Background(), projectID); checkErr != nil && err == nil {
+		err = checkErr
+	}
+	return err
+}
+
+// MonitorSystemd syncs the systemd projects with their units every interval.
+// onCheck is called with each observation whose state changed.
+func (m *Manager) MonitorSystemd(interval time.Duration, onCheck func(projectID uint, u *SystemdUnit)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var projects []struct{ ID uint }
+		m.db.Table(tables.Name("projects")).Select("id").
+			Where("type = ? AND deleted_at IS NULL", string(types.TypeSystemd)).
+			Find(&projects)
+
+		for _, p := range projects {
+			previous := m.SystemdStatus(p.ID)
+			u, err := m.CheckSystemd(context.Background(), p.ID)
+			if err != nil {
+				continue
+			}
+			if onCheck != nil && (previous == nil || previous.ActiveState != u.ActiveState || previous.SubState != u.SubState) {
+				onCheck(p.ID, u)
+			}
+		}
+
+		<-ticker.C
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// tailFilesRescan is how often the patterns of tail_files are matched
+// again, picking up log files created after the start
+const tailFilesRescan = 5 * time.Second
+
+// TailedFile is a log file of a service tailed into its logs
+type TailedFile struct {
+	Source    string `json:"source"` // Tag of its lines, the path as configured or matched
+	Path      string `json:"path"`
+	Status    string `json:"status"` // tailing, waiting (not created yet) or ended
+	Size      int64  `json:"size"`
+	Rotations int    `json:"rotations"` // Times the file was replaced by log rotation
+}
+
+// tailFiles holds the tails of the log files of a process
+type tailFiles struct {
+	mu    sync.Mutex
+	tails map[string]*tailedFile // By path
+}
+
+type tailedFile struct {
+	source string
+	tail   *fileTail
+	ended  bool
+}
+
+// parseTailFiles splits tail_files into its paths or glob patterns
+func parseTailFiles(spec string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(spec, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// startTailFiles tails the log files of tail_files into the logs of a
+// process until ctx ends with its output. Paths are relative to the
+// directory the service runs in. Files matched by a glob pattern after the
+// start are read from their start. Called with m.mu held.
+func (m *Manager) startTailFiles(ctx context.Context, processInfo *ProcessInfo) {
+	var p struct {
+		Path       string
+		WorkingDir string
+		TailFiles  string
+	}
+	m.db.Table(tables.Name("projects")).Select("path, working_dir, tail_files").Where("id = ?", processInfo.ProjectID).Take(&p)
+	patterns := parseTailFiles(p.TailFiles)
+	if len(patterns) == 0 {
+		return
+	}
+	dir := p.WorkingDir
+	if dir == "" {
+		dir = p.Path
+	}
+
+	files := &tailFiles{tails: make(map[string]*tailedFile)}
+	processInfo.tailFiles = files
+
+	go func() {
+		ticker := time.NewTicker(tailFilesRescan)
+		defer ticker.Stop()
+		for first := true; ; first = false {
+			for _, pattern := range patterns {
+				path := pattern
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(dir, path)
+				}
+				if !strings.ContainsAny(pattern, "*?[") {
+					m.tailFile(ctx, processInfo, files, pattern, path, false)
+					continue
+				}
+				matches, _ := filepath.Glob(path)
+				for _, match := range matches {
+					source := match
+					if rel, err := filepath.Rel(dir, match); err == nil && !strings.HasPrefix(rel, "..") {
+						source = rel
+					}
+					m.tailFile(ctx, processInfo, files, source, match, !first)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// tailFile starts tailing a file once, tagging its lines with source
+func (m *Manager) tailFile(ctx context.Context, processInfo *ProcessInfo, files *tailFiles, source, path string, fromStart bool) {
+	files.mu.Lock()
+	defer files.mu.Unlock()
+	if _, ok := files.tails[path]; ok {
+		return
+	}
+
+	var tail *fileTail
+	if fromStart {
+		tail = &fileTail{ctx: ctx, path: path}
+	} else {
+		tail = followFile(ctx, path)
+	}
+	tailed := &tailedFile{source: source, tail: tail}
+	files.tails[path] = tailed
+
+	go func() {
+		m.captureTaggedOutput(tail, processInfo, "["+source+"] ")
+		files.mu.Lock()
+		tailed.ended = true
+		files.mu.Unlock()
+	}()
+}
+
+// TailedFiles returns the log files tailed for a running project, nil when
+// it has none
+func (m *Manager) TailedFiles(projectID uint) []TailedFile {
+	m.mu.RLock()
+	processInfo, ok := m.processes[projectID]
+	var files *tailFiles
+	if ok {
+		files = processInfo.tailFiles
+	}
+	m.mu.RUnlock()
+	if files == nil {
+		return nil
+	}
+
+	files.mu.Lock()
+	defer files.mu.Unlock()
+	result := make([]TailedFile, 0, len(files.tails))
+	for path, tailed := range files.tails {
+		file := TailedFile{
+			Source:    tailed.source,
+			Path:      path,
+			Status:    "waiting",
+			Rotations: int(tailed.tail.rotations.Load()),
+		}
+		if tailed.ended {
+			file.Status = "ended"
+		} else if tailed.tail.opened.Load() {
+			file.Status = "tailing"
+		}
+		if info, err := os.Stat(path); err == nil {
+			file.Size = info.Size()
+		}
+		result = append(result, file)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Source < result[j].Source })
+	return result
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// templatePattern matches placeholders such as {{port}} or {{ project.name }}
+var templatePattern = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_.]+)\s*\}\}`)
+
+// referencePattern matches references to another project such as
+// {{project "api".url}} or {{ project "db".env.DATABASE_URL }}
+var referencePattern = regexp.MustCompile(`\{\{\s*project\s+"([^"]+)"\s*\.([a-zA-Z0-9_.]+)\s*\}\}`)
+
+// TemplateContext holds the values placeholders in Command, Args and EnvVars
+// resolve to when a service starts
+type TemplateContext struct {
+	Port        int
+	ProjectID   uint
+	ProjectName string
+	ProjectPath string
+	GroupName   string
+	Environment string
+	NodeIP      string
+	Hostname    string
+	GroupID     *uint
+
+	// Warnings lists references that could not be resolved, or that point
+	// at projects not running, once each
+	Warnings []string
+
+	// references resolves {{project "name".attribute}}; nil leaves them as is
+	references func(t *TemplateContext, name, attribute string) (string, error)
+}
+
+// values returns the placeholder table for the context
+func (t *TemplateContext) values() map[string]string {
+	port := ""
+	if t.Port > 0 {
+		port = strconv.Itoa(t.Port)
+	}
+
+	return map[string]string{
+		"port":          port,
+		"project.id":    strconv.FormatUint(uint64(t.ProjectID), 10),
+		"project.name":  t.ProjectName,
+		"project.path":  t.ProjectPath,
+		"group.name":    t.GroupName,
+		"environment":   t.Environment,
+		"node.ip":       t.NodeIP,
+		"node.hostname": t.Hostname,
+	}
+}
+
+// Expand replaces known placeholders in s. Unknown placeholders are left
+// untouched so literal {{...}} used by the service itself survives.
+func (t *TemplateContext) Expand(s string) string {
+	if t == nil || !strings.Contains(s, "{{") {
+		return s
+	}
+
+	if t.references != nil {
+		s = referencePattern.ReplaceAllStringFunc(s, func(match string) string {
+			parts := referencePattern.FindStringSubmatch(match)
+			value, err := t.references(t, parts[1], parts[2])
+			if err != nil {
+				t.warn(fmt.Sprintf("%s: %v", match, err))
+				return match
+			}
+			return value
+		})
+	}
+
+	values := t.values()
+	return templatePattern.ReplaceAllStringFunc(s, func(match string) string {
+		key := strings.ToLower(templatePattern.FindStringSubmatch(match)[1])
+		if value, ok := values[key]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// warn records a warning unless it was already recorded
+func (t *TemplateContext) warn(warning string) {
+	for _, w := range t.Warnings {
+		if w == warning {
+			return
+		}
+	}
+	t.Warnings = append(t.Warnings, warning)
+}
+
+// buildTemplateContext collects the placeholder values for a project
+func (m *Manager) buildTemplateContext(projectID uint, name, path, environment string, port int, groupID *uint) *TemplateContext {
+	ctx := &TemplateContext{
+		Port:        port,
+		ProjectID:   projectID,
+		ProjectName: name,
+		ProjectPath: path,
+		Environment: environment,
+		NodeIP:      nodeIP(),
+		GroupID:     groupID,
+		references:  m.resolveReference,
+	}
+
+	if groupID != nil {
+		var group struct {
+			Name string
+		}
+		if err := m.db.Table(tables.Name("project_groups")).Select("name").Where("id = ?", *groupID).First(&group).Error; err == nil {
+			ctx.GroupName = group.Name
+		}
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		ctx.Hostname = hostname
+	}
+
+	return ctx
+}
+
+// nodeIP returns the first non-loopback IPv4 address of this machine,
+// falling back to 127.0.0.1
+func nodeIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "127.0.0.1"
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil {
+			return ip.String()
+		}
+	}
+	return "127.0.0.1"
+}
+
+// resolveReference returns an attribute of another project for
+// {{project "name".attribute}}, read when the referencing project starts:
+// id, name, path, status, pid, environment, host, port, url, socket_path,
+// connection_string, health_check_url or env.NAME. Names match case
+// insensitively, preferring a project of the same group, then a running one.
+func (m *Manager) resolveReference(t *TemplateContext, name, attribute string) (string, error) {
+	var candidates []struct {
+		ID               uint
+		Name             string
+		Path             string
+		Status           string
+		PID              int `gorm:"column:p_id"`
+		Port             int
+		Environment      string
+		SocketPath       string
+		ConnectionString string
+		HealthCheckURL   string
+		SSHHost          string `gorm:"column:ssh_host"`
+		GroupID          *uint
+	}
+	m.db.Table(tables.Name("projects")).Where("LOWER(name) = LOWER(?) AND deleted_at IS NULL", name).Order("id").Find(&candidates)
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no project named %q", name)
+	}
+	// Same group first, then running projects
+	ref, best := candidates[0], -1
+	for _, c := range candidates {
+		score := 0
+		if c.GroupID != nil && t.GroupID != nil && *c.GroupID == *t.GroupID {
+			score += 2
+		}
+		if c.Status == string(types.StatusRunning) {
+			score++
+		}
+		if score > best {
+			ref, best = c, score
+		}
+	}
+	if ref.Status != string(types.StatusRunning) && ref.ID != t.ProjectID {
+		t.warn(fmt.Sprintf("project %q referenced by this project is %s, its configured values were used", ref.Name, ref.Status))
+	}
+
+	// The port the service listens on: the one it got when running (moved
+	// by a blue/green restart, or resolved at its start), else its PORT
+	// variable when set, else the configured one
+	port := func() int {
+		if p := m.runningPort(ref.ID); p > 0 {
+			return p
+		}
+		spec, err := m.launchSpec(ref.ID, false)
+		if err == nil {
+			if p, err := strconv.Atoi(spec.Env["PORT"]); err == nil && p > 0 {
+				return p
+			}
+		}
+		return ref.Port
+	}
+	host := func() string {
+		if ref.SSHHost == "" {
+			return "127.0.0.1"
+		}
+		if node := m.NodeStatus(ref.SSHHost); node != nil && node.Address != "" {
+			if h, _, err := net.SplitHostPort(node.Address); err == nil {
+				return h
+			}
+		}
+		_, h, _ := splitSSHHost(ref.SSHHost)
+		return h
+	}
+
+	if key, ok := strings.CutPrefix(attribute, "env."); ok {
+		spec, err := m.launchSpec(ref.ID, false)
+		if err != nil {
+			return "", err
+		}
+		value, ok := spec.Env[key]
+		if !ok {
+			return "", fmt.Errorf("project %q has no variable %s", ref.Name, key)
+		}
+		return value, nil
+	}
+	attribute = strings.ToLower(attribute)
+	switch attribute {
+	case "id":
+		return strconv.FormatUint(uint64(ref.ID), 10), nil
+	case "name":
+		return ref.Name, nil
+	case "path":
+		return ref.Path, nil
+	case "status":
+		return ref.Status, nil
+	case "pid":
+		return strconv.Itoa(ref.PID), nil
+	case "environment":
+		return ref.Environment, nil
+	case "socket_path":
+		return ref.SocketPath, nil
+	case "connection_string":
+		return ref.ConnectionString, nil
+	case "health_check_url":
+		return ref.HealthCheckURL, nil
+	case "host":
+		return host(), nil
+	case "port", "url":
+		p := port()
+		if p == 0 {
+			return "", fmt.Errorf("project %q has no port", ref.Name)
+		}
+		if attribute == "port" {
+			return strconv.Itoa(p), nil
+		}
+		return "http://" + net.JoinHostPort(host(), strconv.Itoa(p)), nil
+	}
+	return "", fmt.Errorf("unknown attribute %q", attribute)
+}
+
+// runningPort returns the port the running process of a project listens on,
+// from the PORT it was started with, 0 when it is not running or has none
+func (m *Manager) runningPort(projectID uint) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	info, ok := m.processes[projectID]
+	if !ok || info.Process == nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(envValue(info.Process.Env, "PORT"))
+	return m.activePortOf(projectID, port, info)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"os/exec"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestExpand(t *testing.T) {
+	groupID := uint(3)
+	tmpl := &TemplateContext{
+		Port:        8080,
+		ProjectID:   7,
+		ProjectName: "api",
+		ProjectPath: "/srv/api",
+		GroupName:   "backend",
+		Environment: "dev",
+		NodeIP:      "10.0.0.5",
+		Hostname:    "box",
+		GroupID:     &groupID,
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no placeholder", "npm start", "npm start"},
+		{"port", "--port {{port}}", "--port 8080"},
+		{"spaces and case", "{{ PORT }}:{{ Project.Name }}", "8080:api"},
+		{"all values", "{{project.id}} {{project.path}} {{group.name}} {{environment}} {{node.ip}} {{node.hostname}}", "7 /srv/api backend dev 10.0.0.5 box"},
+		{"unknown kept", "{{port}} {{.Values.x}} {{unknown}}", "8080 {{.Values.x}} {{unknown}}"},
+		{"reference kept without resolver", `{{project "db".port}}`, `{{project "db".port}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tmpl.Expand(tt.in); got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	var none *TemplateContext
+	if got := none.Expand("{{port}}"); got != "{{port}}" {
+		t.Errorf("nil context Expand = %q, want the input", got)
+	}
+	tmpl.Port = 0
+	if got := tmpl.Expand("[{{port}}]"); got != "[]" {
+		t.Errorf("Expand without port = %q, want []", got)
+	}
+}
+
+// newReferenceManager returns a manager with the projects table holding
+// a stopped api on port 8081 with API_KEY and a db with a connection string
+func newReferenceManager(t *testing.T) *Manager {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.Exec(`CREATE TABLE projects (
+		id INTEGER PRIMARY KEY, name TEXT, path TEXT, command TEXT, args TEXT, working_dir TEXT,
+		status TEXT, p_id INTEGER, port INTEGER, environment TEXT, env_file TEXT, env_vars TEXT,
+		socket_path TEXT, connection_string TEXT, health_check_url TEXT, ssh_host TEXT,
+		group_id INTEGER, deleted_at DATETIME)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(`INSERT INTO projects (id, name, path, command, status, port, env_vars, ssh_host, connection_string) VALUES
+		(1, 'api', '/srv/api', 'node server.js', 'stopped', 8081, '{"API_KEY":"secret"}', '', ''),
+		(2, 'db', '/srv/db', 'postgres', 'running', 0, '', '', 'postgres://localhost:5432/app')`).Error; err != nil {
+		t.Fatal(err)
+	}
+	return NewManager(db)
+}
+
+func TestExpandReferences(t *testing.T) {
+	m := newReferenceManager(t)
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"port", `{{project "api".port}}`, "8081"},
+		{"port upper case", `{{project "api".PORT}}`, "8081"},
+		{"url", `{{project "api".url}}`, "http://127.0.0.1:8081"},
+		{"url upper case", `{{ project "API".URL }}`, "http://127.0.0.1:8081"},
+		{"env", `{{project "api".env.API_KEY}}`, "secret"},
+		{"connection string", `{{project "db".connection_string}}`, "postgres://localhost:5432/app"},
+		{"no port", `{{project "db".port}}`, `{{project "db".port}}`},
+		{"unknown project", `{{project "cache".port}}`, `{{project "cache".port}}`},
+		{"unknown attribute", `{{project "api".color}}`, `{{project "api".color}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := m.buildTemplateContext(9, "web", "/srv/web", "", 3000, nil)
+			if got := tmpl.Expand(tt.in); got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("stopped reference warns", func(t *testing.T) {
+		tmpl := m.buildTemplateContext(9, "web", "/srv/web", "", 3000, nil)
+		tmpl.Expand(`{{project "api".port}} {{project "api".url}}`)
+		if len(tmpl.Warnings) != 1 {
+			t.Errorf("Warnings = %q, want one warning about api", tmpl.Warnings)
+		}
+	})
+
+	t.Run("running port", func(t *testing.T) {
+		// A running api got another port than configured, e.g. from a
+		// blue/green restart
+		m.processes[1] = &ProcessInfo{ProjectID: 1, Process: &exec.Cmd{Env: []string{"PORT=9001"}}}
+		defer delete(m.processes, 1)
+
+		tmpl := m.buildTemplateContext(9, "web", "/srv/web", "", 3000, nil)
+		if got, want := tmpl.Expand(`{{project "api".port}} {{project "api".url}}`), "9001 http://127.0.0.1:9001"; got != want {
+			t.Errorf("Expand = %q, want %q", got, want)
+		}
+	})
+}
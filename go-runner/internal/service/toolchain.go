@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Version managers that select the runtime of a project
+const (
+	ToolchainSystem = "system" // Runtime found on the PATH
+	ToolchainNvm    = "nvm"
+	ToolchainAsdf   = "asdf"
+	ToolchainGo     = "go" // Toolchain switching of the go command (go.mod toolchain)
+)
+
+// toolchainVersionTimeout bounds each version lookup during a start
+const toolchainVersionTimeout = 5 * time.Second
+
+// nvmExecScript runs a command through nvm: bash -c script nvm <version> <command> [args...]
+const nvmExecScript = `. "$NVM_DIR/nvm.sh" --no-use && v="$1" && shift && nvm exec --silent "$v" "$@"`
+
+// asdfSystemTools maps asdf plugins to the executable reporting their version
+var asdfSystemTools = map[string]string{"nodejs": "node", "golang": "go", "python": "python3"}
+
+// ToolchainResolution is the runtime version a project asked for and the one
+// it was started with
+type ToolchainResolution struct {
+	Tool      string `json:"tool"`               // node, go, or the asdf plugin (nodejs, golang, python...)
+	Requested string `json:"requested"`          // Version from the file
+	Source    string `json:"source"`             // .nvmrc, .node-version, .tool-versions, go.mod
+	Manager   string `json:"manager"`            // nvm, asdf, go, system
+	Resolved  string `json:"resolved,omitempty"` // Version the project runs with
+	Warning   string `json:"warning,omitempty"`  // Why the requested version is not used
+}
+
+// toolchainState keeps the toolchains each project was last started with
+type toolchainState struct {
+	mu       sync.RWMutex
+	resolved map[uint][]ToolchainResolution
+}
+
+// ResolvedToolchains returns the runtime versions a project was last started
+// with, nil when it requests none
+func (m *Manager) ResolvedToolchains(projectID uint) []ToolchainResolution {
+	m.toolchains.mu.RLock()
+	defer m.toolchains.mu.RUnlock()
+	return m.toolchains.resolved[projectID]
+}
+
+// applyToolchains selects the runtimes of a project for its start command,
+// records them for the project status and logs the warnings
+func (m *Manager) applyToolchains(projectID uint, cmd *exec.Cmd) []ToolchainResolution {
+	resolved := resolveToolchains(cmd)
+
+	m.toolchains.mu.Lock()
+	if m.toolchains.resolved == nil {
+		m.toolchains.resolved = make(map[uint][]ToolchainResolution)
+	}
+	if len(resolved) > 0 {
+		m.toolchains.resolved[projectID] = resolved
+	} else {
+		delete(m.toolchains.resolved, projectID)
+	}
+	m.toolchains.mu.Unlock()
+
+	for _, r := range resolved {
+		if r.Warning != "" {
+			log.Printf("⚠️ Project %d: %s", projectID, r.Warning)
+		}
+	}
+	return resolved
+}
+
+// resolveToolchains honors the .tool-versions, .nvmrc / .node-version and
+// go.mod toolchain directive of the command directory: the command is run
+// through asdf exec or nvm exec, and go may switch toolchains. Requested
+// versions that are not installed are reported as warnings and the command
+// runs with the runtime on the PATH.
+func resolveToolchains(cmd *exec.Cmd) []ToolchainResolution {
+	var resolved []ToolchainResolution
+
+	asdfTools := readToolVersions(filepath.Join(cmd.Dir, ".tool-versions"))
+	if len(asdfTools) > 0 {
+		resolved = append(resolved, applyAsdf(cmd, asdfTools)...)
+	}
+
+	if _, ok := asdfTools["nodejs"]; !ok {
+		for _, name := range []string{".nvmrc", ".node-version"} {
+			if data, err := os.ReadFile(filepath.Join(cmd.Dir, name)); err == nil {
+				if requested := strings.TrimPrefix(strings.TrimSpace(string(data)), "v"); requested != "" {
+					resolved = append(resolved, applyNvm(cmd, name, requested))
+				}
+				break
+			}
+		}
+	}
+
+	if _, ok := asdfTools["golang"]; !ok {
+		if data, err := os.ReadFile(filepath.Join(cmd.Dir, "go.mod")); err == nil {
+			// ParseLax skips the toolchain directive
+			if file, err := modfile.Parse("go.mod", data, nil); err == nil && file.Toolchain != nil {
+				resolved = append(resolved, applyGoToolchain(cmd, file.Toolchain.Name))
+			}
+		}
+	}
+
+	return resolved
+}
+
+// readToolVersions parses an asdf .tool-versions file into plugin -> version.
+// Only the first version of a line is used; asdf falls back to the others.
+func readToolVersions(path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	tools := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			tools[fields[0]] = fields[1]
+		}
+	}
+	return tools
+}
+
+// applyAsdf runs the command through asdf exec when it is an asdf shim, and
+// puts the shims first on the PATH so that child processes (npm running node)
+// resolve the versions of .tool-versions too
+func applyAsdf(cmd *exec.Cmd, tools map[string]string) []ToolchainResolution {
+	plugins := make([]string, 0, len(tools))
+	for plugin := range tools {
+		plugins = append(plugins, plugin)
+	}
+	sort.Strings(plugins)
+
+	asdf, found := lookPathIn("asdf", envValue(cmd.Env, "PATH"))
+	resolved := make([]ToolchainResolution, 0, len(plugins))
+	for _, plugin := range plugins {
+		r := ToolchainResolution{Tool: plugin, Requested: tools[plugin], Source: ".tool-versions", Manager: ToolchainAsdf}
+		switch {
+		case !found:
+			systemToolchain(cmd, &r, "asdf is not installed")
+		case runQuiet(cmd, asdf, "where", plugin, r.Requested) != nil:
+			systemToolchain(cmd, &r, fmt.Sprintf("%s %s is not installed in asdf (asdf install %s %s)", plugin, r.Requested, plugin, r.Requested))
+		default:
+			r.Resolved = r.Requested
+		}
+		resolved = append(resolved, r)
+	}
+	if !found {
+		return resolved
+	}
+
+	dataDir := envValue(cmd.Env, "ASDF_DATA_DIR")
+	if dataDir == "" {
+		home, _ := os.UserHomeDir()
+		dataDir = filepath.Join(home, ".asdf")
+	}
+	shims := filepath.Join(dataDir, "shims")
+	cmd.Env = setEnvValue(cmd.Env, "PATH", shims+string(os.PathListSeparator)+envValue(cmd.Env, "PATH"))
+
+	// exec.Cmd resolved the executable on the PATH of go-runner, where the
+	// shims may be missing
+	if len(cmd.Args) > 0 && !strings.ContainsRune(cmd.Args[0], filepath.Separator) {
+		if info, err := os.Stat(filepath.Join(shims, cmd.Args[0])); err == nil && !info.IsDir() {
+			cmd.Path = asdf
+			cmd.Args = append([]string{asdf, "exec"}, cmd.Args...)
+			cmd.Err = nil
+		}
+	}
+	return resolved
+}
+
+// applyNvm runs the command through nvm exec with the version of .nvmrc or
+// .node-version, when nvm has it installed
+func applyNvm(cmd *exec.Cmd, source, requested string) ToolchainResolution {
+	r := ToolchainResolution{Tool: "node", Requested: requested, Source: source, Manager: ToolchainNvm}
+
+	nvmDir := envValue(cmd.Env, "NVM_DIR")
+	if nvmDir == "" {
+		home, _ := os.UserHomeDir()
+		nvmDir = filepath.Join(home, ".nvm")
+	}
+	if _, err := os.Stat(filepath.Join(nvmDir, "nvm.sh")); err != nil {
+		systemToolchain(cmd, &r, "nvm is not installed")
+		return r
+	}
+
+	installed, ok := nvmInstalledVersion(nvmDir, requested)
+	if !ok {
+		systemToolchain(cmd, &r, fmt.Sprintf("node %s is not installed in nvm (nvm install %s)", requested, requested))
+		return r
+	}
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		systemToolchain(cmd, &r, "bash is needed to run nvm")
+		return r
+	}
+
+	r.Resolved = installed
+	cmd.Env = setEnvValue(cmd.Env, "NVM_DIR", nvmDir)
+	cmd.Args = append([]string{bash, "-c", nvmExecScript, "nvm", requested}, cmd.Args...)
+	cmd.Path = bash
+	cmd.Err = nil
+	return r
+}
+
+// nvmInstalledVersion returns the newest node installed by nvm matching a
+// version (20, 20.11, v20.11.1). Aliases such as lts/* are left to nvm.
+func nvmInstalledVersion(nvmDir, requested string) (string, bool) {
+	if _, n, err := parsePartialVersion(requested); err != nil || n == 0 {
+		return requested, true
+	}
+
+	entries, err := os.ReadDir(filepath.Join(nvmDir, "versions", "node"))
+	if err != nil {
+		return "", false
+	}
+	var best toolVersion
+	found := false
+	for _, entry := range entries {
+		v, ok := parseToolVersion(entry.Name())
+		if !ok {
+			continue
+		}
+		if matches, _ := versionSatisfies(v, requested); matches && (!found || v.compare(best) > 0) {
+			best, found = v, true
+		}
+	}
+	if !found {
+		return "", false
+	}
+	return best.String(), true
+}
+
+// applyGoToolchain honors the toolchain directive of go.mod: go 1.21 and
+// newer switch to it by themselves, unless GOTOOLCHAIN=local is configured
+// with go env -w, which is overridden for the project
+func applyGoToolchain(cmd *exec.Cmd, toolchain string) ToolchainResolution {
+	r := ToolchainResolution{Tool: "go", Requested: toolchain, Source: "go.mod", Manager: ToolchainGo}
+	requested, _ := parseToolVersion(toolchain)
+
+	goBin, ok := lookPathIn("go", envValue(cmd.Env, "PATH"))
+	if !ok {
+		r.Manager = ToolchainSystem
+		r.Warning = fmt.Sprintf("go is not installed, %s cannot be used", toolchain)
+		return r
+	}
+	local, err := toolVersionOf(context.Background(), cmd.Dir, append(cmd.Env, "GOTOOLCHAIN=local"), goBin, "env", "GOVERSION")
+	if err != nil {
+		r.Warning = "cannot read the go version: " + err.Error()
+		return r
+	}
+
+	switch {
+	case local.compare(requested) >= 0:
+		r.Manager = ToolchainSystem
+		r.Resolved = "go" + local.String()
+	case local.compare(toolVersion{1, 21, 0}) < 0:
+		r.Manager = ToolchainSystem
+		r.Resolved = "go" + local.String()
+		r.Warning = fmt.Sprintf("go %s cannot switch toolchains, install %s", local, toolchain)
+	case envValue(cmd.Env, "GOTOOLCHAIN") == "local":
+		r.Manager = ToolchainSystem
+		r.Resolved = "go" + local.String()
+		r.Warning = fmt.Sprintf("GOTOOLCHAIN=local keeps go %s instead of %s", local, toolchain)
+	default:
+		if envValue(cmd.Env, "GOTOOLCHAIN") == "" {
+			cmd.Env = setEnvValue(cmd.Env, "GOTOOLCHAIN", "auto")
+		}
+		r.Resolved = toolchain
+	}
+	return r
+}
+
+// systemToolchain records that a requested version is not selected by a
+// version manager, with the runtime on the PATH when it can be read
+func systemToolchain(cmd *exec.Cmd, r *ToolchainResolution, reason string) {
+	r.Manager = ToolchainSystem
+	name := r.Tool
+	if tool, ok := asdfSystemTools[name]; ok {
+		name = tool
+	}
+	path, ok := lookPathIn(name, envValue(cmd.Env, "PATH"))
+	if !ok {
+		r.Warning = fmt.Sprintf("%s, and %s %s from %s is not on the PATH", reason, name, r.Requested, r.Source)
+		return
+	}
+	v, err := toolVersionOf(context.Background(), cmd.Dir, cmd.Env, path, "--version")
+	if err != nil {
+		r.Warning = fmt.Sprintf("%s, using %s from the PATH", reason, path)
+		return
+	}
+	r.Resolved = v.String()
+	if ok, _ := versionSatisfies(v, r.Requested); !ok {
+		r.Warning = fmt.Sprintf("%s, using %s %s instead of %s from %s", reason, name, v, r.Requested, r.Source)
+	}
+}
+
+// runQuiet runs a command in the directory and environment of cmd and
+// returns whether it failed
+func runQuiet(cmd *exec.Cmd, name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), toolchainVersionTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, name, args...)
+	c.Dir = cmd.Dir
+	c.Env = cmd.Env
+	return c.Run()
+}
+
+// toolVersionOf runs an executable and parses the version it prints
+func toolVersionOf(ctx context.Context, dir string, env []string, path string, args ...string) (toolVersion, error) {
+	ctx, cancel := context.WithTimeout(ctx, toolchainVersionTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	out, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		return toolVersion{}, fmt.Errorf("%s %s: %v", filepath.Base(path), strings.Join(args, " "), err)
+	}
+	v, ok := parseToolVersion(text)
+	if !ok {
+		return toolVersion{}, fmt.Errorf("cannot parse version from %q", text)
+	}
+	return v, nil
+}
+
+// lookPathIn finds an executable in a PATH list
+func lookPathIn(name, pathList string) (string, bool) {
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// envValue returns a variable of an environment list, the last one winning
+func envValue(env []string, key string) string {
+	prefix := key + "="
+	for i := len(env) - 1; i >= 0; i-- {
+		if strings.HasPrefix(env[i], prefix) {
+			return env[i][len(prefix):]
+		}
+	}
+	return ""
+}
+
+// setEnvValue replaces or adds a variable of an environment list
+func setEnvValue(env []string, key, value string) []string {
+	prefix := key + "="
+	result := make([]string, 0, len(env)+1)
+	for _, e := range env {
+		if !strings.HasPrefix(e, prefix) {
+			result = append(result, e)
+		}
+	}
+	return append(result, prefix+value)
+}
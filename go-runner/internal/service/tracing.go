@@ -0,0 +1,235 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+const (
+	// maxIndexedTraces bounds the trace index; the oldest traces are evicted first
+	maxIndexedTraces = 5000
+	// maxLinesPerTrace bounds the lines kept for one trace
+	maxLinesPerTrace = 1000
+)
+
+// Sources of a trace line
+const (
+	TraceSourceLive   = "live"   // Indexed when the line was logged
+	TraceSourceBuffer = "buffer" // Found in the in-memory log buffer
+	TraceSourceStored = "stored" // Found in logs saved to the database
+)
+
+var traceIDPatterns = []*regexp.Regexp{
+	// W3C traceparent: 00-<trace id>-<span id>-<flags>
+	regexp.MustCompile(`\b[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}\b`),
+	// trace_id=..., "traceId": "...", request-id: ..., X-Request-ID ...
+	regexp.MustCompile(`(?i)\b(?:trace[_-]?id|x-request-id|request[_-]?id|req[_-]?id|correlation[_-]?id)\b["']?\s*[:=]\s*["']?([A-Za-z0-9][A-Za-z0-9._:-]{7,63})`),
+}
+
+// ExtractTraceIDs returns the trace / request IDs found in a log line, lowercased
+func ExtractTraceIDs(line string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, pattern := range traceIDPatterns {
+		for _, m := range pattern.FindAllStringSubmatch(line, -1) {
+			id := strings.ToLower(m[1])
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// TraceContext is the trace injected into a service on start
+type TraceContext struct {
+	TraceID     string
+	SpanID      string
+	TraceParent string // W3C traceparent header value
+}
+
+// newTraceContext generates a sampled W3C trace context
+func newTraceContext() (*TraceContext, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	tc := &TraceContext{
+		TraceID: hex.EncodeToString(buf[:16]),
+		SpanID:  hex.EncodeToString(buf[16:]),
+	}
+	tc.TraceParent = fmt.Sprintf("00-%s-%s-01", tc.TraceID, tc.SpanID)
+	return tc, nil
+}
+
+// Env returns the variables injected into the service environment
+func (tc *TraceContext) Env() map[string]string {
+	return map[string]string{
+		"TRACEPARENT":  tc.TraceParent,
+		"TRACE_PARENT": tc.TraceParent,
+		"TRACE_ID":     tc.TraceID,
+		"REQUEST_ID":   tc.TraceID,
+	}
+}
+
+// TraceLine is a log line belonging to a trace
+type TraceLine struct {
+	ProjectID   uint       `json:"project_id"`
+	ProjectName string     `json:"project_name"`
+	Line        string     `json:"line"`
+	Timestamp   *time.Time `json:"timestamp,omitempty"` // Capture time, unknown for lines saved without one
+	Source      string     `json:"source"`              // live, buffer, stored
+}
+
+// TraceProject summarizes the lines of one project in a trace
+type TraceProject struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Lines       int    `json:"lines"`
+	StartedWith bool   `json:"started_with"` // The trace was injected when this project started
+}
+
+// TraceResult gathers the log lines of a trace across all projects
+type TraceResult struct {
+	TraceID  string         `json:"trace_id"`
+	Projects []TraceProject `json:"projects"`
+	Lines    []TraceLine    `json:"lines"`
+}
+
+// traceIndex maps trace IDs to the log lines mentioning them
+type traceIndex struct {
+	mu    sync.Mutex
+	lines map[string][]TraceLine
+	order []string // Insertion order for eviction
+}
+
+// add indexes a log line under every trace ID it contains
+func (t *traceIndex) add(projectID uint, line string, at time.Time) {
+	ids := ExtractTraceIDs(line)
+	if len(ids) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lines == nil {
+		t.lines = make(map[string][]TraceLine)
+	}
+	for _, id := range ids {
+		lines, ok := t.lines[id]
+		if !ok {
+			t.order = append(t.order, id)
+			if len(t.order) > maxIndexedTraces {
+				delete(t.lines, t.order[0])
+				t.order = t.order[1:]
+			}
+		}
+		if len(lines) >= maxLinesPerTrace {
+			continue
+		}
+		ts := at
+		t.lines[id] = append(lines, TraceLine{ProjectID: projectID, Line: line, Timestamp: &ts, Source: TraceSourceLive})
+	}
+}
+
+// get returns a copy of the lines indexed for a trace
+func (t *traceIndex) get(id string) []TraceLine {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TraceLine(nil), t.lines[id]...)
+}
+
+// FindTrace gathers the log lines of a trace across all projects: lines
+// indexed as they were logged, plus any line in the log buffers or stored logs
+// containing the ID (covers ID formats the parser does not recognize and logs
+// from before the server started)
+func (m *Manager) FindTrace(traceID string) (*TraceResult, error) {
+	id := strings.ToLower(strings.TrimSpace(traceID))
+	result := &TraceResult{TraceID: id, Projects: []TraceProject{}, Lines: []TraceLine{}}
+	if id == "" {
+		return result, nil
+	}
+
+	var projects []struct {
+		ID       uint
+		Name     string
+		Logs     string
+		LogTimes string
+	}
+	if err := m.db.Table(tables.Name("projects")).Select("id, name, logs, log_times").Where("deleted_at IS NULL").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+
+	seen := make(map[string]bool)
+	key := func(projectID uint, line string) string { return fmt.Sprintf("%d\x00%s", projectID, line) }
+
+	live := m.traces.get(id)
+	sort.SliceStable(live, func(i, j int) bool { return live[i].Timestamp.Before(*live[j].Timestamp) })
+	for _, line := range live {
+		if _, ok := names[line.ProjectID]; !ok {
+			continue // Project deleted
+		}
+		seen[key(line.ProjectID, line.Line)] = true
+		line.ProjectName = names[line.ProjectID]
+		result.Lines = append(result.Lines, line)
+	}
+
+	for _, p := range projects {
+		entries := m.GetServiceLogEntries(p.ID)
+		source := TraceSourceBuffer
+		if len(entries) == 0 && p.Logs != "" {
+			entries = parseStoredLogs(p.Logs, p.LogTimes)
+			source = TraceSourceStored
+		}
+		for _, entry := range entries {
+			line := entry.Line
+			if !strings.Contains(strings.ToLower(line), id) || seen[key(p.ID, line)] {
+				continue
+			}
+			seen[key(p.ID, line)] = true
+			traceLine := TraceLine{ProjectID: p.ID, ProjectName: p.Name, Line: line, Source: source}
+			if !entry.Time.IsZero() {
+				at := entry.Time
+				traceLine.Timestamp = &at
+			}
+			result.Lines = append(result.Lines, traceLine)
+		}
+	}
+
+	// Projects started with this trace injected
+	startedWith := make(map[uint]bool)
+	m.mu.RLock()
+	for projectID, info := range m.processes {
+		if info.TraceID == id {
+			startedWith[projectID] = true
+		}
+	}
+	m.mu.RUnlock()
+
+	counts := make(map[uint]int)
+	for _, line := range result.Lines {
+		counts[line.ProjectID]++
+	}
+	for _, p := range projects {
+		if counts[p.ID] > 0 || startedWith[p.ID] {
+			result.Projects = append(result.Projects, TraceProject{ID: p.ID, Name: p.Name, Lines: counts[p.ID], StartedWith: startedWith[p.ID]})
+		}
+	}
+
+	return result, nil
+}
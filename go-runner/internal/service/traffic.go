@@ -0,0 +1,243 @@
+package service
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-runner/internal/types"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// trafficSamples is how many recent latencies are kept per project
+const trafficSamples = 1000
+
+// TrafficStats reports the requests proxied to a project since go-runner
+// started
+type TrafficStats struct {
+	ProjectID     uint              `json:"project_id"`
+	Requests      uint64            `json:"requests"`
+	Errors        uint64            `json:"errors"`       // 5xx responses, including failed proxying and starts
+	StatusCodes   map[string]uint64 `json:"status_codes"` // By status code
+	StatusClasses map[string]uint64 `json:"status_classes"`
+	P50Ms         float64           `json:"p50_ms"` // Over the recent requests
+	P90Ms         float64           `json:"p90_ms"`
+	P99Ms         float64           `json:"p99_ms"`
+	MaxMs         float64           `json:"max_ms"`
+	LastRequestAt *time.Time        `json:"last_request_at,omitempty"`
+}
+
+// TrafficWindow summarizes the requests proxied to a project during one
+// flush interval
+type TrafficWindow struct {
+	ProjectID     uint
+	Start         time.Time
+	End           time.Time
+	Requests      uint64
+	Errors        uint64
+	StatusClasses map[string]uint64
+	P50Ms         float64
+	P95Ms         float64
+	P99Ms         float64
+	MaxMs         float64
+}
+
+// projectTraffic holds the counters of one project
+type projectTraffic struct {
+	requests uint64
+	errors   uint64
+	codes    map[int]uint64
+	samples  []time.Duration // Ring of recent latencies
+	next     int
+	last     time.Time
+
+	// Current flush window
+	windowStart    time.Time
+	windowRequests uint64
+	windowErrors   uint64
+	windowClasses  map[string]uint64
+	windowSamples  []time.Duration
+}
+
+// trafficCounter counts proxied requests per project
+type trafficCounter struct {
+	mu       sync.Mutex
+	projects map[uint]*projectTraffic
+}
+
+// RecordProxiedRequest counts a request proxied to a project with its status
+// and duration. WebSocket upgrades are counted but their duration is the
+// lifetime of the connection, so it is left out of the latencies.
+func (m *Manager) RecordProxiedRequest(projectID uint, status int, d time.Duration) {
+	c := &m.traffic
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.projects == nil {
+		c.projects = make(map[uint]*projectTraffic)
+	}
+	t, ok := c.projects[projectID]
+	if !ok {
+		t = &projectTraffic{codes: make(map[int]uint64)}
+		c.projects[projectID] = t
+	}
+	if t.windowClasses == nil {
+		t.windowStart = time.Now()
+		t.windowClasses = make(map[string]uint64)
+	}
+
+	failed := status >= http.StatusInternalServerError
+	t.requests++
+	t.windowRequests++
+	if failed {
+		t.errors++
+		t.windowErrors++
+	}
+	t.codes[status]++
+	t.windowClasses[statusClass(status)]++
+	t.last = time.Now()
+
+	if status == http.StatusSwitchingProtocols {
+		return
+	}
+	if len(t.samples) < trafficSamples {
+		t.samples = append(t.samples, d)
+	} else {
+		t.samples[t.next] = d
+		t.next = (t.next + 1) % trafficSamples
+	}
+	t.windowSamples = append(t.windowSamples, d)
+}
+
+// ProjectTraffic returns the traffic of a project, nil when no request was
+// proxied to it
+func (m *Manager) ProjectTraffic(projectID uint) *TrafficStats {
+	c := &m.traffic
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.projects[projectID]
+	if !ok {
+		return nil
+	}
+
+	stats := &TrafficStats{
+		ProjectID:     projectID,
+		Requests:      t.requests,
+		Errors:        t.errors,
+		StatusCodes:   make(map[string]uint64, len(t.codes)),
+		StatusClasses: make(map[string]uint64),
+	}
+	for code, n := range t.codes {
+		stats.StatusCodes[strconv.Itoa(code)] = n
+		stats.StatusClasses[statusClass(code)] += n
+	}
+	if !t.last.IsZero() {
+		last := t.last
+		stats.LastRequestAt = &last
+	}
+	if len(t.samples) > 0 {
+		sorted := sortedDurations(t.samples)
+		stats.P50Ms = durationMs(percentile(sorted, 50))
+		stats.P90Ms = durationMs(percentile(sorted, 90))
+		stats.P99Ms = durationMs(percentile(sorted, 99))
+		stats.MaxMs = durationMs(sorted[len(sorted)-1])
+	}
+	return stats
+}
+
+// FlushTraffic calls onFlush every interval with the traffic of each project
+// that received requests since the previous flush
+func (m *Manager) FlushTraffic(interval time.Duration, onFlush func(window TrafficWindow)) {
+	for {
+		time.Sleep(interval)
+
+		var windows []TrafficWindow
+		now := time.Now()
+		m.traffic.mu.Lock()
+		for projectID, t := range m.traffic.projects {
+			if t.windowRequests == 0 {
+				continue
+			}
+			w := TrafficWindow{
+				ProjectID:     projectID,
+				Start:         t.windowStart,
+				End:           now,
+				Requests:      t.windowRequests,
+				Errors:        t.windowErrors,
+				StatusClasses: t.windowClasses,
+			}
+			if len(t.windowSamples) > 0 {
+				sorted := sortedDurations(t.windowSamples)
+				w.P50Ms = durationMs(percentile(sorted, 50))
+				w.P95Ms = durationMs(percentile(sorted, 95))
+				w.P99Ms = durationMs(percentile(sorted, 99))
+				w.MaxMs = durationMs(sorted[len(sorted)-1])
+			}
+			windows = append(windows, w)
+
+			t.windowStart = now
+			t.windowRequests, t.windowErrors = 0, 0
+			t.windowClasses = make(map[string]uint64)
+			t.windowSamples = nil
+		}
+		m.traffic.mu.Unlock()
+
+		if onFlush == nil {
+			continue
+		}
+		for _, w := range windows {
+			onFlush(w)
+		}
+	}
+}
+
+// GetServiceStats returns the runtime statistics of a project: CPU and memory
+// of its process, uptime and the requests proxied to it
+func (m *Manager) GetServiceStats(projectID uint) types.ServiceStats {
+	stats := types.ServiceStats{ProjectID: projectID, LastUpdated: time.Now()}
+
+	m.mu.RLock()
+	info, ok := m.processes[projectID]
+	m.mu.RUnlock()
+	if ok && !info.LogFollower && info.Process != nil && info.Process.Process != nil {
+		stats.Uptime = int64(time.Since(info.StartTime).Seconds())
+		if p, err := process.NewProcess(int32(info.Process.Process.Pid)); err == nil {
+			if cpu, err := p.CPUPercent(); err == nil {
+				stats.CPUUsage = cpu
+			}
+			if mem, err := p.MemoryInfo(); err == nil {
+				stats.MemoryUsage = int64(mem.RSS)
+			}
+		}
+	}
+
+	if traffic := m.ProjectTraffic(projectID); traffic != nil {
+		stats.RequestCount = int64(traffic.Requests)
+		stats.ErrorCount = int64(traffic.Errors)
+	}
+	return stats
+}
+
+// statusClass groups a status code as 1xx to 5xx
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// sortedDurations returns a sorted copy of durations
+func sortedDurations(durations []time.Duration) []time.Duration {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// percentile returns the p-th percentile of sorted durations (nearest rank)
+func percentile(sorted []time.Duration, p int) time.Duration {
+	return sorted[(len(sorted)*p+99)/100-1]
+}
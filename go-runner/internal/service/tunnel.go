@@ -0,0 +1,316 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tunnel providers
+const (
+	TunnelCloudflared = "cloudflared" // Cloudflare quick tunnel (trycloudflare.com)
+	TunnelNgrok       = "ngrok"
+	TunnelSSH         = "ssh" // Remote forward to the configured relay host
+)
+
+var (
+	// ErrNoTunnel is returned when a project has no open tunnel
+	ErrNoTunnel = errors.New("no tunnel for this project")
+	// ErrTunnelExists is returned with the open tunnel of a project by StartTunnel
+	ErrTunnelExists = errors.New("project already has a tunnel")
+)
+
+// tunnelOutputLines is how many output lines of a tunnel process are kept
+const tunnelOutputLines = 50
+
+var (
+	cloudflaredURLPattern = regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`)
+	ngrokURLPattern       = regexp.MustCompile(`url=(https://\S+)`)
+	sshAllocatedPattern   = regexp.MustCompile(`Allocated port (\d+)`)
+	anyURLPattern         = regexp.MustCompile(`https?://[^\s"']+`)
+)
+
+// TunnelOptions configures how tunnels are opened
+type TunnelOptions struct {
+	Provider     string        // Default provider, detected from PATH when empty
+	RelayHost    string        // ssh destination of the relay, e.g. tunnel@relay.example.com
+	RelayPort    int           // Remote port on the relay, 0 lets the relay allocate one
+	RelayURL     string        // Public URL of the relay, {port} is replaced by the remote port
+	StartTimeout time.Duration // How long to wait for the public URL
+}
+
+// Tunnel is a running tunnel exposing a project port publicly
+type Tunnel struct {
+	ProjectID uint      `json:"project_id"`
+	Provider  string    `json:"provider"`
+	LocalPort int       `json:"local_port"`
+	PublicURL string    `json:"public_url"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	output []string
+}
+
+// tunnelRegistry holds the open tunnels
+type tunnelRegistry struct {
+	mu      sync.Mutex
+	opts    TunnelOptions
+	tunnels map[uint]*Tunnel
+}
+
+// SetTunnelOptions sets the tunnel configuration
+func (m *Manager) SetTunnelOptions(opts TunnelOptions) {
+	m.tunnels.mu.Lock()
+	defer m.tunnels.mu.Unlock()
+	m.tunnels.opts = opts
+}
+
+// GetTunnel returns the open tunnel of a project, or nil
+func (m *Manager) GetTunnel(projectID uint) *Tunnel {
+	m.tunnels.mu.Lock()
+	defer m.tunnels.mu.Unlock()
+	return m.tunnels.tunnels[projectID]
+}
+
+// StartTunnel exposes a local port of a project through a tunnel provider
+// and waits for its public URL. The tunnel is closed when the project stops.
+func (m *Manager) StartTunnel(projectID uint, provider string, port int) (*Tunnel, error) {
+	m.tunnels.mu.Lock()
+	if t := m.tunnels.tunnels[projectID]; t != nil {
+		m.tunnels.mu.Unlock()
+		return t, ErrTunnelExists
+	}
+	opts := m.tunnels.opts
+	m.tunnels.mu.Unlock()
+
+	if provider == "" {
+		provider = opts.Provider
+	}
+	if provider == "" {
+		provider = detectTunnelProvider(opts)
+		if provider == "" {
+			return nil, fmt.Errorf("no tunnel provider: install cloudflared or ngrok, or configure tunnel.relay_host")
+		}
+	}
+
+	args, err := tunnelCommand(provider, port, opts)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, fmt.Errorf("%s not found on PATH", args[0])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.WaitDelay = 5 * time.Second
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	t := &Tunnel{
+		ProjectID: projectID,
+		Provider:  provider,
+		LocalPort: port,
+		PID:       cmd.Process.Pid,
+		StartedAt: time.Now(),
+		cmd:       cmd,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+
+	found := make(chan string, 1)
+	var readers sync.WaitGroup
+	scan := func(r io.Reader) {
+		defer readers.Done()
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := scanner.Text()
+			t.addOutput(line)
+			if url := tunnelURL(provider, line, opts); url != "" {
+				select {
+				case found <- url:
+				default:
+				}
+			}
+		}
+	}
+	readers.Add(2)
+	go scan(stdout)
+	go scan(stderr)
+	go func() {
+		readers.Wait()
+		cmd.Wait()
+		close(t.done)
+
+		m.tunnels.mu.Lock()
+		if m.tunnels.tunnels[projectID] == t {
+			delete(m.tunnels.tunnels, projectID)
+		}
+		m.tunnels.mu.Unlock()
+	}()
+
+	timeout := opts.StartTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	// A fixed relay port never prints its URL: the forward is up once ssh
+	// survives ExitOnForwardFailure for a few seconds
+	if provider == TunnelSSH && opts.RelayURL != "" && opts.RelayPort != 0 {
+		go func() {
+			select {
+			case <-time.After(3 * time.Second):
+				select {
+				case found <- strings.ReplaceAll(opts.RelayURL, "{port}", strconv.Itoa(opts.RelayPort)):
+				default:
+				}
+			case <-t.done:
+			}
+		}()
+	}
+
+	select {
+	case t.PublicURL = <-found:
+	case <-t.done:
+		return nil, fmt.Errorf("%s exited: %s", provider, t.lastOutput())
+	case <-time.After(timeout):
+		t.close()
+		return nil, fmt.Errorf("%s did not report a public URL within %s: %s", provider, timeout, t.lastOutput())
+	}
+
+	m.tunnels.mu.Lock()
+	defer m.tunnels.mu.Unlock()
+	if m.tunnels.tunnels == nil {
+		m.tunnels.tunnels = make(map[uint]*Tunnel)
+	}
+	if existing := m.tunnels.tunnels[projectID]; existing != nil {
+		// Lost a race with a concurrent start
+		go t.close()
+		return existing, ErrTunnelExists
+	}
+	m.tunnels.tunnels[projectID] = t
+	return t, nil
+}
+
+// StopTunnel closes the tunnel of a project
+func (m *Manager) StopTunnel(projectID uint) error {
+	m.tunnels.mu.Lock()
+	t := m.tunnels.tunnels[projectID]
+	delete(m.tunnels.tunnels, projectID)
+	m.tunnels.mu.Unlock()
+
+	if t == nil {
+		return ErrNoTunnel
+	}
+	t.close()
+	return nil
+}
+
+// close stops the tunnel process and waits for it to exit
+func (t *Tunnel) close() {
+	t.cancel()
+	<-t.done
+}
+
+func (t *Tunnel) addOutput(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.output = append(t.output, line)
+	if len(t.output) > tunnelOutputLines {
+		t.output = t.output[len(t.output)-tunnelOutputLines:]
+	}
+}
+
+// lastOutput returns the last output lines, for error messages
+func (t *Tunnel) lastOutput() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lines := t.output
+	if len(lines) > 5 {
+		lines = lines[len(lines)-5:]
+	}
+	if len(lines) == 0 {
+		return "no output"
+	}
+	return strings.Join(lines, " | ")
+}
+
+// detectTunnelProvider picks the first available provider
+func detectTunnelProvider(opts TunnelOptions) string {
+	for _, provider := range []string{TunnelCloudflared, TunnelNgrok} {
+		if _, err := exec.LookPath(provider); err == nil {
+			return provider
+		}
+	}
+	if opts.RelayHost != "" {
+		return TunnelSSH
+	}
+	return ""
+}
+
+// tunnelCommand returns the command opening a tunnel to a local port
+func tunnelCommand(provider string, port int, opts TunnelOptions) ([]string, error) {
+	switch provider {
+	case TunnelCloudflared:
+		return []string{"cloudflared", "tunnel", "--no-autoupdate", "--url", fmt.Sprintf("http://localhost:%d", port)}, nil
+	case TunnelNgrok:
+		return []string{"ngrok", "http", strconv.Itoa(port), "--log", "stdout", "--log-format", "logfmt"}, nil
+	case TunnelSSH:
+		if opts.RelayHost == "" {
+			return nil, fmt.Errorf("tunnel.relay_host is not configured")
+		}
+		return []string{"ssh", "-N",
+			"-o", "ExitOnForwardFailure=yes",
+			"-o", "ServerAliveInterval=30",
+			"-o", "StrictHostKeyChecking=accept-new",
+			"-R", fmt.Sprintf("%d:localhost:%d", opts.RelayPort, port),
+			opts.RelayHost,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown tunnel provider %q", provider)
+}
+
+// tunnelURL extracts the public URL from an output line of a provider
+func tunnelURL(provider, line string, opts TunnelOptions) string {
+	switch provider {
+	case TunnelCloudflared:
+		return cloudflaredURLPattern.FindString(line)
+	case TunnelNgrok:
+		if m := ngrokURLPattern.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	case TunnelSSH:
+		if opts.RelayURL == "" {
+			// Relays like localhost.run print the URL themselves
+			return anyURLPattern.FindString(line)
+		}
+		if m := sshAllocatedPattern.FindStringSubmatch(line); m != nil {
+			return strings.ReplaceAll(opts.RelayURL, "{port}", m[1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+)
+
+// wait_for defaults
+const (
+	defaultWaitForTimeout  = 60 * time.Second
+	defaultWaitForInterval = time.Second
+	waitForCheckTimeout    = 3 * time.Second
+)
+
+// WaitCondition is a condition that must hold before the command of a
+// project runs, such as its database accepting connections
+type WaitCondition struct {
+	Spec   string `json:"spec"`   // As written in wait_for
+	Kind   string `json:"kind"`   // tcp, http, file, postgres, mysql, redis
+	Target string `json:"target"` // host:port, URL, path or connection string
+}
+
+// WaitStatus is a start waiting for the wait_for conditions of its project
+type WaitStatus struct {
+	Since    time.Time `json:"since"`
+	Deadline time.Time `json:"deadline"` // When the start fails
+	Attempts int       `json:"attempts"`
+	Pending  []string  `json:"pending"` // Conditions not met at the last attempt, with the reason
+}
+
+// waitStates holds the starts waiting for their conditions
+type waitStates struct {
+	mu     sync.Mutex
+	active map[uint]*WaitStatus
+}
+
+// ParseWaitFor parses a comma-separated wait_for: tcp://host:port (or just
+// host:port), http:// and https:// URLs answering below 400, file:// paths
+// that must exist (relative to the working directory unless absolute) and
+// postgres://, mysql:// or redis:// servers accepting connections
+func ParseWaitFor(spec string) ([]WaitCondition, error) {
+	var conditions []WaitCondition
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		condition, err := parseWaitCondition(part)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// ValidateWaitFor checks a wait_for as saved, leaving out the conditions with
+// placeholders, which are only known at start
+func ValidateWaitFor(spec string) error {
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || strings.Contains(part, "{{") {
+			continue
+		}
+		if _, err := parseWaitCondition(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseWaitCondition(spec string) (WaitCondition, error) {
+	scheme, rest, found := strings.Cut(spec, "://")
+	if !found {
+		scheme, rest = "tcp", spec
+	}
+	condition := WaitCondition{Spec: spec, Kind: scheme, Target: spec}
+	switch scheme {
+	case "tcp":
+		if _, port, err := net.SplitHostPort(rest); err != nil || port == "" {
+			return condition, fmt.Errorf("%q: expected host:port", spec)
+		}
+		condition.Target = rest
+	case "http", "https":
+		if u, err := url.Parse(spec); err != nil || u.Host == "" {
+			return condition, fmt.Errorf("%q: invalid URL", spec)
+		}
+		condition.Kind = "http"
+	case "file":
+		if rest == "" {
+			return condition, fmt.Errorf("%q: expected file:///absolute/path or file://relative/path", spec)
+		}
+		condition.Target = rest
+	case "postgres", "postgresql", "mysql", "redis":
+		engine, err := DatabaseEngine(spec)
+		if err != nil {
+			return condition, fmt.Errorf("%q: %v", spec, err)
+		}
+		condition.Kind = engine
+	default:
+		return condition, fmt.Errorf("%q: unsupported scheme %q, expected tcp, http, https, file, postgres, mysql or redis", spec, scheme)
+	}
+	return condition, nil
+}
+
+// Check returns nil when the condition holds, dir being the working
+// directory relative file paths are resolved in
+func (c WaitCondition) Check(ctx context.Context, dir string) error {
+	ctx, cancel := context.WithTimeout(ctx, waitForCheckTimeout)
+	defer cancel()
+
+	switch c.Kind {
+	case "tcp":
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", c.Target)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case "http":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		return nil
+	case "file":
+		path := c.Target
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("%s does not exist", path)
+			}
+			return err
+		}
+		return nil
+	default:
+		if stats := ProbeDatabase(ctx, c.Target); !stats.Healthy {
+			return errors.New(stats.Error)
+		}
+		return nil
+	}
+}
+
+// waitForConditions waits until the wait_for conditions of a project hold,
+// checking them every wait_for_interval seconds. It fails once
+// wait_for_timeout seconds passed with conditions still not met, or with
+// ErrStartCancelled when the project is stopped meanwhile.
+func (m *Manager) waitForConditions(start *pendingStart, projectID uint) error {
+	var p struct {
+		ID              uint
+		Name            string
+		Path            string
+		WorkingDir      string
+		Environment     string
+		Port            int
+		GroupID         *uint
+		WaitFor         string
+		WaitForTimeout  int
+		WaitForInterval int
+	}
+	if err := m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Take(&p).Error; err != nil || strings.TrimSpace(p.WaitFor) == "" {
+		return nil
+	}
+
+	// {{project "db".port}} and the other placeholders work as in the command
+	tmpl := m.buildTemplateContext(p.ID, p.Name, p.Path, p.Environment, p.Port, p.GroupID)
+	conditions, err := ParseWaitFor(tmpl.Expand(p.WaitFor))
+	if err != nil {
+		return fmt.Errorf("invalid wait_for: %v", err)
+	}
+	dir := p.Path
+	if p.WorkingDir != "" {
+		dir = p.WorkingDir
+	}
+	timeout, interval := defaultWaitForTimeout, defaultWaitForInterval
+	if p.WaitForTimeout > 0 {
+		timeout = time.Duration(p.WaitForTimeout) * time.Second
+	}
+	if p.WaitForInterval > 0 {
+		interval = time.Duration(p.WaitForInterval) * time.Second
+	}
+
+	now := time.Now()
+	status := &WaitStatus{Since: now, Deadline: now.Add(timeout)}
+	m.waits.mu.Lock()
+	if m.waits.active == nil {
+		m.waits.active = make(map[uint]*WaitStatus)
+	}
+	m.waits.active[projectID] = status
+	m.waits.mu.Unlock()
+	defer func() {
+		m.waits.mu.Lock()
+		delete(m.waits.active, projectID)
+		m.waits.mu.Unlock()
+	}()
+
+	for attempt := 1; ; attempt++ {
+		var pending []string
+		for _, condition := range conditions {
+			if err := condition.Check(start.ctx, dir); err != nil {
+				pending = append(pending, fmt.Sprintf("%s: %v", condition.Spec, err))
+			}
+		}
+		if start.ctx.Err() != nil {
+			return ErrStartCancelled
+		}
+		m.waits.mu.Lock()
+		status.Attempts, status.Pending = attempt, pending
+		m.waits.mu.Unlock()
+
+		if len(pending) == 0 {
+			if attempt > 1 {
+				log.Printf("✅ Project %d: wait_for met after %s", projectID, time.Since(now).Round(time.Second))
+			}
+			return nil
+		}
+		if attempt == 1 {
+			log.Printf("⏳ Project %d waiting for %s", projectID, strings.Join(pending, "; "))
+			m.db.Table(tables.Name("projects")).Where("id = ?", projectID).Update("status", string(types.StatusStarting))
+			m.RecordStatus(projectID, string(types.StatusStarting), "Waiting for "+strings.Join(pending, "; "))
+			start.markWaiting()
+		}
+		if time.Now().Add(interval).After(status.Deadline) {
+			return fmt.Errorf("wait_for not met after %s: %s", timeout, strings.Join(pending, "; "))
+		}
+		if !start.sleep(interval) {
+			return ErrStartCancelled
+		}
+	}
+}
+
+// WaitForStatus returns the wait_for conditions a start of the project is
+// waiting for, nil when it is not waiting
+func (m *Manager) WaitForStatus(projectID uint) *WaitStatus {
+	m.waits.mu.Lock()
+	defer m.waits.mu.Unlock()
+	status, ok := m.waits.active[projectID]
+	if !ok {
+		return nil
+	}
+	copied := *status
+	copied.Pending = append([]string(nil), status.Pending...)
+	return &copied
+}
@@ -0,0 +1,177 @@
+package sysservice
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime"
+)
+
+const usage = `Usage: go-runner service <command> [flags]
+
+Install go-runner itself as an operating system service
+(systemd on Linux, launchd on macOS, Windows service on Windows).
+
+Commands:
+  install     Generate the unit file, install and start the service
+  uninstall   Stop the service and remove the unit file
+  status      Show the service manager status
+  print       Print the generated unit file without installing it
+  run         Run the server under the service manager (used by the unit)
+
+Flags:
+`
+
+// RunCLI handles `go-runner service ...` and returns the process exit code.
+// serve runs the server until its context is cancelled.
+func RunCLI(args []string, serve func(ctx context.Context)) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage(os.Stdout, newFlagSet(&cliFlags{}))
+		return 0
+	}
+
+	command := args[0]
+	var flags cliFlags
+	fs := newFlagSet(&flags)
+	fs.Usage = func() { printUsage(os.Stderr, fs) }
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if command == "run" {
+		if err := runCommand(&flags, serve); err != nil {
+			log.Printf("❌ %v", err)
+			return 1
+		}
+		return 0
+	}
+
+	platformName := flags.platform
+	if platformName == "" {
+		platformName = runtime.GOOS
+	} else if command != "print" {
+		fmt.Fprintln(os.Stderr, "--platform can only be used with print")
+		return 2
+	}
+	platform, err := PlatformFor(platformName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg, err := flags.config(platform)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch command {
+	case "install":
+		if err := platform.Install(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Install failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Installed %s as a %s service\n", cfg.Name, platform.Name())
+		if path := platform.UnitPath(cfg); path != "" {
+			fmt.Printf("   Unit file:   %s\n", path)
+		}
+		fmt.Printf("   Working dir: %s\n", cfg.WorkingDir)
+		fmt.Printf("   Log file:    %s\n", cfg.LogFile())
+	case "uninstall":
+		if err := platform.Uninstall(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Uninstall failed: %v\n", err)
+			return 1
+		}
+		fmt.Printf("✅ Uninstalled %s (logs kept in %s)\n", cfg.Name, cfg.LogDir)
+	case "status":
+		output, err := platform.Status(cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Print(output)
+	case "print":
+		content, err := platform.Render(cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if path := platform.UnitPath(cfg); path != "" {
+			fmt.Printf("# %s\n", path)
+		}
+		fmt.Print(content)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		printUsage(os.Stderr, fs)
+		return 2
+	}
+	return 0
+}
+
+// cliFlags holds the flags shared by all service subcommands
+type cliFlags struct {
+	name     string
+	user     bool
+	workDir  string
+	logDir   string
+	logFile  string
+	platform string
+}
+
+func newFlagSet(flags *cliFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("service", flag.ContinueOnError)
+	fs.StringVar(&flags.name, "name", "go-runner", "service name")
+	fs.BoolVar(&flags.user, "user", false, "install a per-user service (systemd --user, LaunchAgent) instead of a system-wide one")
+	fs.StringVar(&flags.workDir, "workdir", "", "working directory holding config.yaml and data/ (default: current directory)")
+	fs.StringVar(&flags.logDir, "log-dir", "", "log directory (default: platform specific)")
+	fs.StringVar(&flags.logFile, "log-file", "", "run: append server output to this file")
+	fs.StringVar(&flags.platform, "platform", "", "print: render for another platform (systemd, launchd, windows)")
+	return fs
+}
+
+// config builds the install configuration from the defaults and flags
+func (f *cliFlags) config(platform Platform) (*Config, error) {
+	cfg, err := DefaultConfig(f.user)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Name = f.name
+	if f.workDir != "" {
+		cfg.WorkingDir = f.workDir
+	}
+	cfg.LogDir = f.logDir
+	if cfg.LogDir == "" {
+		cfg.LogDir = platform.DefaultLogDir(cfg)
+	}
+	return cfg, nil
+}
+
+// runCommand runs the server in the configured working directory, under the
+// platform's service manager
+func runCommand(flags *cliFlags, serve func(ctx context.Context)) error {
+	if flags.workDir != "" {
+		if err := os.Chdir(flags.workDir); err != nil {
+			return fmt.Errorf("failed to change to working directory: %v", err)
+		}
+	}
+
+	if flags.logFile != "" {
+		file, err := redirectOutput(flags.logFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+	}
+
+	return runService(flags.name, serve)
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprint(w, usage)
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
@@ -0,0 +1,120 @@
+package sysservice
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+var launchdTemplate = template.Must(template.New("launchd").Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{.Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{.Executable}}</string>
+		<string>service</string>
+		<string>run</string>
+	</array>
+	<key>WorkingDirectory</key>
+	<string>{{.WorkingDir}}</string>
+	{{- if .Path}}
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>PATH</key>
+		<string>{{.Path}}</string>
+	</dict>
+	{{- end}}
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>{{.LogFile}}</string>
+	<key>StandardErrorPath</key>
+	<string>{{.LogFile}}</string>
+</dict>
+</plist>
+`))
+
+// launchd installs go-runner as a LaunchAgent or LaunchDaemon (macOS)
+type launchd struct{}
+
+// launchdConfig adds the launchd label to the template data
+type launchdConfig struct {
+	*Config
+	Label string
+}
+
+func (launchd) Name() string { return "launchd" }
+
+func (launchd) label(cfg *Config) string {
+	return "local." + cfg.Name
+}
+
+func (l launchd) UnitPath(cfg *Config) string {
+	if cfg.User {
+		return filepath.Join(homeDir(), "Library", "LaunchAgents", l.label(cfg)+".plist")
+	}
+	return filepath.Join("/Library/LaunchDaemons", l.label(cfg)+".plist")
+}
+
+func (launchd) DefaultLogDir(cfg *Config) string {
+	if cfg.User {
+		return filepath.Join(homeDir(), "Library", "Logs", cfg.Name)
+	}
+	return filepath.Join("/Library/Logs", cfg.Name)
+}
+
+func (l launchd) Render(cfg *Config) (string, error) {
+	var buf bytes.Buffer
+	if err := launchdTemplate.Execute(&buf, launchdConfig{Config: cfg, Label: l.label(cfg)}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (l launchd) Install(cfg *Config) error {
+	if err := checkExecutable(cfg); err != nil {
+		return err
+	}
+
+	plist, err := l.Render(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+		return err
+	}
+
+	path := l.UnitPath(cfg)
+	// Reinstalling: unload the previous definition first
+	run("launchctl", "unload", path)
+	if err := writeUnit(path, plist); err != nil {
+		return err
+	}
+
+	_, err = run("launchctl", "load", "-w", path)
+	return err
+}
+
+func (l launchd) Uninstall(cfg *Config) error {
+	path := l.UnitPath(cfg)
+	// Ignore errors: the job may not be loaded
+	run("launchctl", "unload", "-w", path)
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l launchd) Status(cfg *Config) (string, error) {
+	return run("launchctl", "list", l.label(cfg))
+}
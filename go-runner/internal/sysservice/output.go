@@ -0,0 +1,31 @@
+package sysservice
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redirectOutput sends server output to a log file. Used where the service
+// manager does not capture stdout/stderr (Windows services).
+func redirectOutput(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %v", err)
+	}
+
+	log.SetOutput(file)
+	gin.DefaultWriter = file
+	gin.DefaultErrorWriter = file
+	os.Stdout = file
+	os.Stderr = file
+
+	return file, nil
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package sysservice
+
+import (
+	"context"
+	"os/signal"
+	"syscall"
+)
+
+// runService runs the server until systemd or launchd stops it with SIGTERM
+func runService(name string, serve func(ctx context.Context)) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serve(ctx)
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package sysservice
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/windows/svc"
+)
+
+// runService runs the server under the Windows service control manager, or
+// until Ctrl+C when started from a console
+func runService(name string, serve func(ctx context.Context)) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil {
+		return fmt.Errorf("failed to detect service mode: %v", err)
+	}
+
+	if !isService {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		serve(ctx)
+		return nil
+	}
+
+	return svc.Run(name, &serviceHandler{serve: serve})
+}
+
+// serviceHandler implements svc.Handler around the server
+type serviceHandler struct {
+	serve func(ctx context.Context)
+}
+
+func (h *serviceHandler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		h.serve(ctx)
+		close(done)
+	}()
+
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+				<-done
+				return false, 0
+			}
+		case <-done:
+			// Server exited on its own
+			cancel()
+			return false, 1
+		}
+	}
+}
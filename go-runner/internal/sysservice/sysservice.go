@@ -0,0 +1,136 @@
+// Package sysservice installs go-runner itself as an operating system service
+// (systemd unit, launchd agent/daemon or Windows service) so it survives
+// reboots without manual setup.
+package sysservice
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Config describes how go-runner is installed as a service
+type Config struct {
+	Name        string // Service / unit name
+	DisplayName string
+	Description string
+	Executable  string // Absolute path of the go-runner binary
+	WorkingDir  string // Directory holding config.yaml and data/
+	LogDir      string // Directory receiving go-runner.log
+	User        bool   // Per-user service (systemd --user, LaunchAgent) instead of system-wide
+	RunAs       string // Account the system-wide service runs as (Linux)
+	Path        string // PATH handed to the service so project toolchains resolve
+}
+
+// LogFile returns the path of the service log file
+func (c *Config) LogFile() string {
+	return filepath.Join(c.LogDir, c.Name+".log")
+}
+
+// Platform installs and removes the service on one operating system
+type Platform interface {
+	// Name returns the service manager name (systemd, launchd, windows)
+	Name() string
+	// UnitPath returns where the generated unit file is written, if any
+	UnitPath(cfg *Config) string
+	// Render returns the generated unit file or install script
+	Render(cfg *Config) (string, error)
+	// DefaultLogDir returns the log directory used when none is configured
+	DefaultLogDir(cfg *Config) string
+	Install(cfg *Config) error
+	Uninstall(cfg *Config) error
+	Status(cfg *Config) (string, error)
+}
+
+// PlatformFor returns the platform for a GOOS value or service manager name
+func PlatformFor(name string) (Platform, error) {
+	switch name {
+	case "linux", "systemd":
+		return systemd{}, nil
+	case "darwin", "launchd":
+		return launchd{}, nil
+	case "windows":
+		return windowsService{}, nil
+	}
+	return nil, fmt.Errorf("unsupported platform %q (supported: systemd, launchd, windows)", name)
+}
+
+// DefaultConfig builds a configuration for the running binary and current
+// working directory
+func DefaultConfig(user bool) (*Config, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate executable: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %v", err)
+	}
+
+	cfg := &Config{
+		Name:        "go-runner",
+		DisplayName: "Go Runner",
+		Description: "Go Runner - local microservice management platform",
+		Executable:  executable,
+		WorkingDir:  workingDir,
+		User:        user,
+		Path:        os.Getenv("PATH"),
+	}
+
+	// Run a system-wide service as the developer who installed it, not root,
+	// so projects keep their usual permissions
+	if runAs := os.Getenv("SUDO_USER"); runAs != "" {
+		cfg.RunAs = runAs
+	} else if runAs := os.Getenv("USER"); runAs != "" && runAs != "root" {
+		cfg.RunAs = runAs
+	}
+
+	return cfg, nil
+}
+
+// checkExecutable refuses to install binaries built by `go run`, which are
+// deleted when it exits
+func checkExecutable(cfg *Config) error {
+	if strings.Contains(cfg.Executable, "go-build") {
+		return fmt.Errorf("%s is a temporary `go run` binary; build go-runner first (make build) and run the installed binary", cfg.Executable)
+	}
+	if _, err := os.Stat(cfg.Executable); err != nil {
+		return fmt.Errorf("executable not found: %v", err)
+	}
+	return nil
+}
+
+// writeUnit writes a generated unit file, creating its directory
+func writeUnit(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// run executes a service manager command and includes its output in errors
+func run(name string, args ...string) (string, error) {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("%s %s: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// homeDir returns the home directory, falling back to the working directory
+func homeDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return home
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
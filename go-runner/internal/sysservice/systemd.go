@@ -0,0 +1,136 @@
+package sysservice
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var systemdTemplate = template.Must(template.New("systemd").Funcs(template.FuncMap{
+	"command": systemdCommand,
+	"quote":   systemdQuote,
+	"escape":  systemdEscape,
+}).Parse(`[Unit]
+Description={{escape .Description}}
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+ExecStart={{command .Executable}} service run
+WorkingDirectory={{escape .WorkingDir}}
+{{- if and (not .User) .RunAs}}
+User={{.RunAs}}
+{{- end}}
+{{- if .Path}}
+Environment={{quote (print "PATH=" .Path)}}
+{{- end}}
+Restart=on-failure
+RestartSec=5
+StandardOutput=append:{{escape .LogFile}}
+StandardError=append:{{escape .LogFile}}
+
+[Install]
+WantedBy={{if .User}}default.target{{else}}multi-user.target{{end}}
+`))
+
+// systemdEscape escapes the % specifiers of a value systemd takes as a
+// whole, such as WorkingDirectory, so that paths keep their spaces and percents
+func systemdEscape(value string) string {
+	return strings.ReplaceAll(value, "%", "%%")
+}
+
+// systemdQuote quotes a value systemd splits into words, such as an
+// Environment assignment, so that spaces, quotes and backslashes survive
+func systemdQuote(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(systemdEscape(value))
+	return `"` + value + `"`
+}
+
+// systemdCommand quotes a word of ExecStart, which also expands $ variables
+func systemdCommand(word string) string {
+	return systemdQuote(strings.ReplaceAll(word, "$", "$$"))
+}
+
+// systemd installs go-runner as a systemd unit (Linux)
+type systemd struct{}
+
+func (systemd) Name() string { return "systemd" }
+
+func (systemd) UnitPath(cfg *Config) string {
+	if cfg.User {
+		return filepath.Join(homeDir(), ".config", "systemd", "user", cfg.Name+".service")
+	}
+	return filepath.Join("/etc/systemd/system", cfg.Name+".service")
+}
+
+func (systemd) DefaultLogDir(cfg *Config) string {
+	if cfg.User {
+		return filepath.Join(homeDir(), ".local", "state", cfg.Name)
+	}
+	return filepath.Join("/var/log", cfg.Name)
+}
+
+func (systemd) Render(cfg *Config) (string, error) {
+	var buf bytes.Buffer
+	if err := systemdTemplate.Execute(&buf, cfg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (s systemd) Install(cfg *Config) error {
+	if err := checkExecutable(cfg); err != nil {
+		return err
+	}
+
+	unit, err := s.Render(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+		return err
+	}
+	if !cfg.User && cfg.RunAs != "" {
+		// The service user must be able to append to its log
+		run("chown", cfg.RunAs, cfg.LogDir)
+	}
+	if err := writeUnit(s.UnitPath(cfg), unit); err != nil {
+		return err
+	}
+
+	if _, err := run("systemctl", s.args(cfg, "daemon-reload")...); err != nil {
+		return err
+	}
+	_, err = run("systemctl", s.args(cfg, "enable", "--now", cfg.Name)...)
+	return err
+}
+
+func (s systemd) Uninstall(cfg *Config) error {
+	// Ignore errors: the unit may already be stopped or disabled
+	run("systemctl", s.args(cfg, "disable", "--now", cfg.Name)...)
+
+	if err := os.Remove(s.UnitPath(cfg)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	_, err := run("systemctl", s.args(cfg, "daemon-reload")...)
+	return err
+}
+
+func (s systemd) Status(cfg *Config) (string, error) {
+	// systemctl status exits non-zero for inactive units, the output is still useful
+	output, err := run("systemctl", s.args(cfg, "status", "--no-pager", cfg.Name)...)
+	if output != "" {
+		return output, nil
+	}
+	return "", err
+}
+
+func (systemd) args(cfg *Config, args ...string) []string {
+	if cfg.User {
+		return append([]string{"--user"}, args...)
+	}
+	return args
+}
@@ -0,0 +1,52 @@
+package sysservice
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSystemdRenderQuotesPaths(t *testing.T) {
+	cfg := &Config{
+		Name:        "go-runner",
+		Description: "go-runner 100%",
+		Executable:  "/opt/My Tools/go-runner",
+		WorkingDir:  "/home/me/My Projects/runner",
+		LogDir:      "/var/log/go runner",
+		Path:        `/opt/a "b"/bin:/usr/bin`,
+	}
+
+	unit, err := systemd{}.Render(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`Description=go-runner 100%%`,
+		`ExecStart="/opt/My Tools/go-runner" service run`,
+		`WorkingDirectory=/home/me/My Projects/runner`,
+		`Environment="PATH=/opt/a \"b\"/bin:/usr/bin"`,
+		`StandardOutput=append:/var/log/go runner/go-runner.log`,
+	} {
+		if !strings.Contains(unit, line+"\n") {
+			t.Errorf("unit lacks %q:\n%s", line, unit)
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct {
+		in, quote, command string
+	}{
+		{"/usr/bin/go-runner", `"/usr/bin/go-runner"`, `"/usr/bin/go-runner"`},
+		{"/opt/my app/run", `"/opt/my app/run"`, `"/opt/my app/run"`},
+		{`C:\tools\"x"`, `"C:\\tools\\\"x\""`, `"C:\\tools\\\"x\""`},
+		{"/opt/50%/$HOME", `"/opt/50%%/$HOME"`, `"/opt/50%%/$$HOME"`},
+	}
+	for _, tt := range tests {
+		if got := systemdQuote(tt.in); got != tt.quote {
+			t.Errorf("systemdQuote(%q) = %s, want %s", tt.in, got, tt.quote)
+		}
+		if got := systemdCommand(tt.in); got != tt.command {
+			t.Errorf("systemdCommand(%q) = %s, want %s", tt.in, got, tt.command)
+		}
+	}
+}
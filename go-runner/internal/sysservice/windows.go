@@ -0,0 +1,89 @@
+package sysservice
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// windowsService installs go-runner as a Windows service through sc.exe.
+// The binary handles the service control protocol itself (see run_windows.go).
+type windowsService struct{}
+
+func (windowsService) Name() string { return "windows" }
+
+// UnitPath is empty: Windows services are registered in the service
+// control manager, not in a unit file
+func (windowsService) UnitPath(cfg *Config) string { return "" }
+
+func (windowsService) DefaultLogDir(cfg *Config) string {
+	programData := os.Getenv("ProgramData")
+	if programData == "" {
+		programData = `C:\ProgramData`
+	}
+	return filepath.Join(programData, cfg.Name, "logs")
+}
+
+// binPath is the command line registered with the service control manager.
+// Services start in System32, so the working directory and log file are
+// passed explicitly.
+func (windowsService) binPath(cfg *Config) string {
+	return fmt.Sprintf(`"%s" service run --workdir "%s" --log-file "%s"`, cfg.Executable, cfg.WorkingDir, cfg.LogFile())
+}
+
+// commands returns the sc.exe invocations that register the service
+func (w windowsService) commands(cfg *Config) [][]string {
+	return [][]string{
+		{"create", cfg.Name, "binPath=", w.binPath(cfg), "start=", "auto", "DisplayName=", cfg.DisplayName},
+		{"description", cfg.Name, cfg.Description},
+		{"failure", cfg.Name, "reset=", "86400", "actions=", "restart/5000/restart/5000/restart/5000"},
+		{"start", cfg.Name},
+	}
+}
+
+// Render returns the equivalent install script, for review or manual install
+func (w windowsService) Render(cfg *Config) (string, error) {
+	var b strings.Builder
+	b.WriteString(":: Run from an elevated command prompt\n")
+	fmt.Fprintf(&b, "mkdir \"%s\"\n", cfg.LogDir)
+	for _, args := range w.commands(cfg) {
+		b.WriteString("sc.exe")
+		for _, arg := range args {
+			if strings.ContainsAny(arg, " \"") {
+				arg = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+			}
+			b.WriteString(" " + arg)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+func (w windowsService) Install(cfg *Config) error {
+	if err := checkExecutable(cfg); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+		return err
+	}
+
+	for _, args := range w.commands(cfg) {
+		if _, err := run("sc.exe", args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (windowsService) Uninstall(cfg *Config) error {
+	// Ignore errors: the service may already be stopped
+	run("sc.exe", "stop", cfg.Name)
+
+	_, err := run("sc.exe", "delete", cfg.Name)
+	return err
+}
+
+func (windowsService) Status(cfg *Config) (string, error) {
+	return run("sc.exe", "query", cfg.Name)
+}
@@ -0,0 +1,560 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/jobs"
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// cleanupToolTimeout bounds each package manager or docker call
+const cleanupToolTimeout = 2 * time.Minute
+
+// goCacheMaxAge is the age of unused Go build cache entries considered old.
+// go only trims entries unused for 5 days, and only after a build.
+const goCacheMaxAge = 7 * 24 * time.Hour
+
+// Retention of go-runner data offered for cleanup
+const (
+	cleanupMetricsAge = 7 * 24 * time.Hour
+	cleanupAlertsAge  = 30 * 24 * time.Hour
+)
+
+// Cleanup categories
+const (
+	CleanupCategoryCache    = "cache"     // Package manager and build caches
+	CleanupCategoryDocker   = "docker"    // Docker images and build cache
+	CleanupCategoryGoRunner = "go-runner" // go-runner's own data
+)
+
+// CleanupItem is reclaimable space found by the cleanup scan
+type CleanupItem struct {
+	ID          string `json:"id"`
+	Category    string `json:"category"` // cache, docker, go-runner
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Path        string `json:"path,omitempty"`
+	Bytes       int64  `json:"bytes"`          // Reclaimable bytes, 0 when only rows are known
+	Rows        int64  `json:"rows,omitempty"` // Database rows removed
+	Action      string `json:"action"`         // What the cleanup runs
+	Available   bool   `json:"available"`
+	Reason      string `json:"reason,omitempty"` // Why the item is unavailable
+}
+
+// CleanupReport lists the cleanup items of the host
+type CleanupReport struct {
+	Items      []CleanupItem `json:"items"`
+	TotalBytes int64         `json:"total_bytes"` // Of available items
+	ScannedAt  time.Time     `json:"scanned_at"`
+}
+
+// CleanupRequest selects the items to clean
+type CleanupRequest struct {
+	Items []string `json:"items" binding:"required,min=1"` // Item IDs from GET /system/cleanup
+}
+
+// CleanupOutcome is the result of cleaning one item
+type CleanupOutcome struct {
+	ID         string `json:"id"`
+	FreedBytes int64  `json:"freed_bytes"` // Measured for directories, estimated otherwise
+	Error      string `json:"error,omitempty"`
+}
+
+// CleanupRunResult is the result of a cleanup job
+type CleanupRunResult struct {
+	Items      []CleanupOutcome `json:"items"`
+	FreedBytes int64            `json:"freed_bytes"`
+}
+
+// cleanupSource scans and cleans one kind of reclaimable space
+type cleanupSource struct {
+	id          string
+	category    string
+	title       string
+	description string
+	action      string
+	scan        func(ctx context.Context, h *Handler, item *CleanupItem) error
+	clean       func(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error
+}
+
+// cleanupSources are the items offered, in order
+var cleanupSources = []cleanupSource{
+	{
+		id: "npm-cache", category: CleanupCategoryCache, title: "npm cache",
+		description: "Downloaded packages of npm, fetched again on the next install",
+		action:      "npm cache clean --force",
+		scan:        scanToolDir("npm", "config", "get", "cache"),
+		clean:       cleanWithTool("npm", "cache", "clean", "--force"),
+	},
+	{
+		id: "yarn-cache", category: CleanupCategoryCache, title: "Yarn cache",
+		description: "Downloaded packages of Yarn",
+		action:      "yarn cache clean",
+		scan:        scanToolDir("yarn", "cache", "dir"),
+		clean:       cleanWithTool("yarn", "cache", "clean"),
+	},
+	{
+		id: "pnpm-store", category: CleanupCategoryCache, title: "pnpm store",
+		description: "Packages of the pnpm store; only packages no project references are removed, so less than the store size may be freed",
+		action:      "pnpm store prune",
+		scan:        scanToolDir("pnpm", "store", "path"),
+		clean:       cleanWithTool("pnpm", "store", "prune"),
+	},
+	{
+		id: "pip-cache", category: CleanupCategoryCache, title: "pip cache",
+		description: "Downloaded and built Python packages",
+		action:      "pip cache purge",
+		scan:        scanToolDir("pip", "cache", "dir"),
+		clean:       cleanWithTool("pip", "cache", "purge"),
+	},
+	{
+		id: "go-build-cache", category: CleanupCategoryCache, title: "Old Go build cache",
+		description: "Go build cache entries unused for 7 days",
+		action:      "Remove old entries of GOCACHE",
+		scan:        scanGoBuildCache,
+		clean:       cleanGoBuildCache,
+	},
+	{
+		id: "docker-dangling-images", category: CleanupCategoryDocker, title: "Dangling Docker images",
+		description: "Untagged images left behind by rebuilds",
+		action:      "docker image prune -f",
+		scan:        scanDockerDanglingImages,
+		clean:       cleanWithTool("docker", "image", "prune", "-f"),
+	},
+	{
+		id: "docker-build-cache", category: CleanupCategoryDocker, title: "Docker build cache",
+		description: "Reclaimable layers of the Docker build cache",
+		action:      "docker builder prune -f",
+		scan:        scanDockerBuildCache,
+		clean:       cleanWithTool("docker", "builder", "prune", "-f"),
+	},
+	{
+		id: "go-runner-metrics", category: CleanupCategoryGoRunner, title: "Old system metrics",
+		description: "System metrics older than 7 days",
+		action:      "Delete the metrics",
+		scan: func(ctx context.Context, h *Handler, item *CleanupItem) error {
+			return h.db.Model(&SystemMetrics{}).Where("timestamp < ?", time.Now().Add(-cleanupMetricsAge)).Count(&item.Rows).Error
+		},
+		clean: func(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error {
+			return h.db.Where("timestamp < ?", time.Now().Add(-cleanupMetricsAge)).Delete(&SystemMetrics{}).Error
+		},
+	},
+	{
+		id: "go-runner-alerts", category: CleanupCategoryGoRunner, title: "Resolved alerts",
+		description: "System alerts resolved more than 30 days ago",
+		action:      "Delete the alerts",
+		scan: func(ctx context.Context, h *Handler, item *CleanupItem) error {
+			return h.db.Model(&SystemAlert{}).Where("resolved_at < ?", time.Now().Add(-cleanupAlertsAge)).Count(&item.Rows).Error
+		},
+		clean: func(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error {
+			return h.db.Where("resolved_at < ?", time.Now().Add(-cleanupAlertsAge)).Delete(&SystemAlert{}).Error
+		},
+	},
+	{
+		id: "go-runner-deleted-projects", category: CleanupCategoryGoRunner, title: "Logs of deleted projects",
+		description: "Deleted projects are kept with their stored logs and everything recorded about them; purge them for good",
+		action:      "Purge deleted projects",
+		scan:        scanDeletedProjects,
+		clean:       cleanDeletedProjects,
+	},
+	{
+		id: "go-runner-database", category: CleanupCategoryGoRunner, title: "Unused database pages",
+		description: "Free pages of the SQLite database left by deletions, returned to the filesystem by VACUUM",
+		action:      "VACUUM",
+		scan:        scanSQLiteFreePages,
+		clean: func(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error {
+			return h.db.Exec("VACUUM").Error
+		},
+	},
+}
+
+// GetCleanup godoc
+// @Summary      Scan for reclaimable space
+// @Description  List reclaimable space on the host: package manager caches (npm, Yarn, pnpm, pip), Go build cache entries unused for 7 days, dangling Docker images and build cache, and go-runner's own data (old metrics and alerts, logs of deleted projects, free SQLite pages). Items whose tool is not installed are listed as unavailable. Nothing is removed; see POST /system/cleanup.
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=CleanupReport}
+// @Router       /system/cleanup [get]
+func (h *Handler) GetCleanup(c *gin.Context) {
+	c.JSON(http.StatusOK, types.DataResponse{Data: h.scanCleanup(c.Request.Context())})
+}
+
+// RunCleanup godoc
+// @Summary      Clean reclaimable space
+// @Description  Clean the selected items of GET /system/cleanup as a background job and return the job immediately. Progress is reported per item ("job_update" WebSocket messages, GET /jobs/{id}); the result lists the space freed by each item. Only one cleanup runs at a time.
+// @Tags         system
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CleanupRequest  true  "Items to clean"
+// @Success      202      {object}  types.DataResponse{data=go-runner_internal_jobs.Job}  "Cleanup job queued"
+// @Failure      400      {object}  middleware.ErrorResponse  "Unknown item"
+// @Failure      409      {object}  middleware.ErrorResponse  "A cleanup is already running"
+// @Router       /system/cleanup [post]
+func (h *Handler) RunCleanup(c *gin.Context) {
+	var req CleanupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	var selected []cleanupSource
+	for _, id := range req.Items {
+		source, ok := findCleanupSource(id)
+		if !ok {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Unknown cleanup item", id))
+			return
+		}
+		selected = append(selected, source)
+	}
+
+	job, err := h.jobs.Submit(jobs.Spec{
+		Type:    jobs.TypeCleanup,
+		Key:     jobs.TypeCleanup,
+		Message: "Cleaning " + strings.Join(req.Items, ", "),
+	}, func(ctx context.Context, run *jobs.Run) (interface{}, error) {
+		result := &CleanupRunResult{Items: []CleanupOutcome{}}
+		var failed []string
+		for i, source := range selected {
+			run.Progress(i*100/len(selected), "Cleaning "+source.title)
+			outcome := h.cleanItem(ctx, source, run)
+			if outcome.Error != "" {
+				run.Log(source.id + ": " + outcome.Error)
+				failed = append(failed, source.id)
+			} else {
+				run.Log(fmt.Sprintf("%s: freed %d bytes", source.id, outcome.FreedBytes))
+			}
+			result.Items = append(result.Items, outcome)
+			result.FreedBytes += outcome.FreedBytes
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+		}
+		if len(failed) > 0 {
+			return result, fmt.Errorf("failed to clean %s", strings.Join(failed, ", "))
+		}
+		return result, nil
+	})
+	if err != nil {
+		var conflict *jobs.ConflictError
+		if errors.As(err, &conflict) {
+			middleware.HandleError(c, middleware.NewError(http.StatusConflict, "A cleanup is already running", gin.H{"job_id": conflict.JobID}))
+			return
+		}
+		jobs.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, types.DataResponse{Data: job})
+}
+
+// findCleanupSource returns the source of an item ID
+func findCleanupSource(id string) (cleanupSource, bool) {
+	for _, source := range cleanupSources {
+		if source.id == id {
+			return source, true
+		}
+	}
+	return cleanupSource{}, false
+}
+
+// scanCleanup scans every cleanup source
+func (h *Handler) scanCleanup(ctx context.Context) *CleanupReport {
+	report := &CleanupReport{Items: []CleanupItem{}, ScannedAt: time.Now()}
+	for _, source := range cleanupSources {
+		item := h.scanItem(ctx, source)
+		if item.Available {
+			report.TotalBytes += item.Bytes
+		}
+		report.Items = append(report.Items, item)
+	}
+	return report
+}
+
+// scanItem measures one cleanup source
+func (h *Handler) scanItem(ctx context.Context, source cleanupSource) CleanupItem {
+	item := CleanupItem{
+		ID:          source.id,
+		Category:    source.category,
+		Title:       source.title,
+		Description: source.description,
+		Action:      source.action,
+		Available:   true,
+	}
+	if err := source.scan(ctx, h, &item); err != nil {
+		item.Available = false
+		item.Reason = err.Error()
+	}
+	return item
+}
+
+// cleanItem cleans one source, measuring the space freed
+func (h *Handler) cleanItem(ctx context.Context, source cleanupSource, run *jobs.Run) CleanupOutcome {
+	outcome := CleanupOutcome{ID: source.id}
+	before := h.scanItem(ctx, source)
+	if !before.Available {
+		outcome.Error = "unavailable: " + before.Reason
+		return outcome
+	}
+
+	if err := source.clean(ctx, h, &before, run); err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	// Directories are measured again, other items are freed as scanned
+	outcome.FreedBytes = before.Bytes
+	if before.Path != "" {
+		if after := h.scanItem(ctx, source); after.Available {
+			outcome.FreedBytes = max(before.Bytes-after.Bytes, 0)
+		}
+	}
+	return outcome
+}
+
+// runTool runs a command-line tool and returns its trimmed stdout
+func runTool(ctx context.Context, name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", fmt.Errorf("%s is not installed", name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, cleanupToolTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// scanToolDir sizes the cache directory a tool reports
+func scanToolDir(name string, args ...string) func(ctx context.Context, h *Handler, item *CleanupItem) error {
+	return func(ctx context.Context, h *Handler, item *CleanupItem) error {
+		dir, err := runTool(ctx, name, args...)
+		if err != nil {
+			return err
+		}
+		if lines := strings.Split(dir, "\n"); len(lines) > 0 {
+			dir = strings.TrimSpace(lines[len(lines)-1])
+		}
+		item.Path = dir
+		item.Bytes = dirSize(dir, time.Time{})
+		return nil
+	}
+}
+
+// cleanWithTool runs a cleanup command, logging its output to the job
+func cleanWithTool(name string, args ...string) func(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error {
+	return func(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error {
+		out, err := runTool(ctx, name, args...)
+		if out != "" {
+			run.Log(out)
+		}
+		return err
+	}
+}
+
+// dirSize sums the size of the regular files under dir, only files last
+// modified before olderThan unless it is zero
+func dirSize(dir string, olderThan time.Time) int64 {
+	var total int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && (olderThan.IsZero() || info.ModTime().Before(olderThan)) {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// goCacheEntryDir matches the two hex digit directories holding GOCACHE entries
+var goCacheEntryDir = regexp.MustCompile(`^[0-9a-f]{2}$`)
+
+// scanGoBuildCache sizes the Go build cache entries unused for goCacheMaxAge.
+// go refreshes the modification time of entries it uses.
+func scanGoBuildCache(ctx context.Context, h *Handler, item *CleanupItem) error {
+	dir, err := runTool(ctx, "go", "env", "GOCACHE")
+	if err != nil {
+		return err
+	}
+	if dir == "" || dir == "off" {
+		return fmt.Errorf("the Go build cache is disabled")
+	}
+	item.Path = dir
+
+	cutoff := time.Now().Add(-goCacheMaxAge)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && goCacheEntryDir.MatchString(entry.Name()) {
+			item.Bytes += dirSize(filepath.Join(dir, entry.Name()), cutoff)
+		}
+	}
+	return nil
+}
+
+// cleanGoBuildCache removes the Go build cache entries unused for goCacheMaxAge
+func cleanGoBuildCache(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error {
+	cutoff := time.Now().Add(-goCacheMaxAge)
+	entries, err := os.ReadDir(item.Path)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() || !goCacheEntryDir.MatchString(entry.Name()) {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(item.Path, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			info, err := file.Info()
+			if err != nil || !info.Mode().IsRegular() || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if os.Remove(filepath.Join(item.Path, entry.Name(), file.Name())) == nil {
+				removed++
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	run.Log(fmt.Sprintf("Removed %d Go build cache entries", removed))
+	return nil
+}
+
+// dockerSize matches sizes printed by docker ("1.2GB", "512kB", "0B", "12.5MiB")
+var dockerSize = regexp.MustCompile(`^([0-9.]+)\s*([kKMGT]?i?B)`)
+
+// parseDockerSize converts a docker size to bytes
+func parseDockerSize(size string) int64 {
+	m := dockerSize.FindStringSubmatch(strings.TrimSpace(size))
+	if m == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0
+	}
+	unit := map[string]float64{
+		"B": 1, "kB": 1e3, "KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12,
+		"KiB": 1 << 10, "MiB": 1 << 20, "GiB": 1 << 30, "TiB": 1 << 40,
+	}[m[2]]
+	return int64(value * unit)
+}
+
+// scanDockerDanglingImages sums the size of untagged images
+func scanDockerDanglingImages(ctx context.Context, h *Handler, item *CleanupItem) error {
+	out, err := runTool(ctx, "docker", "image", "ls", "--filter", "dangling=true", "--format", "{{.Size}}")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		item.Bytes += parseDockerSize(line)
+	}
+	return nil
+}
+
+// scanDockerBuildCache reads the reclaimable build cache from docker system df
+func scanDockerBuildCache(ctx context.Context, h *Handler, item *CleanupItem) error {
+	out, err := runTool(ctx, "docker", "system", "df", "--format", "{{.Type}}\t{{.Reclaimable}}")
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		kind, reclaimable, ok := strings.Cut(line, "\t")
+		if ok && kind == "Build Cache" {
+			item.Bytes = parseDockerSize(reclaimable)
+		}
+	}
+	return nil
+}
+
+// scanDeletedProjects sizes the stored logs of deleted projects
+func scanDeletedProjects(ctx context.Context, h *Handler, item *CleanupItem) error {
+	var stats struct {
+		RowCount int64
+		Bytes    int64
+	}
+	err := h.db.Table(tables.Name("projects")).Select("COUNT(*) AS row_count, COALESCE(SUM(LENGTH(logs)), 0) + COALESCE(SUM(LENGTH(log_times)), 0) AS bytes").
+		Where("deleted_at IS NOT NULL").Scan(&stats).Error
+	item.Rows, item.Bytes = stats.RowCount, stats.Bytes
+	return err
+}
+
+// cleanDeletedProjects purges deleted projects with the rows of every table
+// holding a project_id, registered by tables.SetProjectTables, so that none
+// is left orphaned. The spooled log archives of the projects are removed
+// once the rows are gone.
+func cleanDeletedProjects(ctx context.Context, h *Handler, item *CleanupItem, run *jobs.Run) error {
+	deleted := "SELECT id FROM " + tables.Name("projects") + " WHERE deleted_at IS NOT NULL"
+	var spoolFiles []string
+	err := h.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables.ProjectTables() {
+			if table == "log_archives" {
+				if err := tx.Table(tables.Name(table)).Where("project_id IN ("+deleted+") AND spool_file <> ''").
+					Pluck("spool_file", &spoolFiles).Error; err != nil {
+					return fmt.Errorf("list spooled archives: %w", err)
+				}
+			}
+			if err := tx.Exec("DELETE FROM " + tables.Name(table) + " WHERE project_id IN (" + deleted + ")").Error; err != nil {
+				return fmt.Errorf("purge %s: %w", table, err)
+			}
+		}
+		return tx.Exec("DELETE FROM " + tables.Name("projects") + " WHERE deleted_at IS NOT NULL").Error
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, file := range spoolFiles {
+		if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			run.Log(fmt.Sprintf("Failed to remove %s: %v", file, err))
+		}
+	}
+	return nil
+}
+
+// scanSQLiteFreePages computes the space of free pages of a SQLite database
+func scanSQLiteFreePages(ctx context.Context, h *Handler, item *CleanupItem) error {
+	if h.db.Dialector.Name() != "sqlite" {
+		return fmt.Errorf("only SQLite databases are vacuumed")
+	}
+	var pageSize, freePages int64
+	if err := h.db.Raw("PRAGMA page_size").Scan(&pageSize).Error; err != nil {
+		return err
+	}
+	if err := h.db.Raw("PRAGMA freelist_count").Scan(&freePages).Error; err != nil {
+		return err
+	}
+	item.Bytes = pageSize * freePages
+	return nil
+}
@@ -0,0 +1,124 @@
+package system
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-runner/internal/tables"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// projectTables are the tables with a project_id column registered by the
+// db package, which checks that its models give them all
+var projectTables = []string{
+	"project_ports", "dependency_audits", "test_runs", "smoke_test_runs",
+	"project_monitors", "monitor_checks", "project_profiles", "project_annotations",
+	"queue_metrics", "traffic_metrics", "process_metrics", "project_status_histories",
+	"jobs", "audit_events", "api_tokens", "ci_statuses", "log_archives",
+}
+
+// newCleanupHandler returns a handler whose database holds a live project 1
+// and a deleted project 2, each with a row in every project table, and the
+// directory of their spooled log archives
+func newCleanupHandler(t *testing.T) (*Handler, string) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	statements := []string{
+		`CREATE TABLE projects (id INTEGER PRIMARY KEY, logs TEXT, log_times TEXT, deleted_at DATETIME)`,
+		`INSERT INTO projects (id, logs, log_times, deleted_at) VALUES (1, 'live', '', NULL), (2, 'abcdef', '123', '2026-01-01')`,
+	}
+	for _, table := range projectTables {
+		if table == "log_archives" {
+			continue
+		}
+		statements = append(statements,
+			`CREATE TABLE `+table+` (id INTEGER PRIMARY KEY, project_id INTEGER)`,
+			`INSERT INTO `+table+` (project_id) VALUES (1), (2), (2)`)
+	}
+
+	spool := t.TempDir()
+	for _, name := range []string{"project-1.log", "project-2.log"} {
+		if err := os.WriteFile(filepath.Join(spool, name), []byte("output"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	statements = append(statements,
+		`CREATE TABLE log_archives (id INTEGER PRIMARY KEY, project_id INTEGER, spool_file TEXT)`,
+		`INSERT INTO log_archives (project_id, spool_file) VALUES
+			(1, '`+filepath.Join(spool, "project-1.log")+`'),
+			(2, '`+filepath.Join(spool, "project-2.log")+`'),
+			(2, '`+filepath.Join(spool, "already-removed.log")+`'),
+			(2, '')`)
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	previous := tables.ProjectTables()
+	tables.SetProjectTables(projectTables)
+	t.Cleanup(func() { tables.SetProjectTables(previous) })
+	return &Handler{db: db}, spool
+}
+
+func TestScanDeletedProjects(t *testing.T) {
+	h, _ := newCleanupHandler(t)
+
+	var item CleanupItem
+	if err := scanDeletedProjects(context.Background(), h, &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Rows != 1 || item.Bytes != 9 {
+		t.Errorf("scan = %d rows, %d bytes, want 1 row, 9 bytes", item.Rows, item.Bytes)
+	}
+}
+
+func TestCleanDeletedProjects(t *testing.T) {
+	h, spool := newCleanupHandler(t)
+
+	if err := cleanDeletedProjects(context.Background(), h, &CleanupItem{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var projects int64
+	h.db.Table("projects").Count(&projects)
+	if projects != 1 {
+		t.Errorf("projects left = %d, want the live one", projects)
+	}
+	for _, table := range projectTables {
+		var ids []uint
+		h.db.Table(table).Pluck("project_id", &ids)
+		if len(ids) == 0 {
+			t.Errorf("%s lost the rows of the live project", table)
+		}
+		for _, id := range ids {
+			if id != 1 {
+				t.Errorf("%s project_ids left = %v, want only 1", table, ids)
+				break
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(spool, "project-2.log")); !os.IsNotExist(err) {
+		t.Errorf("spooled archive of the purged project kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(spool, "project-1.log")); err != nil {
+		t.Errorf("spooled archive of the live project removed: %v", err)
+	}
+}
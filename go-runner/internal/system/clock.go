@@ -0,0 +1,133 @@
+package system
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"runtime"
+	"time"
+)
+
+// ntpServer is the reference the clock offset is measured against
+const ntpServer = "pool.ntp.org:123"
+
+// ntpTimeout bounds one NTP query
+const ntpTimeout = 2 * time.Second
+
+// clockCheckInterval is how long a clock measurement is reused
+const clockCheckInterval = 5 * time.Minute
+
+// ntpEpochOffset is the number of seconds from 1900 (NTP) to 1970 (Unix)
+const ntpEpochOffset = 2208988800
+
+// ClockInfo is the offset of the system clock from NTP
+type ClockInfo struct {
+	NTPServer    string    `json:"ntp_server"`
+	OffsetMs     float64   `json:"offset_ms"`     // Local clock minus NTP time, positive when ahead
+	RoundTripMs  float64   `json:"round_trip_ms"` // Network delay of the query
+	Synchronized *bool     `json:"synchronized"`  // The OS reports NTP synchronization (timedatectl), null when unknown
+	Timezone     string    `json:"timezone"`
+	CheckedAt    time.Time `json:"checked_at"`
+	Error        string    `json:"error,omitempty"` // Why the offset could not be measured
+}
+
+// getClockInfo measures the clock offset, reusing the last measurement for
+// clockCheckInterval
+func (d *Detector) getClockInfo(info *SystemInfo) {
+	d.clockMu.Lock()
+	defer d.clockMu.Unlock()
+
+	if d.clock == nil || time.Since(d.clock.CheckedAt) >= clockCheckInterval {
+		d.clock = measureClock()
+	}
+	clock := *d.clock
+	info.Clock = &clock
+}
+
+// measureClock queries the NTP server and the OS synchronization status
+func measureClock() *ClockInfo {
+	clock := &ClockInfo{NTPServer: ntpServer, CheckedAt: time.Now()}
+	clock.Timezone, _ = time.Now().Zone()
+
+	offset, rtt, err := queryNTP(ntpServer)
+	if err != nil {
+		clock.Error = err.Error()
+	} else {
+		clock.OffsetMs = float64(offset.Microseconds()) / 1000
+		clock.RoundTripMs = float64(rtt.Microseconds()) / 1000
+	}
+
+	if runtime.GOOS == "linux" {
+		ctx, cancel := context.WithTimeout(context.Background(), ntpTimeout)
+		defer cancel()
+		if out, err := runTool(ctx, "timedatectl", "show", "--property=NTPSynchronized", "--value"); err == nil {
+			synchronized := out == "yes"
+			clock.Synchronized = &synchronized
+		}
+	}
+	return clock
+}
+
+// queryNTP sends an SNTP request and returns the local clock offset (local
+// minus server time) and the round trip delay
+func queryNTP(server string) (time.Duration, time.Duration, error) {
+	conn, err := net.DialTimeout("udp", server, ntpTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ntpTimeout))
+
+	// LI 0, version 4, mode 3 (client); the transmit timestamp is echoed back
+	// as the origin timestamp
+	req := make([]byte, 48)
+	req[0] = 0x23
+	sent := time.Now()
+	putNTPTime(req[40:], sent)
+	if _, err := conn.Write(req); err != nil {
+		return 0, 0, err
+	}
+
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	received := time.Now()
+	if err != nil {
+		return 0, 0, err
+	}
+	if n < 48 {
+		return 0, 0, fmt.Errorf("short NTP response (%d bytes)", n)
+	}
+	if mode := resp[0] & 0x07; mode != 4 {
+		return 0, 0, fmt.Errorf("unexpected NTP mode %d", mode)
+	}
+	if stratum := resp[1]; stratum == 0 || stratum > 15 {
+		return 0, 0, fmt.Errorf("NTP server not synchronized (stratum %d)", stratum)
+	}
+	if binary.BigEndian.Uint64(resp[24:32]) != binary.BigEndian.Uint64(req[40:48]) {
+		return 0, 0, fmt.Errorf("NTP response does not match the request")
+	}
+
+	serverReceive := ntpTime(resp[32:40])
+	serverTransmit := ntpTime(resp[40:48])
+	// Server minus local time, as in RFC 5905
+	theta := (serverReceive.Sub(sent) + serverTransmit.Sub(received)) / 2
+	delay := received.Sub(sent) - serverTransmit.Sub(serverReceive)
+	return -theta, delay, nil
+}
+
+// putNTPTime writes t as a 64-bit NTP timestamp
+func putNTPTime(b []byte, t time.Time) {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / 1e9
+	binary.BigEndian.PutUint64(b, seconds<<32|fraction)
+}
+
+// ntpTime reads a 64-bit NTP timestamp
+func ntpTime(b []byte) time.Time {
+	value := binary.BigEndian.Uint64(b)
+	seconds := int64(value>>32) - ntpEpochOffset
+	nanos := int64(float64(value&math.MaxUint32) * 1e9 / (1 << 32))
+	return time.Unix(seconds, nanos)
+}
@@ -0,0 +1,489 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/events"
+	"go-runner/internal/maintenance"
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// connectivityTick is how often the prober looks for targets due a check
+const connectivityTick = 5 * time.Second
+
+// Connectivity probe types
+const (
+	ProbePing = "ping" // ICMP echo through the ping command
+	ProbeTCP  = "tcp"  // TCP connect to host:port
+	ProbeHTTP = "http" // GET of a URL, 5xx counts as a failure
+)
+
+// Connectivity statuses
+const (
+	ConnectivityUnknown     = "unknown"
+	ConnectivityReachable   = "reachable"
+	ConnectivityUnreachable = "unreachable"
+)
+
+// pingTime matches the round trip printed by ping ("time=12.3 ms")
+var pingTime = regexp.MustCompile(`time[=<]([0-9.]+)\s*ms`)
+
+// pingHost matches host names and addresses passed to ping
+var pingHost = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.:-]*$`)
+
+// ConnectivityTarget is a host probed periodically, such as the VPN gateway,
+// a package registry or a shared database
+type ConnectivityTarget struct {
+	ID               uint      `json:"id" gorm:"primarykey"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	Name             string    `json:"name" gorm:"not null"`
+	Type             string    `json:"type"`              // ping, tcp, http
+	Target           string    `json:"target"`            // Host, host:port or URL
+	IntervalSeconds  int       `json:"interval_seconds"`  // Between checks
+	TimeoutSeconds   int       `json:"timeout_seconds"`   // Of one check
+	FailureThreshold int       `json:"failure_threshold"` // Consecutive failures before unreachable
+	Enabled          bool      `json:"enabled"`
+
+	// Last result
+	Status    string     `json:"status" gorm:"default:unknown"` // unknown, reachable, unreachable
+	LatencyMs float64    `json:"latency_ms"`
+	LastError string     `json:"last_error,omitempty"`
+	Failures  int        `json:"failures"` // Consecutive failed checks
+	CheckedAt *time.Time `json:"checked_at"`
+	ChangedAt *time.Time `json:"changed_at"` // Last status change
+	AlertID   *uint      `json:"alert_id"`   // Active alert while unreachable
+}
+
+// TableName keeps targets apart from other tables
+func (ConnectivityTarget) TableName() string {
+	return tables.Name("connectivity_targets")
+}
+
+// ConnectivityTargetRequest creates or replaces a connectivity target
+type ConnectivityTargetRequest struct {
+	Name             string `json:"name" yaml:"name" binding:"required"`
+	Type             string `json:"type" yaml:"type" binding:"required,oneof=ping tcp http"`
+	Target           string `json:"target" yaml:"target" binding:"required"`                                      // Host for ping, host:port for tcp, URL for http
+	IntervalSeconds  int    `json:"interval_seconds" yaml:"interval_seconds" binding:"omitempty,min=5,max=86400"` // Default 30
+	TimeoutSeconds   int    `json:"timeout_seconds" yaml:"timeout_seconds" binding:"omitempty,min=1,max=60"`      // Default 5
+	FailureThreshold int    `json:"failure_threshold" yaml:"failure_threshold" binding:"omitempty,min=1,max=100"` // Default 2
+	Enabled          *bool  `json:"enabled" yaml:"enabled"`                                                       // Default true
+}
+
+// probeResult is the outcome of one check
+type probeResult struct {
+	ok        bool
+	latencyMs float64
+	err       string
+}
+
+// GetConnectivityTargets godoc
+// @Summary      List connectivity targets
+// @Description  List the hosts probed by the system service with their last result
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=[]ConnectivityTarget}
+// @Router       /system/connectivity [get]
+func (h *Handler) GetConnectivityTargets(c *gin.Context) {
+	targets := []ConnectivityTarget{}
+	if err := h.db.Order("id").Find(&targets).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch connectivity targets", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: targets})
+}
+
+// CreateConnectivityTarget godoc
+// @Summary      Add a connectivity target
+// @Description  Probe a host every interval_seconds: ping (ICMP through the ping command), tcp (connect to host:port) or http (GET, 5xx fails). After failure_threshold consecutive failures the target is unreachable and a "connectivity" alert is raised; it is resolved when the target answers again.
+// @Tags         system
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ConnectivityTargetRequest  true  "Target"
+// @Success      201      {object}  types.DataResponse{data=ConnectivityTarget}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid target"
+// @Router       /system/connectivity [post]
+func (h *Handler) CreateConnectivityTarget(c *gin.Context) {
+	var target ConnectivityTarget
+	if !bindConnectivityTarget(c, &target) {
+		return
+	}
+	target.Status = ConnectivityUnknown
+
+	if err := h.db.Create(&target).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to create connectivity target", err.Error()))
+		return
+	}
+	c.JSON(http.StatusCreated, types.DataResponse{Data: target})
+}
+
+// UpdateConnectivityTarget godoc
+// @Summary      Update a connectivity target
+// @Description  Replace the settings of a target; its status is kept unless the type or target changes
+// @Tags         system
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                        true  "Target ID"
+// @Param        request  body      ConnectivityTargetRequest  true  "Target"
+// @Success      200      {object}  types.DataResponse{data=ConnectivityTarget}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid target"
+// @Failure      404      {object}  middleware.ErrorResponse  "Target not found"
+// @Router       /system/connectivity/{id} [put]
+func (h *Handler) UpdateConnectivityTarget(c *gin.Context) {
+	target, ok := h.loadConnectivityTarget(c)
+	if !ok {
+		return
+	}
+	probe, host := target.Type, target.Target
+	if !bindConnectivityTarget(c, target) {
+		return
+	}
+	if target.Type != probe || target.Target != host {
+		resolveConnectivityAlert(h.db, h.events, target)
+		target.Status = ConnectivityUnknown
+		target.LatencyMs, target.LastError, target.Failures = 0, "", 0
+		target.CheckedAt, target.ChangedAt = nil, nil
+	}
+
+	if err := h.db.Save(target).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to update connectivity target", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: target})
+}
+
+// DeleteConnectivityTarget godoc
+// @Summary      Delete a connectivity target
+// @Description  Stop probing a target, resolving its alert
+// @Tags         system
+// @Produce      json
+// @Param        id   path      int  true  "Target ID"
+// @Success      200  {object}  types.MessageResponse     "Target deleted"
+// @Failure      404  {object}  middleware.ErrorResponse  "Target not found"
+// @Router       /system/connectivity/{id} [delete]
+func (h *Handler) DeleteConnectivityTarget(c *gin.Context) {
+	target, ok := h.loadConnectivityTarget(c)
+	if !ok {
+		return
+	}
+	resolveConnectivityAlert(h.db, h.events, target)
+	if err := h.db.Delete(target).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete connectivity target", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Connectivity target deleted"})
+}
+
+// CheckConnectivityTarget godoc
+// @Summary      Check a connectivity target now
+// @Description  Probe a target immediately and record the result as a scheduled check would
+// @Tags         system
+// @Produce      json
+// @Param        id   path      int  true  "Target ID"
+// @Success      200  {object}  types.DataResponse{data=ConnectivityTarget}
+// @Failure      404  {object}  middleware.ErrorResponse  "Target not found"
+// @Router       /system/connectivity/{id}/check [post]
+func (h *Handler) CheckConnectivityTarget(c *gin.Context) {
+	target, ok := h.loadConnectivityTarget(c)
+	if !ok {
+		return
+	}
+
+	enableAlerts := true
+	var config SystemConfig
+	if err := h.db.First(&config).Error; err == nil {
+		enableAlerts = config.EnableAlerts
+	}
+	result := probeConnectivity(c.Request.Context(), target)
+	recordConnectivity(h.db, h.events, target, result, enableAlerts)
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: target})
+}
+
+// bindConnectivityTarget validates a request into target, answering 400 on error
+func bindConnectivityTarget(c *gin.Context, target *ConnectivityTarget) bool {
+	var req ConnectivityTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return false
+	}
+	if err := req.apply(target); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid target", err.Error()))
+		return false
+	}
+	return true
+}
+
+// apply checks the target matches the probe type and sets the target's
+// settings, filling in the defaults
+func (req *ConnectivityTargetRequest) apply(target *ConnectivityTarget) error {
+	req.Target = strings.TrimSpace(req.Target)
+	if err := validateProbeTarget(req.Type, req.Target); err != nil {
+		return err
+	}
+
+	target.Name = req.Name
+	target.Type = req.Type
+	target.Target = req.Target
+	target.IntervalSeconds = req.IntervalSeconds
+	if target.IntervalSeconds == 0 {
+		target.IntervalSeconds = 30
+	}
+	target.TimeoutSeconds = req.TimeoutSeconds
+	if target.TimeoutSeconds == 0 {
+		target.TimeoutSeconds = 5
+	}
+	target.FailureThreshold = req.FailureThreshold
+	if target.FailureThreshold == 0 {
+		target.FailureThreshold = 2
+	}
+	target.Enabled = req.Enabled == nil || *req.Enabled
+	return nil
+}
+
+// validateProbeTarget checks the target matches the probe type
+func validateProbeTarget(probe, target string) error {
+	switch probe {
+	case ProbePing:
+		if !pingHost.MatchString(target) {
+			return fmt.Errorf("ping needs a host name or address, got %q", target)
+		}
+	case ProbeTCP:
+		host, port, err := net.SplitHostPort(target)
+		if err != nil || host == "" {
+			return fmt.Errorf("tcp needs host:port, got %q", target)
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("invalid port %q", port)
+		}
+	case ProbeHTTP:
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("http needs an http:// or https:// URL, got %q", target)
+		}
+	}
+	return nil
+}
+
+// loadConnectivityTarget loads the target of the path, answering 404 when missing
+func (h *Handler) loadConnectivityTarget(c *gin.Context) (*ConnectivityTarget, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.HandleError(c, middleware.ErrBadRequest)
+		return nil, false
+	}
+	var target ConnectivityTarget
+	if err := h.db.First(&target, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Connectivity target not found", nil))
+		} else {
+			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch connectivity target", err.Error()))
+		}
+		return nil, false
+	}
+	return &target, true
+}
+
+// resolveConnectivityAlert resolves the active alert of a target
+func resolveConnectivityAlert(db *gorm.DB, bus *events.Bus, target *ConnectivityTarget) {
+	if target.AlertID == nil {
+		return
+	}
+	now := time.Now()
+	db.Model(&SystemAlert{}).Where("id = ?", *target.AlertID).Updates(map[string]interface{}{
+		"is_active":   false,
+		"resolved_at": &now,
+		"updated_at":  now,
+	})
+	var alert SystemAlert
+	if err := db.First(&alert, *target.AlertID).Error; err == nil {
+		bus.Publish(0, "alert_resolved", alert)
+	}
+	target.AlertID = nil
+}
+
+// applyConnectivity adds the connectivity targets to a system status; an
+// unreachable target turns a healthy system into a warning
+func applyConnectivity(db *gorm.DB, status *SystemStatus) {
+	status.Connectivity = []ConnectivityTarget{}
+	db.Where("enabled = ?", true).Order("id").Find(&status.Connectivity)
+	if len(status.Connectivity) == 0 {
+		return
+	}
+
+	var down []string
+	for _, target := range status.Connectivity {
+		if target.Status == ConnectivityUnreachable {
+			down = append(down, target.Name)
+		}
+	}
+	status.NetworkStatus = "healthy"
+	if len(down) == 0 {
+		return
+	}
+	status.NetworkStatus = "warning"
+	if status.Status == "healthy" {
+		status.Status = "warning"
+		status.Message = "Unreachable: " + strings.Join(down, ", ")
+	}
+}
+
+// startConnectivityProber checks the enabled targets when they are due
+func (s *Service) startConnectivityProber() {
+	ticker := time.NewTicker(connectivityTick)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var targets []ConnectivityTarget
+		if err := s.db.Where("enabled = ?", true).Find(&targets).Error; err != nil {
+			log.Printf("Failed to load connectivity targets: %v", err)
+			continue
+		}
+
+		now := time.Now()
+		var wg sync.WaitGroup
+		for i := range targets {
+			target := &targets[i]
+			if target.CheckedAt != nil && now.Sub(*target.CheckedAt) < time.Duration(target.IntervalSeconds)*time.Second {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result := probeConnectivity(context.Background(), target)
+				recordConnectivity(s.db, s.events, target, result, s.config.EnableAlerts)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+// probeConnectivity runs one check of a target
+func probeConnectivity(ctx context.Context, target *ConnectivityTarget) probeResult {
+	timeout := time.Duration(target.TimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout+time.Second)
+	defer cancel()
+	start := time.Now()
+
+	switch target.Type {
+	case ProbePing:
+		flag := "-W" // Seconds on Linux
+		if runtime.GOOS == "darwin" {
+			flag = "-t"
+		}
+		out, err := runTool(ctx, "ping", "-c", "1", flag, strconv.Itoa(target.TimeoutSeconds), target.Target)
+		if err != nil {
+			return probeResult{err: err.Error()}
+		}
+		latency := float64(time.Since(start).Microseconds()) / 1000
+		if m := pingTime.FindStringSubmatch(out); m != nil {
+			latency, _ = strconv.ParseFloat(m[1], 64)
+		}
+		return probeResult{ok: true, latencyMs: latency}
+
+	case ProbeTCP:
+		dialer := net.Dialer{Timeout: timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", target.Target)
+		if err != nil {
+			return probeResult{err: err.Error()}
+		}
+		conn.Close()
+		return probeResult{ok: true, latencyMs: float64(time.Since(start).Microseconds()) / 1000}
+
+	case ProbeHTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.Target, nil)
+		if err != nil {
+			return probeResult{err: err.Error()}
+		}
+		resp, err := (&http.Client{Timeout: timeout}).Do(req)
+		if err != nil {
+			return probeResult{err: err.Error()}
+		}
+		resp.Body.Close()
+		latency := float64(time.Since(start).Microseconds()) / 1000
+		if resp.StatusCode >= 500 {
+			return probeResult{latencyMs: latency, err: resp.Status}
+		}
+		return probeResult{ok: true, latencyMs: latency}
+	}
+	return probeResult{err: fmt.Sprintf("unknown probe type %q", target.Type)}
+}
+
+// recordConnectivity stores a check result, raising an alert when the target
+// becomes unreachable and resolving it when the target answers again
+func recordConnectivity(db *gorm.DB, bus *events.Bus, target *ConnectivityTarget, result probeResult, enableAlerts bool) {
+	now := time.Now()
+	previous := target.Status
+
+	target.CheckedAt = &now
+	target.LatencyMs = result.latencyMs
+	target.LastError = result.err
+	if result.ok {
+		target.Failures = 0
+		target.Status = ConnectivityReachable
+	} else {
+		target.Failures++
+		if target.Failures >= target.FailureThreshold {
+			target.Status = ConnectivityUnreachable
+		}
+	}
+	if target.Status != previous {
+		target.ChangedAt = &now
+	}
+
+	if target.Status == ConnectivityReachable && previous == ConnectivityUnreachable {
+		resolveConnectivityAlert(db, bus, target)
+		log.Printf("Connectivity target %s is reachable again", target.Name)
+	}
+	if target.Status == ConnectivityUnreachable && previous != ConnectivityUnreachable {
+		log.Printf("Connectivity target %s is unreachable: %s", target.Name, result.err)
+		// Planned maintenance should not page anyone
+		if enableAlerts && maintenance.SuppressingAlerts(db, 0) == nil {
+			alert := SystemAlert{
+				Type:      "connectivity",
+				Level:     "error",
+				Message:   fmt.Sprintf("%s (%s %s) is unreachable: %s", target.Name, target.Type, target.Target, result.err),
+				Resource:  target.Name,
+				Value:     float64(target.Failures),
+				Threshold: float64(target.FailureThreshold),
+				IsActive:  true,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := db.Create(&alert).Error; err != nil {
+				log.Printf("Failed to create alert: %v", err)
+			} else {
+				target.AlertID = &alert.ID
+				bus.Publish(0, "alert_raised", alert)
+			}
+		}
+	}
+
+	// Only the result columns, settings may have changed meanwhile
+	db.Model(&ConnectivityTarget{}).Where("id = ?", target.ID).Updates(map[string]interface{}{
+		"status":     target.Status,
+		"latency_ms": target.LatencyMs,
+		"last_error": target.LastError,
+		"failures":   target.Failures,
+		"checked_at": target.CheckedAt,
+		"changed_at": target.ChangedAt,
+		"alert_id":   target.AlertID,
+	})
+}
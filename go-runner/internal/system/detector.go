@@ -3,6 +3,7 @@ package system
 import (
 	"fmt"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/cpu"
@@ -17,6 +18,8 @@ import (
 // Detector handles system information collection
 type Detector struct {
 	startTime time.Time
+	clockMu   sync.Mutex
+	clock     *ClockInfo // Last clock measurement
 }
 
 // NewDetector creates a new system detector
@@ -63,6 +66,12 @@ func (d *Detector) GetSystemInfo() (*SystemInfo, error) {
 		return nil, fmt.Errorf("failed to get process info: %v", err)
 	}
 
+	// Get clock offset, a failed NTP query is reported in the result
+	d.getClockInfo(info)
+
+	// Get open file counts
+	d.getFileDescriptorInfo(info)
+
 	return info, nil
 }
 
@@ -122,6 +131,18 @@ func (d *Detector) getCPUInfo(info *SystemInfo) error {
 	return nil
 }
 
+// CPUUsage samples the CPU usage of the host, in percent, over interval
+func (d *Detector) CPUUsage(interval time.Duration) (float64, error) {
+	percent, err := cpu.Percent(interval, false)
+	if err != nil {
+		return 0, err
+	}
+	if len(percent) == 0 {
+		return 0, fmt.Errorf("no CPU usage reported")
+	}
+	return percent[0], nil
+}
+
 // getMemoryInfo collects memory information
 func (d *Detector) getMemoryInfo(info *SystemInfo) error {
 	memInfo, err := mem.VirtualMemory()
@@ -0,0 +1,250 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dockerContainerRef matches container IDs and names; a leading dash would
+// be read as a flag
+var dockerContainerRef = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// DockerContainer is a container of the local Docker daemon
+type DockerContainer struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Image      string  `json:"image"`
+	State      string  `json:"state"`  // running, exited, paused...
+	Status     string  `json:"status"` // "Up 2 hours", "Exited (0) 3 days ago"
+	Ports      string  `json:"ports,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+	CPUPercent float64 `json:"cpu_percent"` // Running containers only
+	MemUsage   int64   `json:"mem_usage"`
+	MemLimit   int64   `json:"mem_limit"`
+	MemPercent float64 `json:"mem_percent"`
+}
+
+// DockerDiskUsage is a row of docker system df
+type DockerDiskUsage struct {
+	Type        string `json:"type"` // Images, Containers, Local Volumes, Build Cache
+	Total       int    `json:"total"`
+	Active      int    `json:"active"`
+	Size        int64  `json:"size"`
+	Reclaimable int64  `json:"reclaimable"`
+}
+
+// DockerOverview is the state of the local Docker daemon
+type DockerOverview struct {
+	Available  bool              `json:"available"`
+	Error      string            `json:"error,omitempty"` // Why the daemon cannot be read
+	Version    string            `json:"version,omitempty"`
+	Running    int               `json:"running"`
+	Containers []DockerContainer `json:"containers"` // Running first, then by name
+	Images     int               `json:"images"`
+	DiskUsage  []DockerDiskUsage `json:"disk_usage"`
+}
+
+// GetDocker godoc
+// @Summary      Get Docker daemon status
+// @Description  Report the containers of the local Docker daemon with the CPU and memory of running ones (docker stats), the image count and disk usage (docker system df). Containers are listed whether or not go-runner started them. When docker is not installed or the daemon is down, available is false with the reason in error.
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=DockerOverview}
+// @Router       /system/docker [get]
+func (h *Handler) GetDocker(c *gin.Context) {
+	c.JSON(http.StatusOK, types.DataResponse{Data: dockerOverview(c.Request.Context())})
+}
+
+// StopDockerContainer godoc
+// @Summary      Stop a Docker container
+// @Description  Stop a container of the local Docker daemon by ID or name (docker stop)
+// @Tags         system
+// @Produce      json
+// @Param        id   path      string  true  "Container ID or name"
+// @Success      200  {object}  types.DataMessageResponse
+// @Failure      400  {object}  middleware.ErrorResponse  "Invalid container ID"
+// @Failure      404  {object}  middleware.ErrorResponse  "Container not found"
+// @Failure      500  {object}  middleware.ErrorResponse  "Docker not available"
+// @Router       /system/docker/containers/{id}/stop [post]
+func (h *Handler) StopDockerContainer(c *gin.Context) {
+	h.dockerContainerAction(c, "stop", "Container stopped")
+}
+
+// RestartDockerContainer godoc
+// @Summary      Restart a Docker container
+// @Description  Restart a container of the local Docker daemon by ID or name (docker restart); stopped containers are started
+// @Tags         system
+// @Produce      json
+// @Param        id   path      string  true  "Container ID or name"
+// @Success      200  {object}  types.DataMessageResponse
+// @Failure      400  {object}  middleware.ErrorResponse  "Invalid container ID"
+// @Failure      404  {object}  middleware.ErrorResponse  "Container not found"
+// @Failure      500  {object}  middleware.ErrorResponse  "Docker not available"
+// @Router       /system/docker/containers/{id}/restart [post]
+func (h *Handler) RestartDockerContainer(c *gin.Context) {
+	h.dockerContainerAction(c, "restart", "Container restarted")
+}
+
+// dockerContainerAction runs docker stop or restart on the container of the path
+func (h *Handler) dockerContainerAction(c *gin.Context, action, message string) {
+	ref := c.Param("id")
+	if !dockerContainerRef.MatchString(ref) {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid container ID", ref))
+		return
+	}
+
+	if _, err := runTool(c.Request.Context(), "docker", action, ref); err != nil {
+		if strings.Contains(err.Error(), "No such container") {
+			middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Container not found", ref))
+			return
+		}
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to "+action+" container", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Data:    gin.H{"id": ref},
+		Message: message,
+	})
+}
+
+// dockerOverview reads containers, stats and disk usage from the docker CLI
+func dockerOverview(ctx context.Context) *DockerOverview {
+	overview := &DockerOverview{Containers: []DockerContainer{}, DiskUsage: []DockerDiskUsage{}}
+
+	version, err := runTool(ctx, "docker", "version", "--format", "{{.Server.Version}}")
+	if err != nil {
+		overview.Error = err.Error()
+		return overview
+	}
+	overview.Available = true
+	overview.Version = version
+
+	out, err := runTool(ctx, "docker", "ps", "--all", "--no-trunc", "--format", "{{json .}}")
+	if err != nil {
+		overview.Error = err.Error()
+		return overview
+	}
+	byID := make(map[string]int)
+	for _, line := range jsonLines(out) {
+		var row struct {
+			ID        string
+			Names     string
+			Image     string
+			State     string
+			Status    string
+			Ports     string
+			CreatedAt string
+		}
+		if json.Unmarshal([]byte(line), &row) != nil {
+			continue
+		}
+		byID[row.ID] = len(overview.Containers)
+		overview.Containers = append(overview.Containers, DockerContainer{
+			ID:        row.ID,
+			Name:      row.Names,
+			Image:     row.Image,
+			State:     row.State,
+			Status:    row.Status,
+			Ports:     row.Ports,
+			CreatedAt: row.CreatedAt,
+		})
+		if row.State == "running" {
+			overview.Running++
+		}
+	}
+
+	if overview.Running > 0 {
+		out, err := runTool(ctx, "docker", "stats", "--no-stream", "--no-trunc", "--format", "{{json .}}")
+		if err != nil {
+			overview.Error = err.Error()
+		}
+		for _, line := range jsonLines(out) {
+			var row struct {
+				ID       string
+				CPUPerc  string
+				MemUsage string
+				MemPerc  string
+			}
+			if json.Unmarshal([]byte(line), &row) != nil {
+				continue
+			}
+			i, ok := byID[row.ID]
+			if !ok {
+				continue
+			}
+			container := &overview.Containers[i]
+			container.CPUPercent = parsePercent(row.CPUPerc)
+			container.MemPercent = parsePercent(row.MemPerc)
+			if usage, limit, ok := strings.Cut(row.MemUsage, "/"); ok {
+				container.MemUsage = parseDockerSize(usage)
+				container.MemLimit = parseDockerSize(limit)
+			}
+		}
+	}
+
+	sort.SliceStable(overview.Containers, func(i, j int) bool {
+		a, b := overview.Containers[i], overview.Containers[j]
+		if (a.State == "running") != (b.State == "running") {
+			return a.State == "running"
+		}
+		return a.Name < b.Name
+	})
+
+	out, err = runTool(ctx, "docker", "system", "df", "--format", "{{json .}}")
+	if err != nil {
+		overview.Error = err.Error()
+		return overview
+	}
+	for _, line := range jsonLines(out) {
+		var row struct {
+			Type        string
+			TotalCount  string
+			Active      string
+			Size        string
+			Reclaimable string
+		}
+		if json.Unmarshal([]byte(line), &row) != nil {
+			continue
+		}
+		usage := DockerDiskUsage{
+			Type:        row.Type,
+			Size:        parseDockerSize(row.Size),
+			Reclaimable: parseDockerSize(row.Reclaimable),
+		}
+		usage.Total, _ = strconv.Atoi(row.TotalCount)
+		usage.Active, _ = strconv.Atoi(row.Active)
+		if usage.Type == "Images" {
+			overview.Images = usage.Total
+		}
+		overview.DiskUsage = append(overview.DiskUsage, usage)
+	}
+	return overview
+}
+
+// jsonLines splits the output of --format '{{json .}}', one object per line
+func jsonLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "{") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parsePercent converts "12.34%" to 12.34
+func parsePercent(value string) float64 {
+	f, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	return f
+}
@@ -0,0 +1,65 @@
+package system
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// FileDescriptorInfo is the open file count of the host and of go-runner
+type FileDescriptorInfo struct {
+	Open         uint64  `json:"open"`          // Allocated file handles system-wide
+	Max          uint64  `json:"max"`           // fs.file-max (kern.maxfiles on macOS)
+	Usage        float64 `json:"usage"`         // Percentage of max
+	ProcessOpen  int32   `json:"process_open"`  // Open by go-runner itself
+	ProcessLimit uint64  `json:"process_limit"` // Soft RLIMIT_NOFILE of go-runner
+}
+
+// getFileDescriptorInfo collects open file counts; counts the platform does
+// not expose stay 0
+func (d *Detector) getFileDescriptorInfo(info *SystemInfo) {
+	fds := &info.FileDescriptors
+
+	if runtime.GOOS == "darwin" {
+		fds.Open = sysctlUint("kern.num_files")
+		fds.Max = sysctlUint("kern.maxfiles")
+	} else if data, err := os.ReadFile("/proc/sys/fs/file-nr"); err == nil {
+		// allocated, allocated but unused, maximum
+		fields := strings.Fields(string(data))
+		if len(fields) == 3 {
+			allocated, _ := strconv.ParseUint(fields[0], 10, 64)
+			unused, _ := strconv.ParseUint(fields[1], 10, 64)
+			fds.Open = allocated - unused
+			fds.Max, _ = strconv.ParseUint(fields[2], 10, 64)
+		}
+	}
+	// Linux reports an effectively unlimited maximum on some systems
+	if fds.Max > 0 && fds.Max < 1<<62 {
+		fds.Usage = float64(fds.Open) / float64(fds.Max) * 100
+	}
+
+	if self, err := process.NewProcess(int32(os.Getpid())); err == nil {
+		fds.ProcessOpen, _ = self.NumFDs()
+		if limits, err := self.Rlimit(); err == nil {
+			for _, limit := range limits {
+				if limit.Resource == process.RLIMIT_NOFILE {
+					fds.ProcessLimit = limit.Soft
+				}
+			}
+		}
+	}
+}
+
+// sysctlUint reads a numeric sysctl value, 0 when unavailable
+func sysctlUint(name string) uint64 {
+	out, err := runTool(context.Background(), "sysctl", "-n", name)
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseUint(out, 10, 64)
+	return value
+}
@@ -0,0 +1,150 @@
+package system
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// forecastWindow is the history the trend of a metric is fitted on
+	forecastWindow = time.Hour
+	// minForecastSamples is how many metric samples a forecast needs
+	minForecastSamples = 10
+)
+
+// Trend is a straight line fitted on the samples of a metric
+type Trend struct {
+	Slope      float64 // Percentage points per hour
+	Current    float64 // Fitted value now
+	Confidence float64 // Coefficient of determination (R²) of the fit, 0 to 1
+}
+
+// HoursUntil returns when the trend reaches limit, false when it does not
+// grow
+func (t Trend) HoursUntil(limit float64) (float64, bool) {
+	if t.Slope <= 0 {
+		return 0, false
+	}
+	return max(limit-t.Current, 0) / t.Slope, true
+}
+
+// fitTrend fits a line on values by least squares, false with too few
+// samples or when they all have the same time
+func fitTrend(times []time.Time, values []float64, now time.Time) (Trend, bool) {
+	n := float64(len(values))
+	if len(values) < minForecastSamples {
+		return Trend{}, false
+	}
+
+	// Hours before now, so the intercept is the fitted value now
+	var sumX, sumY float64
+	xs := make([]float64, len(times))
+	for i, t := range times {
+		xs[i] = t.Sub(now).Hours()
+		sumX += xs[i]
+		sumY += values[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy, syy float64
+	for i, x := range xs {
+		dx, dy := x-meanX, values[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 || sameTimes(times) {
+		return Trend{}, false
+	}
+
+	trend := Trend{Slope: sxy / sxx}
+	trend.Current = meanY - trend.Slope*meanX
+	if syy > 0 {
+		trend.Confidence = sxy * sxy / (sxx * syy)
+	}
+	return trend, true
+}
+
+// sameTimes reports whether all times are equal, which sxx misses when the
+// rounded mean differs from them
+func sameTimes(times []time.Time) bool {
+	for _, t := range times[1:] {
+		if !t.Equal(times[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkForecastAlerts fits the disk and memory usage of the last hour and
+// raises an alert when the line reaches 100% within the forecast horizon,
+// hours before the fixed thresholds would fire
+func (s *Service) checkForecastAlerts() {
+	horizon := s.config.ForecastHorizon
+	if horizon <= 0 {
+		return
+	}
+
+	now := time.Now()
+	var samples []SystemMetrics
+	if err := s.db.Select("timestamp, memory_usage, disk_usage").
+		Where("timestamp >= ?", now.Add(-forecastWindow)).
+		Order("timestamp").Find(&samples).Error; err != nil || len(samples) < minForecastSamples {
+		return
+	}
+
+	times := make([]time.Time, len(samples))
+	disk := make([]float64, len(samples))
+	memory := make([]float64, len(samples))
+	for i, sample := range samples {
+		times[i] = sample.Timestamp
+		disk[i] = sample.DiskUsage
+		memory[i] = sample.MemoryUsage
+	}
+
+	if trend, ok := fitTrend(times, disk, now); ok {
+		s.checkForecast("disk_forecast", "Disk", trend, horizon)
+	}
+	if trend, ok := fitTrend(times, memory, now); ok {
+		s.checkForecast("memory_forecast", "Memory", trend, horizon)
+	}
+}
+
+// checkForecast raises a forecast alert when a trend is confident enough and
+// reaches 100% within horizon hours
+func (s *Service) checkForecast(alertType, resource string, trend Trend, horizon float64) {
+	if trend.Confidence < s.config.ForecastConfidence {
+		return
+	}
+	hours, ok := trend.HoursUntil(100)
+	if !ok || hours > horizon {
+		return
+	}
+
+	level := "warning"
+	if hours <= 1 {
+		level = "critical"
+	}
+
+	alert := SystemAlert{
+		Type:  alertType,
+		Level: level,
+		Message: fmt.Sprintf("%s will be full in ~%s at the current rate (%.1f%% now, +%.2f%%/h, R² %.2f; horizon: %gh)",
+			resource, formatHours(hours), trend.Current, trend.Slope, trend.Confidence, horizon),
+		Value:     hours,
+		Threshold: horizon,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	s.createAlert(&alert)
+}
+
+// formatHours formats a number of hours as 5h 40m, or 25m under an hour
+func formatHours(hours float64) string {
+	d := time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,108 @@
+package system
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFitTrend(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	// hourly returns the times of values sampled every hour up to now
+	hourly := func(values []float64) []time.Time {
+		times := make([]time.Time, len(values))
+		for i := range values {
+			times[i] = now.Add(-time.Duration(len(values)-1-i) * time.Hour)
+		}
+		return times
+	}
+	repeat := func(at time.Time, n int) []time.Time {
+		times := make([]time.Time, n)
+		for i := range times {
+			times[i] = at
+		}
+		return times
+	}
+	line := func(n int, current, slope float64) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = current - slope*float64(n-1-i)
+		}
+		return values
+	}
+
+	tests := []struct {
+		name   string
+		times  []time.Time
+		values []float64
+		ok     bool
+		want   Trend
+	}{
+		{"growing line", nil, line(12, 50, 10), true, Trend{Slope: 10, Current: 50, Confidence: 1}},
+		{"shrinking line", nil, line(10, 20, -4), true, Trend{Slope: -4, Current: 20, Confidence: 1}},
+		{"flat", nil, line(10, 30, 0), true, Trend{Slope: 0, Current: 30, Confidence: 0}},
+		// y = 2x + 60 ± 1, R² and the fit checked against a reference computation
+		{"noisy line", nil, []float64{41, 45, 45, 49, 49, 53, 53, 57, 57, 61}, true, Trend{Slope: 2.0606060606, Current: 60.2727272727, Confidence: 0.9730639731}},
+		{"too few samples", nil, line(minForecastSamples-1, 50, 10), false, Trend{}},
+		{"same time", repeat(now.Add(-time.Hour), 10), line(10, 50, 10), false, Trend{}},
+		// Ten times -0.1 hour do not sum to -1
+		{"same time with a rounded mean", repeat(now.Add(-6*time.Minute), 10), line(10, 50, 10), false, Trend{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times := tt.times
+			if times == nil {
+				times = hourly(tt.values)
+			}
+			got, ok := fitTrend(times, tt.values, now)
+			if ok != tt.ok {
+				t.Fatalf("fitTrend ok = %v, want %v", ok, tt.ok)
+			}
+			const epsilon = 1e-9
+			if math.Abs(got.Slope-tt.want.Slope) > epsilon || math.Abs(got.Current-tt.want.Current) > epsilon || math.Abs(got.Confidence-tt.want.Confidence) > epsilon {
+				t.Errorf("fitTrend = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoursUntil(t *testing.T) {
+	tests := []struct {
+		name  string
+		trend Trend
+		limit float64
+		hours float64
+		ok    bool
+	}{
+		{"growing", Trend{Slope: 10, Current: 50}, 100, 5, true},
+		{"slowly growing", Trend{Slope: 0.5, Current: 88}, 100, 24, true},
+		{"already past the limit", Trend{Slope: 2, Current: 101}, 100, 0, true},
+		{"flat", Trend{Slope: 0, Current: 99}, 100, 0, false},
+		{"shrinking", Trend{Slope: -3, Current: 90}, 100, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hours, ok := tt.trend.HoursUntil(tt.limit)
+			if hours != tt.hours || ok != tt.ok {
+				t.Errorf("HoursUntil(%v) = %v, %v, want %v, %v", tt.limit, hours, ok, tt.hours, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFormatHours(t *testing.T) {
+	tests := []struct {
+		hours float64
+		want  string
+	}{
+		{0.25, "15m"},
+		{0.999, "1h 0m"},
+		{5.67, "5h 40m"},
+		{30, "30h 0m"},
+	}
+	for _, tt := range tests {
+		if got := formatHours(tt.hours); got != tt.want {
+			t.Errorf("formatHours(%v) = %q, want %q", tt.hours, got, tt.want)
+		}
+	}
+}
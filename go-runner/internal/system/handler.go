@@ -5,7 +5,11 @@ import (
 	"strconv"
 	"time"
 
+	"go-runner/internal/events"
+	"go-runner/internal/jobs"
 	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -15,13 +19,17 @@ import (
 type Handler struct {
 	db       *gorm.DB
 	detector *Detector
+	jobs     *jobs.Manager
+	events   *events.Bus
 }
 
 // NewHandler creates a new system handler
-func NewHandler(db *gorm.DB) *Handler {
+func NewHandler(db *gorm.DB, jobManager *jobs.Manager, bus *events.Bus) *Handler {
 	return &Handler{
 		db:       db,
 		detector: NewDetector(),
+		jobs:     jobManager,
+		events:   bus,
 	}
 }
 
@@ -31,8 +39,8 @@ func NewHandler(db *gorm.DB) *Handler {
 // @Tags         system
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "System information"
-// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Success      200  {object}  types.DataResponse{data=SystemInfo}  "System information"
+// @Failure      500  {object}  middleware.ErrorResponse              "Internal server error"
 // @Router       /system/info [get]
 func (h *Handler) GetSystemInfo(c *gin.Context) {
 	info, err := h.detector.GetSystemInfo()
@@ -41,9 +49,7 @@ func (h *Handler) GetSystemInfo(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": info,
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: info})
 }
 
 // GetSystemStatus godoc
@@ -52,8 +58,8 @@ func (h *Handler) GetSystemInfo(c *gin.Context) {
 // @Tags         system
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "System status"
-// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Success      200  {object}  types.DataResponse{data=SystemStatus}  "System status"
+// @Failure      500  {object}  middleware.ErrorResponse                "Internal server error"
 // @Router       /system/status [get]
 func (h *Handler) GetSystemStatus(c *gin.Context) {
 	status, err := h.detector.GetSystemStatus()
@@ -61,10 +67,9 @@ func (h *Handler) GetSystemStatus(c *gin.Context) {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to get system status", err.Error()))
 		return
 	}
+	applyConnectivity(h.db, status)
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": status,
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: status})
 }
 
 // GetSystemMetrics godoc
@@ -76,9 +81,9 @@ func (h *Handler) GetSystemStatus(c *gin.Context) {
 // @Param        page     query     int  false  "Page number"
 // @Param        limit    query     int  false  "Items per page"
 // @Param        hours    query     int  false  "Hours of data to retrieve"
-// @Success      200      {object}  map[string]interface{}  "System metrics"
-// @Failure      400      {object}  map[string]interface{}  "Bad request"
-// @Failure      500      {object}  map[string]interface{}  "Internal server error"
+// @Success      200      {object}  types.PaginatedResponse{data=[]SystemMetrics}  "System metrics"
+// @Failure      400      {object}  middleware.ErrorResponse                       "Bad request"
+// @Failure      500      {object}  middleware.ErrorResponse                       "Internal server error"
 // @Router       /system/metrics [get]
 func (h *Handler) GetSystemMetrics(c *gin.Context) {
 	// Parse query parameters
@@ -118,14 +123,9 @@ func (h *Handler) GetSystemMetrics(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": metrics,
-		"pagination": gin.H{
-			"page":       page,
-			"limit":      limit,
-			"total":      total,
-			"total_pages": (total + int64(limit) - 1) / int64(limit),
-		},
+	c.JSON(http.StatusOK, types.PaginatedResponse{
+		Data:       metrics,
+		Pagination: types.NewPagination(page, limit, total),
 	})
 }
 
@@ -140,9 +140,9 @@ func (h *Handler) GetSystemMetrics(c *gin.Context) {
 // @Param        active   query     bool    false  "Active alerts only"
 // @Param        page     query     int     false  "Page number"
 // @Param        limit    query     int     false  "Items per page"
-// @Success      200      {object}  map[string]interface{}  "System alerts"
-// @Failure      400      {object}  map[string]interface{}  "Bad request"
-// @Failure      500      {object}  map[string]interface{}  "Internal server error"
+// @Success      200      {object}  types.PaginatedResponse{data=[]SystemAlert}  "System alerts"
+// @Failure      400      {object}  middleware.ErrorResponse                     "Bad request"
+// @Failure      500      {object}  middleware.ErrorResponse                     "Internal server error"
 // @Router       /system/alerts [get]
 func (h *Handler) GetSystemAlerts(c *gin.Context) {
 	// Parse query parameters
@@ -187,14 +187,9 @@ func (h *Handler) GetSystemAlerts(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": alerts,
-		"pagination": gin.H{
-			"page":       page,
-			"limit":      limit,
-			"total":      total,
-			"total_pages": (total + int64(limit) - 1) / int64(limit),
-		},
+	c.JSON(http.StatusOK, types.PaginatedResponse{
+		Data:       alerts,
+		Pagination: types.NewPagination(page, limit, total),
 	})
 }
 
@@ -204,32 +199,22 @@ func (h *Handler) GetSystemAlerts(c *gin.Context) {
 // @Tags         system
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "System configuration"
-// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Success      200  {object}  types.DataResponse{data=SystemConfig}  "System configuration"
+// @Failure      500  {object}  middleware.ErrorResponse                "Internal server error"
 // @Router       /system/config [get]
 func (h *Handler) GetSystemConfig(c *gin.Context) {
 	var config SystemConfig
 	if err := h.db.First(&config).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// Return default config if none exists
-			config = SystemConfig{
-				CPULimit:      80.0,
-				MemoryLimit:   80.0,
-				DiskLimit:     85.0,
-				NetworkLimit:  100.0,
-				CheckInterval: 60,
-				RetentionDays: 30,
-				EnableAlerts:  true,
-			}
+			config = defaultSystemConfig()
 		} else {
 			middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to get config", err.Error()))
 			return
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": config,
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: config})
 }
 
 // UpdateSystemConfig godoc
@@ -239,9 +224,9 @@ func (h *Handler) GetSystemConfig(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        config  body      SystemConfig  true  "System configuration"
-// @Success      200     {object}  map[string]interface{}  "Updated configuration"
-// @Failure      400     {object}  map[string]interface{}  "Bad request"
-// @Failure      500     {object}  map[string]interface{}  "Internal server error"
+// @Success      200     {object}  types.DataMessageResponse{data=SystemConfig}  "Updated configuration"
+// @Failure      400     {object}  middleware.ErrorResponse                       "Bad request"
+// @Failure      500     {object}  middleware.ErrorResponse                       "Internal server error"
 // @Router       /system/config [put]
 func (h *Handler) UpdateSystemConfig(c *gin.Context) {
 	var config SystemConfig
@@ -251,24 +236,8 @@ func (h *Handler) UpdateSystemConfig(c *gin.Context) {
 	}
 
 	// Validate configuration
-	if config.CPULimit < 0 || config.CPULimit > 100 {
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "CPU limit must be between 0 and 100", ""))
-		return
-	}
-	if config.MemoryLimit < 0 || config.MemoryLimit > 100 {
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Memory limit must be between 0 and 100", ""))
-		return
-	}
-	if config.DiskLimit < 0 || config.DiskLimit > 100 {
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Disk limit must be between 0 and 100", ""))
-		return
-	}
-	if config.CheckInterval < 10 {
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Check interval must be at least 10 seconds", ""))
-		return
-	}
-	if config.RetentionDays < 1 {
-		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Retention days must be at least 1", ""))
+	if err := config.validate(); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, err.Error(), ""))
 		return
 	}
 
@@ -278,10 +247,11 @@ func (h *Handler) UpdateSystemConfig(c *gin.Context) {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to update config", err.Error()))
 		return
 	}
+	h.events.Publish(0, "system_config_updated", config)
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": config,
-		"message": "Configuration updated successfully",
+	c.JSON(http.StatusOK, types.DataMessageResponse{
+		Message: "Configuration updated successfully",
+		Data:    config,
 	})
 }
 
@@ -291,8 +261,8 @@ func (h *Handler) UpdateSystemConfig(c *gin.Context) {
 // @Tags         system
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "System dashboard"
-// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Success      200  {object}  types.DataResponse{data=SystemDashboard}  "System dashboard"
+// @Failure      500  {object}  middleware.ErrorResponse                   "Internal server error"
 // @Router       /system/dashboard [get]
 func (h *Handler) GetSystemDashboard(c *gin.Context) {
 	// Get system info
@@ -308,6 +278,7 @@ func (h *Handler) GetSystemDashboard(c *gin.Context) {
 		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to get system status", err.Error()))
 		return
 	}
+	applyConnectivity(h.db, status)
 
 	// Get recent metrics (last 24 hours)
 	var recentMetrics []SystemMetrics
@@ -326,18 +297,27 @@ func (h *Handler) GetSystemDashboard(c *gin.Context) {
 		}
 	}
 
-	dashboard := gin.H{
-		"system_info": info,
-		"system_status": status,
-		"recent_metrics": recentMetrics,
-		"active_alerts": activeAlerts,
-		"top_processes": topProcesses,
-		"timestamp": time.Now(),
+	// Get proxied traffic per project (last hour)
+	traffic := []ProjectTraffic{}
+	h.db.Table(tables.Name("traffic_metrics")+" AS t").
+		Select("t.project_id, p.name, SUM(t.requests) AS requests, SUM(t.errors) AS errors, MAX(t.p95_ms) AS p95_ms").
+		Joins("JOIN "+tables.Name("projects")+" p ON p.id = t.project_id AND p.deleted_at IS NULL").
+		Where("t.timestamp >= ?", time.Now().Add(-time.Hour)).
+		Group("t.project_id, p.name").
+		Order("requests DESC").
+		Scan(&traffic)
+
+	dashboard := SystemDashboard{
+		SystemInfo:    info,
+		SystemStatus:  status,
+		RecentMetrics: recentMetrics,
+		ActiveAlerts:  activeAlerts,
+		TopProcesses:  topProcesses,
+		Traffic:       traffic,
+		Timestamp:     time.Now(),
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": dashboard,
-	})
+	c.JSON(http.StatusOK, types.DataResponse{Data: dashboard})
 }
 
 // ClearOldMetrics godoc
@@ -347,9 +327,9 @@ func (h *Handler) GetSystemDashboard(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        days  query     int  false  "Days to keep (default: 30)"
-// @Success      200   {object}  map[string]interface{}  "Cleanup result"
-// @Failure      400   {object}  map[string]interface{}  "Bad request"
-// @Failure      500   {object}  map[string]interface{}  "Internal server error"
+// @Success      200   {object}  CleanupResult             "Cleanup result"
+// @Failure      400   {object}  middleware.ErrorResponse  "Bad request"
+// @Failure      500   {object}  middleware.ErrorResponse  "Internal server error"
 // @Router       /system/metrics/cleanup [post]
 func (h *Handler) ClearOldMetrics(c *gin.Context) {
 	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
@@ -367,9 +347,9 @@ func (h *Handler) ClearOldMetrics(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Old metrics cleared successfully",
-		"deleted_count": result.RowsAffected,
-		"cutoff_time": cutoffTime,
+	c.JSON(http.StatusOK, CleanupResult{
+		Message:      "Old metrics cleared successfully",
+		DeletedCount: result.RowsAffected,
+		CutoffTime:   cutoffTime,
 	})
 }
@@ -1,6 +1,7 @@
 package system
 
 import (
+	"errors"
 	"time"
 )
 
@@ -10,12 +11,14 @@ type SystemInfo struct {
 	Platform    string      `json:"platform"`
 	Architecture string     `json:"architecture"`
 	GoVersion   string      `json:"go_version"`
-	Uptime      time.Duration `json:"uptime"`
+	Uptime      time.Duration `json:"uptime" swaggertype:"integer"`
 	CPU         CPUInfo     `json:"cpu"`
 	Memory      MemoryInfo  `json:"memory"`
 	Disk        DiskInfo    `json:"disk"`
 	Network     NetworkInfo `json:"network"`
 	Processes   []ProcessInfo `json:"processes"`
+	Clock       *ClockInfo  `json:"clock"`
+	FileDescriptors FileDescriptorInfo `json:"file_descriptors"`
 	Timestamp   time.Time   `json:"timestamp"`
 }
 
@@ -103,6 +106,7 @@ type SystemMetrics struct {
 	LoadAvg1  float64   `json:"load_avg_1"`
 	LoadAvg5  float64   `json:"load_avg_5"`
 	LoadAvg15 float64   `json:"load_avg_15"`
+	OpenFiles uint64    `json:"open_files"` // Open file handles system-wide
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -110,9 +114,10 @@ type SystemMetrics struct {
 // SystemAlert represents system alerts
 type SystemAlert struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
-	Type        string    `json:"type"`        // cpu, memory, disk, network
+	Type        string    `json:"type"`        // cpu, memory, disk, network, disk_forecast, memory_forecast...
 	Level       string    `json:"level"`       // info, warning, error, critical
 	Message     string    `json:"message"`
+	Resource    string    `json:"resource,omitempty"` // Project queue, process or connectivity target; empty for host-wide alerts
 	Value       float64   `json:"value"`
 	Threshold   float64   `json:"threshold"`
 	IsActive    bool      `json:"is_active"`
@@ -133,19 +138,97 @@ type SystemConfig struct {
 	EnableAlerts          bool    `json:"enable_alerts"`           // Enable alerting
 	AlertEmail            string  `json:"alert_email"`             // Alert email address
 	AlertWebhook          string  `json:"alert_webhook"`           // Alert webhook URL
+	ClockDriftLimit       float64 `json:"clock_drift_limit" gorm:"default:5"` // Clock offset threshold (seconds), 0 disables
+	FileDescriptorLimit   float64 `json:"file_descriptor_limit" gorm:"default:80"` // Open files threshold (% of the limit), system-wide and per project process
+	SwapLimit             float64 `json:"swap_limit" gorm:"default:50"` // Swap usage threshold (%), 0 disables
+	ForecastHorizon       float64 `json:"forecast_horizon" gorm:"default:6"` // Alert when disk or memory usage trends to 100% within this many hours, 0 disables
+	ForecastConfidence    float64 `json:"forecast_confidence" gorm:"default:0.8"` // Minimum R² of the trend for a forecast alert, 0 to 1
 	CreatedAt             time.Time `json:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at"`
 }
 
+// defaultSystemConfig is the configuration used until one is saved
+func defaultSystemConfig() SystemConfig {
+	return SystemConfig{
+		CPULimit:            80.0,
+		MemoryLimit:         80.0,
+		DiskLimit:           85.0,
+		NetworkLimit:        100.0,
+		CheckInterval:       60,
+		RetentionDays:       30,
+		EnableAlerts:        true,
+		ClockDriftLimit:     5.0,
+		FileDescriptorLimit: 80.0,
+		SwapLimit:           50.0,
+		ForecastHorizon:     6.0,
+		ForecastConfidence:  0.8,
+	}
+}
+
+// validate checks the thresholds and intervals of a configuration
+func (c *SystemConfig) validate() error {
+	switch {
+	case c.CPULimit < 0 || c.CPULimit > 100:
+		return errors.New("CPU limit must be between 0 and 100")
+	case c.MemoryLimit < 0 || c.MemoryLimit > 100:
+		return errors.New("Memory limit must be between 0 and 100")
+	case c.DiskLimit < 0 || c.DiskLimit > 100:
+		return errors.New("Disk limit must be between 0 and 100")
+	case c.CheckInterval < 10:
+		return errors.New("Check interval must be at least 10 seconds")
+	case c.RetentionDays < 1:
+		return errors.New("Retention days must be at least 1")
+	case c.FileDescriptorLimit < 0 || c.FileDescriptorLimit > 100:
+		return errors.New("File descriptor limit must be between 0 and 100")
+	case c.ClockDriftLimit < 0:
+		return errors.New("Clock drift limit must not be negative")
+	case c.SwapLimit < 0 || c.SwapLimit > 100:
+		return errors.New("Swap limit must be between 0 and 100")
+	case c.ForecastHorizon < 0 || c.ForecastHorizon > 720:
+		return errors.New("Forecast horizon must be between 0 and 720 hours")
+	case c.ForecastConfidence < 0 || c.ForecastConfidence > 1:
+		return errors.New("Forecast confidence must be between 0 and 1")
+	}
+	return nil
+}
+
 // SystemStatus represents current system status
 type SystemStatus struct {
 	Status      string    `json:"status"`      // healthy, warning, critical
 	Message     string    `json:"message"`
 	LastCheck   time.Time `json:"last_check"`
-	Uptime      time.Duration `json:"uptime"`
+	Uptime      time.Duration `json:"uptime" swaggertype:"integer"`
 	CPUStatus   string    `json:"cpu_status"`
 	MemoryStatus string   `json:"memory_status"`
 	DiskStatus  string    `json:"disk_status"`
-	NetworkStatus string  `json:"network_status"`
+	NetworkStatus string  `json:"network_status"` // From connectivity targets, empty without any
 	ActiveAlerts int      `json:"active_alerts"`
+	Connectivity []ConnectivityTarget `json:"connectivity"` // Enabled connectivity targets
+}
+
+// SystemDashboard represents the aggregated dashboard payload
+type SystemDashboard struct {
+	SystemInfo    *SystemInfo     `json:"system_info"`
+	SystemStatus  *SystemStatus   `json:"system_status"`
+	RecentMetrics []SystemMetrics `json:"recent_metrics"`
+	ActiveAlerts  []SystemAlert   `json:"active_alerts"`
+	TopProcesses  []ProcessInfo   `json:"top_processes"`
+	Traffic       []ProjectTraffic `json:"traffic"` // Proxied requests per project over the last hour
+	Timestamp     time.Time       `json:"timestamp"`
+}
+
+// ProjectTraffic summarizes the requests proxied to a project
+type ProjectTraffic struct {
+	ProjectID uint    `json:"project_id"`
+	Name      string  `json:"name"`
+	Requests  uint64  `json:"requests"`
+	Errors    uint64  `json:"errors"`
+	P95Ms     float64 `json:"p95_ms"` // Highest per-minute p95
+}
+
+// CleanupResult represents the result of a metrics cleanup
+type CleanupResult struct {
+	Message      string    `json:"message"`
+	DeletedCount int64     `json:"deleted_count"`
+	CutoffTime   time.Time `json:"cutoff_time"`
 }
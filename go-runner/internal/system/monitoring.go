@@ -0,0 +1,578 @@
+package system
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"go-runner/internal/maintenance"
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// monitoringConfigVersion is the version of the exported format
+const monitoringConfigVersion = 1
+
+// MonitoringConfig is the monitoring setup of a machine, exported as YAML to
+// keep in a repository and imported on other machines. Sections missing from
+// an imported file are left as they are.
+type MonitoringConfig struct {
+	Version              int                         `yaml:"version"`
+	System               *MonitoringSettings         `yaml:"system,omitempty"`
+	AlertRules           *AlertRules                 `yaml:"alert_rules,omitempty"`
+	NotificationChannels *NotificationChannels       `yaml:"notification_channels,omitempty"`
+	ConnectivityTargets  []ConnectivityTargetRequest `yaml:"connectivity_targets"`
+	MaintenanceWindows   []MaintenanceWindowConfig   `yaml:"maintenance_windows"`
+}
+
+// MonitoringSettings are the collection settings of the system config
+type MonitoringSettings struct {
+	CheckInterval *int  `yaml:"check_interval,omitempty"` // Seconds
+	RetentionDays *int  `yaml:"retention_days,omitempty"`
+	EnableAlerts  *bool `yaml:"enable_alerts,omitempty"`
+}
+
+// AlertRules are the thresholds alerts are raised at
+type AlertRules struct {
+	CPULimit            *float64 `yaml:"cpu_limit,omitempty"`             // %
+	MemoryLimit         *float64 `yaml:"memory_limit,omitempty"`          // %
+	DiskLimit           *float64 `yaml:"disk_limit,omitempty"`            // %
+	NetworkLimit        *float64 `yaml:"network_limit,omitempty"`         // Mbps
+	ClockDriftLimit     *float64 `yaml:"clock_drift_limit,omitempty"`     // Seconds
+	FileDescriptorLimit *float64 `yaml:"file_descriptor_limit,omitempty"` // % of the limit
+	SwapLimit           *float64 `yaml:"swap_limit,omitempty"`            // %
+	ForecastHorizon     *float64 `yaml:"forecast_horizon,omitempty"`      // Hours
+	ForecastConfidence  *float64 `yaml:"forecast_confidence,omitempty"`   // R², 0 to 1
+}
+
+// NotificationChannels are where alerts are sent besides the alert list
+type NotificationChannels struct {
+	Email   *string `yaml:"email,omitempty"`
+	Webhook *string `yaml:"webhook,omitempty"`
+}
+
+// MaintenanceWindowConfig is a maintenance window with its projects by name,
+// as project IDs differ between machines
+type MaintenanceWindowConfig struct {
+	Name             string     `yaml:"name"`
+	Reason           string     `yaml:"reason,omitempty"`
+	Projects         []string   `yaml:"projects,omitempty"` // The system and every project when empty
+	StartsAt         *time.Time `yaml:"starts_at,omitempty"`
+	EndsAt           *time.Time `yaml:"ends_at,omitempty"`
+	Cron             string     `yaml:"cron,omitempty"`
+	DurationMinutes  int        `yaml:"duration_minutes,omitempty"`
+	SuppressAlerts   *bool      `yaml:"suppress_alerts,omitempty"`    // Default true
+	PauseAutoRestart *bool      `yaml:"pause_auto_restart,omitempty"` // Default true
+}
+
+// ImportMonitoringRequest imports an exported monitoring configuration
+type ImportMonitoringRequest struct {
+	Config string `json:"config" binding:"required"` // YAML from GET /system/config/export
+	DryRun bool   `json:"dry_run"`                   // Report the changes without applying them
+	Prune  bool   `json:"prune"`                     // Delete connectivity targets and maintenance windows missing from the file
+}
+
+// ImportChanges lists connectivity targets or maintenance windows by name
+type ImportChanges struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`
+	Deleted   []string `json:"deleted"`
+}
+
+// ImportMonitoringResult reports what an import changed, or would change
+// for a dry run
+type ImportMonitoringResult struct {
+	DryRun              bool          `json:"dry_run"`
+	SystemConfig        bool          `json:"system_config"` // Whether the system config changed
+	ConnectivityTargets ImportChanges `json:"connectivity_targets"`
+	MaintenanceWindows  ImportChanges `json:"maintenance_windows"`
+	Warnings            []string      `json:"warnings,omitempty"`
+}
+
+func newImportChanges() ImportChanges {
+	return ImportChanges{Created: []string{}, Updated: []string{}, Unchanged: []string{}, Deleted: []string{}}
+}
+
+// ExportMonitoringConfig godoc
+// @Summary      Export the monitoring configuration
+// @Description  Export the system config (collection settings, alert thresholds and notification channels), connectivity targets and maintenance windows that are recurring or not over yet as YAML. Maintenance windows name their projects, so the file can be imported on machines where the project IDs differ. PagerDuty, Opsgenie and webhook integrations live in config.yaml and are not included.
+// @Tags         system
+// @Produce      json
+// @Success      200  {object}  types.DataResponse{data=string}  "YAML configuration"
+// @Failure      500  {object}  middleware.ErrorResponse         "Internal server error"
+// @Router       /system/config/export [get]
+func (h *Handler) ExportMonitoringConfig(c *gin.Context) {
+	config, err := h.loadSystemConfig()
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to get config", err.Error()))
+		return
+	}
+
+	export := MonitoringConfig{
+		Version: monitoringConfigVersion,
+		System: &MonitoringSettings{
+			CheckInterval: &config.CheckInterval,
+			RetentionDays: &config.RetentionDays,
+			EnableAlerts:  &config.EnableAlerts,
+		},
+		AlertRules: &AlertRules{
+			CPULimit:            &config.CPULimit,
+			MemoryLimit:         &config.MemoryLimit,
+			DiskLimit:           &config.DiskLimit,
+			NetworkLimit:        &config.NetworkLimit,
+			ClockDriftLimit:     &config.ClockDriftLimit,
+			FileDescriptorLimit: &config.FileDescriptorLimit,
+			SwapLimit:           &config.SwapLimit,
+			ForecastHorizon:     &config.ForecastHorizon,
+			ForecastConfidence:  &config.ForecastConfidence,
+		},
+		NotificationChannels: &NotificationChannels{
+			Email:   &config.AlertEmail,
+			Webhook: &config.AlertWebhook,
+		},
+		ConnectivityTargets: []ConnectivityTargetRequest{},
+		MaintenanceWindows:  []MaintenanceWindowConfig{},
+	}
+
+	var targets []ConnectivityTarget
+	if err := h.db.Order("id").Find(&targets).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch connectivity targets", err.Error()))
+		return
+	}
+	for _, target := range targets {
+		enabled := target.Enabled
+		export.ConnectivityTargets = append(export.ConnectivityTargets, ConnectivityTargetRequest{
+			Name:             target.Name,
+			Type:             target.Type,
+			Target:           target.Target,
+			IntervalSeconds:  target.IntervalSeconds,
+			TimeoutSeconds:   target.TimeoutSeconds,
+			FailureThreshold: target.FailureThreshold,
+			Enabled:          &enabled,
+		})
+	}
+
+	var windows []maintenance.Window
+	if err := h.db.Where("cron <> '' OR ends_at > ?", time.Now()).Order("id").Find(&windows).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch maintenance windows", err.Error()))
+		return
+	}
+	names, err := projectNames(h.db)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error()))
+		return
+	}
+	for _, window := range windows {
+		suppress, pause := window.SuppressAlerts, window.PauseAutoRestart
+		exported := MaintenanceWindowConfig{
+			Name:             window.Name,
+			Reason:           window.Reason,
+			StartsAt:         window.StartsAt,
+			EndsAt:           window.EndsAt,
+			Cron:             window.Cron,
+			DurationMinutes:  window.DurationMinutes,
+			SuppressAlerts:   &suppress,
+			PauseAutoRestart: &pause,
+		}
+		for _, id := range window.ProjectIDs {
+			if name, ok := names[id]; ok {
+				exported.Projects = append(exported.Projects, name)
+			}
+		}
+		// A window of deleted projects only would cover everything
+		if len(window.ProjectIDs) > 0 && len(exported.Projects) == 0 {
+			continue
+		}
+		export.MaintenanceWindows = append(export.MaintenanceWindows, exported)
+	}
+
+	var data strings.Builder
+	hostname, _ := os.Hostname()
+	fmt.Fprintf(&data, "# go-runner monitoring configuration, exported from %s on %s\n", hostname, time.Now().Format(time.RFC3339))
+	encoder := yaml.NewEncoder(&data)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(export); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to marshal YAML", err.Error()))
+		return
+	}
+	encoder.Close()
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: data.String()})
+}
+
+// ImportMonitoringConfig godoc
+// @Summary      Import a monitoring configuration
+// @Description  Apply a YAML file from GET /system/config/export. The system, alert_rules and notification_channels sections update the fields they set; connectivity targets and maintenance windows are created or updated by name, and with prune those missing from the file are deleted. Sections missing from the file are left alone. Nothing is changed when any part of the file is invalid, or with dry_run.
+// @Tags         system
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ImportMonitoringRequest  true  "Configuration"
+// @Success      200      {object}  types.DataResponse{data=ImportMonitoringResult}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid configuration"
+// @Failure      500      {object}  middleware.ErrorResponse  "Internal server error"
+// @Router       /system/config/import [post]
+func (h *Handler) ImportMonitoringConfig(c *gin.Context) {
+	var req ImportMonitoringRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+
+	var file MonitoringConfig
+	decoder := yaml.NewDecoder(strings.NewReader(req.Config))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid configuration", err.Error()))
+		return
+	}
+	if file.Version > monitoringConfigVersion {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Unsupported configuration version",
+			fmt.Sprintf("version %d, this go-runner reads up to %d", file.Version, monitoringConfigVersion)))
+		return
+	}
+
+	result := ImportMonitoringResult{
+		DryRun:              req.DryRun,
+		ConnectivityTargets: newImportChanges(),
+		MaintenanceWindows:  newImportChanges(),
+	}
+
+	// Check everything before changing anything
+	config, err := h.loadSystemConfig()
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to get config", err.Error()))
+		return
+	}
+	previous := *config
+	file.applyTo(config)
+	if err := config.validate(); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid system config", err.Error()))
+		return
+	}
+	result.SystemConfig = *config != previous
+
+	targets, err := h.planTargets(file.ConnectivityTargets, req.Prune, &result.ConnectivityTargets)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+	windows, err := h.planWindows(file.MaintenanceWindows, req.Prune, &result)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if req.DryRun {
+		c.JSON(http.StatusOK, types.DataResponse{Data: result})
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if result.SystemConfig {
+			config.UpdatedAt = time.Now()
+			if err := tx.Save(config).Error; err != nil {
+				return err
+			}
+		}
+		if err := targets.apply(tx, h); err != nil {
+			return err
+		}
+		return windows.apply(tx)
+	})
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to import configuration", err.Error()))
+		return
+	}
+	if result.SystemConfig {
+		h.events.Publish(0, "system_config_updated", *config)
+	}
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: result})
+}
+
+// loadSystemConfig returns the saved system config, or the defaults
+func (h *Handler) loadSystemConfig() (*SystemConfig, error) {
+	var config SystemConfig
+	if err := h.db.First(&config).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		config = defaultSystemConfig()
+	}
+	return &config, nil
+}
+
+// applyTo sets the system config fields of the file's sections
+func (m *MonitoringConfig) applyTo(config *SystemConfig) {
+	if s := m.System; s != nil {
+		setIf(&config.CheckInterval, s.CheckInterval)
+		setIf(&config.RetentionDays, s.RetentionDays)
+		setIf(&config.EnableAlerts, s.EnableAlerts)
+	}
+	if r := m.AlertRules; r != nil {
+		setIf(&config.CPULimit, r.CPULimit)
+		setIf(&config.MemoryLimit, r.MemoryLimit)
+		setIf(&config.DiskLimit, r.DiskLimit)
+		setIf(&config.NetworkLimit, r.NetworkLimit)
+		setIf(&config.ClockDriftLimit, r.ClockDriftLimit)
+		setIf(&config.FileDescriptorLimit, r.FileDescriptorLimit)
+		setIf(&config.SwapLimit, r.SwapLimit)
+		setIf(&config.ForecastHorizon, r.ForecastHorizon)
+		setIf(&config.ForecastConfidence, r.ForecastConfidence)
+	}
+	if n := m.NotificationChannels; n != nil {
+		setIf(&config.AlertEmail, n.Email)
+		setIf(&config.AlertWebhook, n.Webhook)
+	}
+}
+
+func setIf[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+// projectNames maps the IDs of existing projects to their names
+func projectNames(db *gorm.DB) (map[uint]string, error) {
+	var projects []struct {
+		ID   uint
+		Name string
+	}
+	if err := db.Table(tables.Name("projects")).Select("id, name").Where("deleted_at IS NULL").Scan(&projects).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+	return names, nil
+}
+
+// targetPlan holds the connectivity target changes of an import
+type targetPlan struct {
+	save   []*ConnectivityTarget
+	reset  []*ConnectivityTarget // Type or target changed, the status starts over
+	delete []*ConnectivityTarget
+}
+
+// planTargets matches the targets of the file (nil when the section is
+// missing) to the existing ones by name
+func (h *Handler) planTargets(requests []ConnectivityTargetRequest, prune bool, changes *ImportChanges) (*targetPlan, error) {
+	plan := &targetPlan{}
+	if requests == nil {
+		return plan, nil
+	}
+
+	var existing []ConnectivityTarget
+	if err := h.db.Order("id").Find(&existing).Error; err != nil {
+		return nil, middleware.NewError(http.StatusInternalServerError, "Failed to fetch connectivity targets", err.Error())
+	}
+	byName := make(map[string]*ConnectivityTarget, len(existing))
+	for i := range existing {
+		if _, ok := byName[existing[i].Name]; !ok {
+			byName[existing[i].Name] = &existing[i]
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range requests {
+		req := &requests[i]
+		if err := binding.Validator.ValidateStruct(req); err != nil {
+			return nil, middleware.NewError(http.StatusBadRequest, "Invalid connectivity target", fmt.Sprintf("%s: %v", req.Name, err))
+		}
+		if seen[req.Name] {
+			return nil, middleware.NewError(http.StatusBadRequest, "Duplicate connectivity target", req.Name)
+		}
+		seen[req.Name] = true
+
+		target, found := byName[req.Name]
+		if !found {
+			target = &ConnectivityTarget{Status: ConnectivityUnknown}
+		}
+		before := *target
+		if err := req.apply(target); err != nil {
+			return nil, middleware.NewError(http.StatusBadRequest, "Invalid connectivity target", fmt.Sprintf("%s: %v", req.Name, err))
+		}
+
+		switch {
+		case !found:
+			changes.Created = append(changes.Created, req.Name)
+		case before == *target:
+			changes.Unchanged = append(changes.Unchanged, req.Name)
+			continue
+		default:
+			changes.Updated = append(changes.Updated, req.Name)
+			if target.Type != before.Type || target.Target != before.Target {
+				plan.reset = append(plan.reset, target)
+			}
+		}
+		plan.save = append(plan.save, target)
+	}
+
+	if prune {
+		for i := range existing {
+			if !seen[existing[i].Name] {
+				changes.Deleted = append(changes.Deleted, existing[i].Name)
+				plan.delete = append(plan.delete, &existing[i])
+			}
+		}
+	}
+	return plan, nil
+}
+
+// apply saves the planned targets, resolving the alerts of those reset or
+// deleted like the connectivity endpoints
+func (p *targetPlan) apply(tx *gorm.DB, h *Handler) error {
+	for _, target := range p.reset {
+		resolveConnectivityAlert(tx, h.events, target)
+		target.Status = ConnectivityUnknown
+		target.LatencyMs, target.LastError, target.Failures = 0, "", 0
+		target.CheckedAt, target.ChangedAt = nil, nil
+	}
+	for _, target := range p.save {
+		if err := tx.Save(target).Error; err != nil {
+			return err
+		}
+	}
+	for _, target := range p.delete {
+		resolveConnectivityAlert(tx, h.events, target)
+		if err := tx.Delete(target).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// windowPlan holds the maintenance window changes of an import
+type windowPlan struct {
+	save   []*maintenance.Window
+	delete []*maintenance.Window
+}
+
+// planWindows matches the windows of the file (nil when the section is
+// missing) to the existing ones by name. Projects missing on this machine
+// are dropped from a window with a warning, and a window left without its
+// projects is skipped rather than covering everything.
+func (h *Handler) planWindows(configs []MaintenanceWindowConfig, prune bool, result *ImportMonitoringResult) (*windowPlan, error) {
+	plan := &windowPlan{}
+	if configs == nil {
+		return plan, nil
+	}
+	changes := &result.MaintenanceWindows
+
+	var existing []maintenance.Window
+	if err := h.db.Order("id").Find(&existing).Error; err != nil {
+		return nil, middleware.NewError(http.StatusInternalServerError, "Failed to fetch maintenance windows", err.Error())
+	}
+	byName := make(map[string]*maintenance.Window, len(existing))
+	for i := range existing {
+		if _, ok := byName[existing[i].Name]; !ok {
+			byName[existing[i].Name] = &existing[i]
+		}
+	}
+	names, err := projectNames(h.db)
+	if err != nil {
+		return nil, middleware.NewError(http.StatusInternalServerError, "Failed to fetch projects", err.Error())
+	}
+	// Project names need not be unique; the oldest project keeps the name
+	ids := make(map[string]uint, len(names))
+	for id, name := range names {
+		if known, ok := ids[name]; !ok || id < known {
+			ids[name] = id
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		if seen[config.Name] {
+			return nil, middleware.NewError(http.StatusBadRequest, "Duplicate maintenance window", config.Name)
+		}
+		seen[config.Name] = true
+
+		var projectIDs []uint
+		for _, name := range config.Projects {
+			if id, ok := ids[name]; ok {
+				projectIDs = append(projectIDs, id)
+			} else {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Maintenance window %q: project %q does not exist here", config.Name, name))
+			}
+		}
+		if len(config.Projects) > 0 && len(projectIDs) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Maintenance window %q skipped, none of its projects exist here", config.Name))
+			continue
+		}
+
+		window, found := byName[config.Name]
+		if !found {
+			window = &maintenance.Window{}
+		}
+		before := *window
+		window.Name = config.Name
+		window.Reason = config.Reason
+		window.ProjectIDs = projectIDs
+		window.StartsAt, window.EndsAt = config.StartsAt, config.EndsAt
+		window.Cron, window.DurationMinutes = config.Cron, config.DurationMinutes
+		window.SuppressAlerts = config.SuppressAlerts == nil || *config.SuppressAlerts
+		window.PauseAutoRestart = config.PauseAutoRestart == nil || *config.PauseAutoRestart
+		if err := window.Validate(); err != nil {
+			return nil, middleware.NewError(http.StatusBadRequest, "Invalid maintenance window", fmt.Sprintf("%s: %v", config.Name, err))
+		}
+
+		switch {
+		case !found:
+			changes.Created = append(changes.Created, config.Name)
+		case sameWindow(&before, window):
+			changes.Unchanged = append(changes.Unchanged, config.Name)
+			continue
+		default:
+			changes.Updated = append(changes.Updated, config.Name)
+		}
+		plan.save = append(plan.save, window)
+	}
+
+	if prune {
+		for i := range existing {
+			if !seen[existing[i].Name] {
+				changes.Deleted = append(changes.Deleted, existing[i].Name)
+				plan.delete = append(plan.delete, &existing[i])
+			}
+		}
+	}
+	return plan, nil
+}
+
+// sameWindow reports whether two windows have the same settings
+func sameWindow(a, b *maintenance.Window) bool {
+	sameTime := func(x, y *time.Time) bool {
+		return (x == nil && y == nil) || (x != nil && y != nil && x.Equal(*y))
+	}
+	return a.Name == b.Name && a.Reason == b.Reason && slices.Equal(a.ProjectIDs, b.ProjectIDs) &&
+		sameTime(a.StartsAt, b.StartsAt) && sameTime(a.EndsAt, b.EndsAt) &&
+		a.Cron == b.Cron && a.DurationMinutes == b.DurationMinutes &&
+		a.SuppressAlerts == b.SuppressAlerts && a.PauseAutoRestart == b.PauseAutoRestart
+}
+
+// apply saves the planned windows
+func (p *windowPlan) apply(tx *gorm.DB) error {
+	for _, window := range p.save {
+		if err := tx.Save(window).Error; err != nil {
+			return err
+		}
+	}
+	for _, window := range p.delete {
+		if err := tx.Delete(window).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
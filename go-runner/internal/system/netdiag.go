@@ -0,0 +1,232 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dnsLookupTimeout bounds the resolution of each host name
+const dnsLookupTimeout = 5 * time.Second
+
+// diagnosticHosts are resolved when no hosts are given: the registries
+// installs talk to
+var diagnosticHosts = []string{"registry.npmjs.org", "github.com", "proxy.golang.org", "pypi.org"}
+
+// proxyVariables are the proxy environment variables read by common tools
+var proxyVariables = []string{
+	"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy",
+	"ALL_PROXY", "all_proxy", "NO_PROXY", "no_proxy",
+}
+
+// DNSLookup is the resolution of a host name
+type DNSLookup struct {
+	Host       string   `json:"host"`
+	Addresses  []string `json:"addresses"`
+	DurationMs float64  `json:"duration_ms"`
+	Error      string   `json:"error,omitempty"`
+	Proxy      string   `json:"proxy,omitempty"` // Proxy HTTPS requests to the host go through
+}
+
+// DefaultGateway is the route of the default destination
+type DefaultGateway struct {
+	Gateway   string `json:"gateway"`
+	Interface string `json:"interface"`
+}
+
+// NetworkDiagnostics is the DNS, proxy and routing configuration of the host
+type NetworkDiagnostics struct {
+	DNSServers     []string          `json:"dns_servers"`     // nameserver lines of /etc/resolv.conf
+	SearchDomains  []string          `json:"search_domains"`  // search and domain lines
+	Lookups        []DNSLookup       `json:"lookups"`         // In the order requested
+	ProxyVariables map[string]string `json:"proxy_variables"` // Set variables, credentials masked
+	NpmConfig      map[string]string `json:"npm_config"`      // npm proxy and registry settings, when set
+	DefaultGateway *DefaultGateway   `json:"default_gateway"`
+	Errors         []string          `json:"errors,omitempty"` // Parts that could not be read
+	Timestamp      time.Time         `json:"timestamp"`
+}
+
+// GetNetworkDiagnostics godoc
+// @Summary      Get network diagnostics
+// @Description  Report the DNS servers and search domains of /etc/resolv.conf, the resolution time of host names (the npm, GitHub, Go and PyPI registries unless hosts is set) with the proxy each would use, the proxy environment variables and npm proxy and registry settings (credentials masked), and the default gateway. Meant for installs that hang on one machine.
+// @Tags         system
+// @Produce      json
+// @Param        hosts  query     string  false  "Comma-separated host names to resolve"
+// @Success      200    {object}  types.DataResponse{data=NetworkDiagnostics}
+// @Router       /system/network/diagnostics [get]
+func (h *Handler) GetNetworkDiagnostics(c *gin.Context) {
+	hosts := diagnosticHosts
+	if raw := c.Query("hosts"); raw != "" {
+		hosts = nil
+		for _, host := range strings.Split(raw, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+
+	diag := &NetworkDiagnostics{
+		DNSServers:     []string{},
+		SearchDomains:  []string{},
+		ProxyVariables: map[string]string{},
+		NpmConfig:      map[string]string{},
+		Timestamp:      time.Now(),
+	}
+	if err := readResolvConf("/etc/resolv.conf", diag); err != nil {
+		diag.Errors = append(diag.Errors, err.Error())
+	}
+
+	for _, name := range proxyVariables {
+		if value := os.Getenv(name); value != "" {
+			diag.ProxyVariables[name] = maskProxyURL(value)
+		}
+	}
+	for _, key := range []string{"proxy", "https-proxy", "noproxy", "registry"} {
+		value, err := runTool(c.Request.Context(), "npm", "config", "get", key)
+		if err != nil {
+			break // npm not installed
+		}
+		if value != "" && value != "null" && value != "undefined" {
+			diag.NpmConfig[key] = maskProxyURL(value)
+		}
+	}
+
+	diag.Lookups = resolveHosts(c.Request.Context(), hosts)
+
+	gateway, err := defaultGateway(c.Request.Context())
+	if err != nil {
+		diag.Errors = append(diag.Errors, err.Error())
+	}
+	diag.DefaultGateway = gateway
+
+	c.JSON(http.StatusOK, types.DataResponse{Data: diag})
+}
+
+// readResolvConf reads the name servers and search domains of a resolv.conf
+func readResolvConf(path string, diag *NetworkDiagnostics) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			diag.DNSServers = append(diag.DNSServers, fields[1])
+		case "search", "domain":
+			diag.SearchDomains = append(diag.SearchDomains, fields[1:]...)
+		}
+	}
+	return scanner.Err()
+}
+
+// resolveHosts looks up the host names concurrently, timing each
+func resolveHosts(ctx context.Context, hosts []string) []DNSLookup {
+	lookups := make([]DNSLookup, len(hosts))
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(lookup *DNSLookup, host string) {
+			defer wg.Done()
+			lookup.Host = host
+			lookup.Addresses = []string{}
+
+			ctx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+			defer cancel()
+			start := time.Now()
+			addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+			lookup.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+			if err != nil {
+				lookup.Error = err.Error()
+			} else {
+				lookup.Addresses = addrs
+			}
+
+			req := &http.Request{URL: &url.URL{Scheme: "https", Host: host}}
+			if proxy, err := http.ProxyFromEnvironment(req); err == nil && proxy != nil {
+				lookup.Proxy = maskProxyURL(proxy.String())
+			}
+		}(&lookups[i], host)
+	}
+	wg.Wait()
+	return lookups
+}
+
+// maskProxyURL hides the password of a proxy URL
+func maskProxyURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
+
+// defaultGateway reads the default route from /proc/net/route on Linux and
+// from route on macOS
+func defaultGateway(ctx context.Context) (*DefaultGateway, error) {
+	if runtime.GOOS == "darwin" {
+		out, err := runTool(ctx, "route", "-n", "get", "default")
+		if err != nil {
+			return nil, err
+		}
+		gateway := &DefaultGateway{}
+		for _, line := range strings.Split(out, "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "gateway":
+				gateway.Gateway = strings.TrimSpace(value)
+			case "interface":
+				gateway.Interface = strings.TrimSpace(value)
+			}
+		}
+		return gateway, nil
+	}
+
+	file, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Iface Destination Gateway ..., addresses in little-endian hex
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		raw, err := hex.DecodeString(fields[2])
+		if err != nil || len(raw) != 4 {
+			continue
+		}
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+		return &DefaultGateway{Gateway: ip.String(), Interface: fields[0]}, nil
+	}
+	return nil, scanner.Err()
+}
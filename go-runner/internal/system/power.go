@@ -0,0 +1,103 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// powerSupplyDir lists the power supplies on Linux
+const powerSupplyDir = "/sys/class/power_supply"
+
+// pmsetPercent matches the charge in the output of pmset -g batt
+var pmsetPercent = regexp.MustCompile(`(\d+)%`)
+
+// PowerSource is what the host is running on
+type PowerSource struct {
+	Battery   bool     `json:"battery"`           // A battery is present
+	OnBattery bool     `json:"on_battery"`        // Running on battery rather than AC
+	Percent   *float64 `json:"percent,omitempty"` // Battery charge
+}
+
+// ReadPowerSource reads whether the host runs on battery, from
+// /sys/class/power_supply on Linux and pmset on macOS
+func ReadPowerSource() (*PowerSource, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return readLinuxPowerSource()
+	case "darwin":
+		return readDarwinPowerSource()
+	}
+	return nil, fmt.Errorf("power source not supported on %s", runtime.GOOS)
+}
+
+// readLinuxPowerSource checks the online adapters and discharging batteries
+func readLinuxPowerSource() (*PowerSource, error) {
+	entries, err := os.ReadDir(powerSupplyDir)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &PowerSource{}
+	acOnline, discharging := false, false
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyDir, entry.Name())
+		switch readSysfs(dir, "type") {
+		case "Mains", "USB", "USB_C", "USB_PD":
+			if readSysfs(dir, "online") == "1" {
+				acOnline = true
+			}
+		case "Battery":
+			// Peripherals (mice, headsets) report batteries with scope Device
+			if readSysfs(dir, "scope") == "Device" {
+				continue
+			}
+			source.Battery = true
+			if readSysfs(dir, "status") == "Discharging" {
+				discharging = true
+			}
+			if capacity, err := strconv.ParseFloat(readSysfs(dir, "capacity"), 64); err == nil && source.Percent == nil {
+				source.Percent = &capacity
+			}
+		}
+	}
+	source.OnBattery = source.Battery && discharging && !acOnline
+	return source, nil
+}
+
+// readSysfs reads an attribute of a sysfs device
+func readSysfs(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readDarwinPowerSource parses pmset -g batt:
+//
+//	Now drawing from 'Battery Power'
+//	 -InternalBattery-0 (id=4653155)	85%; discharging; 5:12 remaining present: true
+func readDarwinPowerSource() (*PowerSource, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(out)
+	source := &PowerSource{
+		Battery:   strings.Contains(text, "InternalBattery"),
+		OnBattery: strings.Contains(text, "'Battery Power'"),
+	}
+	if match := pmsetPercent.FindStringSubmatch(text); match != nil {
+		if percent, err := strconv.ParseFloat(match[1], 64); err == nil {
+			source.Percent = &percent
+		}
+	}
+	return source, nil
+}
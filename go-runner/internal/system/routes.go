@@ -1,13 +1,16 @@
 package system
 
 import (
+	"go-runner/internal/events"
+	"go-runner/internal/jobs"
+
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 // RegisterRoutes registers system monitoring routes
-func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB) {
-	handler := NewHandler(db)
+func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB, jobManager *jobs.Manager, bus *events.Bus) {
+	handler := NewHandler(db, jobManager, bus)
 	
 	// System information routes
 	system := r.Group("/system")
@@ -20,12 +23,33 @@ func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB) {
 		// Metrics and monitoring
 		system.GET("/metrics", handler.GetSystemMetrics)
 		system.POST("/metrics/cleanup", handler.ClearOldMetrics)
+
+		// Reclaimable disk space
+		system.GET("/cleanup", handler.GetCleanup)
+		system.POST("/cleanup", handler.RunCleanup)
+
+		// Docker daemon
+		system.GET("/docker", handler.GetDocker)
+		system.POST("/docker/containers/:id/stop", handler.StopDockerContainer)
+		system.POST("/docker/containers/:id/restart", handler.RestartDockerContainer)
 		
+		// Network diagnostics
+		system.GET("/network/diagnostics", handler.GetNetworkDiagnostics)
+
+		// Connectivity probes
+		system.GET("/connectivity", handler.GetConnectivityTargets)
+		system.POST("/connectivity", handler.CreateConnectivityTarget)
+		system.PUT("/connectivity/:id", handler.UpdateConnectivityTarget)
+		system.DELETE("/connectivity/:id", handler.DeleteConnectivityTarget)
+		system.POST("/connectivity/:id/check", handler.CheckConnectivityTarget)
+
 		// Alerts
 		system.GET("/alerts", handler.GetSystemAlerts)
 		
 		// Configuration
 		system.GET("/config", handler.GetSystemConfig)
 		system.PUT("/config", handler.UpdateSystemConfig)
+		system.GET("/config/export", handler.ExportMonitoringConfig)
+		system.POST("/config/import", handler.ImportMonitoringConfig)
 	}
 }
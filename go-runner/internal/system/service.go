@@ -3,9 +3,14 @@ package system
 import (
 	"fmt"
 	"log"
+	"math"
 	"runtime"
+	"sync"
 	"time"
 
+	"go-runner/internal/events"
+	"go-runner/internal/maintenance"
+
 	"gorm.io/gorm"
 )
 
@@ -14,6 +19,11 @@ type Service struct {
 	db       *gorm.DB
 	detector *Detector
 	config   *SystemConfig
+
+	scaleMu       sync.RWMutex
+	intervalScale func() int // Multiplies the check interval, e.g. in low-power mode
+
+	events *events.Bus // Alerts are published as events once set
 }
 
 // NewService creates a new system service
@@ -29,6 +39,7 @@ func NewService(db *gorm.DB) *Service {
 	// Start background tasks
 	go service.startMetricsCollector()
 	go service.startAlertChecker()
+	go service.startConnectivityProber()
 
 	return service
 }
@@ -39,30 +50,12 @@ func (s *Service) loadConfig() {
 	if err := s.db.First(&config).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			// Create default configuration
-			config = SystemConfig{
-				CPULimit:      80.0,
-				MemoryLimit:   80.0,
-				DiskLimit:     85.0,
-				NetworkLimit:  100.0,
-				CheckInterval: 60,
-				RetentionDays: 30,
-				EnableAlerts:  true,
-				CreatedAt:     time.Now(),
-				UpdatedAt:     time.Now(),
-			}
+			config = defaultSystemConfig()
 			s.db.Create(&config)
 		} else {
 			log.Printf("Failed to load system config: %v", err)
 			// Use default config
-			config = SystemConfig{
-				CPULimit:      80.0,
-				MemoryLimit:   80.0,
-				DiskLimit:     85.0,
-				NetworkLimit:  100.0,
-				CheckInterval: 60,
-				RetentionDays: 30,
-				EnableAlerts:  true,
-			}
+			config = defaultSystemConfig()
 		}
 	}
 	s.config = &config
@@ -70,10 +63,8 @@ func (s *Service) loadConfig() {
 
 // startMetricsCollector starts the metrics collection background task
 func (s *Service) startMetricsCollector() {
-	ticker := time.NewTicker(time.Duration(s.config.CheckInterval) * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
+	for {
+		time.Sleep(s.checkInterval())
 		s.collectMetrics()
 	}
 }
@@ -95,6 +86,7 @@ func (s *Service) collectMetrics() {
 		LoadAvg1:   info.CPU.LoadAverage[0],
 		LoadAvg5:   info.CPU.LoadAverage[1],
 		LoadAvg15:  info.CPU.LoadAverage[2],
+		OpenFiles:  info.FileDescriptors.Open,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -127,14 +119,39 @@ func (s *Service) startAlertChecker() {
 		return
 	}
 
-	ticker := time.NewTicker(time.Duration(s.config.CheckInterval) * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
+	for {
+		time.Sleep(s.checkInterval())
 		s.checkAlerts()
 	}
 }
 
+// SetIntervalScale sets a factor the check interval is multiplied by, read
+// before every check
+func (s *Service) SetIntervalScale(scale func() int) {
+	s.scaleMu.Lock()
+	defer s.scaleMu.Unlock()
+	s.intervalScale = scale
+}
+
+// SetEvents sets the bus alerts are published to
+func (s *Service) SetEvents(bus *events.Bus) {
+	s.events = bus
+}
+
+// checkInterval returns the time until the next metrics collection or alert check
+func (s *Service) checkInterval() time.Duration {
+	interval := time.Duration(s.config.CheckInterval) * time.Second
+	s.scaleMu.RLock()
+	scale := s.intervalScale
+	s.scaleMu.RUnlock()
+	if scale != nil {
+		if factor := scale(); factor > 1 {
+			interval *= time.Duration(factor)
+		}
+	}
+	return interval
+}
+
 // checkAlerts checks for system alerts
 func (s *Service) checkAlerts() {
 	info, err := s.detector.GetSystemInfo()
@@ -149,13 +166,27 @@ func (s *Service) checkAlerts() {
 	// Check memory alert
 	s.checkMemoryAlert(info.Memory.Usage)
 
+	// Check swap alert
+	s.checkSwapAlert(info.Memory)
+
 	// Check disk alert
 	s.checkDiskAlert(info.Disk.Usage)
 
+	// Check disk and memory trends
+	s.checkForecastAlerts()
+
 	// Check load average alert
 	if len(info.CPU.LoadAverage) > 0 {
 		s.checkLoadAlert(info.CPU.LoadAverage[0])
 	}
+
+	// Check open files alert
+	s.checkFileDescriptorAlert(info.FileDescriptors)
+
+	// Check clock drift alert
+	if info.Clock != nil && info.Clock.Error == "" {
+		s.checkClockAlert(info.Clock.OffsetMs / 1000)
+	}
 }
 
 // checkCPUAlert checks for CPU usage alerts
@@ -204,6 +235,33 @@ func (s *Service) checkMemoryAlert(usage float64) {
 	}
 }
 
+// checkSwapAlert checks for swap usage alerts, as a machine swapping is
+// usually a service leaking memory
+func (s *Service) checkSwapAlert(memory MemoryInfo) {
+	limit := s.config.SwapLimit
+	if limit <= 0 || memory.SwapTotal == 0 || memory.SwapUsage < limit {
+		return
+	}
+
+	level := "warning"
+	if memory.SwapUsage >= 90 {
+		level = "critical"
+	}
+
+	alert := SystemAlert{
+		Type:      "swap",
+		Level:     level,
+		Message:   fmt.Sprintf("Swap usage is %.2f%%, %d of %d MiB (threshold: %.2f%%)", memory.SwapUsage, memory.SwapUsed>>20, memory.SwapTotal>>20, limit),
+		Value:     memory.SwapUsage,
+		Threshold: limit,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	s.createAlert(&alert)
+}
+
 // checkDiskAlert checks for disk usage alerts
 func (s *Service) checkDiskAlert(usage float64) {
 	if usage >= s.config.DiskLimit {
@@ -254,8 +312,81 @@ func (s *Service) checkLoadAlert(load1 float64) {
 	}
 }
 
+// checkFileDescriptorAlert checks for open file alerts, system-wide and for
+// go-runner itself
+func (s *Service) checkFileDescriptorAlert(fds FileDescriptorInfo) {
+	limit := s.config.FileDescriptorLimit
+	if limit <= 0 {
+		return
+	}
+
+	usage, message := fds.Usage, fmt.Sprintf("%d of %d file handles open system-wide", fds.Open, fds.Max)
+	if fds.ProcessLimit > 0 {
+		if own := float64(fds.ProcessOpen) / float64(fds.ProcessLimit) * 100; own > usage {
+			usage, message = own, fmt.Sprintf("go-runner has %d of %d files open (ulimit -n)", fds.ProcessOpen, fds.ProcessLimit)
+		}
+	}
+	if usage < limit {
+		return
+	}
+
+	level := "warning"
+	if usage >= 95 {
+		level = "critical"
+	}
+
+	alert := SystemAlert{
+		Type:      "file_descriptors",
+		Level:     level,
+		Message:   fmt.Sprintf("%s, %.1f%% (threshold: %.1f%%)", message, usage, limit),
+		Value:     usage,
+		Threshold: limit,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	s.createAlert(&alert)
+}
+
+// checkClockAlert checks for clock drift alerts, as JWT and TLS validation
+// fail when the clock is off
+func (s *Service) checkClockAlert(offset float64) {
+	drift := math.Abs(offset)
+	if s.config.ClockDriftLimit <= 0 || drift < s.config.ClockDriftLimit {
+		return
+	}
+
+	level := "warning"
+	if drift >= 60 {
+		level = "critical"
+	}
+	direction := "ahead of"
+	if offset < 0 {
+		direction = "behind"
+	}
+
+	alert := SystemAlert{
+		Type:      "clock",
+		Level:     level,
+		Message:   fmt.Sprintf("System clock is %.1fs %s NTP (threshold: %.1fs)", drift, direction, s.config.ClockDriftLimit),
+		Value:     drift,
+		Threshold: s.config.ClockDriftLimit,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	s.createAlert(&alert)
+}
+
 // createAlert creates a new alert if it doesn't already exist
 func (s *Service) createAlert(alert *SystemAlert) {
+	// Planned load tests and upgrades should not page anyone
+	if window := maintenance.SuppressingAlerts(s.db, 0); window != nil {
+		return
+	}
+
 	// Check if similar alert already exists
 	var existingAlert SystemAlert
 	err := s.db.Where("type = ? AND level = ? AND is_active = ? AND created_at > ?", 
@@ -267,7 +398,7 @@ func (s *Service) createAlert(alert *SystemAlert) {
 			log.Printf("Failed to create alert: %v", err)
 		} else {
 			log.Printf("Created %s alert: %s", alert.Level, alert.Message)
-			// TODO: Send notification (email, webhook, etc.)
+			s.events.Publish(0, "alert_raised", *alert)
 		}
 	}
 }
@@ -337,14 +468,21 @@ func (s *Service) GetAlerts(alertType, level string, activeOnly bool, page, limi
 	return alerts, total, nil
 }
 
-// ResolveAlert resolves an alert
+// ResolveAlert resolves an alert, publishing it as an "alert_resolved" event
 func (s *Service) ResolveAlert(alertID uint) error {
 	now := time.Now()
-	return s.db.Model(&SystemAlert{}).Where("id = ?", alertID).Updates(map[string]interface{}{
+	if err := s.db.Model(&SystemAlert{}).Where("id = ?", alertID).Updates(map[string]interface{}{
 		"is_active":   false,
 		"resolved_at": &now,
 		"updated_at":  now,
-	}).Error
+	}).Error; err != nil {
+		return err
+	}
+	var alert SystemAlert
+	if err := s.db.First(&alert, alertID).Error; err == nil {
+		s.events.Publish(0, "alert_resolved", alert)
+	}
+	return nil
 }
 
 // ClearOldMetrics clears metrics older than specified days
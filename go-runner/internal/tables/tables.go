@@ -0,0 +1,68 @@
+// Package tables names the database tables for queries that do not go
+// through a model, with the table prefix of the configuration, so that
+// several go-runner instances can share a database.
+package tables
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// prefix is prepended to every table, empty by default
+var prefix string
+
+var validPrefix = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Validate checks that a table prefix can be used in SQL as is
+func Validate(p string) error {
+	if p != "" && !validPrefix.MatchString(p) {
+		return fmt.Errorf("invalid table prefix %q: start with a lowercase letter, then letters, digits and underscores", p)
+	}
+	return nil
+}
+
+// SetPrefix sets the table prefix, before the database is opened
+func SetPrefix(p string) error {
+	if err := Validate(p); err != nil {
+		return err
+	}
+	prefix = p
+	return nil
+}
+
+// Prefix returns the table prefix
+func Prefix() string {
+	return prefix
+}
+
+// Name returns the name of a table with the prefix, e.g. for
+// db.Table(tables.Name("projects"))
+func Name(table string) string {
+	return prefix + table
+}
+
+// From returns a table with the prefix aliased to its bare name, for queries
+// whose columns are qualified with the table name (projects.name) or which
+// join other tables
+func From(table string) string {
+	if prefix == "" {
+		return table
+	}
+	return prefix + table + " " + table
+}
+
+// projectTables hold rows of a project by project_id, registered once the
+// schema is migrated
+var projectTables []string
+
+// SetProjectTables registers the tables with a project_id column, without
+// the prefix
+func SetProjectTables(names []string) {
+	projectTables = names
+}
+
+// ProjectTables returns the tables with a project_id column, e.g. to purge
+// the rows of deleted projects
+func ProjectTables() []string {
+	return projectTables
+}
@@ -0,0 +1,149 @@
+package tokens
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-runner/internal/middleware"
+	"go-runner/internal/tables"
+	"go-runner/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler serves the project token API
+type Handler struct {
+	db *gorm.DB
+}
+
+// RegisterRoutes registers the project token routes
+func RegisterRoutes(r *gin.RouterGroup, db *gorm.DB) {
+	h := &Handler{db: db}
+
+	tokens := r.Group("/tokens")
+	{
+		tokens.GET("", h.GetTokens)
+		tokens.POST("", h.CreateToken)
+		tokens.DELETE("/:id", h.DeleteToken)
+	}
+}
+
+// CreateTokenRequest describes a project token
+type CreateTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	ProjectID     uint     `json:"project_id" binding:"required"`
+	Scopes        []string `json:"scopes"`                                               // status, logs, start, stop, restart; status, logs and restart when empty
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"` // Never expires when empty
+}
+
+// CreatedToken is a new token, with the only copy of its secret
+type CreatedToken struct {
+	APIToken
+	Token string `json:"token"` // Send as Authorization: Bearer <token>; not shown again
+}
+
+// GetTokens godoc
+// @Summary      List project tokens
+// @Description  List the project API tokens, newest first, with their scopes and when and from where each was last used. The tokens themselves are not stored and cannot be shown again.
+// @Tags         tokens
+// @Produce      json
+// @Param        project_id  query     int  false  "Only the tokens of this project"
+// @Success      200         {object}  types.DataResponse{data=[]APIToken}
+// @Failure      400         {object}  middleware.ErrorResponse  "Bad request"
+// @Router       /tokens [get]
+func (h *Handler) GetTokens(c *gin.Context) {
+	query := h.db.Order("created_at desc, id desc")
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid project_id", raw))
+			return
+		}
+		query = query.Where("project_id = ?", id)
+	}
+
+	tokens := []APIToken{}
+	if err := query.Find(&tokens).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to fetch tokens", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, types.DataResponse{Data: tokens})
+}
+
+// CreateToken godoc
+// @Summary      Create a project token
+// @Description  Issue a token that can only operate on one project, within its scopes, for example for a CI pipeline restarting its service on staging: status (GET the project and its status), logs (its logs and log stream), start, stop and restart. The token is returned once; only its hash is stored.
+// @Tags         tokens
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateTokenRequest  true  "Token"
+// @Success      201      {object}  types.DataResponse{data=CreatedToken}
+// @Failure      400      {object}  middleware.ErrorResponse  "Invalid request or scope"
+// @Failure      404      {object}  middleware.ErrorResponse  "Project not found"
+// @Router       /tokens [post]
+func (h *Handler) CreateToken(c *gin.Context) {
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, "Invalid request", err.Error()))
+		return
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusBadRequest, err.Error(), req.Scopes))
+		return
+	}
+
+	var count int64
+	h.db.Table(tables.Name("projects")).Where("id = ? AND deleted_at IS NULL", req.ProjectID).Count(&count)
+	if count == 0 {
+		middleware.HandleError(c, middleware.NewError(http.StatusNotFound, "Project not found", req.ProjectID))
+		return
+	}
+
+	secret, hash, err := newToken()
+	if err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to generate token", err.Error()))
+		return
+	}
+	token := APIToken{
+		Name:      req.Name,
+		ProjectID: req.ProjectID,
+		Scopes:    scopes,
+		Prefix:    secret[:len(tokenPrefix)+6],
+		Hash:      hash,
+	}
+	if req.ExpiresInDays > 0 {
+		expires := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		token.ExpiresAt = &expires
+	}
+	if err := h.db.Create(&token).Error; err != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to save token", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, types.DataResponse{Data: CreatedToken{APIToken: token, Token: secret}})
+}
+
+// DeleteToken godoc
+// @Summary      Revoke a project token
+// @Description  Delete a project token; requests using it are refused from now on
+// @Tags         tokens
+// @Produce      json
+// @Param        id   path      int  true  "Token ID"
+// @Success      200  {object}  types.MessageResponse
+// @Failure      404  {object}  middleware.ErrorResponse  "Token not found"
+// @Router       /tokens/{id} [delete]
+func (h *Handler) DeleteToken(c *gin.Context) {
+	result := h.db.Delete(&APIToken{}, c.Param("id"))
+	if result.Error != nil {
+		middleware.HandleError(c, middleware.NewError(http.StatusInternalServerError, "Failed to delete token", result.Error.Error()))
+		return
+	}
+	if result.RowsAffected == 0 {
+		middleware.HandleError(c, middleware.ErrNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, types.MessageResponse{Message: "Token revoked"})
+}
@@ -0,0 +1,121 @@
+package tokens
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-runner/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// routeScopes are the routes project tokens can use, by method and path
+// below the API base path, with the scope each needs. The :id parameter
+// must be the project of the token.
+var routeScopes = map[string]string{
+	"GET /projects/:id":            ScopeStatus,
+	"GET /projects/:id/status":     ScopeStatus,
+	"GET /projects/:id/logs":       ScopeLogs,
+	"GET /projects/:id/logs/ws":    ScopeLogs,
+	"GET /projects/:id/logs/files": ScopeLogs,
+	"POST /projects/:id/start":     ScopeStart,
+	"POST /projects/:id/stop":      ScopeStop,
+	"POST /projects/:id/restart":   ScopeRestart,
+	"POST /services/:id/start":     ScopeStart,
+	"POST /services/:id/stop":      ScopeStop,
+	"POST /services/:id/restart":   ScopeRestart,
+}
+
+// Authenticate checks the bearer token of API requests, from the
+// Authorization header or, for WebSockets, the token query parameter. The
+// admin token opens every route. A project token only opens the routes of
+// its scopes for its project, and records its use. Without an admin token
+// configured, requests without a token are let through as before.
+func Authenticate(db *gorm.DB, adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c)
+		switch {
+		case token == "":
+			if adminToken != "" {
+				abort(c, http.StatusUnauthorized, "Authentication required", "Send Authorization: Bearer <token>")
+				return
+			}
+		case adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1:
+		case strings.HasPrefix(token, tokenPrefix):
+			if !authorizeProjectToken(c, db, token) {
+				return
+			}
+		default:
+			abort(c, http.StatusUnauthorized, "Invalid token", nil)
+			return
+		}
+		c.Next()
+	}
+}
+
+// authorizeProjectToken checks a project token against the route, aborting
+// the request when it is not allowed
+func authorizeProjectToken(c *gin.Context, db *gorm.DB, token string) bool {
+	var t APIToken
+	if err := db.Where("hash = ?", hashToken(token)).First(&t).Error; err != nil {
+		abort(c, http.StatusUnauthorized, "Invalid token", nil)
+		return false
+	}
+	now := time.Now()
+	if t.Expired(now) {
+		abort(c, http.StatusUnauthorized, "Token expired", t.ExpiresAt)
+		return false
+	}
+
+	route := c.Request.Method + " " + routePath(c)
+	scope, ok := routeScopes[route]
+	if !ok || c.Param("id") != strconv.FormatUint(uint64(t.ProjectID), 10) {
+		abort(c, http.StatusForbidden, "Token not allowed here", "Project tokens only operate on their project")
+		return false
+	}
+	if !t.Allows(scope) {
+		abort(c, http.StatusForbidden, "Token lacks the "+scope+" scope", t.Scopes)
+		return false
+	}
+
+	if err := db.Model(&t).Updates(map[string]interface{}{
+		"last_used_at": now,
+		"last_used_ip": c.ClientIP(),
+		"use_count":    gorm.Expr("use_count + 1"),
+	}).Error; err != nil {
+		log.Printf("Failed to record use of token %d: %v", t.ID, err)
+	}
+	return true
+}
+
+// routePath returns the matched route below the API base path, e.g.
+// /projects/:id/restart
+func routePath(c *gin.Context) string {
+	path := c.FullPath()
+	for _, root := range []string{"/projects/", "/services/"} {
+		if i := strings.Index(path, root); i >= 0 {
+			return path[i:]
+		}
+	}
+	return path
+}
+
+func bearerToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return c.Query("token")
+}
+
+func abort(c *gin.Context, status int, message string, details interface{}) {
+	middleware.HandleError(c, middleware.NewError(status, message, details))
+	c.Abort()
+}
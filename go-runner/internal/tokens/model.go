@@ -0,0 +1,106 @@
+// Package tokens holds project API tokens, which let automation such as CI
+// pipelines operate on one project without the admin token
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-runner/internal/tables"
+)
+
+// tokenPrefix starts every project token, so that they can be told apart
+// from the admin token and found by secret scanners
+const tokenPrefix = "grt_"
+
+// Scopes of project tokens
+const (
+	ScopeStatus  = "status"  // Read the project and its status
+	ScopeLogs    = "logs"    // Read and stream its logs
+	ScopeStart   = "start"   // Start it
+	ScopeStop    = "stop"    // Stop it
+	ScopeRestart = "restart" // Restart it
+)
+
+// validScopes are the scopes a token can be issued with
+var validScopes = map[string]bool{ScopeStatus: true, ScopeLogs: true, ScopeStart: true, ScopeStop: true, ScopeRestart: true}
+
+// defaultScopes are given to tokens issued without scopes, what a pipeline
+// bouncing its service needs
+var defaultScopes = []string{ScopeStatus, ScopeLogs, ScopeRestart}
+
+// APIToken is a token that can only operate on one project, within its
+// scopes. Only a hash of the token is stored; it is shown once, on creation.
+type APIToken struct {
+	ID         uint       `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Name       string     `json:"name" gorm:"not null"`
+	ProjectID  uint       `json:"project_id" gorm:"index"`
+	Scopes     []string   `json:"scopes" gorm:"serializer:json"`
+	Prefix     string     `json:"prefix"` // First characters of the token, to recognize it
+	Hash       string     `json:"-" gorm:"uniqueIndex;size:64"`
+	ExpiresAt  *time.Time `json:"expires_at"` // Never when null
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip"`
+	UseCount   int64      `json:"use_count"`
+}
+
+// TableName keeps tokens apart from other tables
+func (APIToken) TableName() string {
+	return tables.Name("api_tokens")
+}
+
+// Allows reports whether the token has a scope
+func (t *APIToken) Allows(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the token expired at now
+func (t *APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// newToken generates a project token, returning it with its hash
+func newToken() (token, hash string, err error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token = tokenPrefix + hex.EncodeToString(secret)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeScopes validates scopes, removing duplicates; empty scopes give
+// the default ones
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return append([]string(nil), defaultScopes...), nil
+	}
+	seen := make(map[string]bool, len(scopes))
+	var result []string
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !validScopes[scope] {
+			return nil, fmt.Errorf("unknown scope %q (use status, logs, start, stop or restart)", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package types
+
+// DataResponse is the standard envelope for successful responses carrying a payload
+type DataResponse struct {
+	Data interface{} `json:"data"`
+}
+
+// MessageResponse is returned by endpoints that only report an outcome
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// DataMessageResponse carries both a payload and a human readable message
+type DataMessageResponse struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+// ProjectActionResponse is returned by project lifecycle actions (start, stop, restart, ...)
+type ProjectActionResponse struct {
+	Message   string `json:"message"`
+	ProjectID int    `json:"project_id"`
+}
+
+// Pagination describes the page returned by a paginated endpoint
+type Pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+// NewPagination builds pagination metadata from page, limit and total count
+func NewPagination(page, limit int, total int64) Pagination {
+	return Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	}
+}
+
+// PaginatedResponse is the envelope for paginated list endpoints
+type PaginatedResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
@@ -14,7 +14,10 @@ const (
 	StatusRunning  ServiceStatus = "running"
 	StatusStopping ServiceStatus = "stopping"
 	StatusError    ServiceStatus = "error"
+	StatusPaused   ServiceStatus = "paused" // Frozen with SIGSTOP, see POST /projects/:id/pause
 	StatusUnknown  ServiceStatus = "unknown"
+	// The node of a remote project cannot be reached, the process may still run
+	StatusUnreachable ServiceStatus = "unreachable"
 )
 
 // ServiceType represents the type of microservice
@@ -26,9 +29,19 @@ const (
 	TypeWorker   ServiceType = "worker"
 	TypeDatabase ServiceType = "database"
 	TypeQueue    ServiceType = "queue"
+	TypeSystemd  ServiceType = "systemd" // Host unit controlled with systemctl
+	TypeMock     ServiceType = "mock"    // HTTP stub served by go-runner from mock_spec
 	TypeOther    ServiceType = "other"
 )
 
+// PortProtocol represents the transport protocol of a declared port
+type PortProtocol string
+
+const (
+	ProtocolTCP PortProtocol = "tcp"
+	ProtocolUDP PortProtocol = "udp"
+)
+
 // ProcessInfo holds information about a running process
 type ProcessInfo struct {
 	ProjectID uint
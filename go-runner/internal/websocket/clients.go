@@ -0,0 +1,70 @@
+package websocket
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// ClientInfo describes a connected client, to find out why someone does
+// not see the logs of a project
+type ClientInfo struct {
+	ID              uint64        `json:"id"`
+	ProjectID       uint          `json:"project_id"` // Project the client is subscribed to
+	RemoteIP        string        `json:"remote_ip"`
+	UserAgent       string        `json:"user_agent,omitempty"`
+	ConnectedAt     time.Time     `json:"connected_at"`
+	DurationSeconds int64         `json:"duration_seconds"`
+	LastSeen        time.Time     `json:"last_seen"` // Last message or answer to a ping (every 54s); past 60s the connection is dropped
+	Queued          int           `json:"queued"`    // Messages waiting to be written, disconnected at capacity
+	Capacity        int           `json:"capacity"`
+	Sent            uint64        `json:"sent"` // Messages queued to the client since it connected
+	Filter          LogFilterSpec `json:"filter"`
+	Timestamps      bool          `json:"timestamps"`
+	Timezone        TimezoneInfo  `json:"timezone"`
+}
+
+// Clients returns the connected clients, oldest first
+func (h *Hub) Clients() []ClientInfo {
+	now := time.Now()
+	h.mu.RLock()
+	clients := make([]ClientInfo, 0, len(h.clients))
+	for client := range h.clients {
+		filter, display := client.getSettings()
+		clients = append(clients, ClientInfo{
+			ID:              client.id,
+			ProjectID:       client.projectID,
+			RemoteIP:        client.remoteIP,
+			UserAgent:       client.userAgent,
+			ConnectedAt:     client.connectedAt,
+			DurationSeconds: int64(now.Sub(client.connectedAt).Seconds()),
+			LastSeen:        time.Unix(0, client.lastSeen.Load()),
+			Queued:          len(client.send),
+			Capacity:        cap(client.send),
+			Sent:            client.sent.Load(),
+			Filter:          filter.Spec(),
+			Timestamps:      display.Timestamps,
+			Timezone:        DescribeTimezone(display.Location, now),
+		})
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+// Disconnect closes the connection of a client, like falling behind does;
+// false when no client has this ID
+func (h *Hub) Disconnect(id uint64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for client := range h.clients {
+		if client.id == id {
+			close(client.send)
+			delete(h.clients, client)
+			log.Printf("Disconnected client %d of project %d (%s)", id, client.projectID, client.remoteIP)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// logTimeLayout is the timestamp prefixed to log lines, RFC 3339 with milliseconds
+const logTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// LogDisplay controls how captured log lines are rendered for a client.
+// Capture times are always kept in UTC; Location only changes the prefix.
+type LogDisplay struct {
+	Timestamps bool
+	Location   *time.Location
+}
+
+// Format renders a log line, prefixed with its capture time when timestamps
+// are on. Lines without a capture time are left as is.
+func (d LogDisplay) Format(line string, at time.Time) string {
+	if !d.Timestamps || at.IsZero() {
+		return line
+	}
+	loc := d.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return at.In(loc).Format(logTimeLayout) + " " + line
+}
+
+// TimezoneInfo describes the timezone log timestamps are displayed in
+type TimezoneInfo struct {
+	Name          string `json:"name"`           // IANA name, UTC or the offset given
+	Offset        string `json:"offset"`         // e.g. +07:00
+	OffsetSeconds int    `json:"offset_seconds"` // East of UTC
+}
+
+// ParseTimezone resolves an IANA name (Asia/Ho_Chi_Minh), a UTC offset
+// (+07:00, -0530) or UTC; empty means UTC
+func ParseTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "UTC") || name == "Z" {
+		return time.UTC, nil
+	}
+
+	if name[0] == '+' || name[0] == '-' {
+		layout := "-07:00"
+		if !strings.Contains(name, ":") {
+			layout = "-0700"
+		}
+		t, err := time.Parse(layout, name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone offset %q, expected +HH:MM", name)
+		}
+		_, offset := t.Zone()
+		return time.FixedZone(name, offset), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// DescribeTimezone returns the offset of a timezone at the given time, which
+// differs across daylight saving changes
+func DescribeTimezone(loc *time.Location, at time.Time) TimezoneInfo {
+	if loc == nil {
+		loc = time.UTC
+	}
+	_, offset := at.In(loc).Zone()
+	return TimezoneInfo{
+		Name:          loc.String(),
+		Offset:        at.In(loc).Format("-07:00"),
+		OffsetSeconds: offset,
+	}
+}
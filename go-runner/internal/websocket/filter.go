@@ -0,0 +1,200 @@
+package websocket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LogLevel is the severity of a log line, ordered from least to most severe
+type LogLevel int
+
+const (
+	LevelTrace LogLevel = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levelNames = map[string]LogLevel{
+	"trace":    LevelTrace,
+	"debug":    LevelDebug,
+	"info":     LevelInfo,
+	"notice":   LevelInfo,
+	"warn":     LevelWarn,
+	"warning":  LevelWarn,
+	"error":    LevelError,
+	"err":      LevelError,
+	"fatal":    LevelFatal,
+	"panic":    LevelFatal,
+	"critical": LevelFatal,
+	"crit":     LevelFatal,
+}
+
+// String returns the canonical level name
+func (l LogLevel) String() string {
+	switch l {
+	case LevelTrace:
+		return "trace"
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelFatal:
+		return "fatal"
+	}
+	return "info"
+}
+
+// ParseLogLevel parses a level name (debug, info, warn, error, ...)
+func ParseLogLevel(name string) (LogLevel, error) {
+	level, ok := levelNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return LevelInfo, fmt.Errorf("unknown log level %q (use trace, debug, info, warn, error or fatal)", name)
+	}
+	return level, nil
+}
+
+// stderrPrefix is added by the service manager to lines read from stderr
+const stderrPrefix = "[ERROR] "
+
+// sourceTagPattern matches the "[path] " tag the service manager adds to lines
+// of tail_files, told apart from "[WARN]" by the dot or slash of the path
+var sourceTagPattern = regexp.MustCompile(`^\[[^\]\s]*[./][^\]\s]*\] `)
+
+var (
+	// level=warn, "level":"warn", severity: WARN
+	structuredLevelPattern = regexp.MustCompile(`(?i)"?(?:level|lvl|severity)"?\s*[=:]\s*"?([a-z]+)`)
+	// [WARN], WARN:, <warn>, or a bare level word near the start of the line
+	leadingLevelPattern = regexp.MustCompile(`(?i)\b(trace|debug|info|notice|warn|warning|error|err|fatal|panic|critical|crit)\b`)
+)
+
+// leadingLevelWindow limits bare level words to the line prefix, where
+// timestamps and levels live, so messages mentioning "error" are not misread
+const leadingLevelWindow = 48
+
+// DetectLogLevel guesses the level of a log line. Lines from stderr without a
+// level of their own are errors; other lines without a level are info.
+func DetectLogLevel(line string) LogLevel {
+	fallback := LevelInfo
+	if strings.HasPrefix(line, stderrPrefix) {
+		// Many tools log everything to stderr; prefer the level they wrote
+		line = strings.TrimPrefix(line, stderrPrefix)
+		fallback = LevelError
+	}
+	// The file name is not part of the line ("[error.log] ...")
+	if tag := sourceTagPattern.FindString(line); tag != "" {
+		line = line[len(tag):]
+	}
+
+	if m := structuredLevelPattern.FindStringSubmatch(line); m != nil {
+		if level, ok := levelNames[strings.ToLower(m[1])]; ok {
+			return level
+		}
+	}
+
+	head := line
+	if len(head) > leadingLevelWindow {
+		head = head[:leadingLevelWindow]
+	}
+	if m := leadingLevelPattern.FindStringSubmatch(head); m != nil {
+		return levelNames[strings.ToLower(m[1])]
+	}
+
+	if strings.HasPrefix(line, "panic:") || strings.HasPrefix(line, "Traceback ") {
+		return LevelFatal
+	}
+	return fallback
+}
+
+// LogFilter selects log lines by minimum level and include/exclude patterns
+type LogFilter struct {
+	MinLevel LogLevel
+	Include  *regexp.Regexp
+	Exclude  *regexp.Regexp
+}
+
+// LogFilterSpec is the wire form of a log filter, used in subscribe messages
+// and query parameters
+type LogFilterSpec struct {
+	Level   string `json:"level,omitempty"`   // Minimum level: trace, debug, info, warn, error, fatal
+	Include string `json:"include,omitempty"` // Only lines matching this regex
+	Exclude string `json:"exclude,omitempty"` // Drop lines matching this regex
+}
+
+// IsZero reports whether the spec filters nothing
+func (s LogFilterSpec) IsZero() bool {
+	return s.Level == "" && s.Include == "" && s.Exclude == ""
+}
+
+// Compile validates the spec and builds the filter. A zero spec returns nil.
+func (s LogFilterSpec) Compile() (*LogFilter, error) {
+	if s.IsZero() {
+		return nil, nil
+	}
+
+	filter := &LogFilter{MinLevel: LevelTrace}
+	if s.Level != "" {
+		level, err := ParseLogLevel(s.Level)
+		if err != nil {
+			return nil, err
+		}
+		filter.MinLevel = level
+	}
+	if s.Include != "" {
+		re, err := regexp.Compile(s.Include)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern: %v", err)
+		}
+		filter.Include = re
+	}
+	if s.Exclude != "" {
+		re, err := regexp.Compile(s.Exclude)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern: %v", err)
+		}
+		filter.Exclude = re
+	}
+	return filter, nil
+}
+
+// Spec returns the wire form of the filter. A nil filter returns a zero
+// spec.
+func (f *LogFilter) Spec() LogFilterSpec {
+	var spec LogFilterSpec
+	if f == nil {
+		return spec
+	}
+	if f.MinLevel > LevelTrace {
+		spec.Level = f.MinLevel.String()
+	}
+	if f.Include != nil {
+		spec.Include = f.Include.String()
+	}
+	if f.Exclude != nil {
+		spec.Exclude = f.Exclude.String()
+	}
+	return spec
+}
+
+// Match reports whether a log line passes the filter. A nil filter matches
+// everything.
+func (f *LogFilter) Match(line string) bool {
+	if f == nil {
+		return true
+	}
+	if f.MinLevel > LevelTrace && DetectLogLevel(line) < f.MinLevel {
+		return false
+	}
+	if f.Include != nil && !f.Include.MatchString(line) {
+		return false
+	}
+	if f.Exclude != nil && f.Exclude.MatchString(line) {
+		return false
+	}
+	return true
+}
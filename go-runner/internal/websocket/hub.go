@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -28,6 +30,29 @@ type Hub struct {
 
 	// Mutex for thread safety
 	mu sync.RWMutex
+
+	// Messages queued to clients, and clients dropped for falling behind
+	sent    atomic.Uint64
+	dropped atomic.Uint64
+
+	// Last client ID handed out
+	lastClientID atomic.Uint64
+}
+
+// HubStats reports the connected clients of the hub
+type HubStats struct {
+	Clients  int              `json:"clients"`
+	Projects []ProjectClients `json:"projects"`
+	Sent     uint64           `json:"sent"`    // Messages queued to clients since start
+	Dropped  uint64           `json:"dropped"` // Clients disconnected because their queue was full
+}
+
+// ProjectClients reports the clients listening to one project
+type ProjectClients struct {
+	ProjectID uint `json:"project_id"`
+	Clients   int  `json:"clients"`
+	Queued    int  `json:"queued"`   // Messages waiting to be written to the clients
+	Capacity  int  `json:"capacity"` // Queue size of the clients together
 }
 
 // Client is a middleman between the websocket connection and the hub
@@ -42,6 +67,40 @@ type Client struct {
 
 	// Project ID this client is listening to
 	projectID uint
+
+	// Server-side filter for "log" messages, set on connect or by a subscribe message
+	filter   *LogFilter
+	filterMu sync.RWMutex
+
+	// Rendering of "log" messages, set on connect or by a display message;
+	// guarded by filterMu
+	display LogDisplay
+
+	// Connection metadata reported by GET /ws/clients
+	id          uint64
+	remoteIP    string
+	userAgent   string
+	connectedAt time.Time
+	lastSeen    atomic.Int64 // Unix nanoseconds of the last message or pong
+	sent        atomic.Uint64
+}
+
+// ClientMessage is a message sent by a client, e.g. to change its log filter
+// or how log lines are displayed:
+//
+//	{"type": "subscribe", "filter": {"level": "warn", "include": "api", "exclude": "healthz"}}
+//	{"type": "display", "timestamps": true, "tz": "Asia/Ho_Chi_Minh"}
+type ClientMessage struct {
+	Type       string        `json:"type"` // subscribe, display
+	Filter     LogFilterSpec `json:"filter"`
+	Timestamps *bool         `json:"timestamps"` // Prefix log lines with their capture time
+	Timezone   *string       `json:"tz"`         // IANA name or +HH:MM for the prefix, UTC by default
+}
+
+// DisplaySettings is the reply to a display message
+type DisplaySettings struct {
+	Timestamps bool         `json:"timestamps"`
+	Timezone   TimezoneInfo `json:"timezone"`
 }
 
 // Message represents a WebSocket message
@@ -50,6 +109,7 @@ type Message struct {
 	ProjectID uint        `json:"project_id,omitempty"`
 	Data      interface{} `json:"data"`
 	Timestamp int64       `json:"timestamp"`
+	Time      *time.Time  `json:"time,omitempty"` // Capture time of a log line, in UTC
 }
 
 var upgrader = websocket.Upgrader{
@@ -58,6 +118,12 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// Upgrade upgrades an HTTP request to a WebSocket connection for handlers
+// that stream to a single connection outside the hub
+func Upgrade(c *gin.Context) (*websocket.Conn, error) {
+	return upgrader.Upgrade(c.Writer, c.Request, nil)
+}
+
 // NewHub creates a new WebSocket hub
 func NewHub() *Hub {
 	return &Hub{
@@ -88,16 +154,15 @@ func (h *Hub) Run() {
 			log.Printf("Client disconnected for project %d", client.projectID)
 
 		case message := <-h.broadcast:
+			var full []*Client
 			h.mu.RLock()
 			for client := range h.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(h.clients, client)
+				if !h.deliver(client, message) {
+					full = append(full, client)
 				}
 			}
 			h.mu.RUnlock()
+			h.dropClients(full)
 		}
 	}
 }
@@ -117,18 +182,123 @@ func (h *Hub) BroadcastToProject(projectID uint, messageType string, data interf
 		return
 	}
 
+	// Log lines are filtered per client so chatty services don't flood slow connections
+	line, isLog := data.(string)
+	isLog = isLog && messageType == "log"
+
+	var full []*Client
 	h.mu.RLock()
 	for client := range h.clients {
 		if client.projectID == projectID {
-			select {
-			case client.send <- jsonMessage:
-			default:
-				close(client.send)
-				delete(h.clients, client)
+			if isLog && !client.getFilter().Match(line) {
+				continue
+			}
+			if !h.deliver(client, jsonMessage) {
+				full = append(full, client)
 			}
 		}
 	}
 	h.mu.RUnlock()
+	h.dropClients(full)
+}
+
+// BroadcastLog sends a captured log line to the clients of a project,
+// rendered with each client's display settings. Filters match the line
+// without its timestamp.
+func (h *Hub) BroadcastLog(projectID uint, line string, at time.Time) {
+	message := Message{
+		Type:      "log",
+		ProjectID: projectID,
+		Timestamp: time.Now().Unix(),
+	}
+	if !at.IsZero() {
+		utc := at.UTC()
+		message.Time = &utc
+	}
+
+	var full []*Client
+	h.mu.RLock()
+	for client := range h.clients {
+		if client.projectID != projectID {
+			continue
+		}
+		filter, display := client.getSettings()
+		if !filter.Match(line) {
+			continue
+		}
+		message.Data = display.Format(line, at)
+		jsonMessage, err := json.Marshal(message)
+		if err != nil {
+			log.Printf("Error marshaling message: %v", err)
+			break
+		}
+		if !h.deliver(client, jsonMessage) {
+			full = append(full, client)
+		}
+	}
+	h.mu.RUnlock()
+	h.dropClients(full)
+}
+
+// deliver queues a message to a client and reports false when its queue is
+// full. Called under h.mu.RLock, so it leaves dropping the client to
+// dropClients.
+func (h *Hub) deliver(client *Client, message []byte) bool {
+	select {
+	case client.send <- message:
+		h.sent.Add(1)
+		client.sent.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// dropClients disconnects clients that fell behind. Another broadcast may
+// have dropped one already, so only those still registered are closed.
+func (h *Hub) dropClients(clients []*Client) {
+	if len(clients) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, client := range clients {
+		if _, ok := h.clients[client]; ok {
+			delete(h.clients, client)
+			close(client.send)
+			h.dropped.Add(1)
+		}
+	}
+}
+
+// Stats reports the connected clients per project and their queues
+func (h *Hub) Stats() HubStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	byProject := make(map[uint]*ProjectClients)
+	for client := range h.clients {
+		pc, ok := byProject[client.projectID]
+		if !ok {
+			pc = &ProjectClients{ProjectID: client.projectID}
+			byProject[client.projectID] = pc
+		}
+		pc.Clients++
+		pc.Queued += len(client.send)
+		pc.Capacity += cap(client.send)
+	}
+
+	stats := HubStats{
+		Clients:  len(h.clients),
+		Projects: make([]ProjectClients, 0, len(byProject)),
+		Sent:     h.sent.Load(),
+		Dropped:  h.dropped.Load(),
+	}
+	for _, pc := range byProject {
+		stats.Projects = append(stats.Projects, *pc)
+	}
+	sort.Slice(stats.Projects, func(i, j int) bool { return stats.Projects[i].ProjectID < stats.Projects[j].ProjectID })
+	return stats
 }
 
 // HasClientsForProject checks if there are any clients connected for a specific project
@@ -193,6 +363,30 @@ func (h *Hub) HandleProjectWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Initial log filter from ?level=warn&include=...&exclude=...
+	filter, err := LogFilterSpec{
+		Level:   c.Query("level"),
+		Include: c.Query("include"),
+		Exclude: c.Query("exclude"),
+	}.Compile()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Initial display from ?timestamps=true&tz=Asia/Ho_Chi_Minh
+	var display LogDisplay
+	if v := c.Query("timestamps"); v != "" {
+		if display.Timestamps, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timestamps value"})
+			return
+		}
+	}
+	if display.Location, err = ParseTimezone(c.Query("tz")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
@@ -200,11 +394,18 @@ func (h *Hub) HandleProjectWebSocket(c *gin.Context) {
 	}
 
 	client := &Client{
-		hub:       h,
-		conn:      conn,
-		send:      make(chan []byte, 256),
-		projectID: uint(projectID),
+		hub:         h,
+		conn:        conn,
+		send:        make(chan []byte, 256),
+		projectID:   uint(projectID),
+		filter:      filter,
+		display:     display,
+		id:          h.lastClientID.Add(1),
+		remoteIP:    c.ClientIP(),
+		userAgent:   c.Request.UserAgent(),
+		connectedAt: time.Now(),
 	}
+	client.lastSeen.Store(client.connectedAt.UnixNano())
 
 	client.hub.register <- client
 
@@ -224,22 +425,107 @@ func (c *Client) readPump() {
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(512)
+	c.conn.SetReadLimit(4096)
 	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+		c.lastSeen.Store(time.Now().UnixNano())
 		return nil
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
+		c.lastSeen.Store(time.Now().UnixNano())
+		c.handleMessage(data)
+	}
+}
+
+// handleMessage processes a message sent by the client
+func (c *Client) handleMessage(data []byte) {
+	var msg ClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		c.reply("error", "invalid message: "+err.Error())
+		return
 	}
+
+	switch msg.Type {
+	case "subscribe":
+		filter, err := msg.Filter.Compile()
+		if err != nil {
+			c.reply("error", err.Error())
+			return
+		}
+		c.filterMu.Lock()
+		c.filter = filter
+		c.filterMu.Unlock()
+		c.reply("subscribed", msg.Filter)
+	case "display":
+		c.filterMu.Lock()
+		if msg.Timezone != nil {
+			loc, err := ParseTimezone(*msg.Timezone)
+			if err != nil {
+				c.filterMu.Unlock()
+				c.reply("error", err.Error())
+				return
+			}
+			c.display.Location = loc
+		}
+		if msg.Timestamps != nil {
+			c.display.Timestamps = *msg.Timestamps
+		}
+		display := c.display
+		c.filterMu.Unlock()
+		c.reply("display", DisplaySettings{
+			Timestamps: display.Timestamps,
+			Timezone:   DescribeTimezone(display.Location, time.Now()),
+		})
+	default:
+		c.reply("error", "unknown message type: "+msg.Type)
+	}
+}
+
+// reply sends a message to this client only
+func (c *Client) reply(messageType string, data interface{}) {
+	jsonMessage, err := json.Marshal(Message{
+		Type:      messageType,
+		ProjectID: c.projectID,
+		Data:      data,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return
+	}
+
+	// The hub closes send when the client falls behind; only send while registered
+	c.hub.mu.RLock()
+	defer c.hub.mu.RUnlock()
+	if _, ok := c.hub.clients[c]; ok {
+		select {
+		case c.send <- jsonMessage:
+			c.sent.Add(1)
+		default:
+		}
+	}
+}
+
+// getFilter returns the client's current log filter
+func (c *Client) getFilter() *LogFilter {
+	c.filterMu.RLock()
+	defer c.filterMu.RUnlock()
+	return c.filter
+}
+
+// getSettings returns the client's current log filter and display
+func (c *Client) getSettings() (*LogFilter, LogDisplay) {
+	c.filterMu.RLock()
+	defer c.filterMu.RUnlock()
+	return c.filter, c.display
 }
 
 // writePump pumps messages from the hub to the websocket connection
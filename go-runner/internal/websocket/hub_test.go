@@ -0,0 +1,160 @@
+package websocket
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// addClient registers a client of a project with a queue of size messages,
+// as Run does for a new connection
+func addClient(h *Hub, projectID uint, size int) *Client {
+	client := &Client{hub: h, send: make(chan []byte, size), projectID: projectID, id: h.lastClientID.Add(1)}
+	h.mu.Lock()
+	h.clients[client] = true
+	h.mu.Unlock()
+	return client
+}
+
+// drain reads the queue of a client until it is closed, returning the count
+// of messages read
+func drain(t *testing.T, client *Client) int {
+	t.Helper()
+	count := 0
+	for {
+		select {
+		case _, ok := <-client.send:
+			if !ok {
+				return count
+			}
+			count++
+		case <-time.After(time.Second):
+			t.Fatalf("queue of client %d not closed", client.id)
+		}
+	}
+}
+
+func TestConcurrentBroadcastsDropSlowClientOnce(t *testing.T) {
+	const broadcasters, messages, stalled = 8, 200, 50
+	// Broadcasters must overlap for -race to see them, also on one CPU
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	h := NewHub()
+	go h.Run()
+	slow := addClient(h, 1, 1)
+	healthy := addClient(h, 1, broadcasters*messages*2)
+	other := addClient(h, 2, 1)
+	// Clients without room, full for every broadcaster at once
+	crowd := make([]*Client, stalled)
+	for i := range crowd {
+		crowd[i] = addClient(h, 1, 0)
+	}
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < broadcasters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for j := 0; j < messages; j++ {
+				h.BroadcastToProject(1, "status", j)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if stats := h.Stats(); stats.Clients != 2 || stats.Dropped != stalled+1 {
+		t.Errorf("stats = %d clients, %d dropped, want 2 clients, %d dropped", stats.Clients, stats.Dropped, stalled+1)
+	}
+	for _, client := range crowd {
+		drain(t, client)
+	}
+	if got := drain(t, slow); got != 1 {
+		t.Errorf("slow client got %d messages, want the one fitting its queue", got)
+	}
+	if got := len(healthy.send); got != broadcasters*messages {
+		t.Errorf("healthy client got %d messages, want %d", got, broadcasters*messages)
+	}
+	if got := len(other.send); got != 0 {
+		t.Errorf("client of another project got %d messages", got)
+	}
+
+	// A broadcast to everyone drops the client of the other project too
+	// once its queue is full
+	h.BroadcastToAll("notice", "a")
+	h.BroadcastToAll("notice", "b")
+	// Run takes the next broadcast once done with the previous one
+	h.BroadcastToAll("notice", "c")
+	if got := drain(t, other); got != 1 {
+		t.Errorf("other client got %d messages, want 1", got)
+	}
+}
+
+func TestDisconnectWhileBroadcasting(t *testing.T) {
+	h := NewHub()
+	clients := make([]*Client, 20)
+	for i := range clients {
+		clients[i] = addClient(h, 1, 4)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				h.BroadcastToProject(1, "status", j)
+			}
+		}()
+	}
+	for _, client := range clients {
+		h.Disconnect(client.id)
+	}
+	wg.Wait()
+
+	if stats := h.Stats(); stats.Clients != 0 {
+		t.Errorf("%d clients left, want 0", stats.Clients)
+	}
+}
+
+func TestConcurrentLogBroadcasts(t *testing.T) {
+	const lines, stalled = 500, 20
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	h := NewHub()
+	listener := addClient(h, 1, 2*lines)
+	listener.display.Timestamps = true
+	crowd := make([]*Client, stalled)
+	for i := range crowd {
+		crowd[i] = addClient(h, 1, 0)
+	}
+
+	// Like the stdout and stderr capture goroutines of a process
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, stream := range []string{"stdout", "stderr"} {
+		wg.Add(1)
+		go func(stream string) {
+			defer wg.Done()
+			<-start
+			for i := 0; i < lines; i++ {
+				h.BroadcastLog(1, stream+" line", time.Now())
+			}
+		}(stream)
+	}
+	close(start)
+	wg.Wait()
+
+	if stats := h.Stats(); stats.Clients != 1 || stats.Dropped != stalled {
+		t.Errorf("stats = %d clients, %d dropped, want 1 client, %d dropped", stats.Clients, stats.Dropped, stalled)
+	}
+	for _, client := range crowd {
+		drain(t, client)
+	}
+	if got := len(listener.send); got != 2*lines {
+		t.Errorf("listener got %d lines, want %d", got, 2*lines)
+	}
+}
@@ -0,0 +1,7 @@
+// Package client provides a Go client for the Go Runner API.
+//
+// The client is generated from docs/openapi.yaml; run `make client` after
+// changing handler annotations to keep it in sync.
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -generate types,client -package client -o client.gen.go ../../docs/openapi.yaml
@@ -48,7 +48,7 @@ export const projectApi = {
     packageManager: "npm" | "yarn" | "pnpm" | "go" | "pip",
     packages?: string[]
   ) =>
-    api.post<ApiResponse<{ message: string }>>(
+    api.post<ApiResponse<{ id: number; status: string }>>(
       `/api/v1/projects/${id}/install`,
       { package_manager: packageManager, packages }
     ),